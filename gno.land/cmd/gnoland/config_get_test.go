@@ -882,6 +882,22 @@ func TestConfig_Get_Mempool(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"type",
+			"mempool.type",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.Mempool.Type, unmarshalJSONCommon[string](t, value))
+			},
+			false,
+		},
+		{
+			"type, raw",
+			"mempool.type",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.Mempool.Type, escapeNewline(value))
+			},
+			true,
+		},
 		{
 			"size",
 			"mempool.size",
@@ -906,6 +922,14 @@ func TestConfig_Get_Mempool(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"price bump",
+			"mempool.price_bump",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.Mempool.PriceBump, unmarshalJSONCommon[int64](t, value))
+			},
+			false,
+		},
 	}
 
 	verifyGetTestTableCommon(t, testTable)
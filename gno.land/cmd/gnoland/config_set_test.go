@@ -774,6 +774,16 @@ func TestConfig_Set_Mempool(t *testing.T) {
 				assert.Equal(t, value, loadedCfg.Mempool.WalPath)
 			},
 		},
+		{
+			"type updated",
+			[]string{
+				"mempool.type",
+				"clist",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.Mempool.Type)
+			},
+		},
 		{
 			"size updated",
 			[]string{
@@ -804,6 +814,124 @@ func TestConfig_Set_Mempool(t *testing.T) {
 				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Mempool.CacheSize))
 			},
 		},
+		{
+			"price bump updated",
+			[]string{
+				"mempool.price_bump",
+				"25",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Mempool.PriceBump))
+			},
+		},
+	}
+
+	verifySetTestTableCommon(t, testTable)
+}
+
+func TestConfig_Set_StateSync(t *testing.T) {
+	t.Parallel()
+
+	testTable := []testSetCase{
+		{
+			"trust height updated",
+			[]string{
+				"state_sync.trust_height",
+				"100",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.StateSync.TrustHeight))
+			},
+		},
+		{
+			"trust hash updated",
+			[]string{
+				"state_sync.trust_hash",
+				"0123abcd",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.StateSync.TrustHash)
+			},
+		},
+		{
+			"trust period updated",
+			[]string{
+				"state_sync.trust_period",
+				"1h0m0s",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.StateSync.TrustPeriod.String())
+			},
+		},
+		{
+			"discovery time updated",
+			[]string{
+				"state_sync.discovery_time",
+				"5s",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.StateSync.DiscoveryTime.String())
+			},
+		},
+		{
+			"chunk fetchers updated",
+			[]string{
+				"state_sync.chunk_fetchers",
+				"8",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.StateSync.ChunkFetchers))
+			},
+		},
+	}
+
+	verifySetTestTableCommon(t, testTable)
+}
+
+func TestConfig_Set_Pruning(t *testing.T) {
+	t.Parallel()
+
+	testTable := []testSetCase{
+		{
+			"keep recent blocks updated",
+			[]string{
+				"pruning.keep_recent_blocks",
+				"100000",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Pruning.KeepRecentBlocks))
+			},
+		},
+		{
+			"keep recent results updated",
+			[]string{
+				"pruning.keep_recent_results",
+				"1000",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Pruning.KeepRecentResults))
+			},
+		},
+		{
+			"retain height updated",
+			[]string{
+				"pruning.retain_height",
+				"42",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Pruning.RetainHeight))
+			},
+		},
+		{
+			"interval updated",
+			[]string{
+				"pruning.interval",
+				"10s",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.Pruning.Interval.String())
+			},
+		},
 	}
 
 	verifySetTestTableCommon(t, testTable)
@@ -833,6 +961,76 @@ func TestConfig_Set_Application(t *testing.T) {
 				assert.Equal(t, types.PruneStrategy(value), loadedCfg.Application.PruneStrategy)
 			},
 		},
+		{
+			"prune keep recent updated",
+			[]string{
+				"application.prune_keep_recent",
+				"1000",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Application.PruneKeepRecent))
+			},
+		},
+		{
+			"prune keep every updated",
+			[]string{
+				"application.prune_keep_every",
+				"100",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Application.PruneKeepEvery))
+			},
+		},
+		{
+			"snapshot interval updated",
+			[]string{
+				"application.snapshot_interval",
+				"1000",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Application.SnapshotInterval))
+			},
+		},
+		{
+			"snapshot keep recent updated",
+			[]string{
+				"application.snapshot_keep_recent",
+				"5",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Application.SnapshotKeepRecent))
+			},
+		},
+		{
+			"snapshot trust unverified updated",
+			[]string{
+				"application.snapshot_trust_unverified",
+				"true",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%v", loadedCfg.Application.SnapshotTrustUnverified))
+			},
+		},
+		{
+			"keep history updated",
+			[]string{
+				"application.keep_history",
+				"true",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%v", loadedCfg.Application.KeepHistory))
+			},
+		},
+		{
+			"history keep recent updated",
+			[]string{
+				"application.history_keep_recent",
+				"1000",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Application.HistoryKeepRecent))
+			},
+		},
 	}
 
 	verifySetTestTableCommon(t, testTable)
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoland"
+	"github.com/gnolang/gno/tm2/pkg/bft/config"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+)
+
+// The names of the node databases, which use the configured db_backend
+const (
+	blockStoreDBName = "blockstore"
+	stateDBName      = "state"
+)
+
+var (
+	errUnknownDB = errors.New("unknown database")
+	errMissingDB = errors.New("database not found")
+)
+
+type dbCfg struct {
+	dataDir string
+}
+
+// newDBCmd creates the db root command
+func newDBCmd(io commands.IO) *commands.Command {
+	cmd := commands.NewCommand(
+		commands.Metadata{
+			Name:       "db",
+			ShortUsage: "db <subcommand> [flags]",
+			ShortHelp:  "gno databases inspection and maintenance suite",
+			LongHelp: "Gno databases inspection and maintenance suite, for the databases of a node data directory. " +
+				"The node must be stopped, as the databases can't be opened by two processes",
+		},
+		commands.NewEmptyConfig(),
+		commands.HelpExec,
+	)
+
+	cmd.AddSubCommands(
+		newDBStatsCmd(io),
+		newDBCompactCmd(io),
+		newDBMigrateCmd(io),
+		newDBCheckCmd(io),
+	)
+
+	return cmd
+}
+
+func (c *dbCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.dataDir,
+		"data-dir",
+		defaultNodeDir,
+		"the path to the node's data directory",
+	)
+}
+
+// nodeDB is a database of the node data directory
+type nodeDB struct {
+	name    string
+	backend dbm.BackendType
+	dir     string
+}
+
+// path returns the path of the database files
+func (d nodeDB) path() string {
+	return filepath.Join(d.dir, d.name+".db")
+}
+
+// open opens the database, which must exist
+func (d nodeDB) open() (dbm.DB, error) {
+	if _, err := os.Stat(d.path()); err != nil {
+		return nil, fmt.Errorf("%w: %s at %q", errMissingDB, d.name, d.path())
+	}
+
+	db, err := dbm.NewDB(d.name, d.backend, d.dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the %s database, %w", d.name, err)
+	}
+
+	return db, nil
+}
+
+// loadNodeDBs returns the databases of the node in the given data directory:
+// the app database, and the node databases at the configured path and backend
+func loadNodeDBs(dataDir string) ([]nodeDB, *config.Config, error) {
+	nodeDir, err := filepath.Abs(dataDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get absolute path for data directory, %w", err)
+	}
+
+	cfg, err := config.LoadConfig(nodeDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s, %w", tryConfigInit, err)
+	}
+
+	backend := dbm.BackendType(cfg.DBBackend)
+
+	return []nodeDB{
+		{name: gnoland.AppDBName, backend: gnoland.AppDBBackend, dir: filepath.Join(nodeDir, config.DefaultDBDir)},
+		{name: blockStoreDBName, backend: backend, dir: cfg.DBDir()},
+		{name: stateDBName, backend: backend, dir: cfg.DBDir()},
+	}, cfg, nil
+}
+
+// selectNodeDBs returns the databases with the given names, or all of them if
+// no names are given
+func selectNodeDBs(dbs []nodeDB, names []string) ([]nodeDB, error) {
+	if len(names) == 0 {
+		return dbs, nil
+	}
+
+	selected := make([]nodeDB, 0, len(names))
+
+	for _, name := range names {
+		idx := slices.IndexFunc(dbs, func(d nodeDB) bool {
+			return d.name == name
+		})
+		if idx == -1 {
+			return nil, fmt.Errorf("%w: %q", errUnknownDB, name)
+		}
+
+		selected = append(selected, dbs[idx])
+	}
+
+	return selected, nil
+}
+
+// dirSize returns the size on disk of the files in the given path
+func dirSize(path string) (int64, error) {
+	var size int64
+
+	err := filepath.WalkDir(path, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		size += info.Size()
+
+		return nil
+	})
+
+	return size, err
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoland"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	bftstore "github.com/gnolang/gno/tm2/pkg/bft/store"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/store"
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+)
+
+var (
+	errNoCommittedBlocks  = errors.New("no committed blocks")
+	errInvalidCheckHeight = errors.New("invalid height")
+	errMissingHeader      = errors.New("block header not found")
+	errAppHashMismatch    = errors.New("app hash mismatch")
+)
+
+type dbCheckCfg struct {
+	dbCfg
+
+	height int64
+}
+
+// newDBCheckCmd creates the db check command
+func newDBCheckCmd(io commands.IO) *commands.Command {
+	cfg := &dbCheckCfg{}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "check",
+			ShortUsage: "db check [flags]",
+			ShortHelp:  "checks the consistency of the app state with the chain",
+			LongHelp: "Loads the app state at the given height, checking the IAVL roots against the commit info " +
+				"of the app database, and checks the resulting app hash against the one stored by the node: " +
+				"in the state database for the latest height, or in the header of the next block otherwise",
+		},
+		cfg,
+		func(_ context.Context, _ []string) error {
+			return execDBCheck(cfg, io)
+		},
+	)
+}
+
+func (c *dbCheckCfg) RegisterFlags(fs *flag.FlagSet) {
+	c.dbCfg.RegisterFlags(fs)
+
+	fs.Int64Var(
+		&c.height,
+		"height",
+		0,
+		"the height to check (0 for the latest height)",
+	)
+}
+
+func execDBCheck(cfg *dbCheckCfg, io commands.IO) error {
+	if cfg.height < 0 {
+		return fmt.Errorf("%w %d", errInvalidCheckHeight, cfg.height)
+	}
+
+	dbs, _, err := loadNodeDBs(cfg.dataDir)
+	if err != nil {
+		return err
+	}
+
+	// Open the app, block store and state databases, in this order
+	opened := make([]dbm.DB, 0, len(dbs))
+
+	defer func() {
+		for _, db := range opened {
+			db.Close()
+		}
+	}()
+
+	for _, d := range dbs {
+		db, err := d.open()
+		if err != nil {
+			return err
+		}
+
+		opened = append(opened, db)
+	}
+
+	var (
+		appDB      = opened[0]
+		blockStore = bftstore.NewBlockStore(opened[1])
+		state      = sm.LoadState(opened[2])
+	)
+
+	if state.LastBlockHeight == 0 {
+		return errNoCommittedBlocks
+	}
+
+	height := cfg.height
+	if height == 0 {
+		height = state.LastBlockHeight
+	}
+
+	if height > state.LastBlockHeight {
+		return fmt.Errorf("%w %d, the latest height is %d", errInvalidCheckHeight, height, state.LastBlockHeight)
+	}
+
+	// The app hash after a block is saved in the state, and in the header
+	// of the next block
+	expectedHash, source := state.AppHash, "the state"
+	if height < state.LastBlockHeight {
+		meta := blockStore.LoadBlockMeta(height + 1)
+		if meta == nil {
+			return fmt.Errorf("%w at height %d, the lowest height is %d", errMissingHeader, height+1, blockStore.Base())
+		}
+
+		expectedHash, source = meta.Header.AppHash, fmt.Sprintf("the header of block %d", height+1)
+	}
+
+	// Load the app stores, as mounted by the app. Loading a version checks
+	// the roots of the stores against the commit info of the version
+	var (
+		mainKey = store.NewStoreKey("main")
+		baseKey = store.NewStoreKey("base")
+		cms     = store.NewCommitMultiStore(appDB)
+	)
+
+	cms.SetStoreOptions(store.StoreOptions{Immutable: true})
+	cms.MountStoreWithDB(mainKey, iavl.StoreConstructor, appDB)
+	cms.MountStoreWithDB(baseKey, dbadapter.StoreConstructor, appDB)
+
+	if err := cms.LoadVersion(height); err != nil {
+		return fmt.Errorf("unable to load the %s state at height %d, %w", gnoland.AppDBName, height, err)
+	}
+
+	io.Printfln(
+		"IAVL root of the %s store at height %d: %X, matches the commit info",
+		mainKey.Name(),
+		height,
+		cms.GetCommitStore(mainKey).LastCommitID().Hash,
+	)
+
+	appHash := cms.LastCommitID().Hash
+	if !bytes.Equal(appHash, expectedHash) {
+		return fmt.Errorf(
+			"%w at height %d: %X in the app database, %X in %s",
+			errAppHashMismatch,
+			height,
+			appHash,
+			expectedHash,
+			source,
+		)
+	}
+
+	io.Printfln("App hash at height %d: %X, matches %s", height, appHash, source)
+
+	return nil
+}
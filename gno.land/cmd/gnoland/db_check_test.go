@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+)
+
+func TestDB_Check(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid height", func(t *testing.T) {
+		t.Parallel()
+
+		dataDir, _ := initTestNodeDir(t, 3)
+
+		for _, height := range []string{"-1", "4"} {
+			// Create the command
+			cmd := newRootCmd(commands.NewTestIO())
+			args := []string{
+				"db",
+				"check",
+				"--data-dir",
+				dataDir,
+				"--height",
+				height,
+			}
+
+			// Run the command
+			cmdErr := cmd.ParseAndRun(context.Background(), args)
+			assert.ErrorIs(t, cmdErr, errInvalidCheckHeight)
+		}
+	})
+
+	t.Run("latest height", func(t *testing.T) {
+		t.Parallel()
+
+		dataDir, appHashes := initTestNodeDir(t, 3)
+
+		mockOut := new(bytes.Buffer)
+		io := commands.NewTestIO()
+		io.SetOut(commands.WriteNopCloser(mockOut))
+
+		// Create the command
+		cmd := newRootCmd(io)
+		args := []string{
+			"db",
+			"check",
+			"--data-dir",
+			dataDir,
+		}
+
+		// Run the command
+		require.NoError(t, cmd.ParseAndRun(context.Background(), args))
+		assert.Contains(t, mockOut.String(), fmt.Sprintf("App hash at height 3: %X, matches the state", appHashes[3]))
+	})
+
+	t.Run("previous height", func(t *testing.T) {
+		t.Parallel()
+
+		dataDir, appHashes := initTestNodeDir(t, 3)
+
+		mockOut := new(bytes.Buffer)
+		io := commands.NewTestIO()
+		io.SetOut(commands.WriteNopCloser(mockOut))
+
+		// Create the command
+		cmd := newRootCmd(io)
+		args := []string{
+			"db",
+			"check",
+			"--data-dir",
+			dataDir,
+			"--height",
+			"2",
+		}
+
+		// Run the command
+		require.NoError(t, cmd.ParseAndRun(context.Background(), args))
+		assert.Contains(t, mockOut.String(), fmt.Sprintf("App hash at height 2: %X, matches the header of block 3", appHashes[2]))
+	})
+
+	t.Run("app hash mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		dataDir, _ := initTestNodeDir(t, 3)
+
+		// Overwrite the app hash of the state
+		dbs, _, err := loadNodeDBs(dataDir)
+		require.NoError(t, err)
+
+		stateDB, err := dbs[2].open()
+		require.NoError(t, err)
+
+		sm.SaveState(stateDB, sm.State{LastBlockHeight: 3, AppHash: []byte("invalid")})
+		require.NoError(t, stateDB.Close())
+
+		// Create the command
+		cmd := newRootCmd(commands.NewTestIO())
+		args := []string{
+			"db",
+			"check",
+			"--data-dir",
+			dataDir,
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorIs(t, cmdErr, errAppHashMismatch)
+	})
+
+	t.Run("no committed blocks", func(t *testing.T) {
+		t.Parallel()
+
+		dataDir, _ := initTestNodeDir(t, 0)
+
+		// Create the command
+		cmd := newRootCmd(commands.NewTestIO())
+		args := []string{
+			"db",
+			"check",
+			"--data-dir",
+			dataDir,
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorIs(t, cmdErr, errNoCommittedBlocks)
+	})
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+)
+
+type dbCompactCfg struct {
+	dbCfg
+}
+
+// newDBCompactCmd creates the db compact command
+func newDBCompactCmd(io commands.IO) *commands.Command {
+	cfg := &dbCompactCfg{}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "compact",
+			ShortUsage: "db compact [flags] [<db>...]",
+			ShortHelp:  "compacts the databases",
+			LongHelp: "Compacts the given databases (all of them by default), reclaiming the space of the deleted " +
+				"and overwritten keys, for instance after pruning. The backends which can't compact are skipped",
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execDBCompact(cfg, args, io)
+		},
+	)
+}
+
+func (c *dbCompactCfg) RegisterFlags(fs *flag.FlagSet) {
+	c.dbCfg.RegisterFlags(fs)
+}
+
+func execDBCompact(cfg *dbCompactCfg, args []string, io commands.IO) error {
+	dbs, _, err := loadNodeDBs(cfg.dataDir)
+	if err != nil {
+		return err
+	}
+
+	dbs, err = selectNodeDBs(dbs, args)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range dbs {
+		if err := compactDB(d, io); err != nil {
+			return fmt.Errorf("unable to compact the %s database, %w", d.name, err)
+		}
+	}
+
+	return nil
+}
+
+// compactDB compacts the given database, if its backend supports it
+func compactDB(d nodeDB, io commands.IO) error {
+	db, err := d.open()
+	if err != nil {
+		return err
+	}
+
+	compacter, ok := db.(dbm.Compacter)
+	if !ok {
+		io.Printfln("%s: skipped, the %s backend can't compact", d.name, d.backend)
+
+		return db.Close()
+	}
+
+	before, err := dirSize(d.path())
+	if err != nil {
+		db.Close()
+
+		return err
+	}
+
+	if err := compacter.Compact(nil, nil); err != nil {
+		db.Close()
+
+		return err
+	}
+
+	// Close the database first, to flush any pending changes on disk
+	if err := db.Close(); err != nil {
+		return err
+	}
+
+	after, err := dirSize(d.path())
+	if err != nil {
+		return err
+	}
+
+	io.Printfln("%s: compacted from %d to %d bytes", d.name, before, after)
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/commands"
+)
+
+func TestDB_Compact(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing config", func(t *testing.T) {
+		t.Parallel()
+
+		// Create the command
+		cmd := newRootCmd(commands.NewTestIO())
+		args := []string{
+			"db",
+			"compact",
+			"--data-dir",
+			t.TempDir(),
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorContains(t, cmdErr, tryConfigInit)
+	})
+
+	t.Run("all databases", func(t *testing.T) {
+		t.Parallel()
+
+		dataDir, appHashes := initTestNodeDir(t, 3)
+
+		mockOut := new(bytes.Buffer)
+		io := commands.NewTestIO()
+		io.SetOut(commands.WriteNopCloser(mockOut))
+
+		// Create the command
+		cmd := newRootCmd(io)
+		args := []string{
+			"db",
+			"compact",
+			"--data-dir",
+			dataDir,
+		}
+
+		// Run the command
+		require.NoError(t, cmd.ParseAndRun(context.Background(), args))
+
+		out := mockOut.String()
+		assert.Contains(t, out, "gnolang: compacted from")
+		assert.Contains(t, out, "blockstore: compacted from")
+		assert.Contains(t, out, "state: compacted from")
+
+		// Make sure the databases are still consistent
+		mockOut.Reset()
+
+		cmd = newRootCmd(io)
+		args = []string{
+			"db",
+			"check",
+			"--data-dir",
+			dataDir,
+		}
+
+		require.NoError(t, cmd.ParseAndRun(context.Background(), args))
+		assert.Contains(t, mockOut.String(), fmt.Sprintf("%X", appHashes[3]))
+	})
+}
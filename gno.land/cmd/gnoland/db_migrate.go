@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoland"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+)
+
+// migrateBatchSize is the number of keys written per batch during a migration
+const migrateBatchSize = 10_000
+
+var (
+	errMissingMigrateBackend = errors.New("missing target backend")
+	errInvalidMigrateBackend = errors.New("invalid target backend")
+	errMissingMigrateDir     = errors.New("missing target directory")
+	errDBExists              = errors.New("database already exists")
+	errAppDBBackend          = errors.New("the app database only supports the " + string(gnoland.AppDBBackend) + " backend")
+)
+
+type dbMigrateCfg struct {
+	dbCfg
+
+	backend   string
+	targetDir string
+}
+
+// newDBMigrateCmd creates the db migrate command
+func newDBMigrateCmd(io commands.IO) *commands.Command {
+	cfg := &dbMigrateCfg{}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "migrate",
+			ShortUsage: "db migrate [flags] [<db>...]",
+			ShortHelp:  "copies the databases to another backend",
+			LongHelp: "Copies the given databases (the node databases by default) to new databases of the target backend, " +
+				"in the target directory. The source databases are left untouched: to use the new databases, " +
+				"set the db_backend and db_dir of the node config to the target backend and directory",
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execDBMigrate(cfg, args, io)
+		},
+	)
+}
+
+func (c *dbMigrateCfg) RegisterFlags(fs *flag.FlagSet) {
+	c.dbCfg.RegisterFlags(fs)
+
+	fs.StringVar(
+		&c.backend,
+		"backend",
+		"",
+		fmt.Sprintf("the target database backend (%v)", dbm.BackendList()),
+	)
+
+	fs.StringVar(
+		&c.targetDir,
+		"target-dir",
+		"",
+		"the directory of the target databases",
+	)
+}
+
+func execDBMigrate(cfg *dbMigrateCfg, args []string, io commands.IO) error {
+	if cfg.backend == "" {
+		return errMissingMigrateBackend
+	}
+
+	if cfg.targetDir == "" {
+		return errMissingMigrateDir
+	}
+
+	backend := dbm.BackendType(cfg.backend)
+	if backend == dbm.MemDBBackend || !slices.Contains(dbm.BackendList(), backend) {
+		return fmt.Errorf("%w %q, expected one of %v", errInvalidMigrateBackend, backend, dbm.BackendList())
+	}
+
+	dbs, _, err := loadNodeDBs(cfg.dataDir)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		// The app database doesn't follow the node db_backend
+		args = []string{blockStoreDBName, stateDBName}
+	}
+
+	dbs, err = selectNodeDBs(dbs, args)
+	if err != nil {
+		return err
+	}
+
+	targetDir, err := filepath.Abs(cfg.targetDir)
+	if err != nil {
+		return fmt.Errorf("unable to get absolute path for the target directory, %w", err)
+	}
+
+	for _, d := range dbs {
+		if d.name == gnoland.AppDBName && backend != gnoland.AppDBBackend {
+			return errAppDBBackend
+		}
+
+		target := nodeDB{
+			name:    d.name,
+			backend: backend,
+			dir:     targetDir,
+		}
+
+		keys, err := migrateDB(d, target)
+		if err != nil {
+			return fmt.Errorf("unable to migrate the %s database, %w", d.name, err)
+		}
+
+		io.Printfln("%s: migrated %d keys from %s to %s at %q", d.name, keys, d.backend, target.backend, target.path())
+	}
+
+	return nil
+}
+
+// migrateDB copies all the keys of the source database to the target
+// database, which must not exist yet. It returns the number of copied keys
+func migrateDB(source, target nodeDB) (int64, error) {
+	if _, err := os.Stat(target.path()); err == nil {
+		return 0, fmt.Errorf("%w: %q", errDBExists, target.path())
+	}
+
+	srcDB, err := source.open()
+	if err != nil {
+		return 0, err
+	}
+	defer srcDB.Close()
+
+	dstDB, err := dbm.NewDB(target.name, target.backend, target.dir)
+	if err != nil {
+		return 0, err
+	}
+	defer dstDB.Close()
+
+	it, err := srcDB.Iterator(nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer it.Close()
+
+	var (
+		keys  int64
+		batch = dstDB.NewBatch()
+	)
+
+	defer func() {
+		batch.Close()
+	}()
+
+	for ; it.Valid(); it.Next() {
+		if err := batch.Set(it.Key(), it.Value()); err != nil {
+			return keys, err
+		}
+
+		keys++
+
+		if keys%migrateBatchSize != 0 {
+			continue
+		}
+
+		if err := batch.Write(); err != nil {
+			return keys, err
+		}
+
+		batch.Close()
+		batch = dstDB.NewBatch()
+	}
+
+	if err := it.Error(); err != nil {
+		return keys, err
+	}
+
+	return keys, batch.WriteSync()
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	_ "github.com/gnolang/gno/tm2/pkg/db/goleveldb" // only available with a build tag in the binary
+)
+
+// countDBKeys returns the number of keys of the given database
+func countDBKeys(t *testing.T, d nodeDB) int {
+	t.Helper()
+
+	db, err := d.open()
+	require.NoError(t, err)
+	defer db.Close()
+
+	it, err := db.Iterator(nil, nil)
+	require.NoError(t, err)
+	defer it.Close()
+
+	keys := 0
+	for ; it.Valid(); it.Next() {
+		keys++
+	}
+
+	return keys
+}
+
+func TestDB_Migrate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing backend", func(t *testing.T) {
+		t.Parallel()
+
+		// Create the command
+		cmd := newRootCmd(commands.NewTestIO())
+		args := []string{
+			"db",
+			"migrate",
+			"--target-dir",
+			t.TempDir(),
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorIs(t, cmdErr, errMissingMigrateBackend)
+	})
+
+	t.Run("missing target directory", func(t *testing.T) {
+		t.Parallel()
+
+		// Create the command
+		cmd := newRootCmd(commands.NewTestIO())
+		args := []string{
+			"db",
+			"migrate",
+			"--backend",
+			dbm.PebbleDBBackend.String(),
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorIs(t, cmdErr, errMissingMigrateDir)
+	})
+
+	t.Run("invalid backend", func(t *testing.T) {
+		t.Parallel()
+
+		for _, backend := range []string{"unknown", dbm.MemDBBackend.String()} {
+			// Create the command
+			cmd := newRootCmd(commands.NewTestIO())
+			args := []string{
+				"db",
+				"migrate",
+				"--backend",
+				backend,
+				"--target-dir",
+				t.TempDir(),
+			}
+
+			// Run the command
+			cmdErr := cmd.ParseAndRun(context.Background(), args)
+			assert.ErrorIs(t, cmdErr, errInvalidMigrateBackend)
+		}
+	})
+
+	t.Run("node databases migrated", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			dataDir, _ = initTestNodeDir(t, 3)
+			targetDir  = filepath.Join(t.TempDir(), "db")
+		)
+
+		mockOut := new(bytes.Buffer)
+		io := commands.NewTestIO()
+		io.SetOut(commands.WriteNopCloser(mockOut))
+
+		// Create the command
+		cmd := newRootCmd(io)
+		args := []string{
+			"db",
+			"migrate",
+			"--data-dir",
+			dataDir,
+			"--backend",
+			dbm.GoLevelDBBackend.String(),
+			"--target-dir",
+			targetDir,
+		}
+
+		// Run the command
+		require.NoError(t, cmd.ParseAndRun(context.Background(), args))
+
+		out := mockOut.String()
+		assert.Contains(t, out, "blockstore: migrated")
+		assert.Contains(t, out, "state: migrated")
+		assert.NotContains(t, out, "gnolang")
+
+		// Make sure all the keys were copied
+		dbs, _, err := loadNodeDBs(dataDir)
+		require.NoError(t, err)
+
+		for _, d := range dbs[1:] {
+			target := nodeDB{name: d.name, backend: dbm.GoLevelDBBackend, dir: targetDir}
+			assert.Equal(t, countDBKeys(t, d), countDBKeys(t, target), d.name)
+		}
+
+		// Make sure the migrated databases are not overwritten
+		cmd = newRootCmd(io)
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorIs(t, cmdErr, errDBExists)
+	})
+
+	t.Run("app database backend", func(t *testing.T) {
+		t.Parallel()
+
+		dataDir, _ := initTestNodeDir(t, 1)
+
+		// Create the command
+		cmd := newRootCmd(commands.NewTestIO())
+		args := []string{
+			"db",
+			"migrate",
+			"--data-dir",
+			dataDir,
+			"--backend",
+			dbm.GoLevelDBBackend.String(),
+			"--target-dir",
+			t.TempDir(),
+			"gnolang",
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorIs(t, cmdErr, errAppDBBackend)
+	})
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoland"
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+)
+
+const (
+	gnoObjectPrefix   = "s/_/oid:"
+	gnoPkgIndexPrefix = "s/_/pkgidx:"
+)
+
+// appKeyPrefixes are the known prefixes of the app database keys, matched in
+// order. The IAVL (main) and the Gno (base) stores share the "s/_/" prefix.
+var appKeyPrefixes = []struct {
+	prefix string
+	desc   string
+}{
+	{gnoObjectPrefix, "gno objects"},
+	{"s/_/tid:", "gno types"},
+	{"s/_/node:", "gno nodes"},
+	{gnoPkgIndexPrefix, "gno package index"},
+	{"s/_/s", "iavl nodes"},
+	{"s/_/f", "iavl fast nodes"},
+	{"s/_/m", "iavl metadata"},
+	{"s/_/o", "iavl orphans (legacy)"},
+	{"s/_/r", "iavl roots (legacy)"},
+	{"s/_/", "other store keys"},
+	{"s/latest", "latest version"},
+	{"s/", "commit infos"},
+	{"h/", "gno store history"},
+}
+
+type dbStatsCfg struct {
+	dbCfg
+
+	top int
+}
+
+// newDBStatsCmd creates the db stats command
+func newDBStatsCmd(io commands.IO) *commands.Command {
+	cfg := &dbStatsCfg{}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "stats",
+			ShortUsage: "db stats [flags] [<db>...]",
+			ShortHelp:  "shows the number of keys and their size per prefix",
+			LongHelp: "Shows the number of keys and their size (keys and values) per prefix, for the given databases " +
+				"(all of them by default). For the app database, also shows the objects of each package or realm in the Gno store",
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execDBStats(cfg, args, io)
+		},
+	)
+}
+
+func (c *dbStatsCfg) RegisterFlags(fs *flag.FlagSet) {
+	c.dbCfg.RegisterFlags(fs)
+
+	fs.IntVar(
+		&c.top,
+		"top",
+		20,
+		"the number of packages to show, by decreasing size (0 for all)",
+	)
+}
+
+// prefixStats are the stats of the keys sharing a prefix
+type prefixStats struct {
+	prefix string
+	desc   string
+	keys   int64
+	size   int64
+}
+
+// pkgStats are the stats of the objects of a package in the Gno store
+type pkgStats struct {
+	path    string
+	objects int64
+	size    int64
+}
+
+// dbStats are the stats of a database
+type dbStats struct {
+	keys     int64
+	size     int64
+	prefixes []*prefixStats
+	packages []*pkgStats
+}
+
+func execDBStats(cfg *dbStatsCfg, args []string, io commands.IO) error {
+	if cfg.top < 0 {
+		return fmt.Errorf("invalid number of packages %d", cfg.top)
+	}
+
+	dbs, _, err := loadNodeDBs(cfg.dataDir)
+	if err != nil {
+		return err
+	}
+
+	dbs, err = selectNodeDBs(dbs, args)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range dbs {
+		db, err := d.open()
+		if err != nil {
+			return err
+		}
+
+		stats, err := collectDBStats(db, d.name == gnoland.AppDBName)
+		db.Close()
+
+		if err != nil {
+			return fmt.Errorf("unable to collect the stats of the %s database, %w", d.name, err)
+		}
+
+		printDBStats(io, d, stats, cfg.top)
+	}
+
+	return nil
+}
+
+// collectDBStats iterates over all the keys of db to collect its stats.
+// The keys of the app database are grouped by store, and its Gno objects by
+// package
+func collectDBStats(db dbm.DB, app bool) (*dbStats, error) {
+	it, err := db.Iterator(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	var (
+		stats    = &dbStats{}
+		prefixes = make(map[string]*prefixStats)
+		packages = make(map[string]*pkgStats)
+		paths    = make(map[string]string) // package ID -> path
+	)
+
+	for ; it.Valid(); it.Next() {
+		key, value := it.Key(), it.Value()
+		size := int64(len(key) + len(value))
+
+		prefix, desc := keyPrefix(key), ""
+		if app {
+			prefix, desc = appKeyPrefix(key)
+		}
+
+		ps, ok := prefixes[prefix]
+		if !ok {
+			ps = &prefixStats{prefix: prefix, desc: desc}
+			prefixes[prefix] = ps
+		}
+
+		ps.keys++
+		ps.size += size
+		stats.keys++
+		stats.size += size
+
+		if !app {
+			continue
+		}
+
+		switch {
+		case bytes.HasPrefix(key, []byte(gnoObjectPrefix)):
+			// oid:<package ID>:<new time>[#realm]
+			oid := strings.TrimPrefix(string(key), gnoObjectPrefix)
+			pid, _, _ := strings.Cut(oid, ":")
+
+			pkg, ok := packages[pid]
+			if !ok {
+				pkg = &pkgStats{}
+				packages[pid] = pkg
+			}
+
+			if !strings.HasSuffix(oid, "#realm") {
+				pkg.objects++
+			}
+			pkg.size += size
+		case bytes.HasPrefix(key, []byte(gnoPkgIndexPrefix)):
+			// pkgidx:<index> -> path, along with the pkgidx:counter
+			if string(key) == gnoPkgIndexPrefix+"counter" {
+				continue
+			}
+
+			path := string(value)
+			pid := gno.PkgIDFromPkgPath(path)
+			paths[hex.EncodeToString(pid.Bytes())] = path
+		}
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	for _, ps := range prefixes {
+		stats.prefixes = append(stats.prefixes, ps)
+	}
+
+	slices.SortFunc(stats.prefixes, func(a, b *prefixStats) int {
+		return strings.Compare(a.prefix, b.prefix)
+	})
+
+	for pid, pkg := range packages {
+		pkg.path = paths[pid]
+		if pkg.path == "" {
+			pkg.path = pid
+		}
+
+		stats.packages = append(stats.packages, pkg)
+	}
+
+	slices.SortFunc(stats.packages, func(a, b *pkgStats) int {
+		return cmp.Or(cmp.Compare(b.size, a.size), strings.Compare(a.path, b.path))
+	})
+
+	return stats, nil
+}
+
+// keyPrefix returns the prefix under which key is counted: the key up to its
+// first colon, or the whole key if it has none
+func keyPrefix(key []byte) string {
+	if len(key) == 0 {
+		// Written by the block store to flush its writes
+		return "(empty)"
+	}
+
+	if idx := bytes.IndexByte(key, ':'); idx != -1 && isPrintable(key[:idx]) {
+		return string(key[:idx+1])
+	}
+
+	if len(key) <= 32 && isPrintable(key) {
+		return string(key)
+	}
+
+	return "(other)"
+}
+
+// appKeyPrefix returns the prefix under which the app database key is
+// counted, and its description
+func appKeyPrefix(key []byte) (string, string) {
+	for _, p := range appKeyPrefixes {
+		if bytes.HasPrefix(key, []byte(p.prefix)) {
+			return p.prefix, p.desc
+		}
+	}
+
+	return keyPrefix(key), ""
+}
+
+func isPrintable(bz []byte) bool {
+	for _, b := range bz {
+		if b < 0x20 || b > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+func printDBStats(io commands.IO, d nodeDB, stats *dbStats, top int) {
+	io.Printfln("%s (%s): %d keys, %d bytes", d.name, d.backend, stats.keys, stats.size)
+	io.Println()
+
+	w := tabwriter.NewWriter(io.Out(), 0, 2, 2, ' ', 0)
+
+	fmt.Fprintln(w, "PREFIX\tKEYS\tSIZE\tDESCRIPTION")
+	for _, ps := range stats.prefixes {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", ps.prefix, ps.keys, ps.size, ps.desc)
+	}
+
+	w.Flush()
+	io.Println()
+
+	if len(stats.packages) == 0 {
+		return
+	}
+
+	packages := stats.packages
+	if top > 0 && len(packages) > top {
+		packages = packages[:top]
+	}
+
+	fmt.Fprintln(w, "PACKAGE\tOBJECTS\tSIZE")
+	for _, pkg := range packages {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", pkg.path, pkg.objects, pkg.size)
+	}
+
+	w.Flush()
+
+	if len(packages) < len(stats.packages) {
+		io.Printfln("... and %d more packages", len(stats.packages)-len(packages))
+	}
+
+	io.Println()
+}
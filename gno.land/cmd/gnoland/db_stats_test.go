@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/commands"
+)
+
+func TestDB_Stats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing config", func(t *testing.T) {
+		t.Parallel()
+
+		// Create the command
+		cmd := newRootCmd(commands.NewTestIO())
+		args := []string{
+			"db",
+			"stats",
+			"--data-dir",
+			t.TempDir(),
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorContains(t, cmdErr, tryConfigInit)
+	})
+
+	t.Run("unknown database", func(t *testing.T) {
+		t.Parallel()
+
+		dataDir, _ := initTestNodeDir(t, 1)
+
+		// Create the command
+		cmd := newRootCmd(commands.NewTestIO())
+		args := []string{
+			"db",
+			"stats",
+			"--data-dir",
+			dataDir,
+			"unknown",
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorIs(t, cmdErr, errUnknownDB)
+	})
+
+	t.Run("invalid number of packages", func(t *testing.T) {
+		t.Parallel()
+
+		// Create the command
+		cmd := newRootCmd(commands.NewTestIO())
+		args := []string{
+			"db",
+			"stats",
+			"--top",
+			"-1",
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorContains(t, cmdErr, "invalid number of packages")
+	})
+
+	t.Run("all databases", func(t *testing.T) {
+		t.Parallel()
+
+		dataDir, _ := initTestNodeDir(t, 3)
+
+		mockOut := new(bytes.Buffer)
+		io := commands.NewTestIO()
+		io.SetOut(commands.WriteNopCloser(mockOut))
+
+		// Create the command
+		cmd := newRootCmd(io)
+		args := []string{
+			"db",
+			"stats",
+			"--data-dir",
+			dataDir,
+		}
+
+		// Run the command
+		require.NoError(t, cmd.ParseAndRun(context.Background(), args))
+
+		out := mockOut.String()
+		assert.Contains(t, out, "gnolang (pebbledb)")
+		assert.Contains(t, out, "blockstore (pebbledb)")
+		assert.Contains(t, out, "state (pebbledb)")
+
+		// Keys per prefix
+		assert.Regexp(t, `s/_/oid:\s+4\s+\d+\s+gno objects`, out)
+		assert.Regexp(t, `s/_/pkgidx:\s+2\s+\d+\s+gno package index`, out)
+		assert.Regexp(t, `s/latest\s+1\s+\d+\s+latest version`, out)
+		assert.Regexp(t, `H:\s+3\s+\d+`, out)
+
+		// Objects per package, without the realm
+		assert.Regexp(t, testDBRealmPath+`\s+3\s+\d+`, out)
+	})
+
+	t.Run("selected database", func(t *testing.T) {
+		t.Parallel()
+
+		dataDir, _ := initTestNodeDir(t, 1)
+
+		mockOut := new(bytes.Buffer)
+		io := commands.NewTestIO()
+		io.SetOut(commands.WriteNopCloser(mockOut))
+
+		// Create the command
+		cmd := newRootCmd(io)
+		args := []string{
+			"db",
+			"stats",
+			"--data-dir",
+			dataDir,
+			"state",
+		}
+
+		// Run the command
+		require.NoError(t, cmd.ParseAndRun(context.Background(), args))
+
+		out := mockOut.String()
+		assert.Contains(t, out, "state (pebbledb)")
+		assert.NotContains(t, out, "gnolang")
+		assert.NotContains(t, out, "blockstore")
+	})
+}
+
+func TestKeyPrefix(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name     string
+		key      []byte
+		expected string
+	}{
+		{"colon", []byte("H:10"), "H:"},
+		{"first colon", []byte("P:10:0"), "P:"},
+		{"no colon", []byte("stateKey"), "stateKey"},
+		{"empty", nil, "(empty)"},
+		{"binary", []byte{0x01, 0x02, ':'}, "(other)"},
+		{"long", bytes.Repeat([]byte("a"), 33), "(other)"},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.expected, keyPrefix(testCase.key))
+		})
+	}
+}
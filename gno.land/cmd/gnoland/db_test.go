@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoland"
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/bft/config"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	bftstore "github.com/gnolang/gno/tm2/pkg/bft/store"
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/store"
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+)
+
+const testDBRealmPath = "gno.land/r/demo/db"
+
+// initTestNodeDir initializes a node data directory with a default config,
+// and the databases of a chain of the given height. It returns the app hashes
+// after each height
+func initTestNodeDir(t *testing.T, height int64) (string, [][]byte) {
+	t.Helper()
+
+	dataDir := t.TempDir()
+
+	// Initialize the config
+	cfg := &configInitCfg{
+		configCfg: configCfg{
+			configPath: constructConfigPath(dataDir),
+		},
+	}
+	require.NoError(t, execConfigInit(cfg, commands.NewTestIO()))
+
+	nodeCfg, err := config.LoadConfig(dataDir)
+	require.NoError(t, err)
+
+	// Commit the app state, with the objects of a realm in the Gno store
+	appDB, err := dbm.NewDB(gnoland.AppDBName, gnoland.AppDBBackend, filepath.Join(dataDir, config.DefaultDBDir))
+	require.NoError(t, err)
+
+	var (
+		mainKey = store.NewStoreKey("main")
+		baseKey = store.NewStoreKey("base")
+		cms     = store.NewCommitMultiStore(appDB)
+	)
+
+	cms.SetStoreOptions(store.StoreOptions{PruningOptions: store.PruneNothing})
+	cms.MountStoreWithDB(mainKey, iavl.StoreConstructor, appDB)
+	cms.MountStoreWithDB(baseKey, dbadapter.StoreConstructor, appDB)
+	require.NoError(t, cms.LoadLatestVersion())
+
+	pid := hex.EncodeToString(gno.PkgIDFromPkgPath(testDBRealmPath).Bytes())
+	cms.GetStore(baseKey).Set([]byte("pkgidx:counter"), []byte("1"))
+	cms.GetStore(baseKey).Set([]byte(fmt.Sprintf("pkgidx:%020d", 1)), []byte(testDBRealmPath))
+	cms.GetStore(baseKey).Set([]byte("oid:"+pid+":1#realm"), []byte("realm"))
+
+	appHashes := make([][]byte, height+1)
+	for h := int64(1); h <= height; h++ {
+		cms.GetStore(mainKey).Set([]byte("height"), []byte(fmt.Sprint(h)))
+		cms.GetStore(baseKey).Set([]byte(fmt.Sprintf("oid:%s:%d", pid, h)), []byte("object"))
+
+		appHashes[h] = cms.Commit().Hash
+	}
+	require.NoError(t, appDB.Close())
+
+	// Save the blocks, with the app hash of the previous height
+	blockStoreDB, err := dbm.NewDB(blockStoreDBName, dbm.BackendType(nodeCfg.DBBackend), nodeCfg.DBDir())
+	require.NoError(t, err)
+
+	var (
+		blockStore = bftstore.NewBlockStore(blockStoreDB)
+		lastCommit = new(bft.Commit)
+	)
+
+	for h := int64(1); h <= height; h++ {
+		block := bft.MakeBlock(h, nil, lastCommit)
+		block.AppHash = appHashes[h-1]
+
+		seenCommit := bft.NewCommit(bft.BlockID{}, []*bft.CommitSig{{Height: h}})
+		blockStore.SaveBlock(block, block.MakePartSet(1024), seenCommit)
+		lastCommit = seenCommit
+	}
+	require.NoError(t, blockStoreDB.Close())
+
+	// Save the state at the latest height
+	stateDB, err := dbm.NewDB(stateDBName, dbm.BackendType(nodeCfg.DBBackend), nodeCfg.DBDir())
+	require.NoError(t, err)
+
+	sm.SaveState(stateDB, sm.State{LastBlockHeight: height, AppHash: appHashes[height]})
+	require.NoError(t, stateDB.Close())
+
+	return dataDir, appHashes
+}
@@ -8,6 +8,10 @@ import (
 type Client struct {
 	Signer    Signer           // Signer for transaction authentication
 	RPCClient rpcclient.Client // RPC client for blockchain communication
+
+	// NonceManager, if set, provides the account number and sequence of
+	// the transactions which don't specify them, see [NewNonceManager].
+	NonceManager *NonceManager
 }
 
 // validateSigner checks that the signer is correctly configured.
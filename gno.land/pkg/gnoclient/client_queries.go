@@ -2,8 +2,10 @@ package gnoclient
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
+	"github.com/gnolang/gno/gnovm/pkg/gnolang"
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
@@ -82,6 +84,32 @@ func (c *Client) QueryAppVersion() (string, *ctypes.ResultABCIQuery, error) {
 	return version, qres, nil
 }
 
+// QueryGasPrice retrieves the gas price of the last block, which is the
+// minimum price accepted by the chain for new transactions.
+func (c *Client) QueryGasPrice() (*std.GasPrice, *ctypes.ResultABCIQuery, error) {
+	if err := c.validateRPCClient(); err != nil {
+		return nil, nil, err
+	}
+
+	path := "auth/gasprice"
+	data := []byte{}
+
+	qres, err := c.RPCClient.ABCIQuery(context.Background(), path, data)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "query gas price")
+	}
+	if qres.Response.Error != nil {
+		return nil, qres, errors.Wrap(qres.Response.Error, "query gas price")
+	}
+
+	gp := &std.GasPrice{}
+	if err := amino.UnmarshalJSON(qres.Response.Data, gp); err != nil {
+		return nil, qres, errors.Wrap(err, "unmarshaling gas price")
+	}
+
+	return gp, qres, nil
+}
+
 // Render calls the Render function for pkgPath with optional args. The pkgPath should
 // include the prefix like "gno.land/". This is similar to using a browser URL
 // <testnet>/<pkgPath>:<args> where <pkgPath> doesn't have the prefix like "gno.land/".
@@ -127,6 +155,32 @@ func (c *Client) QEval(pkgPath string, expression string) (string, *ctypes.Resul
 	return string(qres.Response.Data), qres, nil
 }
 
+// QEvalJSON is like QEval, but returns the results of the expression as
+// JSONValues, which can be decoded without parsing the typed expressions.
+func (c *Client) QEvalJSON(pkgPath string, expression string) ([]gnolang.JSONValue, *ctypes.ResultABCIQuery, error) {
+	if err := c.validateRPCClient(); err != nil {
+		return nil, nil, err
+	}
+
+	path := "vm/qeval_json"
+	data := fmt.Appendf(nil, "%s.%s", pkgPath, expression)
+
+	qres, err := c.RPCClient.ABCIQuery(context.Background(), path, data)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "query qeval_json")
+	}
+	if qres.Response.Error != nil {
+		return nil, qres, errors.Wrapf(qres.Response.Error, "QEvalJSON failed: log:%s", qres.Response.Log)
+	}
+
+	var results []gnolang.JSONValue
+	if err := json.Unmarshal(qres.Response.Data, &results); err != nil {
+		return nil, qres, errors.Wrap(err, "unmarshaling qeval_json results")
+	}
+
+	return results, qres, nil
+}
+
 // Block gets the latest block at height, if any
 // Height must be larger than 0
 func (c *Client) Block(height int64) (*ctypes.ResultBlock, error) {
@@ -65,6 +65,36 @@ func TestRender(t *testing.T) {
 	assert.Equal(t, data.Response.Data, expectedRender)
 }
 
+func TestQEvalJSON(t *testing.T) {
+	t.Parallel()
+
+	client := Client{
+		Signer: &mockSigner{},
+		RPCClient: &mockRPCClient{
+			abciQuery: func(ctx context.Context, path string, data []byte) (*ctypes.ResultABCIQuery, error) {
+				assert.Equal(t, "vm/qeval_json", path)
+				assert.Equal(t, "gno.land/r/demo.Get(1)", string(data))
+				res := &ctypes.ResultABCIQuery{
+					Response: abci.ResponseQuery{
+						ResponseBase: abci.ResponseBase{
+							Data: []byte(`[{"T":"int","V":"1"},{"T":"*errors.errorString","V":{"ObjectID":"x"}}]`),
+						},
+					},
+				}
+				return res, nil
+			},
+		},
+	}
+
+	res, _, err := client.QEvalJSON("gno.land/r/demo", "Get(1)")
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Equal(t, "int", res[0].T)
+	assert.JSONEq(t, `"1"`, string(res[0].V))
+	assert.Equal(t, "*errors.errorString", res[1].T)
+	assert.JSONEq(t, `{"ObjectID":"x"}`, string(res[1].V))
+}
+
 // Call tests
 func TestCallSingle(t *testing.T) {
 	t.Parallel()
@@ -250,8 +280,8 @@ func TestCallErrors(t *testing.T) {
 		{
 			name: "Invalid RPCClient",
 			client: Client{
-				&mockSigner{},
-				nil,
+				Signer:    &mockSigner{},
+				RPCClient: nil,
 			},
 			cfg: BaseTxCfg{
 				GasWanted:      100000,
@@ -442,8 +472,8 @@ func TestClient_Send_Errors(t *testing.T) {
 		{
 			name: "Invalid RPCClient",
 			client: Client{
-				&mockSigner{},
-				nil,
+				Signer:    &mockSigner{},
+				RPCClient: nil,
 			},
 			cfg: BaseTxCfg{
 				GasWanted:      100000,
@@ -818,8 +848,8 @@ func TestRunErrors(t *testing.T) {
 		{
 			name: "Invalid RPCClient",
 			client: Client{
-				&mockSigner{},
-				nil,
+				Signer:    &mockSigner{},
+				RPCClient: nil,
 			},
 			cfg: BaseTxCfg{
 				GasWanted:      100000,
@@ -1017,8 +1047,8 @@ func TestAddPackageErrors(t *testing.T) {
 		{
 			name: "Invalid RPCClient",
 			client: Client{
-				&mockSigner{},
-				nil,
+				Signer:    &mockSigner{},
+				RPCClient: nil,
 			},
 			cfg: BaseTxCfg{
 				GasWanted:      100000,
@@ -1255,8 +1285,8 @@ func TestBlockErrors(t *testing.T) {
 		{
 			name: "Invalid RPCClient",
 			client: Client{
-				&mockSigner{},
-				nil,
+				Signer:    &mockSigner{},
+				RPCClient: nil,
 			},
 			height:        1,
 			expectedError: ErrMissingRPCClient,
@@ -1264,8 +1294,8 @@ func TestBlockErrors(t *testing.T) {
 		{
 			name: "Invalid height",
 			client: Client{
-				&mockSigner{},
-				&mockRPCClient{},
+				Signer:    &mockSigner{},
+				RPCClient: &mockRPCClient{},
 			},
 			height:        0,
 			expectedError: ErrInvalidBlockHeight,
@@ -1296,8 +1326,8 @@ func TestBlockResultErrors(t *testing.T) {
 		{
 			name: "Invalid RPCClient",
 			client: Client{
-				&mockSigner{},
-				nil,
+				Signer:    &mockSigner{},
+				RPCClient: nil,
 			},
 			height:        1,
 			expectedError: ErrMissingRPCClient,
@@ -1305,8 +1335,8 @@ func TestBlockResultErrors(t *testing.T) {
 		{
 			name: "Invalid height",
 			client: Client{
-				&mockSigner{},
-				&mockRPCClient{},
+				Signer:    &mockSigner{},
+				RPCClient: &mockRPCClient{},
 			},
 			height:        0,
 			expectedError: ErrInvalidBlockHeight,
@@ -1336,8 +1366,8 @@ func TestLatestBlockHeightErrors(t *testing.T) {
 		{
 			name: "Invalid RPCClient",
 			client: Client{
-				&mockSigner{},
-				nil,
+				Signer:    &mockSigner{},
+				RPCClient: nil,
 			},
 			expectedError: ErrMissingRPCClient,
 		},
@@ -1624,3 +1654,143 @@ func TestClient_EstimateGas(t *testing.T) {
 		assert.Equal(t, "1000ugnot", coinsDelta.String())
 	})
 }
+
+func TestTxBuilder(t *testing.T) {
+	t.Parallel()
+
+	caller, _ := crypto.AddressFromBech32("g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5")
+	newClient := func(t *testing.T, broadcast func(tx std.Tx)) *Client {
+		t.Helper()
+
+		simulated, err := amino.Marshal(&abci.ResponseDeliverTx{GasUsed: 100_000})
+		require.NoError(t, err)
+
+		return &Client{
+			Signer: &mockSigner{
+				sign: func(cfg SignCfg) (*std.Tx, error) {
+					assert.Equal(t, uint64(7), cfg.AccountNumber)
+					assert.Equal(t, uint64(42), cfg.SequenceNumber)
+					return &cfg.UnsignedTX, nil
+				},
+				info: func() (keys.Info, error) {
+					return &mockKeysInfo{
+						getAddress: func() crypto.Address { return caller },
+					}, nil
+				},
+			},
+			RPCClient: &mockRPCClient{
+				abciQuery: func(ctx context.Context, path string, data []byte) (*ctypes.ResultABCIQuery, error) {
+					var res abci.ResponseQuery
+					switch path {
+					case "auth/accounts/" + caller.String():
+						res.Data = []byte(`{"BaseAccount":{"address":"` + caller.String() + `","account_number":"7","sequence":"42"}}`)
+					case simulatePath:
+						var tx std.Tx
+						require.NoError(t, amino.Unmarshal(data, &tx))
+						assert.Equal(t, std.NewCoin(ugnot.Denom, 1), tx.Fee.GasFee)
+						assert.Equal(t, int64(1_000_000), tx.Fee.GasWanted)
+						res.Value = simulated
+					case "auth/gasprice":
+						res.Data = []byte(`{"gas":"1000","price":"1ugnot"}`)
+					default:
+						t.Fatalf("unexpected query: %s", path)
+					}
+					return &ctypes.ResultABCIQuery{Response: res}, nil
+				},
+				consensusParams: func(ctx context.Context, height *int64) (*ctypes.ResultConsensusParams, error) {
+					return &ctypes.ResultConsensusParams{
+						ConsensusParams: abci.ConsensusParams{
+							Block: &abci.BlockParams{MaxGas: 1_000_000},
+						},
+					}, nil
+				},
+				broadcastTxCommit: func(ctx context.Context, bz types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
+					var tx std.Tx
+					require.NoError(t, amino.Unmarshal(bz, &tx))
+					broadcast(tx)
+					return &ctypes.ResultBroadcastTxCommit{}, nil
+				},
+			},
+		}
+	}
+
+	msgAddPkg := vm.MsgAddPackage{
+		Creator: caller,
+		Package: &std.MemPackage{
+			Name:  "echo",
+			Path:  "gno.land/r/test/echo",
+			Files: []*std.MemFile{{Name: "echo.gno", Body: "package echo"}},
+		},
+	}
+	msgCall := vm.MsgCall{
+		Caller:  caller,
+		PkgPath: "gno.land/r/test/echo",
+		Func:    "Init",
+	}
+	msgSend := bank.MsgSend{
+		FromAddress: caller,
+		ToAddress:   caller,
+		Amount:      std.NewCoins(std.NewCoin(ugnot.Denom, 10)),
+	}
+
+	t.Run("estimate and broadcast", func(t *testing.T) {
+		t.Parallel()
+
+		var broadcasted *std.Tx
+		client := newClient(t, func(tx std.Tx) { broadcasted = &tx })
+		b := client.NewTxBuilder().AddPackage(msgAddPkg).AddCall(msgCall).AddSend(msgSend)
+
+		cfg, err := b.Estimate(BaseTxCfg{Memo: "deploy"})
+		require.NoError(t, err)
+		assert.Equal(t, BaseTxCfg{
+			GasWanted:      110_000,
+			GasFee:         "116ugnot", // (110_000/1000+1) * 1ugnot + 5%
+			AccountNumber:  7,
+			SequenceNumber: 42,
+			Memo:           "deploy",
+		}, cfg)
+
+		_, err = b.Broadcast(BaseTxCfg{Memo: "deploy"})
+		require.NoError(t, err)
+		require.NotNil(t, broadcasted)
+		require.Len(t, broadcasted.Msgs, 3)
+		assert.Equal(t, "add_package", broadcasted.Msgs[0].Type())
+		assert.Equal(t, "exec", broadcasted.Msgs[1].Type())
+		assert.Equal(t, "send", broadcasted.Msgs[2].Type())
+		assert.Equal(t, std.NewFee(110_000, std.NewCoin(ugnot.Denom, 116)), broadcasted.Fee)
+		assert.Equal(t, "deploy", broadcasted.Memo)
+	})
+
+	t.Run("explicit gas", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(t, func(tx std.Tx) {
+			assert.Equal(t, std.NewFee(2000, std.NewCoin(ugnot.Denom, 3)), tx.Fee)
+		})
+		_, err := client.NewTxBuilder().AddCall(msgCall).Broadcast(BaseTxCfg{
+			GasWanted:      2000,
+			GasFee:         "3ugnot",
+			AccountNumber:  7,
+			SequenceNumber: 42,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(t, func(std.Tx) { t.Fatal("unexpected broadcast") })
+
+		_, err := client.NewTxBuilder().Broadcast(BaseTxCfg{})
+		assert.ErrorIs(t, err, ErrEmptyTx)
+
+		_, err = client.NewTxBuilder().AddCall(vm.MsgCall{Caller: caller}).Broadcast(BaseTxCfg{})
+		assert.ErrorContains(t, err, "invalid package path")
+
+		_, err = client.NewTxBuilder().AddCall(msgCall).Build(BaseTxCfg{GasFee: "1ugnot"})
+		assert.ErrorIs(t, err, ErrInvalidGasWanted)
+
+		_, err = (&Client{RPCClient: client.RPCClient}).NewTxBuilder().AddCall(msgCall).Broadcast(BaseTxCfg{})
+		assert.ErrorIs(t, err, ErrMissingSigner)
+	})
+}
@@ -0,0 +1,209 @@
+package gnoclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoland/ugnot"
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/overflow"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+var ErrEmptyTx = errors.New("transaction has no messages")
+
+const (
+	// defaultSimulateGasWanted is the gas wanted when simulating a
+	// transaction, if the block gas limit is unknown.
+	defaultSimulateGasWanted = 3_000_000_000
+	// gasWantedBuffer is the percentage added to the simulated gas usage,
+	// to cover the costs not accounted for by the simulation (e.g. fee
+	// deduction).
+	gasWantedBuffer = 10
+	// gasFeeBuffer is the percentage added to the estimated fee, to cover
+	// sudden changes of the gas price.
+	gasFeeBuffer = 5
+)
+
+// TxBuilder builds a transaction made of several messages, possibly of
+// different kinds, which are executed atomically: if one of them fails, none
+// of them is committed.
+//
+//	res, err := client.NewTxBuilder().
+//		AddPackage(addPkgMsg).
+//		AddCall(initMsg).
+//		AddSend(fundMsg).
+//		Broadcast(gnoclient.BaseTxCfg{})
+type TxBuilder struct {
+	client *Client
+	msgs   []std.Msg
+}
+
+// NewTxBuilder returns an empty transaction builder, using c to estimate,
+// sign and broadcast the transaction.
+func (c *Client) NewTxBuilder() *TxBuilder {
+	return &TxBuilder{client: c}
+}
+
+// AddCall appends one or more MsgCall to the transaction.
+func (b *TxBuilder) AddCall(msgs ...vm.MsgCall) *TxBuilder {
+	for _, msg := range msgs {
+		b.msgs = append(b.msgs, msg)
+	}
+	return b
+}
+
+// AddRun appends one or more MsgRun to the transaction.
+func (b *TxBuilder) AddRun(msgs ...vm.MsgRun) *TxBuilder {
+	for _, msg := range msgs {
+		b.msgs = append(b.msgs, msg)
+	}
+	return b
+}
+
+// AddPackage appends one or more MsgAddPackage to the transaction.
+func (b *TxBuilder) AddPackage(msgs ...vm.MsgAddPackage) *TxBuilder {
+	for _, msg := range msgs {
+		b.msgs = append(b.msgs, msg)
+	}
+	return b
+}
+
+// AddSend appends one or more MsgSend to the transaction.
+func (b *TxBuilder) AddSend(msgs ...bank.MsgSend) *TxBuilder {
+	for _, msg := range msgs {
+		b.msgs = append(b.msgs, msg)
+	}
+	return b
+}
+
+// Msgs returns the messages of the transaction, in order.
+func (b *TxBuilder) Msgs() []std.Msg {
+	return b.msgs
+}
+
+// Build makes an unsigned transaction from the messages. GasWanted and GasFee
+// must be set in cfg; see [TxBuilder.Estimate] to compute them.
+func (b *TxBuilder) Build(cfg BaseTxCfg) (*std.Tx, error) {
+	if len(b.msgs) == 0 {
+		return nil, ErrEmptyTx
+	}
+	return NewTx(cfg, b.msgs...)
+}
+
+// Estimate simulates the transaction, and returns cfg completed with the gas
+// wanted and the gas fee required for the transaction to be accepted, as well
+// as the account number and sequence of the signer, unless they are provided
+// by the NonceManager of the client.
+// The values already set in cfg are kept.
+func (b *TxBuilder) Estimate(cfg BaseTxCfg) (BaseTxCfg, error) {
+	if err := b.client.validateSigner(); err != nil {
+		return cfg, err
+	}
+	if err := b.client.validateRPCClient(); err != nil {
+		return cfg, err
+	}
+	if len(b.msgs) == 0 {
+		return cfg, ErrEmptyTx
+	}
+	for _, msg := range b.msgs {
+		if err := msg.ValidateBasic(); err != nil {
+			return cfg, err
+		}
+	}
+
+	// Fetch the account number and sequence once, for the simulation and
+	// the broadcast.
+	if b.client.NonceManager == nil && (cfg.AccountNumber == 0 || cfg.SequenceNumber == 0) {
+		caller, err := b.client.Signer.Info()
+		if err != nil {
+			return cfg, err
+		}
+		account, _, err := b.client.QueryAccount(caller.GetAddress())
+		if err != nil {
+			return cfg, errors.Wrap(err, "query account")
+		}
+		cfg.AccountNumber = account.AccountNumber
+		cfg.SequenceNumber = account.Sequence
+	}
+
+	if cfg.GasWanted <= 0 {
+		gasUsed, err := b.simulate(cfg)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.GasWanted = overflow.Addp(gasUsed, overflow.Mulp(gasUsed, gasWantedBuffer)/100)
+	}
+
+	if cfg.GasFee == "" {
+		fee, err := b.estimateFee(cfg.GasWanted)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.GasFee = fee.String()
+	}
+
+	return cfg, nil
+}
+
+// simulate returns the gas used by the transaction.
+func (b *TxBuilder) simulate(cfg BaseTxCfg) (int64, error) {
+	gasWanted := int64(defaultSimulateGasWanted)
+	params, err := b.client.RPCClient.ConsensusParams(context.Background(), nil)
+	if err == nil && params != nil && params.ConsensusParams.Block != nil && params.ConsensusParams.Block.MaxGas > 0 {
+		gasWanted = params.ConsensusParams.Block.MaxGas
+	}
+
+	// Signatures aren't verified when simulating, and neither is the gas
+	// price, so use a minimal fee to not require funds for the maximum gas.
+	// (A zero fee isn't valid once encoded.)
+	tx := std.Tx{
+		Msgs: b.msgs,
+		Fee:  std.NewFee(gasWanted, std.NewCoin(ugnot.Denom, 1)),
+		Memo: cfg.Memo,
+	}
+	// The account number and sequence don't need to be valid either.
+	signedTx, err := b.client.sign(tx, cfg.AccountNumber, cfg.SequenceNumber)
+	if err != nil {
+		return 0, err
+	}
+	return b.client.EstimateGas(signedTx)
+}
+
+// estimateFee returns the fee for gasWanted, at the current gas price.
+func (b *TxBuilder) estimateFee(gasWanted int64) (std.Coin, error) {
+	gp, _, err := b.client.QueryGasPrice()
+	if err != nil {
+		return std.Coin{}, err
+	}
+	denom := gp.Price.Denom
+	if denom == "" {
+		denom = ugnot.Denom
+	}
+	if gp.Gas == 0 {
+		// No gas price, but a zero fee isn't valid once encoded.
+		return std.NewCoin(denom, 1), nil
+	}
+
+	fee := overflow.Mulp(gasWanted/gp.Gas+1, gp.Price.Amount)
+	fee = overflow.Addp(fee, overflow.Mulp(fee, gasFeeBuffer)/100)
+	return std.NewCoin(denom, fee), nil
+}
+
+// Broadcast signs the transaction and broadcasts it, waiting for it to be
+// committed. The zero values of cfg are estimated first, see
+// [TxBuilder.Estimate].
+func (b *TxBuilder) Broadcast(cfg BaseTxCfg) (*ctypes.ResultBroadcastTxCommit, error) {
+	cfg, err := b.Estimate(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("estimating transaction: %w", err)
+	}
+	tx, err := b.Build(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return b.client.signAndBroadcastTxCommit(*tx, cfg.AccountNumber, cfg.SequenceNumber)
+}
@@ -51,34 +51,11 @@ func (c *Client) Call(cfg BaseTxCfg, msgs ...vm.MsgCall) (*ctypes.ResultBroadcas
 // NewCallTx makes an unsigned transaction from one or more MsgCall.
 // The Caller field must be set.
 func NewCallTx(cfg BaseTxCfg, msgs ...vm.MsgCall) (*std.Tx, error) {
-	// Validate base transaction config
-	if err := cfg.validateBaseTxConfig(); err != nil {
-		return nil, err
-	}
-
-	vmMsgs := make([]std.Msg, 0, len(msgs))
+	stdMsgs := make([]std.Msg, 0, len(msgs))
 	for _, msg := range msgs {
-		// Validate MsgCall fields
-		if err := msg.ValidateBasic(); err != nil {
-			return nil, err
-		}
-
-		vmMsgs = append(vmMsgs, std.Msg(msg))
+		stdMsgs = append(stdMsgs, msg)
 	}
-
-	// Parse gas fee
-	gasFeeCoins, err := std.ParseCoin(cfg.GasFee)
-	if err != nil {
-		return nil, err
-	}
-
-	// Pack transaction
-	return &std.Tx{
-		Msgs:       vmMsgs,
-		Fee:        std.NewFee(cfg.GasWanted, gasFeeCoins),
-		Signatures: nil,
-		Memo:       cfg.Memo,
-	}, nil
+	return NewTx(cfg, stdMsgs...)
 }
 
 // Run executes one or more MsgRun calls on the blockchain
@@ -101,34 +78,11 @@ func (c *Client) Run(cfg BaseTxCfg, msgs ...vm.MsgRun) (*ctypes.ResultBroadcastT
 // NewRunTx makes an unsigned transaction from one or more MsgRun.
 // The Caller field must be set.
 func NewRunTx(cfg BaseTxCfg, msgs ...vm.MsgRun) (*std.Tx, error) {
-	// Validate base transaction config
-	if err := cfg.validateBaseTxConfig(); err != nil {
-		return nil, err
-	}
-
-	vmMsgs := make([]std.Msg, 0, len(msgs))
+	stdMsgs := make([]std.Msg, 0, len(msgs))
 	for _, msg := range msgs {
-		// Validate MsgRun fields
-		if err := msg.ValidateBasic(); err != nil {
-			return nil, err
-		}
-
-		vmMsgs = append(vmMsgs, std.Msg(msg))
-	}
-
-	// Parse gas fee
-	gasFeeCoins, err := std.ParseCoin(cfg.GasFee)
-	if err != nil {
-		return nil, err
+		stdMsgs = append(stdMsgs, msg)
 	}
-
-	// Pack transaction
-	return &std.Tx{
-		Msgs:       vmMsgs,
-		Fee:        std.NewFee(cfg.GasWanted, gasFeeCoins),
-		Signatures: nil,
-		Memo:       cfg.Memo,
-	}, nil
+	return NewTx(cfg, stdMsgs...)
 }
 
 // Send executes one or more MsgSend calls on the blockchain
@@ -151,34 +105,11 @@ func (c *Client) Send(cfg BaseTxCfg, msgs ...bank.MsgSend) (*ctypes.ResultBroadc
 // NewSendTx makes an unsigned transaction from one or more MsgSend.
 // The FromAddress field must be set.
 func NewSendTx(cfg BaseTxCfg, msgs ...bank.MsgSend) (*std.Tx, error) {
-	// Validate base transaction config
-	if err := cfg.validateBaseTxConfig(); err != nil {
-		return nil, err
-	}
-
-	vmMsgs := make([]std.Msg, 0, len(msgs))
+	stdMsgs := make([]std.Msg, 0, len(msgs))
 	for _, msg := range msgs {
-		// Validate MsgSend fields
-		if err := msg.ValidateBasic(); err != nil {
-			return nil, err
-		}
-
-		vmMsgs = append(vmMsgs, std.Msg(msg))
+		stdMsgs = append(stdMsgs, msg)
 	}
-
-	// Parse gas fee
-	gasFeeCoins, err := std.ParseCoin(cfg.GasFee)
-	if err != nil {
-		return nil, err
-	}
-
-	// Pack transaction
-	return &std.Tx{
-		Msgs:       vmMsgs,
-		Fee:        std.NewFee(cfg.GasWanted, gasFeeCoins),
-		Signatures: nil,
-		Memo:       cfg.Memo,
-	}, nil
+	return NewTx(cfg, stdMsgs...)
 }
 
 // AddPackage executes one or more AddPackage calls on the blockchain
@@ -201,19 +132,26 @@ func (c *Client) AddPackage(cfg BaseTxCfg, msgs ...vm.MsgAddPackage) (*ctypes.Re
 // NewAddPackageTx makes an unsigned transaction from one or more MsgAddPackage.
 // The Creator field must be set.
 func NewAddPackageTx(cfg BaseTxCfg, msgs ...vm.MsgAddPackage) (*std.Tx, error) {
+	stdMsgs := make([]std.Msg, 0, len(msgs))
+	for _, msg := range msgs {
+		stdMsgs = append(stdMsgs, msg)
+	}
+	return NewTx(cfg, stdMsgs...)
+}
+
+// NewTx makes an unsigned transaction from one or more messages, possibly of
+// different kinds. The messages are executed atomically, in order.
+func NewTx(cfg BaseTxCfg, msgs ...std.Msg) (*std.Tx, error) {
 	// Validate base transaction config
 	if err := cfg.validateBaseTxConfig(); err != nil {
 		return nil, err
 	}
 
-	vmMsgs := make([]std.Msg, 0, len(msgs))
+	// Validate messages fields
 	for _, msg := range msgs {
-		// Validate MsgAddPackage fields
 		if err := msg.ValidateBasic(); err != nil {
 			return nil, err
 		}
-
-		vmMsgs = append(vmMsgs, std.Msg(msg))
 	}
 
 	// Parse gas fee
@@ -224,7 +162,7 @@ func NewAddPackageTx(cfg BaseTxCfg, msgs ...vm.MsgAddPackage) (*std.Tx, error) {
 
 	// Pack transaction
 	return &std.Tx{
-		Msgs:       vmMsgs,
+		Msgs:       msgs,
 		Fee:        std.NewFee(cfg.GasWanted, gasFeeCoins),
 		Signatures: nil,
 		Memo:       cfg.Memo,
@@ -233,6 +171,20 @@ func NewAddPackageTx(cfg BaseTxCfg, msgs ...vm.MsgAddPackage) (*std.Tx, error) {
 
 // signAndBroadcastTxCommit signs a transaction and broadcasts it, returning the result
 func (c *Client) signAndBroadcastTxCommit(tx std.Tx, accountNumber, sequenceNumber uint64) (*ctypes.ResultBroadcastTxCommit, error) {
+	if c.useNonceManager(accountNumber, sequenceNumber) {
+		bres, err := withNonce(c, tx, false, func(signedTx *std.Tx) (*ctypes.ResultBroadcastTxCommit, abci.Error, error) {
+			bres, err := c.broadcastTxCommit(signedTx)
+			if err != nil {
+				return nil, nil, err
+			}
+			return bres, bres.CheckTx.Error, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return bres, commitError(bres)
+	}
+
 	signedTx, err := c.SignTx(tx, accountNumber, sequenceNumber)
 	if err != nil {
 		return nil, err
@@ -240,6 +192,58 @@ func (c *Client) signAndBroadcastTxCommit(tx std.Tx, accountNumber, sequenceNumb
 	return c.BroadcastTxCommit(signedTx)
 }
 
+// SignAndBroadcastTxSync signs a transaction and broadcasts it, returning
+// once it has been checked and added to the mempool, without waiting for it
+// to be committed.
+// If accountNumber or sequenceNumber is 0, then use the NonceManager of c if
+// set, or query the blockchain for the value.
+func (c *Client) SignAndBroadcastTxSync(tx std.Tx, accountNumber, sequenceNumber uint64) (*ctypes.ResultBroadcastTx, error) {
+	if c.useNonceManager(accountNumber, sequenceNumber) {
+		bres, err := withNonce(c, tx, true, func(signedTx *std.Tx) (*ctypes.ResultBroadcastTx, abci.Error, error) {
+			bres, err := c.broadcastTxSync(signedTx)
+			if err != nil {
+				return nil, nil, err
+			}
+			return bres, bres.Error, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return bres, checkError(bres)
+	}
+
+	signedTx, err := c.SignTx(tx, accountNumber, sequenceNumber)
+	if err != nil {
+		return nil, err
+	}
+	return c.BroadcastTxSync(signedTx)
+}
+
+// SignAndBroadcastTxAsync signs a transaction and broadcasts it, returning
+// immediately, without waiting for it to be checked.
+// If accountNumber or sequenceNumber is 0, then use the NonceManager of c if
+// set, or query the blockchain for the value.
+func (c *Client) SignAndBroadcastTxAsync(tx std.Tx, accountNumber, sequenceNumber uint64) (*ctypes.ResultBroadcastTx, error) {
+	if c.useNonceManager(accountNumber, sequenceNumber) {
+		return withNonce(c, tx, true, func(signedTx *std.Tx) (*ctypes.ResultBroadcastTx, abci.Error, error) {
+			bres, err := c.BroadcastTxAsync(signedTx)
+			return bres, nil, err
+		})
+	}
+
+	signedTx, err := c.SignTx(tx, accountNumber, sequenceNumber)
+	if err != nil {
+		return nil, err
+	}
+	return c.BroadcastTxAsync(signedTx)
+}
+
+// useNonceManager returns true if the nonce manager should provide the
+// account number and sequence of a transaction.
+func (c *Client) useNonceManager(accountNumber, sequenceNumber uint64) bool {
+	return c.NonceManager != nil && (accountNumber == 0 || sequenceNumber == 0)
+}
+
 // SignTx signs a transaction and returns a signed tx ready for broadcasting.
 // If accountNumber or sequenceNumber is 0 then query the blockchain for the value.
 func (c *Client) SignTx(tx std.Tx, accountNumber, sequenceNumber uint64) (*std.Tx, error) {
@@ -260,6 +264,11 @@ func (c *Client) SignTx(tx std.Tx, accountNumber, sequenceNumber uint64) (*std.T
 		sequenceNumber = account.Sequence
 	}
 
+	return c.sign(tx, accountNumber, sequenceNumber)
+}
+
+// sign signs a transaction with the given account number and sequence.
+func (c *Client) sign(tx std.Tx, accountNumber, sequenceNumber uint64) (*std.Tx, error) {
 	signCfg := SignCfg{
 		UnsignedTX:     tx,
 		SequenceNumber: sequenceNumber,
@@ -275,6 +284,14 @@ func (c *Client) SignTx(tx std.Tx, accountNumber, sequenceNumber uint64) (*std.T
 // BroadcastTxCommit marshals and broadcasts the signed transaction, returning the result.
 // If the result has a delivery error, then return a wrapped error.
 func (c *Client) BroadcastTxCommit(signedTx *std.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
+	bres, err := c.broadcastTxCommit(signedTx)
+	if err != nil {
+		return nil, err
+	}
+	return bres, commitError(bres)
+}
+
+func (c *Client) broadcastTxCommit(signedTx *std.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
 	if err := c.validateRPCClient(); err != nil {
 		return nil, err
 	}
@@ -287,14 +304,68 @@ func (c *Client) BroadcastTxCommit(signedTx *std.Tx) (*ctypes.ResultBroadcastTxC
 	if err != nil {
 		return nil, errors.Wrap(err, "broadcasting bytes")
 	}
+	return bres, nil
+}
 
+func commitError(bres *ctypes.ResultBroadcastTxCommit) error {
 	if bres.CheckTx.IsErr() {
-		return bres, errors.Wrapf(bres.CheckTx.Error, "check transaction failed: log:%s", bres.CheckTx.Log)
+		return errors.Wrapf(bres.CheckTx.Error, "check transaction failed: log:%s", bres.CheckTx.Log)
 	}
 	if bres.DeliverTx.IsErr() {
-		return bres, errors.Wrapf(bres.DeliverTx.Error, "deliver transaction failed: log:%s", bres.DeliverTx.Log)
+		return errors.Wrapf(bres.DeliverTx.Error, "deliver transaction failed: log:%s", bres.DeliverTx.Log)
+	}
+	return nil
+}
+
+// BroadcastTxSync marshals and broadcasts the signed transaction, returning
+// once it has been checked and added to the mempool.
+// If the transaction was rejected, then return a wrapped error.
+func (c *Client) BroadcastTxSync(signedTx *std.Tx) (*ctypes.ResultBroadcastTx, error) {
+	bres, err := c.broadcastTxSync(signedTx)
+	if err != nil {
+		return nil, err
+	}
+	return bres, checkError(bres)
+}
+
+func (c *Client) broadcastTxSync(signedTx *std.Tx) (*ctypes.ResultBroadcastTx, error) {
+	if err := c.validateRPCClient(); err != nil {
+		return nil, err
+	}
+	bz, err := amino.Marshal(signedTx)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling tx binary bytes")
 	}
 
+	bres, err := c.RPCClient.BroadcastTxSync(context.Background(), bz)
+	if err != nil {
+		return nil, errors.Wrap(err, "broadcasting bytes")
+	}
+	return bres, nil
+}
+
+func checkError(bres *ctypes.ResultBroadcastTx) error {
+	if bres.Error != nil {
+		return errors.Wrapf(bres.Error, "check transaction failed: log:%s", bres.Log)
+	}
+	return nil
+}
+
+// BroadcastTxAsync marshals and broadcasts the signed transaction, returning
+// immediately. The result only contains the hash of the transaction.
+func (c *Client) BroadcastTxAsync(signedTx *std.Tx) (*ctypes.ResultBroadcastTx, error) {
+	if err := c.validateRPCClient(); err != nil {
+		return nil, err
+	}
+	bz, err := amino.Marshal(signedTx)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling tx binary bytes")
+	}
+
+	bres, err := c.RPCClient.BroadcastTxAsync(context.Background(), bz)
+	if err != nil {
+		return nil, errors.Wrap(err, "broadcasting bytes")
+	}
 	return bres, nil
 }
 
@@ -707,6 +707,115 @@ func Hello(str string) string {
 	assert.Contains(t, string(query.Response.Data), "gnomod.toml")
 }
 
+func TestTxBuilder_Integration(t *testing.T) {
+	// Set up in-memory node
+	config := integration.TestingMinimalNodeConfig(gnoenv.RootDir())
+	node, remoteAddr := integration.TestingInMemoryNode(t, log.NewNoopLogger(), config)
+	defer node.Stop()
+
+	// Init Signer & RPCClient
+	signer := newInMemorySigner(t, "tendermint_test")
+	rpcClient, err := rpcclient.NewHTTPClient(remoteAddr)
+	require.NoError(t, err)
+
+	// Setup Client
+	client := Client{
+		Signer:    signer,
+		RPCClient: rpcClient,
+	}
+
+	caller, err := client.Signer.Info()
+	require.NoError(t, err)
+
+	body := `package counter
+
+var count int
+
+func Init(cur realm, n int) {
+	count = n
+}
+
+func Render(_ string) string {
+	return "count: " + itoa(count)
+}
+
+func itoa(n int) string {
+	if n < 10 {
+		return string(rune('0' + n))
+	}
+	return itoa(n/10) + itoa(n%10)
+}`
+	deploymentPath := "gno.land/r/demo/integration/test/counter"
+	fund := std.Coins{{Denom: ugnot.Denom, Amount: 1000}}
+
+	// Add the package, initialize it and fund it, in a single transaction
+	res, err := client.NewTxBuilder().
+		AddPackage(vm.MsgAddPackage{
+			Creator: caller.GetAddress(),
+			Package: &std.MemPackage{
+				Name: "counter",
+				Path: deploymentPath,
+				Files: []*std.MemFile{
+					{Name: "counter.gno", Body: body},
+					{Name: "gnomod.toml", Body: gnolang.GenGnoModLatest(deploymentPath)},
+				},
+			},
+			MaxDeposit: std.Coins{{Denom: ugnot.Denom, Amount: 10000000}},
+		}).
+		AddCall(vm.MsgCall{
+			Caller:  caller.GetAddress(),
+			PkgPath: deploymentPath,
+			Func:    "Init",
+			Args:    []string{"42"},
+		}).
+		AddSend(bank.MsgSend{
+			FromAddress: caller.GetAddress(),
+			ToAddress:   gnolang.DerivePkgCryptoAddr(deploymentPath),
+			Amount:      fund,
+		}).
+		Broadcast(BaseTxCfg{}) // gas, fee, account number and sequence are estimated
+	require.NoError(t, err)
+	assert.Greater(t, res.DeliverTx.GasUsed, int64(0))
+	assert.LessOrEqual(t, res.DeliverTx.GasUsed, res.DeliverTx.GasWanted)
+
+	render, _, err := client.Render(deploymentPath, "")
+	require.NoError(t, err)
+	assert.Equal(t, "count: 42", render)
+
+	realmAcc, _, err := client.QueryAccount(gnolang.DerivePkgCryptoAddr(deploymentPath))
+	require.NoError(t, err)
+	assert.Equal(t, fund, realmAcc.GetCoins())
+
+	// A failing message reverts the whole transaction
+	failingPath := "gno.land/r/demo/integration/test/counter2"
+	_, err = client.NewTxBuilder().
+		AddPackage(vm.MsgAddPackage{
+			Creator: caller.GetAddress(),
+			Package: &std.MemPackage{
+				Name: "counter",
+				Path: failingPath,
+				Files: []*std.MemFile{
+					{Name: "counter.gno", Body: body},
+					{Name: "gnomod.toml", Body: gnolang.GenGnoModLatest(failingPath)},
+				},
+			},
+			MaxDeposit: std.Coins{{Denom: ugnot.Denom, Amount: 10000000}},
+		}).
+		AddCall(vm.MsgCall{
+			Caller:  caller.GetAddress(),
+			PkgPath: failingPath,
+			Func:    "Missing",
+		}).
+		Broadcast(BaseTxCfg{})
+	require.Error(t, err)
+
+	_, err = client.Query(QueryCfg{
+		Path: "vm/qfile",
+		Data: []byte(failingPath),
+	})
+	assert.Error(t, err)
+}
+
 // todo add more integration tests:
 // MsgCall with Send field populated (single/multiple)
 // MsgRun with Send field populated (single/multiple)
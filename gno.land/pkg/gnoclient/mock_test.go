@@ -121,6 +121,7 @@ type (
 	mockUnconfirmedTxs       func(ctx context.Context, limit int) (*ctypes.ResultUnconfirmedTxs, error)
 	mockNumUnconfirmedTxs    func(ctx context.Context) (*ctypes.ResultUnconfirmedTxs, error)
 	mockTx                   func(ctx context.Context, hash []byte) (*ctypes.ResultTx, error)
+	mockTxSearch             func(ctx context.Context, query string, page, perPage int, orderBy string) (*ctypes.ResultTxSearch, error)
 )
 
 type mockRPCClient struct {
@@ -145,6 +146,7 @@ type mockRPCClient struct {
 	unconfirmedTxs       mockUnconfirmedTxs
 	numUnconfirmedTxs    mockNumUnconfirmedTxs
 	tx                   mockTx
+	txSearch             mockTxSearch
 }
 
 func (m *mockRPCClient) BroadcastTxCommit(ctx context.Context, tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
@@ -294,3 +296,11 @@ func (m *mockRPCClient) Tx(ctx context.Context, hash []byte) (*ctypes.ResultTx,
 
 	return nil, nil
 }
+
+func (m *mockRPCClient) TxSearch(ctx context.Context, query string, page, perPage int, orderBy string) (*ctypes.ResultTxSearch, error) {
+	if m.txSearch != nil {
+		return m.txSearch(ctx, query, page, perPage, orderBy)
+	}
+
+	return nil, nil
+}
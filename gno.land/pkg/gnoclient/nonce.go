@@ -0,0 +1,182 @@
+package gnoclient
+
+import (
+	"sync"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// DefaultMaxRetries is the default number of times a transaction is signed
+// again and rebroadcast, after failing because of a wrong sequence.
+const DefaultMaxRetries = 3
+
+// NonceManager tracks the account number and the sequence of the signer of a
+// [Client] locally, so that several transactions can be submitted without
+// waiting for the previous ones to be committed, possibly concurrently.
+//
+// The values are fetched from the chain when first needed, and then the
+// sequence is incremented locally for each signed transaction. When the
+// chain rejects a transaction because of a wrong sequence (e.g. the key was
+// also used elsewhere), the values are fetched again, and the transaction is
+// signed again and rebroadcast, up to MaxRetries times. Note that the fetched
+// sequence doesn't account for the transactions still in the mempool.
+//
+// Sync and async broadcasts are ordered, so that concurrent transactions reach
+// the node in the order of their sequences; they only wait for the previous
+// ones to be checked, not committed.
+type NonceManager struct {
+	client *Client
+
+	// MaxRetries is the maximum number of rebroadcasts of a transaction
+	// rejected because of a wrong sequence.
+	MaxRetries int
+
+	broadcastMu   sync.Mutex // orders sync and async broadcasts
+	mu            sync.Mutex // protects the fields below
+	synced        bool
+	accountNumber uint64
+	sequence      uint64 // next sequence to use
+}
+
+// NewNonceManager returns a nonce manager for the signer of c. It must be set
+// as c.NonceManager to be used by the transaction methods of c.
+func NewNonceManager(c *Client) *NonceManager {
+	return &NonceManager{
+		client:     c,
+		MaxRetries: DefaultMaxRetries,
+	}
+}
+
+// Next reserves a sequence for a new transaction, and returns it with the
+// account number.
+func (m *NonceManager) Next() (accountNumber, sequence uint64, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.synced {
+		if err := m.sync(); err != nil {
+			return 0, 0, err
+		}
+	}
+	sequence = m.sequence
+	m.sequence++
+	return m.accountNumber, sequence, nil
+}
+
+// Peek returns the account number and the sequence that the next transaction
+// would use, without reserving it.
+func (m *NonceManager) Peek() (accountNumber, sequence uint64, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.synced {
+		if err := m.sync(); err != nil {
+			return 0, 0, err
+		}
+	}
+	return m.accountNumber, m.sequence, nil
+}
+
+// Sync fetches the account number and the sequence of the signer from the
+// chain, discarding the local values.
+func (m *NonceManager) Sync() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.sync()
+}
+
+func (m *NonceManager) sync() error {
+	if err := m.client.validateSigner(); err != nil {
+		return err
+	}
+	caller, err := m.client.Signer.Info()
+	if err != nil {
+		return err
+	}
+	account, _, err := m.client.QueryAccount(caller.GetAddress())
+	if err != nil {
+		return errors.Wrap(err, "query account")
+	}
+
+	m.accountNumber = account.AccountNumber
+	m.sequence = account.Sequence
+	m.synced = true
+	return nil
+}
+
+// release gives back sequence, reserved for a transaction that was rejected
+// before being added to the mempool. If other sequences were reserved since,
+// they can't be used anymore, so the values are fetched again on next use.
+func (m *NonceManager) release(sequence uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.synced && m.sequence == sequence+1 {
+		m.sequence = sequence
+		return
+	}
+	m.synced = false
+}
+
+// invalidate discards the local values, so that they are fetched again on
+// next use.
+func (m *NonceManager) invalidate() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.synced = false
+}
+
+// isSequenceMismatch returns true if the transaction was rejected because it
+// was signed with a wrong account number or sequence.
+func isSequenceMismatch(err abci.Error) bool {
+	_, ok := err.(std.UnauthorizedError)
+	return ok
+}
+
+// withNonce signs tx using the nonce manager of c, and broadcasts it using
+// broadcast, which returns the CheckTx error of the transaction, if any.
+// The transaction is signed again and rebroadcast if it was rejected
+// because of a wrong sequence. If ordered is true, the broadcast is done
+// in the order of the sequences.
+func withNonce[R any](c *Client, tx std.Tx, ordered bool, broadcast func(*std.Tx) (R, abci.Error, error)) (R, error) {
+	var zero R
+	m := c.NonceManager
+	if ordered {
+		m.broadcastMu.Lock()
+		defer m.broadcastMu.Unlock()
+	}
+
+	for attempt := 0; ; attempt++ {
+		accountNumber, sequence, err := m.Next()
+		if err != nil {
+			return zero, err
+		}
+		signedTx, err := c.sign(tx, accountNumber, sequence)
+		if err != nil {
+			m.release(sequence)
+			return zero, err
+		}
+
+		res, checkErr, err := broadcast(signedTx)
+		switch {
+		case err != nil && checkErr == nil:
+			// Unknown state: the transaction may have been added to
+			// the mempool or not.
+			m.invalidate()
+			return res, err
+		case checkErr == nil:
+			return res, nil
+		case isSequenceMismatch(checkErr) && attempt < m.MaxRetries:
+			if err := m.Sync(); err != nil {
+				return zero, err
+			}
+		default:
+			m.release(sequence)
+			return res, err
+		}
+	}
+}
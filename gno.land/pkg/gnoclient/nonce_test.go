@@ -0,0 +1,225 @@
+package gnoclient
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// mockNode accepts transactions with the expected sequence, which is stored in
+// the memo of the transactions by the mock signer.
+type mockNode struct {
+	mu        sync.Mutex
+	committed uint64   // sequence returned by account queries
+	expected  uint64   // sequence expected by CheckTx
+	reject    error    // error returned by CheckTx for valid sequences
+	accepted  []uint64 // sequences of the accepted transactions
+	queries   int
+}
+
+func (n *mockNode) checkTx(bz []byte) abci.Error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var tx std.Tx
+	if err := amino.Unmarshal(bz, &tx); err != nil {
+		panic(err)
+	}
+	seq, _ := strconv.ParseUint(tx.Memo, 10, 64)
+	switch {
+	case seq != n.expected:
+		return std.UnauthorizedError{}
+	case n.reject != nil:
+		return abci.StringError(n.reject.Error())
+	}
+	n.expected++
+	n.accepted = append(n.accepted, seq)
+	return nil
+}
+
+func newNonceTestClient(t *testing.T, node *mockNode) *Client {
+	t.Helper()
+
+	caller, _ := crypto.AddressFromBech32("g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5")
+	c := &Client{
+		Signer: &mockSigner{
+			sign: func(cfg SignCfg) (*std.Tx, error) {
+				assert.Equal(t, uint64(3), cfg.AccountNumber)
+				tx := cfg.UnsignedTX
+				tx.Memo = strconv.FormatUint(cfg.SequenceNumber, 10)
+				return &tx, nil
+			},
+			info: func() (keys.Info, error) {
+				return &mockKeysInfo{
+					getAddress: func() crypto.Address { return caller },
+				}, nil
+			},
+		},
+		RPCClient: &mockRPCClient{
+			abciQuery: func(ctx context.Context, path string, data []byte) (*ctypes.ResultABCIQuery, error) {
+				require.Equal(t, "auth/accounts/"+caller.String(), path)
+				node.mu.Lock()
+				defer node.mu.Unlock()
+				node.queries++
+				res := &ctypes.ResultABCIQuery{}
+				res.Response.Data = []byte(`{"BaseAccount":{"account_number":"3","sequence":"` + strconv.FormatUint(node.committed, 10) + `"}}`)
+				return res, nil
+			},
+			broadcastTxSync: func(ctx context.Context, tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
+				return &ctypes.ResultBroadcastTx{Error: node.checkTx(tx)}, nil
+			},
+			broadcastTxAsync: func(ctx context.Context, tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
+				node.checkTx(tx)
+				return &ctypes.ResultBroadcastTx{}, nil
+			},
+			broadcastTxCommit: func(ctx context.Context, tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
+				res := &ctypes.ResultBroadcastTxCommit{}
+				res.CheckTx.Error = node.checkTx(tx)
+				return res, nil
+			},
+		},
+	}
+	c.NonceManager = NewNonceManager(c)
+	return c
+}
+
+func TestNonceManager(t *testing.T) {
+	t.Parallel()
+
+	node := &mockNode{committed: 5}
+	c := newNonceTestClient(t, node)
+
+	acc, seq, err := c.NonceManager.Peek()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), acc)
+	assert.Equal(t, uint64(5), seq)
+
+	for i := range uint64(3) {
+		acc, seq, err := c.NonceManager.Next()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), acc)
+		assert.Equal(t, 5+i, seq)
+	}
+	assert.Equal(t, 1, node.queries)
+
+	node.committed = 42
+	require.NoError(t, c.NonceManager.Sync())
+	_, seq, err = c.NonceManager.Next()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), seq)
+}
+
+func TestSignAndBroadcastTx_NonceManager(t *testing.T) {
+	t.Parallel()
+
+	tx := std.Tx{
+		Msgs: []std.Msg{vm.MsgCall{PkgPath: "gno.land/r/demo/echo", Func: "Echo"}},
+		Fee:  std.NewFee(1000, std.NewCoin("ugnot", 1)),
+	}
+
+	t.Run("concurrent", func(t *testing.T) {
+		t.Parallel()
+
+		node := &mockNode{committed: 7, expected: 7}
+		c := newNonceTestClient(t, node)
+
+		var wg sync.WaitGroup
+		for i := range 30 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var err error
+				if i%2 == 0 {
+					_, err = c.SignAndBroadcastTxSync(tx, 0, 0)
+				} else {
+					_, err = c.SignAndBroadcastTxAsync(tx, 0, 0)
+				}
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		require.Len(t, node.accepted, 30)
+		for i, seq := range node.accepted {
+			assert.Equal(t, uint64(7+i), seq)
+		}
+		assert.Equal(t, 1, node.queries)
+	})
+
+	t.Run("sequence mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		// The key was used elsewhere: the local sequence is outdated.
+		node := &mockNode{committed: 7, expected: 7}
+		c := newNonceTestClient(t, node)
+		_, err := c.SignAndBroadcastTxSync(tx, 0, 0)
+		require.NoError(t, err)
+		node.committed, node.expected = 10, 10
+
+		_, err = c.SignAndBroadcastTxSync(tx, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{7, 10}, node.accepted)
+		assert.Equal(t, 2, node.queries)
+
+		_, err = c.signAndBroadcastTxCommit(tx, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{7, 10, 11}, node.accepted)
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		node := &mockNode{committed: 7, expected: 100}
+		c := newNonceTestClient(t, node)
+		c.NonceManager.MaxRetries = 2
+
+		_, err := c.SignAndBroadcastTxSync(tx, 0, 0)
+		require.ErrorIs(t, err, std.UnauthorizedError{})
+		assert.Equal(t, 3, node.queries)
+		assert.Empty(t, node.accepted)
+	})
+
+	t.Run("rejected transaction", func(t *testing.T) {
+		t.Parallel()
+
+		node := &mockNode{committed: 7, expected: 7, reject: assert.AnError}
+		c := newNonceTestClient(t, node)
+
+		_, err := c.SignAndBroadcastTxSync(tx, 0, 0)
+		require.ErrorContains(t, err, assert.AnError.Error())
+		_, err = c.signAndBroadcastTxCommit(tx, 0, 0)
+		require.ErrorContains(t, err, assert.AnError.Error())
+
+		// The sequences of the rejected transactions are reused.
+		node.reject = nil
+		_, err = c.SignAndBroadcastTxSync(tx, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{7}, node.accepted)
+		assert.Equal(t, 1, node.queries)
+	})
+
+	t.Run("explicit sequence", func(t *testing.T) {
+		t.Parallel()
+
+		node := &mockNode{expected: 4}
+		c := newNonceTestClient(t, node)
+
+		_, err := c.SignAndBroadcastTxSync(tx, 3, 4)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{4}, node.accepted)
+		assert.Equal(t, 0, node.queries)
+	})
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/store"
 	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
 	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/snapshots"
 	"github.com/gnolang/gno/tm2/pkg/store/types"
 )
 
@@ -43,7 +44,11 @@ type AppOptions struct {
 	SkipGenesisSigVerification bool               // default to verify genesis transactions
 	InitChainerConfig                             // options related to InitChainer
 	MinGasPrices               string             // optional
-	PruneStrategy              types.PruneStrategy
+	PruningOptions             types.PruningOptions
+	SnapshotDir                string            // optional, directory of the state sync snapshots
+	SnapshotOptions            snapshots.Options // optional, when to take state sync snapshots
+	KeepHistory                bool              // optional, keep the history of the gno store for queries at past heights
+	HistoryKeepRecent          int64             // optional, number of recent heights of the history to keep (0 keeps all)
 }
 
 // TestAppOptions provides a "ready" default [AppOptions] for use with
@@ -59,7 +64,7 @@ func TestAppOptions(db dbm.DB) *AppOptions {
 			CacheStdlibLoad:        true,
 		},
 		SkipGenesisSigVerification: true,
-		PruneStrategy:              types.PruneNothingStrategy,
+		PruningOptions:             types.PruneNothing,
 	}
 }
 
@@ -92,7 +97,11 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 		appOpts = append(appOpts, sdk.SetMinGasPrices(cfg.MinGasPrices))
 	}
 
-	appOpts = append(appOpts, sdk.SetPruningOptions(cfg.PruneStrategy.Options()))
+	appOpts = append(appOpts, sdk.SetPruningOptions(cfg.PruningOptions))
+
+	if cfg.SnapshotDir != "" {
+		appOpts = append(appOpts, sdk.SetSnapshotOptions(cfg.SnapshotDir, cfg.SnapshotOptions))
+	}
 
 	// Create BaseApp.
 	baseApp := sdk.NewBaseApp("gnoland", cfg.Logger, cfg.DB, baseKey, mainKey, appOpts...)
@@ -100,7 +109,12 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 
 	// Set mounts for BaseApp's MultiStore.
 	baseApp.MountStoreWithDB(mainKey, iavl.StoreConstructor, cfg.DB)
-	baseApp.MountStoreWithDB(baseKey, dbadapter.StoreConstructor, cfg.DB)
+	if cfg.KeepHistory {
+		historyDB := dbm.NewPrefixDB(cfg.DB, []byte("h/k:"+baseKey.Name()+"/"))
+		baseApp.MountStoreWithDB(baseKey, dbadapter.HistoryStoreConstructor(historyDB, cfg.HistoryKeepRecent, cfg.Logger), cfg.DB)
+	} else {
+		baseApp.MountStoreWithDB(baseKey, dbadapter.StoreConstructor, cfg.DB)
+	}
 
 	// Construct keepers.
 
@@ -108,8 +122,10 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 	acck := auth.NewAccountKeeper(mainKey, prmk.ForModule(auth.ModuleName), ProtoGnoAccount)
 	bankk := bank.NewBankKeeper(acck, prmk.ForModule(bank.ModuleName))
 	gpk := auth.NewGasPriceKeeper(mainKey)
+	fgk := auth.NewFeeGrantKeeper(mainKey)
 	vmk := vm.NewVMKeeper(baseKey, mainKey, acck, bankk, prmk)
 	vmk.Output = cfg.VMOutput
+	vmk.KeepHistory = cfg.KeepHistory
 
 	prmk.Register(auth.ModuleName, acck)
 	prmk.Register(bank.ModuleName, bankk)
@@ -124,6 +140,7 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 	// Set AnteHandler
 	authOptions := auth.AnteOptions{
 		VerifyGenesisSignatures: !cfg.SkipGenesisSigVerification,
+		FeeGrantKeeper:          fgk,
 	}
 	authAnteHandler := auth.NewAnteHandler(
 		acck, bankk, auth.DefaultSigVerificationGasConsumer, authOptions)
@@ -193,11 +210,19 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 	)
 
 	// Set a handler Route.
-	baseApp.Router().AddRoute("auth", auth.NewHandler(acck, gpk))
+	baseApp.Router().AddRoute("auth", auth.NewHandler(acck, gpk, fgk))
 	baseApp.Router().AddRoute("bank", bank.NewHandler(bankk))
 	baseApp.Router().AddRoute("params", params.NewHandler(prmk))
 	baseApp.Router().AddRoute("vm", vm.NewHandler(vmk))
 
+	// Profile the gas of the simulations requested with the profile flag.
+	baseApp.SetSimulateProfiler(vm.ProfileSimulation)
+
+	// Reinitialize the VMKeeper after restoring a state sync snapshot.
+	baseApp.SetRestoreHook(func(ms store.MultiStore) {
+		vmk.Reinitialize(cfg.Logger, ms)
+	})
+
 	// Load latest version.
 	if err := baseApp.LoadLatestVersion(); err != nil {
 		return nil, err
@@ -226,6 +251,13 @@ func NewTestGenesisAppConfig() GenesisAppConfig {
 	}
 }
 
+// The database of the app, in the DB directory of the node data directory.
+// Unlike the node databases, it doesn't follow the configured db_backend.
+const (
+	AppDBName    = "gnolang"
+	AppDBBackend = dbm.PebbleDBBackend
+)
+
 // NewApp creates the gno.land application.
 func NewApp(
 	dataRootDir string,
@@ -245,16 +277,24 @@ func NewApp(
 		},
 		MinGasPrices:               appCfg.MinGasPrices,
 		SkipGenesisSigVerification: genesisCfg.SkipSigVerification,
-		PruneStrategy:              appCfg.PruneStrategy,
+		PruningOptions:             appCfg.PruningOptions(),
+		SnapshotDir:                filepath.Join(dataRootDir, config.DefaultSnapshotsDir),
+		SnapshotOptions: snapshots.Options{
+			Interval:              appCfg.SnapshotInterval,
+			KeepRecent:            appCfg.SnapshotKeepRecent,
+			TrustUnverifiedStores: appCfg.SnapshotTrustUnverified,
+		},
+		KeepHistory:       appCfg.KeepHistory,
+		HistoryKeepRecent: appCfg.HistoryKeepRecent,
 	}
 	if genesisCfg.SkipFailingTxs {
 		cfg.GenesisTxResultHandler = NoopGenesisTxResultHandler
 	}
 
 	// Get main DB.
-	cfg.DB, err = dbm.NewDB("gnolang", dbm.PebbleDBBackend, filepath.Join(dataRootDir, config.DefaultDBDir))
+	cfg.DB, err = dbm.NewDB(AppDBName, AppDBBackend, filepath.Join(dataRootDir, config.DefaultDBDir))
 	if err != nil {
-		return nil, fmt.Errorf("error initializing database %q using path %q: %w", dbm.PebbleDBBackend, dataRootDir, err)
+		return nil, fmt.Errorf("error initializing database %q using path %q: %w", AppDBBackend, dataRootDir, err)
 	}
 
 	return NewAppWithOptions(cfg)
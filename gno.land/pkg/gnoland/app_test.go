@@ -1,10 +1,12 @@
 package gnoland
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -115,6 +117,27 @@ func TestNewAppWithOptions(t *testing.T) {
 	cres := bapp.Commit()
 	require.NotNil(t, cres)
 
+	// Simulate the transaction again, recording its gas profile.
+	var stx std.Tx
+	amino.MustUnmarshal(tx, &stx)
+	prof := gnolang.NewGasProfile()
+	sres := bapp.Simulate(tx, stx, func(ctx sdk.Context) sdk.Context {
+		return vm.WithGasProfile(ctx, prof)
+	})
+	require.True(t, sres.IsOK(), "Simulate response: %v", sres)
+	assert.Positive(t, prof.Total())
+	assert.LessOrEqual(t, prof.Total(), sres.GasUsed)
+
+	// The profile is returned by the simulate query with the profile flag.
+	qres := bapp.Query(abci.RequestQuery{
+		Path: ".app/simulate?profile=1",
+		Data: tx,
+	})
+	require.True(t, qres.IsOK(), "Query response: %v", qres)
+	require.NoError(t, amino.Unmarshal(qres.Value, &sres))
+	require.True(t, sres.IsOK(), "Simulate response: %v", sres)
+	assert.True(t, bytes.HasPrefix(qres.Data, []byte{0x1f, 0x8b}), "the profile must be gzipped")
+
 	tcs := []struct {
 		path        string
 		expectedVal string
@@ -136,6 +159,145 @@ func TestNewAppWithOptions(t *testing.T) {
 	}
 }
 
+func TestNewAppWithOptions_KeepHistory(t *testing.T) {
+	t.Parallel()
+
+	const chainID = "dev"
+	opts := TestAppOptions(memdb.NewMemDB())
+	opts.KeepHistory = true
+	app, err := NewAppWithOptions(opts)
+	require.NoError(t, err)
+	bapp := app.(*sdk.BaseApp)
+
+	addr := crypto.AddressFromPreimage([]byte("test1"))
+	appState := DefaultGenState()
+	appState.Balances = []Balance{
+		{
+			Address: addr,
+			Amount:  []std.Coin{{Amount: 1e15, Denom: "ugnot"}},
+		},
+	}
+	appState.Txs = []TxWithMetadata{
+		{
+			Tx: std.Tx{
+				Msgs: []std.Msg{vm.NewMsgAddPackage(addr, "gno.land/r/demo", []*std.MemFile{
+					{
+						Name: "demo.gno",
+						Body: "package demo; var Version = `v1`",
+					},
+					{
+						Name: "gnomod.toml",
+						Body: gnolang.GenGnoModLatest("gno.land/r/demo"),
+					},
+				})},
+				Fee:        std.Fee{GasWanted: 1e6, GasFee: std.Coin{Amount: 1e6, Denom: "ugnot"}},
+				Signatures: []std.Signature{{}}, // one empty signature
+			},
+		},
+	}
+	resp := bapp.InitChain(abci.RequestInitChain{
+		Time:    time.Now(),
+		ChainID: chainID,
+		ConsensusParams: &abci.ConsensusParams{
+			Block: defaultBlockParams(),
+		},
+		AppState: appState,
+	})
+	require.True(t, resp.IsOK(), "InitChain response: %v", resp)
+	bapp.Commit()
+
+	// Commit a few empty blocks.
+	genesisHeight := bapp.LastBlockHeight()
+	for h := genesisHeight + 1; h <= genesisHeight+3; h++ {
+		bapp.BeginBlock(abci.RequestBeginBlock{
+			Header: &bft.Header{ChainID: chainID, Height: h},
+		})
+		bapp.EndBlock(abci.RequestEndBlock{})
+		bapp.Commit()
+	}
+
+	// The vm state can be queried at a past height.
+	qres := bapp.Query(abci.RequestQuery{
+		Path:   "vm/qeval",
+		Data:   []byte("gno.land/r/demo.Version"),
+		Height: genesisHeight + 1,
+	})
+	require.True(t, qres.IsOK(), "Query response: %v", qres)
+	assert.Equal(t, `("v1" string)`, string(qres.Data))
+}
+
+func TestNewAppWithOptions_ObjectProof(t *testing.T) {
+	t.Parallel()
+
+	app, err := NewAppWithOptions(TestAppOptions(memdb.NewMemDB()))
+	require.NoError(t, err)
+	bapp := app.(*sdk.BaseApp)
+
+	addr := crypto.AddressFromPreimage([]byte("test1"))
+	appState := DefaultGenState()
+	appState.Balances = []Balance{
+		{
+			Address: addr,
+			Amount:  []std.Coin{{Amount: 1e15, Denom: "ugnot"}},
+		},
+	}
+	appState.Txs = []TxWithMetadata{
+		{
+			Tx: std.Tx{
+				Msgs: []std.Msg{vm.NewMsgAddPackage(addr, "gno.land/r/demo", []*std.MemFile{
+					{
+						Name: "demo.gno",
+						Body: "package demo; type Config struct{ Name string }; var config = Config{Name: `v1`}",
+					},
+					{
+						Name: "gnomod.toml",
+						Body: gnolang.GenGnoModLatest("gno.land/r/demo"),
+					},
+				})},
+				Fee:        std.Fee{GasWanted: 1e6, GasFee: std.Coin{Amount: 1e6, Denom: "ugnot"}},
+				Signatures: []std.Signature{{}}, // one empty signature
+			},
+		},
+	}
+	resp := bapp.InitChain(abci.RequestInitChain{
+		Time:    time.Now(),
+		ChainID: "dev",
+		ConsensusParams: &abci.ConsensusParams{
+			Block: defaultBlockParams(),
+		},
+		AppState: appState,
+	})
+	require.True(t, resp.IsOK(), "InitChain response: %v", resp)
+	bapp.Commit()
+
+	// Proofs are only available after the genesis.
+	bapp.BeginBlock(abci.RequestBeginBlock{
+		Header: &bft.Header{ChainID: "dev", Height: bapp.LastBlockHeight() + 1},
+	})
+	bapp.EndBlock(abci.RequestEndBlock{})
+	appHash := bapp.Commit().Data
+
+	qres := bapp.Query(abci.RequestQuery{
+		Path:  "vm/qobject",
+		Data:  []byte("gno.land/r/demo.config"),
+		Prove: true,
+	})
+	require.True(t, qres.IsOK(), "Query response: %v", qres)
+	require.NotNil(t, qres.Proof)
+
+	oo, err := vm.VerifyObjectProof(qres, appHash, "main")
+	require.NoError(t, err)
+	sv, ok := oo.(*gnolang.StructValue)
+	require.True(t, ok, "%T", oo)
+	assert.Equal(t, gnolang.StringValue("v1"), sv.Fields[0].V)
+
+	// The proof does not verify against another app hash.
+	badHash := slices.Clone(appHash)
+	badHash[0]++
+	_, err = vm.VerifyObjectProof(qres, badHash, "main")
+	assert.Error(t, err)
+}
+
 func TestNewAppWithOptions_ErrNoDB(t *testing.T) {
 	t.Parallel()
 
@@ -1045,6 +1207,73 @@ func TestGasPriceUpdate(t *testing.T) {
 	require.Equal(t, "100ugnot", gp.Price.String())
 }
 
+// Tests that CheckTx reports the mempool priority of txs, and accepts the
+// replacement of a pending tx with the same sequence.
+func TestCheckTxReplacement(t *testing.T) {
+	t.Parallel()
+
+	app, err := NewAppWithOptions(TestAppOptions(memdb.NewMemDB()))
+	require.NoError(t, err)
+	bapp := app.(*sdk.BaseApp)
+
+	key := getDummyKey(t)
+	addr := key.PubKey().Address()
+	appState := DefaultGenState()
+	appState.Balances = []Balance{{Address: addr, Amount: std.MustParseCoins("1000000000000ugnot")}}
+	resp := bapp.InitChain(abci.RequestInitChain{
+		ChainID:         "dev",
+		ConsensusParams: &abci.ConsensusParams{Block: defaultBlockParams()},
+		AppState:        appState,
+	})
+	require.True(t, resp.IsOK(), "InitChain response: %v", resp)
+	// signatures are verified past the genesis block
+	bapp.BeginBlock(abci.RequestBeginBlock{Header: &bft.Header{ChainID: "dev", Height: 1}})
+	bapp.EndBlock(abci.RequestEndBlock{})
+	bapp.Commit()
+
+	checkTx := func(sequence uint64, gasFee int64) abci.ResponseCheckTx {
+		t.Helper()
+
+		tx := std.Tx{
+			Msgs: []std.Msg{bank.NewMsgSend(addr, crypto.AddressFromPreimage([]byte("to")), std.MustParseCoins("1ugnot"))},
+			Fee:  std.NewFee(1_000_000, std.NewCoin("ugnot", gasFee)),
+		}
+		signBytes, err := tx.GetSignBytes("dev", 0, sequence)
+		require.NoError(t, err)
+		sig, err := key.Sign(signBytes)
+		require.NoError(t, err)
+		tx.Signatures = []std.Signature{{PubKey: key.PubKey(), Signature: sig}}
+
+		return bapp.CheckTx(abci.RequestCheckTx{Tx: amino.MustMarshal(tx)})
+	}
+
+	res := checkTx(0, 1_000_000)
+	require.True(t, res.IsOK(), "CheckTx response: %v", res)
+	assert.Equal(t, auth.TxPriority(std.NewFee(1_000_000, std.NewCoin("ugnot", 1_000_000))), res.Priority)
+	assert.Equal(t, addr.String(), res.Sender)
+	assert.Equal(t, uint64(0), res.Sequence)
+
+	// the next sequence
+	res = checkTx(1, 1_000_000)
+	require.True(t, res.IsOK(), "CheckTx response: %v", res)
+	assert.Equal(t, uint64(1), res.Sequence)
+
+	// a replacement of the first tx, the mempool decides if it pays enough
+	res = checkTx(0, 2_000_000)
+	require.True(t, res.IsOK(), "CheckTx response: %v", res)
+	assert.Equal(t, uint64(0), res.Sequence)
+	assert.Equal(t, 2*auth.TxPriority(std.NewFee(1_000_000, std.NewCoin("ugnot", 1_000_000))), res.Priority)
+
+	// the following tx keeps its sequence
+	res = checkTx(2, 1_000_000)
+	require.True(t, res.IsOK(), "CheckTx response: %v", res)
+	assert.Equal(t, uint64(2), res.Sequence)
+
+	// a sequence which is neither pending nor next
+	res = checkTx(4, 1_000_000)
+	assert.False(t, res.IsOK())
+}
+
 func newGasPriceTestApp(t *testing.T) abci.Application {
 	t.Helper()
 	cfg := TestAppOptions(memdb.NewMemDB())
@@ -1125,7 +1354,7 @@ func newGasPriceTestApp(t *testing.T) abci.Application {
 	)
 
 	// Set a handler Route.
-	baseApp.Router().AddRoute("auth", auth.NewHandler(acck, gpk))
+	baseApp.Router().AddRoute("auth", auth.NewHandler(acck, gpk, auth.NewFeeGrantKeeper(mainKey)))
 	baseApp.Router().AddRoute("bank", bank.NewHandler(bankk))
 	baseApp.Router().AddRoute(
 		testutils.RouteMsgCounter,
@@ -1185,6 +1414,7 @@ func gnoGenesisState(t *testing.T) GnoGenesisState {
         "max_memo_bytes": "65536",
         "sig_verify_cost_ed25519": "590",
         "sig_verify_cost_secp256k1": "1000",
+        "sig_verify_cost_secp256r1": "1500",
         "target_gas_ratio": "60",
         "tx_sig_limit": "7",
         "tx_size_cost_per_byte": "10",
@@ -1315,3 +1545,81 @@ func TestPruneStrategyNothing(t *testing.T) {
 	err = db.Close()
 	require.NoError(t, err)
 }
+
+func TestPruneStrategyCustom(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chainID = "dev"
+		appDir  = t.TempDir()
+	)
+
+	appCfg := config.DefaultAppConfig()
+	appCfg.PruneStrategy = types.PruneCustomStrategy
+	appCfg.PruneKeepRecent = 2
+
+	app, err := NewApp(
+		appDir,
+		NewTestGenesisAppConfig(),
+		appCfg,
+		events.NewEventSwitch(),
+		log.NewNoopLogger(),
+	)
+	require.NoError(t, err)
+
+	base := app.(*sdk.BaseApp)
+
+	// Run the genesis initialization, and commit it
+	base.InitChain(abci.RequestInitChain{
+		ChainID: chainID,
+		Time:    time.Now(),
+		ConsensusParams: &abci.ConsensusParams{
+			Block: &abci.BlockParams{MaxGas: 1_000_000},
+		},
+		AppState: DefaultGenState(),
+	})
+	base.Commit()
+
+	// Simulate a few empty blocks being committed
+	startHeight := base.LastBlockHeight() + 1
+	for h := startHeight; h <= startHeight+5; h++ {
+		base.BeginBlock(abci.RequestBeginBlock{
+			Header: &bft.Header{ChainID: chainID, Height: h},
+		})
+
+		base.EndBlock(abci.RequestEndBlock{})
+
+		base.Commit()
+	}
+	lastHeight := base.LastBlockHeight()
+
+	// Close the app, so it releases the DB
+	require.NoError(t, base.Close())
+
+	// Reopen the same DB
+	db, err := dbm.NewDB(
+		"gnolang",
+		dbm.PebbleDBBackend,
+		filepath.Join(appDir, bftCfg.DefaultDBDir),
+	)
+	require.NoError(t, err)
+
+	var (
+		mainKey = store.NewStoreKey("main")
+		baseKey = store.NewStoreKey("base")
+	)
+
+	newCMS := func() store.CommitMultiStore {
+		cms := store.NewCommitMultiStore(db)
+		cms.MountStoreWithDB(mainKey, iavl.StoreConstructor, db)
+		cms.MountStoreWithDB(baseKey, dbadapter.StoreConstructor, db)
+		return cms
+	}
+
+	// Only the recent versions are kept
+	assert.NoError(t, newCMS().LoadVersion(lastHeight-2))
+	assert.Error(t, newCMS().LoadVersion(1))
+
+	err = db.Close()
+	require.NoError(t, err)
+}
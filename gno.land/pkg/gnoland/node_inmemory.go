@@ -113,6 +113,7 @@ func NewInMemoryNode(logger *slog.Logger, cfg *InMemoryNodeConfig) (*node.Node,
 		InitChainerConfig:          cfg.InitChainerConfig,
 		VMOutput:                   cfg.VMOutput,
 		SkipGenesisSigVerification: cfg.SkipGenesisSigVerification,
+		PruningOptions:             cfg.TMConfig.Application.PruningOptions(),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error initializing new app: %w", err)
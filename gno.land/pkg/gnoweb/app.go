@@ -97,6 +97,7 @@ func NewRouter(logger *slog.Logger, cfg *AppConfig) (http.Handler, error) {
 
 	// Setup client adapter
 	adpcli := NewRPCClientAdapter(logger, rpcclient, cfg.Domain)
+	chaincli := NewRPCChainClient(logger, rpcclient)
 
 	// Setup StaticMetadata
 	chromaStylePath := path.Join(assetsBase, "_chroma", "style.css")
@@ -129,6 +130,7 @@ func NewRouter(logger *slog.Logger, cfg *AppConfig) (http.Handler, error) {
 	}
 	httphandler, err := NewHTTPHandler(logger, &HTTPHandlerConfig{
 		ClientAdapter: adpcli,
+		ChainClient:   chaincli,
 		Meta:          staticMeta,
 		Renderer:      renderer,
 		Aliases:       cfg.Aliases,
@@ -174,6 +176,10 @@ func NewRouter(logger *slog.Logger, cfg *AppConfig) (http.Handler, error) {
 	assetsHandler := cacheAssetHandler(AssetHandler())
 	mux.Handle(assetsBase, http.StripPrefix(assetsBase, assetsHandler))
 
+	// Handle transaction building and broadcasting, used by the help page
+	mux.Handle("/tx/build", handlerTxBuildJSON(logger, chaincli, cfg.ChainID))
+	mux.Handle("/tx/broadcast", handlerTxBroadcastJSON(logger, chaincli))
+
 	// Handle status page
 	mux.Handle("/status.json", handlerStatusJSON(logger, rpcclient))
 
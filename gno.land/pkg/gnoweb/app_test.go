@@ -84,6 +84,20 @@ func TestRoutes(t *testing.T) {
 			{"/test3", notFound, ""},       // Alias "/test3" points to "/r/not/found" which doesn't exist
 			{"/test4", ok, uuid2.String()}, // Alias "/test2_b" points to another static file containing an uuid
 			{"/test123", badRequest, ""},   // Alias "/test123" doesn't exist, points to "" which is not valid
+
+			// Chain explorer
+			{"/chain", ok, "Latest blocks"},
+			{"/chain/blocks/1", ok, "Block 1"},
+			{"/chain/blocks/100000", notFound, ""},
+			{"/chain/blocks/abc", badRequest, ""},
+			{"/chain/txs/00ff", notFound, ""},
+			{"/chain/validators", ok, "Voting power"},
+			{"/chain/accounts/g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5", ok, "Sequence"},
+
+			// Transactions
+			{"/tx/build?caller=g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5&pkgpath=gno.land/r/gnoland/blog&func=AdminSetAdminAddr&args=g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5", ok, `"chain_id": "tendermint_test"`},
+			{"/tx/build", badRequest, "invalid caller address"},
+			{"/tx/broadcast", http.StatusMethodNotAllowed, ""},
 		}
 	)
 
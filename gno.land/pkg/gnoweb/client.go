@@ -22,6 +22,7 @@ var (
 	ErrClientBadRequest        = errors.New("bad request")
 	ErrClientTimeout           = errors.New("RPC node request timeout")
 	ErrClientResponse          = errors.New("RPC node response error")
+	ErrClientNotFound          = errors.New("not found")
 )
 
 type FileMeta struct {
@@ -0,0 +1,151 @@
+package gnoweb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// ChainClient fetches the blocks, transactions, accounts and validators
+// shown by the chain explorer views.
+type ChainClient interface {
+	// Blocks returns the metas of the blocks from minHeight to maxHeight,
+	// latest first, and at most 20 of them. A maxHeight of 0 is the latest
+	// height.
+	Blocks(ctx context.Context, minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error)
+
+	// Block returns the block at the given height, and the results of its
+	// transactions.
+	Block(ctx context.Context, height int64) (*ctypes.ResultBlock, *ctypes.ResultBlockResults, error)
+
+	// Tx returns the transaction of the given hash, and its result.
+	Tx(ctx context.Context, hash []byte) (*ctypes.ResultTx, error)
+
+	// Account returns the account of the given address.
+	Account(ctx context.Context, addr crypto.Address) (*std.BaseAccount, error)
+
+	// Validators returns the latest validator set.
+	Validators(ctx context.Context) (*ctypes.ResultValidators, error)
+
+	// BroadcastTx broadcasts the given signed and amino encoded
+	// transaction, and waits for it to be committed.
+	BroadcastTx(ctx context.Context, tx []byte) (*ctypes.ResultBroadcastTxCommit, error)
+}
+
+type rpcChainClient struct {
+	logger *slog.Logger
+	client *client.RPCClient
+}
+
+var _ ChainClient = (*rpcChainClient)(nil)
+
+// NewRPCChainClient creates a new ChainClient fetching the chain data from
+// the given RPC client.
+func NewRPCChainClient(logger *slog.Logger, cli *client.RPCClient) ChainClient {
+	return &rpcChainClient{
+		logger: logger,
+		client: cli,
+	}
+}
+
+func (c *rpcChainClient) Blocks(ctx context.Context, minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
+	res, err := c.client.BlockchainInfo(ctx, minHeight, maxHeight)
+	if err != nil {
+		return nil, c.wrapError("blockchain", err)
+	}
+	return res, nil
+}
+
+func (c *rpcChainClient) Block(ctx context.Context, height int64) (*ctypes.ResultBlock, *ctypes.ResultBlockResults, error) {
+	block, err := c.client.Block(ctx, &height)
+	if err != nil {
+		return nil, nil, c.wrapError("block", err)
+	}
+
+	results, err := c.client.BlockResults(ctx, &height)
+	if err != nil {
+		return nil, nil, c.wrapError("block_results", err)
+	}
+
+	return block, results, nil
+}
+
+func (c *rpcChainClient) Tx(ctx context.Context, hash []byte) (*ctypes.ResultTx, error) {
+	res, err := c.client.Tx(ctx, hash)
+	if err != nil {
+		return nil, c.wrapError("tx", err)
+	}
+	return res, nil
+}
+
+func (c *rpcChainClient) Account(ctx context.Context, addr crypto.Address) (*std.BaseAccount, error) {
+	qpath := "auth/accounts/" + addr.String()
+
+	start := time.Now()
+	qres, err := c.client.ABCIQuery(ctx, qpath, nil)
+	if err != nil {
+		return nil, c.wrapError(qpath, err)
+	}
+	c.logger.Debug("query response received", "path", qpath, "took", time.Since(start))
+
+	if qres.Response.Error != nil {
+		c.logger.Error("node response error", "path", qpath, "error", qres.Response.Error)
+		return nil, fmt.Errorf("%w: %w", ErrClientResponse, qres.Response.Error)
+	}
+
+	data := qres.Response.Data
+	if len(data) == 0 || string(data) == "null" {
+		return nil, ErrClientNotFound
+	}
+
+	var qret struct{ BaseAccount std.BaseAccount }
+	if err := amino.UnmarshalJSON(data, &qret); err != nil {
+		c.logger.Warn("unable to unmarshal account, client is probably outdated")
+		return nil, fmt.Errorf("unable to unmarshal account %s: %w", addr, err)
+	}
+
+	return &qret.BaseAccount, nil
+}
+
+func (c *rpcChainClient) Validators(ctx context.Context) (*ctypes.ResultValidators, error) {
+	res, err := c.client.Validators(ctx, nil)
+	if err != nil {
+		return nil, c.wrapError("validators", err)
+	}
+	return res, nil
+}
+
+func (c *rpcChainClient) BroadcastTx(ctx context.Context, tx []byte) (*ctypes.ResultBroadcastTxCommit, error) {
+	res, err := c.client.BroadcastTxCommit(ctx, tx)
+	if err != nil {
+		return nil, c.wrapError("broadcast_tx_commit", err)
+	}
+	return res, nil
+}
+
+// wrapError logs and wraps an error of an RPC request.
+func (c *rpcChainClient) wrapError(method string, err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		c.logger.Error("chain request timeout", "method", method, "error", err)
+		return fmt.Errorf("%w: %s", ErrClientTimeout, err.Error())
+	// XXX: the RPC errors are not assertable, match their message.
+	case strings.Contains(err.Error(), "less than or equal to the current blockchain height"),
+		strings.Contains(err.Error(), "greater than or equal to"),
+		strings.Contains(err.Error(), "Could not find tx result for hash"):
+		c.logger.Debug("chain data not found", "method", method, "error", err)
+		return fmt.Errorf("%w: %s", ErrClientNotFound, err.Error())
+	default:
+		c.logger.Error("chain request failed", "method", method, "error", err)
+		return fmt.Errorf("%w: %s", ErrClientBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,122 @@
+package components
+
+import "time"
+
+const ChainViewType ViewType = "chain-view"
+
+// ChainField is a labeled value of a chain view, linking to URL if set.
+type ChainField struct {
+	Label string
+	Value string
+	URL   string
+}
+
+// ChainBlockItem is a block in the list of the latest blocks.
+type ChainBlockItem struct {
+	Height   int64
+	URL      string
+	Hash     string
+	Time     time.Time
+	NumTxs   int64
+	Proposer string
+}
+
+// ChainOverviewData contains data for the latest blocks view.
+type ChainOverviewData struct {
+	ChainId      string
+	LatestHeight int64
+	Blocks       []ChainBlockItem
+	OlderURL     string
+	NewerURL     string
+}
+
+// ChainTxItem is a transaction in the list of the transactions of a block.
+type ChainTxItem struct {
+	Index     int
+	Hash      string
+	URL       string
+	Msgs      []string
+	GasWanted int64
+	GasUsed   int64
+	Error     string
+}
+
+// ChainBlockData contains data for the block view.
+type ChainBlockData struct {
+	Height  int64
+	Fields  []ChainField
+	Txs     []ChainTxItem
+	PrevURL string
+	NextURL string
+}
+
+// ChainMsg is a decoded message of a transaction. Fields sum up the known
+// messages, and Source holds the files of the added packages and runs.
+type ChainMsg struct {
+	Type   string
+	Fields []ChainField
+	Source []ChainFile
+	JSON   string
+}
+
+// ChainFile is a source file of a message.
+type ChainFile struct {
+	Name string
+	Body string
+}
+
+// ChainTxData contains data for the transaction view.
+type ChainTxData struct {
+	Hash   string
+	Fields []ChainField
+	Error  string
+	Log    string
+	Msgs   []ChainMsg
+	Events string
+}
+
+// ChainAccountData contains data for the account view.
+type ChainAccountData struct {
+	Address string
+	Fields  []ChainField
+}
+
+// ChainValidatorItem is a validator of the validator set.
+type ChainValidatorItem struct {
+	Address          string
+	URL              string
+	PubKey           string
+	VotingPower      int64
+	ProposerPriority int64
+}
+
+// ChainValidatorsData contains data for the validators view.
+type ChainValidatorsData struct {
+	Height     int64
+	Validators []ChainValidatorItem
+}
+
+// ChainOverviewView creates a new view of the latest blocks.
+func ChainOverviewView(data ChainOverviewData) *View {
+	return NewTemplateView(ChainViewType, "renderChainOverview", data)
+}
+
+// ChainBlockView creates a new view of a block and its transactions.
+func ChainBlockView(data ChainBlockData) *View {
+	return NewTemplateView(ChainViewType, "renderChainBlock", data)
+}
+
+// ChainTxView creates a new view of a transaction and its result.
+func ChainTxView(data ChainTxData) *View {
+	return NewTemplateView(ChainViewType, "renderChainTx", data)
+}
+
+// ChainAccountView creates a new view of an account.
+func ChainAccountView(data ChainAccountData) *View {
+	return NewTemplateView(ChainViewType, "renderChainAccount", data)
+}
+
+// ChainValidatorsView creates a new view of the validator set.
+func ChainValidatorsView(data ChainValidatorsData) *View {
+	return NewTemplateView(ChainViewType, "renderChainValidators", data)
+}
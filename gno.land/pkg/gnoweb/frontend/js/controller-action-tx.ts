@@ -0,0 +1,213 @@
+import { BaseController } from "./controller.js";
+
+// TYPE DEFINITIONS
+// Injected wallet provider (eg. Adena), see docs/resources/gnoconnect.md
+interface WalletResponse<T = Record<string, unknown>> {
+	status: "success" | "failure";
+	message: string;
+	data: T;
+}
+
+interface WalletMsgCall {
+	type: "/vm.m_call";
+	value: {
+		caller: string;
+		send: string;
+		pkg_path: string;
+		func: string;
+		args: string[];
+	};
+}
+
+interface WalletProvider {
+	AddEstablish(name: string): Promise<WalletResponse>;
+	GetAccount(): Promise<WalletResponse<{ address: string }>>;
+	DoContract(tx: {
+		messages: WalletMsgCall[];
+		gasFee: number;
+		gasWanted: number;
+		memo?: string;
+	}): Promise<WalletResponse<{ hash: string; height: string }>>;
+}
+
+interface TxResult {
+	hash?: string;
+	url?: string;
+	height?: number | string;
+	error?: string;
+}
+
+// Same fee as the suggested gnokey commands and the /tx/build endpoint
+const GAS_FEE = 1000000; // ugnot
+const GAS_WANTED = 1000000000;
+
+// CONTROLLER
+export class ActionTxController extends BaseController {
+	declare _funcName: string;
+	declare _pkgPath: string;
+
+	protected connect(): void {
+		this._funcName = this.getValue("name");
+		this._pkgPath = this.getValue("pkgpath");
+	}
+
+	// read the args and the send of the function from its form
+	private _readCall(): { args: string[]; send: string } {
+		const article =
+			this.element.closest(".b-action-function") || this.element;
+		const seen = new Set<string>();
+		const args: string[] = [];
+		article
+			.querySelectorAll<HTMLInputElement>(
+				"input[data-action-function-param-value]",
+			)
+			.forEach((input) => {
+				const name = input.dataset.actionFunctionParamValue || "";
+				if (seen.has(name)) return;
+				seen.add(name);
+				args.push(input.value.trim());
+			});
+
+		const sendFlag = article.querySelector<HTMLInputElement>(
+			"input[data-action-function-send-value]",
+		);
+		const send = sendFlag?.checked
+			? sendFlag.dataset.actionFunctionSendValue || ""
+			: "";
+
+		return { args, send };
+	}
+
+	// show the result of a transaction, linking to its explorer page
+	private _showResult(result: TxResult): void {
+		const container = this.getTarget("result");
+		const text = this.getTarget("result-text");
+		const link = this.getTarget("result-link") as HTMLAnchorElement | null;
+		if (!container || !text || !link) return;
+
+		container.classList.remove("u-hidden");
+		text.classList.toggle("u-color-danger", !!result.error);
+		text.textContent = result.error
+			? `Error: ${result.error}`
+			: `Transaction committed at height ${result.height}.`;
+
+		link.classList.toggle("u-hidden", !result.url);
+		if (result.url) link.href = result.url;
+	}
+
+	// wallets may return base64 encoded hashes, the explorer expects hex
+	private _hexHash(hash: string): string {
+		if (/^[0-9a-f]{64}$/i.test(hash)) return hash.toLowerCase();
+		try {
+			return Array.from(atob(hash), (c) =>
+				c.charCodeAt(0).toString(16).padStart(2, "0"),
+			).join("");
+		} catch {
+			return "";
+		}
+	}
+
+	// DOM ACTIONS
+	// sign and broadcast the call with the injected wallet (DOM action)
+	public async signWithWallet(): Promise<void> {
+		const wallet = (window as { adena?: WalletProvider }).adena;
+		if (!wallet) {
+			this._showResult({ error: "no browser wallet found" });
+			return;
+		}
+
+		try {
+			await wallet.AddEstablish(document.title);
+			const account = await wallet.GetAccount();
+			if (account.status !== "success") {
+				this._showResult({ error: account.message });
+				return;
+			}
+
+			const { args, send } = this._readCall();
+			const res = await wallet.DoContract({
+				messages: [
+					{
+						type: "/vm.m_call",
+						value: {
+							caller: account.data.address,
+							send,
+							pkg_path: this._pkgPath,
+							func: this._funcName,
+							args,
+						},
+					},
+				],
+				gasFee: GAS_FEE,
+				gasWanted: GAS_WANTED,
+			});
+			if (res.status !== "success") {
+				this._showResult({ error: res.message });
+				return;
+			}
+
+			const hash = this._hexHash(res.data.hash);
+			this._showResult({
+				height: res.data.height,
+				url: hash ? `/chain/txs/${hash}` : undefined,
+			});
+		} catch (err) {
+			this._showResult({ error: String(err) });
+		}
+	}
+
+	// build the unsigned transaction and its gnokey commands (DOM action)
+	public async build(): Promise<void> {
+		const caller = localStorage.getItem("actionAddressInput") || "";
+		const { args, send } = this._readCall();
+
+		const query = new URLSearchParams({
+			caller: caller.trim(),
+			pkgpath: this._pkgPath,
+			func: this._funcName,
+			send,
+		});
+		args.forEach((arg) => query.append("args", arg));
+
+		try {
+			const response = await fetch(`/tx/build?${query}`);
+			const res = await response.json();
+			if (!response.ok) {
+				this._showResult({ error: res.error });
+				return;
+			}
+
+			const unsigned = this.getTarget("unsigned");
+			const tx = this.getTarget("unsigned-tx");
+			const command = this.getTarget("sign-command");
+			if (!unsigned || !tx || !command) return;
+
+			tx.textContent = JSON.stringify(res.tx, null, 2);
+			command.textContent = [
+				"# Save the transaction above as call.tx, then sign it:",
+				`gnokey sign -tx-path call.tx -chainid "${res.chain_id}" -account-number ${res.account_number} -account-sequence ${res.sequence} ${caller}`,
+				"# and paste the content of call.tx below to broadcast it.",
+			].join("\n");
+			unsigned.classList.remove("u-hidden");
+		} catch (err) {
+			this._showResult({ error: String(err) });
+		}
+	}
+
+	// broadcast the pasted signed transaction (DOM action)
+	public async broadcast(): Promise<void> {
+		const signed = this.getTarget("signed-tx") as HTMLTextAreaElement | null;
+		if (!signed?.value.trim()) return;
+
+		try {
+			const response = await fetch("/tx/broadcast", {
+				method: "POST",
+				headers: { "Content-Type": "application/json" },
+				body: signed.value,
+			});
+			this._showResult(await response.json());
+		} catch (err) {
+			this._showResult({ error: String(err) });
+		}
+	}
+}
@@ -0,0 +1,406 @@
+package gnoweb
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/components"
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+const chainPathPrefix = "/chain"
+
+// chainBlocksPerPage is the number of blocks returned by the blockchain RPC
+// endpoint, and so listed per page of the overview.
+const chainBlocksPerPage = 20
+
+// GetChainView renders the chain explorer pages:
+//
+//	/chain                     the latest blocks, or the ones up to $max=<height>
+//	/chain/blocks/<height>     a block and its transactions
+//	/chain/txs/<hash>          a transaction, by hex hash
+//	/chain/accounts/<address>  an account
+//	/chain/validators          the validator set
+func (h *HTTPHandler) GetChainView(ctx context.Context, gnourl *weburl.GnoURL) (int, *components.View) {
+	if h.Chain == nil {
+		return http.StatusNotFound, components.StatusErrorComponent("chain explorer not available")
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(gnourl.Path, chainPathPrefix), "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "":
+		return h.GetChainOverviewView(ctx, gnourl)
+	case len(parts) == 2 && parts[0] == "blocks":
+		height, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || height <= 0 {
+			return http.StatusBadRequest, components.StatusErrorComponent("invalid block height")
+		}
+		return h.GetChainBlockView(ctx, gnourl, height)
+	case len(parts) == 2 && parts[0] == "txs":
+		hash, err := hex.DecodeString(parts[1])
+		if err != nil || len(hash) == 0 {
+			return http.StatusBadRequest, components.StatusErrorComponent("invalid transaction hash")
+		}
+		return h.GetChainTxView(ctx, gnourl, hash)
+	case len(parts) == 2 && parts[0] == "accounts":
+		addr, err := crypto.AddressFromBech32(parts[1])
+		if err != nil {
+			return http.StatusBadRequest, components.StatusErrorComponent("invalid address")
+		}
+		return h.GetChainAccountView(ctx, gnourl, addr)
+	case len(parts) == 1 && parts[0] == "validators":
+		return h.GetChainValidatorsView(ctx, gnourl)
+	default:
+		return http.StatusNotFound, components.StatusErrorComponent("invalid path")
+	}
+}
+
+// GetChainOverviewView renders the list of the latest blocks.
+func (h *HTTPHandler) GetChainOverviewView(ctx context.Context, gnourl *weburl.GnoURL) (int, *components.View) {
+	var maxHeight int64
+	if max := gnourl.WebQuery.Get("max"); max != "" {
+		var err error
+		if maxHeight, err = strconv.ParseInt(max, 10, 64); err != nil || maxHeight <= 0 {
+			return http.StatusBadRequest, components.StatusErrorComponent("invalid block height")
+		}
+	}
+
+	res, err := h.Chain.Blocks(ctx, 0, maxHeight)
+	if err != nil {
+		h.Logger.Error("unable to fetch blocks", "max", maxHeight, "error", err)
+		return GetClientErrorStatusPage(gnourl, err)
+	}
+
+	data := components.ChainOverviewData{
+		ChainId:      h.Static.ChainId,
+		LatestHeight: res.LastHeight,
+		Blocks:       make([]components.ChainBlockItem, 0, len(res.BlockMetas)),
+	}
+	for _, meta := range res.BlockMetas {
+		data.Blocks = append(data.Blocks, components.ChainBlockItem{
+			Height:   meta.Header.Height,
+			URL:      chainBlockURL(meta.Header.Height),
+			Hash:     hex.EncodeToString(meta.BlockID.Hash),
+			Time:     meta.Header.Time,
+			NumTxs:   meta.Header.NumTxs,
+			Proposer: meta.Header.ProposerAddress.String(),
+		})
+	}
+
+	if n := len(data.Blocks); n > 0 {
+		if oldest := data.Blocks[n-1].Height; oldest > 1 {
+			data.OlderURL = fmt.Sprintf("%s$max=%d", chainPathPrefix, oldest-1)
+		}
+		if newest := data.Blocks[0].Height; newest < res.LastHeight {
+			data.NewerURL = fmt.Sprintf("%s$max=%d", chainPathPrefix, min(newest+chainBlocksPerPage, res.LastHeight))
+		}
+	}
+
+	return http.StatusOK, components.ChainOverviewView(data)
+}
+
+// GetChainBlockView renders a block and the list of its transactions.
+func (h *HTTPHandler) GetChainBlockView(ctx context.Context, gnourl *weburl.GnoURL, height int64) (int, *components.View) {
+	block, results, err := h.Chain.Block(ctx, height)
+	if err != nil {
+		h.Logger.Warn("unable to fetch block", "height", height, "error", err)
+		return GetClientErrorStatusPage(gnourl, err)
+	}
+
+	header := block.Block.Header
+	data := components.ChainBlockData{
+		Height: height,
+		Fields: []components.ChainField{
+			{Label: "Hash", Value: hex.EncodeToString(block.BlockMeta.BlockID.Hash)},
+			{Label: "Chain ID", Value: header.ChainID},
+			{Label: "Time", Value: header.Time.UTC().String()},
+			{Label: "Proposer", Value: header.ProposerAddress.String(), URL: chainAccountURL(header.ProposerAddress)},
+			{Label: "Transactions", Value: strconv.FormatInt(header.NumTxs, 10)},
+			{Label: "Total transactions", Value: strconv.FormatInt(header.TotalTxs, 10)},
+			{Label: "App hash", Value: hex.EncodeToString(header.AppHash)},
+		},
+	}
+	if height > 1 {
+		data.PrevURL = chainBlockURL(height - 1)
+	}
+	data.NextURL = chainBlockURL(height + 1)
+
+	for i, txbz := range block.Block.Txs {
+		item := components.ChainTxItem{
+			Index: i,
+			Hash:  hex.EncodeToString(txbz.Hash()),
+		}
+		item.URL = chainTxURL(item.Hash)
+
+		var tx std.Tx
+		if err := amino.Unmarshal(txbz, &tx); err != nil {
+			item.Msgs = []string{"undecodable"}
+		} else {
+			for _, msg := range tx.Msgs {
+				item.Msgs = append(item.Msgs, chainMsgType(msg))
+			}
+		}
+
+		if results != nil && results.Results != nil && i < len(results.Results.DeliverTxs) {
+			res := results.Results.DeliverTxs[i]
+			item.GasWanted, item.GasUsed = res.GasWanted, res.GasUsed
+			if res.Error != nil {
+				item.Error = res.Error.Error()
+			}
+		}
+
+		data.Txs = append(data.Txs, item)
+	}
+
+	return http.StatusOK, components.ChainBlockView(data)
+}
+
+// GetChainTxView renders a transaction, its decoded messages and its result.
+func (h *HTTPHandler) GetChainTxView(ctx context.Context, gnourl *weburl.GnoURL, hash []byte) (int, *components.View) {
+	res, err := h.Chain.Tx(ctx, hash)
+	if err != nil {
+		h.Logger.Warn("unable to fetch transaction", "hash", hex.EncodeToString(hash), "error", err)
+		return GetClientErrorStatusPage(gnourl, err)
+	}
+
+	result := res.TxResult
+	status := "Success"
+	if result.Error != nil {
+		status = "Failed"
+	}
+
+	data := components.ChainTxData{
+		Hash: hex.EncodeToString(hash),
+		Fields: []components.ChainField{
+			{Label: "Status", Value: status},
+			{Label: "Block", Value: strconv.FormatInt(res.Height, 10), URL: chainBlockURL(res.Height)},
+			{Label: "Index", Value: strconv.FormatUint(uint64(res.Index), 10)},
+			{Label: "Gas used", Value: strconv.FormatInt(result.GasUsed, 10)},
+			{Label: "Gas wanted", Value: strconv.FormatInt(result.GasWanted, 10)},
+		},
+		Log: result.Log,
+	}
+	if result.Error != nil {
+		data.Error = result.Error.Error()
+	}
+	if len(result.Events) > 0 {
+		data.Events = chainJSON(result.Events)
+	}
+
+	var tx std.Tx
+	if err := amino.Unmarshal(res.Tx, &tx); err != nil {
+		h.Logger.Warn("unable to decode transaction", "hash", data.Hash, "error", err)
+		data.Msgs = []components.ChainMsg{{Type: "undecodable transaction"}}
+		return http.StatusOK, components.ChainTxView(data)
+	}
+
+	data.Fields = append(data.Fields,
+		components.ChainField{Label: "Fee", Value: tx.Fee.GasFee.String()},
+	)
+	for _, signer := range tx.GetSigners() {
+		data.Fields = append(data.Fields,
+			components.ChainField{Label: "Signer", Value: signer.String(), URL: chainAccountURL(signer)},
+		)
+	}
+	if tx.Memo != "" {
+		data.Fields = append(data.Fields,
+			components.ChainField{Label: "Memo", Value: tx.Memo},
+		)
+	}
+	// The info holds the JSON results of the calls.
+	if info := strings.TrimSpace(result.Info); info != "" {
+		data.Fields = append(data.Fields,
+			components.ChainField{Label: "Info", Value: info},
+		)
+	}
+
+	for _, msg := range tx.Msgs {
+		data.Msgs = append(data.Msgs, h.chainMsg(msg))
+	}
+
+	return http.StatusOK, components.ChainTxView(data)
+}
+
+// GetChainAccountView renders the balance and sequence of an account.
+func (h *HTTPHandler) GetChainAccountView(ctx context.Context, gnourl *weburl.GnoURL, addr crypto.Address) (int, *components.View) {
+	acc, err := h.Chain.Account(ctx, addr)
+	if err != nil {
+		h.Logger.Warn("unable to fetch account", "address", addr.String(), "error", err)
+		return GetClientErrorStatusPage(gnourl, err)
+	}
+
+	pubKey := "none"
+	if acc.PubKey != nil {
+		pubKey = acc.PubKey.String()
+	}
+	balance := acc.Coins.String()
+	if balance == "" {
+		balance = "0"
+	}
+
+	return http.StatusOK, components.ChainAccountView(components.ChainAccountData{
+		Address: addr.String(),
+		Fields: []components.ChainField{
+			{Label: "Balance", Value: balance},
+			{Label: "Account number", Value: strconv.FormatUint(acc.AccountNumber, 10)},
+			{Label: "Sequence", Value: strconv.FormatUint(acc.Sequence, 10)},
+			{Label: "Public key", Value: pubKey},
+			{Label: "Packages", Value: "/u/" + addr.String(), URL: "/u/" + addr.String()},
+		},
+	})
+}
+
+// GetChainValidatorsView renders the latest validator set.
+func (h *HTTPHandler) GetChainValidatorsView(ctx context.Context, gnourl *weburl.GnoURL) (int, *components.View) {
+	res, err := h.Chain.Validators(ctx)
+	if err != nil {
+		h.Logger.Error("unable to fetch validators", "error", err)
+		return GetClientErrorStatusPage(gnourl, err)
+	}
+
+	data := components.ChainValidatorsData{
+		Height:     res.BlockHeight,
+		Validators: make([]components.ChainValidatorItem, 0, len(res.Validators)),
+	}
+	for _, val := range res.Validators {
+		item := components.ChainValidatorItem{
+			Address:          val.Address.String(),
+			URL:              chainAccountURL(val.Address),
+			VotingPower:      val.VotingPower,
+			ProposerPriority: val.ProposerPriority,
+		}
+		if val.PubKey != nil {
+			item.PubKey = val.PubKey.String()
+		}
+		data.Validators = append(data.Validators, item)
+	}
+
+	return http.StatusOK, components.ChainValidatorsView(data)
+}
+
+// chainMsg decodes a message of a transaction, summing up the known ones.
+func (h *HTTPHandler) chainMsg(msg std.Msg) components.ChainMsg {
+	cmsg := components.ChainMsg{
+		Type: chainMsgType(msg),
+		JSON: chainJSON(msg),
+	}
+
+	switch msg := msg.(type) {
+	case vm.MsgCall:
+		pkgURL := h.chainPkgURL(msg.PkgPath)
+		cmsg.Fields = []components.ChainField{
+			{Label: "Caller", Value: msg.Caller.String(), URL: chainAccountURL(msg.Caller)},
+			{Label: "Package", Value: msg.PkgPath, URL: pkgURL},
+			{Label: "Function", Value: msg.Func, URL: pkgURL + "$help&func=" + msg.Func},
+		}
+		for i, arg := range msg.Args {
+			cmsg.Fields = append(cmsg.Fields, components.ChainField{
+				Label: fmt.Sprintf("Arg %d", i), Value: arg,
+			})
+		}
+		cmsg.Fields = append(cmsg.Fields, chainCoinsFields(msg.Send, msg.MaxDeposit)...)
+	case vm.MsgAddPackage:
+		cmsg.Fields = h.chainPkgFields(msg.Creator, msg.Package)
+		cmsg.Fields = append(cmsg.Fields, chainCoinsFields(msg.Send, msg.MaxDeposit)...)
+	case vm.MsgUpgradePackage:
+		cmsg.Fields = h.chainPkgFields(msg.Creator, msg.Package)
+		cmsg.Fields = append(cmsg.Fields, chainCoinsFields(msg.Send, msg.MaxDeposit)...)
+	case vm.MsgRun:
+		cmsg.Fields = []components.ChainField{
+			{Label: "Caller", Value: msg.Caller.String(), URL: chainAccountURL(msg.Caller)},
+		}
+		cmsg.Fields = append(cmsg.Fields, chainCoinsFields(msg.Send, msg.MaxDeposit)...)
+		// The source of a run is not stored on chain: show it.
+		if msg.Package != nil {
+			for _, file := range msg.Package.Files {
+				cmsg.Source = append(cmsg.Source, components.ChainFile{Name: file.Name, Body: file.Body})
+			}
+		}
+		// Do not repeat the source in the JSON.
+		cmsg.JSON = ""
+	case bank.MsgSend:
+		cmsg.Fields = []components.ChainField{
+			{Label: "From", Value: msg.FromAddress.String(), URL: chainAccountURL(msg.FromAddress)},
+			{Label: "To", Value: msg.ToAddress.String(), URL: chainAccountURL(msg.ToAddress)},
+			{Label: "Amount", Value: msg.Amount.String()},
+		}
+	}
+
+	return cmsg
+}
+
+// chainPkgFields returns the fields of a message adding or upgrading pkg.
+func (h *HTTPHandler) chainPkgFields(creator crypto.Address, pkg *std.MemPackage) []components.ChainField {
+	fields := []components.ChainField{
+		{Label: "Creator", Value: creator.String(), URL: chainAccountURL(creator)},
+	}
+	if pkg == nil {
+		return fields
+	}
+
+	fields = append(fields, components.ChainField{
+		Label: "Package", Value: pkg.Path, URL: h.chainPkgURL(pkg.Path),
+	})
+	for _, file := range pkg.Files {
+		fields = append(fields, components.ChainField{
+			Label: "File",
+			Value: fmt.Sprintf("%s (%d bytes)", file.Name, len(file.Body)),
+			URL:   h.chainPkgURL(pkg.Path) + "/" + file.Name,
+		})
+	}
+	return fields
+}
+
+// chainPkgURL returns the gnoweb URL of a package path of the chain domain,
+// or "" for another domain.
+func (h *HTTPHandler) chainPkgURL(pkgPath string) string {
+	if rest, ok := strings.CutPrefix(pkgPath, h.Static.Domain+"/"); ok {
+		return "/" + rest
+	}
+	return ""
+}
+
+func chainCoinsFields(send, maxDeposit std.Coins) []components.ChainField {
+	var fields []components.ChainField
+	if !send.IsZero() {
+		fields = append(fields, components.ChainField{Label: "Send", Value: send.String()})
+	}
+	if !maxDeposit.IsZero() {
+		fields = append(fields, components.ChainField{Label: "Max deposit", Value: maxDeposit.String()})
+	}
+	return fields
+}
+
+// chainMsgType returns the Go type of msg, e.g. "vm.MsgCall".
+func chainMsgType(msg std.Msg) string {
+	return fmt.Sprintf("%T", msg)
+}
+
+func chainJSON(v any) string {
+	bz, err := amino.MarshalJSONIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("unable to encode: %v", err)
+	}
+	return string(bz)
+}
+
+func chainBlockURL(height int64) string {
+	return chainPathPrefix + "/blocks/" + strconv.FormatInt(height, 10)
+}
+
+func chainTxURL(hash string) string {
+	return chainPathPrefix + "/txs/" + hash
+}
+
+func chainAccountURL(addr crypto.Address) string {
+	return chainPathPrefix + "/accounts/" + addr.String()
+}
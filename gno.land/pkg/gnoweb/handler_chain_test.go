@@ -0,0 +1,228 @@
+package gnoweb_test
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb"
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	bfttypes "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubChainClient serves a chain of blocks, each with the same transactions.
+type stubChainClient struct {
+	height  int64
+	txs     []bfttypes.Tx
+	results []abci.ResponseDeliverTx
+	account *std.BaseAccount
+}
+
+var _ gnoweb.ChainClient = (*stubChainClient)(nil)
+
+func (s *stubChainClient) meta(height int64) *bfttypes.BlockMeta {
+	return &bfttypes.BlockMeta{
+		BlockID: bfttypes.BlockID{Hash: []byte{byte(height)}},
+		Header: bfttypes.Header{
+			ChainID: "test-chain",
+			Height:  height,
+			Time:    time.Now(),
+			NumTxs:  int64(len(s.txs)),
+		},
+	}
+}
+
+func (s *stubChainClient) Blocks(ctx context.Context, minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
+	if maxHeight == 0 || maxHeight > s.height {
+		maxHeight = s.height
+	}
+	res := &ctypes.ResultBlockchainInfo{LastHeight: s.height}
+	for h := maxHeight; h >= max(minHeight, 1, maxHeight-19); h-- {
+		res.BlockMetas = append(res.BlockMetas, s.meta(h))
+	}
+	return res, nil
+}
+
+func (s *stubChainClient) Block(ctx context.Context, height int64) (*ctypes.ResultBlock, *ctypes.ResultBlockResults, error) {
+	if height > s.height {
+		return nil, nil, fmt.Errorf("%w: height %d", gnoweb.ErrClientNotFound, height)
+	}
+	meta := s.meta(height)
+	block := &bfttypes.Block{Header: meta.Header, Data: bfttypes.Data{Txs: s.txs}}
+	results := &ctypes.ResultBlockResults{
+		Height:  height,
+		Results: &sm.ABCIResponses{DeliverTxs: s.results},
+	}
+	return &ctypes.ResultBlock{BlockMeta: meta, Block: block}, results, nil
+}
+
+func (s *stubChainClient) Tx(ctx context.Context, hash []byte) (*ctypes.ResultTx, error) {
+	for i, tx := range s.txs {
+		if string(tx.Hash()) == string(hash) {
+			return &ctypes.ResultTx{
+				Hash:     hash,
+				Height:   s.height,
+				Index:    uint32(i),
+				TxResult: s.results[i],
+				Tx:       tx,
+			}, nil
+		}
+	}
+	return nil, gnoweb.ErrClientNotFound
+}
+
+func (s *stubChainClient) Account(ctx context.Context, addr crypto.Address) (*std.BaseAccount, error) {
+	if s.account == nil || s.account.Address != addr {
+		return nil, gnoweb.ErrClientNotFound
+	}
+	return s.account, nil
+}
+
+func (s *stubChainClient) Validators(ctx context.Context) (*ctypes.ResultValidators, error) {
+	return &ctypes.ResultValidators{
+		BlockHeight: s.height,
+		Validators: []*bfttypes.Validator{
+			{Address: s.account.Address, VotingPower: 10},
+		},
+	}, nil
+}
+
+func (s *stubChainClient) BroadcastTx(ctx context.Context, tx []byte) (*ctypes.ResultBroadcastTxCommit, error) {
+	return nil, gnoweb.ErrClientBadRequest
+}
+
+func TestHTTPHandler_Chain(t *testing.T) {
+	t.Parallel()
+
+	caller := crypto.AddressFromPreimage([]byte("caller"))
+	callTx := std.Tx{
+		Msgs: []std.Msg{vm.MsgCall{
+			Caller:  caller,
+			PkgPath: "gno.land/r/demo/counter",
+			Func:    "Incr",
+			Args:    []string{"42"},
+			Send:    std.MustParseCoins("1000ugnot"),
+		}},
+		Fee:  std.NewFee(1_000_000, std.MustParseCoin("1000ugnot")),
+		Memo: "hello memo",
+	}
+	runTx := std.Tx{
+		Msgs: []std.Msg{vm.MsgRun{
+			Caller: caller,
+			Package: &std.MemPackage{
+				Name:  "main",
+				Path:  "gno.land/e/" + caller.String() + "/run",
+				Files: []*std.MemFile{{Name: "script.gno", Body: "package main\n\nfunc main() { println(\"run body\") }"}},
+			},
+		}},
+		Fee: std.NewFee(1_000_000, std.MustParseCoin("1000ugnot")),
+	}
+	callbz := bfttypes.Tx(amino.MustMarshal(callTx))
+	runbz := bfttypes.Tx(amino.MustMarshal(runTx))
+	client := &stubChainClient{
+		height: 30,
+		txs:    []bfttypes.Tx{callbz, runbz},
+		results: []abci.ResponseDeliverTx{
+			{
+				ResponseBase: abci.ResponseBase{Info: `[{"T":"int","V":"43"}]`},
+				GasWanted:    1_000_000,
+				GasUsed:      123_456,
+			},
+			{
+				ResponseBase: abci.ResponseBase{Error: abci.StringError("out of gas"), Log: "run failed"},
+				GasWanted:    1_000_000,
+				GasUsed:      1_000_000,
+			},
+		},
+		account: &std.BaseAccount{
+			Address:       caller,
+			Coins:         std.MustParseCoins("5000ugnot"),
+			AccountNumber: 7,
+			Sequence:      3,
+		},
+	}
+	callHash := hex.EncodeToString(callbz.Hash())
+	runHash := hex.EncodeToString(runbz.Hash())
+
+	cases := []struct {
+		path     string
+		status   int
+		contains []string
+	}{
+		{"/chain", http.StatusOK, []string{"test-chain", "Height 30", `href="/chain/blocks/30"`, `href="/chain/blocks/11"`, `href="/chain$max=10"`}},
+		{"/chain$max=10", http.StatusOK, []string{`href="/chain/blocks/10"`, `href="/chain/blocks/1"`, `href="/chain$max=30"`}},
+		{"/chain/blocks/12", http.StatusOK, []string{
+			"Block 12", `href="/chain/blocks/11"`, `href="/chain/blocks/13"`,
+			`href="/chain/txs/` + callHash + `"`, "vm.MsgCall", "123456 / 1000000", "Success",
+			`href="/chain/txs/` + runHash + `"`, "vm.MsgRun", "Failed",
+		}},
+		{"/chain/txs/" + callHash, http.StatusOK, []string{
+			"Success", `href="/chain/blocks/30"`, "1000ugnot", "hello memo",
+			`href="/chain/accounts/` + caller.String() + `"`,
+			`href="/r/demo/counter"`, `href="/r/demo/counter$help&amp;func=Incr"`, "42",
+			"[{&#34;T&#34;:&#34;int&#34;,&#34;V&#34;:&#34;43&#34;}]",
+		}},
+		{"/chain/txs/" + runHash, http.StatusOK, []string{"Failed", "out of gas", "run failed", "script.gno", "run body"}},
+		{"/chain/accounts/" + caller.String(), http.StatusOK, []string{caller.String(), "5000ugnot", "<td class=\"u-font-mono\">7</td>", "<td class=\"u-font-mono\">3</td>"}},
+		{"/chain/validators", http.StatusOK, []string{"Height 30", caller.String(), "<td>10</td>"}},
+		{"/chain/blocks/31", http.StatusNotFound, []string{"not found"}},
+		{"/chain/blocks/0", http.StatusBadRequest, []string{"invalid block height"}},
+		{"/chain/txs/00ff", http.StatusNotFound, []string{"not found"}},
+		{"/chain/txs/xyz", http.StatusBadRequest, []string{"invalid transaction hash"}},
+		{"/chain/accounts/g1notanaddress", http.StatusBadRequest, []string{"invalid address"}},
+		{"/chain/unknown", http.StatusNotFound, []string{"invalid path"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.path, func(t *testing.T) {
+			t.Parallel()
+
+			logger := slog.New(slog.NewTextHandler(&testingLogger{t}, &slog.HandlerOptions{}))
+			config := newTestHandlerConfig(t, gnoweb.NewMockClient())
+			config.ChainClient = client
+			config.Meta.Domain = "gno.land"
+			config.Meta.ChainId = "test-chain"
+			handler, err := gnoweb.NewHTTPHandler(logger, config)
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodGet, tc.path, nil)
+			require.NoError(t, err)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.status, rr.Code)
+			for _, s := range tc.contains {
+				assert.Contains(t, rr.Body.String(), s)
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_ChainDisabled(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(&testingLogger{t}, &slog.HandlerOptions{}))
+	handler, err := gnoweb.NewHTTPHandler(logger, newTestHandlerConfig(t, gnoweb.NewMockClient()))
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, "/chain", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), "chain explorer not available")
+}
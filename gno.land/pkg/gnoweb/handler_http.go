@@ -49,9 +49,11 @@ type AliasTarget struct {
 type HTTPHandlerConfig struct {
 	Meta          StaticMetadata
 	ClientAdapter ClientAdapter
-	Renderer      Renderer
-	Aliases       map[string]AliasTarget
-	Timeout       time.Duration
+	// ChainClient, if set, enables the chain explorer views under /chain.
+	ChainClient ChainClient
+	Renderer    Renderer
+	Aliases     map[string]AliasTarget
+	Timeout     time.Duration
 }
 
 // validate checks if the HTTPHandlerConfig is valid.
@@ -73,6 +75,7 @@ type HTTPHandler struct {
 	Logger   *slog.Logger
 	Static   StaticMetadata
 	Client   ClientAdapter
+	Chain    ChainClient
 	Renderer Renderer
 	Aliases  map[string]AliasTarget
 }
@@ -85,6 +88,7 @@ func NewHTTPHandler(logger *slog.Logger, cfg *HTTPHandlerConfig) (*HTTPHandler,
 
 	return &HTTPHandler{
 		Client:   cfg.ClientAdapter,
+		Chain:    cfg.ChainClient,
 		Static:   cfg.Meta,
 		Renderer: cfg.Renderer,
 		Aliases:  cfg.Aliases,
@@ -169,6 +173,8 @@ func (h *HTTPHandler) Get(w http.ResponseWriter, r *http.Request) {
 		indexData.Mode = components.ViewModePackage
 	case gnourl.IsUser():
 		indexData.Mode = components.ViewModeUser
+	case gnourl.IsChain():
+		indexData.Mode = components.ViewModeExplorer
 	default:
 		indexData.Mode = components.ViewModeRealm
 	}
@@ -267,6 +273,8 @@ func (h *HTTPHandler) prepareIndexBodyView(r *http.Request, indexData *component
 		return h.GetMarkdownView(gnourl, aliasTarget.Value)
 	case gnourl.IsRealm(), gnourl.IsPure(), gnourl.IsUser():
 		return h.GetPackageView(ctx, gnourl, indexData)
+	case gnourl.IsChain():
+		return h.GetChainView(ctx, gnourl)
 	default:
 		h.Logger.Debug("invalid path: path is neither a pure package or a realm")
 		return http.StatusBadRequest, components.StatusErrorComponent("invalid path")
@@ -727,6 +735,8 @@ func GetClientErrorStatusPage(_ *weburl.GnoURL, err error) (int, *components.Vie
 		return http.StatusRequestTimeout, components.StatusErrorComponent(err.Error())
 	case errors.Is(err, ErrClientPackageNotFound):
 		return http.StatusNotFound, components.StatusErrorComponent(err.Error())
+	case errors.Is(err, ErrClientNotFound):
+		return http.StatusNotFound, components.StatusErrorComponent(ErrClientNotFound.Error())
 	case errors.Is(err, ErrClientBadRequest):
 		return http.StatusInternalServerError, components.StatusErrorComponent("bad request")
 	case errors.Is(err, ErrClientResponse):
@@ -112,7 +112,7 @@ func TestHTTPHandler_Get(t *testing.T) {
 			"LicEnse":    `my super license`,
 		},
 		Functions: []*doc.JSONFunc{
-			{Name: "SuperRenderFunction", Params: []*doc.JSONField{{Name: "my_super_arg", Type: "string"}}},
+			{Name: "SuperRenderFunction", Params: []*doc.JSONField{{Name: "my_super_arg", Type: "string"}}, Crossing: true},
 			{Name: "Render", Params: []*doc.JSONField{{Name: "path", Type: "string"}}, Results: []*doc.JSONField{{Name: "", Type: "string"}}},
 		},
 	}
@@ -141,6 +141,7 @@ func TestHTTPHandler_Get(t *testing.T) {
 		{Path: "/r/mock/path$help", Status: http.StatusOK, Contains: []string{
 			"my_super_arg",
 			"SuperRenderFunction",
+			`data-controller="action-tx" data-action-tx-name-value="SuperRenderFunction"`,
 		}},
 
 		// Package not found
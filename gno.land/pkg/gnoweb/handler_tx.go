@@ -0,0 +1,187 @@
+package gnoweb
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+const (
+	// Default fee of the transactions built from the help page, matching
+	// the one of the suggested gnokey commands.
+	defaultTxGasWanted = 1_000_000_000
+	defaultTxGasFee    = "1000000ugnot"
+
+	// maxTxBodySize is the maximum size of a signed transaction sent to the
+	// broadcast endpoint.
+	maxTxBodySize = 1 << 20
+)
+
+// txBuildResponse is the response of the tx build endpoint: the unsigned
+// transaction, and the values needed to sign it with `gnokey sign`.
+type txBuildResponse struct {
+	Tx            json.RawMessage `json:"tx"`
+	ChainID       string          `json:"chain_id"`
+	AccountNumber uint64          `json:"account_number"`
+	Sequence      uint64          `json:"sequence"`
+}
+
+// txBroadcastResponse is the response of the tx broadcast endpoint. URL is
+// the chain explorer page of the transaction, and Error is set if the
+// transaction failed, either on check or on delivery.
+type txBroadcastResponse struct {
+	Hash      string `json:"hash"`
+	URL       string `json:"url"`
+	Height    int64  `json:"height"`
+	GasWanted int64  `json:"gas_wanted"`
+	GasUsed   int64  `json:"gas_used"`
+	Info      string `json:"info,omitempty"`
+	Log       string `json:"log,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// handlerTxBuildJSON returns an http.Handler building the unsigned
+// transaction of a realm function call, as described by the query:
+//
+//	/tx/build?caller=<address>&pkgpath=<realm>&func=<name>&args=<arg>&send=<coins>
+//
+// The transaction is returned as amino JSON, ready for `gnokey sign`.
+func handlerTxBuildJSON(logger *slog.Logger, cli ChainClient, chainID string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeTxError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		query := r.URL.Query()
+		caller, err := crypto.AddressFromBech32(query.Get("caller"))
+		if err != nil {
+			writeTxError(w, http.StatusBadRequest, "invalid caller address")
+			return
+		}
+
+		send, err := std.ParseCoins(query.Get("send"))
+		if err != nil {
+			writeTxError(w, http.StatusBadRequest, "invalid send coins: "+err.Error())
+			return
+		}
+
+		msg := vm.NewMsgCall(caller, send, query.Get("pkgpath"), query.Get("func"), query["args"])
+		if err := msg.ValidateBasic(); err != nil {
+			writeTxError(w, http.StatusBadRequest, "invalid call: "+err.Error())
+			return
+		}
+
+		acc, err := cli.Account(r.Context(), caller)
+		switch {
+		case errors.Is(err, ErrClientNotFound):
+			writeTxError(w, http.StatusNotFound, "caller account not found")
+			return
+		case err != nil:
+			logger.Error("unable to fetch caller account", "caller", caller, "error", err)
+			writeTxError(w, http.StatusBadGateway, "unable to fetch caller account")
+			return
+		}
+
+		tx := std.Tx{
+			Msgs: []std.Msg{msg},
+			Fee:  std.NewFee(defaultTxGasWanted, std.MustParseCoin(defaultTxGasFee)),
+		}
+		txbz, err := amino.MarshalJSON(tx)
+		if err != nil {
+			logger.Error("unable to marshal transaction", "error", err)
+			writeTxError(w, http.StatusInternalServerError, "unable to marshal transaction")
+			return
+		}
+
+		writeTxJSON(w, http.StatusOK, txBuildResponse{
+			Tx:            txbz,
+			ChainID:       chainID,
+			AccountNumber: acc.AccountNumber,
+			Sequence:      acc.Sequence,
+		})
+	})
+}
+
+// handlerTxBroadcastJSON returns an http.Handler broadcasting the signed
+// amino JSON transaction posted as the request body, and returning its
+// result once committed.
+func handlerTxBroadcastJSON(logger *slog.Logger, cli ChainClient) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeTxError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxTxBodySize))
+		if err != nil {
+			writeTxError(w, http.StatusRequestEntityTooLarge, "transaction too large")
+			return
+		}
+
+		var tx std.Tx
+		if err := amino.UnmarshalJSON(body, &tx); err != nil {
+			writeTxError(w, http.StatusBadRequest, "invalid transaction: "+err.Error())
+			return
+		}
+		if len(tx.Signatures) == 0 {
+			writeTxError(w, http.StatusBadRequest, "transaction is not signed")
+			return
+		}
+		if err := tx.ValidateBasic(); err != nil {
+			writeTxError(w, http.StatusBadRequest, "invalid transaction: "+err.Error())
+			return
+		}
+
+		txbz, err := amino.Marshal(tx)
+		if err != nil {
+			writeTxError(w, http.StatusBadRequest, "unable to encode transaction: "+err.Error())
+			return
+		}
+
+		res, err := cli.BroadcastTx(r.Context(), txbz)
+		if err != nil {
+			logger.Error("unable to broadcast transaction", "error", err)
+			writeTxError(w, http.StatusBadGateway, "unable to broadcast transaction")
+			return
+		}
+
+		hash := hex.EncodeToString(res.Hash)
+		ret := txBroadcastResponse{
+			Hash:   hash,
+			URL:    chainTxURL(hash),
+			Height: res.Height,
+		}
+		if res.CheckTx.IsErr() {
+			ret.GasWanted, ret.GasUsed = res.CheckTx.GasWanted, res.CheckTx.GasUsed
+			ret.Log, ret.Error = res.CheckTx.Log, res.CheckTx.Error.Error()
+		} else {
+			ret.GasWanted, ret.GasUsed = res.DeliverTx.GasWanted, res.DeliverTx.GasUsed
+			ret.Info, ret.Log = res.DeliverTx.Info, res.DeliverTx.Log
+			if res.DeliverTx.IsErr() {
+				ret.Error = res.DeliverTx.Error.Error()
+			}
+		}
+
+		writeTxJSON(w, http.StatusOK, ret)
+	})
+}
+
+func writeTxJSON(w http.ResponseWriter, status int, v any) {
+	out, _ := json.MarshalIndent(v, "", "  ")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(out)
+}
+
+func writeTxError(w http.ResponseWriter, status int, msg string) {
+	writeTxJSON(w, status, map[string]string{"error": msg})
+}
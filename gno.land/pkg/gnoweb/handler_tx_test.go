@@ -0,0 +1,205 @@
+package gnoweb
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	bfttypes "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/secp256k1"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// txChainClient implements the ChainClient methods used by the tx handlers.
+type txChainClient struct {
+	ChainClient
+
+	account   *std.BaseAccount
+	deliverTx abci.ResponseDeliverTx
+	broadcast []byte
+}
+
+func (c *txChainClient) Account(ctx context.Context, addr crypto.Address) (*std.BaseAccount, error) {
+	if c.account == nil || c.account.Address != addr {
+		return nil, ErrClientNotFound
+	}
+	return c.account, nil
+}
+
+func (c *txChainClient) BroadcastTx(ctx context.Context, tx []byte) (*ctypes.ResultBroadcastTxCommit, error) {
+	c.broadcast = tx
+	return &ctypes.ResultBroadcastTxCommit{
+		DeliverTx: c.deliverTx,
+		Hash:      bfttypes.Tx(tx).Hash(),
+		Height:    42,
+	}, nil
+}
+
+func TestHandlerTxBuildJSON(t *testing.T) {
+	t.Parallel()
+
+	caller := crypto.AddressFromPreimage([]byte("caller"))
+	cli := &txChainClient{account: &std.BaseAccount{Address: caller, AccountNumber: 7, Sequence: 3}}
+	handler := handlerTxBuildJSON(slog.New(slog.DiscardHandler), cli, "dev")
+
+	build := func(query url.Values) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/tx/build?"+query.Encode(), nil))
+		return rr
+	}
+	valid := func() url.Values {
+		return url.Values{
+			"caller":  {caller.String()},
+			"pkgpath": {"gno.land/r/demo/counter"},
+			"func":    {"Incr"},
+			"args":    {"1", "two"},
+			"send":    {"1000ugnot"},
+		}
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		rr := build(valid())
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		var res txBuildResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+		assert.Equal(t, "dev", res.ChainID)
+		assert.Equal(t, uint64(7), res.AccountNumber)
+		assert.Equal(t, uint64(3), res.Sequence)
+
+		var tx std.Tx
+		require.NoError(t, amino.UnmarshalJSON(res.Tx, &tx))
+		require.Len(t, tx.Msgs, 1)
+		assert.Equal(t, vm.MsgCall{
+			Caller:  caller,
+			Send:    std.MustParseCoins("1000ugnot"),
+			PkgPath: "gno.land/r/demo/counter",
+			Func:    "Incr",
+			Args:    []string{"1", "two"},
+		}, tx.Msgs[0])
+		assert.Equal(t, std.NewFee(defaultTxGasWanted, std.MustParseCoin(defaultTxGasFee)), tx.Fee)
+		assert.Empty(t, tx.Signatures)
+	})
+
+	for _, tc := range []struct {
+		name   string
+		key    string
+		value  string
+		status int
+		errMsg string
+	}{
+		{"invalid caller", "caller", "g1notanaddress", http.StatusBadRequest, "invalid caller address"},
+		{"unknown caller", "caller", crypto.AddressFromPreimage([]byte("other")).String(), http.StatusNotFound, "caller account not found"},
+		{"invalid send", "send", "ugnot", http.StatusBadRequest, "invalid send coins"},
+		{"not a realm", "pkgpath", "gno.land/p/demo/avl", http.StatusBadRequest, "invalid call"},
+		{"missing func", "func", "", http.StatusBadRequest, "invalid call"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			query := valid()
+			query.Set(tc.key, tc.value)
+			rr := build(query)
+			assert.Equal(t, tc.status, rr.Code)
+			assert.Contains(t, rr.Body.String(), tc.errMsg)
+		})
+	}
+
+	t.Run("method not allowed", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/tx/build", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	})
+}
+
+func TestHandlerTxBroadcastJSON(t *testing.T) {
+	t.Parallel()
+
+	key := secp256k1.GenPrivKey()
+	caller := key.PubKey().Address()
+	tx := std.Tx{
+		Msgs: []std.Msg{vm.NewMsgCall(caller, nil, "gno.land/r/demo/counter", "Incr", nil)},
+		Fee:  std.NewFee(defaultTxGasWanted, std.MustParseCoin(defaultTxGasFee)),
+	}
+	unsigned := amino.MustMarshalJSON(tx)
+
+	signBytes, err := tx.GetSignBytes("dev", 7, 3)
+	require.NoError(t, err)
+	sig, err := key.Sign(signBytes)
+	require.NoError(t, err)
+	tx.Signatures = []std.Signature{{PubKey: key.PubKey(), Signature: sig}}
+	signed := amino.MustMarshalJSON(tx)
+
+	broadcast := func(cli *txChainClient, method string, body []byte) *httptest.ResponseRecorder {
+		handler := handlerTxBroadcastJSON(slog.New(slog.DiscardHandler), cli)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(method, "/tx/broadcast", strings.NewReader(string(body))))
+		return rr
+	}
+
+	t.Run("success", func(t *testing.T) {
+		cli := &txChainClient{deliverTx: abci.ResponseDeliverTx{
+			ResponseBase: abci.ResponseBase{Info: "ok info"},
+			GasWanted:    defaultTxGasWanted,
+			GasUsed:      1234,
+		}}
+		rr := broadcast(cli, http.MethodPost, signed)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		assert.Equal(t, amino.MustMarshal(tx), cli.broadcast)
+
+		hash := hex.EncodeToString(bfttypes.Tx(cli.broadcast).Hash())
+		var res txBroadcastResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+		assert.Equal(t, txBroadcastResponse{
+			Hash:      hash,
+			URL:       "/chain/txs/" + hash,
+			Height:    42,
+			GasWanted: defaultTxGasWanted,
+			GasUsed:   1234,
+			Info:      "ok info",
+		}, res)
+	})
+
+	t.Run("delivery error", func(t *testing.T) {
+		cli := &txChainClient{deliverTx: abci.ResponseDeliverTx{
+			ResponseBase: abci.ResponseBase{Error: abci.StringError("boom"), Log: "failed"},
+		}}
+		rr := broadcast(cli, http.MethodPost, signed)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var res txBroadcastResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+		assert.Equal(t, "boom", res.Error)
+		assert.Equal(t, "failed", res.Log)
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		cli := &txChainClient{}
+		rr := broadcast(cli, http.MethodPost, unsigned)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), "transaction is not signed")
+		assert.Nil(t, cli.broadcast)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		rr := broadcast(&txChainClient{}, http.MethodPost, []byte("{not json"))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), "invalid transaction")
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		rr := broadcast(&txChainClient{}, http.MethodGet, nil)
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	})
+}
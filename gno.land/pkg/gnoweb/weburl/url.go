@@ -155,6 +155,11 @@ func (gnoURL GnoURL) IsUser() bool {
 	return strings.HasPrefix(gnoURL.Path, "/u/")
 }
 
+// IsChain checks if the URL path represents a chain explorer path.
+func (gnoURL GnoURL) IsChain() bool {
+	return gnoURL.Path == "/chain" || strings.HasPrefix(gnoURL.Path, "/chain/")
+}
+
 // IsFile checks if the URL path represents a file.
 func (gnoURL GnoURL) IsFile() bool {
 	return gnoURL.File != ""
@@ -0,0 +1,104 @@
+package vm
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+)
+
+// ABIType is the full description of the type of a param or result of a
+// FunctionSignature, for the clients encoding the arguments of a call. The
+// arguments of the primitive types and of the byte arrays and slices are
+// passed as in convertArgToGno, the other ones as JSON (see
+// convertJSONArgToGno).
+//
+// Kind is the name of a primitive type, or one of "array", "slice",
+// "struct", "map", "pointer", "interface" and "func". Name is the qualified
+// name of a declared type. A declared type already being described, as in
+// recursive types, only has its Kind and Name.
+type ABIType struct {
+	Kind   string     `json:"Kind"`
+	Name   string     `json:"Name,omitempty"`
+	Len    int        `json:"Len,omitempty"`    // arrays
+	Key    *ABIType   `json:"Key,omitempty"`    // maps
+	Elem   *ABIType   `json:"Elem,omitempty"`   // arrays, slices, maps and pointers
+	Fields []ABIField `json:"Fields,omitempty"` // structs, exported only
+}
+
+// ABIField is an exported field of a struct ABIType.
+type ABIField struct {
+	Name string
+	Type *ABIType
+}
+
+// NewABIType returns the ABIType of t.
+func NewABIType(t gno.Type) *ABIType {
+	return newABIType(t, make(map[*gno.DeclaredType]struct{}))
+}
+
+func newABIType(t gno.Type, seen map[*gno.DeclaredType]struct{}) *ABIType {
+	abi := &ABIType{}
+	if dt, ok := t.(*gno.DeclaredType); ok {
+		abi.Name = dt.String()
+		if _, ok := seen[dt]; ok {
+			abi.Kind = abiKind(dt.Base)
+			return abi
+		}
+		seen[dt] = struct{}{}
+		defer delete(seen, dt)
+	}
+	bt := gno.BaseOf(t)
+	abi.Kind = abiKind(bt)
+	switch bt := bt.(type) {
+	case *gno.ArrayType:
+		abi.Len = bt.Len
+		abi.Elem = newABIType(bt.Elt, seen)
+	case *gno.SliceType:
+		abi.Elem = newABIType(bt.Elt, seen)
+	case *gno.MapType:
+		abi.Key = newABIType(bt.Key, seen)
+		abi.Elem = newABIType(bt.Value, seen)
+	case *gno.PointerType:
+		abi.Elem = newABIType(bt.Elt, seen)
+	case *gno.StructType:
+		for _, f := range bt.Fields {
+			if !isExportedName(string(f.Name)) {
+				continue
+			}
+			abi.Fields = append(abi.Fields, ABIField{
+				Name: string(f.Name),
+				Type: newABIType(f.Type, seen),
+			})
+		}
+	}
+	return abi
+}
+
+func abiKind(bt gno.Type) string {
+	switch bt := bt.(type) {
+	case gno.PrimitiveType:
+		return bt.String()
+	case *gno.ArrayType:
+		return "array"
+	case *gno.SliceType:
+		return "slice"
+	case *gno.StructType:
+		return "struct"
+	case *gno.MapType:
+		return "map"
+	case *gno.PointerType:
+		return "pointer"
+	case *gno.InterfaceType:
+		return "interface"
+	case *gno.FuncType:
+		return "func"
+	default:
+		return bt.String()
+	}
+}
+
+func isExportedName(name string) bool {
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r)
+}
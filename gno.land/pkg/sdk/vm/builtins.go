@@ -5,8 +5,10 @@ import (
 	"strings"
 
 	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/overflow"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
 	"github.com/gnolang/gno/tm2/pkg/std"
+	stypes "github.com/gnolang/gno/tm2/pkg/store/types"
 )
 
 // ----------------------------------------
@@ -142,6 +144,67 @@ func (prm *SDKParams) UpdateStrings(key string, vals []string, add bool) {
 	prm.SetStrings(key, updatedList)
 }
 
+// Getters return the value stored under key and whether it was found. Reads
+// are charged to the context's gas meter like any other store read.
+
+func (prm *SDKParams) GetString(key string) (value string, ok bool) {
+	ok = prm.getWithCheck(key, func() { prm.pmk.GetString(prm.ctx, key, &value) })
+	prm.consumeReadGas(len(value))
+	return
+}
+
+func (prm *SDKParams) GetBool(key string) (value bool, ok bool) {
+	ok = prm.getWithCheck(key, func() { prm.pmk.GetBool(prm.ctx, key, &value) })
+	prm.consumeReadGas(1)
+	return
+}
+
+func (prm *SDKParams) GetInt64(key string) (value int64, ok bool) {
+	ok = prm.getWithCheck(key, func() { prm.pmk.GetInt64(prm.ctx, key, &value) })
+	prm.consumeReadGas(8)
+	return
+}
+
+func (prm *SDKParams) GetUint64(key string) (value uint64, ok bool) {
+	ok = prm.getWithCheck(key, func() { prm.pmk.GetUint64(prm.ctx, key, &value) })
+	prm.consumeReadGas(8)
+	return
+}
+
+func (prm *SDKParams) GetBytes(key string) (value []byte, ok bool) {
+	ok = prm.getWithCheck(key, func() { prm.pmk.GetBytes(prm.ctx, key, &value) })
+	prm.consumeReadGas(len(value))
+	return
+}
+
+func (prm *SDKParams) GetStrings(key string) (value []string, ok bool) {
+	ok = prm.getWithCheck(key, func() { prm.pmk.GetStrings(prm.ctx, key, &value) })
+	size := 0
+	for _, s := range value {
+		size += len(s)
+	}
+	prm.consumeReadGas(size)
+	return
+}
+
+func (prm *SDKParams) getWithCheck(key string, get func()) bool {
+	prm.mustHaveModuleKeeper(key)
+	if !prm.pmk.Has(prm.ctx, key) {
+		return false
+	}
+	get()
+	return true
+}
+
+// consumeReadGas charges a param read the same amount as a read from a
+// gas-wrapped store returning size bytes.
+func (prm *SDKParams) consumeReadGas(size int) {
+	gcfg := stypes.DefaultGasConfig()
+	gm := prm.ctx.GasMeter()
+	gm.ConsumeGas(gcfg.ReadCostFlat, stypes.GasReadCostFlatDesc)
+	gm.ConsumeGas(overflow.Mulp(gcfg.ReadCostPerByte, stypes.Gas(size)), stypes.GasReadPerByteDesc)
+}
+
 func (prm *SDKParams) setWithCheck(key string, set func()) {
 	prm.mustHaveModuleKeeper(key)
 	set()
@@ -31,14 +31,19 @@ type testEnv struct {
 }
 
 func setupTestEnv() testEnv {
-	return _setupTestEnv(true)
+	return _setupTestEnv(true, false)
 }
 
 func setupTestEnvCold() testEnv {
-	return _setupTestEnv(false)
+	return _setupTestEnv(false, false)
 }
 
-func _setupTestEnv(cacheStdlibs bool) testEnv {
+// setupTestEnvHistory returns a testEnv whose base store keeps its history.
+func setupTestEnvHistory() testEnv {
+	return _setupTestEnv(true, true)
+}
+
+func _setupTestEnv(cacheStdlibs, keepHistory bool) testEnv {
 	db := memdb.NewMemDB()
 
 	baseCapKey := store.NewStoreKey("baseCapKey")
@@ -46,7 +51,12 @@ func _setupTestEnv(cacheStdlibs bool) testEnv {
 
 	// Mount db store and iavlstore
 	ms := store.NewCommitMultiStore(db)
-	ms.MountStoreWithDB(baseCapKey, dbadapter.StoreConstructor, db)
+	if keepHistory {
+		historyDB := memdb.NewMemDB()
+		ms.MountStoreWithDB(baseCapKey, dbadapter.HistoryStoreConstructor(historyDB, 0, log.NewNoopLogger()), db)
+	} else {
+		ms.MountStoreWithDB(baseCapKey, dbadapter.StoreConstructor, db)
+	}
 	ms.MountStoreWithDB(iavlCapKey, iavl.StoreConstructor, db)
 	ms.LoadLatestVersion()
 
@@ -56,6 +66,7 @@ func _setupTestEnv(cacheStdlibs bool) testEnv {
 	acck := authm.NewAccountKeeper(iavlCapKey, prmk.ForModule(authm.ModuleName), std.ProtoBaseAccount)
 	bankk := bankm.NewBankKeeper(acck, prmk.ForModule(bankm.ModuleName))
 	vmk := NewVMKeeper(baseCapKey, iavlCapKey, acck, bankk, prmk)
+	vmk.KeepHistory = keepHistory
 
 	prmk.Register(authm.ModuleName, acck)
 	prmk.Register(bankm.ModuleName, bankk)
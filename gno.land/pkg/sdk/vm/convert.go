@@ -18,8 +18,10 @@ func assertNoPlusPrefix(s string) {
 }
 
 // These convert string representations of public-facing arguments to GNO types.
-// The limited set of input types available should map 1:1 to types supported
-// in FunctionSignature{}.
+// The input types available should map 1:1 to types supported in
+// FunctionSignature{}: primitives and byte arrays and slices are converted
+// here, and the other arrays and slices, structs, maps and pointers are
+// decoded from JSON by convertJSONArgToGno.
 // String representation of arg must be deterministic.
 // NOTE: very important that there is no malleability.
 func convertArgToGno(arg string, argT gno.Type) (tv gno.TypedValue) {
@@ -172,7 +174,7 @@ func convertArgToGno(arg string, argT gno.Type) (tv gno.TypedValue) {
 			}
 			return
 		} else {
-			panic("unexpected array type in contract arg")
+			return convertJSONArgToGno(arg, argT)
 		}
 	case *gno.SliceType:
 		if bt.Elt == gno.Uint8Type {
@@ -192,8 +194,10 @@ func convertArgToGno(arg string, argT gno.Type) (tv gno.TypedValue) {
 			}
 			return
 		} else {
-			panic("unexpected slice type in contract arg")
+			return convertJSONArgToGno(arg, argT)
 		}
+	case *gno.StructType, *gno.MapType, *gno.PointerType:
+		return convertJSONArgToGno(arg, argT)
 	default:
 		panic(fmt.Sprintf("unexpected type in contract arg: %v", argT))
 	}
@@ -0,0 +1,306 @@
+package vm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+)
+
+// convertJSONArgToGno converts the JSON representation of an argument of a
+// composite type (struct, array, slice, map or pointer) to a Gno value:
+//   - bools and strings are JSON booleans and strings.
+//   - numbers are JSON numbers or strings, in the format of the primitive
+//     arguments (see convertArgToGno).
+//   - arrays and slices are JSON arrays, except arrays and slices of bytes
+//     which are base64 strings. A null slice is nil.
+//   - structs are JSON objects of the exported fields, by name. The omitted
+//     fields have their zero value.
+//   - maps are JSON objects, whose keys are the primitive arguments of the
+//     key type. A null map is nil.
+//   - pointers are the JSON of the pointed value, or null for a nil pointer.
+//
+// Objects are decoded in order, and their keys must be unique, so that the
+// arguments have a single representation, and the maps a deterministic order.
+func convertJSONArgToGno(arg string, argT gno.Type) gno.TypedValue {
+	dec := json.NewDecoder(strings.NewReader(arg))
+	dec.UseNumber()
+	node, err := decodeJSONNode(dec)
+	if err == nil {
+		if _, err = dec.Token(); err == io.EOF {
+			err = nil
+		} else if err == nil {
+			err = fmt.Errorf("unexpected data after the value")
+		}
+	}
+	if err != nil {
+		panic(fmt.Sprintf(
+			"error parsing JSON of %s argument: %v",
+			argT, err))
+	}
+	return node.toGno(argT, "")
+}
+
+// jsonNode is a decoded JSON value, which keeps the order of the keys of the
+// objects.
+type jsonNode struct {
+	value any         // bool, string, json.Number, or nil for null
+	elems []*jsonNode // array
+	keys  []string    // object
+	isArr bool
+	isObj bool
+}
+
+func decodeJSONNode(dec *json.Decoder) (*jsonNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok := tok.(type) {
+	case json.Delim:
+		switch tok {
+		case '[':
+			node := &jsonNode{isArr: true}
+			for dec.More() {
+				elem, err := decodeJSONNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				node.elems = append(node.elems, elem)
+			}
+			_, err := dec.Token() // ']'
+			return node, err
+		case '{':
+			node := &jsonNode{isObj: true}
+			seen := make(map[string]struct{})
+			for dec.More() {
+				tok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key := tok.(string)
+				if _, ok := seen[key]; ok {
+					return nil, fmt.Errorf("duplicate key %q", key)
+				}
+				seen[key] = struct{}{}
+				elem, err := decodeJSONNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				node.keys = append(node.keys, key)
+				node.elems = append(node.elems, elem)
+			}
+			_, err := dec.Token() // '}'
+			return node, err
+		default:
+			return nil, fmt.Errorf("unexpected delimiter %v", tok)
+		}
+	default:
+		return &jsonNode{value: tok}, nil
+	}
+}
+
+func (n *jsonNode) isNull() bool {
+	return !n.isArr && !n.isObj && n.value == nil
+}
+
+func (n *jsonNode) describe() string {
+	switch {
+	case n.isArr:
+		return "array"
+	case n.isObj:
+		return "object"
+	}
+	switch n.value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	default:
+		panic("should not happen")
+	}
+}
+
+// Panics with the path of the value in the argument, and what was expected.
+func (n *jsonNode) mismatch(path string, t gno.Type, expected string) {
+	if path == "" {
+		path = "value"
+	}
+	panic(fmt.Sprintf(
+		"error converting JSON argument: %s of type %s: expected %s, got %s",
+		path, t, expected, n.describe()))
+}
+
+// Returns the string of a primitive value, or of a number.
+func (n *jsonNode) primitiveString(path string, t gno.Type) string {
+	switch v := n.value.(type) {
+	case string:
+		if !n.isArr && !n.isObj {
+			return v
+		}
+	case json.Number:
+		return v.String()
+	}
+	n.mismatch(path, t, "number or string")
+	panic("unreachable")
+}
+
+func (n *jsonNode) toGno(t gno.Type, path string) (tv gno.TypedValue) {
+	switch bt := gno.BaseOf(t).(type) {
+	case gno.PrimitiveType:
+		switch bt {
+		case gno.BoolType:
+			b, ok := n.value.(bool)
+			if !ok {
+				n.mismatch(path, t, "boolean")
+			}
+			tv.T = t
+			tv.SetBool(b)
+			return tv
+		case gno.StringType:
+			s, ok := n.value.(string)
+			if !ok || n.isArr || n.isObj {
+				n.mismatch(path, t, "string")
+			}
+			return convertArgToGno(s, t)
+		default:
+			return convertArgToGno(n.primitiveString(path, t), t)
+		}
+	case *gno.ArrayType:
+		if bt.Elt == gno.Uint8Type {
+			s, ok := n.value.(string)
+			if !ok || n.isArr || n.isObj {
+				n.mismatch(path, t, "base64 string")
+			}
+			return convertArgToGno(s, t)
+		}
+		if !n.isArr {
+			n.mismatch(path, t, "array")
+		}
+		if len(n.elems) != bt.Len {
+			panic(fmt.Sprintf(
+				"error converting JSON argument: %s of type %s: expected %d elements, got %d",
+				pathOrValue(path), t, bt.Len, len(n.elems)))
+		}
+		list := make([]gno.TypedValue, len(n.elems))
+		for i, elem := range n.elems {
+			list[i] = elem.toGno(bt.Elt, fmt.Sprintf("%s[%d]", path, i))
+		}
+		tv.T = t
+		tv.V = &gno.ArrayValue{List: list}
+		return tv
+	case *gno.SliceType:
+		if n.isNull() {
+			return gno.TypedValue{T: t}
+		}
+		if bt.Elt == gno.Uint8Type {
+			s, ok := n.value.(string)
+			if !ok || n.isArr || n.isObj {
+				n.mismatch(path, t, "base64 string")
+			}
+			return convertArgToGno(s, t)
+		}
+		if !n.isArr {
+			n.mismatch(path, t, "array")
+		}
+		list := make([]gno.TypedValue, len(n.elems))
+		for i, elem := range n.elems {
+			list[i] = elem.toGno(bt.Elt, fmt.Sprintf("%s[%d]", path, i))
+		}
+		tv.T = t
+		tv.V = &gno.SliceValue{
+			Base:   &gno.ArrayValue{List: list},
+			Offset: 0,
+			Length: len(list),
+			Maxcap: len(list),
+		}
+		return tv
+	case *gno.StructType:
+		if !n.isObj {
+			n.mismatch(path, t, "object")
+		}
+		fields := make([]gno.TypedValue, len(bt.Fields))
+		set := make([]bool, len(bt.Fields))
+		for i, key := range n.keys {
+			idx := -1
+			for j, f := range bt.Fields {
+				if string(f.Name) == key && isExportedName(key) {
+					idx = j
+					break
+				}
+			}
+			if idx < 0 {
+				panic(fmt.Sprintf(
+					"error converting JSON argument: %s of type %s: unknown or unexported field %q",
+					pathOrValue(path), t, key))
+			}
+			fields[idx] = n.elems[i].toGno(bt.Fields[idx].Type, path+"."+key)
+			set[idx] = true
+		}
+		for i, f := range bt.Fields {
+			if !set[i] {
+				fields[i] = gno.DefaultTypedValue(nil, f.Type)
+			}
+		}
+		tv.T = t
+		tv.V = &gno.StructValue{Fields: fields}
+		return tv
+	case *gno.MapType:
+		if n.isNull() {
+			return gno.TypedValue{T: t}
+		}
+		if !n.isObj {
+			n.mismatch(path, t, "object")
+		}
+		if _, ok := gno.BaseOf(bt.Key).(gno.PrimitiveType); !ok {
+			panic(fmt.Sprintf(
+				"error converting JSON argument: %s of type %s: unsupported map key type %s",
+				pathOrValue(path), t, bt.Key))
+		}
+		mv := &gno.MapValue{}
+		mv.MakeMap(len(n.keys))
+		for i, key := range n.keys {
+			ktv := convertArgToGno(key, bt.Key)
+			vtv := n.elems[i].toGno(bt.Value, fmt.Sprintf("%s[%q]", path, key))
+			ptr := mv.GetPointerForKey(nil, nil, ktv)
+			if mv.GetLength() != i+1 {
+				panic(fmt.Sprintf(
+					"error converting JSON argument: %s of type %s: duplicate key %q",
+					pathOrValue(path), t, key))
+			}
+			*ptr.TV = vtv
+		}
+		tv.T = t
+		tv.V = mv
+		return tv
+	case *gno.PointerType:
+		if n.isNull() {
+			return gno.TypedValue{T: t}
+		}
+		hi := &gno.HeapItemValue{Value: n.toGno(bt.Elt, path)}
+		tv.T = t
+		tv.V = gno.PointerValue{
+			TV:    &hi.Value,
+			Base:  hi,
+			Index: 0,
+		}
+		return tv
+	default:
+		panic(fmt.Sprintf(
+			"error converting JSON argument: %s of type %s: unsupported type",
+			pathOrValue(path), t))
+	}
+}
+
+func pathOrValue(path string) string {
+	if path == "" {
+		return "value"
+	}
+	return path
+}
@@ -83,3 +83,44 @@ func TestConvertByteArrayLengthValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertJSONArgErrors(t *testing.T) {
+	t.Parallel()
+
+	itemT := &gnolang.StructType{
+		PkgPath: "gno.land/r/test",
+		Fields: []gnolang.FieldType{
+			{Name: "Name", Type: gnolang.StringType},
+			{Name: "Tags", Type: &gnolang.SliceType{Elt: gnolang.StringType}},
+			{Name: "secret", Type: gnolang.IntType},
+		},
+	}
+	tests := []struct {
+		arg         string
+		argT        gnolang.Type
+		expectedErr string
+	}{
+		{`[1,2`, &gnolang.SliceType{Elt: gnolang.IntType}, `error parsing JSON of []int argument: unexpected end of JSON input`},
+		{`[1] [2]`, &gnolang.SliceType{Elt: gnolang.IntType}, `error parsing JSON of []int argument: unexpected data after the value`},
+		{`{"Name":"a","Name":"b"}`, itemT, `error parsing JSON of struct{Name string; Tags []string; secret int} argument: duplicate key "Name"`},
+		{`"a"`, &gnolang.SliceType{Elt: gnolang.IntType}, `error converting JSON argument: value of type []int: expected array, got string`},
+		{`[1,"x"]`, &gnolang.SliceType{Elt: gnolang.IntType}, `error parsing int "x": strconv.ParseInt: parsing "x": invalid syntax`},
+		{`[1,true]`, &gnolang.SliceType{Elt: gnolang.IntType}, `error converting JSON argument: [1] of type int: expected number or string, got boolean`},
+		{`[1]`, &gnolang.ArrayType{Len: 2, Elt: gnolang.IntType}, `error converting JSON argument: value of type [2]int: expected 2 elements, got 1`},
+		{`{"Name":1}`, itemT, `error converting JSON argument: .Name of type string: expected string, got number`},
+		{`{"Tags":[null]}`, itemT, `error converting JSON argument: .Tags[0] of type string: expected string, got null`},
+		{`{"secret":1}`, itemT, `error converting JSON argument: value of type struct{Name string; Tags []string; secret int}: unknown or unexported field "secret"`},
+		{`{"1":true,"01":false}`, &gnolang.MapType{Key: gnolang.IntType, Value: gnolang.BoolType}, `error converting JSON argument: value of type map[int]bool: duplicate key "01"`},
+		{`[true]`, &gnolang.PointerType{Elt: gnolang.BoolType}, `error converting JSON argument: value of type bool: expected boolean, got array`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			t.Parallel()
+
+			assert.PanicsWithValue(t, tt.expectedErr, func() {
+				convertArgToGno(tt.arg, tt.argT)
+			})
+		})
+	}
+}
@@ -12,6 +12,11 @@ import (
 type GenesisState struct {
 	Params      Params         `json:"params" yaml:"params"`
 	RealmParams []params.Param `json:"realm_params" yaml:"realm_params"`
+	// Whether the hashes of the realm package values are saved in the iavl
+	// store, so that the realm objects can be proven with vm/qobject. It is
+	// opt-in, as it changes the app hashes: it must not be set in the genesis
+	// of the chains started without it.
+	RealmObjectProofs bool `json:"realm_object_proofs" yaml:"realm_object_proofs"`
 }
 
 // NewGenesisState - Create a new genesis state
@@ -56,6 +61,13 @@ func (vm *VMKeeper) InitGenesis(ctx sdk.Context, gs GenesisState) {
 	for _, rp := range gs.RealmParams {
 		vm.prmk.SetAny(ctx, "vm:"+rp.Key, rp.Value)
 	}
+	if gs.RealmObjectProofs {
+		// Save the hashes of the realms already deployed, if any,
+		// and of all the realms from then on.
+		gnostore := vm.newGnoTransactionStore(ctx)
+		gnostore.SaveRealmRoots()
+		gnostore.Write()
+	}
 }
 
 // ExportGenesis returns a GenesisState for a given context and keeper
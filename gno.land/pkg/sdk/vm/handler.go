@@ -1,14 +1,21 @@
 package vm
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
 
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/amino"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
+	"github.com/gnolang/gno/tm2/pkg/errors"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
 	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store/rootmulti"
 	"github.com/gnolang/gno/tm2/pkg/version"
 )
 
@@ -27,6 +34,8 @@ func (vh vmHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
 	switch msg := msg.(type) {
 	case MsgAddPackage:
 		return vh.handleMsgAddPackage(ctx, msg)
+	case MsgUpgradePackage:
+		return vh.handleMsgUpgradePackage(ctx, msg)
 	case MsgCall:
 		return vh.handleMsgCall(ctx, msg)
 	case MsgRun:
@@ -46,13 +55,29 @@ func (vh vmHandler) handleMsgAddPackage(ctx sdk.Context, msg MsgAddPackage) sdk.
 	return sdk.Result{}
 }
 
+// Handle MsgUpgradePackage.
+func (vh vmHandler) handleMsgUpgradePackage(ctx sdk.Context, msg MsgUpgradePackage) sdk.Result {
+	err := vh.vm.UpgradePackage(ctx, msg)
+	if err != nil {
+		return abciResult(err)
+	}
+	return sdk.Result{}
+}
+
 // Handle MsgCall.
+// The results are also set in res.Info as a JSON array of gno.JSONValue, on a
+// single line: the Info of a transaction has the line of each message.
 func (vh vmHandler) handleMsgCall(ctx sdk.Context, msg MsgCall) (res sdk.Result) {
-	resstr, err := vh.vm.Call(ctx, msg)
+	resstr, jres, err := vh.vm.CallJSON(ctx, msg)
 	if err != nil {
 		return abciResult(err)
 	}
+	bz, err := json.Marshal(jres)
+	if err != nil {
+		return abciResult(std.ErrInternal(err.Error()))
+	}
 	res.Data = []byte(resstr)
+	res.Info = string(bz)
 	return
 }
 
@@ -71,13 +96,15 @@ func (vh vmHandler) handleMsgRun(ctx sdk.Context, msg MsgRun) (res sdk.Result) {
 
 // query paths
 const (
-	QueryRender  = "qrender"
-	QueryFuncs   = "qfuncs"
-	QueryEval    = "qeval"
-	QueryFile    = "qfile"
-	QueryDoc     = "qdoc"
-	QueryPaths   = "qpaths"
-	QueryStorage = "qstorage"
+	QueryRender   = "qrender"
+	QueryFuncs    = "qfuncs"
+	QueryEval     = "qeval"
+	QueryEvalJSON = "qeval_json"
+	QueryFile     = "qfile"
+	QueryDoc      = "qdoc"
+	QueryPaths    = "qpaths"
+	QueryStorage  = "qstorage"
+	QueryObject   = "qobject"
 )
 
 func (vh vmHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
@@ -86,6 +113,18 @@ func (vh vmHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.Resp
 		path = path[:i]
 	}
 
+	// The multistore of ctx is loaded at req.Height, but the base store
+	// only has the latest state unless its history is kept.
+	if req.Height > 0 && req.Height < ctx.BlockHeight() {
+		if !vh.vm.KeepHistory {
+			return sdk.ABCIResponseQueryFromError(
+				std.ErrInternal(fmt.Sprintf(
+					"cannot query %s at past height %d: the node does not keep the history of the vm state",
+					req.Path, req.Height)))
+		}
+		ctx = withHistoric(ctx)
+	}
+
 	switch path {
 	case QueryRender:
 		res = vh.queryRender(ctx, req)
@@ -93,6 +132,8 @@ func (vh vmHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.Resp
 		res = vh.queryFuncs(ctx, req)
 	case QueryEval:
 		res = vh.queryEval(ctx, req)
+	case QueryEvalJSON:
+		res = vh.queryEvalJSON(ctx, req)
 	case QueryFile:
 		res = vh.queryFile(ctx, req)
 	case QueryDoc:
@@ -101,6 +142,8 @@ func (vh vmHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.Resp
 		res = vh.queryPaths(ctx, req)
 	case QueryStorage:
 		res = vh.queryStorage(ctx, req)
+	case QueryObject:
+		res = vh.queryObject(ctx, req)
 	default:
 		return sdk.ABCIResponseQueryFromError(
 			std.ErrUnknownRequest(fmt.Sprintf(
@@ -194,6 +237,24 @@ func (vh vmHandler) queryEval(ctx sdk.Context, req abci.RequestQuery) (res abci.
 	return
 }
 
+// queryEvalJSON evaluates any expression in readonly mode, like queryEval,
+// and returns the results as a JSON array of gno.JSONValue.
+func (vh vmHandler) queryEvalJSON(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	pkgPath, expr := parseQueryEvalData(string(req.Data))
+	result, err := vh.vm.QueryEvalJSON(ctx, pkgPath, expr)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(err)
+		return
+	}
+	bz, err := json.Marshal(result)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(std.ErrInternal(err.Error()))
+		return
+	}
+	res.Data = bz
+	return
+}
+
 // parseQueryEval parses the input string of vm/qeval. It takes the first dot
 // after the first slash (if any) to separe the pkgPath and the expr.
 // For instance, in gno.land/r/realm.MyFunction(), gno.land/r/realm is the
@@ -255,6 +316,73 @@ func (vh vmHandler) queryStorage(ctx sdk.Context, req abci.RequestQuery) (res ab
 	return
 }
 
+// queryObject returns a persisted object, with the chain of its owners up to
+// the object whose hash is in the iavl store, as the JSON of a
+// gno.ObjectProof. The iavl key and value of this hash are set in res.Key and
+// res.Value, and, if requested, proven against the app hash in res.Proof.
+// See VerifyObjectProof.
+func (vh vmHandler) queryObject(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	proof, err := vh.vm.QueryObject(ctx, string(req.Data))
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(err)
+		return
+	}
+	_, key, value, err := proof.Verify()
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(std.ErrInternal(err.Error()))
+		return
+	}
+	if req.Prove {
+		sres := sdk.QueryStore(ctx, abci.RequestQuery{
+			Path:   "/" + vh.vm.iavlKey.Name() + "/key",
+			Data:   key,
+			Height: req.Height,
+			Prove:  true,
+		})
+		if sres.Error != nil {
+			return sres
+		}
+		if !bytes.Equal(sres.Value, value) {
+			res = sdk.ABCIResponseQueryFromError(std.ErrInternal(fmt.Sprintf(
+				"hash of object %s in iavl does not match the object", key)))
+			return
+		}
+		res.Proof = sres.Proof
+	}
+	res.Data = amino.MustMarshalJSON(proof)
+	res.Key = key
+	res.Value = value
+	res.Height = req.Height
+	return
+}
+
+// VerifyObjectProof verifies the result of a vm/qobject query requesting a
+// proof, against the app hash of the state at res.Height (i.e. the app hash
+// in the header of the next block). storeName is the name of the iavl store
+// of the vm keeper ("main" on gno.land). It returns the proven object, with
+// its children as RefValues.
+func VerifyObjectProof(res abci.ResponseQuery, appHash []byte, storeName string) (gno.Object, error) {
+	var proof gno.ObjectProof
+	if err := amino.UnmarshalJSON(res.Data, &proof); err != nil {
+		return nil, errors.Wrap(err, "decoding object proof")
+	}
+	oo, key, value, err := proof.Verify()
+	if err != nil {
+		return nil, err
+	}
+	if res.Proof == nil {
+		return nil, errors.New("missing proof of object hash")
+	}
+	keypath := merkle.KeyPath{}.
+		AppendKey([]byte(storeName), merkle.KeyEncodingURL).
+		AppendKey(key, merkle.KeyEncodingURL)
+	prt := rootmulti.DefaultProofRuntime()
+	if err := prt.VerifyValue(res.Proof, appHash, keypath.String(), value); err != nil {
+		return nil, errors.Wrap(err, "verifying object hash")
+	}
+	return oo, nil
+}
+
 // ----------------------------------------
 // misc
 
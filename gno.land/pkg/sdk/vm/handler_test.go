@@ -1,15 +1,21 @@
 package vm
 
 import (
+	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/gnolang/gno/gnovm/pkg/doc"
 	"github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/amino"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_parseQueryEvalData(t *testing.T) {
@@ -193,7 +199,7 @@ func TestVmHandlerQuery_Funcs(t *testing.T) {
 		expectedErrorMatch string
 	}{
 		// valid queries
-		{input: []byte(`gno.land/r/hello`), expectedResult: `[{"FuncName":"Panic","Params":null,"Results":null},{"FuncName":"Echo","Params":[{"Name":"msg","Type":"string","Value":""}],"Results":[{"Name":".res.0","Type":"string","Value":""}]},{"FuncName":"GetCounter","Params":null,"Results":[{"Name":".res.0","Type":"int","Value":""}]},{"FuncName":"Inc","Params":null,"Results":[{"Name":".res.0","Type":"int","Value":""}]}]`},
+		{input: []byte(`gno.land/r/hello`), expectedResult: `[{"FuncName":"Panic","Params":null,"Results":null},{"FuncName":"Echo","Params":[{"Name":"msg","Type":"string","Value":"","ABI":{"Kind":"string"}}],"Results":[{"Name":".res.0","Type":"string","Value":"","ABI":{"Kind":"string"}}]},{"FuncName":"GetCounter","Params":null,"Results":[{"Name":".res.0","Type":"int","Value":"","ABI":{"Kind":"int"}}]},{"FuncName":"Inc","Params":null,"Results":[{"Name":".res.0","Type":"int","Value":"","ABI":{"Kind":"int"}}]}]`},
 		{input: []byte(`gno.land/r/doesnotexist`), expectedErrorMatch: `invalid package path`},
 		{input: []byte(`std`), expectedErrorMatch: `invalid package path`},
 		{input: []byte(`strings`), expectedErrorMatch: `invalid package path`},
@@ -474,3 +480,435 @@ func Hello(msg string) (res string) { res = prefix+" "+msg; return }
 		})
 	}
 }
+
+func TestVmHandlerQuery_History(t *testing.T) {
+	env := setupTestEnvHistory()
+	ms := env.ctx.MultiStore().(store.CommitMultiStore)
+	ms.SetStoreOptions(store.StoreOptions{PruningOptions: store.PruneNothing})
+	ms.Commit() // version 1: stdlibs.
+
+	// Give "addr1" some gnots.
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// version 2: create test package.
+	const pkgPath = "gno.land/r/hello"
+	files := []*std.MemFile{
+		{Name: "gnomod.toml", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "hello.gno", Body: `
+package hello
+
+type item struct{ n int }
+
+var items []*item
+
+func Inc(cur realm) int {
+	items = append(items, &item{n: len(items) + 1})
+	return len(items)
+}
+
+func Render(_ string) string {
+	s := "items:"
+	for _, it := range items {
+		s += " " + string(rune('0'+it.n))
+	}
+	return s
+}
+`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+	ms.Commit()
+
+	// versions 3 and 4: add items.
+	for range 2 {
+		ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+		_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Inc", nil))
+		require.NoError(t, err)
+		env.vmk.CommitGnoTransactionStore(ctx)
+		ms.Commit()
+	}
+
+	expected := map[int64]string{
+		2: "items:",
+		3: "items: 1",
+		4: "items: 1 2",
+	}
+	for height, render := range expected {
+		past, err := ms.MultiImmutableCacheWrapWithVersion(height)
+		require.NoError(t, err)
+		qctx := env.ctx.WithMultiStore(past)
+
+		res := env.vmh.Query(qctx, abci.RequestQuery{
+			Path:   "vm/qrender",
+			Data:   []byte(pkgPath + ":"),
+			Height: height,
+		})
+		require.True(t, res.IsOK(), "height %d: %v", height, res.Error)
+		assert.Equal(t, render, string(res.Data), "height %d", height)
+
+		res = env.vmh.Query(qctx, abci.RequestQuery{
+			Path:   "vm/qeval",
+			Data:   []byte(pkgPath + ".len(items)"),
+			Height: height,
+		})
+		require.True(t, res.IsOK(), "height %d: %v", height, res.Error)
+		assert.Equal(t, fmt.Sprintf("(%d int)", height-2), string(res.Data), "height %d", height)
+	}
+
+	// The package does not exist yet at version 1.
+	past, err := ms.MultiImmutableCacheWrapWithVersion(1)
+	require.NoError(t, err)
+	res := env.vmh.Query(env.ctx.WithMultiStore(past), abci.RequestQuery{
+		Path:   "vm/qrender",
+		Data:   []byte(pkgPath + ":"),
+		Height: 1,
+	})
+	assert.False(t, res.IsOK())
+}
+
+func TestVmHandlerQuery_HistoryUpgrade(t *testing.T) {
+	env := setupTestEnvHistory()
+	ms := env.ctx.MultiStore().(store.CommitMultiStore)
+	ms.SetStoreOptions(store.StoreOptions{PruningOptions: store.PruneNothing})
+	ms.Commit() // version 1: stdlibs.
+
+	// Give "addr1" some gnots.
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// version 2: create test package.
+	const pkgPath = "gno.land/r/hello"
+	gnomodToml := `module = "gno.land/r/hello"
+gno = "0.9"
+upgradable = true`
+	files := []*std.MemFile{
+		{Name: "gnomod.toml", Body: gnomodToml},
+		{Name: "hello.gno", Body: `package hello
+
+var name = "world"
+
+func Render(_ string) string {
+	return "hello " + name
+}`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+	ms.Commit()
+
+	// version 3: upgrade the package.
+	files[1].Body = strings.Replace(files[1].Body, `"hello "`, `"hi "`, 1)
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+	ms.Commit()
+
+	// The past heights are queried with the code of the time.
+	expected := map[int64]string{
+		2: "hello world",
+		3: "hi world",
+	}
+	for height, render := range expected {
+		past, err := ms.MultiImmutableCacheWrapWithVersion(height)
+		require.NoError(t, err)
+		res := env.vmh.Query(env.ctx.WithMultiStore(past), abci.RequestQuery{
+			Path:   "vm/qrender",
+			Data:   []byte(pkgPath + ":"),
+			Height: height,
+		})
+		require.True(t, res.IsOK(), "height %d: %v", height, res.Error)
+		assert.Equal(t, render, string(res.Data), "height %d", height)
+	}
+
+	// The latest height still uses the latest code.
+	res := env.vmh.Query(env.ctx, abci.RequestQuery{
+		Path: "vm/qrender",
+		Data: []byte(pkgPath + ":"),
+	})
+	require.True(t, res.IsOK(), "%v", res.Error)
+	assert.Equal(t, "hi world", string(res.Data))
+}
+
+func TestVmHandlerQuery_HistoryNotKept(t *testing.T) {
+	env := setupTestEnv()
+
+	res := env.vmh.Query(env.ctx, abci.RequestQuery{
+		Path:   "vm/qrender",
+		Data:   []byte("gno.land/r/hello:"),
+		Height: 1,
+	})
+	require.False(t, res.IsOK())
+	assert.Contains(t, res.Log, "the node does not keep the history of the vm state")
+}
+
+func TestVmHandlerQuery_Object(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	const pkgPath = "gno.land/r/obj"
+	files := []*std.MemFile{
+		{Name: "gnomod.toml", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "obj.gno", Body: `
+package obj
+
+type item struct {
+	n    int
+	tags []string
+}
+
+var (
+	it    = item{n: 1, tags: []string{"a", "b"}}
+	items = []*item{&item{n: 2}}
+	ptr   = &item{n: 3}
+	count = 4
+)
+`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	query := func(data string) abci.ResponseQuery {
+		return env.vmh.Query(env.ctx, abci.RequestQuery{
+			Path: "vm/qobject",
+			Data: []byte(data),
+		})
+	}
+	verify := func(res abci.ResponseQuery) (gnolang.Object, gnolang.ObjectProof) {
+		t.Helper()
+		require.True(t, res.IsOK(), "%s", res.Log)
+		var proof gnolang.ObjectProof
+		require.NoError(t, amino.UnmarshalJSON(res.Data, &proof))
+		oo, key, value, err := proof.Verify()
+		require.NoError(t, err)
+		assert.Equal(t, key, res.Key)
+		assert.Equal(t, value, res.Value)
+		// The key and the value are the ones of the iavl store.
+		iavl := env.ctx.Store(env.vmk.iavlKey)
+		assert.Equal(t, value, iavl.Get(key))
+		return oo, proof
+	}
+
+	// By variable name.
+	oo, proof := verify(query(pkgPath + ".it"))
+	sv, ok := oo.(*gnolang.StructValue)
+	require.True(t, ok, "%T", oo)
+	assert.Equal(t, int64(1), sv.Fields[0].GetInt())
+	// The struct is owned by its variable, itself owned by the block of the
+	// realm package, which is escaped (also referred to by the file blocks)
+	// and so has its hash in the iavl store.
+	require.Len(t, proof.Objects, 3)
+	var root gnolang.Object
+	amino.MustUnmarshal(proof.Objects[2], &root)
+	_, ok = root.(*gnolang.Block)
+	require.True(t, ok, "%T", root)
+	assert.True(t, root.GetIsEscaped())
+
+	// By object ID.
+	oo2, _ := verify(query(sv.GetObjectID().String()))
+	assert.Equal(t, sv.GetObjectID(), oo2.GetObjectID())
+
+	// Through a slice or a pointer.
+	oo, _ = verify(query(pkgPath + ".items"))
+	assert.IsType(t, &gnolang.ArrayValue{}, oo)
+	oo, _ = verify(query(pkgPath + ".ptr"))
+	assert.IsType(t, &gnolang.HeapItemValue{}, oo)
+
+	// Tampering with an object of the proof breaks it.
+	tampered := gnolang.ObjectProof{Objects: slices.Clone(proof.Objects)}
+	bz := slices.Clone(tampered.Objects[0])
+	bz[len(bz)-1]++
+	tampered.Objects[0] = bz
+	_, _, _, err = tampered.Verify()
+	assert.Error(t, err)
+	// As does removing an owner.
+	tampered = gnolang.ObjectProof{Objects: proof.Objects[:len(proof.Objects)-1]}
+	_, _, _, err = tampered.Verify()
+	assert.Error(t, err)
+
+	// Errors.
+	for _, data := range []string{
+		pkgPath + ".count",                            // not an object
+		pkgPath + ".missing",                          // not declared
+		"gno.land/r/none.it",                          // no realm
+		"gno.land/p/none.it",                          // no realm
+		"0000000000000000000000000000000000000000:99", // no object
+		"invalid",
+	} {
+		res := query(data)
+		assert.False(t, res.IsOK(), "%s", data)
+	}
+
+	// A proof against the app hash requires the committed stores.
+	res := env.vmh.Query(env.ctx, abci.RequestQuery{
+		Path:  "vm/qobject",
+		Data:  []byte(pkgPath + ".it"),
+		Prove: true,
+	})
+	require.False(t, res.IsOK())
+	assert.Contains(t, res.Log, "only available to queries requesting a proof")
+}
+
+func TestVmHandlerQuery_ObjectRealmRoot(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	const pkgPath = "gno.land/r/obj"
+	files := []*std.MemFile{
+		{Name: "gnomod.toml", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "obj.gno", Body: "package obj\n\nvar count = 4\n"},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	query := func() abci.ResponseQuery {
+		return env.vmh.Query(env.ctx, abci.RequestQuery{
+			Path: "vm/qobject",
+			Data: []byte(gnolang.ObjectIDFromPkgPath(pkgPath).String()),
+		})
+	}
+	rootKey := []byte(gnolang.ObjectIDFromPkgPath(pkgPath).String())
+	iavl := env.ctx.Store(env.vmk.iavlKey)
+
+	// The realm roots are not saved: the package value can't be proven.
+	assert.Nil(t, iavl.Get(rootKey))
+	res := query()
+	require.False(t, res.IsOK())
+	assert.Contains(t, res.Log, "the realm roots are not saved")
+
+	// They are not saved by default.
+	gs := DefaultGenesisState()
+	env.vmk.InitGenesis(env.ctx, gs)
+	assert.Nil(t, iavl.Get(rootKey))
+
+	// Saving them at genesis backfills the realms already deployed.
+	gs.RealmObjectProofs = true
+	env.vmk.InitGenesis(env.ctx, gs)
+	assert.NotNil(t, iavl.Get(rootKey))
+	res = query()
+	require.True(t, res.IsOK(), "%s", res.Log)
+	var proof gnolang.ObjectProof
+	require.NoError(t, amino.UnmarshalJSON(res.Data, &proof))
+	oo, key, value, err := proof.Verify()
+	require.NoError(t, err)
+	assert.IsType(t, &gnolang.PackageValue{}, oo)
+	assert.Equal(t, rootKey, key)
+	assert.Equal(t, value, iavl.Get(key))
+
+	// And the realm package values saved afterwards are kept up to date.
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	const pkgPath2 = "gno.land/r/obj2"
+	files = []*std.MemFile{
+		{Name: "gnomod.toml", Body: gnolang.GenGnoModLatest(pkgPath2)},
+		{Name: "obj.gno", Body: "package obj2\n\nvar count = 5\n"},
+	}
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath2, files))
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+	assert.NotNil(t, iavl.Get([]byte(gnolang.ObjectIDFromPkgPath(pkgPath2).String())))
+}
+
+func TestVmHandlerQuery_EvalJSON(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	const pkgPath = "gno.land/r/hello"
+	files := []*std.MemFile{
+		{Name: "gnomod.toml", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "hello.gno", Body: `
+package hello
+
+import "errors"
+
+type Item struct {
+	Name  string
+	Count int
+}
+
+var items = []*Item{{Name: "a", Count: 1}}
+
+func Get(i int) (*Item, error) {
+	if i >= len(items) {
+		return nil, errors.New("not found")
+	}
+	return items[i], nil
+}
+
+func Add(cur realm, name string) (int, bool) {
+	items = append(items, &Item{Name: name})
+	return len(items), true
+}
+`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	query := func(expr string) []gnolang.JSONValue {
+		t.Helper()
+		res := env.vmh.Query(env.ctx, abci.RequestQuery{
+			Path: "vm/qeval_json",
+			Data: []byte(pkgPath + "." + expr),
+		})
+		require.True(t, res.IsOK(), "%s", res.Log)
+		var jvs []gnolang.JSONValue
+		require.NoError(t, json.Unmarshal(res.Data, &jvs))
+		return jvs
+	}
+
+	jvs := query(`*items[0]`)
+	require.Len(t, jvs, 1)
+	assert.Equal(t, "gno.land/r/hello.Item", jvs[0].T)
+	assert.JSONEq(t, `{"Name":{"T":"string","V":"a"},"Count":{"T":"int","V":"1"}}`, string(jvs[0].V))
+
+	// Persisted values are referred to by their object ID.
+	jvs = query(`Get(0)`)
+	require.Len(t, jvs, 2)
+	assert.Equal(t, "*gno.land/r/hello.Item", jvs[0].T)
+	var ptr gnolang.JSONPointer
+	require.NoError(t, json.Unmarshal(jvs[0].V, &ptr))
+	assert.NotEmpty(t, ptr.ObjectID)
+	assert.Nil(t, ptr.Value)
+	assert.Equal(t, gnolang.JSONValue{V: json.RawMessage("null")}, jvs[1])
+
+	// Errors are encoded like other values.
+	jvs = query(`Get(1)`)
+	require.Len(t, jvs, 2)
+	assert.Equal(t, "*errors.errorString", jvs[1].T)
+	assert.JSONEq(t, `{"Value":{"T":"errors.errorString","V":{"s":{"T":"string","V":"not found"}}}}`, string(jvs[1].V))
+
+	// The results of calls are in the info of the result.
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	res := env.vmh.Process(ctx, NewMsgCall(addr, nil, pkgPath, "Add", []string{"b"}))
+	require.True(t, res.IsOK(), "%s", res.Log)
+	assert.Equal(t, "(2 int)\n(true bool)\n\n", string(res.Data))
+	assert.Equal(t, `[{"T":"int","V":"2"},{"T":"bool","V":true}]`, res.Info)
+}
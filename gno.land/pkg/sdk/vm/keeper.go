@@ -68,6 +68,9 @@ var _ VMKeeperI = &VMKeeper{}
 type VMKeeper struct {
 	// Needs to be explicitly set, like in the case of gnodev.
 	Output io.Writer
+	// Set when the base store keeps its history, e.g. when mounted with
+	// dbadapter.HistoryStoreConstructor, to allow queries at past heights.
+	KeepHistory bool
 
 	baseKey store.StoreKey
 	iavlKey store.StoreKey
@@ -158,6 +161,16 @@ func (vm *VMKeeper) Initialize(
 	}
 }
 
+// Reinitialize initializes the VMKeeper again, once the state of its stores
+// was replaced (e.g. restored from a state sync snapshot).
+func (vm *VMKeeper) Reinitialize(
+	logger *slog.Logger,
+	ms store.MultiStore,
+) {
+	vm.gnoStore = nil
+	vm.Initialize(logger, ms)
+}
+
 type stdlibCache struct {
 	dir  string
 	base store.Store
@@ -328,17 +341,63 @@ type vmkContextKey int
 const (
 	vmkContextKeyStore vmkContextKey = iota
 	vmkContextKeyTypeCheckCache
+	vmkContextKeyGasProfile
+	vmkContextKeyHistoric
 )
 
+// WithGasProfile returns a copy of ctx, in which the gas consumed by the
+// transaction messages handled by the VM is recorded in p. It can be passed
+// as a [sdk.ContextFn], for instance to profile a simulated transaction.
+func WithGasProfile(ctx sdk.Context, p *gno.GasProfile) sdk.Context {
+	return ctx.WithValue(vmkContextKeyGasProfile, p)
+}
+
+// ProfileSimulation is a [sdk.SimulateProfiler]: it returns a [sdk.ContextFn]
+// recording the gas of a simulated transaction in a new gas profile (see
+// [WithGasProfile]), and a function returning the profile in pprof format.
+func ProfileSimulation() (sdk.ContextFn, func() ([]byte, error)) {
+	p := gno.NewGasProfile()
+	ctxFn := func(ctx sdk.Context) sdk.Context {
+		return WithGasProfile(ctx, p)
+	}
+	return ctxFn, func() ([]byte, error) {
+		var buf bytes.Buffer
+		// The files of the profile are named after their package paths.
+		err := p.WriteProfile(&buf, func(pkgPath, file string) string {
+			return pkgPath + "/" + file
+		})
+		return buf.Bytes(), err
+	}
+}
+
+func getGasProfile(ctx sdk.Context) *gno.GasProfile {
+	p, _ := ctx.Value(vmkContextKeyGasProfile).(*gno.GasProfile)
+	return p
+}
+
+// withHistoric returns a copy of ctx, whose stores are loaded at a past
+// height, for which the gno transaction stores don't use the BlockNodes of the
+// latest height (see [gno.Store.BeginHistoricTransaction]).
+func withHistoric(ctx sdk.Context) sdk.Context {
+	return ctx.WithValue(vmkContextKeyHistoric, true)
+}
+
 func (vm *VMKeeper) newGnoTransactionStore(ctx sdk.Context) gno.TransactionStore {
 	base := ctx.Store(vm.baseKey)
 	iavl := ctx.Store(vm.iavlKey)
 	gasMeter := ctx.GasMeter()
 
+	if historic, _ := ctx.Value(vmkContextKeyHistoric).(bool); historic {
+		return vm.gnoStore.BeginHistoricTransaction(base, iavl, gasMeter)
+	}
 	return vm.gnoStore.BeginTransaction(base, iavl, gasMeter)
 }
 
 func (vm *VMKeeper) MakeGnoTransactionStore(ctx sdk.Context) sdk.Context {
+	if p := getGasProfile(ctx); p != nil {
+		// Record the store accesses of the transaction.
+		ctx = ctx.WithGasMeter(p.GasMeter(ctx.GasMeter()))
+	}
 	return ctx.
 		WithValue(vmkContextKeyTypeCheckCache, maps.Clone(vm.typeCheckCache)).
 		WithValue(vmkContextKeyStore, vm.newGnoTransactionStore(ctx))
@@ -386,12 +445,13 @@ func (vm *VMKeeper) callRealmBool(
 
 	m := gno.NewMachineWithOptions(
 		gno.MachineOptions{
-			PkgPath:  "",
-			Output:   vm.Output,
-			Store:    store,
-			Context:  msgCtx,
-			Alloc:    store.GetAllocator(),
-			GasMeter: ctx.GasMeter(),
+			PkgPath:    "",
+			Output:     vm.Output,
+			Store:      store,
+			Context:    msgCtx,
+			Alloc:      store.GetAllocator(),
+			GasMeter:   ctx.GasMeter(),
+			GasProfile: getGasProfile(ctx),
 		})
 	defer m.Release()
 	defer doRecover(m, &err)
@@ -574,6 +634,12 @@ func (vm *VMKeeper) AddPackage(ctx sdk.Context, msg MsgAddPackage) (err error) {
 	if gm.Private && !gno.IsRealmPath(pkgPath) {
 		return ErrInvalidPackage("private packages must be realm packages")
 	}
+	if gm.Upgradable && !gno.IsRealmPath(pkgPath) {
+		return ErrInvalidPackage("upgradable packages must be realm packages")
+	}
+	if gm.Upgradable && gm.Private {
+		return ErrInvalidPackage("private packages cannot be upgradable, they can be overridden instead")
+	}
 	if gm.Draft && ctx.BlockHeight() > 0 {
 		return ErrInvalidPackage("draft packages can only be deployed at genesis time")
 	}
@@ -586,6 +652,7 @@ func (vm *VMKeeper) AddPackage(ctx sdk.Context, msg MsgAddPackage) (err error) {
 	gm.Module = pkgPath // XXX: if gm.Module != msg.Package.Path { panic() }?
 	gm.AddPkg.Creator = creator.String()
 	gm.AddPkg.Height = int(ctx.BlockHeight())
+	gm.Upgrade.Height = 0
 	// Re-encode gnomod.toml in memPkg
 	memPkg.SetFile("gnomod.toml", gm.WriteString())
 
@@ -625,12 +692,13 @@ func (vm *VMKeeper) AddPackage(ctx sdk.Context, msg MsgAddPackage) (err error) {
 	// Parse and run the files, construct *PV.
 	m2 := gno.NewMachineWithOptions(
 		gno.MachineOptions{
-			PkgPath:  "",
-			Output:   vm.Output,
-			Store:    gnostore,
-			Alloc:    gnostore.GetAllocator(),
-			Context:  msgCtx,
-			GasMeter: ctx.GasMeter(),
+			PkgPath:    "",
+			Output:     vm.Output,
+			Store:      gnostore,
+			Alloc:      gnostore.GetAllocator(),
+			Context:    msgCtx,
+			GasMeter:   ctx.GasMeter(),
+			GasProfile: getGasProfile(ctx),
 		})
 	defer m2.Release()
 	defer doRecover(m2, &err)
@@ -656,8 +724,197 @@ func (vm *VMKeeper) AddPackage(ctx sdk.Context, msg MsgAddPackage) (err error) {
 	return nil
 }
 
+// checkUpgradePermission checks whether creator may upgrade the package
+// pkgPath, given its current gnomod.toml gm: creator must be the creator of
+// the package, or be authorized by the governance realm of the package.
+func (vm *VMKeeper) checkUpgradePermission(ctx sdk.Context, creator crypto.Address, pkgPath string, gm *gnomod.File) error {
+	if gm.AddPkg.Creator == creator.String() {
+		return nil
+	}
+	govPkg := gm.Upgrade.Governance
+	if govPkg == "" {
+		return ErrUnauthorizedUser(
+			fmt.Sprintf("%s is not the creator of %s", creator.String(), pkgPath))
+	}
+
+	store := vm.getGnoTransactionStore(ctx)
+	if store.GetPackage(govPkg, false) == nil {
+		return ErrUnauthorizedUser(
+			fmt.Sprintf("governance realm %s of %s does not exist", govPkg, pkgPath))
+	}
+
+	result, err := vm.callRealmBool(ctx, creator, govPkg, "gov",
+		"IsUpgradeAuthorized",
+		gno.Str(pkgPath), gno.Str(creator.String()))
+	if err != nil {
+		return err
+	}
+
+	if !result {
+		return ErrUnauthorizedUser(
+			fmt.Sprintf("%s is not authorized by %s to upgrade %s",
+				creator.String(), govPkg, pkgPath))
+	}
+
+	return nil
+}
+
+// UpgradePackage replaces the code of an upgradable realm with the given
+// fileset, keeping its state. See [gno.Machine.UpgradeMemPackage].
+func (vm *VMKeeper) UpgradePackage(ctx sdk.Context, msg MsgUpgradePackage) (err error) {
+	creator := msg.Creator
+	pkgPath := msg.Package.Path
+	memPkg := msg.Package
+	send := msg.Send
+	maxDeposit := msg.MaxDeposit
+	gnostore := vm.getGnoTransactionStore(ctx)
+	chainDomain := vm.getChainDomainParam(ctx)
+
+	memPkg.Type = gno.MPUserAll
+
+	// Validate arguments.
+	if creator.IsZero() {
+		return std.ErrInvalidAddress("missing creator address")
+	}
+	creatorAcc := vm.acck.GetAccount(ctx, creator)
+	if creatorAcc == nil {
+		return std.ErrUnknownAddress(fmt.Sprintf("account %s does not exist, it must receive coins to be created", creator))
+	}
+	if err := gno.ValidateMemPackageAny(msg.Package); err != nil {
+		return ErrInvalidPkgPath(err.Error())
+	}
+	if !gno.IsRealmPath(pkgPath) {
+		return ErrInvalidPkgPath("only realm packages can be upgraded")
+	}
+
+	pv := gnostore.GetPackage(pkgPath, false)
+	if pv == nil {
+		return ErrInvalidPkgPath("package does not exist: " + pkgPath)
+	}
+	oldgm, err := gnomod.ParseMemPackage(gnostore.GetMemPackage(pkgPath))
+	if err != nil {
+		return ErrInvalidPackage(err.Error())
+	}
+	if !oldgm.Upgradable {
+		return ErrInvalidPackage("package is not upgradable: " + pkgPath)
+	}
+	if err := vm.checkUpgradePermission(ctx, creator, pkgPath, oldgm); err != nil {
+		return err
+	}
+
+	opts := gno.TypeCheckOptions{
+		Getter:     gnostore,
+		TestGetter: vm.testStdlibCache.memPackageGetter(gnostore),
+		Mode:       gno.TCLatestStrict,
+		Cache:      vm.getTypeCheckCache(ctx),
+	}
+	// Validate Gno syntax and type check.
+	_, err = gno.TypeCheckMemPackage(memPkg, opts)
+	if err != nil {
+		return ErrTypeCheck(err)
+	}
+
+	// Extra keeper-only checks.
+	gm, err := gnomod.ParseMemPackage(memPkg)
+	if err != nil {
+		return ErrInvalidPackage(err.Error())
+	}
+	if gm.HasReplaces() {
+		return ErrInvalidPackage("development packages are not allowed")
+	}
+	if gm.Private || gm.Draft {
+		return ErrInvalidPackage("an upgraded package cannot be private or draft")
+	}
+	if memPkg.GetFile("gno.mod") != nil {
+		return ErrInvalidPackage("gno.mod file is deprecated and not allowed, run 'gno mod tidy' to upgrade to gnomod.toml")
+	}
+	// The governance realm authorizes the upgrades, so an upgrade cannot
+	// change it.
+	if gm.Upgrade.Governance != oldgm.Upgrade.Governance {
+		return ErrInvalidPackage(fmt.Sprintf("the governance realm of %s cannot change from %q to %q",
+			pkgPath, oldgm.Upgrade.Governance, gm.Upgrade.Governance))
+	}
+
+	// Patch gnomod.toml metadata; the package keeps its creator.
+	gm.Module = pkgPath
+	gm.AddPkg = oldgm.AddPkg
+	gm.Upgrade.Height = int(ctx.BlockHeight())
+	memPkg.SetFile("gnomod.toml", gm.WriteString())
+
+	// Check CLA signature
+	if err := vm.checkCLASignature(ctx, creator); err != nil {
+		return err
+	}
+
+	pkgAddr := gno.DerivePkgCryptoAddr(pkgPath)
+	err = vm.bank.SendCoins(ctx, creator, pkgAddr, send)
+	if err != nil {
+		return err
+	}
+
+	msgCtx := stdlibs.ExecContext{
+		ChainID:         ctx.ChainID(),
+		ChainDomain:     chainDomain,
+		Height:          ctx.BlockHeight(),
+		Timestamp:       ctx.BlockTime().Unix(),
+		OriginCaller:    creator.Bech32(),
+		OriginSend:      send,
+		OriginSendSpent: new(std.Coins),
+		Banker:          NewSDKBanker(vm, ctx),
+		Params:          NewSDKParams(vm.prmk, ctx),
+		EventLogger:     ctx.EventLogger(),
+	}
+	// Parse and run the files in the existing package.
+	m2 := gno.NewMachineWithOptions(
+		gno.MachineOptions{
+			PkgPath:    "",
+			Output:     vm.Output,
+			Store:      gnostore,
+			Alloc:      gnostore.GetAllocator(),
+			Context:    msgCtx,
+			GasMeter:   ctx.GasMeter(),
+			GasProfile: getGasProfile(ctx),
+		})
+	defer m2.Release()
+	defer doRecover(m2, &err)
+	defer func() {
+		if r := recover(); r != nil {
+			if uerr, ok := r.(gno.UpgradeError); ok {
+				err = ErrInvalidPackage(uerr.Error())
+				return
+			}
+			panic(r) // handled by doRecover.
+		}
+	}()
+	params := vm.GetParams(ctx)
+	m2.UpgradeMemPackage(memPkg)
+
+	err = vm.processStorageDeposit(ctx, creator, maxDeposit, gnostore, params)
+	if err != nil {
+		return err
+	}
+	// Log the telemetry
+	logTelemetry(
+		m2.GasMeter.GasConsumed(),
+		m2.Cycles,
+		attribute.KeyValue{
+			Key:   "operation",
+			Value: attribute.StringValue("m_upgradepkg"),
+		},
+	)
+
+	return nil
+}
+
 // Call calls a public Gno function (for delivertx).
 func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
+	res, _, err = vm.CallJSON(ctx, msg)
+	return res, err
+}
+
+// CallJSON is like Call, but also returns the results of the function as
+// JSONValues.
+func (vm *VMKeeper) CallJSON(ctx sdk.Context, msg MsgCall) (res string, jres []gno.JSONValue, err error) {
 	params := vm.GetParams(ctx)
 	pkgPath := msg.PkgPath // to import
 	fnc := msg.Func
@@ -711,18 +968,19 @@ func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 	// Construct machine and evaluate.
 	m := gno.NewMachineWithOptions(
 		gno.MachineOptions{
-			PkgPath:  "",
-			Output:   vm.Output,
-			Store:    gnostore,
-			Context:  msgCtx,
-			Alloc:    gnostore.GetAllocator(),
-			GasMeter: ctx.GasMeter(),
+			PkgPath:    "",
+			Output:     vm.Output,
+			Store:      gnostore,
+			Context:    msgCtx,
+			Alloc:      gnostore.GetAllocator(),
+			GasMeter:   ctx.GasMeter(),
+			GasProfile: getGasProfile(ctx),
 		})
 	xn := m.MustParseExpr(expr)
 	// Send send-coins to pkg from caller.
 	err = vm.bank.SendCoins(ctx, caller, pkgAddr, send)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 	// Convert Args to gno values.
 	cx := xn.(*gno.CallExpr)
@@ -754,8 +1012,11 @@ func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 	// Parameter changes take effect only after the message has executed successfully.
 	err = vm.processStorageDeposit(ctx, caller, msg.MaxDeposit, gnostore, params)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
+	// The results are encoded with a throwaway store consuming no gas, as
+	// they are not part of the execution of the message.
+	jres = gno.ToJSONValues(gnostore.BeginTransaction(nil, nil, nil), rtvs)
 	// Log the telemetry
 	logTelemetry(
 		m.GasMeter.GasConsumed(),
@@ -768,7 +1029,7 @@ func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 
 	res += "\n\n" // use `\n\n` as separator to separate results for single tx with multi msgs
 
-	return res, nil
+	return res, jres, nil
 	// TODO pay for gas? TODO see context?
 }
 
@@ -894,12 +1155,13 @@ func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
 		}
 		m := gno.NewMachineWithOptions(
 			gno.MachineOptions{
-				PkgPath:  "",
-				Output:   output,
-				Store:    gnostore,
-				Alloc:    alloc,
-				Context:  msgCtx,
-				GasMeter: ctx.GasMeter(),
+				PkgPath:    "",
+				Output:     output,
+				Store:      gnostore,
+				Alloc:      alloc,
+				Context:    msgCtx,
+				GasMeter:   ctx.GasMeter(),
+				GasProfile: getGasProfile(ctx),
 			})
 		defer m.Release()
 		defer doRecover(m, &err)
@@ -914,12 +1176,13 @@ func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
 
 	m2 := gno.NewMachineWithOptions(
 		gno.MachineOptions{
-			PkgPath:  "",
-			Output:   output,
-			Store:    gnostore,
-			Alloc:    alloc,
-			Context:  msgCtx,
-			GasMeter: ctx.GasMeter(),
+			PkgPath:    "",
+			Output:     output,
+			Store:      gnostore,
+			Alloc:      alloc,
+			Context:    msgCtx,
+			GasMeter:   ctx.GasMeter(),
+			GasProfile: getGasProfile(ctx),
 		})
 	defer m2.Release()
 	m2.SetActivePackage(pv)
@@ -1060,7 +1323,7 @@ func (vm *VMKeeper) QueryFuncs(ctx sdk.Context, pkgPath string) (fsigs FunctionS
 			}
 			ptype := gno.BaseOf(param.Type).String()
 			fsig.Params = append(fsig.Params,
-				NamedType{Name: pname, Type: ptype},
+				NamedType{Name: pname, Type: ptype, ABI: NewABIType(param.Type)},
 			)
 		}
 		for _, result := range ft.Results {
@@ -1070,7 +1333,7 @@ func (vm *VMKeeper) QueryFuncs(ctx sdk.Context, pkgPath string) (fsigs FunctionS
 			}
 			rtype := gno.BaseOf(result.Type).String()
 			fsig.Results = append(fsig.Results,
-				NamedType{Name: rname, Type: rtype},
+				NamedType{Name: rname, Type: rtype, ABI: NewABIType(result.Type)},
 			)
 		}
 		fsigs = append(fsigs, fsig)
@@ -1094,6 +1357,18 @@ func (vm *VMKeeper) QueryEval(ctx sdk.Context, pkgPath string, expr string) (res
 	return res, nil
 }
 
+// QueryEvalJSON evaluates a gno expression (readonly, for ABCI queries),
+// returning its results as JSONValues.
+func (vm *VMKeeper) QueryEvalJSON(ctx sdk.Context, pkgPath string, expr string) (res []gno.JSONValue, err error) {
+	err = vm.queryEvalWith(ctx, pkgPath, expr, func(m *gno.Machine, rtvs []gno.TypedValue) {
+		res = gno.ToJSONValues(m.Store, rtvs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 // QueryEvalString evaluates a gno expression (readonly, for ABCI queries).
 // The result is expected to be a single string (not a tuple).
 func (vm *VMKeeper) QueryEvalString(ctx sdk.Context, pkgPath string, expr string) (res string, err error) {
@@ -1111,6 +1386,15 @@ func (vm *VMKeeper) QueryEvalString(ctx sdk.Context, pkgPath string, expr string
 }
 
 func (vm *VMKeeper) queryEvalInternal(ctx sdk.Context, pkgPath string, expr string) (rtvs []gno.TypedValue, err error) {
+	err = vm.queryEvalWith(ctx, pkgPath, expr, func(_ *gno.Machine, res []gno.TypedValue) {
+		rtvs = res
+	})
+	return rtvs, err
+}
+
+// queryEvalWith evaluates expr, and passes its results to fn along with the
+// machine which evaluated them, before releasing it.
+func (vm *VMKeeper) queryEvalWith(ctx sdk.Context, pkgPath string, expr string, fn func(*gno.Machine, []gno.TypedValue)) (err error) {
 	ctx = ctx.WithGasMeter(store.NewGasMeter(maxGasQuery))
 	alloc := gno.NewAllocator(maxAllocQuery)
 	gnostore := vm.newGnoTransactionStore(ctx) // throwaway (never committed)
@@ -1119,7 +1403,7 @@ func (vm *VMKeeper) queryEvalInternal(ctx sdk.Context, pkgPath string, expr stri
 	if pv == nil {
 		err = ErrInvalidPkgPath(fmt.Sprintf(
 			"package not found: %s", pkgPath))
-		return nil, err
+		return err
 	}
 	// Construct new machine.
 	chainDomain := vm.getChainDomainParam(ctx)
@@ -1137,21 +1421,23 @@ func (vm *VMKeeper) queryEvalInternal(ctx sdk.Context, pkgPath string, expr stri
 	}
 	m := gno.NewMachineWithOptions(
 		gno.MachineOptions{
-			PkgPath:  pkgPath,
-			Output:   vm.Output,
-			Store:    gnostore,
-			Context:  msgCtx,
-			Alloc:    alloc,
-			GasMeter: ctx.GasMeter(),
+			PkgPath:    pkgPath,
+			Output:     vm.Output,
+			Store:      gnostore,
+			Context:    msgCtx,
+			Alloc:      alloc,
+			GasMeter:   ctx.GasMeter(),
+			GasProfile: getGasProfile(ctx),
 		})
 	defer m.Release()
 	defer doRecoverQuery(m, &err)
 	// Parse expression.
 	xx, err := m.ParseExpr(expr)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return m.Eval(xx), err
+	fn(m, m.Eval(xx))
+	return err
 }
 
 func (vm *VMKeeper) QueryFile(ctx sdk.Context, filepath string) (res string, err error) {
@@ -1208,6 +1494,60 @@ func (vm *VMKeeper) QueryStorage(ctx sdk.Context, pkgPath string) (string, error
 	return res, nil
 }
 
+// QueryObject returns the proof of a persisted object, given by its ObjectID,
+// or as <pkgpath>.<name> for the variable name of the realm pkgpath.
+func (vm *VMKeeper) QueryObject(ctx sdk.Context, query string) (*gno.ObjectProof, error) {
+	gnostore := vm.newGnoTransactionStore(ctx) // throwaway (never committed)
+	var oid gno.ObjectID
+	if strings.Contains(query, "/") {
+		pkgPath, name := parseQueryEvalData(query)
+		pv := gnostore.GetPackage(pkgPath, false)
+		if pv == nil || !pv.IsRealm() {
+			return nil, ErrInvalidPkgPath(fmt.Sprintf(
+				"realm not found: %s", pkgPath))
+		}
+		pn := gnostore.GetPackageNode(pkgPath)
+		idx, ok := pn.GetLocalIndex(gno.Name(name))
+		if !ok {
+			return nil, ErrInvalidExpr(fmt.Sprintf(
+				"name %s not declared in %s", name, pkgPath))
+		}
+		// the object of the variable, or the one it refers to.
+		v := pv.GetBlock(gnostore).Values[idx].V
+		if hiv, ok := v.(*gno.HeapItemValue); ok {
+			v = hiv.Value.V
+		}
+		switch rv := v.(type) {
+		case gno.PointerValue:
+			v = rv.Base
+		case *gno.SliceValue:
+			v = rv.Base
+		}
+		switch v := v.(type) {
+		case gno.RefValue:
+			oid = v.ObjectID
+		case gno.Object:
+			oid = v.GetObjectID()
+		default:
+			return nil, ErrInvalidExpr(fmt.Sprintf(
+				"%s.%s is not an object", pkgPath, name))
+		}
+	} else if err := oid.UnmarshalAmino(query); err != nil {
+		return nil, ErrInvalidExpr(fmt.Sprintf(
+			"invalid object id %q: %v", query, err))
+	}
+	proof, err := gnostore.GetObjectProof(oid)
+	if err != nil {
+		return nil, ErrInvalidExpr(fmt.Sprintf(
+			"cannot prove object %s: %v", oid, err))
+	}
+	if proof == nil {
+		return nil, ErrInvalidExpr(fmt.Sprintf(
+			"object not found: %s", oid))
+	}
+	return proof, nil
+}
+
 // processStorageDeposit processes storage deposit adjustments for package realms based on
 // storage size changes tracked within the gnoStore.
 //
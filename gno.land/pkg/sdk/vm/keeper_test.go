@@ -3,8 +3,11 @@ package vm
 // TODO: move most of the logic in ROOT/gno.land/...
 
 import (
+	"bytes"
+	"compress/gzip"
 	"errors"
 	"fmt"
+	"io"
 	"path"
 	"runtime"
 	"strings"
@@ -16,6 +19,7 @@ import (
 	"github.com/gnolang/gno/gno.land/pkg/gnoland/ugnot"
 	"github.com/gnolang/gno/gnovm/pkg/gnolang"
 	"github.com/gnolang/gno/gnovm/pkg/gnomod"
+	"github.com/gnolang/gno/tm2/pkg/amino"
 	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/db/memdb"
@@ -815,6 +819,77 @@ func Do(cur realm) string {
 	assert.Equal(t, int64(1337), bar)
 }
 
+// Reading realm-local and module params from a realm.
+func TestVMKeeperParamsGetters(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, initialBalance)
+
+	env.prmk.SetStrings(ctx, "bank:p:restricted_denoms", []string{"foocoin"})
+
+	const pkgPath = "gno.land/r/myuser/myrealm"
+	files := []*std.MemFile{
+		{Name: "gnomod.toml", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "init.gno", Body: `
+package params
+
+import (
+	"chain/params"
+	sysparams "sys/params"
+)
+
+func init() {
+	params.SetInt64("bar.int64", int64(1337))
+}
+
+func Do(cur realm) string {
+	bar, ok := params.GetInt64("bar.int64")
+	if !ok || bar != 1337 {
+		panic("unexpected bar.int64")
+	}
+	if _, ok := params.GetString("missing"); ok {
+		panic("unexpected missing param")
+	}
+	denoms, ok := sysparams.GetSysParamStrings("bank", "p", "restricted_denoms")
+	if !ok || len(denoms) != 1 {
+		panic("unexpected restricted_denoms")
+	}
+	return denoms[0]
+}`},
+	}
+
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	require.NoError(t, err)
+
+	msg2 := NewMsgCall(addr, nil, pkgPath, "Do", []string{})
+	res, err := env.vmk.Call(ctx, msg2)
+	require.NoError(t, err)
+	assert.Equal(t, "(\"foocoin\" string)\n\n", res)
+
+	// Each read is charged like a read of the same size from a gas-wrapped
+	// store, including the reads of missing params.
+	gcfg := types.DefaultGasConfig()
+	for _, tc := range []struct {
+		name string
+		get  func(prm *SDKParams)
+		size int64
+	}{
+		{"int64", func(prm *SDKParams) { prm.GetInt64("vm:gno.land/r/myuser/myrealm:bar.int64") }, 8},
+		{"strings", func(prm *SDKParams) { prm.GetStrings("bank:p:restricted_denoms") }, int64(len("foocoin"))},
+		{"missing", func(prm *SDKParams) { prm.GetString("vm:gno.land/r/myuser/myrealm:missing") }, 0},
+	} {
+		gasMeter := types.NewInfiniteGasMeter()
+		tc.get(NewSDKParams(env.vmk.prmk, ctx.WithGasMeter(gasMeter)))
+		assert.Equal(t, gcfg.ReadCostFlat+gcfg.ReadCostPerByte*tc.size, gasMeter.GasConsumed(), tc.name)
+	}
+}
+
 // Assign admin as OriginCaller on deploying the package.
 func TestVMKeeperOriginCallerInit(t *testing.T) {
 	env := setupTestEnv()
@@ -1105,6 +1180,84 @@ func Echo(cur realm, msg string) string { // XXX remove crossing call ?
 	)
 }
 
+func TestVMKeeperCallCompositeArgs(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, initialBalance)
+
+	const pkgPath = "gno.land/r/test"
+	files := []*std.MemFile{
+		{Name: "gnomod.toml", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{
+			Name: "test.gno",
+			Body: `package test
+
+import "strconv"
+
+type Item struct {
+	Name  string
+	Count int64
+	Data  []byte
+	Next  *Item
+	note  string
+}
+
+var items []Item
+
+func Add(cur realm, its []Item, prices map[string]uint32, ids [2]int) string {
+	s := ""
+	for _, it := range its {
+		items = append(items, it)
+		s += it.Name + ":" + strconv.FormatInt(it.Count, 10) + ":" + string(it.Data) + ":" + strconv.Itoa(int(prices[it.Name]))
+		if it.Next != nil {
+			s += "->" + it.Next.Name
+		}
+		s += ";"
+	}
+	return s + strconv.Itoa(ids[0]+ids[1]) + ":" + strconv.Itoa(len(items))
+}`,
+		},
+	}
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	require.NoError(t, err)
+
+	coins := std.MustParseCoins(ugnot.ValueString(1))
+	msg2 := NewMsgCall(addr, coins, pkgPath, "Add", []string{
+		`[{"Name":"a","Count":"3","Data":"aGk=","Next":{"Name":"b"}},{"Name":"b","Count":4}]`,
+		`{"a":10,"b":"20"}`,
+		`[1,2]`,
+	})
+	res, err := env.vmk.Call(ctx, msg2)
+	require.NoError(t, err)
+	assert.Equal(t, `("a:3:hi:10->b;b:4::20;3:2" string)`+"\n\n", res)
+
+	// Unexported fields and mismatches are errors.
+	msg3 := NewMsgCall(addr, coins, pkgPath, "Add", []string{`[{"note":"x"}]`, `null`, `[1,2]`})
+	assert.PanicsWithValue(t,
+		`error converting JSON argument: [0] of type gno.land/r/test.Item: unknown or unexported field "note"`,
+		func() { env.vmk.Call(ctx, msg3) })
+
+	// The ABI describes the parameters in full.
+	fsigs, err := env.vmk.QueryFuncs(env.ctx, pkgPath)
+	require.NoError(t, err)
+	require.Len(t, fsigs, 1)
+	assert.Equal(t,
+		`[{"Name":"its","Type":"[]gno.land/r/test.Item","Value":"","ABI":{"Kind":"slice","Elem":{"Kind":"struct","Name":"gno.land/r/test.Item","Fields":[`+
+			`{"Name":"Name","Type":{"Kind":"string"}},`+
+			`{"Name":"Count","Type":{"Kind":"int64"}},`+
+			`{"Name":"Data","Type":{"Kind":"slice","Elem":{"Kind":"uint8"}}},`+
+			`{"Name":"Next","Type":{"Kind":"pointer","Elem":{"Kind":"struct","Name":"gno.land/r/test.Item"}}}]}}},`+
+			`{"Name":"prices","Type":"map[string]uint32","Value":"","ABI":{"Kind":"map","Key":{"Kind":"string"},"Elem":{"Kind":"uint32"}}},`+
+			`{"Name":"ids","Type":"[2]int","Value":"","ABI":{"Kind":"array","Len":"2","Elem":{"Kind":"int"}}}]`,
+		string(amino.MustMarshalJSON(fsigs[0].Params[1:])))
+}
+
 func TestNonCrossingCallError(t *testing.T) {
 	env := setupTestEnv()
 	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
@@ -1206,6 +1359,62 @@ func Echo(cur realm, msg string) string {
 	assert.Equal(t, `("echo:hello world" string)`+"\n\n", res)
 }
 
+func TestVMKeeperReinitializeGenerics(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, initialBalance)
+
+	// Create test package, persisting an instance of a generic type.
+	const pkgPath = "gno.land/r/test"
+	files := []*std.MemFile{
+		{Name: "gnomod.toml", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "stack.gno", Body: `
+package test
+
+type Stack[T any] struct {
+	items []T
+}
+
+func (s *Stack[T]) Push(v T) int {
+	s.items = append(s.items, v)
+	return len(s.items)
+}
+
+var stack = &Stack[string]{}
+
+func Push(cur realm, msg string) int {
+	return stack.Push(msg)
+}`},
+	}
+
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	require.NoError(t, err)
+
+	msg2 := NewMsgCall(addr, nil, pkgPath, "Push", []string{"hello"})
+	res, err := env.vmk.Call(ctx, msg2)
+	require.NoError(t, err)
+	assert.Equal(t, "(1 int)\n\n", res)
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	// Clear out gnovm and reinitialize: the instance is loaded from the
+	// store, and its methods are instantiated again.
+	env.vmk.gnoStore = nil
+	mcw := env.ctx.MultiStore().MultiCacheWrap()
+	env.vmk.Initialize(log.NewNoopLogger(), mcw)
+	mcw.MultiWrite()
+
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	res, err = env.vmk.Call(ctx, msg2)
+	require.NoError(t, err)
+	assert.Equal(t, "(2 int)\n\n", res)
+}
+
 func Test_loadStdlibPackage(t *testing.T) {
 	mdb := memdb.NewMemDB()
 	cs := dbadapter.StoreConstructor(mdb, types.StoreOptions{})
@@ -1651,3 +1860,386 @@ func Hello(cur realm) string { return "hello" }`},
 	err := env.vmk.AddPackage(ctx, userMsg)
 	assert.NoError(t, err, "should allow deployment when CLA realm is not deployed (bootstrap)")
 }
+
+func TestVMKeeperGasProfile(t *testing.T) {
+	env := setupTestEnv()
+	p := gnolang.NewGasProfile()
+	gasMeter := types.NewInfiniteGasMeter()
+	ctx := env.vmk.MakeGnoTransactionStore(WithGasProfile(env.ctx.WithGasMeter(gasMeter), p))
+
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, initialBalance)
+
+	const pkgPath = "gno.land/r/test"
+	files := []*std.MemFile{
+		{Name: "counter.gno", Body: `
+package test
+
+var counters []int
+
+func Inc(cur realm) int {
+	counters = append(counters, len(counters))
+	return len(counters)
+}`},
+		{Name: "gnomod.toml", Body: gnolang.GenGnoModLatest(pkgPath)},
+	}
+	require.NoError(t, env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files)))
+	_, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Inc", nil))
+	require.NoError(t, err)
+
+	// All the gas consumed through the context is recorded.
+	assert.Equal(t, gasMeter.GasConsumed(), p.Total())
+
+	var buf bytes.Buffer
+	fileName := func(pkgPath, file string) string { return path.Join(pkgPath, file) }
+	require.NoError(t, p.WriteProfile(&buf, fileName))
+	zr, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	prof, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Contains(t, string(prof), "gno.land/r/test.Inc")
+	assert.Contains(t, string(prof), "gno.land/r/test/counter.gno")
+}
+
+func TestVMKeeperUpgradePackage(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" and "addr2" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, initialBalance)
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+	acc2 := env.acck.NewAccountWithAddress(ctx, addr2)
+	env.acck.SetAccount(ctx, acc2)
+	env.bankk.SetCoins(ctx, addr2, initialBalance)
+
+	const pkgPath = "gno.land/r/test"
+	gnomodToml := `module = "gno.land/r/test"
+gno = "0.9"
+upgradable = true`
+	files := []*std.MemFile{
+		{Name: "gnomod.toml", Body: gnomodToml},
+		{Name: "test.gno", Body: `package test
+
+type Item struct {
+	Name string
+}
+
+func (it *Item) Greet() string {
+	return "hi " + it.Name
+}
+
+var (
+	items []*Item
+	count int
+	hook  = Counter
+	greet func() string
+)
+
+func Add(cur realm, name string) int {
+	items = append(items, &Item{Name: name})
+	if greet == nil {
+		greet = items[0].Greet
+	}
+	count++
+	return count
+}
+
+func Counter() int {
+	type counter struct{ n int }
+	c := counter{n: count}
+	return c.n
+}`},
+	}
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	require.NoError(t, err)
+	for _, name := range []string{"a", "b"} {
+		_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Add", []string{name}))
+		require.NoError(t, err)
+	}
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	// The new code grows the bodies of a method and of a function, moving the
+	// declarations below them, and adds variables and functions. The function
+	// values stored in hook and greet run the new code.
+	files2 := []*std.MemFile{
+		{Name: "gnomod.toml", Body: gnomodToml},
+		{Name: "test.gno", Body: `package test
+
+type Item struct {
+	Name string
+}
+
+func (it *Item) Greet() string {
+	greeting := "hello"
+	if it.Name == "" {
+		greeting = "hey"
+	}
+	return greeting + " " + it.Name
+}
+
+var (
+	items []*Item
+	count int
+	hook  = Counter
+	greet func() string
+)
+
+func Add(cur realm, name string) int {
+	items = append(items, &Item{Name: name})
+	if greet == nil {
+		greet = items[0].Greet
+	}
+	count++
+	return count
+}
+
+func Counter() int {
+	type counter struct{ n int8 }
+	c := counter{n: int8(count)}
+	return int(c.n) + 100
+}
+
+var (
+	total    = count * 10
+	migrated int
+)
+
+func migrate() {
+	migrated = len(items)
+}
+
+func Status(cur realm) string {
+	return items[0].Greet() + " " + greet() + " " + itoa(hook()) + " " + itoa(total) + " " + itoa(migrated)
+}
+
+func itoa(i int) string {
+	if i < 10 {
+		return string(rune('0' + i))
+	}
+	return itoa(i/10) + itoa(i%10)
+}`},
+	}
+
+	// Only the creator can upgrade the package.
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr2, pkgPath, files2))
+	assert.True(t, errors.Is(err, UnauthorizedUserError{}), "error should be UnauthorizedUserError, got: %v", err)
+
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr, pkgPath, files2))
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	res, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Add", []string{"c"}))
+	require.NoError(t, err)
+	assert.Equal(t, "(3 int)\n\n", res)
+	statusMsg := NewMsgCall(addr, nil, pkgPath, "Status", nil)
+	res, err = env.vmk.Call(ctx, statusMsg)
+	require.NoError(t, err)
+	assert.Equal(t, "(\"hello a hello a 103 20 2\" string)\n\n", res)
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	// The gnomod.toml keeps the creator, and records the upgrade.
+	store := env.vmk.getGnoTransactionStore(ctx)
+	gm, err := gnomod.ParseBytes("gnomod.toml", []byte(store.GetMemFile(pkgPath, "gnomod.toml").Body))
+	require.NoError(t, err)
+	assert.Equal(t, addr.String(), gm.AddPkg.Creator)
+
+	// Same state after reinitializing gnovm.
+	env.vmk.gnoStore = nil
+	mcw := env.ctx.MultiStore().MultiCacheWrap()
+	env.vmk.Initialize(log.NewNoopLogger(), mcw)
+	mcw.MultiWrite()
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	res, err = env.vmk.Call(ctx, statusMsg)
+	require.NoError(t, err)
+	assert.Equal(t, "(\"hello a hello a 103 20 2\" string)\n\n", res)
+
+	for _, tc := range []struct {
+		name     string
+		old, new string
+		err      string
+	}{
+		{"type layout", "Name string\n}", "Name string\n\tAge  int\n}", "type Item cannot change its underlying type"},
+		{"variable type", "itoa(total)", "itoa(int(total))", "variable total cannot change type"},
+		{"method signature", "func (it *Item) Greet()", "func (it Item) Greet()", "method Item.Greet cannot change its signature"},
+		{"removed function", "func Status(", "func Status2(", "function Status cannot be removed"},
+		// the type keeps its TypeID, as the function keeps its location.
+		{"local type layout", "int8", "uint", "counter cannot change its underlying type"},
+	} {
+		body := strings.ReplaceAll(files2[1].Body, tc.old, tc.new)
+		if tc.name == "variable type" {
+			body = strings.Replace(body, "total    = count * 10", "total    = int64(count * 10)", 1)
+		}
+		files3 := []*std.MemFile{
+			{Name: "gnomod.toml", Body: gnomodToml},
+			{Name: "test.gno", Body: body},
+		}
+		ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+		err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr, pkgPath, files3))
+		assert.True(t, errors.Is(err, InvalidPackageError{}), "%s: error should be InvalidPackageError, got: %v", tc.name, err)
+		assert.Contains(t, fmt.Sprintf("%+v", err), tc.err, tc.name)
+	}
+
+	// Upgradable packages cannot be imported.
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	files4 := []*std.MemFile{
+		{Name: "gnomod.toml", Body: gnolang.GenGnoModLatest("gno.land/r/importer")},
+		{Name: "importer.gno", Body: `package importer
+
+import "gno.land/r/test"
+
+func Add(cur realm) int { return test.Add(cross, "d") }`},
+	}
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/importer", files4))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is upgradable and cannot be imported")
+}
+
+func TestVMKeeperUpgradePackage_Closure(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, initialBalance)
+
+	const pkgPath = "gno.land/r/test"
+	gnomodToml := `module = "gno.land/r/test"
+gno = "0.9"
+upgradable = true`
+	files := []*std.MemFile{
+		{Name: "gnomod.toml", Body: gnomodToml},
+		{Name: "test.gno", Body: `package test
+
+var cb func() string
+
+func Set(cur realm, s string) {
+	cb = func() string { return s }
+}
+
+func Clear(cur realm) {
+	cb = nil
+}`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Set", []string{"v1"}))
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	// The stored closure refers to the old code.
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr, pkgPath, files))
+	assert.True(t, errors.Is(err, InvalidPackageError{}), "error should be InvalidPackageError, got: %v", err)
+	assert.Contains(t, fmt.Sprintf("%+v", err), "the realm stores a closure declared at gno.land/r/test/test.gno:6")
+
+	// Without it, the realm can be upgraded.
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Clear", nil))
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr, pkgPath, files))
+	require.NoError(t, err)
+}
+
+func TestVMKeeperUpgradePackage_Governance(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" and "addr2" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, initialBalance)
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+	acc2 := env.acck.NewAccountWithAddress(ctx, addr2)
+	env.acck.SetAccount(ctx, acc2)
+	env.bankk.SetCoins(ctx, addr2, initialBalance)
+
+	// The governance realm authorizes addr2.
+	govFiles := []*std.MemFile{
+		{Name: "gnomod.toml", Body: gnolang.GenGnoModLatest("gno.land/r/gov")},
+		{Name: "gov.gno", Body: `package gov
+
+func IsUpgradeAuthorized(pkgPath, addr string) bool {
+	return pkgPath == "gno.land/r/test" && addr == "` + addr2.String() + `"
+}`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/gov", govFiles))
+	require.NoError(t, err)
+
+	const pkgPath = "gno.land/r/test"
+	gnomodToml := `module = "gno.land/r/test"
+gno = "0.9"
+upgradable = true
+
+[upgrade]
+governance = "gno.land/r/gov"`
+	files := []*std.MemFile{
+		{Name: "gnomod.toml", Body: gnomodToml},
+		{Name: "test.gno", Body: `package test
+
+func Echo(cur realm) string { return "v1" }`},
+	}
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+
+	files[1].Body = `package test
+
+func Echo(cur realm) string { return "v2" }`
+	addr3 := crypto.AddressFromPreimage([]byte("addr3"))
+	acc3 := env.acck.NewAccountWithAddress(ctx, addr3)
+	env.acck.SetAccount(ctx, acc3)
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr3, pkgPath, files))
+	assert.True(t, errors.Is(err, UnauthorizedUserError{}), "error should be UnauthorizedUserError, got: %v", err)
+
+	// The upgrade cannot replace or drop the governance realm.
+	for _, gov := range []string{"gno.land/r/gov2", ""} {
+		files2 := []*std.MemFile{
+			{Name: "gnomod.toml", Body: strings.Replace(gnomodToml, "gno.land/r/gov", gov, 1)},
+			files[1],
+		}
+		err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr2, pkgPath, files2))
+		assert.True(t, errors.Is(err, InvalidPackageError{}), "error should be InvalidPackageError, got: %v", err)
+		assert.Contains(t, fmt.Sprintf("%+v", err), "the governance realm of gno.land/r/test cannot change")
+	}
+
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr2, pkgPath, files))
+	require.NoError(t, err)
+
+	res, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Echo", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "(\"v2\" string)\n\n", res)
+}
+
+func TestVMKeeperUpgradePackage_NotUpgradable(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, initialBalance)
+
+	const pkgPath = "gno.land/r/test"
+	files := []*std.MemFile{
+		{Name: "gnomod.toml", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "test.gno", Body: `package test
+
+func Echo(cur realm) string { return "v1" }`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr, pkgPath, files))
+	assert.True(t, errors.Is(err, InvalidPackageError{}), "error should be InvalidPackageError, got: %v", err)
+	assert.Contains(t, fmt.Sprintf("%+v", err), "package is not upgradable")
+}
@@ -85,6 +85,73 @@ func (msg MsgAddPackage) GetReceived() std.Coins {
 	return msg.Send
 }
 
+//----------------------------------------
+// MsgUpgradePackage
+
+// MsgUpgradePackage - replace the code of an upgradable realm, keeping its state
+type MsgUpgradePackage struct {
+	Creator    crypto.Address  `json:"creator" yaml:"creator"`
+	Package    *std.MemPackage `json:"package" yaml:"package"`
+	Send       std.Coins       `json:"send" yaml:"send"`
+	MaxDeposit std.Coins       `json:"max_deposit" yaml:"max_deposit"`
+}
+
+var _ std.Msg = MsgUpgradePackage{}
+
+// NewMsgUpgradePackage - upload the new files of a package.
+func NewMsgUpgradePackage(creator crypto.Address, pkgPath string, files []*std.MemFile) MsgUpgradePackage {
+	msg := NewMsgAddPackage(creator, pkgPath, files)
+	return MsgUpgradePackage{
+		Creator: msg.Creator,
+		Package: msg.Package,
+	}
+}
+
+// Implements Msg.
+func (msg MsgUpgradePackage) Route() string { return RouterKey }
+
+// Implements Msg.
+func (msg MsgUpgradePackage) Type() string { return "upgrade_package" }
+
+// Implements Msg.
+func (msg MsgUpgradePackage) ValidateBasic() error {
+	if msg.Creator.IsZero() {
+		return std.ErrInvalidAddress("missing creator address")
+	}
+	if msg.Package.Path == "" {
+		return ErrInvalidPkgPath("missing package path")
+	}
+	if !gno.IsRealmPath(msg.Package.Path) {
+		return ErrInvalidPkgPath("pkgpath must be of a realm")
+	}
+	if !msg.Send.IsValid() {
+		return std.ErrInvalidCoins(msg.Send.String())
+	}
+	if !msg.MaxDeposit.IsValid() {
+		return std.ErrInvalidCoins(msg.MaxDeposit.String())
+	}
+	// Validate: ensure the package contains at least one file.
+	if len(msg.Package.Files) == 0 {
+		return ErrInvalidFile("no files in MsgUpgradePackage")
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgUpgradePackage) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// Implements Msg.
+func (msg MsgUpgradePackage) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Creator}
+}
+
+// Implements ReceiveMsg.
+func (msg MsgUpgradePackage) GetReceived() std.Coins {
+	return msg.Send
+}
+
 //----------------------------------------
 // MsgCall
 
@@ -157,6 +224,11 @@ func (msg MsgCall) GetReceived() std.Coins {
 	return msg.Send
 }
 
+// Implements auth.PkgPathMsg.
+func (msg MsgCall) GetPkgPath() string {
+	return msg.PkgPath
+}
+
 //----------------------------------------
 // MsgRun
 
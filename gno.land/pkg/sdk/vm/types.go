@@ -42,6 +42,7 @@ type NamedType struct {
 	Name  string
 	Type  string
 	Value string
+	ABI   *ABIType `json:"ABI,omitempty"`
 }
 
 type FunctionSignatures []FunctionSignature
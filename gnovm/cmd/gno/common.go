@@ -27,20 +27,25 @@ const (
 	gnoParserError     gnoCode = "gnoParserError"
 	gnoTypeCheckError  gnoCode = "gnoTypeCheckError"
 	gnoLintError       gnoCode = "gnoLintError"
+	gnoLintWarning     gnoCode = "gnoLintWarning" // see internal/lint.
 
 	// TODO: add new gno codes here.
 )
 
 type gnoIssue struct {
-	Code       gnoCode
-	Msg        string
-	Confidence float64 // 1 is 100%
-	Location   string  // file:line, or equivalent
+	Code       gnoCode `json:"code"`
+	Rule       string  `json:"rule,omitempty"` // lint rule, for gnoLintWarning
+	Msg        string  `json:"msg"`
+	Confidence float64 `json:"confidence"` // 1 is 100%
+	Location   string  `json:"location"`   // file:line, or equivalent
 	// TODO: consider writing fix suggestions
 }
 
 func (i gnoIssue) String() string {
 	// TODO: consider crafting a doc URL based on Code.
+	if i.Rule != "" {
+		return fmt.Sprintf("%s: %s (code=%s, rule=%s)", i.Location, i.Msg, i.Code, i.Rule)
+	}
 	return fmt.Sprintf("%s: %s (code=%s)", i.Location, i.Msg, i.Code)
 }
 
@@ -107,12 +112,18 @@ func parsePkgPathDirective(body string, defaultPkgPath string) (string, error) {
 }
 
 func printError(w io.WriteCloser, dir, pkgPath string, err error) {
+	for _, issue := range issuesFromError(dir, pkgPath, err) {
+		fmt.Fprintln(w, issue)
+	}
+}
+
+func issuesFromError(dir, pkgPath string, err error) []gnoIssue {
 	switch err := err.(type) {
 	case *gno.PreprocessError:
 		err2 := err.Unwrap()
 		// XXX probably no need for guessing, replace with exact issue.
-		fmt.Fprintln(w, guessIssueFromError(
-			dir, pkgPath, err2, gnoPreprocessError).String())
+		return []gnoIssue{guessIssueFromError(
+			dir, pkgPath, err2, gnoPreprocessError)}
 	case gno.ImportError:
 		// NOTE: gnovm/pkg/test.LoadImport will return a
 		// ImportNotFoundError with format "<loc>: unknown import path:
@@ -121,12 +132,12 @@ func printError(w io.WriteCloser, dir, pkgPath string, err error) {
 		// path: <path>"; but Go .Check ends up returning a types.Error
 		// instead, as seen in the hack in the next clause.  So
 		// test.LoadImport needs this and guessing isn't needed.
-		fmt.Fprintln(w, gnoIssue{
+		return []gnoIssue{{
 			Code:       gnoImportError,
 			Msg:        err.GetMsg(),
 			Confidence: 1,
 			Location:   err.GetLocation(),
-		})
+		}}
 	case types.Error:
 		loc := err.Fset.Position(err.Pos).String()
 		loc = guessFilePathLocRel(loc, pkgPath, dir)
@@ -137,50 +148,60 @@ func printError(w io.WriteCloser, dir, pkgPath string, err error) {
 			// on why this is necessary, and how to make it less hacky.
 			code = gnoImportError
 		}
-		fmt.Fprintln(w, gnoIssue{
+		return []gnoIssue{{
 			Code:       code,
 			Msg:        err.Msg,
 			Confidence: 1,
 			Location:   loc,
-		})
+		}}
 	case scanner.ErrorList:
+		issues := make([]gnoIssue, 0, len(err))
 		for _, err := range err {
 			loc := err.Pos.String()
 			loc = guessFilePathLocRel(loc, pkgPath, dir)
-			fmt.Fprintln(w, gnoIssue{
+			issues = append(issues, gnoIssue{
 				Code:       gnoParserError,
 				Msg:        err.Msg,
 				Confidence: 1,
 				Location:   loc,
 			})
 		}
+		return issues
 	case scanner.Error:
 		loc := err.Pos.String()
 		loc = guessFilePathLocRel(loc, pkgPath, dir)
-		fmt.Fprintln(w, gnoIssue{
+		return []gnoIssue{{
 			Code:       gnoParserError,
 			Msg:        err.Msg,
 			Confidence: 1,
 			Location:   loc,
-		})
+		}}
 	default: // error type
 		errors := multierr.Errors(err)
 		if len(errors) == 1 {
-			fmt.Fprintln(w, guessIssueFromError(
+			return []gnoIssue{guessIssueFromError(
 				dir,
 				pkgPath,
 				err,
 				gnoUnknownError,
-			).String())
-			return
+			)}
 		}
+		var issues []gnoIssue
 		for _, err := range errors {
-			printError(w, dir, pkgPath, err)
+			issues = append(issues, issuesFromError(dir, pkgPath, err)...)
 		}
+		return issues
 	}
 }
 
 func catchPanic(dir, pkgPath string, stderr io.WriteCloser, action func()) (didPanic bool) {
+	return catchPanicFunc(func(err error) {
+		printError(stderr, dir, pkgPath, err)
+	}, action)
+}
+
+// Like catchPanic, but calls report with the recovered error.
+func catchPanicFunc(report func(err error), action func()) (didPanic bool) {
 	// If this gets out of hand (e.g. with nested catchPanic with need for
 	// selective catching) then pass in a bool instead.
 	// See also pkg/test/imports.go.
@@ -196,7 +217,7 @@ func catchPanic(dir, pkgPath string, stderr io.WriteCloser, action func()) (didP
 			}
 			didPanic = true
 			if err, ok := r.(error); ok {
-				printError(stderr, dir, pkgPath, err)
+				report(err)
 			} else {
 				panic(r)
 			}
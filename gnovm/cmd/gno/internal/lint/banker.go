@@ -0,0 +1,39 @@
+package lint
+
+import (
+	"go/ast"
+)
+
+// BankerSend reports bankers of type BankerTypeRealmSend created by
+// functions which don't check their caller.
+var BankerSend = &Analyzer{
+	Name: "banker-send",
+	Doc: `A banker of type BankerTypeRealmSend can send the coins of the realm. The
+functions creating one, or the exported functions calling them, should
+check their caller.`,
+	Confidence: 0.8,
+	RealmOnly:  true,
+	Run:        runBankerSend,
+}
+
+func runBankerSend(pass *Pass) {
+	idx := pass.pkg()
+	for _, fd := range idx.funcs {
+		f := idx.fileOf[fd]
+		if fd.Body == nil || importName(f, "chain/banker") == "" || idx.isGuarded(fd) {
+			continue
+		}
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || !isPkgSel(f, call.Fun, "chain/banker", "NewBanker") || len(call.Args) != 1 {
+				return true
+			}
+			if isPkgSel(f, call.Args[0], "chain/banker", "BankerTypeRealmSend") {
+				pass.Reportf(call.Pos(),
+					"%s creates a banker of type BankerTypeRealmSend without checking the previous realm",
+					fd.Name.Name)
+			}
+			return true
+		})
+	}
+}
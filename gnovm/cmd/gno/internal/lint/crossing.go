@@ -0,0 +1,70 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+	"slices"
+)
+
+// CrossingAuth reports exported crossing functions of realms which modify
+// the state of the realm without checking their caller.
+var CrossingAuth = &Analyzer{
+	Name: "crossing-auth",
+	Doc: `Exported crossing functions can be called by anyone. Those modifying the
+state of the realm should check their caller, with runtime.PreviousRealm(),
+cur.Previous(), or an assertion like ownable's AssertOwnedByPrevious().`,
+	Confidence: 0.7,
+	RealmOnly:  true,
+	Run:        runCrossingAuth,
+}
+
+// Methods which are assumed to modify their receiver, like those of avl
+// trees.
+var mutatingMethods = []string{
+	"Set", "Remove", "Delete", "Append", "Push", "Pop",
+	"Insert", "Add", "Update", "Clear",
+}
+
+func runCrossingAuth(pass *Pass) {
+	idx := pass.pkg()
+	writes := idx.transitive(func(fd *ast.FuncDecl) bool {
+		return fd.Body != nil && writesGlobals(idx, fd)
+	})
+	for _, fd := range idx.funcs {
+		if fd.Recv != nil || !fd.Name.IsExported() || !isCrossing(fd) {
+			continue
+		}
+		if writes[fd] && !idx.auth[fd] {
+			pass.Reportf(fd.Name.Pos(),
+				"exported crossing function %s modifies the realm state without checking the previous realm",
+				fd.Name.Name)
+		}
+	}
+}
+
+// Returns whether the body of fd assigns to a package-level variable, or
+// calls a mutating method on one.
+func writesGlobals(idx *pkgIndex, fd *ast.FuncDecl) bool {
+	isGlobal := func(x ast.Expr) bool {
+		id := rootIdent(x)
+		return id != nil && idx.isGlobal(fd, id.Name)
+	}
+	found := false
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if n.Tok != token.DEFINE && slices.ContainsFunc(n.Lhs, isGlobal) {
+				found = true
+			}
+		case *ast.IncDecStmt:
+			found = found || isGlobal(n.X)
+		case *ast.CallExpr:
+			if sel, ok := n.Fun.(*ast.SelectorExpr); ok &&
+				slices.Contains(mutatingMethods, sel.Sel.Name) && isGlobal(sel.X) {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
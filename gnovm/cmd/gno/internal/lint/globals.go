@@ -0,0 +1,46 @@
+package lint
+
+import (
+	"go/ast"
+)
+
+// GlobalSliceReturn reports exported functions returning a package-level
+// slice of a realm.
+var GlobalSliceReturn = &Analyzer{
+	Name: "global-slice-return",
+	Doc: `Returning a package-level slice shares its underlying array with the
+caller, which may then modify the state of the realm through it. Return a
+copy instead.`,
+	Confidence: 0.8,
+	RealmOnly:  true,
+	Run:        runGlobalSliceReturn,
+}
+
+func runGlobalSliceReturn(pass *Pass) {
+	idx := pass.pkg()
+	for _, fd := range idx.funcs {
+		if fd.Body == nil || !fd.Name.IsExported() {
+			continue
+		}
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				return false // returns of the closure, not fd.
+			case *ast.ReturnStmt:
+				for _, x := range n.Results {
+					x = ast.Unparen(x)
+					if sx, ok := x.(*ast.SliceExpr); ok {
+						x = sx.X
+					}
+					id, ok := x.(*ast.Ident)
+					if ok && idx.globals[id.Name] && !idx.isLocal(fd, id.Name) {
+						pass.Reportf(x.Pos(),
+							"%s returns the package-level slice %s; return a copy instead",
+							fd.Name.Name, id.Name)
+					}
+				}
+			}
+			return true
+		})
+	}
+}
@@ -0,0 +1,356 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// pkgIndex indexes the package-level declarations of a package, shared by
+// the analyzers. Names are resolved syntactically: a local declaration
+// anywhere in a function shadows a package-level name in the whole function.
+type pkgIndex struct {
+	funcs   []*ast.FuncDecl          // all functions and methods
+	byName  map[string]*ast.FuncDecl // functions (not methods), by name
+	fileOf  map[*ast.FuncDecl]*ast.File
+	callees map[*ast.FuncDecl][]*ast.FuncDecl
+	callers map[*ast.FuncDecl][]*ast.FuncDecl
+	locals  map[*ast.FuncDecl]map[string]struct{}
+	globals map[string]bool // package-level variables, true if slices
+	auth    map[*ast.FuncDecl]bool
+}
+
+func (pass *Pass) pkg() *pkgIndex {
+	if pass.index != nil {
+		return pass.index
+	}
+	idx := &pkgIndex{
+		byName:  map[string]*ast.FuncDecl{},
+		fileOf:  map[*ast.FuncDecl]*ast.File{},
+		callees: map[*ast.FuncDecl][]*ast.FuncDecl{},
+		callers: map[*ast.FuncDecl][]*ast.FuncDecl{},
+		locals:  map[*ast.FuncDecl]map[string]struct{}{},
+		globals: map[string]bool{},
+	}
+	for _, f := range pass.Files {
+		for _, d := range f.Decls {
+			switch d := d.(type) {
+			case *ast.FuncDecl:
+				idx.funcs = append(idx.funcs, d)
+				idx.fileOf[d] = f
+				if d.Recv == nil {
+					idx.byName[d.Name.Name] = d
+				}
+			case *ast.GenDecl:
+				if d.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range d.Specs {
+					vs := spec.(*ast.ValueSpec)
+					for i, nx := range vs.Names {
+						idx.globals[nx.Name] = isSliceSpec(vs, i)
+					}
+				}
+			}
+		}
+	}
+	for _, fd := range idx.funcs {
+		idx.locals[fd] = localNames(fd)
+	}
+	for _, fd := range idx.funcs {
+		if fd.Body == nil {
+			continue
+		}
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if id, ok := call.Fun.(*ast.Ident); ok && !idx.isLocal(fd, id.Name) {
+				if callee := idx.byName[id.Name]; callee != nil {
+					idx.callees[fd] = append(idx.callees[fd], callee)
+					idx.callers[callee] = append(idx.callers[callee], fd)
+				}
+			}
+			return true
+		})
+	}
+	idx.auth = idx.transitive(func(fd *ast.FuncDecl) bool {
+		return fd.Body != nil && containsCall(fd.Body, func(call *ast.CallExpr) bool {
+			return isAuthCall(idx.fileOf[fd], call)
+		})
+	})
+	pass.index = idx
+	return idx
+}
+
+func (idx *pkgIndex) isLocal(fd *ast.FuncDecl, name string) bool {
+	_, ok := idx.locals[fd][name]
+	return ok
+}
+
+// Returns whether name refers to a package-level variable within fd.
+func (idx *pkgIndex) isGlobal(fd *ast.FuncDecl, name string) bool {
+	_, ok := idx.globals[name]
+	return ok && !idx.isLocal(fd, name)
+}
+
+// Returns the functions for which direct is true, or which call such a
+// function, directly or not.
+func (idx *pkgIndex) transitive(direct func(fd *ast.FuncDecl) bool) map[*ast.FuncDecl]bool {
+	res := map[*ast.FuncDecl]bool{}
+	for _, fd := range idx.funcs {
+		if direct(fd) {
+			res[fd] = true
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, fd := range idx.funcs {
+			if res[fd] {
+				continue
+			}
+			if slices.ContainsFunc(idx.callees[fd], func(c *ast.FuncDecl) bool { return res[c] }) {
+				res[fd] = true
+				changed = true
+			}
+		}
+	}
+	return res
+}
+
+// Returns whether the caller of fd is checked, either by fd itself, or by
+// all the callers of fd if it is an unexported function. Unexported methods
+// are assumed to be checked, since their callers are not resolved.
+func (idx *pkgIndex) isGuarded(fd *ast.FuncDecl) bool {
+	return idx.isGuarded2(fd, map[*ast.FuncDecl]bool{})
+}
+
+func (idx *pkgIndex) isGuarded2(fd *ast.FuncDecl, visiting map[*ast.FuncDecl]bool) bool {
+	switch {
+	case idx.auth[fd], fd.Recv == nil && fd.Name.Name == "init":
+		return true
+	case fd.Name.IsExported():
+		return false
+	case fd.Recv != nil:
+		return true
+	case visiting[fd]:
+		return true // recursion; decided by the other callers.
+	}
+	callers := idx.callers[fd]
+	if len(callers) == 0 {
+		return false
+	}
+	visiting[fd] = true
+	defer delete(visiting, fd)
+	for _, c := range callers {
+		if !idx.isGuarded2(c, visiting) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the functions reachable from fd, including fd.
+func (idx *pkgIndex) reachable(fd *ast.FuncDecl) []*ast.FuncDecl {
+	res := []*ast.FuncDecl{fd}
+	seen := map[*ast.FuncDecl]bool{fd: true}
+	for i := 0; i < len(res); i++ {
+		for _, c := range idx.callees[res[i]] {
+			if !seen[c] {
+				seen[c] = true
+				res = append(res, c)
+			}
+		}
+	}
+	return res
+}
+
+// ----------------------------------------
+// syntax helpers
+
+// Returns the names declared within fd, including its receiver, parameters
+// and results.
+func localNames(fd *ast.FuncDecl) map[string]struct{} {
+	names := map[string]struct{}{}
+	addFields := func(fl *ast.FieldList) {
+		if fl == nil {
+			return
+		}
+		for _, field := range fl.List {
+			for _, nx := range field.Names {
+				names[nx.Name] = struct{}{}
+			}
+		}
+	}
+	addFields(fd.Recv)
+	ast.Inspect(fd, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncType:
+			addFields(n.Params)
+			addFields(n.Results)
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE {
+				for _, x := range n.Lhs {
+					if id, ok := x.(*ast.Ident); ok {
+						names[id.Name] = struct{}{}
+					}
+				}
+			}
+		case *ast.RangeStmt:
+			if n.Tok == token.DEFINE {
+				for _, x := range []ast.Expr{n.Key, n.Value} {
+					if id, ok := x.(*ast.Ident); ok {
+						names[id.Name] = struct{}{}
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for _, nx := range n.Names {
+				names[nx.Name] = struct{}{}
+			}
+		case *ast.TypeSpec:
+			names[n.Name.Name] = struct{}{}
+		}
+		return true
+	})
+	return names
+}
+
+// Returns whether the i-th variable of vs is declared as a slice, either by
+// its type or by its value, e.g. `[]int{}` or `make([]int, 0)`.
+func isSliceSpec(vs *ast.ValueSpec, i int) bool {
+	if vs.Type != nil {
+		return isSliceType(vs.Type)
+	}
+	if len(vs.Values) != len(vs.Names) {
+		return false
+	}
+	switch x := ast.Unparen(vs.Values[i]).(type) {
+	case *ast.CompositeLit:
+		return isSliceType(x.Type)
+	case *ast.CallExpr:
+		if id, ok := x.Fun.(*ast.Ident); ok && id.Name == "make" && len(x.Args) > 0 {
+			return isSliceType(x.Args[0])
+		}
+	}
+	return false
+}
+
+func isSliceType(x ast.Expr) bool {
+	at, ok := x.(*ast.ArrayType)
+	return ok && at.Len == nil
+}
+
+// Returns the identifier at the root of a selector, index or dereference
+// expression, e.g. a for `a.b[c].d`.
+func rootIdent(x ast.Expr) *ast.Ident {
+	for {
+		switch cx := x.(type) {
+		case *ast.Ident:
+			return cx
+		case *ast.SelectorExpr:
+			x = cx.X
+		case *ast.IndexExpr:
+			x = cx.X
+		case *ast.StarExpr:
+			x = cx.X
+		case *ast.ParenExpr:
+			x = cx.X
+		default:
+			return nil
+		}
+	}
+}
+
+func containsCall(n ast.Node, pred func(call *ast.CallExpr) bool) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && pred(call) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// Returns whether fd is a crossing function, i.e. whose first parameter is
+// of type realm.
+func isCrossing(fd *ast.FuncDecl) bool {
+	params := fd.Type.Params
+	if params == nil || len(params.List) == 0 {
+		return false
+	}
+	id, ok := params.List[0].Type.(*ast.Ident)
+	return ok && id.Name == "realm"
+}
+
+// Returns whether call checks the caller of the current function: a call to
+// runtime.PreviousRealm(), runtime.OriginCaller(), cur.Previous(), or to a
+// function whose name suggests so, like runtime.AssertOriginCall() and the
+// Owned(), AssertOwnedByPrevious() and DoByPrevious() helpers of p/nt.
+func isAuthCall(f *ast.File, call *ast.CallExpr) bool {
+	var name string
+	switch fx := call.Fun.(type) {
+	case *ast.Ident:
+		name = fx.Name
+	case *ast.SelectorExpr:
+		name = fx.Sel.Name
+		if isPkgSel(f, fx, "chain/runtime", "OriginCaller") {
+			return true
+		}
+		if name == "Origin" && len(call.Args) == 0 {
+			return true
+		}
+	}
+	return strings.HasPrefix(name, "Assert") ||
+		strings.HasPrefix(name, "assert") ||
+		strings.Contains(name, "Previous") ||
+		strings.Contains(name, "Owned")
+}
+
+var reVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// Returns the name of the import of path in f, or "" if f doesn't import it.
+func importName(f *ast.File, path string) string {
+	for _, imp := range f.Imports {
+		ipath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || ipath != path {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return defaultImportName(ipath)
+	}
+	return ""
+}
+
+// Returns the name of a package by its path, e.g. avl for
+// gno.land/p/nt/avl/v0.
+func defaultImportName(path string) string {
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+	if reVersion.MatchString(name) && len(elems) > 1 {
+		name = elems[len(elems)-2]
+	}
+	return name
+}
+
+// Returns whether x is a selector of one of names in the package path
+// imported by f, e.g. banker.NewBanker.
+func isPkgSel(f *ast.File, x ast.Expr, path string, names ...string) bool {
+	sel, ok := x.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	id, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	name := importName(f, path)
+	return name != "" && id.Name == name && slices.Contains(names, sel.Sel.Name)
+}
@@ -0,0 +1,199 @@
+// Package lint implements the rule-based checks of `gno lint`, which
+// complement the type checker and the preprocessor with checks specific to
+// realms.
+//
+// Each rule is an [Analyzer] run on the Go AST of the non-test files of a
+// package. Diagnostics may be suppressed with a comment on the same line,
+// or on its own line(s) right above:
+//
+//	//nolint                      suppresses all rules.
+//	//nolint:global-slice-return  suppresses the given rules (comma separated).
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Analyzer is an individual rule of the linter.
+type Analyzer struct {
+	Name       string
+	Doc        string
+	Confidence float64 // 1 is 100%
+	RealmOnly  bool    // only run on realm packages
+	Run        func(pass *Pass)
+}
+
+// Analyzers are all the rules of the linter, by default all enabled.
+var Analyzers = []*Analyzer{
+	CrossingAuth,
+	BankerSend,
+	RenderIterate,
+	GlobalSliceReturn,
+}
+
+// Diagnostic is a problem reported by an Analyzer.
+type Diagnostic struct {
+	Rule       string
+	Pos        token.Position
+	Msg        string
+	Confidence float64
+}
+
+// Pass is the input of an Analyzer for a single package.
+type Pass struct {
+	Fset    *token.FileSet
+	Files   []*ast.File
+	PkgPath string
+	IsRealm bool
+
+	analyzer *Analyzer
+	diags    []Diagnostic
+	index    *pkgIndex // lazily computed.
+}
+
+// Reportf reports a diagnostic of the current analyzer at pos.
+func (pass *Pass) Reportf(pos token.Pos, format string, args ...any) {
+	pass.diags = append(pass.diags, Diagnostic{
+		Rule:       pass.analyzer.Name,
+		Pos:        pass.Fset.Position(pos),
+		Msg:        fmt.Sprintf(format, args...),
+		Confidence: pass.analyzer.Confidence,
+	})
+}
+
+// Run runs the analyzers on the files of the package pkgPath, and returns
+// their diagnostics sorted by position, except those suppressed by nolint
+// comments.
+func Run(fset *token.FileSet, files []*ast.File, pkgPath string, isRealm bool, analyzers []*Analyzer) []Diagnostic {
+	pass := &Pass{
+		Fset:    fset,
+		Files:   files,
+		PkgPath: pkgPath,
+		IsRealm: isRealm,
+	}
+	for _, a := range analyzers {
+		if a.RealmOnly && !isRealm {
+			continue
+		}
+		pass.analyzer = a
+		a.Run(pass)
+	}
+
+	nolints := map[string]map[int][]string{}
+	for _, f := range files {
+		fname := fset.File(f.Pos()).Name()
+		nolints[fname] = fileNolints(fset, f)
+	}
+	diags := make([]Diagnostic, 0, len(pass.diags))
+	for _, d := range pass.diags {
+		lines := nolints[d.Pos.Filename]
+		if isSuppressed(lines[d.Pos.Line], d.Rule) {
+			continue
+		}
+		diags = append(diags, d)
+	}
+	sort.SliceStable(diags, func(i, j int) bool {
+		pi, pj := diags[i].Pos, diags[j].Pos
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		if pi.Line != pj.Line {
+			return pi.Line < pj.Line
+		}
+		return pi.Column < pj.Column
+	})
+	return diags
+}
+
+// ----------------------------------------
+// nolint comments
+
+// Returns the rules suppressed by the nolint comments of f, by line. An
+// empty slice suppresses all rules.
+func fileNolints(fset *token.FileSet, f *ast.File) map[int][]string {
+	// the lowest column of code on each line, to distinguish a comment
+	// standing on its own line from one trailing a statement.
+	code := map[int]int{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n.(type) {
+		case nil:
+			return false
+		case *ast.CommentGroup, *ast.Comment:
+			return false
+		}
+		for _, pos := range []token.Pos{n.Pos(), n.End() - 1} {
+			p := fset.Position(pos)
+			if col, ok := code[p.Line]; !ok || p.Column < col {
+				code[p.Line] = p.Column
+			}
+		}
+		return true
+	})
+
+	res := map[int][]string{}
+	add := func(line int, rules []string) {
+		prev, ok := res[line]
+		switch {
+		case ok && len(prev) == 0:
+			// already suppresses all rules.
+		case rules == nil:
+			res[line] = []string{}
+		default:
+			res[line] = append(prev, rules...)
+		}
+	}
+	for _, cg := range f.Comments {
+		for _, c := range cg.List {
+			rules, ok := parseNolint(c.Text)
+			if !ok {
+				continue
+			}
+			p := fset.Position(c.Pos())
+			add(p.Line, rules)
+			if col, ok := code[p.Line]; !ok || col > p.Column {
+				// the comment is on its own line, e.g. in the
+				// doc comment of a declaration.
+				add(fset.Position(cg.End()).Line+1, rules)
+			}
+		}
+	}
+	return res
+}
+
+// Parses a comment of the form `//nolint` or `//nolint:rule1,rule2`,
+// optionally followed by an explanation.
+func parseNolint(text string) (rules []string, ok bool) {
+	text, ok = strings.CutPrefix(text, "//nolint")
+	if !ok {
+		return nil, false
+	}
+	if text == "" || text[0] == ' ' {
+		return nil, true
+	}
+	text, ok = strings.CutPrefix(text, ":")
+	if !ok {
+		return nil, false // e.g. //nolintfoo
+	}
+	text, _, _ = strings.Cut(text, " ")
+	for _, rule := range strings.Split(text, ",") {
+		if rule = strings.TrimSpace(rule); rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+	if rules == nil {
+		return nil, false
+	}
+	return rules, true
+}
+
+func isSuppressed(rules []string, rule string) bool {
+	if rules == nil {
+		return false
+	}
+	return len(rules) == 0 || slices.Contains(rules, rule)
+}
@@ -0,0 +1,264 @@
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runSource(t *testing.T, src string, isRealm bool, analyzers ...*Analyzer) []string {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "gno.land/r/test/test.gno", src, parser.ParseComments)
+	require.NoError(t, err)
+	var res []string
+	for _, d := range Run(fset, []*ast.File{f}, "gno.land/r/test", isRealm, analyzers) {
+		res = append(res, fmt.Sprintf("%d: %s (%s)", d.Pos.Line, d.Msg, d.Rule))
+	}
+	return res
+}
+
+func TestCrossingAuth(t *testing.T) {
+	t.Parallel()
+
+	const src = `package test
+
+import (
+	"chain/runtime"
+
+	"gno.land/p/nt/avl/v0"
+)
+
+var (
+	count int
+	tree  avl.Tree
+)
+
+func Inc(cur realm) {
+	count++
+}
+
+func Set(cur realm, k string) {
+	tree.Set(k, k)
+}
+
+func Get(cur realm, k string) any {
+	v, _ := tree.Get(k)
+	return v
+}
+
+func Reset(cur realm) {
+	assertAdmin()
+	count = 0
+}
+
+func assertAdmin() {
+	if runtime.PreviousRealm().Address() != "g1admin" {
+		panic("unauthorized")
+	}
+}
+
+func Dec(cur realm) {
+	if cur.Previous().Address() != "g1admin" {
+		panic("unauthorized")
+	}
+	dec()
+}
+
+func Dec2(cur realm) {
+	dec()
+}
+
+func dec() {
+	count--
+}
+
+func Shadow(cur realm) {
+	count := 0
+	count++
+}
+
+func NotCrossing() {
+	count++
+}
+`
+	assert.Equal(t, []string{
+		"14: exported crossing function Inc modifies the realm state without checking the previous realm (crossing-auth)",
+		"18: exported crossing function Set modifies the realm state without checking the previous realm (crossing-auth)",
+		"45: exported crossing function Dec2 modifies the realm state without checking the previous realm (crossing-auth)",
+	}, runSource(t, src, true, CrossingAuth))
+	assert.Empty(t, runSource(t, src, false, CrossingAuth), "realm only")
+}
+
+func TestBankerSend(t *testing.T) {
+	t.Parallel()
+
+	const src = `package test
+
+import (
+	"chain/banker"
+	"chain/runtime"
+)
+
+func Withdraw(cur realm) {
+	send()
+}
+
+func AdminWithdraw(cur realm) {
+	if runtime.PreviousRealm().Address() != "g1admin" {
+		panic("unauthorized")
+	}
+	adminSend()
+}
+
+func send() {
+	_ = banker.NewBanker(banker.BankerTypeRealmSend)
+}
+
+func adminSend() {
+	_ = banker.NewBanker(banker.BankerTypeRealmSend)
+}
+
+func Balance(cur realm) {
+	_ = banker.NewBanker(banker.BankerTypeReadonly)
+}
+
+func init() {
+	_ = banker.NewBanker(banker.BankerTypeRealmSend)
+}
+`
+	assert.Equal(t, []string{
+		"20: send creates a banker of type BankerTypeRealmSend without checking the previous realm (banker-send)",
+	}, runSource(t, src, true, BankerSend))
+}
+
+func TestRenderIterate(t *testing.T) {
+	t.Parallel()
+
+	const src = `package test
+
+import "gno.land/p/nt/avl/v0"
+
+var tree avl.Tree
+
+func Render(path string) string {
+	tree.Iterate("", "", func(k string, v any) bool { return false })
+	tree.IterateByOffset(0, 10, func(k string, v any) bool { return false })
+	tree.Iterate("a", "b", func(k string, v any) bool { return false })
+	return list()
+}
+
+func list() string {
+	tree.ReverseIterateByOffset(0, tree.Size(), func(k string, v any) bool { return false })
+	return ""
+}
+
+func notRendered() {
+	tree.Iterate("", "", func(k string, v any) bool { return false })
+}
+`
+	assert.Equal(t, []string{
+		"8: unbounded Iterate over an avl tree in Render (render-unbounded-iterate)",
+		"15: unbounded ReverseIterateByOffset over an avl tree in list, called by Render (render-unbounded-iterate)",
+	}, runSource(t, src, true, RenderIterate))
+}
+
+func TestGlobalSliceReturn(t *testing.T) {
+	t.Parallel()
+
+	const src = `package test
+
+var (
+	names  []string
+	ids    = make([]int, 0)
+	values = []int{1, 2}
+	count  int
+)
+
+func Names() []string { return names }
+
+func IDs() []int { return ids[1:] }
+
+func Values() []int {
+	values := []int{3}
+	return values
+}
+
+func Count() int { return count }
+
+func Copy() []int {
+	f := func() []int { return ids }
+	return append([]int(nil), f()...)
+}
+
+func names2() []string { return names }
+`
+	assert.Equal(t, []string{
+		"10: Names returns the package-level slice names; return a copy instead (global-slice-return)",
+		"12: IDs returns the package-level slice ids; return a copy instead (global-slice-return)",
+	}, runSource(t, src, true, GlobalSliceReturn))
+}
+
+func TestNolint(t *testing.T) {
+	t.Parallel()
+
+	const src = `package test
+
+var names []string
+
+func A() []string { return names } //nolint
+
+func B() []string { return names } //nolint:global-slice-return
+
+func C() []string { return names } //nolint:crossing-auth
+
+//nolint:global-slice-return
+func D() []string { return names }
+
+// E returns the names.
+//
+//nolint:global-slice-return because it is a test
+func E() []string { return names }
+
+func F() []string { //nolint
+	return names
+}
+
+func G() []string { return names } //nolintfoo
+`
+	assert.Equal(t, []string{
+		"9: C returns the package-level slice names; return a copy instead (global-slice-return)",
+		"20: F returns the package-level slice names; return a copy instead (global-slice-return)",
+		"23: G returns the package-level slice names; return a copy instead (global-slice-return)",
+	}, runSource(t, src, true, GlobalSliceReturn))
+}
+
+func TestParseNolint(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		text  string
+		rules []string
+		ok    bool
+	}{
+		{"//nolint", nil, true},
+		{"//nolint explanation", nil, true},
+		{"//nolint:a", []string{"a"}, true},
+		{"//nolint:a,b explanation", []string{"a", "b"}, true},
+		{"//nolint:", nil, false},
+		{"//nolintfoo", nil, false},
+		{"// nolint", nil, false},
+		{"// comment", nil, false},
+	}
+	for _, tc := range tt {
+		rules, ok := parseNolint(tc.text)
+		assert.Equal(t, tc.ok, ok, tc.text)
+		assert.Equal(t, tc.rules, rules, tc.text)
+	}
+}
@@ -0,0 +1,81 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// RenderIterate reports iterations over whole avl trees in Render.
+var RenderIterate = &Analyzer{
+	Name: "render-unbounded-iterate",
+	Doc: `Iterating over a whole avl tree in Render, or in a function it calls, gets
+more expensive as the tree grows, until the realm can't be rendered
+anymore. Iterate over a page of the tree instead, e.g. with
+IterateByOffset(offset, count, cb).`,
+	Confidence: 0.8,
+	RealmOnly:  true,
+	Run:        runRenderIterate,
+}
+
+func runRenderIterate(pass *Pass) {
+	idx := pass.pkg()
+	render := idx.byName["Render"]
+	if render == nil {
+		return
+	}
+	for _, fd := range idx.reachable(render) {
+		if fd.Body == nil || !importsAvl(idx.fileOf[fd]) {
+			continue
+		}
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || !isUnboundedIterate(call) {
+				return true
+			}
+			method := call.Fun.(*ast.SelectorExpr).Sel.Name
+			if fd == render {
+				pass.Reportf(call.Pos(), "unbounded %s over an avl tree in Render", method)
+			} else {
+				pass.Reportf(call.Pos(), "unbounded %s over an avl tree in %s, called by Render",
+					method, fd.Name.Name)
+			}
+			return true
+		})
+	}
+}
+
+func importsAvl(f *ast.File) bool {
+	for _, imp := range f.Imports {
+		if defaultImportName(strings.Trim(imp.Path.Value, `"`)) == "avl" {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns whether call iterates over a whole tree, e.g. tree.Iterate("",
+// "", cb) or tree.IterateByOffset(0, tree.Size(), cb).
+func isUnboundedIterate(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) != 3 {
+		return false
+	}
+	switch sel.Sel.Name {
+	case "Iterate", "ReverseIterate":
+		return isEmptyString(call.Args[0]) && isEmptyString(call.Args[1])
+	case "IterateByOffset", "ReverseIterateByOffset":
+		size, ok := call.Args[1].(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		ssel, ok := size.Fun.(*ast.SelectorExpr)
+		return ok && ssel.Sel.Name == "Size" && len(size.Args) == 0
+	}
+	return false
+}
+
+func isEmptyString(x ast.Expr) bool {
+	lit, ok := x.(*ast.BasicLit)
+	return ok && lit.Kind == token.STRING && (lit.Value == `""` || lit.Value == "``")
+}
@@ -5,13 +5,18 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"go/ast"
+	"go/parser"
 	"go/token"
 	"go/types"
 	goio "io"
 	"io/fs"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/gnolang/gno/gnovm/cmd/gno/internal/cmdutil"
+	"github.com/gnolang/gno/gnovm/cmd/gno/internal/lint"
 	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
 	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
 	"github.com/gnolang/gno/gnovm/pkg/gnomod"
@@ -31,6 +36,8 @@ type lintCmd struct {
 	verbose    bool
 	rootDir    string
 	autoGnomod bool
+	format     string
+	strict     bool
 	// min_confidence: minimum confidence of a problem to print it
 	// (default 0.8) auto-fix: apply suggested fixes automatically.
 }
@@ -57,14 +64,26 @@ func (c *lintCmd) RegisterFlags(fs *flag.FlagSet) {
 	fs.BoolVar(&c.verbose, "v", false, "verbose output when lintning")
 	fs.StringVar(&c.rootDir, "root-dir", rootdir, "clone location of github.com/gnolang/gno (gno tries to guess it)")
 	fs.BoolVar(&c.autoGnomod, "auto-gnomod", true, "auto-generate gnomod.toml file if not already present")
+	fs.StringVar(&c.format, "format", lintFormatText, "output format of the issues: text (on stderr), json or sarif (on stdout)")
+	fs.BoolVar(&c.strict, "strict", false, "exit with an error on warnings of the lint rules")
 }
 
-func execLint(cmd *lintCmd, args []string, io commands.IO) error {
+func execLint(cmd *lintCmd, args []string, io commands.IO) (err error) {
 	// Show a help message by default.
 	if len(args) == 0 {
 		return flag.ErrHelp
 	}
 
+	reporter, err := newLintReporter(io, cmd.format)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if ferr := reporter.flush(); ferr != nil && err == nil {
+			err = ferr
+		}
+	}()
+
 	// Guess opts.RootDir.
 	if cmd.rootDir == "" {
 		cmd.rootDir = gnoenv.RootDir()
@@ -84,6 +103,7 @@ func execLint(cmd *lintCmd, args []string, io commands.IO) error {
 	}
 
 	hasError := false
+	hasWarning := false
 
 	prodbs, prodgs := test.StoreWithOptions(
 		cmd.rootDir, goio.Discard,
@@ -163,7 +183,7 @@ func execLint(cmd *lintCmd, args []string, io commands.IO) error {
 				Location:   fpath,
 				Msg:        err.Error(),
 			}
-			io.ErrPrintln(issue)
+			reporter.Report(issue)
 			hasError = true
 			return commands.ExitCodeError(1)
 		}
@@ -174,7 +194,7 @@ func execLint(cmd *lintCmd, args []string, io commands.IO) error {
 		pkgPath, _ := determinePkgPath(mod, dir, cmd.rootDir)
 		mpkg, err := gno.ReadMemPackage(dir, pkgPath, gno.MPAnyAll)
 		if err != nil {
-			printError(io.Err(), dir, pkgPath, err)
+			reporter.ReportError(dir, pkgPath, err)
 			hasError = true
 			continue
 		}
@@ -190,7 +210,7 @@ func execLint(cmd *lintCmd, args []string, io commands.IO) error {
 		// Perform imports using the parent store.
 		abortOnError := true
 		if err := test.LoadImports(testgs, mpkg, abortOnError); err != nil {
-			printError(io.Err(), dir, pkgPath, err)
+			reporter.ReportError(dir, pkgPath, err)
 			hasError = true
 			continue
 		}
@@ -239,7 +259,10 @@ func execLint(cmd *lintCmd, args []string, io commands.IO) error {
 		}
 
 		// Handle runtime errors
-		didPanic := catchPanic(dir, pkgPath, io.Err(), func() {
+		reportPanic := func(err error) {
+			reporter.ReportError(dir, pkgPath, err)
+		}
+		didPanic := catchPanicFunc(reportPanic, func() {
 			// Memo process results here.
 			ppkg := cmdutil.ProcessedPackage{MPkg: mpkg, Dir: dir}
 
@@ -259,7 +282,7 @@ func execLint(cmd *lintCmd, args []string, io commands.IO) error {
 				tcmode = gno.TCLatestRelaxed
 			}
 			tcFset := token.NewFileSet()
-			tcPkg, errs := lintTypeCheck(io, dir, mpkg, gno.TypeCheckOptions{
+			tcPkg, errs := lintTypeCheck(reporter, dir, mpkg, gno.TypeCheckOptions{
 				Getter:     newProdGnoStore(),
 				TestGetter: newTestGnoStore(true),
 				Mode:       tcmode,
@@ -267,19 +290,24 @@ func execLint(cmd *lintCmd, args []string, io commands.IO) error {
 				Fset:       tcFset,
 			})
 			if errs != nil {
-				// io.ErrPrintln(errs) printed above.
+				// errs reported above.
 				hasError = true
 				return
 			}
 
 			// ensure the 'Render' function is correct
-			err = lintRenderSignature(io, tcPkg, tcFset)
+			err = lintRenderSignature(reporter, tcPkg, tcFset)
 			if err != nil {
-				// io.ErrPrintln(err) printed above.
+				// err reported above.
 				hasError = true
 				return
 			}
 
+			// Run the lint rules, see internal/lint.
+			if lintRules(reporter, dir, mpkg) {
+				hasWarning = true
+			}
+
 			// Construct machine for testing.
 			tm := test.Machine(newProdGnoStore(), goio.Discard, pkgPath, false, nil)
 			defer tm.Release()
@@ -325,7 +353,7 @@ func execLint(cmd *lintCmd, args []string, io commands.IO) error {
 					pkgPath := fmt.Sprintf("%s_filetest%d", mpkg.Path, i)
 					pkgPath, err = parsePkgPathDirective(mfile.Body, pkgPath)
 					if err != nil {
-						io.ErrPrintln(err)
+						reporter.ReportError(dir, pkgPath, err)
 						hasError = true
 						continue
 					}
@@ -342,7 +370,7 @@ func execLint(cmd *lintCmd, args []string, io commands.IO) error {
 			hasError = true
 		}
 	}
-	if hasError {
+	if hasError || (hasWarning && cmd.strict) {
 		return commands.ExitCodeError(1)
 	}
 
@@ -371,11 +399,11 @@ func execLint(cmd *lintCmd, args []string, io commands.IO) error {
 	return nil
 }
 
-// Wrapper around TypeCheckMemPackage() to io.ErrPrintln(gnoIssue{}).
-// Prints and returns errors. Panics upon an unexpected error.
+// Wrapper around TypeCheckMemPackage() to report gnoIssue{}.
+// Reports and returns errors. Panics upon an unexpected error.
 func lintTypeCheck(
 	// Args:
-	io commands.IO,
+	reporter *lintReporter,
 	dir string,
 	mpkg *std.MemPackage,
 	opts gno.TypeCheckOptions) (
@@ -389,7 +417,7 @@ func lintTypeCheck(
 	// Print errors, and return the first unexpected error.
 	errors := multierr.Errors(tcErrs)
 	for _, err := range errors {
-		printError(io.Err(), dir, mpkg.Path, err)
+		reporter.ReportError(dir, mpkg.Path, err)
 	}
 
 	lerr = tcErrs
@@ -406,7 +434,7 @@ func lintTargetName(pkg *packages.Package) string {
 
 // lintRenderSignature checks if a Render function in the package has the expected signature
 // Returns error if the signature is incorrect.
-func lintRenderSignature(io commands.IO, pkg *types.Package, fset *token.FileSet) error {
+func lintRenderSignature(reporter *lintReporter, pkg *types.Package, fset *token.FileSet) error {
 	// ignore pure package and ephemeral realms
 	if pkg == nil || !gno.IsRealmPath(pkg.Path()) {
 		return nil
@@ -446,7 +474,7 @@ func lintRenderSignature(io commands.IO, pkg *types.Package, fset *token.FileSet
 		}
 
 		err := fmt.Errorf("invalid signature for the realm's Render function; must be of the form: func Render(string) string")
-		fmt.Fprintln(io.Err(), gnoIssue{
+		reporter.Report(gnoIssue{
 			Code:       gnoLintError,
 			Msg:        err.Error(),
 			Confidence: 1,
@@ -457,3 +485,36 @@ func lintRenderSignature(io commands.IO, pkg *types.Package, fset *token.FileSet
 
 	return nil
 }
+
+// lintRules runs the rules of internal/lint on the non-test files of mpkg,
+// and reports their diagnostics as warnings. Returns whether any was
+// reported.
+func lintRules(reporter *lintReporter, dir string, mpkg *std.MemPackage) bool {
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, mfile := range mpkg.Files {
+		if !strings.HasSuffix(mfile.Name, ".gno") || gno.IsTestFile(mfile.Name) {
+			continue
+		}
+		fpath := path.Join(mpkg.Path, mfile.Name)
+		f, err := parser.ParseFile(fset, fpath, mfile.Body, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			// already reported by the type checker.
+			return false
+		}
+		files = append(files, f)
+	}
+
+	diags := lint.Run(fset, files, mpkg.Path, gno.IsRealmPath(mpkg.Path), lint.Analyzers)
+	for _, d := range diags {
+		loc := fmt.Sprintf("%s:%d:%d", d.Pos.Filename, d.Pos.Line, d.Pos.Column)
+		reporter.Report(gnoIssue{
+			Code:       gnoLintWarning,
+			Rule:       d.Rule,
+			Msg:        d.Msg,
+			Confidence: d.Confidence,
+			Location:   guessFilePathLocRel(loc, mpkg.Path, dir),
+		})
+	}
+	return len(diags) > 0
+}
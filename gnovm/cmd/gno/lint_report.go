@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/gnovm/cmd/gno/internal/lint"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+)
+
+// Output formats of `gno lint`.
+const (
+	lintFormatText  = "text"
+	lintFormatJSON  = "json"
+	lintFormatSARIF = "sarif"
+)
+
+// lintReporter collects the issues found by `gno lint`. Issues are printed
+// to stderr as they are found in the text format, and otherwise written to
+// stdout at once by flush.
+type lintReporter struct {
+	io     commands.IO
+	format string
+	issues []gnoIssue
+}
+
+func newLintReporter(io commands.IO, format string) (*lintReporter, error) {
+	switch format {
+	case lintFormatText, lintFormatJSON, lintFormatSARIF:
+	default:
+		return nil, fmt.Errorf("invalid lint output format %q; must be one of text, json or sarif", format)
+	}
+	return &lintReporter{io: io, format: format}, nil
+}
+
+func (r *lintReporter) Report(issue gnoIssue) {
+	if r.format == lintFormatText {
+		r.io.ErrPrintln(issue)
+		return
+	}
+	r.issues = append(r.issues, issue)
+}
+
+// ReportError reports the issues of err, like printError.
+func (r *lintReporter) ReportError(dir, pkgPath string, err error) {
+	for _, issue := range issuesFromError(dir, pkgPath, err) {
+		r.Report(issue)
+	}
+}
+
+func (r *lintReporter) flush() error {
+	var v any
+	switch r.format {
+	case lintFormatJSON:
+		if r.issues == nil {
+			r.issues = []gnoIssue{}
+		}
+		v = r.issues
+	case lintFormatSARIF:
+		v = newSARIFLog(r.issues)
+	default:
+		return nil
+	}
+	bz, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	r.io.Println(string(bz))
+	return nil
+}
+
+// ----------------------------------------
+// SARIF
+
+// A minimal SARIF 2.1.0 log, as understood by most CI code scanning tools.
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription sarifMessage  `json:"shortDescription"`
+	FullDescription  *sarifMessage `json:"fullDescription,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+func newSARIFLog(issues []gnoIssue) sarifLog {
+	// all lint rules, and the codes of the other issues.
+	var rules []sarifRule
+	for _, a := range lint.Analyzers {
+		doc := strings.Join(strings.Fields(a.Doc), " ")
+		short, _, _ := strings.Cut(doc, ". ")
+		rules = append(rules, sarifRule{
+			ID:               a.Name,
+			ShortDescription: sarifMessage{Text: strings.TrimSuffix(short, ".") + "."},
+			FullDescription:  &sarifMessage{Text: doc},
+		})
+	}
+	seen := map[string]bool{}
+	results := make([]sarifResult, 0, len(issues))
+	for _, issue := range issues {
+		res := sarifResult{
+			RuleID:  issue.Rule,
+			Level:   "warning",
+			Message: sarifMessage{Text: issue.Msg},
+		}
+		if issue.Rule == "" {
+			res.RuleID = string(issue.Code)
+			res.Level = "error"
+			if !seen[res.RuleID] {
+				seen[res.RuleID] = true
+				rules = append(rules, sarifRule{
+					ID:               res.RuleID,
+					ShortDescription: sarifMessage{Text: res.RuleID},
+				})
+			}
+		}
+		file, line, col := parseIssueLocation(issue.Location)
+		loc := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: file}}
+		if line > 0 {
+			loc.Region = &sarifRegion{StartLine: line, StartColumn: col}
+		}
+		res.Locations = []sarifLocation{{PhysicalLocation: loc}}
+		results = append(results, res)
+	}
+	return sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "gno lint",
+				InformationURI: "https://github.com/gnolang/gno",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+// e.g. main.gno:4:6, main.gno:4:6-22, or gno.land/r/demo/foo/foo.gno:5.
+var reIssueLocation = regexp.MustCompile(`^(.+?):(\d+)(?::(\d+))?(?:-[\d:]+)?$`)
+
+// Splits the location of an issue in its file, line and column. The line
+// and the column are 0 if unknown.
+func parseIssueLocation(loc string) (file string, line, col int) {
+	m := reIssueLocation.FindStringSubmatch(loc)
+	if m == nil {
+		return loc, 0, 0
+	}
+	line, _ = strconv.Atoi(m[2])
+	col, _ = strconv.Atoi(m[3])
+	return m[1], line, col
+}
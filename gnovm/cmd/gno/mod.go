@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	goerrors "errors"
 	"flag"
 	"fmt"
 	"os"
@@ -41,7 +42,7 @@ func newModCmd(io commands.IO) *commands.Command {
 		newModInitCmd(),
 		newModTidy(io),
 		// vendor
-		// verify
+		newModVerifyCmd(io),
 		newModWhy(io),
 	)
 
@@ -109,6 +110,28 @@ func newModTidy(io commands.IO) *commands.Command {
 	)
 }
 
+func newModVerifyCmd(io commands.IO) *commands.Command {
+	cfg := &modDownloadCfg{}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "verify",
+			ShortUsage: "verify [flags]",
+			ShortHelp:  "verify locked modules against their remote",
+			LongHelp: `Verifies that the remote packages locked in gno.lock have not changed.
+
+gno mod verify fetches again every package listed in the gno.lock file of the
+current workspace or module, and checks that its chain ID, add height and
+content hash are the ones recorded by gno mod download or gno mod tidy.
+`,
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execModVerify(cfg, args, io)
+		},
+	)
+}
+
 func newModWhy(io commands.IO) *commands.Command {
 	return commands.NewCommand(
 		commands.Metadata{
@@ -237,17 +260,115 @@ func execModDownload(cfg *modDownloadCfg, args []string, io commands.IO) error {
 		return flag.ErrHelp
 	}
 
-	fetcher := testPackageFetcher
-	if fetcher == nil {
-		remoteOverrides, err := parseRemoteOverrides(cfg.remoteOverrides)
-		if err != nil {
-			return fmt.Errorf("invalid %s flag: %w", remoteOverridesArgName, err)
+	fetcher, err := newModFetcher(cfg.remoteOverrides)
+	if err != nil {
+		return err
+	}
+
+	pkgs, err := loadModDeps(fetcher, io)
+	if err != nil {
+		return err
+	}
+
+	return syncLockFile(pkgs, fetcher, false)
+}
+
+func execModVerify(cfg *modDownloadCfg, args []string, io commands.IO) error {
+	if len(args) > 0 {
+		return flag.ErrHelp
+	}
+
+	fetcher, err := newModFetcher(cfg.remoteOverrides)
+	if err != nil {
+		return err
+	}
+
+	root, err := packages.RootDir()
+	if err != nil {
+		return err
+	}
+	lf, err := gnomod.ReadLockFile(filepath.Join(root, gnomod.LockFileName))
+	if err != nil {
+		return err
+	}
+
+	errCount := uint(0)
+	for _, locked := range lf.Packages {
+		if err := verifyLockedPackage(locked, fetcher); err != nil {
+			io.ErrPrintfln("%s: %v", locked.Path, err)
+			errCount++
+		}
+	}
+	if errCount != 0 {
+		return fmt.Errorf("%d package(s) differ from %s", errCount, gnomod.LockFileName)
+	}
+
+	io.Println("all packages verified")
+	return nil
+}
+
+// verifyLockedPackage fetches the locked package from its remote and compares
+// it with its lockfile entry.
+func verifyLockedPackage(locked gnomod.LockedPackage, fetcher pkgdownload.PackageFetcher) error {
+	files, err := fetcher.FetchPackage(locked.Path)
+	if err != nil {
+		return err
+	}
+
+	chainID, err := fetchChainID(locked.Path, fetcher)
+	if err != nil {
+		return err
+	}
+
+	remote, err := gnomod.NewLockedPackage(locked.Path, chainID, files)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case remote.ChainID != locked.ChainID:
+		return fmt.Errorf("chain ID mismatch\n\tremote:   %s\n\t%s: %s", remote.ChainID, gnomod.LockFileName, locked.ChainID)
+	case remote.Height != locked.Height:
+		return fmt.Errorf("height mismatch\n\tremote:   %d\n\t%s: %d", remote.Height, gnomod.LockFileName, locked.Height)
+	case remote.Hash != locked.Hash:
+		return fmt.Errorf("checksum mismatch\n\tremote:   %s\n\t%s: %s", remote.Hash, gnomod.LockFileName, locked.Hash)
+	}
+	return nil
+}
+
+// newModFetcher returns the package fetcher used by the mod commands.
+func newModFetcher(remoteOverridesArg string) (pkgdownload.PackageFetcher, error) {
+	if testPackageFetcher != nil {
+		if len(remoteOverridesArg) != 0 {
+			return nil, fmt.Errorf("can't use %s flag with a custom package fetcher", remoteOverridesArgName)
 		}
-		fetcher = rpcpkgfetcher.New(remoteOverrides)
-	} else if len(cfg.remoteOverrides) != 0 {
-		return fmt.Errorf("can't use %s flag with a custom package fetcher", remoteOverridesArgName)
+		return testPackageFetcher, nil
 	}
 
+	remoteOverrides, err := parseRemoteOverrides(remoteOverridesArg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s flag: %w", remoteOverridesArgName, err)
+	}
+	return rpcpkgfetcher.New(remoteOverrides), nil
+}
+
+// fetchChainID returns the ID of the chain the package at pkgPath is fetched
+// from, or an empty string if the fetcher doesn't know it.
+func fetchChainID(pkgPath string, fetcher pkgdownload.PackageFetcher) (string, error) {
+	cf, ok := fetcher.(pkgdownload.ChainIDFetcher)
+	if !ok {
+		return "", nil
+	}
+	chainID, err := cf.ChainID(pkgPath)
+	if err != nil {
+		return "", fmt.Errorf("get chain ID: %w", err)
+	}
+	return chainID, nil
+}
+
+// loadModDeps loads the packages of the current workspace or module along with
+// their dependencies, downloading the missing ones to the modcache.
+func loadModDeps(fetcher pkgdownload.PackageFetcher, io commands.IO) (packages.PkgList, error) {
 	loadCfg := packages.LoadConfig{
 		Fetcher:    fetcher,
 		Deps:       true,
@@ -257,7 +378,7 @@ func execModDownload(cfg *modDownloadCfg, args []string, io commands.IO) error {
 	}
 	pkgs, err := packages.Load(loadCfg, "./...")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	errCount := uint(0)
@@ -268,10 +389,78 @@ func execModDownload(cfg *modDownloadCfg, args []string, io commands.IO) error {
 		}
 	}
 	if errCount != 0 {
-		return fmt.Errorf("%d build error(s)", errCount)
+		return nil, fmt.Errorf("%d build error(s)", errCount)
 	}
 
-	return nil
+	return pkgs, nil
+}
+
+// syncLockFile verifies the downloaded dependencies in pkgs against the
+// gno.lock file of the current workspace or module, and locks the ones that
+// are not in it yet. If prune is set, the entries of the packages that are no
+// longer dependencies are removed.
+func syncLockFile(pkgs packages.PkgList, fetcher pkgdownload.PackageFetcher, prune bool) error {
+	root, err := packages.RootDir()
+	if err != nil {
+		return err
+	}
+	lockPath := filepath.Join(root, gnomod.LockFileName)
+	lf, err := gnomod.ReadLockFile(lockPath)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	used := make(map[string]struct{})
+	var errs error
+	for _, pkg := range pkgs {
+		// only lock the packages downloaded to the modcache
+		if pkg.ImportPath == "" || pkg.Dir != packages.PackageDir(pkg.ImportPath) {
+			continue
+		}
+		used[pkg.ImportPath] = struct{}{}
+
+		files, err := packages.ReadCachedPackage(pkg.ImportPath)
+		if err != nil {
+			return err
+		}
+
+		if locked, ok := lf.Get(pkg.ImportPath); ok {
+			hash, err := gnomod.HashFiles(files)
+			if err != nil {
+				return fmt.Errorf("hash package %q: %w", pkg.ImportPath, err)
+			}
+			if hash != locked.Hash {
+				errs = multierr.Append(errs, fmt.Errorf(
+					"%s: checksum mismatch\n\tdownloaded: %s\n\t%s:   %s",
+					pkg.ImportPath, hash, gnomod.LockFileName, locked.Hash,
+				))
+			}
+			continue
+		}
+
+		chainID, err := fetchChainID(pkg.ImportPath, fetcher)
+		if err != nil {
+			return fmt.Errorf("lock package %q: %w", pkg.ImportPath, err)
+		}
+		lp, err := gnomod.NewLockedPackage(pkg.ImportPath, chainID, files)
+		if err != nil {
+			return err
+		}
+		lf.Set(lp)
+		changed = true
+	}
+	if errs != nil {
+		return errs
+	}
+
+	if prune && len(lf.Prune(used)) != 0 {
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return lf.WriteFile(lockPath)
 }
 
 func parseRemoteOverrides(arg string) (map[string]string, error) {
@@ -331,8 +520,9 @@ func execModInit(args []string) error {
 }
 
 type modTidyCfg struct {
-	verbose   bool
-	recursive bool
+	verbose         bool
+	recursive       bool
+	remoteOverrides string
 }
 
 func (c *modTidyCfg) RegisterFlags(fs *flag.FlagSet) {
@@ -348,6 +538,12 @@ func (c *modTidyCfg) RegisterFlags(fs *flag.FlagSet) {
 		false,
 		"walk subdirs for gno.mod files",
 	)
+	fs.StringVar(
+		&c.remoteOverrides,
+		remoteOverridesArgName,
+		"",
+		"chain-domain=rpc-url comma-separated list",
+	)
 }
 
 func execModTidy(cfg *modTidyCfg, args []string, io commands.IO) error {
@@ -370,11 +566,43 @@ func execModTidy(cfg *modTidyCfg, args []string, io commands.IO) error {
 			err := modTidyOnce(cfg, wd, pkg.Dir, io)
 			errs = multierr.Append(errs, err)
 		}
-		return errs
+		if errs != nil {
+			return errs
+		}
+		return modTidyLock(cfg, wd, io)
 	}
 
 	// XXX: recursively check parents if no $PWD/gno.mod
-	return modTidyOnce(cfg, wd, wd, io)
+	if err := modTidyOnce(cfg, wd, wd, io); err != nil {
+		return err
+	}
+	return modTidyLock(cfg, wd, io)
+}
+
+// modTidyLock updates the gno.lock file of the current workspace or module.
+// Unused entries are only pruned when run from its root, as the dependencies
+// of the packages outside of wd are not loaded.
+func modTidyLock(cfg *modTidyCfg, wd string, io commands.IO) error {
+	root, err := packages.RootDir()
+	if goerrors.Is(err, packages.ErrGnoContextNotFound) {
+		// not in a workspace nor a module, nothing to lock
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fetcher, err := newModFetcher(cfg.remoteOverrides)
+	if err != nil {
+		return err
+	}
+
+	pkgs, err := loadModDeps(fetcher, io)
+	if err != nil {
+		return err
+	}
+
+	return syncLockFile(pkgs, fetcher, root == wd)
 }
 
 func modTidyOnce(cfg *modTidyCfg, wd, pkgdir string, io commands.IO) error {
@@ -1,7 +1,16 @@
 package main
 
 import (
+	"context"
+	"path/filepath"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnomod"
+	"github.com/gnolang/gno/gnovm/pkg/packages/pkgdownload/examplespkgfetcher"
+	"github.com/gnolang/gno/tm2/pkg/commands"
 )
 
 func TestModApp(t *testing.T) {
@@ -72,6 +81,13 @@ func TestModApp(t *testing.T) {
 			simulateExternalRepo: true,
 			stderrShouldContain:  "gno: downloading gno.land/p/nt/avl/v0",
 		},
+		{
+			args:                 []string{"mod", "download"},
+			testDir:              "../../tests/integ/locked_remote_module",
+			simulateExternalRepo: true,
+			stderrShouldContain:  "gno: downloading gno.land/p/nt/avl/v0",
+			errShouldContain:     "gno.land/p/nt/avl/v0: checksum mismatch",
+		},
 		// TODO: that functionality is not available on gnomod.toml anymore. should we remove this?
 		// {
 		// 	args:                 []string{"mod", "download"},
@@ -131,11 +147,41 @@ func TestModApp(t *testing.T) {
 			args:                 []string{"mod", "tidy"},
 			testDir:              "../../tests/integ/require_remote_module",
 			simulateExternalRepo: true,
+			stderrShouldContain:  "gno: downloading gno.land/p/nt/avl/v0",
 		},
 		{
 			args:                 []string{"mod", "tidy"},
 			testDir:              "../../tests/integ/valid2",
 			simulateExternalRepo: true,
+			stderrShouldContain:  "gno: downloading gno.land/p/nt/avl/v0",
+		},
+		{
+			args:                 []string{"mod", "tidy"},
+			testDir:              "../../tests/integ/locked_remote_module",
+			simulateExternalRepo: true,
+			stderrShouldContain:  "gno: downloading gno.land/p/nt/avl/v0",
+			errShouldContain:     "gno.land/p/nt/avl/v0: checksum mismatch",
+		},
+
+		// test `gno mod verify`
+		{
+			args:                 []string{"mod", "verify", "arg1"},
+			testDir:              "../../tests/integ/minimalist_gnomod",
+			simulateExternalRepo: true,
+			errShouldContain:     "flag: help requested",
+		},
+		{
+			args:                 []string{"mod", "verify"},
+			testDir:              "../../tests/integ/minimalist_gnomod",
+			simulateExternalRepo: true,
+			stdoutShouldBe:       "all packages verified\n",
+		},
+		{
+			args:                 []string{"mod", "verify"},
+			testDir:              "../../tests/integ/locked_remote_module",
+			simulateExternalRepo: true,
+			stderrShouldContain:  "gno.land/p/nt/avl/v0: checksum mismatch",
+			errShouldBe:          "1 package(s) differ from gno.lock",
 		},
 
 		// test `gno mod why`
@@ -245,3 +291,45 @@ gno.land/p/nt/avl/v0 testing
 
 	testMainCaseRun(t, tc)
 }
+
+func TestModLockFile(t *testing.T) {
+	t.Setenv("GNOHOME", t.TempDir())
+	src, err := filepath.Abs("../../tests/integ/require_remote_module")
+	require.NoError(t, err)
+	dir := t.TempDir()
+	require.NoError(t, copyDir(src, dir))
+	t.Chdir(dir)
+
+	testPackageFetcher = examplespkgfetcher.New("")
+	run := func(args ...string) error {
+		cmd, _ := newGnocliCmd(commands.NewTestIO())
+		return cmd.ParseAndRun(context.Background(), args)
+	}
+	lockPath := filepath.Join(dir, gnomod.LockFileName)
+
+	// download locks the remote dependencies
+	require.NoError(t, run("mod", "download"))
+	lf, err := gnomod.ReadLockFile(lockPath)
+	require.NoError(t, err)
+	files, err := testPackageFetcher.FetchPackage("gno.land/p/nt/avl/v0")
+	require.NoError(t, err)
+	expected, err := gnomod.NewLockedPackage("gno.land/p/nt/avl/v0", "", files)
+	require.NoError(t, err)
+	assert.Equal(t, []gnomod.LockedPackage{expected}, lf.Packages)
+	require.NoError(t, run("mod", "verify"))
+
+	// tidy prunes the entries of the packages that are no longer needed
+	lf.Set(gnomod.LockedPackage{Path: "gno.land/p/demo/unused", Hash: "h1:unused"})
+	require.NoError(t, lf.WriteFile(lockPath))
+	require.NoError(t, run("mod", "tidy"))
+	lf, err = gnomod.ReadLockFile(lockPath)
+	require.NoError(t, err)
+	_, ok := lf.Get("gno.land/p/demo/unused")
+	assert.False(t, ok)
+	assert.Equal(t, []gnomod.LockedPackage{expected}, lf.Packages)
+
+	// verify detects the remote packages that differ from the lockfile
+	lf.Packages[0].Height = 42
+	require.NoError(t, lf.WriteFile(lockPath))
+	require.EqualError(t, run("mod", "verify"), "1 package(s) differ from gno.lock")
+}
@@ -18,11 +18,13 @@ import (
 )
 
 type runCmd struct {
-	verbose   bool
-	rootDir   string
-	expr      string
-	debug     bool
-	debugAddr string
+	verbose    bool
+	rootDir    string
+	expr       string
+	debug      bool
+	debugAddr  string
+	debugDAP   string
+	gasProfile string
 }
 
 func newRunCmd(cio commands.IO) *commands.Command {
@@ -76,6 +78,20 @@ func (c *runCmd) RegisterFlags(fs *flag.FlagSet) {
 		"",
 		"enable interactive debugger using tcp address in the form [host]:port",
 	)
+
+	fs.StringVar(
+		&c.debugDAP,
+		"debug-dap",
+		"",
+		"enable debugger for a Debug Adapter Protocol client (such as an IDE) using tcp address in the form [host]:port",
+	)
+
+	fs.StringVar(
+		&c.gasProfile,
+		"gasprofile",
+		"",
+		"write a gas profile in pprof format to the file",
+	)
 }
 
 func packageNameFromFiles(args []string) (string, error) {
@@ -201,6 +217,10 @@ func execRun(cfg *runCmd, args []string, cio commands.IO) error {
 		return err
 	}
 	ctx := test.Context("", pkgPath, send)
+	var gasProfile *gno.GasProfile
+	if cfg.gasProfile != "" {
+		gasProfile = gno.NewGasProfile()
+	}
 	m := gno.NewMachineWithOptions(gno.MachineOptions{
 		PkgPath:       pkgPath,
 		Output:        output,
@@ -208,7 +228,8 @@ func execRun(cfg *runCmd, args []string, cio commands.IO) error {
 		Store:         testStore,
 		MaxAllocBytes: maxAllocRun,
 		Context:       ctx,
-		Debug:         cfg.debug || cfg.debugAddr != "",
+		Debug:         cfg.debug || cfg.debugAddr != "" || cfg.debugDAP != "",
+		GasProfile:    gasProfile,
 	})
 
 	defer m.Release()
@@ -230,9 +251,34 @@ func execRun(cfg *runCmd, args []string, cio commands.IO) error {
 		}
 	}
 
+	// If the DAP address is set, the debugger waits for a DAP client to connect to it.
+	var dap *gno.DAPSession
+	if cfg.debugDAP != "" {
+		dap, err = gno.ListenDAP(cfg.debugDAP)
+		if err != nil {
+			return err
+		}
+		m.Debugger.EnableDAP(dap, nil)
+	}
+
 	// run files
 	m.RunFiles(files...)
-	return runExpr(m, cfg.expr)
+	err = runExpr(m, cfg.expr)
+	if dap != nil {
+		exitCode := 0
+		if err != nil {
+			exitCode = 1
+		}
+		dap.Close(exitCode)
+	}
+	if gasProfile != nil {
+		// The files are named after the arguments.
+		pkgDirs := map[string]string{pkgPath: ""}
+		if werr := writeGasProfile(gasProfile, cfg.gasProfile, cfg.rootDir, pkgDirs); werr != nil {
+			return fmt.Errorf("unable to write gas profile: %w", werr)
+		}
+	}
+	return err
 }
 
 func parseFiles(m *gno.Machine, fpaths []string, stderr io.WriteCloser) ([]*gno.FileNode, error) {
@@ -10,6 +10,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -33,6 +34,12 @@ type testCmd struct {
 	printEvents         bool
 	debug               bool
 	debugAddr           string
+	debugDAP            string
+	cover               bool
+	coverProfile        string
+	gasProfile          string
+	fuzz                string
+	fuzzTime            string
 }
 
 func newTestCmd(io commands.IO) *commands.Command {
@@ -51,9 +58,15 @@ file pattern "*_test.gno" or "*_filetest.gno".
 The <package> can be directory or file path (relative or absolute).
 
 - "*_test.gno" files work like "*_test.go" files, but they contain only test
-functions. Benchmark and fuzz functions aren't supported yet. Similarly, only
+and fuzz functions. Benchmark functions aren't supported yet. Similarly, only
 tests that belong to the same package are supported for now (no "xxx_test").
 
+Fuzz functions, of the form "func FuzzXxx(f *testing.F)", are run on their
+seed corpus (added with f.Add) and on the inputs saved in "testdata/fuzz/FuzzXxx".
+With the -fuzz flag, the selected fuzz function is also run with inputs
+generated by mutating its corpus; failing inputs are minimized and saved in
+"testdata/fuzz/FuzzXxx", so that they are run by later invocations of 'gno test'.
+
 The package path used to execute the "*_test.gno" file is fetched from the
 module name found in 'gno.mod', or else it is set to
 "gno.land/r/txtar".
@@ -179,6 +192,48 @@ func (c *testCmd) RegisterFlags(fs *flag.FlagSet) {
 		"",
 		"enable interactive debugger using tcp address in the form [host]:port",
 	)
+
+	fs.StringVar(
+		&c.debugDAP,
+		"debug-dap",
+		"",
+		"enable debugger for a Debug Adapter Protocol client (such as an IDE) using tcp address in the form [host]:port",
+	)
+
+	fs.BoolVar(
+		&c.cover,
+		"cover",
+		false,
+		"enable coverage analysis",
+	)
+
+	fs.StringVar(
+		&c.coverProfile,
+		"coverprofile",
+		"",
+		"write a coverage profile to the file (implies -cover)",
+	)
+
+	fs.StringVar(
+		&c.gasProfile,
+		"gasprofile",
+		"",
+		"write a gas profile of the tests in pprof format to the file",
+	)
+
+	fs.StringVar(
+		&c.fuzz,
+		"fuzz",
+		"",
+		"run the fuzz test matching the regular expression, generating new inputs",
+	)
+
+	fs.StringVar(
+		&c.fuzzTime,
+		"fuzztime",
+		"",
+		"time spent fuzzing (e.g. 30s), or number of generated inputs (e.g. 1000x); default 10s",
+	)
 }
 
 func execTest(cmd *testCmd, args []string, io commands.IO) error {
@@ -209,6 +264,18 @@ func execTest(cmd *testCmd, args []string, io commands.IO) error {
 		return nil
 	}
 
+	if cmd.fuzz != "" {
+		matched := 0
+		for _, pkg := range pkgs {
+			if len(pkg.Match) != 0 {
+				matched++
+			}
+		}
+		if matched > 1 {
+			return errors.New("cannot use -fuzz flag with multiple packages")
+		}
+	}
+
 	if cmd.timeout > 0 {
 		go func() {
 			time.Sleep(cmd.timeout)
@@ -229,6 +296,18 @@ func execTest(cmd *testCmd, args []string, io commands.IO) error {
 	opts.Events = cmd.printEvents
 	opts.Debug = cmd.debug
 	opts.FailfastFlag = cmd.failfast
+	if cmd.cover || cmd.coverProfile != "" {
+		opts.Coverage = gno.NewCoverage()
+	}
+	if cmd.gasProfile != "" {
+		opts.GasProfile = gno.NewGasProfile()
+	}
+	opts.FuzzFlag = cmd.fuzz
+	if err := parseFuzzTime(cmd.fuzzTime, opts); err != nil {
+		return err
+	}
+	// Directory of each tested package, used to write the cover profile.
+	pkgDirs := make(map[string]string)
 	cache := make(gno.TypeCheckCache, 64)
 
 	// test.ProdStore() is suitable for type-checking prod (non-test) files.
@@ -236,6 +315,23 @@ func execTest(cmd *testCmd, args []string, io commands.IO) error {
 
 	buildErrCount := 0
 	testErrCount := 0
+
+	if cmd.debugDAP != "" {
+		dap, err := gno.ListenDAP(cmd.debugDAP)
+		if err != nil {
+			return err
+		}
+		opts.Debug = true
+		opts.DebugDAP = dap
+		defer func() {
+			exitCode := 0
+			if testErrCount > 0 || buildErrCount > 0 {
+				exitCode = 1
+			}
+			dap.Close(exitCode)
+		}()
+	}
+
 	fail := func() error {
 		io.ErrPrintfln("FAIL")
 		return fmt.Errorf("FAIL: %d build errors, %d test errors", buildErrCount, testErrCount)
@@ -303,13 +399,19 @@ func execTest(cmd *testCmd, args []string, io commands.IO) error {
 			io.ErrPrintfln("WARNING: unable to read package path from gno.mod or gno root directory; try creating a gno.mod file")
 		}
 
+		pkgDirs[pkgPath] = pkg.Dir
+		if opts.DebugDAP != nil {
+			opts.DebugDAP.AddPackageDir(pkgPath, pkg.Dir)
+		}
+
 		// Read MemPackage with all files.
 		mpkg := gno.MustReadMemPackage(pkg.Dir, pkgPath, gno.MPAnyAll)
 		var didPanic, didError bool
 		startedAt := time.Now()
 		didPanic = catchPanic(pkg.Dir, pkgPath, io.Err(), func() {
 			if mod == nil || !mod.Ignore {
-				_, errs := lintTypeCheck(io, pkg.Dir, mpkg, gno.TypeCheckOptions{
+				reporter := &lintReporter{io: io, format: lintFormatText}
+				_, errs := lintTypeCheck(reporter, pkg.Dir, mpkg, gno.TypeCheckOptions{
 					Getter:     opts.TestStore,
 					TestGetter: opts.TestStore,
 					Mode:       gno.TCLatestRelaxed,
@@ -338,6 +440,9 @@ func execTest(cmd *testCmd, args []string, io commands.IO) error {
 		// Print status with duration.
 		duration := time.Since(startedAt)
 		dstr := fmtDuration(duration)
+		if opts.Coverage != nil {
+			dstr += "\t" + fmtCoverage(opts.Coverage, pkgPath)
+		}
 		if didPanic || didError {
 			io.ErrPrintfln("FAIL    %s \t%s", prettyDir, dstr)
 			testErrCount++
@@ -348,6 +453,17 @@ func execTest(cmd *testCmd, args []string, io commands.IO) error {
 			io.ErrPrintfln("ok      %s \t%s", prettyDir, dstr)
 		}
 	}
+	if cmd.coverProfile != "" {
+		if err := writeCoverProfile(opts.Coverage, cmd.coverProfile, pkgDirs); err != nil {
+			return fmt.Errorf("unable to write cover profile: %w", err)
+		}
+	}
+	if cmd.gasProfile != "" {
+		if err := writeGasProfile(opts.GasProfile, cmd.gasProfile, cmd.rootDir, pkgDirs); err != nil {
+			return fmt.Errorf("unable to write gas profile: %w", err)
+		}
+	}
+
 	if testErrCount > 0 || buildErrCount > 0 {
 		return fail()
 	}
@@ -355,6 +471,96 @@ func execTest(cmd *testCmd, args []string, io commands.IO) error {
 	return nil
 }
 
+// parseFuzzTime parses the value of -fuzztime, which is either a duration or
+// a number of iterations suffixed with "x".
+func parseFuzzTime(s string, opts *test.TestOptions) error {
+	if s == "" {
+		return nil
+	}
+	if n, ok := strings.CutSuffix(s, "x"); ok {
+		iters, err := strconv.Atoi(n)
+		if err != nil || iters <= 0 {
+			return fmt.Errorf("invalid -fuzztime %q: number of iterations must be a positive integer", s)
+		}
+		opts.FuzzIterations = iters
+		return nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("invalid -fuzztime %q: must be a positive duration or a number of iterations (e.g. 100x)", s)
+	}
+	opts.FuzzTime = d
+	return nil
+}
+
+func fmtCoverage(cov *gno.Coverage, pkgPath string) string {
+	covered, total := cov.Stats(pkgPath)
+	if total == 0 {
+		return "coverage: [no statements]"
+	}
+	return fmt.Sprintf("coverage: %.1f%% of statements", 100*float64(covered)/float64(total))
+}
+
+// writeCoverProfile writes cov to the given file. Files are referred to by
+// their absolute path, so that the profile can be used by `go tool cover`.
+func writeCoverProfile(cov *gno.Coverage, fname string, pkgDirs map[string]string) error {
+	f, err := os.Create(fname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fileName := func(pkgPath, file string) string {
+		p := filepath.Join(pkgDirs[pkgPath], file)
+		if abs, err := filepath.Abs(p); err == nil {
+			return abs
+		}
+		return p
+	}
+	if err := cov.WriteProfile(f, fileName); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// writeGasProfile writes prof to the given file. Files are referred to by
+// their absolute path when their directory is known: either the directory of
+// a package in pkgDirs, or the standard libraries and examples of rootDir.
+func writeGasProfile(prof *gno.GasProfile, fname, rootDir string, pkgDirs map[string]string) error {
+	f, err := os.Create(fname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fileName := func(pkgPath, file string) string {
+		dir, ok := pkgDirs[pkgPath]
+		if !ok {
+			for _, d := range []string{
+				filepath.Join(rootDir, "gnovm", "stdlibs", pkgPath),
+				filepath.Join(rootDir, "examples", pkgPath),
+			} {
+				if _, err := os.Stat(filepath.Join(d, file)); err == nil {
+					dir, ok = d, true
+					break
+				}
+			}
+		}
+		if !ok {
+			return pkgPath + "/" + file
+		}
+		p := filepath.Join(dir, file)
+		if abs, err := filepath.Abs(p); err == nil {
+			return abs
+		}
+		return p
+	}
+	if err := prof.WriteProfile(f, fileName); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
 func determinePkgPath(mod *gnomod.File, dir, rootDir string) (string, bool) {
 	if mod != nil {
 		return mod.Module, true
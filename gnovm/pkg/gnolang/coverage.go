@@ -0,0 +1,194 @@
+package gnolang
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Coverage records which statements of a set of registered files are executed
+// by the machines it is attached to (see [MachineOptions.Coverage]). It is used
+// by `gno test -cover`, and can be written out as a Go-compatible cover
+// profile with [Coverage.WriteProfile].
+//
+// Statements are identified by their starting position within a file, so the
+// same Coverage can be shared by machines executing different copies of the
+// same file nodes (e.g. when a package is re-loaded from the store). Compound
+// statements (if, for, range, switch, select) only cover their header, as their
+// bodies are recorded statement by statement.
+type Coverage struct {
+	mu    sync.Mutex
+	files map[coverageFileKey]*coverageFile
+	order []coverageFileKey
+}
+
+type coverageFileKey struct {
+	PkgPath string
+	File    string
+}
+
+type coverageFile struct {
+	blocks []CoverBlock
+	index  map[Pos]int // stmt start -> blocks index
+}
+
+// CoverBlock is a single statement tracked by [Coverage].
+type CoverBlock struct {
+	Span
+	NumStmt int
+	Count   int
+}
+
+// NewCoverage returns an empty Coverage.
+func NewCoverage() *Coverage {
+	return &Coverage{
+		files: make(map[coverageFileKey]*coverageFile),
+	}
+}
+
+// AddFile registers all the statements of fn, declared in the package pkgPath,
+// as coverable. fn should be freshly parsed, as preprocessing may introduce
+// statements which don't exist in the source.
+func (c *Coverage) AddFile(pkgPath string, fn *FileNode) {
+	key := coverageFileKey{PkgPath: pkgPath, File: fn.FileName}
+	cf := &coverageFile{index: make(map[Pos]int)}
+	Transcribe(fn, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER {
+			return n, TRANS_CONTINUE
+		}
+		switch ftype {
+		case TRANS_FILE_BODY, TRANS_DECL_BODY,
+			TRANS_IF_INIT, TRANS_FOR_INIT, TRANS_FOR_POST,
+			TRANS_SWITCH_INIT, TRANS_SELECTCASE_COMM:
+			// declarations, or part of a compound statement's header.
+			return n, TRANS_CONTINUE
+		}
+		s, ok := n.(Stmt)
+		if !ok {
+			return n, TRANS_CONTINUE
+		}
+		span, ok := coverStmtSpan(s)
+		if !ok || span.IsZero() {
+			return n, TRANS_CONTINUE
+		}
+		if _, exists := cf.index[span.Pos]; !exists {
+			cf.index[span.Pos] = len(cf.blocks)
+			cf.blocks = append(cf.blocks, CoverBlock{Span: span, NumStmt: 1})
+		}
+		return n, TRANS_CONTINUE
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.files[key]; !exists {
+		c.order = append(c.order, key)
+	}
+	c.files[key] = cf
+}
+
+// coverStmtSpan returns the span of s to be reported in the cover profile.
+func coverStmtSpan(s Stmt) (Span, bool) {
+	pos := s.GetPos()
+	switch s := s.(type) {
+	case *BlockStmt, *EmptyStmt, *IfCaseStmt, *SwitchClauseStmt, *SelectCaseStmt, *bodyStmt:
+		return Span{}, false
+	case *IfStmt:
+		return Span{Pos: pos, End: s.Cond.GetSpan().End}, true
+	case *ForStmt:
+		switch {
+		case s.Post != nil:
+			return Span{Pos: pos, End: s.Post.GetSpan().End}, true
+		case s.Cond != nil:
+			return Span{Pos: pos, End: s.Cond.GetSpan().End}, true
+		case s.Init != nil:
+			return Span{Pos: pos, End: s.Init.GetSpan().End}, true
+		}
+		return Span{Pos: pos, End: Pos{pos.Line, pos.Column + len("for")}}, true
+	case *RangeStmt:
+		return Span{Pos: pos, End: s.X.GetSpan().End}, true
+	case *SwitchStmt:
+		switch {
+		case s.X != nil:
+			return Span{Pos: pos, End: s.X.GetSpan().End}, true
+		case s.Init != nil:
+			return Span{Pos: pos, End: s.Init.GetSpan().End}, true
+		}
+		return Span{Pos: pos, End: Pos{pos.Line, pos.Column + len("switch")}}, true
+	case *SelectStmt:
+		return Span{Pos: pos, End: Pos{pos.Line, pos.Column + len("select")}}, true
+	default:
+		return s.GetSpan(), true
+	}
+}
+
+// hit records the execution of s, which is part of the last block of m.
+func (c *Coverage) hit(m *Machine, s Stmt) {
+	pos := s.GetPos()
+	if pos.Line == 0 {
+		return
+	}
+	loc := m.LastBlock().GetSource(m.Store).GetLocation()
+	key := coverageFileKey{PkgPath: loc.PkgPath, File: loc.File}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cf := c.files[key]
+	if cf == nil {
+		return
+	}
+	if idx, ok := cf.index[pos]; ok {
+		cf.blocks[idx].Count++
+	}
+}
+
+// Stats returns the number of covered statements, and the total number of
+// statements registered for pkgPath.
+func (c *Coverage) Stats(pkgPath string) (covered, total int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range c.order {
+		if key.PkgPath != pkgPath {
+			continue
+		}
+		for _, b := range c.files[key].blocks {
+			total += b.NumStmt
+			if b.Count > 0 {
+				covered += b.NumStmt
+			}
+		}
+	}
+	return
+}
+
+// WriteProfile writes the coverage data in the format of Go cover profiles,
+// using mode "set". fileName is used to determine the name of each file in
+// the profile; to be usable with `go tool cover`, it should return either an
+// absolute path or a path relative to the working directory, starting with
+// ".".
+func (c *Coverage) WriteProfile(w io.Writer, fileName func(pkgPath, file string) string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := io.WriteString(w, "mode: set\n"); err != nil {
+		return err
+	}
+	for _, key := range c.order {
+		blocks := append([]CoverBlock(nil), c.files[key].blocks...)
+		sort.Slice(blocks, func(i, j int) bool {
+			return blocks[i].Span.Compare(blocks[j].Span) < 0
+		})
+		name := fileName(key.PkgPath, key.File)
+		for _, b := range blocks {
+			count := 0
+			if b.Count > 0 {
+				count = 1
+			}
+			_, err := fmt.Fprintf(w, "%s:%d.%d,%d.%d %d %d\n",
+				name, b.Line, b.Column, b.End.Line, b.End.Column, b.NumStmt, count)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -59,6 +59,7 @@ type Debugger struct {
 	nextDepth   int                         // function call depth at the 'next' command
 	getSrc      func(string, string) string // helper to access source from repl or others
 	rootDir     string
+	dap         *DAPSession // if set, the debugger is driven by a DAP client instead of text commands
 }
 
 // Enable makes the debugger d active, using in as input reader, out as output writer and f as a source helper.
@@ -140,10 +141,18 @@ loop:
 		switch m.Debugger.state {
 		case DebugAtInit:
 			debugUpdateLocation(m)
+			if m.Debugger.dap != nil {
+				m.Debugger.dap.start(m)
+				continue loop
+			}
 			fmt.Fprintln(m.Debugger.out, "Welcome to the Gnovm debugger. Type 'help' for list of commands.")
 			m.Debugger.scanner = bufio.NewScanner(m.Debugger.in)
 			m.Debugger.state = DebugAtCmd
 		case DebugAtCmd:
+			if m.Debugger.dap != nil {
+				m.Debugger.dap.next(m)
+				continue loop
+			}
 			if err := debugCmd(m); err != nil {
 				fmt.Fprintln(m.Debugger.out, "Command failed:", err)
 			}
@@ -151,37 +160,33 @@ loop:
 			if !m.Debugger.enabled {
 				break loop
 			}
+			if m.Debugger.dap != nil && m.Debugger.dap.poll(m) {
+				debugStop(m, "pause")
+				continue loop
+			}
 			switch m.Debugger.lastCmd {
 			case "si", "stepi":
 				m.Debugger.state = DebugAtCmd
 				debugLineInfo(m)
 			case "s", "step":
 				if m.Debugger.loc != m.Debugger.prevLoc && m.Debugger.loc.File != "" {
-					m.Debugger.state = DebugAtCmd
-					m.Debugger.prevLoc = m.Debugger.loc
-					debugList(m, "")
+					debugStop(m, "step")
 					continue loop
 				}
 			case "n", "next":
 				if m.Debugger.loc != m.Debugger.prevLoc && m.Debugger.loc.File != "" &&
 					(m.Debugger.nextDepth == 0 || !sameLine(m.Debugger.loc, m.Debugger.nextLoc) && callDepth(m) <= m.Debugger.nextDepth) {
-					m.Debugger.state = DebugAtCmd
-					m.Debugger.prevLoc = m.Debugger.loc
-					debugList(m, "")
+					debugStop(m, "step")
 					continue loop
 				}
 			case "stepout", "so":
 				if callDepth(m) < m.Debugger.nextDepth {
-					m.Debugger.state = DebugAtCmd
-					m.Debugger.prevLoc = m.Debugger.loc
-					debugList(m, "")
+					debugStop(m, "step")
 					continue loop
 				}
 			default:
 				if atBreak(m) {
-					m.Debugger.state = DebugAtCmd
-					m.Debugger.prevLoc = m.Debugger.loc
-					debugList(m, "")
+					debugStop(m, "breakpoint")
 					continue loop
 				}
 			}
@@ -203,6 +208,18 @@ loop:
 	}
 }
 
+// debugStop stops the program at the current location, for the given reason
+// ("step", "breakpoint" or "pause"), and waits for the next command.
+func debugStop(m *Machine, reason string) {
+	m.Debugger.state = DebugAtCmd
+	m.Debugger.prevLoc = m.Debugger.loc
+	if m.Debugger.dap != nil {
+		m.Debugger.dap.stopped(m, reason)
+		return
+	}
+	debugList(m, "")
+}
+
 // callDepth returns the function call depth.
 func callDepth(m *Machine) int {
 	n := 0
@@ -226,6 +243,9 @@ func atBreak(m *Machine) bool {
 	if loc == m.Debugger.prevLoc {
 		return false
 	}
+	if m.Debugger.dap != nil {
+		return m.Debugger.dap.atBreak(m, loc)
+	}
 	for _, b := range m.Debugger.breakpoints {
 		if loc.File == b.File && loc.Line == b.Line {
 			return true
@@ -739,6 +759,38 @@ func debugEvalExpr(m *Machine, node ast.Node) (tv TypedValue, err error) {
 // the current function call frame, or the global frame if not found.
 // Note: the commands 'up' and 'down' change the frame level to start from.
 func debugLookup(m *Machine, name string) (tv TypedValue, ok bool) {
+	sblocks := debugFrameBlocks(m)
+	if sblocks == nil {
+		return tv, false
+	}
+
+	// Search value in current frame level blocks, or main scope.
+	for _, b := range sblocks {
+		switch t := b.Source.(type) {
+		case *IfStmt:
+			for i, s := range ifBody(m, t).Source.GetBlockNames() {
+				if string(s) == name || string(s) == name+".loopvar" {
+					return b.Values[i], true
+				}
+			}
+		}
+		for i, s := range b.Source.GetBlockNames() {
+			if string(s) == name || string(s) == name+".loopvar" {
+				return b.Values[i], true
+			}
+		}
+	}
+	// Fallback: search a global value.
+	if v := sblocks[0].Source.GetSlot(m.Store, Name(name), true); v != nil {
+		return *v, true
+	}
+	return tv, false
+}
+
+// debugFrameBlocks returns the blocks of the function call frame at the
+// current frame level, innermost first, followed by the global block.
+// It returns nil if the frame can't be found.
+func debugFrameBlocks(m *Machine) []*Block {
 	// Position to the right frame.
 	ncall := 0
 	var i int
@@ -757,7 +809,7 @@ func debugLookup(m *Machine, name string) (tv TypedValue, ok bool) {
 		}
 	}
 	if i < 0 {
-		return tv, false
+		return nil
 	}
 
 	// XXX The following logic isn't necessary and it isn't correct either.
@@ -791,7 +843,7 @@ func debugLookup(m *Machine, name string) (tv TypedValue, ok bool) {
 		}
 	}
 	if i < 0 {
-		return tv, false
+		return nil
 	}
 
 	// get SourceBlocks in the same frame level.
@@ -806,27 +858,7 @@ func debugLookup(m *Machine, name string) (tv TypedValue, ok bool) {
 		sblocks = append(sblocks, m.Blocks[0]) // Add global block
 	}
 
-	// Search value in current frame level blocks, or main scope.
-	for _, b := range sblocks {
-		switch t := b.Source.(type) {
-		case *IfStmt:
-			for i, s := range ifBody(m, t).Source.GetBlockNames() {
-				if string(s) == name || string(s) == name+".loopvar" {
-					return b.Values[i], true
-				}
-			}
-		}
-		for i, s := range b.Source.GetBlockNames() {
-			if string(s) == name || string(s) == name+".loopvar" {
-				return b.Values[i], true
-			}
-		}
-	}
-	// Fallback: search a global value.
-	if v := sblocks[0].Source.GetSlot(m.Store, Name(name), true); v != nil {
-		return *v, true
-	}
-	return tv, false
+	return sblocks
 }
 
 // ifBody returns the Then or Else body corresponding to the current location.
@@ -851,18 +883,21 @@ func debugStack(m *Machine, arg string) error {
 		if ff == nil {
 			break
 		}
-		var fname string
-		if ff.IsMethod {
-			fname = fmt.Sprintf("%v.(%v).%v", ff.PkgPath, ff.Type.(*FuncType).Params[0].Type, ff.Name)
-		} else {
-			fname = fmt.Sprintf("%v.%v", ff.PkgPath, ff.Name)
-		}
-		fmt.Fprintf(m.Debugger.out, "%d\tin %s\n\tat %s\n", i, fname, loc)
+		fmt.Fprintf(m.Debugger.out, "%d\tin %s\n\tat %s\n", i, debugFuncName(ff), loc)
 		i++
 	}
 	return nil
 }
 
+// debugFuncName returns the qualified name of function ff, as displayed in
+// stack traces.
+func debugFuncName(ff *FuncValue) string {
+	if ff.IsMethod {
+		return fmt.Sprintf("%v.(%v).%v", ff.PkgPath, ff.Type.(*FuncType).Params[0].Type, ff.Name)
+	}
+	return fmt.Sprintf("%v.%v", ff.PkgPath, ff.Name)
+}
+
 func debugFrameFunc(m *Machine, n int) *FuncValue {
 	for ncall, i := 0, len(m.Frames)-1; i >= 0; i-- {
 		f := m.Frames[i]
@@ -0,0 +1,822 @@
+package gnolang
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/parser"
+	"io"
+	"net"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
+)
+
+// DAPSession is a connection to a client of the Debug Adapter Protocol
+// (https://microsoft.github.io/debug-adapter-protocol), such as VS Code.
+// It drives the debugger of one or several machines, in sequence, through
+// structured requests instead of text commands: breakpoints, stepping, stack
+// traces, scopes, variables and expression evaluation.
+//
+// Requests are read in a separate goroutine, but they are always handled
+// by the goroutine of the machine being debugged.
+type DAPSession struct {
+	conn io.ReadWriteCloser
+	reqs chan *dapMessage
+
+	wmu sync.Mutex // protects conn writes and seq
+	seq int
+
+	configured  bool                    // configurationDone was received
+	stopOnEntry bool                    // stop as soon as the program starts
+	detached    bool                    // client disconnected, without terminating the program
+	terminated  bool                    // terminated event was sent
+	pause       bool                    // pause was requested while running
+	breakpoints map[string]map[int]bool // breakpoint lines, by absolute source path
+	dirs        map[string]string       // directories of packages, by package path
+	paths       map[Location]string     // cache of absolute source paths
+	refs        []dapRef                // variable references, valid while stopped
+	rootDir     string
+}
+
+// dapThreadID is the ID of the only thread reported to clients.
+const dapThreadID = 1
+
+// maxDAPVariables is the maximum number of children returned for a variable,
+// when the client doesn't request a specific range.
+const maxDAPVariables = 1000
+
+// ListenDAP waits for a DAP client to connect to addr, and returns a session
+// using this connection.
+func ListenDAP(addr string) (*DAPSession, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer l.Close()
+	print("Waiting for DAP client to connect at ", addr)
+	conn, err := l.Accept()
+	if err != nil {
+		return nil, err
+	}
+	println(" connected!")
+	return NewDAPSession(conn), nil
+}
+
+// NewDAPSession returns a session using conn to communicate with a DAP client.
+func NewDAPSession(conn io.ReadWriteCloser) *DAPSession {
+	s := &DAPSession{
+		conn:        conn,
+		reqs:        make(chan *dapMessage, 16),
+		breakpoints: map[string]map[int]bool{},
+		dirs:        map[string]string{},
+		paths:       map[Location]string{},
+		rootDir:     gnoenv.RootDir(),
+	}
+	go s.readRoutine()
+	return s
+}
+
+// AddPackageDir registers dir as the directory containing the source files
+// of the package pkgPath, so that they can be shown to the client.
+func (s *DAPSession) AddPackageDir(pkgPath, dir string) {
+	if abs, err := filepath.Abs(dir); err == nil {
+		s.dirs[pkgPath] = abs
+	}
+}
+
+// EnableDAP makes the debugger d active, driven by the client of session s,
+// and using f as a source helper.
+// It does nothing if the client already disconnected.
+func (d *Debugger) EnableDAP(s *DAPSession, f func(string, string) string) {
+	if s.detached || s.terminated {
+		return
+	}
+	d.Enable(nil, io.Discard, f)
+	d.dap = s
+}
+
+// Close notifies the client that the program exited with exitCode, and closes
+// the connection.
+func (s *DAPSession) Close(exitCode int) error {
+	if !s.detached && !s.terminated {
+		s.event("exited", map[string]any{"exitCode": exitCode})
+		s.event("terminated", nil)
+		s.terminated = true
+
+		// Give the client a chance to disconnect cleanly.
+		timeout := time.After(time.Second)
+	wait:
+		for {
+			select {
+			case req, ok := <-s.reqs:
+				if !ok {
+					break wait
+				}
+				s.respond(req, nil, nil)
+				if req.Command == "disconnect" {
+					break wait
+				}
+			case <-timeout:
+				break wait
+			}
+		}
+	}
+	return s.conn.Close()
+}
+
+// ----------------------------------------
+// Protocol messages.
+
+type dapMessage struct {
+	Seq       int             `json:"seq"`
+	Type      string          `json:"type"`
+	Command   string          `json:"command,omitempty"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+type dapResponse struct {
+	Seq        int    `json:"seq"`
+	Type       string `json:"type"`
+	RequestSeq int    `json:"request_seq"`
+	Success    bool   `json:"success"`
+	Command    string `json:"command"`
+	Message    string `json:"message,omitempty"`
+	Body       any    `json:"body,omitempty"`
+}
+
+type dapEvent struct {
+	Seq   int    `json:"seq"`
+	Type  string `json:"type"`
+	Event string `json:"event"`
+	Body  any    `json:"body,omitempty"`
+}
+
+type dapSource struct {
+	Name string `json:"name"`
+	Path string `json:"path,omitempty"`
+}
+
+type dapStackFrame struct {
+	ID     int        `json:"id"`
+	Name   string     `json:"name"`
+	Source *dapSource `json:"source,omitempty"`
+	Line   int        `json:"line"`
+	Column int        `json:"column"`
+}
+
+type dapScope struct {
+	Name               string `json:"name"`
+	PresentationHint   string `json:"presentationHint,omitempty"`
+	VariablesReference int    `json:"variablesReference"`
+	Expensive          bool   `json:"expensive"`
+}
+
+type dapVariable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Type               string `json:"type,omitempty"`
+	VariablesReference int    `json:"variablesReference"`
+	IndexedVariables   int    `json:"indexedVariables,omitempty"`
+}
+
+// readRoutine reads the requests of the client, until the connection is
+// closed.
+func (s *DAPSession) readRoutine() {
+	defer close(s.reqs)
+	r := bufio.NewReader(s.conn)
+	for {
+		msg, err := readDAPMessage(r)
+		if err != nil {
+			return
+		}
+		if msg.Type == "request" {
+			s.reqs <- msg
+		}
+	}
+}
+
+func readDAPMessage(r *bufio.Reader) (*dapMessage, error) {
+	header, err := textproto.NewReader(r).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid Content-Length header: %q", header.Get("Content-Length"))
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	msg := &dapMessage{}
+	if err := json.Unmarshal(buf, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (s *DAPSession) send(msg func(seq int) any) {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+	s.seq++
+	b, err := json.Marshal(msg(s.seq))
+	if err != nil {
+		panic(err)
+	}
+	// Write errors mean the client is gone, which is detected by readRoutine.
+	fmt.Fprintf(s.conn, "Content-Length: %d\r\n\r\n%s", len(b), b)
+}
+
+func (s *DAPSession) respond(req *dapMessage, body any, err error) {
+	s.send(func(seq int) any {
+		resp := dapResponse{
+			Seq:        seq,
+			Type:       "response",
+			RequestSeq: req.Seq,
+			Success:    err == nil,
+			Command:    req.Command,
+			Body:       body,
+		}
+		if err != nil {
+			resp.Message = err.Error()
+		}
+		return resp
+	})
+}
+
+func (s *DAPSession) event(name string, body any) {
+	s.send(func(seq int) any {
+		return dapEvent{Seq: seq, Type: "event", Event: name, Body: body}
+	})
+}
+
+// ----------------------------------------
+// Debugger states.
+
+// start is called when the machine starts being debugged. The first machine
+// of a session waits for the client to be configured.
+func (s *DAPSession) start(m *Machine) {
+	if !s.configured {
+		m.Debugger.state = DebugAtCmd
+		return
+	}
+	m.Debugger.lastCmd = "continue"
+	debugContinue(m, "")
+}
+
+// next waits for and handles the next request, while the program is stopped.
+func (s *DAPSession) next(m *Machine) {
+	req, ok := <-s.reqs
+	if !ok {
+		s.detach(m)
+		return
+	}
+	s.handle(m, req)
+}
+
+// poll handles the pending requests, while the program is running. It
+// returns true if the program should be paused.
+func (s *DAPSession) poll(m *Machine) bool {
+	for {
+		select {
+		case req, ok := <-s.reqs:
+			if !ok {
+				s.detach(m)
+				return false
+			}
+			s.handle(m, req)
+			if m.Debugger.state != DebugAtRun || !m.Debugger.enabled {
+				return false
+			}
+		default:
+			if s.pause && m.Debugger.loc.File != "" && m.Debugger.loc != m.Debugger.prevLoc {
+				s.pause = false
+				return true
+			}
+			return false
+		}
+	}
+}
+
+// stopped notifies the client that the program stopped.
+func (s *DAPSession) stopped(m *Machine, reason string) {
+	s.event("stopped", map[string]any{
+		"reason":            reason,
+		"threadId":          dapThreadID,
+		"allThreadsStopped": true,
+	})
+}
+
+// resume lets the program run according to the debugger command cmd.
+func (s *DAPSession) resume(m *Machine, cmd string) {
+	s.refs = nil
+	m.Debugger.lastCmd = cmd
+	debugContinue(m, "")
+}
+
+func (s *DAPSession) detach(m *Machine) {
+	s.detached = true
+	debugDetach(m, "")
+}
+
+// atBreak returns true if loc matches one of the breakpoints.
+func (s *DAPSession) atBreak(m *Machine, loc Location) bool {
+	if len(s.breakpoints) == 0 {
+		return false
+	}
+	return s.breakpoints[s.sourcePath(m, loc)][loc.Line]
+}
+
+// sourcePath returns the absolute path of the source file of loc, or an empty
+// string if it can't be found.
+func (s *DAPSession) sourcePath(m *Machine, loc Location) string {
+	key := Location{PkgPath: loc.PkgPath, File: loc.File}
+	if p, ok := s.paths[key]; ok {
+		return p
+	}
+
+	var candidates []string
+	switch {
+	case loc.File == "":
+	case filepath.IsAbs(loc.File):
+		candidates = append(candidates, loc.File)
+	default:
+		// Files given on the command line are relative to the working directory.
+		candidates = append(candidates, loc.File)
+		if dir, ok := s.dirs[loc.PkgPath]; ok {
+			candidates = append(candidates, filepath.Join(dir, loc.File))
+		}
+		if s.rootDir != "" {
+			candidates = append(candidates,
+				filepath.Join(s.rootDir, "gnovm", "stdlibs", loc.PkgPath, loc.File),
+				filepath.Join(s.rootDir, "examples", loc.PkgPath, loc.File),
+			)
+		}
+	}
+
+	p := ""
+	for _, c := range candidates {
+		if _, err := os.Stat(c); err == nil {
+			p, _ = filepath.Abs(c)
+			break
+		}
+	}
+	s.paths[key] = p
+	return p
+}
+
+// ----------------------------------------
+// Requests.
+
+func (s *DAPSession) handle(m *Machine, req *dapMessage) {
+	var (
+		body any
+		err  error
+	)
+	// Evaluating values may panic on invalid machine states.
+	defer func() {
+		if r := recover(); r != nil {
+			s.respond(req, nil, fmt.Errorf("%v", r))
+		}
+	}()
+
+	switch req.Command {
+	case "initialize":
+		s.respond(req, map[string]any{
+			"supportsConfigurationDoneRequest": true,
+			"supportsEvaluateForHovers":        true,
+			"supportTerminateDebuggee":         true,
+			"supportsTerminateRequest":         true,
+		}, nil)
+		s.event("initialized", nil)
+		return
+	case "launch", "attach":
+		var args struct {
+			StopOnEntry bool `json:"stopOnEntry"`
+		}
+		err = unmarshalDAPArgs(req, &args)
+		s.stopOnEntry = args.StopOnEntry
+	case "configurationDone":
+		s.configured = true
+		s.respond(req, nil, nil)
+		if s.stopOnEntry {
+			m.Debugger.prevLoc = m.Debugger.loc
+			s.stopped(m, "entry")
+		} else {
+			s.resume(m, "continue")
+		}
+		return
+	case "setBreakpoints":
+		body, err = s.setBreakpoints(req)
+	case "setExceptionBreakpoints":
+		body = map[string]any{"breakpoints": []any{}}
+	case "threads":
+		body = map[string]any{"threads": []map[string]any{{"id": dapThreadID, "name": "main"}}}
+	case "stackTrace":
+		body, err = s.stackTrace(m, req)
+	case "scopes":
+		body, err = s.scopes(m, req)
+	case "variables":
+		body, err = s.variables(m, req)
+	case "evaluate":
+		body, err = s.evaluate(m, req)
+	case "continue", "next", "stepIn", "stepOut":
+		if m.Debugger.state != DebugAtCmd {
+			err = errors.New("program is not stopped")
+			break
+		}
+		s.respond(req, map[string]any{"allThreadsContinued": true}, nil)
+		s.resume(m, map[string]string{
+			"continue": "continue",
+			"next":     "next",
+			"stepIn":   "step",
+			"stepOut":  "stepout",
+		}[req.Command])
+		return
+	case "pause":
+		if m.Debugger.state == DebugAtRun {
+			s.pause = true
+		}
+	case "disconnect", "terminate":
+		var args struct {
+			TerminateDebuggee *bool `json:"terminateDebuggee"`
+		}
+		err = unmarshalDAPArgs(req, &args)
+		s.respond(req, nil, err)
+		if req.Command == "terminate" || args.TerminateDebuggee == nil || *args.TerminateDebuggee {
+			s.event("terminated", nil)
+			s.terminated = true
+			m.Debugger.state = DebugAtExit
+			return
+		}
+		s.detach(m)
+		return
+	default:
+		err = fmt.Errorf("unsupported command: %s", req.Command)
+	}
+	s.respond(req, body, err)
+}
+
+func unmarshalDAPArgs(req *dapMessage, args any) error {
+	if len(req.Arguments) == 0 {
+		return nil
+	}
+	return json.Unmarshal(req.Arguments, args)
+}
+
+func (s *DAPSession) setBreakpoints(req *dapMessage) (any, error) {
+	var args struct {
+		Source      dapSource `json:"source"`
+		Breakpoints []struct {
+			Line int `json:"line"`
+		} `json:"breakpoints"`
+	}
+	if err := unmarshalDAPArgs(req, &args); err != nil {
+		return nil, err
+	}
+	path, err := filepath.Abs(args.Source.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := map[int]bool{}
+	bps := make([]map[string]any, 0, len(args.Breakpoints))
+	for i, bp := range args.Breakpoints {
+		lines[bp.Line] = true
+		bps = append(bps, map[string]any{
+			"id":       i + 1,
+			"verified": true,
+			"line":     bp.Line,
+			"source":   dapSource{Name: filepath.Base(path), Path: path},
+		})
+	}
+	if len(lines) == 0 {
+		delete(s.breakpoints, path)
+	} else {
+		s.breakpoints[path] = lines
+	}
+	return map[string]any{"breakpoints": bps}, nil
+}
+
+// Frame IDs are the frame levels, plus one.
+func (s *DAPSession) stackTrace(m *Machine, req *dapMessage) (any, error) {
+	var args struct {
+		StartFrame int `json:"startFrame"`
+		Levels     int `json:"levels"`
+	}
+	if err := unmarshalDAPArgs(req, &args); err != nil {
+		return nil, err
+	}
+
+	var frames []dapStackFrame
+	for i := 0; i == 0 || i <= len(m.Debugger.call); i++ {
+		ff := debugFrameFunc(m, i)
+		if ff == nil && i > 0 {
+			break
+		}
+		loc := debugFrameLoc(m, i)
+		frame := dapStackFrame{
+			ID:     i + 1,
+			Line:   loc.Line,
+			Column: loc.Column,
+		}
+		if ff != nil {
+			frame.Name = debugFuncName(ff)
+		} else {
+			frame.Name = loc.PkgPath
+		}
+		if loc.File != "" {
+			frame.Source = &dapSource{Name: filepath.Base(loc.File), Path: s.sourcePath(m, loc)}
+		}
+		frames = append(frames, frame)
+	}
+
+	total := len(frames)
+	frames = frames[min(args.StartFrame, total):]
+	if args.Levels > 0 && args.Levels < len(frames) {
+		frames = frames[:args.Levels]
+	}
+	return map[string]any{"stackFrames": frames, "totalFrames": total}, nil
+}
+
+// dapRef is what a variables reference refers to: either the locals or the
+// globals of a frame, or the children of a value.
+type dapRef struct {
+	level   int
+	globals bool
+	scope   bool
+	tv      TypedValue
+}
+
+func (s *DAPSession) newRef(ref dapRef) int {
+	s.refs = append(s.refs, ref)
+	return len(s.refs)
+}
+
+func (s *DAPSession) scopes(m *Machine, req *dapMessage) (any, error) {
+	var args struct {
+		FrameID int `json:"frameId"`
+	}
+	if err := unmarshalDAPArgs(req, &args); err != nil {
+		return nil, err
+	}
+	level := args.FrameID - 1
+	return map[string]any{"scopes": []dapScope{
+		{Name: "Locals", PresentationHint: "locals", VariablesReference: s.newRef(dapRef{level: level, scope: true})},
+		{Name: "Globals", VariablesReference: s.newRef(dapRef{level: level, scope: true, globals: true}), Expensive: true},
+	}}, nil
+}
+
+func (s *DAPSession) variables(m *Machine, req *dapMessage) (any, error) {
+	var args struct {
+		VariablesReference int `json:"variablesReference"`
+		Start              int `json:"start"`
+		Count              int `json:"count"`
+	}
+	if err := unmarshalDAPArgs(req, &args); err != nil {
+		return nil, err
+	}
+	if args.VariablesReference < 1 || args.VariablesReference > len(s.refs) {
+		return nil, fmt.Errorf("invalid variables reference: %d", args.VariablesReference)
+	}
+	ref := s.refs[args.VariablesReference-1]
+
+	var (
+		names  []string
+		values []TypedValue
+	)
+	switch {
+	case ref.scope && ref.globals:
+		names, values = debugFrameGlobals(m, ref.level)
+	case ref.scope:
+		names, values = debugFrameLocals(m, ref.level)
+	default:
+		names, values = debugChildren(m, ref.tv, args.Start, args.Count)
+	}
+
+	vars := make([]dapVariable, len(names))
+	for i := range names {
+		vars[i] = s.variable(names[i], values[i])
+	}
+	return map[string]any{"variables": vars}, nil
+}
+
+func (s *DAPSession) evaluate(m *Machine, req *dapMessage) (any, error) {
+	var args struct {
+		Expression string `json:"expression"`
+		FrameID    int    `json:"frameId"`
+	}
+	if err := unmarshalDAPArgs(req, &args); err != nil {
+		return nil, err
+	}
+	x, err := parser.ParseExpr(args.Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	level := m.Debugger.frameLevel
+	defer func() { m.Debugger.frameLevel = level }()
+	m.Debugger.frameLevel = max(args.FrameID-1, 0)
+
+	tv, err := debugEvalExpr(m, x)
+	if err != nil {
+		return nil, err
+	}
+	v := s.variable(args.Expression, tv)
+	return map[string]any{
+		"result":             v.Value,
+		"type":               v.Type,
+		"variablesReference": v.VariablesReference,
+		"indexedVariables":   v.IndexedVariables,
+	}, nil
+}
+
+// variable returns the representation of tv for the client. Values with
+// children are given a reference, so that they can be expanded.
+func (s *DAPSession) variable(name string, tv TypedValue) dapVariable {
+	if hiv, ok := tv.V.(*HeapItemValue); ok {
+		tv = hiv.Value
+	}
+	v := dapVariable{Name: name}
+	if tv.T == nil {
+		v.Value = "nil"
+		return v
+	}
+	v.Type = tv.T.String()
+	v.Value = debugValueString(tv)
+
+	n, indexed := debugChildCount(tv)
+	if n > 0 {
+		v.VariablesReference = s.newRef(dapRef{tv: tv})
+		if indexed {
+			v.IndexedVariables = n
+		}
+	}
+	return v
+}
+
+// ----------------------------------------
+// Inspection of values, independent of the protocol.
+
+// maxDebugValueLen is the maximum length of the representation of a value.
+const maxDebugValueLen = 200
+
+// debugValueString returns a short representation of tv.
+func debugValueString(tv TypedValue) string {
+	if bt, ok := baseOf(tv.T).(PrimitiveType); ok && bt.Kind() == StringKind {
+		return strconv.Quote(tv.GetString())
+	}
+	str := tv.ProtectedSprint(newSeenValues(), false)
+	if len(str) > maxDebugValueLen {
+		str = str[:maxDebugValueLen] + "..."
+	}
+	return str
+}
+
+// debugChildCount returns the number of children of tv, and whether they are
+// indexed (as the elements of an array or slice).
+func debugChildCount(tv TypedValue) (n int, indexed bool) {
+	if tv.V == nil {
+		return 0, false
+	}
+	switch cv := tv.V.(type) {
+	case *ArrayValue:
+		return cv.GetLength(), true
+	case *SliceValue:
+		return cv.GetLength(), true
+	case *StructValue:
+		return len(cv.Fields), false
+	case *MapValue:
+		if cv.List == nil {
+			return 0, false
+		}
+		return cv.GetLength(), false
+	case PointerValue:
+		if cv.TV == nil {
+			return 0, false
+		}
+		return 1, false
+	default:
+		return 0, false
+	}
+}
+
+// debugChildren returns the children of tv: the elements of arrays, slices
+// and maps, the fields of structs, and the value pointed to by pointers.
+// For arrays and slices, only count elements starting at start are returned;
+// count defaults to maxDAPVariables.
+func debugChildren(m *Machine, tv TypedValue, start, count int) (names []string, values []TypedValue) {
+	if count <= 0 {
+		count = maxDAPVariables
+	}
+	switch cv := tv.V.(type) {
+	case *ArrayValue:
+		et := baseOf(tv.T).(*ArrayType).Elt
+		for i := start; i < cv.GetLength() && i < start+count; i++ {
+			names = append(names, "["+strconv.Itoa(i)+"]")
+			values = append(values, cv.GetPointerAtIndexInt2(m.Store, i, et).Deref())
+		}
+	case *SliceValue:
+		et := baseOf(tv.T).(*SliceType).Elt
+		for i := start; i < cv.GetLength() && i < start+count; i++ {
+			names = append(names, "["+strconv.Itoa(i)+"]")
+			values = append(values, cv.GetPointerAtIndexInt2(m.Store, i, et).Deref())
+		}
+	case *StructValue:
+		st := baseOf(tv.T).(*StructType)
+		for i := range cv.Fields {
+			names = append(names, string(st.Fields[i].Name))
+			values = append(values, cv.GetPointerToInt(m.Store, i).Deref())
+		}
+	case *MapValue:
+		for item := cv.List.Head; item != nil && len(names) < count; item = item.Next {
+			names = append(names, debugValueString(item.Key))
+			values = append(values, item.Value)
+		}
+	case PointerValue:
+		names = append(names, "*")
+		values = append(values, cv.Deref())
+	}
+	return names, values
+}
+
+// debugFrameLocals returns the local variables of the function call frame at
+// the given level.
+func debugFrameLocals(m *Machine, level int) (names []string, values []TypedValue) {
+	saved := m.Debugger.frameLevel
+	defer func() { m.Debugger.frameLevel = saved }()
+	m.Debugger.frameLevel = level
+
+	seen := map[string]bool{}
+	add := func(b *Block, bnames []Name) {
+		for i, n := range bnames {
+			name := strings.TrimSuffix(string(n), ".loopvar")
+			if i >= len(b.Values) || name == "_" || strings.HasPrefix(name, ".") || seen[name] {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+			values = append(values, b.Values[i])
+		}
+	}
+	for _, b := range debugFrameBlocks(m) {
+		switch t := b.Source.(type) {
+		case *PackageNode, *FileNode:
+			continue
+		case *IfStmt:
+			add(b, ifBody(m, t).Source.GetBlockNames())
+		}
+		add(b, b.Source.GetBlockNames())
+	}
+	return names, values
+}
+
+// debugFrameGlobals returns the global variables of the package of the
+// function call frame at the given level.
+func debugFrameGlobals(m *Machine, level int) (names []string, values []TypedValue) {
+	pkgPath := m.Package.PkgPath
+	if ff := debugFrameFunc(m, level); ff != nil {
+		pkgPath = ff.PkgPath
+	}
+	pv := m.Store.GetPackage(pkgPath, false)
+	if pv == nil {
+		return nil, nil
+	}
+	b := pv.GetBlock(m.Store)
+	bnames := b.Source.GetBlockNames()
+	idx := make([]int, 0, len(bnames))
+	for i, n := range bnames {
+		if i >= len(b.Values) || n == "_" || strings.HasPrefix(string(n), ".") {
+			continue
+		}
+		tv := fillValueTV(m.Store, &b.Values[i])
+		if tv.T == nil {
+			continue
+		}
+		// Only show variables, not types and function declarations.
+		switch kind := tv.T.Kind(); {
+		case kind == TypeKind, kind == PackageKind:
+			continue
+		case kind == FuncKind:
+			if fv, ok := tv.V.(*FuncValue); ok && fv.Name == n {
+				continue
+			}
+		}
+		idx = append(idx, i)
+	}
+	slices.SortFunc(idx, func(i, j int) int { return strings.Compare(string(bnames[i]), string(bnames[j])) })
+	for _, i := range idx {
+		names = append(names, string(bnames[i]))
+		values = append(values, b.Values[i])
+	}
+	return names, values
+}
@@ -0,0 +1,250 @@
+package gnolang_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
+	"github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/gnovm/pkg/test"
+)
+
+// dapClient is a minimal DAP client, for testing.
+type dapClient struct {
+	t    *testing.T
+	conn net.Conn
+	seq  int
+	msgs chan map[string]any
+}
+
+func newDAPClient(t *testing.T, conn net.Conn) *dapClient {
+	t.Helper()
+
+	c := &dapClient{t: t, conn: conn, msgs: make(chan map[string]any, 100)}
+	go func() {
+		defer close(c.msgs)
+		r := bufio.NewReader(conn)
+		for {
+			header, err := textproto.NewReader(r).ReadMIMEHeader()
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(header.Get("Content-Length"))
+			buf := make([]byte, n)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return
+			}
+			var msg map[string]any
+			if err := json.Unmarshal(buf, &msg); err != nil {
+				return
+			}
+			c.msgs <- msg
+		}
+	}()
+	return c
+}
+
+func (c *dapClient) send(command string, args any) {
+	c.t.Helper()
+
+	c.seq++
+	b, err := json.Marshal(map[string]any{
+		"seq":       c.seq,
+		"type":      "request",
+		"command":   command,
+		"arguments": args,
+	})
+	require.NoError(c.t, err)
+	_, err = fmt.Fprintf(c.conn, "Content-Length: %d\r\n\r\n%s", len(b), b)
+	require.NoError(c.t, err)
+}
+
+// expect waits for a message of the given type ("response" or "event") and
+// name, skipping the other ones.
+func (c *dapClient) expect(typ, name string) map[string]any {
+	c.t.Helper()
+
+	key := "command"
+	if typ == "event" {
+		key = "event"
+	}
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case msg, ok := <-c.msgs:
+			require.True(c.t, ok, "connection closed, waiting for %s %s", typ, name)
+			if msg["type"] == typ && msg[key] == name {
+				return msg
+			}
+		case <-timeout:
+			c.t.Fatalf("timeout waiting for %s %s", typ, name)
+		}
+	}
+}
+
+// request sends a request and returns the body of its successful response.
+func (c *dapClient) request(command string, args any) map[string]any {
+	c.t.Helper()
+
+	c.send(command, args)
+	resp := c.expect("response", command)
+	require.Equal(c.t, true, resp["success"], "%s: %v", command, resp["message"])
+	body, _ := resp["body"].(map[string]any)
+	return body
+}
+
+func (c *dapClient) variables(ref any) map[string]string {
+	c.t.Helper()
+
+	body := c.request("variables", map[string]any{"variablesReference": ref})
+	vars := map[string]string{}
+	for _, v := range body["variables"].([]any) {
+		v := v.(map[string]any)
+		vars[v["name"].(string)] = v["value"].(string)
+	}
+	return vars
+}
+
+func evalDAPTest(t *testing.T, conn net.Conn, file string) (out string) {
+	t.Helper()
+
+	bout := bytes.NewBufferString("")
+	output := test.OutputWithError(writeNopCloser{bout}, writeNopCloser{io.Discard})
+	_, testStore := test.TestStore(gnoenv.RootDir(), output, nil)
+
+	pkgName, err := gnolang.ParseFilePackageName(file)
+	require.NoError(t, err)
+
+	m := gnolang.NewMachineWithOptions(gnolang.MachineOptions{
+		PkgPath: pkgName,
+		Output:  output,
+		Store:   testStore,
+		Context: test.Context(test.DefaultCaller, pkgName, nil),
+		Debug:   true,
+	})
+	defer m.Release()
+
+	s := gnolang.NewDAPSession(conn)
+	m.Debugger.EnableDAP(s, nil)
+
+	m.RunFiles(m.MustReadFile(file))
+	ex, _ := m.ParseExpr("main()")
+	m.Eval(ex)
+	require.NoError(t, s.Close(0))
+	return bout.String()
+}
+
+func TestDAP(t *testing.T) {
+	srv, cli := net.Pipe()
+	defer cli.Close()
+
+	done := make(chan string)
+	go func() { done <- evalDAPTest(t, srv, debugTarget) }()
+
+	c := newDAPClient(t, cli)
+	target, err := filepath.Abs(debugTarget)
+	require.NoError(t, err)
+
+	body := c.request("initialize", map[string]any{"adapterID": "gno"})
+	assert.Equal(t, true, body["supportsConfigurationDoneRequest"])
+	c.expect("event", "initialized")
+	c.request("launch", map[string]any{})
+
+	body = c.request("setBreakpoints", map[string]any{
+		"source":      map[string]any{"path": target},
+		"breakpoints": []any{map[string]any{"line": 7}},
+	})
+	bps := body["breakpoints"].([]any)
+	require.Len(t, bps, 1)
+	assert.Equal(t, true, bps[0].(map[string]any)["verified"])
+
+	c.request("configurationDone", nil)
+	ev := c.expect("event", "stopped")
+	assert.Equal(t, "breakpoint", ev["body"].(map[string]any)["reason"])
+
+	body = c.request("threads", nil)
+	assert.Len(t, body["threads"], 1)
+
+	body = c.request("stackTrace", map[string]any{"threadId": 1})
+	frames := body["stackFrames"].([]any)
+	require.Len(t, frames, 3)
+	top := frames[0].(map[string]any)
+	assert.Equal(t, "main.f", top["name"])
+	assert.Equal(t, float64(7), top["line"])
+	assert.Equal(t, target, top["source"].(map[string]any)["path"])
+	assert.Equal(t, "main.g", frames[1].(map[string]any)["name"])
+	assert.Equal(t, "main.main", frames[2].(map[string]any)["name"])
+
+	body = c.request("scopes", map[string]any{"frameId": top["id"]})
+	scopes := body["scopes"].([]any)
+	require.Len(t, scopes, 2)
+	locals := c.variables(scopes[0].(map[string]any)["variablesReference"])
+	assert.Equal(t, map[string]string{"name": `"hello"`, "i": "3"}, locals)
+	globals := c.variables(scopes[1].(map[string]any)["variablesReference"])
+	assert.Equal(t, `"test"`, globals["global"])
+	assert.NotContains(t, globals, "main")
+	assert.NotContains(t, globals, "T")
+
+	body = c.request("evaluate", map[string]any{"expression": "i", "frameId": top["id"]})
+	assert.Equal(t, "3", body["result"])
+	assert.Equal(t, "int", body["type"])
+	body = c.request("evaluate", map[string]any{"expression": "s", "frameId": frames[1].(map[string]any)["id"]})
+	assert.Equal(t, `"hello"`, body["result"])
+
+	c.send("evaluate", map[string]any{"expression": "foo", "frameId": top["id"]})
+	resp := c.expect("response", "evaluate")
+	assert.Equal(t, false, resp["success"])
+	assert.Contains(t, resp["message"], "could not find symbol value for foo")
+
+	// Stop in method get, to inspect a struct.
+	c.request("setBreakpoints", map[string]any{
+		"source":      map[string]any{"path": target},
+		"breakpoints": []any{map[string]any{"line": 21}},
+	})
+	c.request("continue", map[string]any{"threadId": 1})
+	c.expect("event", "stopped")
+	body = c.request("evaluate", map[string]any{"expression": "*t", "frameId": 1})
+	assert.Equal(t, "main.T", body["type"])
+	fields := c.variables(body["variablesReference"])
+	require.Contains(t, fields, "A")
+
+	for _, step := range []struct {
+		command string
+		line    int
+	}{
+		{"stepOut", 40},
+		{"next", 41},
+	} {
+		c.request(step.command, map[string]any{"threadId": 1})
+		ev = c.expect("event", "stopped")
+		assert.Equal(t, "step", ev["body"].(map[string]any)["reason"])
+		body = c.request("stackTrace", map[string]any{"threadId": 1, "levels": 1})
+		assert.Equal(t, float64(step.line), body["stackFrames"].([]any)[0].(map[string]any)["line"], step.command)
+	}
+
+	c.request("setBreakpoints", map[string]any{"source": map[string]any{"path": target}})
+	c.request("continue", map[string]any{"threadId": 1})
+	ev = c.expect("event", "exited")
+	assert.Equal(t, float64(0), ev["body"].(map[string]any)["exitCode"])
+	c.expect("event", "terminated")
+	c.send("disconnect", nil)
+
+	select {
+	case out := <-done:
+		assert.Contains(t, out, "hello 3")
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for the program to terminate")
+	}
+}
@@ -0,0 +1,407 @@
+package gnolang
+
+import (
+	"compress/gzip"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/gnolang/gno/tm2/pkg/store"
+	storetypes "github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+// GasProfile records the gas consumed by the machines it is attached to (see
+// [MachineOptions.GasProfile] and [GasProfile.Attach]), by call stack and by
+// line, split by kind of consumption: CPU ops, allocations, and store reads
+// and writes. It is used by `gno test -gasprofile` and `gno run -gasprofile`,
+// and can be written out in pprof format with [GasProfile.WriteProfile].
+//
+// Gas is recorded by the gas meters returned by [GasProfile.GasMeter], which
+// attribute the gas to the current stack of the last attached machine that
+// wasn't released yet.
+type GasProfile struct {
+	mu       sync.Mutex
+	machines []*Machine
+	samples  map[string]*gasSample
+	order    []string
+}
+
+// gasKind is the kind of gas consumption, according to its descriptor.
+type gasKind int
+
+const (
+	gasKindCPU gasKind = iota
+	gasKindAlloc
+	gasKindStoreRead
+	gasKindStoreWrite
+	gasKindOther
+	numGasKinds
+)
+
+var gasKindNames = [numGasKinds]string{
+	gasKindCPU:        "cpu",
+	gasKindAlloc:      "alloc",
+	gasKindStoreRead:  "store_read",
+	gasKindStoreWrite: "store_write",
+	gasKindOther:      "other",
+}
+
+func gasKindOf(descriptor string) gasKind {
+	switch descriptor {
+	case "CPUCycles", "parsing":
+		return gasKindCPU
+	case "memory allocation (cpu)", "GC":
+		return gasKindAlloc
+	case GasGetObjectDesc, GasGetTypeDesc, GasGetPackageRealmDesc, GasGetMemPackageDesc,
+		storetypes.GasReadCostFlatDesc, storetypes.GasReadPerByteDesc,
+		storetypes.GasHasDesc, storetypes.GasIterNextCostFlatDesc, storetypes.GasValuePerByteDesc:
+		return gasKindStoreRead
+	case GasSetObjectDesc, GasSetTypeDesc, GasSetPackageRealmDesc, GasAddMemPackageDesc, GasDeleteObjectDesc,
+		storetypes.GasWriteCostFlatDesc, storetypes.GasWritePerByteDesc, storetypes.GasDeleteDesc:
+		return gasKindStoreWrite
+	default:
+		return gasKindOther
+	}
+}
+
+// gasFrame is a function in the stack of a sample, and the line being
+// executed in it.
+type gasFrame struct {
+	Func    string
+	PkgPath string
+	File    string
+	Line    int
+}
+
+type gasSample struct {
+	stack []gasFrame // leaf first
+	gas   [numGasKinds]int64
+}
+
+// NewGasProfile returns an empty GasProfile.
+func NewGasProfile() *GasProfile {
+	return &GasProfile{
+		samples: make(map[string]*gasSample),
+	}
+}
+
+// GasMeter returns a gas meter which records the gas consumed in p, before
+// consuming it from base. If base is nil, an infinite gas meter is used.
+// Gas meters of p are returned unchanged, so that gas isn't recorded twice.
+func (p *GasProfile) GasMeter(base store.GasMeter) store.GasMeter {
+	if gm, ok := base.(*gasProfileMeter); ok && gm.profile == p {
+		return gm
+	}
+	if base == nil {
+		base = store.NewInfiniteGasMeter()
+	}
+	return &gasProfileMeter{GasMeter: base, profile: p}
+}
+
+// Attach attributes the gas recorded in p to the stack of m, until m is
+// released, and makes the gas meter of m, and of its allocator, record the
+// gas in p. It must be called once m.GasMeter and m.Alloc are set.
+func (p *GasProfile) Attach(m *Machine) {
+	m.GasProfile = p
+	m.GasMeter = p.GasMeter(m.GasMeter)
+	if m.Alloc != nil {
+		m.Alloc.SetGasMeter(m.GasMeter)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.machines = append(p.machines, m)
+}
+
+// detach is called when m is released.
+func (p *GasProfile) detach(m *Machine) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := len(p.machines) - 1; i >= 0; i-- {
+		if p.machines[i] == m {
+			p.machines = append(p.machines[:i], p.machines[i+1:]...)
+			return
+		}
+	}
+}
+
+func (p *GasProfile) record(amount int64, descriptor string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var m *Machine
+	if len(p.machines) > 0 {
+		m = p.machines[len(p.machines)-1]
+	}
+	stack := gasStack(m)
+
+	var key strings.Builder
+	for _, fr := range stack {
+		key.WriteString(fr.Func)
+		key.WriteByte(0)
+		key.WriteString(fr.File)
+		key.WriteByte(':')
+		key.WriteString(strconv.Itoa(fr.Line))
+		key.WriteByte('\n')
+	}
+	s := p.samples[key.String()]
+	if s == nil {
+		s = &gasSample{stack: stack}
+		p.samples[key.String()] = s
+		p.order = append(p.order, key.String())
+	}
+	s.gas[gasKindOf(descriptor)] += amount
+}
+
+// gasStack returns the current call stack of m, leaf first. Gas consumed
+// outside of any function (e.g. when initializing package variables) is
+// attributed to the package "init", and gas consumed outside of a machine to
+// a "(vm)" pseudo-function.
+func gasStack(m *Machine) []gasFrame {
+	if m == nil {
+		return []gasFrame{{Func: "(vm)"}}
+	}
+	var stack []gasFrame
+	line := gasLine(m)
+	for i := len(m.Frames) - 1; i >= 0; i-- {
+		fr := &m.Frames[i]
+		if !fr.IsCall() {
+			continue
+		}
+		fv := fr.Func
+		if fv.IsNative() || (fv.FileName == "" && !fv.IsClosure) {
+			// Native or uverse function.
+			stack = append(stack, gasFrame{Func: gasFuncName(fv, Location{}), PkgPath: fv.PkgPath})
+		} else {
+			loc := fv.GetSource(m.Store).GetLocation()
+			stack = append(stack, gasFrame{Func: gasFuncName(fv, loc), PkgPath: loc.PkgPath, File: loc.File, Line: line})
+		}
+		line = 0
+		if cx, ok := fr.Source.(*CallExpr); ok {
+			line = cx.GetLine()
+		}
+	}
+	if len(stack) == 0 {
+		if m.Package == nil {
+			return []gasFrame{{Func: "(vm)"}}
+		}
+		pkgPath := m.Package.PkgPath
+		stack = append(stack, gasFrame{Func: pkgPath + ".init", PkgPath: pkgPath, Line: line})
+	}
+	return stack
+}
+
+// gasFuncName returns the name of fv, declared at loc, similar to the names of
+// Go functions in profiles. Closures are named after their location, as they
+// don't keep track of the function declaring them.
+func gasFuncName(fv *FuncValue, loc Location) string {
+	switch {
+	case fv.IsClosure:
+		return fv.PkgPath + ".func@" + strconv.Itoa(loc.Line)
+	case fv.IsMethod:
+		recv := fv.Type.(*FuncType).Params[0].Type.String()
+		recv = strings.ReplaceAll(recv, fv.PkgPath+".", "")
+		if strings.HasPrefix(recv, "*") {
+			recv = "(" + recv + ")"
+		}
+		return fv.PkgPath + "." + recv + "." + string(fv.Name)
+	default:
+		return fv.PkgPath + "." + string(fv.Name)
+	}
+}
+
+// gasLine returns the line being executed by m. Like in [Machine.Stacktrace],
+// the current statement is used when the expression being evaluated has no
+// line (e.g. constants introduced by the preprocessor).
+func gasLine(m *Machine) int {
+	if m.Lastline != 0 || len(m.Stmts) == 0 {
+		return m.Lastline
+	}
+	s := m.PeekStmt(1)
+	if bs, ok := s.(*bodyStmt); ok {
+		if bs.NextBodyIndex <= 0 || bs.NextBodyIndex > len(bs.Body) {
+			return 0
+		}
+		s = bs.LastStmt()
+	}
+	return s.GetLine()
+}
+
+// Total returns the total gas recorded in p.
+func (p *GasProfile) Total() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var total int64
+	for _, s := range p.samples {
+		for _, gas := range s.gas {
+			total += gas
+		}
+	}
+	return total
+}
+
+// WriteProfile writes the gas profile in the (gzipped) protocol buffer format
+// of pprof, which can be inspected with `go tool pprof`. Each sample holds the
+// total gas consumed, followed by the gas of each kind. fileName is used to
+// determine the name of each file in the profile, for tools to find the
+// sources.
+func (p *GasProfile) WriteProfile(w io.Writer, fileName func(pkgPath, file string) string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var b pprofBuilder
+	b.init()
+	var prof []byte
+
+	// sample_type (1), default_sample_type (14)
+	gasUnit := b.str("gas")
+	prof = protowire.AppendTag(prof, 1, protowire.BytesType)
+	prof = protowire.AppendBytes(prof, pprofValueType(b.str("gas"), gasUnit))
+	for _, name := range gasKindNames {
+		prof = protowire.AppendTag(prof, 1, protowire.BytesType)
+		prof = protowire.AppendBytes(prof, pprofValueType(b.str(name), gasUnit))
+	}
+	prof = protowire.AppendTag(prof, 14, protowire.VarintType)
+	prof = protowire.AppendVarint(prof, uint64(b.str("gas")))
+
+	// sample (2)
+	for _, key := range p.order {
+		s := p.samples[key]
+		var locs, values []byte
+		for _, fr := range s.stack {
+			file := ""
+			if fr.File != "" {
+				file = fileName(fr.PkgPath, fr.File)
+			}
+			locs = protowire.AppendVarint(locs, b.location(fr.Func, file, fr.Line))
+		}
+		var total int64
+		for _, gas := range s.gas {
+			total += gas
+		}
+		values = protowire.AppendVarint(values, uint64(total))
+		for _, gas := range s.gas {
+			values = protowire.AppendVarint(values, uint64(gas))
+		}
+		var sample []byte
+		sample = protowire.AppendTag(sample, 1, protowire.BytesType)
+		sample = protowire.AppendBytes(sample, locs)
+		sample = protowire.AppendTag(sample, 2, protowire.BytesType)
+		sample = protowire.AppendBytes(sample, values)
+		prof = protowire.AppendTag(prof, 2, protowire.BytesType)
+		prof = protowire.AppendBytes(prof, sample)
+	}
+
+	// location (4), function (5), string_table (6)
+	for _, loc := range b.locations {
+		prof = protowire.AppendTag(prof, 4, protowire.BytesType)
+		prof = protowire.AppendBytes(prof, loc)
+	}
+	for _, fn := range b.functions {
+		prof = protowire.AppendTag(prof, 5, protowire.BytesType)
+		prof = protowire.AppendBytes(prof, fn)
+	}
+	for _, s := range b.strings {
+		prof = protowire.AppendTag(prof, 6, protowire.BytesType)
+		prof = protowire.AppendString(prof, s)
+	}
+
+	zw := gzip.NewWriter(w)
+	if _, err := zw.Write(prof); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// pprofBuilder holds the tables of a pprof profile being encoded.
+type pprofBuilder struct {
+	strings   []string
+	stringIDs map[string]int64
+	functions [][]byte
+	funcIDs   map[[2]string]uint64
+	locations [][]byte
+	locIDs    map[[2]uint64]uint64
+}
+
+func (b *pprofBuilder) init() {
+	b.strings = []string{""} // index 0 must be the empty string.
+	b.stringIDs = map[string]int64{"": 0}
+	b.funcIDs = make(map[[2]string]uint64)
+	b.locIDs = make(map[[2]uint64]uint64)
+}
+
+func (b *pprofBuilder) str(s string) int64 {
+	if id, ok := b.stringIDs[s]; ok {
+		return id
+	}
+	id := int64(len(b.strings))
+	b.strings = append(b.strings, s)
+	b.stringIDs[s] = id
+	return id
+}
+
+// location returns the id of the location of line in the function name,
+// declared in file.
+func (b *pprofBuilder) location(name, file string, line int) uint64 {
+	fkey := [2]string{name, file}
+	fid, ok := b.funcIDs[fkey]
+	if !ok {
+		fid = uint64(len(b.functions) + 1)
+		b.funcIDs[fkey] = fid
+		var fn []byte
+		fn = protowire.AppendTag(fn, 1, protowire.VarintType)
+		fn = protowire.AppendVarint(fn, fid)
+		fn = protowire.AppendTag(fn, 2, protowire.VarintType)
+		fn = protowire.AppendVarint(fn, uint64(b.str(name)))
+		fn = protowire.AppendTag(fn, 3, protowire.VarintType)
+		fn = protowire.AppendVarint(fn, uint64(b.str(name)))
+		fn = protowire.AppendTag(fn, 4, protowire.VarintType)
+		fn = protowire.AppendVarint(fn, uint64(b.str(file)))
+		b.functions = append(b.functions, fn)
+	}
+
+	lkey := [2]uint64{fid, uint64(line)}
+	if id, ok := b.locIDs[lkey]; ok {
+		return id
+	}
+	id := uint64(len(b.locations) + 1)
+	b.locIDs[lkey] = id
+	var ln []byte
+	ln = protowire.AppendTag(ln, 1, protowire.VarintType)
+	ln = protowire.AppendVarint(ln, fid)
+	ln = protowire.AppendTag(ln, 2, protowire.VarintType)
+	ln = protowire.AppendVarint(ln, uint64(line))
+	var loc []byte
+	loc = protowire.AppendTag(loc, 1, protowire.VarintType)
+	loc = protowire.AppendVarint(loc, id)
+	loc = protowire.AppendTag(loc, 4, protowire.BytesType)
+	loc = protowire.AppendBytes(loc, ln)
+	b.locations = append(b.locations, loc)
+	return id
+}
+
+func pprofValueType(typ, unit int64) []byte {
+	var vt []byte
+	vt = protowire.AppendTag(vt, 1, protowire.VarintType)
+	vt = protowire.AppendVarint(vt, uint64(typ))
+	vt = protowire.AppendTag(vt, 2, protowire.VarintType)
+	vt = protowire.AppendVarint(vt, uint64(unit))
+	return vt
+}
+
+// gasProfileMeter is a gas meter recording the gas it consumes in a
+// GasProfile.
+type gasProfileMeter struct {
+	store.GasMeter
+	profile *GasProfile
+}
+
+// ConsumeGas records the gas before consuming it, so that the consumption
+// running out of gas is part of the profile.
+func (gm *gasProfileMeter) ConsumeGas(amount store.Gas, descriptor string) {
+	gm.profile.record(amount, descriptor)
+	gm.GasMeter.ConsumeGas(amount, descriptor)
+}
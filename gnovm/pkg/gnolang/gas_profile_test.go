@@ -0,0 +1,158 @@
+package gnolang
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	stypes "github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+const gasProfileTestFile = `package main
+
+func fib(n int) int {
+	if n < 2 {
+		return n
+	}
+	return fib(n-1) + fib(n-2)
+}
+
+type T struct{ n int }
+
+func (t *T) Inc() { t.n++ }
+
+func main() {
+	s := []int{}
+	for i := 0; i < 10; i++ {
+		s = append(s, fib(i))
+	}
+	t := &T{}
+	func() {
+		t.Inc()
+	}()
+}
+`
+
+func TestGasProfile(t *testing.T) {
+	p := NewGasProfile()
+	gasMeter := stypes.NewInfiniteGasMeter()
+	m := NewMachineWithOptions(MachineOptions{
+		PkgPath:       "main",
+		MaxAllocBytes: 100_000_000,
+		GasMeter:      gasMeter,
+		GasProfile:    p,
+	})
+	m.RunFiles(m.MustParseFile("main.gno", gasProfileTestFile))
+	m.RunMain()
+	m.Release()
+
+	assert.Equal(t, gasMeter.GasConsumed(), p.Total())
+
+	// Gas consumed by the released machine isn't attributed to it.
+	p.GasMeter(nil).ConsumeGas(10, GasGetObjectDesc)
+
+	byFunc := map[string][numGasKinds]int64{}
+	lines := map[int]bool{}
+	for _, s := range p.samples {
+		leaf := s.stack[0]
+		g := byFunc[leaf.Func]
+		for k, gas := range s.gas {
+			g[k] += gas
+		}
+		byFunc[leaf.Func] = g
+		if leaf.Func == "main.fib" {
+			assert.Equal(t, "main.gno", leaf.File)
+			lines[leaf.Line] = true
+			// fib is called from main, or recursively.
+			caller := s.stack[1]
+			assert.Contains(t, []int{7, 17}, caller.Line)
+		}
+		if leaf.Func == "main.(*T).Inc" {
+			assert.Equal(t, "main.func@20", s.stack[1].Func)
+			assert.Equal(t, 21, s.stack[1].Line)
+		}
+	}
+	assert.Contains(t, byFunc, "main.(*T).Inc")
+	assert.Positive(t, byFunc["main.fib"][gasKindCPU])
+	assert.Positive(t, byFunc["main.main"][gasKindAlloc])
+	assert.Equal(t, int64(10), byFunc["(vm)"][gasKindStoreRead])
+	assert.True(t, lines[4] && lines[7], "lines of fib: %v", lines)
+
+	// Check the encoded profile.
+	var buf bytes.Buffer
+	fileName := func(pkgPath, file string) string { return path.Join("/src", pkgPath, file) }
+	require.NoError(t, p.WriteProfile(&buf, fileName))
+	zr, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	prof, err := io.ReadAll(zr)
+	require.NoError(t, err)
+
+	var (
+		strs                []string
+		numSampleTypes      int
+		numSamples          int
+		total, totalOfKinds int64
+	)
+	for len(prof) > 0 {
+		num, typ, n := protowire.ConsumeTag(prof)
+		require.GreaterOrEqual(t, n, 0)
+		prof = prof[n:]
+		if typ != protowire.BytesType {
+			_, n = protowire.ConsumeVarint(prof)
+			prof = prof[n:]
+			continue
+		}
+		b, n := protowire.ConsumeBytes(prof)
+		require.GreaterOrEqual(t, n, 0)
+		prof = prof[n:]
+		switch num {
+		case 1:
+			numSampleTypes++
+		case 2:
+			numSamples++
+			values := sampleValues(t, b)
+			require.Len(t, values, 1+int(numGasKinds))
+			total += values[0]
+			for _, v := range values[1:] {
+				totalOfKinds += v
+			}
+		case 6:
+			strs = append(strs, string(b))
+		}
+	}
+	assert.Equal(t, 1+int(numGasKinds), numSampleTypes)
+	assert.Equal(t, len(p.samples), numSamples)
+	assert.Equal(t, p.Total(), total)
+	assert.Equal(t, total, totalOfKinds)
+	assert.Equal(t, "", strs[0])
+	assert.Contains(t, strs, "main.fib")
+	assert.Contains(t, strs, "/src/main/main.gno")
+	assert.Contains(t, strs, "store_read")
+}
+
+// sampleValues returns the values of an encoded pprof sample.
+func sampleValues(t *testing.T, b []byte) (values []int64) {
+	t.Helper()
+	for len(b) > 0 {
+		num, _, n := protowire.ConsumeTag(b)
+		b = b[n:]
+		v, n := protowire.ConsumeBytes(b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+		if num != 2 {
+			continue
+		}
+		for len(v) > 0 {
+			x, n := protowire.ConsumeVarint(v)
+			v = v[n:]
+			values = append(values, int64(x))
+		}
+	}
+	return values
+}
@@ -0,0 +1,597 @@
+package gnolang
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Generic functions and types are declared as templates, which are never
+// preprocessed themselves. Their names are defined as constants of
+// *GenericType, and every reference to them must instantiate them with type
+// arguments, either explicitly as in `Max[int]` and `List[string]`, or for
+// functions by inference from the arguments of a call as in `Max(1, 2)`.
+//
+// An instance is a copy of the template with its type parameters bound to the
+// type arguments, preprocessed in the package of the template. Instances of
+// functions and methods are *FuncDecls named after the template and the type
+// arguments, e.g. `Max[int]`, located in the file of the template suffixed by
+// the type arguments, e.g. `max.gno[int]`. Instances of types are
+// *DeclaredTypes named the same way, e.g. `List[string]`, and are saved in
+// the store, so their TypeIDs are stable across transactions.
+
+// Defines the name of a generic function or type as a *GenericType constant.
+// Methods of generic types are instantiated with their receiver type, so they
+// don't define anything.
+func predefineGeneric(store Store, pkg *PackageNode, last BlockNode, d Decl) {
+	switch last.(type) {
+	case *PackageNode, *FileNode:
+	default:
+		panic("generic declarations must be at the package level")
+	}
+	var nx *NameExpr
+	var nst NSType
+	switch d := d.(type) {
+	case *TypeDecl:
+		nx, nst = &d.NameExpr, NSTypeDecl
+	case *FuncDecl:
+		if d.IsMethod {
+			return
+		}
+		nx, nst = &d.NameExpr, NSFuncDecl
+	default:
+		panic("should not happen")
+	}
+	if nx.Name == blankIdentifier {
+		return
+	}
+	gt := &GenericType{PkgPath: pkg.PkgPath, Name: nx.Name}
+	skipFile(last).Define2(true, nx.Name, gt, TypedValue{T: gt}, NameSource{nx, d, nst, -1})
+	nx.Path = last.GetPathForName(store, nx.Name)
+}
+
+// Generic functions and types can only be referred to in order to instantiate
+// them, by indexing or calling them.
+func assertInstantiated(cx *ConstExpr, ftype TransField) {
+	if gt, ok := cx.T.(*GenericType); ok {
+		if ftype != TRANS_INDEX_X && ftype != TRANS_CALL_FUNC {
+			panic(fmt.Sprintf("cannot use generic %s without instantiation", gt.Name))
+		}
+	}
+}
+
+// Returns the generic type of x, if x is a generic function or type.
+func genericTypeOf(x Expr) *GenericType {
+	if cx, ok := x.(*ConstExpr); ok {
+		if gt, ok := cx.T.(*GenericType); ok {
+			return gt
+		}
+	}
+	return nil
+}
+
+// Returns the template declaration of gt with its package and file.
+func genericDeclOf(store Store, last BlockNode, gt *GenericType) (*PackageNode, *FileNode, Decl) {
+	pn := packageOf(last)
+	if pn.PkgPath != gt.PkgPath {
+		pn = store.GetPackageNode(gt.PkgPath)
+	}
+	fn, d := pn.FileSet.GetDeclFor(gt.Name)
+	return pn, fn, *d
+}
+
+// Instantiates the generic function or type of n.X with the type arguments
+// targs, for n an *IndexExpr or *IndexListExpr. Generic functions that are
+// only partially instantiated are left as is if called, the remaining type
+// arguments being inferred from the call.
+func instantiateIndex(store Store, last BlockNode, n Expr, ftype TransField, gt *GenericType, targs []Expr) Expr {
+	types := make([]Type, len(targs))
+	for i, tx := range targs {
+		types[i] = evalStaticType(store, last, tx)
+	}
+	pn, fn, d := genericDeclOf(store, last, gt)
+	switch d := d.(type) {
+	case *TypeDecl:
+		if len(d.TypeParams) == 0 {
+			panic(fmt.Sprintf("cannot instantiate constraint interface %s", gt.Name))
+		}
+		assertNumTypeArgs(gt, d.TypeParams, types)
+		dt := instantiateType(store, pn, fn, d, types)
+		// n may be evaluated again, e.g. as a composite type.
+		n.SetAttribute(ATTR_TYPE_VALUE, dt)
+		return toConstTypeExpr(last, n, dt)
+	case *FuncDecl:
+		if len(types) < len(d.TypeParams) && ftype == TRANS_CALL_FUNC {
+			return n // see instantiateCall.
+		}
+		assertNumTypeArgs(gt, d.TypeParams, types)
+		fv := instantiateFunc(store, pn, fn, d, types)
+		return toConstExpr(n, TypedValue{T: fv.Type, V: fv})
+	default:
+		panic("should not happen")
+	}
+}
+
+// Instantiates the generic function called by n, inferring its type
+// arguments from those of the call. Returns nil if n.Func is not generic.
+func instantiateCall(store Store, last BlockNode, n *CallExpr) Expr {
+	var targs []Expr
+	gt := genericTypeOf(n.Func)
+	if gt == nil {
+		switch fx := n.Func.(type) {
+		case *IndexExpr:
+			gt, targs = genericTypeOf(fx.X), []Expr{fx.Index}
+		case *IndexListExpr:
+			gt, targs = genericTypeOf(fx.X), fx.Indices
+		}
+		if gt == nil {
+			return nil
+		}
+	}
+	pn, fn, d := genericDeclOf(store, last, gt)
+	fd, ok := d.(*FuncDecl)
+	if !ok {
+		panic(fmt.Sprintf("cannot use generic type %s without instantiation", gt.Name))
+	}
+	if len(targs) > len(fd.TypeParams) {
+		panic(fmt.Sprintf("got %d type arguments but %s has %d type parameters",
+			len(targs), gt.Name, len(fd.TypeParams)))
+	}
+	types := make([]Type, len(fd.TypeParams))
+	for i, tx := range targs {
+		types[i] = evalStaticType(store, last, tx)
+	}
+	inferTypeArgs(store, last, n, fd, types)
+	fv := instantiateFunc(store, pn, fn, fd, types)
+	return toConstExpr(n.Func, TypedValue{T: fv.Type, V: fv})
+}
+
+func assertNumTypeArgs(gt *GenericType, tparams FieldTypeExprs, types []Type) {
+	if len(types) != len(tparams) {
+		panic(fmt.Sprintf("wrong number of type arguments for %s: have %d, want %d",
+			gt.Name, len(types), len(tparams)))
+	}
+}
+
+// Returns the suffix of the names and file names of instances, e.g.
+// `[int,string]`.
+func typeArgsSuffix(types []Type) string {
+	ss := make([]string, len(types))
+	for i, t := range types {
+		ss[i] = t.TypeID().String()
+	}
+	return "[" + strings.Join(ss, ",") + "]"
+}
+
+// Returns the instance of the generic function fd, preprocessing it upon the
+// first instantiation.
+func instantiateFunc(store Store, pn *PackageNode, fn *FileNode, fd *FuncDecl, types []Type) *FuncValue {
+	suffix := typeArgsSuffix(types)
+	loc := Location{PkgPath: pn.PkgPath, File: fn.FileName + suffix, Span: fd.GetSpan()}
+	inst, _ := store.GetBlockNodeSafe(loc).(*FuncDecl)
+	if inst == nil {
+		inst = newInstance(store, fn, fd, types, loc)
+		// set before preprocessing, for recursive functions.
+		store.SetBlockNode(inst)
+		tpb := typeParamsBlock(fn, inst.TypeParams)
+		predefineDeps(store, pn, tpb, &inst.Type)
+		inst.Type = *Preprocess(store, tpb, &inst.Type).(*FuncTypeExpr)
+		evalStaticType(store, tpb, &inst.Type)
+		preprocessInstance(store, pn, inst)
+	}
+	ft := getType(&inst.Type).(*FuncType)
+	return &FuncValue{
+		Type:     ft,
+		IsMethod: false,
+		Source:   inst,
+		Name:     inst.Name,
+		Parent:   nil, // set lazily.
+		FileName: fn.FileName,
+		PkgPath:  pn.PkgPath,
+		Crossing: ft.IsCrossing(),
+		body:     nil, // set lazily.
+	}
+}
+
+// Returns the instance of the generic type td, constructing it and its
+// methods upon the first instantiation.
+func instantiateType(store Store, pn *PackageNode, fn *FileNode, td *TypeDecl, types []Type) *DeclaredType {
+	name := td.Name + Name(typeArgsSuffix(types))
+	tid := DeclaredTypeID(pn.PkgPath, Location{}, name)
+	if dt, ok := store.GetTypeSafe(tid).(*DeclaredType); ok {
+		// an unsealed type is being constructed, e.g. for `type
+		// node[T any] struct { next *node[T] }`.
+		if dt.sealed {
+			// the type may have been saved by a prior
+			// transaction, but not the nodes of its methods.
+			instantiateMethods(store, pn, td, dt, types)
+		}
+		return dt
+	}
+	tx := copyGeneric(td.Type).(Expr)
+	// like in tryPredefine, the base is a placeholder for recursive
+	// definitions until evaluated.
+	var bt Type
+	switch tx.(type) {
+	case *FuncTypeExpr:
+		bt = &FuncType{}
+	case *ArrayTypeExpr:
+		bt = &ArrayType{}
+	case *SliceTypeExpr:
+		bt = &SliceType{}
+	case *InterfaceTypeExpr:
+		bt = &InterfaceType{}
+	case *ChanTypeExpr:
+		bt = &ChanType{}
+	case *MapTypeExpr:
+		bt = &MapType{}
+	case *StructTypeExpr:
+		bt = &StructType{}
+	case *StarExpr:
+		bt = &PointerType{}
+	}
+	dt := declareWith(pn.PkgPath, fn, name, bt)
+	store.SetCacheType(dt)
+	tparams := copyFTs(td.TypeParams)
+	bindTypeParams(fn, tparams, types)
+	tpb := typeParamsBlock(fn, tparams)
+	predefineDeps(store, pn, tpb, tx)
+	tx = Preprocess(store, tpb, tx).(Expr)
+	*dt = *declareWith(pn.PkgPath, fn, name, evalStaticType(store, tpb, tx))
+	dt.Seal()
+	instantiateMethods(store, pn, td, dt, types)
+	store.SetType(dt)
+	return dt
+}
+
+// Instantiates the methods of the generic type td for its instance dt.
+// All method signatures are defined before any body is preprocessed, since
+// bodies may call any method.
+func instantiateMethods(store Store, pn *PackageNode, td *TypeDecl, dt *DeclaredType, types []Type) {
+	suffix := typeArgsSuffix(types)
+	var insts []*FuncDecl
+	for _, fn := range pn.FileSet.Files {
+		for _, d := range fn.Decls {
+			md, ok := d.(*FuncDecl)
+			if !ok || !md.IsMethod || !isGenericDecl(md) || recvTypeNameOf(md) != td.Name {
+				continue
+			}
+			loc := Location{PkgPath: pn.PkgPath, File: fn.FileName + suffix, Span: md.GetSpan()}
+			if store.GetBlockNodeSafe(loc) != nil {
+				// instantiated already, or being instantiated
+				// by the caller.
+				continue
+			}
+			if len(md.TypeParams) != len(td.TypeParams) {
+				panic(fmt.Sprintf("receiver of method %s.%s has %d type parameters, want %d",
+					td.Name, md.Name, len(md.TypeParams), len(td.TypeParams)))
+			}
+			inst := newInstance(store, fn, md, types, loc)
+			rt := Type(dt)
+			if _, ok := md.Recv.Type.(*StarExpr); ok {
+				rt = &PointerType{Elt: dt}
+			}
+			inst.Recv.Type = toConstTypeExpr(fn, inst.Recv.Type, rt)
+			store.SetBlockNode(inst)
+			insts = append(insts, inst)
+		}
+	}
+	for _, inst := range insts {
+		fn := inst.GetParentNode(nil).(*FileNode)
+		tpb := typeParamsBlock(fn, inst.TypeParams)
+		predefineDeps(store, pn, tpb, &inst.Type)
+		inst.Recv = *Preprocess(store, tpb, &inst.Recv).(*FieldTypeExpr)
+		inst.Type = *Preprocess(store, tpb, &inst.Type).(*FuncTypeExpr)
+		rft := evalStaticType(store, tpb, &inst.Recv).(FieldType)
+		ft := evalStaticType(store, tpb, &inst.Type).(*FuncType)
+		if !dt.TryDefineMethod(&FuncValue{
+			Type:     ft.UnboundType(rft),
+			IsMethod: true,
+			Source:   inst,
+			Name:     inst.Name,
+			Parent:   nil, // set lazily.
+			FileName: fn.FileName,
+			PkgPath:  pn.PkgPath,
+			Crossing: ft.IsCrossing(),
+			body:     nil, // set lazily.
+		}) {
+			panic(fmt.Sprintf("redeclaration of method %s.%s",
+				td.Name, inst.Name))
+		}
+	}
+	for _, inst := range insts {
+		preprocessInstance(store, pn, inst)
+	}
+}
+
+// Returns the name of the receiver base type of a method, e.g. List for
+// `func (l *List[T]) Len() int`.
+func recvTypeNameOf(md *FuncDecl) Name {
+	rx := md.Recv.Type
+	if sx, ok := rx.(*StarExpr); ok {
+		rx = sx.X
+	}
+	switch x := rx.(type) {
+	case *IndexExpr:
+		rx = x.X
+	case *IndexListExpr:
+		rx = x.X
+	}
+	if nx, ok := rx.(*NameExpr); ok {
+		return nx.Name
+	}
+	return ""
+}
+
+// Returns a copy of the template fd with its type parameters bound to types,
+// located at loc. Methods keep their name; functions are suffixed by their
+// type arguments.
+func newInstance(store Store, fn *FileNode, fd *FuncDecl, types []Type, loc Location) *FuncDecl {
+	inst := copyGeneric(fd).(*FuncDecl)
+	if !inst.IsMethod {
+		inst.Name += Name(typeArgsSuffix(types))
+	}
+	bindTypeParams(fn, inst.TypeParams, types)
+	setNodeLocations(loc.PkgPath, loc.File, inst)
+	inst.SetAttribute(ATTR_PREDEFINED, true)
+	initStaticBlocks(store, fn, inst)
+	return inst
+}
+
+// Copies the template n, keeping the spans and labels of its nodes which
+// Copy() does not, since instances are located by their spans.
+func copyGeneric(n Node) Node {
+	var srcs []Node
+	Transcribe(n, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage == TRANS_ENTER {
+			srcs = append(srcs, n)
+		}
+		return n, TRANS_CONTINUE
+	})
+	i := 0
+	return Transcribe(n.Copy(), func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage == TRANS_ENTER {
+			src := srcs[i]
+			i++
+			n.SetSpan(src.GetSpan())
+			n.SetLabel(src.GetLabel())
+			if iota := src.GetAttribute(ATTR_IOTA); iota != nil {
+				n.SetAttribute(ATTR_IOTA, iota)
+			}
+		}
+		return n, TRANS_CONTINUE
+	})
+}
+
+func bindTypeParams(last BlockNode, tparams FieldTypeExprs, types []Type) {
+	for i := range tparams {
+		tp := &tparams[i]
+		tp.Type = toConstTypeExpr(last, tp.Type, types[i])
+	}
+}
+
+// Returns a block defining the bound type parameters tparams, wherein the
+// signature of instances is preprocessed.
+func typeParamsBlock(fn *FileNode, tparams FieldTypeExprs) BlockNode {
+	bs := &BlockStmt{}
+	bs.InitStaticBlock(bs, fn)
+	for i := range tparams {
+		tp := &tparams[i]
+		if tp.Name == blankIdentifier {
+			continue
+		}
+		t := getType(tp.Type)
+		bs.Define2(true, tp.Name, t, asValue(t), NameSource{&tp.NameExpr, bs, NSTypeParam, i})
+	}
+	return bs
+}
+
+// Predefines the declarations of pn that x depends on, for instantiations
+// happening while pn is itself being predefined.
+func predefineDeps(store Store, pn *PackageNode, last BlockNode, x Expr) {
+	for {
+		un, _ := findUndefinedT(store, last, x, nil, map[Name]struct{}{}, false, false)
+		if un == "" {
+			return
+		}
+		fn, d, ok := pn.FileSet.GetDeclForSafe(un)
+		if !ok || (*d).GetAttribute(ATTR_PREDEFINED) == true {
+			return // let the preprocessor fail.
+		}
+		predefineRecursively(store, fn, *d)
+	}
+}
+
+// Preprocesses the body of the instance inst of pn, unless pn is being
+// predefined, in which case the body may refer to names not yet defined and
+// is preprocessed along with the file being preprocessed; see
+// preprocessPendingInstances.
+func preprocessInstance(store Store, pn *PackageNode, inst *FuncDecl) {
+	if !isPackagePredefined(pn) {
+		pending, _ := pn.GetAttribute(ATTR_PENDING_INSTANCES).([]*FuncDecl)
+		pn.SetAttribute(ATTR_PENDING_INSTANCES, append(pending, inst))
+		return
+	}
+	Preprocess(store, inst.GetParentNode(nil), inst)
+	saveInstanceBlockNodes(store, inst)
+}
+
+func preprocessPendingInstances(store Store, pn *PackageNode) {
+	for isPackagePredefined(pn) {
+		pending, _ := pn.GetAttribute(ATTR_PENDING_INSTANCES).([]*FuncDecl)
+		if len(pending) == 0 {
+			return
+		}
+		inst := pending[0]
+		pn.SetAttribute(ATTR_PENDING_INSTANCES, pending[1:])
+		preprocessInstance(store, pn, inst)
+	}
+}
+
+func isPackagePredefined(pn *PackageNode) bool {
+	if pn.FileSet == nil {
+		return true
+	}
+	for _, fn := range pn.FileSet.Files {
+		for _, d := range fn.Decls {
+			if d.GetAttribute(ATTR_PREDEFINED) != true {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Like SaveBlockNodes, for the block nodes of an instance.
+func saveInstanceBlockNodes(store Store, inst *FuncDecl) {
+	Transcribe(inst, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER {
+			return n, TRANS_CONTINUE
+		}
+		if bn, ok := n.(BlockNode); ok {
+			store.SetBlockNode(bn)
+		}
+		return n, TRANS_CONTINUE
+	})
+}
+
+// ----------------------------------------
+// Type argument inference
+
+// Infers the missing (nil) types of a call n to the generic function fd,
+// by unifying the types of the parameters of fd with those of the
+// arguments. Typed arguments are unified first; the remaining type
+// parameters of untyped constant arguments get their default types.
+func inferTypeArgs(store Store, last BlockNode, n *CallExpr, fd *FuncDecl, types []Type) {
+	u := typeUnifier{
+		types:   types,
+		untyped: make([]Type, len(types)),
+		index:   make(map[Name]int, len(types)),
+	}
+	for i, tp := range fd.TypeParams {
+		if tp.Name != blankIdentifier {
+			u.index[tp.Name] = i
+		}
+	}
+	// the types of the arguments, e.g. of g() in f(g()).
+	var ats []Type
+	if len(n.Args) == 1 {
+		if tt, ok := evalStaticTypeOfRaw(store, last, n.Args[0]).(*tupleType); ok {
+			ats = tt.Elts
+		}
+	}
+	if ats == nil {
+		ats = make([]Type, len(n.Args))
+		for i, arg := range n.Args {
+			ats[i] = evalStaticTypeOf(store, last, arg)
+		}
+	}
+	// returns the type expr of the parameter of the i-th argument.
+	params := fd.Type.Params
+	paramOf := func(i int) Expr {
+		if np := len(params); np > 0 && i >= np-1 {
+			if stx, ok := params[np-1].Type.(*SliceTypeExpr); ok && stx.Vrd {
+				if n.Varg {
+					return stx
+				}
+				return stx.Elt
+			}
+		}
+		if i < len(params) {
+			return params[i].Type
+		}
+		return nil
+	}
+	for i, at := range ats {
+		if px := paramOf(i); px != nil && at != nil && !isUntyped(at) {
+			u.unify(px, at)
+		}
+	}
+	for i, at := range ats {
+		if nx, ok := paramOf(i).(*NameExpr); ok && at != nil && isUntyped(at) {
+			u.unifyUntyped(nx.Name, at)
+		}
+	}
+	for i, t := range types {
+		if t != nil {
+			continue
+		}
+		if ut := u.untyped[i]; ut != nil {
+			types[i] = defaultTypeOf(ut)
+			continue
+		}
+		panic(fmt.Sprintf("in call to %s, cannot infer %s",
+			fd.Name, fd.TypeParams[i].Name))
+	}
+}
+
+type typeUnifier struct {
+	types   []Type       // bound type parameters, or nil
+	untyped []Type       // untyped constant types of unbound type parameters
+	index   map[Name]int // type parameter indices by name
+}
+
+// Binds the type parameters in the type expr px to the corresponding types
+// of at. Mismatches are left for the preprocessor to report.
+func (u *typeUnifier) unify(px Expr, at Type) {
+	switch px := px.(type) {
+	case *NameExpr:
+		if i, ok := u.index[px.Name]; ok && u.types[i] == nil {
+			u.types[i] = at
+		}
+	case *StarExpr:
+		if pt, ok := baseOf(at).(*PointerType); ok {
+			u.unify(px.X, pt.Elt)
+		}
+	case *ArrayTypeExpr:
+		if at, ok := baseOf(at).(*ArrayType); ok {
+			u.unify(px.Elt, at.Elt)
+		}
+	case *SliceTypeExpr:
+		if st, ok := baseOf(at).(*SliceType); ok {
+			u.unify(px.Elt, st.Elt)
+		}
+	case *MapTypeExpr:
+		if mt, ok := baseOf(at).(*MapType); ok {
+			u.unify(px.Key, mt.Key)
+			u.unify(px.Value, mt.Value)
+		}
+	case *ChanTypeExpr:
+		if ct, ok := baseOf(at).(*ChanType); ok {
+			u.unify(px.Value, ct.Elt)
+		}
+	case *FuncTypeExpr:
+		if ft, ok := baseOf(at).(*FuncType); ok {
+			for i := 0; i < len(px.Params) && i < len(ft.Params); i++ {
+				u.unify(px.Params[i].Type, ft.Params[i].Type)
+			}
+			for i := 0; i < len(px.Results) && i < len(ft.Results); i++ {
+				u.unify(px.Results[i].Type, ft.Results[i].Type)
+			}
+		}
+	}
+}
+
+// Notes the untyped constant type ut for the type parameter name, keeping
+// the one with the largest default type, like for `Max(1, 2.5)`.
+func (u *typeUnifier) unifyUntyped(name Name, ut Type) {
+	i, ok := u.index[name]
+	if !ok || u.types[i] != nil {
+		return
+	}
+	if u.untyped[i] == nil || untypedRank(ut) > untypedRank(u.untyped[i]) {
+		u.untyped[i] = ut
+	}
+}
+
+func untypedRank(t Type) int {
+	switch t {
+	case UntypedBigintType:
+		return 1
+	case UntypedRuneType:
+		return 2
+	case UntypedBigdecType:
+		return 3
+	default:
+		return 0
+	}
+}
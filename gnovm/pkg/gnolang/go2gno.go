@@ -532,6 +532,7 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 	case *ast.FuncDecl:
 		isMethod := gon.Recv != nil
 		recv := FieldTypeExpr{}
+		var tparams FieldTypeExprs
 		if isMethod {
 			if len(gon.Recv.List) > 1 {
 				panicWithPos("method has multiple receivers")
@@ -540,6 +541,9 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 				panicWithPos("method has no receiver")
 			}
 			recv = *Go2Gno(fs, gon.Recv.List[0]).(*FieldTypeExpr)
+			tparams = toRecvTypeParams(recv.Type)
+		} else {
+			tparams = toFieldsFromList(fs, gon.Type.TypeParams)
 		}
 		name := toName(gon.Name)
 		type_ := Go2Gno(fs, gon.Type).(*FuncTypeExpr)
@@ -548,11 +552,12 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 			body = Go2Gno(fs, gon.Body).(*BlockStmt).Body
 		}
 		return &FuncDecl{
-			IsMethod: isMethod,
-			Recv:     recv,
-			NameExpr: NameExpr{Name: name},
-			Type:     *type_,
-			Body:     body,
+			IsMethod:   isMethod,
+			Recv:       recv,
+			TypeParams: tparams,
+			NameExpr:   NameExpr{Name: name},
+			Type:       *type_,
+			Body:       body,
 		}
 	case *ast.GenDecl:
 		panicWithPos("unexpected *ast.GenDecl; use toDecls(fs,) instead")
@@ -574,10 +579,10 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 	case *ast.EmptyStmt:
 		return &EmptyStmt{}
 	case *ast.IndexListExpr:
-		if len(gon.Indices) > 1 {
-			panicWithPos("invalid operation: more than one index")
+		return &IndexListExpr{
+			X:       toExpr(fs, gon.X),
+			Indices: toExprs(fs, gon.Indices),
 		}
-		panicWithPos("invalid operation: indexList is not permitted in Gno")
 	case *ast.GoStmt:
 		panicWithPos("goroutines are not permitted")
 	default:
@@ -665,6 +670,7 @@ var token2word = map[token.Token]Word{
 	token.STRUCT:         STRUCT,
 	token.TYPE:           TYPE,
 	token.VAR:            VAR,
+	token.TILDE:          TILDE,
 }
 
 func toWord(tok token.Token) Word {
@@ -751,10 +757,15 @@ func toDecls(fs *token.FileSet, gd *ast.GenDecl) (ds Decls) {
 			name := toName(s.Name)
 			tipe := toExpr(fs, s.Type)
 			alias := s.Assign != 0
+			tparams := toFieldsFromList(fs, s.TypeParams)
+			if alias && len(tparams) > 0 {
+				panic("generic type aliases are not supported")
+			}
 			td := &TypeDecl{
-				NameExpr: NameExpr{Name: name},
-				Type:     tipe,
-				IsAlias:  alias,
+				NameExpr:   NameExpr{Name: name},
+				TypeParams: tparams,
+				Type:       tipe,
+				IsAlias:    alias,
 			}
 			setSpan(fs, s, td)
 			ds = append(ds, td)
@@ -885,6 +896,35 @@ func toFields(fs *token.FileSet, fields ...*ast.Field) (ftxs []FieldTypeExpr) {
 	return
 }
 
+// The type parameters of a method are those of its receiver type, e.g. K and
+// V for a receiver of type *Map[K, V]. Their constraints are those of the
+// type declaration, so they are only checked by the type checker.
+func toRecvTypeParams(rt Expr) (tparams FieldTypeExprs) {
+	if sx, ok := rt.(*StarExpr); ok {
+		rt = sx.X
+	}
+	var xs Exprs
+	switch rt := rt.(type) {
+	case *IndexExpr:
+		xs = Exprs{rt.Index}
+	case *IndexListExpr:
+		xs = rt.Indices
+	default:
+		return nil
+	}
+	for _, x := range xs {
+		nx, ok := x.(*NameExpr)
+		if !ok {
+			panic(fmt.Sprintf("invalid receiver type parameter %s", x.String()))
+		}
+		tparams = append(tparams, FieldTypeExpr{
+			NameExpr: *Nx(nx.Name),
+			Type:     Nx("any"),
+		})
+	}
+	return tparams
+}
+
 func toKeyValueExprs(fs *token.FileSet, elts []ast.Expr) (kvxs KeyValueExprs) {
 	kvxs = make([]KeyValueExpr, len(elts))
 	for i, x := range elts {
@@ -347,6 +347,17 @@ func (gimp *gnoImporter) ImportFrom(pkgPath, _ string, _ types.ImportMode) (gopk
 		result.pending = false
 		return nil, err
 	}
+	if mod != nil && mod.Upgradable {
+		// If the package is upgradable, we cannot import it: the
+		// preprocessed code of importers refers to the declarations of
+		// the package by their index in its block, which an upgrade
+		// may change (see Machine.UpgradeMemPackage).
+		err := ImportUpgradableError{PkgPath: pkgPath}
+		// NOTE: see comment above for ImportNotFoundError.
+		result.err = err
+		result.pending = false
+		return nil, err
+	}
 	wtests := gimp.testing && gimp.pkgPath == pkgPath
 	pkg, errs := gimp.typeCheckMemPackage(mpkg, &wtests)
 	if errs != nil {
@@ -725,14 +736,16 @@ type ImportError interface {
 	GetMsg() string
 }
 
-func (e ImportNotFoundError) assertImportError() {}
-func (e ImportPrivateError) assertImportError()  {}
-func (e ImportDraftError) assertImportError()    {}
-func (e ImportCycleError) assertImportError()    {}
+func (e ImportNotFoundError) assertImportError()   {}
+func (e ImportPrivateError) assertImportError()    {}
+func (e ImportUpgradableError) assertImportError() {}
+func (e ImportDraftError) assertImportError()      {}
+func (e ImportCycleError) assertImportError()      {}
 
 var (
 	_ ImportError = ImportNotFoundError{}
 	_ ImportError = ImportPrivateError{}
+	_ ImportError = ImportUpgradableError{}
 	_ ImportError = ImportDraftError{}
 	_ ImportError = ImportCycleError{}
 )
@@ -777,6 +790,20 @@ func (e ImportPrivateError) GetMsg() string {
 
 func (e ImportPrivateError) Error() string { return importErrorString(e) }
 
+// ImportUpgradableError implements ImportError
+type ImportUpgradableError struct {
+	Location string
+	PkgPath  string
+}
+
+func (e ImportUpgradableError) GetLocation() string { return e.Location }
+
+func (e ImportUpgradableError) GetMsg() string {
+	return fmt.Sprintf("import path %q is upgradable and cannot be imported", e.PkgPath)
+}
+
+func (e ImportUpgradableError) Error() string { return importErrorString(e) }
+
 // ImportCycleError implements ImportError
 type ImportCycleError struct {
 	Location string
@@ -41,7 +41,9 @@ type Machine struct {
 	ReviveEnabled bool          // true if revive() enabled (only in testing mode for now)
 	Lastline      int           // the line the VM is currently executing
 
-	Debugger Debugger
+	Debugger   Debugger
+	Coverage   *Coverage   // if set, records executed statements
+	GasProfile *GasProfile // if set, records consumed gas, see [GasProfile.Attach]
 
 	// Configuration
 	Output   io.Writer
@@ -80,7 +82,9 @@ type MachineOptions struct {
 	MaxAllocBytes int64      // or 0 for no limit.
 	GasMeter      store.GasMeter
 	ReviveEnabled bool
-	SkipPackage   bool // don't get/set package or realm.
+	SkipPackage   bool        // don't get/set package or realm.
+	Coverage      *Coverage   // if set, records executed statements.
+	GasProfile    *GasProfile // if set, records consumed gas.
 }
 
 const (
@@ -141,6 +145,10 @@ func NewMachineWithOptions(opts MachineOptions) *Machine {
 	mm.Debugger.in = opts.Input
 	mm.Debugger.out = output
 	mm.ReviveEnabled = opts.ReviveEnabled
+	mm.Coverage = opts.Coverage
+	if opts.GasProfile != nil {
+		opts.GasProfile.Attach(mm)
+	}
 	// Maybe get/set package and realm.
 	if !opts.SkipPackage && opts.PkgPath != "" {
 		pv := (*PackageValue)(nil)
@@ -165,6 +173,9 @@ func NewMachineWithOptions(opts MachineOptions) *Machine {
 // and m should not be used after this call. Only Machines initialized with this
 // package's constructors should be released.
 func (m *Machine) Release() {
+	if m.GasProfile != nil {
+		m.GasProfile.detach(m)
+	}
 	// here we zero in the values for the next user
 	ops, values := m.Ops[:0:startingOpsCap], m.Values[:0:startingValuesCap]
 	clear(ops[:startingOpsCap])
@@ -196,28 +207,33 @@ func (m *Machine) SetActivePackage(pv *PackageValue) {
 func (m *Machine) PreprocessAllFilesAndSaveBlockNodes() {
 	ch := m.Store.IterMemPackage()
 	for mpkg := range ch {
-		mpkg = MPFProd.FilterMemPackage(mpkg)
-		fset := m.ParseMemPackage(mpkg)
-		pn := NewPackageNode(Name(mpkg.Name), mpkg.Path, fset)
-		m.Store.SetBlockNode(pn)
-		PredefineFileSet(m.Store, pn, fset)
-		for _, fn := range fset.Files {
-			// Save Types to m.Store (while preprocessing).
-			fn = Preprocess(m.Store, pn, fn).(*FileNode)
-			// Save BlockNodes to m.Store.
-			SaveBlockNodes(m.Store, fn)
-		}
-		// Normally, the fileset would be added onto the
-		// package node only after runFiles(), but we cannot
-		// run files upon restart (only preprocess them).
-		// So, add them here instead.
-		// TODO: is this right?
-		if pn.FileSet == nil {
-			pn.FileSet = fset
-		}
-		// pn.FileSet != nil happens for non-realm file tests.
-		// TODO ensure the files are the same.
+		m.preprocessMemPackageAndSaveBlockNodes(mpkg)
+	}
+}
+
+// Preprocesses mpkg, already in the store, and saves its blocknodes.
+func (m *Machine) preprocessMemPackageAndSaveBlockNodes(mpkg *std.MemPackage) {
+	mpkg = MPFProd.FilterMemPackage(mpkg)
+	fset := m.ParseMemPackage(mpkg)
+	pn := NewPackageNode(Name(mpkg.Name), mpkg.Path, fset)
+	m.Store.SetBlockNode(pn)
+	PredefineFileSet(m.Store, pn, fset)
+	for _, fn := range fset.Files {
+		// Save Types to m.Store (while preprocessing).
+		fn = Preprocess(m.Store, pn, fn).(*FileNode)
+		// Save BlockNodes to m.Store.
+		SaveBlockNodes(m.Store, fn)
+	}
+	// Normally, the fileset would be added onto the
+	// package node only after runFiles(), but we cannot
+	// run files upon restart (only preprocess them).
+	// So, add them here instead.
+	// TODO: is this right?
+	if pn.FileSet == nil {
+		pn.FileSet = fset
 	}
+	// pn.FileSet != nil happens for non-realm file tests.
+	// TODO ensure the files are the same.
 }
 
 //----------------------------------------
@@ -290,7 +306,7 @@ func (m *Machine) runMemPackage(mpkg *std.MemPackage, save, overrides bool) (*Pa
 	}
 	m.SetActivePackage(pv)
 	// run files.
-	updates := m.runFileDecls(overrides, files.Files...)
+	updates := m.runFileDecls(overrides, nil, files.Files...)
 	// populate pv.fBlocksMap.
 	pv.deriveFBlocksMap(m.Store)
 	// save package value and mempackage.
@@ -455,7 +471,7 @@ func (m *Machine) RunFiles(fns ...*FileNode) {
 	if rlm == nil && pv.IsRealm() {
 		rlm = NewRealm(pv.PkgPath) // throwaway
 	}
-	updates := m.runFileDecls(IsStdlib(pv.PkgPath), fns...)
+	updates := m.runFileDecls(IsStdlib(pv.PkgPath), nil, fns...)
 	if rlm != nil {
 		pb := pv.GetBlock(m.Store)
 		for _, update := range updates {
@@ -528,7 +544,9 @@ func (m *Machine) PreprocessFiles(pkgName, pkgPath string, fset *FileSet, save,
 // This will also run each init function encountered.
 // Returns the updated typed values of package.
 // m.Package must match fns's package path.
-func (m *Machine) runFileDecls(withOverrides bool, fns ...*FileNode) []TypedValue {
+// If skip is not nil, the declarations for which it returns true are not run,
+// e.g. to keep the values of variables when upgrading a package.
+func (m *Machine) runFileDecls(withOverrides bool, skip func(Decl) bool, fns ...*FileNode) []TypedValue {
 	// Files' package names must match the machine's active one.
 	// if there is one.
 	for _, fn := range fns {
@@ -599,6 +617,16 @@ func (m *Machine) runFileDecls(withOverrides bool, fns ...*FileNode) []TypedValu
 	// recursive function for var declarations.
 	var runDeclarationFor func(fn *FileNode, decl Decl)
 	runDeclarationFor = func(fn *FileNode, decl Decl) {
+		// templates are only declared upon instantiation.
+		if isGenericDecl(decl) {
+			return
+		}
+		if skip != nil && skip(decl) {
+			for _, n := range decl.GetDeclNames() {
+				fdeclared[n] = struct{}{}
+			}
+			return
+		}
 		// get fileblock of fn.
 		// fb := pv.GetFileBlock(nil, fn.FileName)
 		// get dependencies of decl.
@@ -101,6 +101,9 @@ const (
 	SWITCH
 	TYPE
 	VAR
+
+	// Type constraints
+	TILDE // ~
 )
 
 type Name string
@@ -139,9 +142,10 @@ const (
 	ATTR_LAST_BLOCK_STMT       GnoAttribute = "ATTR_LAST_BLOCK_STMT"
 	ATTR_PACKAGE_REF           GnoAttribute = "ATTR_PACKAGE_REF"
 	ATTR_PACKAGE_DECL          GnoAttribute = "ATTR_PACKAGE_DECL"
-	ATTR_PACKAGE_PATH          GnoAttribute = "ATTR_PACKAGE_PATH" // if name expr refers to package.
-	ATTR_FIX_FROM              GnoAttribute = "ATTR_FIX_FROM"     // gno fix this version.
-	ATTR_LOOPVAR_SKIP          GnoAttribute = "ATTR_LOOPVAR_SKIP" // temp only
+	ATTR_PACKAGE_PATH          GnoAttribute = "ATTR_PACKAGE_PATH"      // if name expr refers to package.
+	ATTR_FIX_FROM              GnoAttribute = "ATTR_FIX_FROM"          // gno fix this version.
+	ATTR_LOOPVAR_SKIP          GnoAttribute = "ATTR_LOOPVAR_SKIP"      // temp only
+	ATTR_PENDING_INSTANCES     GnoAttribute = "ATTR_PENDING_INSTANCES" // []*FuncDecl generic instances.
 )
 
 // Embedded in each Node.
@@ -226,6 +230,7 @@ func (*BasicLitExpr) assertNode()      {}
 func (*BinaryExpr) assertNode()        {}
 func (*CallExpr) assertNode()          {}
 func (*IndexExpr) assertNode()         {}
+func (*IndexListExpr) assertNode()     {}
 func (*SelectorExpr) assertNode()      {}
 func (*SliceExpr) assertNode()         {}
 func (*StarExpr) assertNode()          {}
@@ -278,6 +283,7 @@ var (
 	_ Node = &BinaryExpr{}
 	_ Node = &CallExpr{}
 	_ Node = &IndexExpr{}
+	_ Node = &IndexListExpr{}
 	_ Node = &SelectorExpr{}
 	_ Node = &SliceExpr{}
 	_ Node = &StarExpr{}
@@ -342,6 +348,7 @@ func (*BasicLitExpr) assertExpr()     {}
 func (*BinaryExpr) assertExpr()       {}
 func (*CallExpr) assertExpr()         {}
 func (*IndexExpr) assertExpr()        {}
+func (*IndexListExpr) assertExpr()    {}
 func (*SelectorExpr) assertExpr()     {}
 func (*SliceExpr) assertExpr()        {}
 func (*StarExpr) assertExpr()         {}
@@ -359,6 +366,7 @@ var (
 	_ Expr = &BinaryExpr{}
 	_ Expr = &CallExpr{}
 	_ Expr = &IndexExpr{}
+	_ Expr = &IndexListExpr{}
 	_ Expr = &SelectorExpr{}
 	_ Expr = &SliceExpr{}
 	_ Expr = &StarExpr{}
@@ -467,6 +475,12 @@ type IndexExpr struct { // X[Index]
 	HasOK bool // if true, is form: `value, ok := <X>[<Key>]
 }
 
+type IndexListExpr struct { // X[Indices...]
+	Attributes
+	X       Expr  // generic function or type
+	Indices Exprs // type arguments
+}
+
 type SelectorExpr struct { // X.Sel
 	Attributes
 	X    Expr      // expression
@@ -1092,10 +1106,11 @@ type FuncDecl struct {
 	Attributes
 	StaticBlock
 	NameExpr
-	IsMethod bool
-	Recv     FieldTypeExpr // receiver (if method); or empty (if function)
-	Type     FuncTypeExpr  // function signature: parameters and results
-	Body                   // function body; or empty for external (non-Go) function
+	IsMethod   bool
+	Recv       FieldTypeExpr  // receiver (if method); or empty (if function)
+	Type       FuncTypeExpr   // function signature: parameters and results
+	Body                      // function body; or empty for external (non-Go) function
+	TypeParams FieldTypeExprs // type parameters (of the receiver if method); or empty
 
 	unboundType *FuncTypeExpr // memoized
 }
@@ -1181,8 +1196,9 @@ func (x *ValueDecl) GetDeclNames() []Name {
 type TypeDecl struct {
 	Attributes
 	NameExpr
-	Type    Expr // Name, SelectorExpr, StarExpr, or XxxTypes
-	IsAlias bool // type alias since Go 1.9
+	Type       Expr           // Name, SelectorExpr, StarExpr, or XxxTypes
+	IsAlias    bool           // type alias since Go 1.9
+	TypeParams FieldTypeExprs // type parameters; or empty
 }
 
 func (x *TypeDecl) GetDeclNames() []Name {
@@ -1193,6 +1209,45 @@ func (x *TypeDecl) GetDeclNames() []Name {
 	}
 }
 
+// A declaration with type parameters is a template until its type parameters
+// are bound to type arguments by instantiation (see generics.go). Templates
+// are never preprocessed themselves.
+func (ftxz FieldTypeExprs) IsGeneric() bool {
+	for _, ftx := range ftxz {
+		if _, ok := ftx.Type.(*constTypeExpr); !ok {
+			return true
+		}
+	}
+	return false
+}
+
+func isGenericDecl(n Node) bool {
+	switch d := n.(type) {
+	case *FuncDecl:
+		return d.TypeParams.IsGeneric()
+	case *TypeDecl:
+		return d.TypeParams.IsGeneric() || isConstraintTypeExpr(d.Type)
+	default:
+		return false
+	}
+}
+
+// Interfaces with type elements like `interface{ ~int | string }` can only
+// be used as constraints, so they are templates without type parameters.
+func isConstraintTypeExpr(x Expr) bool {
+	itx, ok := x.(*InterfaceTypeExpr)
+	if !ok {
+		return false
+	}
+	for _, m := range itx.Methods {
+		switch m.Type.(type) {
+		case *BinaryExpr, *UnaryExpr:
+			return true
+		}
+	}
+	return false
+}
+
 func HasDeclName(d Decl, n2 Name) bool {
 	ns := d.GetDeclNames()
 	return slices.Contains(ns, n2)
@@ -1641,6 +1696,7 @@ const (
 	NSFuncParam    // func(<name>...) (indexed)
 	NSFuncResult   // func()<name>... (indexed)
 	NSTypeSwitch   // switch <name> := _.(type)
+	NSTypeParam    // func _[<name>...]() (indexed)
 )
 
 type oldValue struct {
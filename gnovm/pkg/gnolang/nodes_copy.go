@@ -4,6 +4,7 @@ package gnolang
 // * Attributes are not copied.
 // * Paths are not copied.
 // * *ConstExpr, *constTypeExpr, *bodyStmt not yet supported.
+// * nil slices are kept nil, e.g. the body of a native *FuncDecl.
 
 func (x *ConstExpr) Copy() Node {
 	panic("*ConstExpr.Copy() not yet implemented")
@@ -53,6 +54,13 @@ func (x *IndexExpr) Copy() Node {
 	}
 }
 
+func (x *IndexListExpr) Copy() Node {
+	return &IndexListExpr{
+		X:       x.X.Copy().(Expr),
+		Indices: copyExprs(x.Indices),
+	}
+}
+
 func (x *SelectorExpr) Copy() Node {
 	return &SelectorExpr{
 		X:   x.X.Copy().(Expr),
@@ -305,10 +313,11 @@ func (x *SwitchClauseStmt) Copy() Node {
 
 func (x *FuncDecl) Copy() Node {
 	funcDecl := &FuncDecl{
-		NameExpr: *(x.NameExpr.Copy().(*NameExpr)),
-		IsMethod: x.IsMethod,
-		Type:     *(x.Type.Copy().(*FuncTypeExpr)),
-		Body:     copyStmts(x.Body),
+		NameExpr:   *(x.NameExpr.Copy().(*NameExpr)),
+		IsMethod:   x.IsMethod,
+		TypeParams: copyFTs(x.TypeParams),
+		Type:       *(x.Type.Copy().(*FuncTypeExpr)),
+		Body:       copyStmts(x.Body),
 	}
 	if x.IsMethod {
 		funcDecl.Recv = *(x.Recv.Copy().(*FieldTypeExpr))
@@ -334,9 +343,10 @@ func (x *ValueDecl) Copy() Node {
 
 func (x *TypeDecl) Copy() Node {
 	return &TypeDecl{
-		NameExpr: *(x.NameExpr.Copy().(*NameExpr)),
-		Type:     x.Type.Copy().(Expr),
-		IsAlias:  x.IsAlias,
+		NameExpr:   *(x.NameExpr.Copy().(*NameExpr)),
+		TypeParams: copyFTs(x.TypeParams),
+		Type:       x.Type.Copy().(Expr),
+		IsAlias:    x.IsAlias,
 	}
 }
 
@@ -374,6 +384,9 @@ func copyExpr(x Expr) Expr {
 }
 
 func copyExprs(xs []Expr) []Expr {
+	if xs == nil {
+		return nil
+	}
 	res := make([]Expr, len(xs))
 	for i, x := range xs {
 		res[i] = x.Copy().(Expr)
@@ -382,6 +395,9 @@ func copyExprs(xs []Expr) []Expr {
 }
 
 func copyNameExprs(nxs NameExprs) NameExprs {
+	if nxs == nil {
+		return nil
+	}
 	res := make([]NameExpr, len(nxs))
 	for i, nx := range nxs {
 		res[i] = *(nx.Copy().(*NameExpr))
@@ -390,6 +406,9 @@ func copyNameExprs(nxs NameExprs) NameExprs {
 }
 
 func copyKVs(kvs []KeyValueExpr) []KeyValueExpr {
+	if kvs == nil {
+		return nil
+	}
 	res := make([]KeyValueExpr, len(kvs))
 	for i, kv := range kvs {
 		res[i] = *(kv.Copy().(*KeyValueExpr))
@@ -405,6 +424,9 @@ func copyStmt(x Stmt) Stmt {
 }
 
 func copyStmts(ss []Stmt) []Stmt {
+	if ss == nil {
+		return nil
+	}
 	res := make([]Stmt, len(ss))
 	for i, s := range ss {
 		res[i] = s.Copy().(Stmt)
@@ -413,6 +435,9 @@ func copyStmts(ss []Stmt) []Stmt {
 }
 
 func copyFTs(fts []FieldTypeExpr) []FieldTypeExpr {
+	if fts == nil {
+		return nil
+	}
 	res := make([]FieldTypeExpr, len(fts))
 	for i, ft := range fts {
 		res[i] = *(ft.Copy().(*FieldTypeExpr))
@@ -421,6 +446,9 @@ func copyFTs(fts []FieldTypeExpr) []FieldTypeExpr {
 }
 
 func copyDecls(ds []Decl) []Decl {
+	if ds == nil {
+		return nil
+	}
 	res := make([]Decl, len(ds))
 	for i, d := range ds {
 		res[i] = d.Copy().(Decl)
@@ -429,6 +457,9 @@ func copyDecls(ds []Decl) []Decl {
 }
 
 func copySelectCases(scs []SelectCaseStmt) []SelectCaseStmt {
+	if scs == nil {
+		return nil
+	}
 	res := make([]SelectCaseStmt, len(scs))
 	for i, sc := range scs {
 		res[i] = *(sc.Copy().(*SelectCaseStmt))
@@ -437,6 +468,9 @@ func copySelectCases(scs []SelectCaseStmt) []SelectCaseStmt {
 }
 
 func copyCaseClauses(scs []SwitchClauseStmt) []SwitchClauseStmt {
+	if scs == nil {
+		return nil
+	}
 	res := make([]SwitchClauseStmt, len(scs))
 	for i, sc := range scs {
 		res[i] = *(sc.Copy().(*SwitchClauseStmt))
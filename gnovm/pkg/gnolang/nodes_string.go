@@ -45,6 +45,7 @@ var wordTokenStrings = map[Word]string{
 	LEQ:             "<=",
 	GEQ:             ">=",
 	DEFINE:          ":=",
+	TILDE:           "~",
 
 	// Branch operations
 	BREAK:       "break",
@@ -136,6 +137,10 @@ func (x IndexExpr) String() string {
 	return fmt.Sprintf("%s[%s]", x.X, x.Index)
 }
 
+func (x IndexListExpr) String() string {
+	return fmt.Sprintf("%s[%s]", x.X, x.Indices.String())
+}
+
 func (x SelectorExpr) String() string {
 	return fmt.Sprintf("%s.%s", x.X, x.Sel)
 }
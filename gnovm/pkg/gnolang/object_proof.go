@@ -0,0 +1,120 @@
+package gnolang
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/overflow"
+	"github.com/gnolang/gno/tm2/pkg/store"
+)
+
+// ObjectProof links a persisted object to a hash saved in the iavl store, and
+// so to the app hash: the object is owned by a chain of objects up to an
+// escaped object or a realm package value, whose hash is saved in the iavl
+// store (see [defaultStore.SetObject]). Each owner refers to the object it
+// owns by its hash.
+type ObjectProof struct {
+	// The amino binary encodings of the object, and of its owners up to the
+	// one whose hash is saved in the iavl store. These are the bytes which
+	// are hashed into the object hashes.
+	Objects [][]byte `json:"objects"`
+}
+
+// GetObjectProof returns the proof of the object oid, or nil if the object
+// does not exist. The hash of the last object of the proof is at the iavl key
+// returned by [ObjectProof.Verify]. It returns an error if the object can't
+// be proven, e.g. if the realm roots were not saved (see
+// [defaultStore.SaveRealmRoots]).
+func (ds *defaultStore) GetObjectProof(oid ObjectID) (*ObjectProof, error) {
+	var proof ObjectProof
+	for {
+		hashbz := ds.baseStore.Get([]byte(backendObjectKey(oid)))
+		if hashbz == nil {
+			if len(proof.Objects) == 0 {
+				return nil, nil
+			}
+			return nil, errors.New("missing owner %v of object", oid)
+		}
+		bz := hashbz[HashSize:]
+		gas := overflow.Mulp(ds.gasConfig.GasGetObject, store.Gas(len(bz)))
+		ds.consumeGas(gas, GasGetObjectDesc)
+		proof.Objects = append(proof.Objects, bz)
+
+		var oo Object
+		if err := amino.Unmarshal(bz, &oo); err != nil {
+			return nil, errors.Wrapf(err, "decoding object %v", oid)
+		}
+		if isIavlObject(oo) {
+			if !ds.iavlStore.Has([]byte(oid.String())) {
+				if isRealmRoot(oo) {
+					return nil, errors.New("hash of realm %v is not in iavl: the realm roots are not saved on this chain", oid)
+				}
+				return nil, errors.New("hash of object %v is not in iavl", oid)
+			}
+			return &proof, nil
+		}
+		// the owner is not loaded: use the persisted owner ID.
+		oid = oo.GetObjectInfo().OwnerID
+		if oid.IsZero() {
+			return nil, errors.New("object %v has no owner nor hash in iavl", oo.GetObjectID())
+		}
+	}
+}
+
+// Verify checks that each object of the proof is owned by the next one, which
+// refers to it by its hash. It returns the first object of the proof, and the
+// key and value of the iavl store which must be verified against the app hash
+// (e.g. with an ICS23 proof) to verify the object.
+func (p ObjectProof) Verify() (oo Object, key, value []byte, err error) {
+	if len(p.Objects) == 0 {
+		return nil, nil, nil, errors.New("empty object proof")
+	}
+	var (
+		child     Object
+		childHash Hashlet
+	)
+	for i, bz := range p.Objects {
+		var obj Object
+		if err := amino.Unmarshal(bz, &obj); err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "decoding object %d of proof", i)
+		}
+		hash := HashBytes(bz)
+		if child == nil {
+			oo = obj
+		} else if err := verifyOwnedObject(obj, child, childHash); err != nil {
+			return nil, nil, nil, err
+		}
+		last := i == len(p.Objects)-1
+		if isIavlObject(obj) != last {
+			if last {
+				return nil, nil, nil, errors.New("hash of object %v is not in iavl", obj.GetObjectID())
+			}
+			return nil, nil, nil, errors.New("object %v of proof has no owner", obj.GetObjectID())
+		}
+		if last {
+			return oo, []byte(obj.GetObjectID().String()), hash.Bytes(), nil
+		}
+		child, childHash = obj, hash
+	}
+	panic("should not happen")
+}
+
+// Checks that owner owns child, referring to it with hash.
+func verifyOwnedObject(owner, child Object, hash Hashlet) error {
+	oid := child.GetObjectID()
+	if ownerID := child.GetObjectInfo().OwnerID; ownerID != owner.GetObjectID() {
+		return errors.New("object %v is owned by %v, not %v",
+			oid, ownerID, owner.GetObjectID())
+	}
+	for _, ch := range getChildObjects(owner, nil) {
+		ref, ok := ch.(RefValue)
+		if !ok || ref.ObjectID != oid {
+			continue
+		}
+		if ref.Hash.Hashlet != hash {
+			return errors.New("hash mismatch for object %v: %X vs %X",
+				oid, ref.Hash.Bytes(), hash.Bytes())
+		}
+		return nil
+	}
+	return errors.New("object %v does not refer to object %v", owner.GetObjectID(), oid)
+}
@@ -53,6 +53,7 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 	BinaryExpr{},
 	CallExpr{},
 	IndexExpr{},
+	IndexListExpr{},
 	SelectorExpr{},
 	SliceExpr{},
 	StarExpr{},
@@ -127,6 +128,7 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 	&tupleType{},
 	RefType{},
 	heapItemType{},
+	&GenericType{},
 
 	//----------------------------------------
 	// MemPackage related
@@ -291,6 +291,10 @@ func initStaticBlocks1(store Store, ctx BlockNode, nn Node) {
 		switch stage {
 		// ----------------------------------------
 		case TRANS_ENTER:
+			if isGenericDecl(n) {
+				// templates are only initialized once instantiated.
+				return n, TRANS_SKIP
+			}
 			switch n := n.(type) {
 			case *ForStmt:
 				switch fsinit := n.Init.(type) {
@@ -435,14 +439,28 @@ func initStaticBlocks2(store Store, ctx BlockNode, nn Node) {
 				nx := &n.NameExpr
 				nx.Type = NameExprTypeDefine
 				last2.Reserve(true, nx, n, NSTypeDecl, -1)
+				if isGenericDecl(n) {
+					return n, TRANS_SKIP
+				}
 			case *FuncDecl:
+				if isGenericDecl(n) {
+					// the name of a generic function is a constant;
+					// methods of generic types are defined upon
+					// instantiation of their receiver type.
+					if !n.IsMethod {
+						nx := &n.NameExpr
+						nx.Type = NameExprTypeDefine
+						skipFile(last).Reserve(true, nx, n, NSFuncDecl, -1)
+					}
+					return n, TRANS_SKIP
+				}
 				if n.IsMethod {
 					if n.Recv.Name == "" || n.Recv.Name == blankIdentifier {
 						// create a hidden var with leading dot.
 						// NOTE: document somewhere.
 						n.Recv.Name = ".recv"
 					}
-				} else {
+				} else if len(n.TypeParams) == 0 {
 					pkg := skipFile(last).(*PackageNode)
 					// special case: if n.Name == "init", assign unique suffix.
 					switch n.Name {
@@ -574,6 +592,16 @@ func initStaticBlocks2(store Store, ctx BlockNode, nn Node) {
 					rx.Type = NameExprTypeDefine
 					n.Reserve(false, rx, &n.Type, NSFuncResult, i)
 				}
+				// type parameters of instances are bound to
+				// their type arguments.
+				for i := range n.TypeParams {
+					tx := &n.TypeParams[i].NameExpr
+					if tx.Name == blankIdentifier {
+						continue
+					}
+					tx.Type = NameExprTypeDefine
+					n.Reserve(true, tx, n, NSTypeParam, i)
+				}
 			}
 			return n, TRANS_CONTINUE
 
@@ -686,6 +714,9 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 					return n, TRANS_SKIP
 				}
 			}
+			if isGenericDecl(n) {
+				return n, TRANS_SKIP
+			}
 			if bn, ok := n.(BlockNode); ok {
 				// codaGotoLoopDefines(ctx, bn)
 				codaHeapDefinesByUse(ctx, bn)
@@ -696,6 +727,12 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 			return n, TRANS_CONTINUE
 		})
 
+	// Preprocess the generic instances created while the
+	// package was being predefined.
+	if _, ok := n.(*FileNode); ok {
+		preprocessPendingInstances(store, packageOf(ctx))
+	}
+
 	return n
 }
 
@@ -763,6 +800,11 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 				if n.GetAttribute(ATTR_PREDEFINED) == true {
 					// skip declarations already predefined
 					// (e.g. through recursion for a dependent)
+					if isGenericDecl(n) {
+						// templates are preprocessed upon
+						// instantiation.
+						return n, TRANS_SKIP
+					}
 				} else {
 					d := n.(Decl)
 					if cd, ok := d.(*ValueDecl); ok {
@@ -771,7 +813,7 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 
 					// recursively predefine dependencies.
 					preprocessed := predefineRecursively(store, last, d)
-					if preprocessed {
+					if preprocessed || isGenericDecl(d) {
 						return d, TRANS_SKIP
 					} else {
 						return d, TRANS_CONTINUE
@@ -1073,6 +1115,16 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 					last.Define(name, anyValue(rf.Type))
 					n.Type.Results[i].Path = n.GetPathForName(nil, name)
 				}
+				// define type parameters (of instances) in new block.
+				for i := range n.TypeParams {
+					tp := &n.TypeParams[i]
+					if tp.Name == blankIdentifier {
+						continue
+					}
+					t := getType(tp.Type)
+					last.Define2(true, tp.Name, t, asValue(t), NameSource{&tp.NameExpr, n, NSTypeParam, i})
+					tp.Path = n.GetPathForName(nil, tp.Name)
+				}
 				// functions that don't return a value do not need termination analysis
 				// functions that are externally defined or builtin implemented in the vm can't be analysed
 				if len(ft.Results) > 0 && ctxpn.PkgPath != uversePkgPath && n.Body != nil {
@@ -1333,6 +1385,7 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 						// this behavior, it's reasonable for
 						// a name to be a value by default.
 						cx := evalConst(store, last, n)
+						assertInstantiated(cx, ftype)
 						/*
 							if !cx.IsUndefined() && cx.T.Kind() == TypeKind && ftype == TRANS_TYPE_TYPE {
 								return toConstTypeExpr(last, n, cx.GetType()), TRANS_CONTINUE
@@ -1505,6 +1558,11 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 				}
 			// TRANS_LEAVE -----------------------
 			case *CallExpr:
+				// Instantiate generic function, inferring
+				// its type arguments.
+				if fx := instantiateCall(store, last, n); fx != nil {
+					n.Func = fx
+				}
 				// Func type evaluation.
 				nft := evalStaticTypeOf(store, last, n.Func)
 				switch bnft := baseOf(nft).(type) {
@@ -2032,6 +2090,9 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 
 			// TRANS_LEAVE -----------------------
 			case *IndexExpr:
+				if gt := genericTypeOf(n.X); gt != nil {
+					return instantiateIndex(store, last, n, ftype, gt, []Expr{n.Index}), TRANS_CONTINUE
+				}
 				dt := evalStaticTypeOf(store, last, n.X)
 				if dt.Kind() == PointerKind {
 					// if a is a pointer to an array,
@@ -2055,6 +2116,14 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 						dt.String()))
 				}
 
+			// TRANS_LEAVE -----------------------
+			case *IndexListExpr:
+				gt := genericTypeOf(n.X)
+				if gt == nil {
+					panic("invalid operation: more than one index")
+				}
+				return instantiateIndex(store, last, n, ftype, gt, n.Indices), TRANS_CONTINUE
+
 			// TRANS_LEAVE -----------------------
 			case *SliceExpr:
 				// Replace const L/H/M with int *ConstExpr,
@@ -2344,6 +2413,7 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 					tt := pn.GetStaticTypeOfAt(store, n.Path)
 					if isUntyped(tt) || pn.GetIsConstAt(store, n.Path) {
 						cx := evalConst(store, last, n)
+						assertInstantiated(cx, ftype)
 						return cx, TRANS_CONTINUE
 					}
 				case *TypeType:
@@ -3238,6 +3308,12 @@ func codaHeapDefinesByUse(ctx BlockNode, bn BlockNode) {
 		}
 
 		switch stage {
+		// ----------------------------------------
+		case TRANS_ENTER:
+			if isGenericDecl(n) {
+				return n, TRANS_SKIP
+			}
+
 		// ----------------------------------------
 		case TRANS_BLOCK:
 
@@ -3492,6 +3568,9 @@ func codaHeapUsesDemoteDefines(ctx BlockNode, bn BlockNode) {
 
 		// ----------------------------------------
 		case TRANS_ENTER:
+			if isGenericDecl(n) {
+				return n, TRANS_SKIP
+			}
 			switch n := n.(type) {
 			// type switch is a special cast that varName is
 			// defined in case clauses.
@@ -3632,6 +3711,9 @@ func codaPackageSelectors(bn BlockNode) {
 	_ = Transcribe(bn, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
 		switch stage {
 		case TRANS_ENTER:
+			if isGenericDecl(n) {
+				return n, TRANS_SKIP
+			}
 			switch n := n.(type) {
 			case *NameExpr:
 				// Replace a package name with RefValue{PkgPath}
@@ -4725,6 +4807,17 @@ func findUndefinedAny(store Store, last BlockNode, x Expr, stack []Name, definin
 		if un != "" {
 			return
 		}
+	case *IndexListExpr:
+		un, directR = findUndefinedV(store, last, cx.X, stack, defining, direct, nil)
+		if un != "" {
+			return
+		}
+		for i := range cx.Indices {
+			un, directR = findUndefinedV(store, last, cx.Indices[i], stack, defining, direct, nil)
+			if un != "" {
+				return
+			}
+		}
 	case *constTypeExpr:
 		return
 	case *ConstExpr:
@@ -4885,6 +4978,9 @@ func predefineRecursively2(store Store, last BlockNode, d Decl, stack []Name, de
 		*cd = *vd2
 		return true
 	case *TypeDecl:
+		if isGenericDecl(cd) {
+			return false
+		}
 		td2 := Preprocess(store, last, cd).(*TypeDecl)
 		*cd = *td2
 		return true
@@ -4915,6 +5011,13 @@ func tryPredefine(store Store, pkg *PackageNode, last BlockNode, d Decl, stack [
 		}
 	}()
 
+	// Generic functions and types only define their names; they are
+	// otherwise predefined upon instantiation.
+	if isGenericDecl(d) {
+		predefineGeneric(store, pkg, last, d)
+		return "", false, false
+	}
+
 	// NOTE: These happen upon enter from the top,
 	// so value paths cannot be used here.
 	switch d := d.(type) {
@@ -5076,6 +5179,15 @@ func tryPredefine(store Store, pkg *PackageNode, last BlockNode, d Decl, stack [
 				tx.Path = pn.GetPathForName(store, tx.Sel)
 				ptr := pv.GetBlock(store).GetPointerTo(store, tx.Path)
 				t = ptr.TV.GetType()
+			case *IndexExpr, *IndexListExpr:
+				// instantiation of a generic type.
+				un, directR = findUndefinedT(store, last, tx, stack, defining, d.IsAlias, direct)
+				if un != "" {
+					untype = true
+					return
+				}
+				d.Type = Preprocess(store, last, tx).(Expr)
+				t = getType(d.Type)
 			default:
 				panic(fmt.Sprintf(
 					"unexpected type declaration type %v",
@@ -5539,6 +5651,11 @@ func findDependentNames(n Node, dst map[Name]struct{}) {
 	case *IndexExpr:
 		findDependentNames(cn.X, dst)
 		findDependentNames(cn.Index, dst)
+	case *IndexListExpr:
+		findDependentNames(cn.X, dst)
+		for i := range cn.Indices {
+			findDependentNames(cn.Indices[i], dst)
+		}
 	case *FuncLitExpr:
 		findDependentNames(&cn.Type, dst)
 		for _, n := range cn.GetExternNames() {
@@ -5694,6 +5811,10 @@ func SaveBlockNodes(store Store, fn *FileNode) {
 		if stage != TRANS_ENTER {
 			return n, TRANS_CONTINUE
 		}
+		// templates are never preprocessed, see saveInstanceBlockNodes.
+		if isGenericDecl(n) {
+			return n, TRANS_SKIP
+		}
 		// save node to store if blocknode.
 		if bn, ok := n.(BlockNode); ok {
 			// Location must exist already.
@@ -559,6 +559,11 @@ func (rlm *Realm) decRefDeletedDescendants(store Store, oo Object) {
 	// recurse for children
 	more := getChildObjects2(store, oo)
 	for _, child := range more {
+		if _, ok := child.(*PackageValue); ok {
+			// extern package values are skipped,
+			// e.g. imports of a deleted file block.
+			continue
+		}
 		child.DecRefCount()
 		rc := child.GetRefCount()
 		if rc == 0 {
@@ -1034,6 +1039,8 @@ func (rlm *Realm) assertTypeIsPublic(store Store, t Type, visited map[TypeID]str
 			}
 		}
 		pkgPath = tt.GetPkgPath()
+	case *GenericType:
+		pkgPath = tt.GetPkgPath()
 	case *RefType:
 		panic("should not happen: ref type in assert type is public")
 	case PrimitiveType, *TypeType, *PackageType, blockType, heapItemType:
@@ -1294,6 +1301,8 @@ func copyTypeWithRefs(typ Type) Type {
 		}
 	case blockType:
 		return blockType{}
+	case *GenericType:
+		return ct
 	case *tupleType:
 		elts2 := make([]Type, len(ct.Elts))
 		for i, elt := range ct.Elts {
@@ -1557,6 +1566,8 @@ func fillType(store Store, typ Type) Type {
 		return ct
 	case blockType:
 		return ct // nothing to do
+	case *GenericType:
+		return ct // nothing to do
 	case *tupleType:
 		for i, elt := range ct.Elts {
 			ct.Elts[i] = fillType(store, elt)
@@ -1,6 +1,7 @@
 package gnolang
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"fmt"
 	"io"
@@ -42,6 +43,7 @@ type NativeResolver func(pkgName string, name Name) func(m *Machine)
 type Store interface {
 	// STABLE
 	BeginTransaction(baseStore, iavlStore store.Store, gasMeter store.GasMeter) TransactionStore
+	BeginHistoricTransaction(baseStore, iavlStore store.Store, gasMeter store.GasMeter) TransactionStore
 	GetPackageGetter() PackageGetter
 	SetPackageGetter(PackageGetter)
 	GetPackage(pkgPath string, isImport bool) *PackageValue
@@ -50,6 +52,8 @@ type Store interface {
 	SetPackageRealm(*Realm)
 	GetObject(oid ObjectID) Object
 	GetObjectSafe(oid ObjectID) Object
+	GetObjectProof(oid ObjectID) (*ObjectProof, error)
+	SaveRealmRoots()
 	SetObject(Object) int64 // returns size difference of the object
 	GetStagingPackage() *PackageValue
 	SetStagingPackage(pv *PackageValue)
@@ -75,6 +79,7 @@ type Store interface {
 	GetMemPackage(path string) *std.MemPackage
 	GetMemFile(path string, name string) *std.MemFile
 	FindPathsByPrefix(prefix string) iter.Seq[string]
+	IterRealmObjects(pkgPath string) iter.Seq[Object]
 	IterMemPackage() <-chan *std.MemPackage
 	ClearObjectCache() // run before processing a message
 	GarbageCollectObjectCache(gcCycle int64)
@@ -149,6 +154,7 @@ type defaultStore struct {
 	cacheTypes   map[TypeID]Type
 	cacheNodes   txlog.Map[Location, BlockNode]
 	alloc        *Allocator // for accounting for cached items
+	realmRoots   *bool      // cached by hasRealmRoots, nil until then
 
 	// Partially restored package; occupies memory and tracked for GC,
 	// this is more efficient than iterating over cacheObjects.
@@ -169,6 +175,10 @@ type defaultStore struct {
 
 	// realm storage changes on message level.
 	realmStorageDiffs map[string]int64 // maps realm path to size diff
+
+	// set by BeginHistoricTransaction: the packages whose BlockNodes are
+	// preprocessed, or being preprocessed.
+	historicPkgs map[string]struct{}
 }
 
 var globalAminoCache = sync.OnceValue[*ristretto.Cache[[]byte, Type]](func() *ristretto.Cache[[]byte, Type] {
@@ -209,6 +219,22 @@ func NewStore(alloc *Allocator, baseStore, iavlStore store.Store) *defaultStore
 
 // If nil baseStore and iavlStore, the baseStores are re-used.
 func (ds *defaultStore) BeginTransaction(baseStore, iavlStore store.Store, gasMeter store.GasMeter) TransactionStore {
+	return ds.beginTransaction(baseStore, iavlStore, gasMeter, txlog.Wrap(ds.cacheNodes))
+}
+
+// BeginHistoricTransaction is like BeginTransaction, for reading the state of
+// a past height from baseStore and iavlStore, loaded at that height. The
+// BlockNodes of ds, which are the ones of the latest code of the packages
+// (e.g. of the upgraded realms), are not used: they are preprocessed from the
+// MemPackages of baseStore, as needed. The transaction must not be written.
+func (ds *defaultStore) BeginHistoricTransaction(baseStore, iavlStore store.Store, gasMeter store.GasMeter) TransactionStore {
+	ts := ds.beginTransaction(baseStore, iavlStore, gasMeter,
+		txlog.GoMap[Location, BlockNode](map[Location]BlockNode{}))
+	ts.historicPkgs = make(map[string]struct{})
+	return ts
+}
+
+func (ds *defaultStore) beginTransaction(baseStore, iavlStore store.Store, gasMeter store.GasMeter, cacheNodes txlog.Map[Location, BlockNode]) transactionStore {
 	if baseStore == nil {
 		baseStore = ds.baseStore
 	}
@@ -223,7 +249,7 @@ func (ds *defaultStore) BeginTransaction(baseStore, iavlStore store.Store, gasMe
 		// transaction-scoped
 		cacheObjects: make(map[ObjectID]Object),
 		cacheTypes:   make(map[TypeID]Type),
-		cacheNodes:   txlog.Wrap(ds.cacheNodes),
+		cacheNodes:   cacheNodes,
 		alloc:        ds.alloc.Fork().Reset(),
 
 		// store configuration
@@ -251,6 +277,9 @@ type transactionStore struct {
 }
 
 func (t transactionStore) Write() {
+	if t.historicPkgs != nil {
+		panic("a historic transaction store cannot be written")
+	}
 	t.cacheNodes.(txlog.MapCommitter[Location, BlockNode]).Commit()
 }
 
@@ -504,11 +533,83 @@ func (ds *defaultStore) loadObjectSafe(oid ObjectID) Object {
 		ds.cacheObjects[oid] = oo
 		oo.GetObjectInfo().LastObjectSize = int64(size)
 		_ = fillTypesOfValue(ds, oo)
+		switch cv := oo.(type) {
+		case *FuncValue:
+			ds.rebindFunc(cv)
+		case *BoundMethodValue:
+			ds.rebindFunc(cv.Func)
+		}
 		return oo
 	}
 	return nil
 }
 
+// Points the function fv, loaded from the store, to its declaration in the
+// current code of its package, found by name: the function values are saved
+// with the location of their declaration, which moves when the code of a
+// realm is upgraded (see [Machine.UpgradeMemPackage]). Closures are not
+// rebound, as they have no name.
+func (ds *defaultStore) rebindFunc(fv *FuncValue) {
+	if fv.Name == "" || fv.IsNative() || fv.PkgPath == "" {
+		return
+	}
+	pn, ok := ds.GetBlockNodeSafe(PackageNodeLocation(fv.PkgPath)).(*PackageNode)
+	if !ok {
+		return
+	}
+	nfv := findFuncDecl(pn, fv)
+	if nfv == nil || nfv.Source.GetLocation() == fv.Source.GetLocation() {
+		return
+	}
+	fv.Source = nfv.Source
+	fv.FileName = nfv.FileName
+	fv.Crossing = nfv.Crossing
+	fv.body = nil
+}
+
+// Returns the function or method of pn with the name of fv, or nil.
+func findFuncDecl(pn *PackageNode, fv *FuncValue) *FuncValue {
+	if !fv.IsMethod {
+		idx, ok := pn.GetLocalIndex(fv.Name)
+		if !ok {
+			return nil
+		}
+		nfv, _ := pn.Values[idx].V.(*FuncValue)
+		return nfv
+	}
+	// the receiver is the first parameter of the unbound method type.
+	ft, ok := fv.Type.(*FuncType)
+	if !ok || len(ft.Params) == 0 {
+		return nil
+	}
+	rt := ft.Params[0].Type
+	if pt, ok := rt.(*PointerType); ok {
+		rt = pt.Elt
+	}
+	rdt, ok := rt.(*DeclaredType)
+	if !ok {
+		return nil
+	}
+	idx, ok := pn.GetLocalIndex(rdt.Name)
+	if !ok {
+		return nil
+	}
+	tv, ok := pn.Values[idx].V.(TypeValue)
+	if !ok {
+		return nil
+	}
+	dt, ok := tv.Type.(*DeclaredType)
+	if !ok || dt.TypeID() != rdt.TypeID() {
+		return nil
+	}
+	for _, mv := range dt.Methods {
+		if mfv := mv.V.(*FuncValue); mfv.Name == fv.Name {
+			return mfv
+		}
+	}
+	return nil
+}
+
 func (ds *defaultStore) fillPackage(pv *PackageValue) {
 	pv.GetBlock(ds) // preload
 	if pv.IsRealm() && pv.Realm == nil {
@@ -608,8 +709,9 @@ func (ds *defaultStore) SetObject(oo Object) int64 {
 		}
 	}
 	ds.cacheObjects[oid] = oo
-	// if escaped, add hash to iavl.
-	if oo.GetIsEscaped() && ds.iavlStore != nil {
+	// if escaped, or the root of a realm once the realm roots are saved,
+	// add hash to iavl.
+	if ds.iavlStore != nil && isIavlObject(oo) && (!isRealmRoot(oo) || ds.hasRealmRoots()) {
 		var key, value []byte
 		key = []byte(oid.String())
 		value = hash.Bytes()
@@ -618,6 +720,65 @@ func (ds *defaultStore) SetObject(oo Object) int64 {
 	return diff
 }
 
+// Returns whether the hash of oo is saved in the iavl store, so that it can be
+// proven along with the objects it owns (see [ObjectProof]): escaped objects
+// have no owner, and realm package values are the roots of the realms (once
+// the realm roots are saved, see [defaultStore.SaveRealmRoots]).
+func isIavlObject(oo Object) bool {
+	if isRealmRoot(oo) {
+		return true
+	}
+	return oo.GetIsEscaped()
+}
+
+func isRealmRoot(oo Object) bool {
+	pv, ok := oo.(*PackageValue)
+	return ok && pv.IsRealm()
+}
+
+// Returns whether the hashes of the realm package values are saved in the
+// iavl store. It is only read once per transaction, as the realm package
+// values are saved at the end of each message.
+func (ds *defaultStore) hasRealmRoots() bool {
+	if ds.realmRoots == nil {
+		has := ds.iavlStore.Has([]byte(iavlRealmRootsKey))
+		ds.realmRoots = &has
+	}
+	return *ds.realmRoots
+}
+
+// SaveRealmRoots saves the hashes of the package values of all the realms in
+// the iavl store, and from then on, the ones of the realm package values
+// saved afterwards, so that the realm objects can be proven (see
+// [ObjectProof]).
+//
+// Saving them changes the app hash: it must be done by all the nodes of a
+// chain at the same height (e.g. at genesis), and not for the chains whose
+// history is replayed without it.
+func (ds *defaultStore) SaveRealmRoots() {
+	if ds.hasRealmRoots() {
+		return
+	}
+	// the paths are collected first, not to write while iterating.
+	var realms []string
+	for path := range ds.FindPathsByPrefix("") {
+		if IsRealmPath(path) {
+			realms = append(realms, path)
+		}
+	}
+	for _, path := range realms {
+		oid := ObjectIDFromPkgPath(path)
+		hashbz := ds.baseStore.Get([]byte(backendObjectKey(oid)))
+		if hashbz == nil {
+			continue
+		}
+		ds.iavlStore.Set([]byte(oid.String()), hashbz[:HashSize])
+	}
+	ds.iavlStore.Set([]byte(iavlRealmRootsKey), []byte{1})
+	has := true
+	ds.realmRoots = &has
+}
+
 func (ds *defaultStore) loadForLog(oid ObjectID) Object {
 	key := backendObjectKey(oid)
 	hashbz := ds.baseStore.Get([]byte(key))
@@ -813,9 +974,30 @@ func (ds *defaultStore) GetBlockNodeSafe(loc Location) BlockNode {
 			return bn
 		}
 	}
+	if ds.historicPkgs != nil {
+		return ds.getHistoricBlockNode(loc)
+	}
 	return nil
 }
 
+// Preprocesses the MemPackage of the package of loc, if not already done, and
+// returns the BlockNode at loc, for the historic transaction stores.
+func (ds *defaultStore) getHistoricBlockNode(loc Location) BlockNode {
+	if _, ok := ds.historicPkgs[loc.PkgPath]; ok {
+		return nil
+	}
+	ds.historicPkgs[loc.PkgPath] = struct{}{}
+	mpkg := ds.GetMemPackage(loc.PkgPath)
+	if mpkg == nil {
+		return nil
+	}
+	m := NewMachineWithOptions(MachineOptions{Store: ds})
+	defer m.Release()
+	m.preprocessMemPackageAndSaveBlockNodes(mpkg)
+	bn, _ := ds.cacheNodes.Get(loc)
+	return bn
+}
+
 func (ds *defaultStore) SetBlockNode(bn BlockNode) {
 	loc := bn.GetLocation()
 	if loc.IsZero() {
@@ -988,6 +1170,36 @@ func (ds *defaultStore) FindPathsByPrefix(prefix string) iter.Seq[string] {
 	}
 }
 
+// IterRealmObjects returns the objects of the realm pkgPath saved in the
+// store, in no particular order. They are decoded, consuming gas, but neither
+// cached nor filled: e.g. their types are RefTypes.
+func (ds *defaultStore) IterRealmObjects(pkgPath string) iter.Seq[Object] {
+	prefix := []byte(backendObjectKey(ObjectID{PkgID: PkgIDFromPkgPath(pkgPath)}))
+	// the keys of the objects are followed by their time.
+	prefix = prefix[:bytes.LastIndexByte(prefix, ':')+1]
+	endKey := slices.Clone(prefix)
+	endKey[len(endKey)-1]++
+
+	return func(yield func(Object) bool) {
+		iter := ds.baseStore.Iterator(prefix, endKey)
+		defer iter.Close()
+
+		for ; iter.Valid(); iter.Next() {
+			if bytes.IndexByte(iter.Key()[len(prefix):], '#') >= 0 {
+				continue // the realm, not an object.
+			}
+			bz := iter.Value()[HashSize:]
+			gas := overflow.Mulp(ds.gasConfig.GasGetObject, store.Gas(len(bz)))
+			ds.consumeGas(gas, GasGetObjectDesc)
+			var oo Object
+			amino.MustUnmarshal(bz, &oo)
+			if !yield(oo) {
+				return
+			}
+		}
+	}
+}
+
 func (ds *defaultStore) IterMemPackage() <-chan *std.MemPackage {
 	ctrkey := []byte(backendPackageIndexCtrKey())
 	ctrbz := ds.baseStore.Get(ctrkey)
@@ -1113,6 +1325,11 @@ func backendRealmKey(oid ObjectID) string {
 	return "oid:" + oid.String() + "#realm"
 }
 
+// iavlRealmRootsKey is set in the iavl store once the hashes of the realm
+// package values are saved in it. It can't be mistaken for an object ID, nor
+// a package path key.
+const iavlRealmRootsKey = "realmroots"
+
 func backendTypeKey(tid TypeID) string {
 	return "tid:" + tid.String()
 }
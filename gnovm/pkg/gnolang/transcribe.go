@@ -178,6 +178,17 @@ func transcribe(t Transform, ns []Node, ftype TransField, index int, n Node, nc
 		if stopOrSkip(nc, c) {
 			return
 		}
+	case *IndexListExpr:
+		cnn.X = transcribe(t, nns, TRANS_INDEX_X, 0, cnn.X, &c).(Expr)
+		if stopOrSkip(nc, c) {
+			return
+		}
+		for idx := range cnn.Indices {
+			cnn.Indices[idx] = transcribe(t, nns, TRANS_INDEX_INDEX, idx, cnn.Indices[idx], &c).(Expr)
+			if stopOrSkip(nc, c) {
+				return
+			}
+		}
 	case *SelectorExpr:
 		cnn.X = transcribe(t, nns, TRANS_SELECTOR_X, 0, cnn.X, &c).(Expr)
 		if stopOrSkip(nc, c) {
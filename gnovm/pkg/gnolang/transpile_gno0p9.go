@@ -115,6 +115,13 @@ func FindXformsGno0p9(store Store, pn *PackageNode, fn *FileNode) {
 		defer doRecover(stack, n)
 
 		switch stage {
+		// ----------------------------------------
+		case TRANS_ENTER:
+			if isGenericDecl(n) {
+				// templates are not preprocessed.
+				return n, TRANS_SKIP
+			}
+
 		// ----------------------------------------
 		case TRANS_LEAVE:
 			switch n := n.(type) {
@@ -278,6 +285,13 @@ func FindMoreXformsGno0p9(store Store, pn *PackageNode, last BlockNode, n Node)
 		defer doRecover(stack, n)
 
 		switch stage {
+		// ----------------------------------------
+		case TRANS_ENTER:
+			if isGenericDecl(n) {
+				// templates are not preprocessed.
+				return n, TRANS_SKIP
+			}
+
 		// ----------------------------------------
 		case TRANS_LEAVE:
 			switch n := n.(type) {
@@ -81,6 +81,7 @@ func (blockType) assertType()      {}
 func (heapItemType) assertType()   {}
 func (*tupleType) assertType()     {}
 func (RefType) assertType()        {}
+func (*GenericType) assertType()   {}
 
 // IsImmutable
 func (PrimitiveType) IsImmutable() bool    { return true }
@@ -100,6 +101,7 @@ func (blockType) IsImmutable() bool        { return false }
 func (heapItemType) IsImmutable() bool     { return false }
 func (*tupleType) IsImmutable() bool       { panic("should not happen") }
 func (RefType) IsImmutable() bool          { panic("should not happen") }
+func (*GenericType) IsImmutable() bool     { return true }
 
 // ----------------------------------------
 // Primitive types
@@ -1922,6 +1924,40 @@ func (rt RefType) IsNamed() bool {
 	panic("RefType has no property called named")
 }
 
+// ----------------------------------------
+// GenericType
+
+// GenericType is the type of the name of a generic function or type, which
+// is only usable once instantiated with type arguments (see generics.go).
+type GenericType struct {
+	PkgPath string
+	Name    Name
+}
+
+func (gt *GenericType) Kind() Kind {
+	return GenericKind
+}
+
+func (gt *GenericType) TypeID() TypeID {
+	return typeidf("generic{%s.%s}", gt.PkgPath, gt.Name)
+}
+
+func (gt *GenericType) String() string {
+	return fmt.Sprintf("generic %s.%s", gt.PkgPath, gt.Name)
+}
+
+func (gt *GenericType) Elem() Type {
+	panic("GenericType has no elem type")
+}
+
+func (gt *GenericType) GetPkgPath() string {
+	return gt.PkgPath
+}
+
+func (gt *GenericType) IsNamed() bool {
+	return true
+}
+
 // ----------------------------------------
 // Kind
 
@@ -1961,6 +1997,7 @@ const (
 	HeapItemKind // not in go.
 	TupleKind    // not in go.
 	RefTypeKind  // not in go.
+	GenericKind  // not in go.
 )
 
 // This is generally slower than switching on baseOf(t).
@@ -2037,6 +2074,8 @@ func KindOf(t Type) Kind {
 		return TupleKind
 	case RefType:
 		return RefTypeKind
+	case *GenericType:
+		return GenericKind
 	default:
 		panic(fmt.Sprintf("unexpected type %#v", t))
 	}
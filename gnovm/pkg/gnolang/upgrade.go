@@ -0,0 +1,362 @@
+package gnolang
+
+import (
+	"fmt"
+	"strings"
+
+	bm "github.com/gnolang/gno/gnovm/pkg/benchops"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// UpgradeError is the panic value of [Machine.UpgradeMemPackage] when the new
+// code of a package is not compatible with its persisted state.
+type UpgradeError struct {
+	PkgPath string
+	Msg     string
+}
+
+func (e UpgradeError) Error() string {
+	return fmt.Sprintf("cannot upgrade %s: %s", e.PkgPath, e.Msg)
+}
+
+func panicUpgrade(pkgPath string, format string, args ...any) {
+	panic(UpgradeError{PkgPath: pkgPath, Msg: fmt.Sprintf(format, args...)})
+}
+
+// UpgradeMemPackage replaces the code of the realm mpkg.Path, already in the
+// store, with the files of mpkg, keeping its state: the package value, its
+// realm and all the objects reachable from the package-level variables.
+//
+//   - Package-level variables declared with the same name and type keep their
+//     value, and their declarations are not run. The other variables are
+//     initialized, and the values of removed variables are dropped.
+//   - Declared types keep their TypeID, and so must keep their underlying
+//     type (e.g. the fields of a struct), so that the persisted values of
+//     these types remain valid. Their methods are replaced. The TypeID of the
+//     types declared in function bodies includes the location of the
+//     function: they become new types when it moves, and the persisted values
+//     keep the old ones.
+//   - Functions and methods are kept with the same signature, and their body
+//     may change. The function values stored in the realm state are bound to
+//     the new declaration of the same name when loaded.
+//   - Closures (function literals) cannot be bound to the new code, so realms
+//     storing closures in their state cannot be upgraded.
+//   - init() functions are not run. Instead, if the new code declares a
+//     function `migrate()` (or `migrate(cur realm)`), it is run once the new
+//     code is saved.
+//
+// Panics with an [UpgradeError] if the new code is not compatible with the
+// state of the package.
+// NOTE: Does not validate the mpkg, nor the permission to upgrade it. Caller
+// must check both before calling.
+func (m *Machine) UpgradeMemPackage(mpkg *std.MemPackage) (*PackageNode, *PackageValue) {
+	if bm.OpsEnabled || bm.StorageEnabled || bm.NativeEnabled {
+		bm.InitMeasure()
+	}
+	if bm.StorageEnabled {
+		defer bm.FinishStore()
+	}
+	// validate mpkg.Type.
+	mptype := mpkg.Type.(MemPackageType)
+	if !mptype.IsStorable() {
+		panic(fmt.Sprintf("mempackage type must be storable, but got %v", mptype))
+	}
+	pkgPath := mpkg.Path
+	pv := m.Store.GetPackage(pkgPath, false)
+	if pv == nil {
+		panicUpgrade(pkgPath, "package not found")
+	}
+	if !pv.IsRealm() {
+		panicUpgrade(pkgPath, "only realm packages can be upgraded")
+	}
+	if pv.PkgName != Name(mpkg.Name) {
+		panicUpgrade(pkgPath, "package name cannot change from %s to %s", pv.PkgName, mpkg.Name)
+	}
+	// parse files.
+	mpkg.Sort()
+	files := m.ParseMemPackageAsType(mpkg, mptype.AsRunnable())
+
+	// detach the old code from the package value and its block.
+	opn := m.Store.GetPackageNode(pkgPath)
+	pb := pv.GetBlock(m.Store)
+	ovalues := pb.Values
+	ofblocks := make([]*Block, len(pv.FNames))
+	for i, fname := range pv.FNames {
+		ofblocks[i] = pv.GetFileBlock(m.Store, fname)
+	}
+	pn := NewPackageNode(Name(mpkg.Name), pkgPath, &FileSet{})
+	pb.Source = pn
+	pb.Values = nil
+	pv.FNames = nil
+	pv.FBlocks = nil
+	pv.fBlocksMap = nil
+	m.Store.SetBlockNode(pn)
+	m.SetActivePackage(pv)
+
+	// Returns the index of the old variable kept as n, if any.
+	kept := func(n Name) (int, bool) {
+		oidx, ok := opn.GetLocalIndex(n)
+		if !ok || ovalues[oidx].T != (heapItemType{}) {
+			return 0, false // not a variable.
+		}
+		nidx, ok := pn.GetLocalIndex(n)
+		if !ok || !pn.HeapItems[nidx] {
+			return 0, false
+		}
+		ot, nt := opn.Types[oidx], pn.Types[nidx]
+		if ot == nil || nt == nil || ot.TypeID() != nt.TypeID() {
+			return 0, false
+		}
+		return int(oidx), true
+	}
+	keep := map[Object]struct{}{}
+	restore := func(n Name) bool {
+		oidx, ok := kept(n)
+		if ok {
+			nidx, _ := pn.GetLocalIndex(n)
+			pb.Values[nidx] = ovalues[oidx]
+			keep[ovalues[oidx].V.(Object)] = struct{}{}
+		}
+		return ok
+	}
+	// Restores the kept variables of decl when it declares only such
+	// variables, before the declarations depending on them are run.
+	skip := func(decl Decl) bool {
+		vd, ok := decl.(*ValueDecl)
+		if !ok || vd.Const {
+			return false
+		}
+		for _, nx := range vd.NameExprs {
+			if _, ok := kept(nx.Name); !ok && nx.Name != blankIdentifier {
+				return false
+			}
+		}
+		for _, nx := range vd.NameExprs {
+			restore(nx.Name)
+		}
+		return true
+	}
+	// run files, except for the declarations of kept variables.
+	m.runFileDecls(false, skip, files.Files...)
+	pv.deriveFBlocksMap(m.Store)
+	checkUpgradeCompat(pkgPath, opn, ovalues, pn, kept)
+	checkUpgradeClosures(m.Store, pkgPath)
+	migrate := getMigrateFunc(pkgPath, pn)
+
+	// restore the other kept variables, e.g. declared along with new ones,
+	// and attach the new values to the realm.
+	for _, n := range pn.Names {
+		restore(n)
+	}
+	rlm := pv.Realm
+	for _, tv := range pb.Values {
+		if oo, ok := tv.V.(Object); ok {
+			if _, ok := keep[oo]; !ok {
+				rlm.DidUpdate(pb, nil, oo)
+			}
+		}
+	}
+	for _, tv := range ovalues {
+		if oo, ok := tv.V.(Object); ok {
+			if _, ok := keep[oo]; !ok {
+				rlm.DidUpdate(pb, oo, nil)
+			}
+		}
+	}
+	for _, fb := range pv.FBlocks {
+		rlm.DidUpdate(pv, nil, fb.(Object))
+	}
+	for _, fb := range ofblocks {
+		rlm.DidUpdate(pv, fb, nil)
+	}
+	rlm.FinalizeRealmTransaction(m.Store)
+	m.Store.SetPackageRealm(rlm)
+	m.saveUpgradedTypes(pb)
+
+	// run migrate function.
+	if migrate != nil {
+		fb := pv.GetFileBlock(m.Store, migrate.FileName)
+		m.PushBlock(fb)
+		m.runFunc(StageAdd, migrate.Name, true)
+		m.PopBlock()
+	}
+	// save again after migrate, and store mempackage.
+	m.resavePackageValues(nil)
+	m.Store.AddMemPackage(mpkg, mptype)
+
+	return pn, pv
+}
+
+// Checks that the declared types of opn, which may be persisted, are kept
+// with the same underlying type in pn, that the kept variables are not
+// changing type, and that the functions and methods are kept with the same
+// signature.
+func checkUpgradeCompat(pkgPath string, opn *PackageNode, ovalues []TypedValue, pn *PackageNode, kept func(Name) (int, bool)) {
+	for oidx, n := range opn.Names {
+		otv := ovalues[oidx]
+		switch {
+		case otv.T == (heapItemType{}):
+			// a variable which is still declared must keep its type.
+			nidx, ok := pn.GetLocalIndex(n)
+			if !ok || !pn.HeapItems[nidx] {
+				continue // removed.
+			}
+			if _, ok := kept(n); !ok {
+				panicUpgrade(pkgPath, "variable %s cannot change type from %s to %s; declare a new variable and copy it in migrate() instead",
+					n, opn.Types[oidx], pn.Types[nidx])
+			}
+		case otv.T != nil && otv.T.Kind() == TypeKind:
+			odt, ok := otv.V.(TypeValue).Type.(*DeclaredType)
+			if !ok || odt.PkgPath != pkgPath || odt.Name != n {
+				continue // alias or generic.
+			}
+			nidx, ok := pn.GetLocalIndex(n)
+			if !ok {
+				panicUpgrade(pkgPath, "type %s cannot be removed", n)
+			}
+			ntv := pn.Values[nidx]
+			var ndt *DeclaredType
+			if ntv.T != nil && ntv.T.Kind() == TypeKind {
+				ndt, _ = ntv.V.(TypeValue).Type.(*DeclaredType)
+			}
+			if ndt == nil || ndt.TypeID() != odt.TypeID() {
+				panicUpgrade(pkgPath, "type %s cannot be removed or made an alias", n)
+			}
+			if ndt.Base.TypeID() != odt.Base.TypeID() {
+				panicUpgrade(pkgPath, "type %s cannot change its underlying type from %s to %s",
+					n, odt.Base.String(), ndt.Base.String())
+			}
+			for _, omv := range odt.Methods {
+				ofv := omv.V.(*FuncValue)
+				var nfv *FuncValue
+				for _, nmv := range ndt.Methods {
+					if mfv := nmv.V.(*FuncValue); mfv.Name == ofv.Name {
+						nfv = mfv
+					}
+				}
+				checkUpgradeFunc(pkgPath, "method "+string(n)+"."+string(ofv.Name), ofv, nfv)
+			}
+		case otv.T != nil && otv.T.Kind() == FuncKind:
+			ofv, ok := otv.V.(*FuncValue)
+			if !ok || strings.HasPrefix(string(n), "init.") {
+				continue
+			}
+			var nfv *FuncValue
+			if nidx, ok := pn.GetLocalIndex(n); ok {
+				nfv, _ = pn.Values[nidx].V.(*FuncValue)
+			}
+			checkUpgradeFunc(pkgPath, "function "+string(n), ofv, nfv)
+		}
+	}
+	// the types declared in function bodies are not in the package block.
+	// Those of the functions which did not move keep their TypeID.
+	odts := localDeclaredTypes(opn)
+	for tid, ndt := range localDeclaredTypes(pn) {
+		odt, ok := odts[tid]
+		if ok && ndt.Base.TypeID() != odt.Base.TypeID() {
+			panicUpgrade(pkgPath, "type %s cannot change its underlying type from %s to %s",
+				odt.String(), odt.Base.String(), ndt.Base.String())
+		}
+	}
+}
+
+// Checks that the function ofv, which may be referred to by the function
+// values of the realm, is kept with the same signature by nfv, if not nil.
+func checkUpgradeFunc(pkgPath string, what string, ofv, nfv *FuncValue) {
+	if nfv == nil {
+		panicUpgrade(pkgPath, "%s cannot be removed; the function values of the realm may refer to it", what)
+	}
+	if ofv.Type.TypeID() != nfv.Type.TypeID() {
+		panicUpgrade(pkgPath, "%s cannot change its signature from %s to %s",
+			what, ofv.Type.String(), nfv.Type.String())
+	}
+}
+
+// Checks that the objects of the realm pkgPath include no closure declared by
+// the realm, as they refer to their function literal by location, which is
+// not kept by the new code.
+func checkUpgradeClosures(store Store, pkgPath string) {
+	for oo := range store.IterRealmObjects(pkgPath) {
+		var fv *FuncValue
+		switch cv := oo.(type) {
+		case *FuncValue:
+			fv = cv
+		case *BoundMethodValue:
+			fv = cv.Func
+		default:
+			continue
+		}
+		// closures have no name, which is not persisted with IsClosure.
+		if fv.Name == "" && fv.PkgPath == pkgPath && !fv.IsNative() {
+			panicUpgrade(pkgPath, "the realm stores a closure declared at %s; closures cannot be bound to new code",
+				fv.Source.GetLocation())
+		}
+	}
+}
+
+// Returns the declared types of the files of pn which are not declared at the
+// package level, but in function bodies, by TypeID.
+func localDeclaredTypes(pn *PackageNode) map[TypeID]*DeclaredType {
+	dts := map[TypeID]*DeclaredType{}
+	if pn.FileSet == nil {
+		return dts
+	}
+	for _, fn := range pn.Files {
+		Transcribe(fn, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+			if stage != TRANS_ENTER {
+				return n, TRANS_CONTINUE
+			}
+			td, ok := n.(*TypeDecl)
+			if !ok || td.IsAlias {
+				return n, TRANS_CONTINUE
+			}
+			if ctx, ok := td.Type.(*constTypeExpr); ok {
+				if dt, ok := ctx.Type.(*DeclaredType); ok && !dt.ParentLoc.IsZero() {
+					dts[dt.TypeID()] = dt
+				}
+			}
+			return n, TRANS_CONTINUE
+		})
+	}
+	return dts
+}
+
+// Returns the migrate function declared in pn, if any.
+func getMigrateFunc(pkgPath string, pn *PackageNode) *FuncValue {
+	idx, ok := pn.GetLocalIndex("migrate")
+	if !ok {
+		return nil
+	}
+	fv, ok := pn.Values[idx].V.(*FuncValue)
+	ft, _ := pn.Types[idx].(*FuncType)
+	if !ok || ft == nil {
+		panicUpgrade(pkgPath, "migrate must be a function")
+	}
+	if len(ft.Results) != 0 || len(ft.Params) > 1 ||
+		(len(ft.Params) == 1 && !ft.IsCrossing()) {
+		panicUpgrade(pkgPath, "migrate must be declared as func migrate() or func migrate(cur realm)")
+	}
+	return fv
+}
+
+// Saves the declared types of the upgraded package block pb. Types already
+// persisted are saved with the new methods, also updating the instances which
+// may already be referenced by values loaded from the store.
+func (m *Machine) saveUpgradedTypes(pb *Block) {
+	for _, tv := range pb.Values {
+		tvv, ok := tv.V.(TypeValue)
+		if !ok {
+			continue
+		}
+		dt, ok := tvv.Type.(*DeclaredType)
+		if !ok {
+			continue
+		}
+		if odt, ok := m.Store.GetTypeSafe(dt.TypeID()).(*DeclaredType); ok && odt != dt {
+			odt.Methods = dt.Methods
+			m.Store.SetType(odt)
+		} else {
+			m.Store.SetType(dt)
+		}
+	}
+}
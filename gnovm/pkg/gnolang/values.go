@@ -2687,6 +2687,12 @@ func defaultTypedValue(alloc *Allocator, t Type) TypedValue {
 	}
 }
 
+// DefaultTypedValue returns the zero value of type t, e.g. for the fields
+// omitted from the arguments of a call.
+func DefaultTypedValue(alloc *Allocator, t Type) TypedValue {
+	return defaultTypedValue(alloc, t)
+}
+
 func typedInt(i int) TypedValue {
 	tv := TypedValue{T: IntType}
 	tv.SetInt(int64(i))
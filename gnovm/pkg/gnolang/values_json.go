@@ -0,0 +1,321 @@
+package gnolang
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"strconv"
+)
+
+// JSONValue is the JSON encoding of a TypedValue, for the clients decoding
+// the results of calls and queries, which TypedValue.String formats with the
+// "(value type)" syntax meant for humans.
+//
+// T is the type of the value, as in TypedValue.String, or "" for a nil
+// interface. V depends on the kind of T:
+//   - bool and string: a JSON boolean or string.
+//   - integers of up to 32 bits and floats: a JSON number. Floats which are
+//     not finite are the strings "NaN", "+Inf" and "-Inf".
+//   - int, int64, uint, uint64 and untyped big numbers: a decimal string, as
+//     they may not be exactly represented by a JSON number.
+//   - arrays and slices: an array of JSONValues, or the base64 string of the
+//     bytes for the arrays and slices of bytes.
+//   - structs: an object of the JSONValue of each field, by field name.
+//   - maps: an array of {"K": key, "V": value} JSONValues, in the order of
+//     insertion.
+//   - pointers: {"ObjectID": id} if the pointed value is persisted, which can
+//     be queried with vm/qobject, otherwise {"Value": value}.
+//   - funcs: the name of the function. Types: the type. Packages: the path.
+//   - nil pointers, slices, maps, funcs and interfaces: null.
+//
+// A persisted array, struct or map already encoded (e.g. shared by several
+// values) is {"ObjectID": id} the next times. A value refering to itself is
+// cut with null at the cycle. The values beyond the first maxJSONNodes values
+// are cut from the arrays, slices and maps, or with null, and so are the
+// strings and numbers beyond maxJSONSize bytes.
+// Errors are encoded like any other value: their methods, like Error, are not
+// run.
+type JSONValue struct {
+	T string          `json:"T"`
+	V json.RawMessage `json:"V"`
+}
+
+// JSONMapItem is an item of the V of the JSONValue of a map.
+type JSONMapItem struct {
+	K JSONValue `json:"K"`
+	V JSONValue `json:"V"`
+}
+
+// JSONPointer is the V of the JSONValue of a non-nil pointer, or of a
+// persisted value already encoded (with only the ObjectID).
+type JSONPointer struct {
+	ObjectID string     `json:"ObjectID,omitempty"`
+	Value    *JSONValue `json:"Value,omitempty"`
+}
+
+const (
+	maxJSONNodes = 10_000  // maximum number of encoded values
+	maxJSONSize  = 1 << 20 // maximum number of bytes of encoded strings and numbers
+)
+
+// ToJSONValue returns the JSONValue of tv. Persisted values are loaded from
+// store.
+func (tv *TypedValue) ToJSONValue(store Store) JSONValue {
+	enc := newJSONEncoder(store)
+	return enc.encode(tv)
+}
+
+// ToJSONValues returns the JSONValue of each of tvs, e.g. of the results of
+// a call.
+func ToJSONValues(store Store, tvs []TypedValue) []JSONValue {
+	enc := newJSONEncoder(store)
+	jvs := make([]JSONValue, len(tvs))
+	for i := range tvs {
+		jvs[i] = enc.encode(&tvs[i])
+	}
+	return jvs
+}
+
+type jsonEncoder struct {
+	store Store
+	// the container values being encoded, to cut the cycles.
+	seen map[Value]struct{}
+	// the persisted objects already encoded, referred to by ID.
+	done map[Object]struct{}
+	// the number of encoded values, and of bytes of strings and numbers.
+	nodes, size int
+}
+
+func newJSONEncoder(store Store) *jsonEncoder {
+	return &jsonEncoder{
+		store: store,
+		seen:  make(map[Value]struct{}),
+		done:  make(map[Object]struct{}),
+	}
+}
+
+var jsonNull = json.RawMessage("null")
+
+func (enc *jsonEncoder) encode(tv *TypedValue) (jv JSONValue) {
+	if tv.T == nil {
+		return JSONValue{V: jsonNull}
+	}
+	if enc.full() {
+		return JSONValue{V: jsonNull}
+	}
+	enc.nodes++
+	fillValueTV(enc.store, tv)
+	jv.T = tv.T.String()
+	jv.V = enc.encodeValue(tv)
+	return jv
+}
+
+func (enc *jsonEncoder) encodeValue(tv *TypedValue) json.RawMessage {
+	switch bt := baseOf(tv.T).(type) {
+	case PrimitiveType:
+		return enc.encodeBytes(encodePrimitive(tv, bt))
+	case *ArrayType:
+		av := tv.V.(*ArrayValue)
+		if ref := enc.reference(av); ref != nil {
+			return ref
+		}
+		return enc.encodeList(av, 0, av.GetLength(), bt.Elt)
+	case *SliceType:
+		if tv.V == nil {
+			return jsonNull
+		}
+		sv := tv.V.(*SliceValue)
+		return enc.encodeList(sv.GetBase(enc.store), sv.Offset, sv.Length, bt.Elt)
+	case *StructType:
+		sv := tv.V.(*StructValue)
+		if ref := enc.reference(sv); ref != nil {
+			return ref
+		}
+		if !enc.push(sv) {
+			return jsonNull
+		}
+		defer enc.pop(sv)
+		var buf bytes.Buffer
+		buf.WriteByte('{')
+		for i := range sv.Fields {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.Write(mustMarshalJSON(string(bt.Fields[i].Name)))
+			buf.WriteByte(':')
+			buf.Write(mustMarshalJSON(enc.encode(&sv.Fields[i])))
+		}
+		buf.WriteByte('}')
+		return buf.Bytes()
+	case *MapType:
+		if tv.V == nil {
+			return jsonNull
+		}
+		mv := tv.V.(*MapValue)
+		if ref := enc.reference(mv); ref != nil {
+			return ref
+		}
+		if !enc.push(mv) {
+			return jsonNull
+		}
+		defer enc.pop(mv)
+		items := make([]JSONMapItem, 0, min(mv.GetLength(), maxJSONNodes))
+		for item := mv.List.Head; item != nil && !enc.full(); item = item.Next {
+			items = append(items, JSONMapItem{
+				K: enc.encode(&item.Key),
+				V: enc.encode(&item.Value),
+			})
+		}
+		return mustMarshalJSON(items)
+	case *PointerType:
+		if tv.V == nil {
+			return jsonNull
+		}
+		pv := tv.V.(PointerValue)
+		base := pv.GetBase(enc.store)
+		if oid := base.GetObjectID(); !oid.IsZero() {
+			return mustMarshalJSON(JSONPointer{ObjectID: oid.String()})
+		}
+		if !enc.push(base) {
+			return jsonNull
+		}
+		defer enc.pop(base)
+		elem := enc.encode(pv.TV)
+		return mustMarshalJSON(JSONPointer{Value: &elem})
+	case *FuncType:
+		switch fv := tv.V.(type) {
+		case nil:
+			return jsonNull
+		case *FuncValue:
+			return mustMarshalJSON(string(fv.Name))
+		case *BoundMethodValue:
+			return mustMarshalJSON(string(fv.Func.Name))
+		}
+	case *InterfaceType:
+		return jsonNull
+	case *TypeType:
+		return mustMarshalJSON(tv.V.(TypeValue).Type.String())
+	case *PackageType:
+		return mustMarshalJSON(tv.V.(*PackageValue).PkgPath)
+	}
+	return jsonNull
+}
+
+func encodePrimitive(tv *TypedValue, bt PrimitiveType) json.RawMessage {
+	switch bt {
+	case UntypedBoolType, BoolType:
+		return strconv.AppendBool(nil, tv.GetBool())
+	case UntypedStringType, StringType:
+		return mustMarshalJSON(tv.GetString())
+	case Int8Type:
+		return strconv.AppendInt(nil, int64(tv.GetInt8()), 10)
+	case Int16Type:
+		return strconv.AppendInt(nil, int64(tv.GetInt16()), 10)
+	case UntypedRuneType, Int32Type:
+		return strconv.AppendInt(nil, int64(tv.GetInt32()), 10)
+	case Uint8Type:
+		return strconv.AppendUint(nil, uint64(tv.GetUint8()), 10)
+	case DataByteType:
+		return strconv.AppendUint(nil, uint64(tv.GetDataByte()), 10)
+	case Uint16Type:
+		return strconv.AppendUint(nil, uint64(tv.GetUint16()), 10)
+	case Uint32Type:
+		return strconv.AppendUint(nil, uint64(tv.GetUint32()), 10)
+	case IntType:
+		return mustMarshalJSON(strconv.FormatInt(tv.GetInt(), 10))
+	case Int64Type:
+		return mustMarshalJSON(strconv.FormatInt(tv.GetInt64(), 10))
+	case UintType:
+		return mustMarshalJSON(strconv.FormatUint(tv.GetUint(), 10))
+	case Uint64Type:
+		return mustMarshalJSON(strconv.FormatUint(tv.GetUint64(), 10))
+	case Float32Type:
+		return encodeFloat(float64(math.Float32frombits(tv.GetFloat32())), 32)
+	case Float64Type:
+		return encodeFloat(math.Float64frombits(tv.GetFloat64()), 64)
+	case UntypedBigintType:
+		return mustMarshalJSON(tv.V.(BigintValue).V.String())
+	case UntypedBigdecType:
+		return mustMarshalJSON(tv.V.(BigdecValue).V.String())
+	default:
+		panic("should not happen")
+	}
+}
+
+func encodeFloat(f float64, bitSize int) json.RawMessage {
+	switch {
+	case math.IsNaN(f):
+		return mustMarshalJSON("NaN")
+	case math.IsInf(f, 1):
+		return mustMarshalJSON("+Inf")
+	case math.IsInf(f, -1):
+		return mustMarshalJSON("-Inf")
+	}
+	return strconv.AppendFloat(nil, f, 'g', -1, bitSize)
+}
+
+// Encodes the length elements of av from offset, of type et.
+func (enc *jsonEncoder) encodeList(av *ArrayValue, offset, length int, et Type) json.RawMessage {
+	if et.Kind() == Uint8Kind {
+		bz := av.GetReadonlyBytes()[offset : offset+length]
+		return enc.encodeBytes(mustMarshalJSON(base64.StdEncoding.EncodeToString(bz)))
+	}
+	if !enc.push(av) {
+		return jsonNull
+	}
+	defer enc.pop(av)
+	jvs := make([]JSONValue, 0, min(length, maxJSONNodes))
+	for i := 0; i < length && !enc.full(); i++ {
+		jvs = append(jvs, enc.encode(&av.List[offset+i]))
+	}
+	return mustMarshalJSON(jvs)
+}
+
+// Returns the encoded string or number bz, or null if it is beyond the
+// maximum size.
+func (enc *jsonEncoder) encodeBytes(bz json.RawMessage) json.RawMessage {
+	if enc.size += len(bz); enc.size > maxJSONSize {
+		return jsonNull
+	}
+	return bz
+}
+
+// Returns the reference to the persisted object o if it was already encoded,
+// otherwise marks it as encoded and returns nil.
+func (enc *jsonEncoder) reference(o Object) json.RawMessage {
+	oid := o.GetObjectID()
+	if oid.IsZero() {
+		return nil
+	}
+	if _, ok := enc.done[o]; ok {
+		return mustMarshalJSON(JSONPointer{ObjectID: oid.String()})
+	}
+	enc.done[o] = struct{}{}
+	return nil
+}
+
+// Returns whether the maximum number of values were encoded.
+func (enc *jsonEncoder) full() bool {
+	return enc.nodes >= maxJSONNodes
+}
+
+func (enc *jsonEncoder) push(v Value) bool {
+	if _, ok := enc.seen[v]; ok {
+		return false
+	}
+	enc.seen[v] = struct{}{}
+	return true
+}
+
+func (enc *jsonEncoder) pop(v Value) {
+	delete(enc.seen, v)
+}
+
+func mustMarshalJSON(v any) json.RawMessage {
+	bz, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
@@ -0,0 +1,136 @@
+package gnolang
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	stypes "github.com/gnolang/gno/tm2/pkg/store/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToJSONValues(t *testing.T) {
+	db := memdb.NewMemDB()
+	baseStore := dbadapter.StoreConstructor(db, stypes.StoreOptions{})
+	iavlStore := iavl.StoreConstructor(db, stypes.StoreOptions{})
+	store := NewStore(nil, baseStore, iavlStore)
+	m := NewMachine("jsontest", store)
+	m.RunMemPackage(&std.MemPackage{
+		Type: MPStdlibProd,
+		Name: "jsontest",
+		Path: "jsontest",
+		Files: []*std.MemFile{
+			{Name: "a.gno", Body: `package jsontest
+
+type Item struct {
+	Name string
+	N    int
+	Tags []string
+	Next *Item
+}
+
+type MyErr struct{ Code int }
+
+func (e *MyErr) Error() string { return "code " + itoa(e.Code) }
+
+func itoa(n int) string {
+	if n < 10 {
+		return string(rune('0' + n))
+	}
+	return itoa(n/10) + itoa(n%10)
+}
+
+var Shared = map[string]int{"a": 1}
+
+func Pair() [2]map[string]int {
+	return [2]map[string]int{Shared, Shared}
+}
+
+func Big() string {
+	s := "x"
+	for i := 0; i < 21; i++ {
+		s += s
+	}
+	return s
+}
+
+func Cycle() *Item {
+	it := &Item{Name: "a"}
+	it.Next = it
+	return it
+}
+`},
+		},
+	}, true)
+
+	sharedID := m.Eval(m.MustParseExpr(`Shared`))[0].V.(*MapValue).GetObjectID().String()
+
+	tt := []struct {
+		expr string
+		want string
+	}{
+		{`true`, `{"T":"bool","V":true}`},
+		{`"hello\n"`, `{"T":"string","V":"hello\n"}`},
+		{`int8(-8)`, `{"T":"int8","V":-8}`},
+		{`uint32(32)`, `{"T":"uint32","V":32}`},
+		{`int(1 << 62)`, `{"T":"int","V":"4611686018427387904"}`},
+		{`uint64(1 << 63)`, `{"T":"uint64","V":"9223372036854775808"}`},
+		{`float64(1.5)`, `{"T":"float64","V":1.5}`},
+		{`float32(0.25)`, `{"T":"float32","V":0.25}`},
+		{`[]byte("hi")`, `{"T":"[]uint8","V":"aGk="}`},
+		{`[2]byte{1, 2}`, `{"T":"[2]uint8","V":"AQI="}`},
+		{`[]int{1, 2}[1:]`, `{"T":"[]int","V":[{"T":"int","V":"2"}]}`},
+		{`[]string(nil)`, `{"T":"[]string","V":null}`},
+		{`map[string]int{"a": 1}`, `{"T":"map[string]int","V":[{"K":{"T":"string","V":"a"},"V":{"T":"int","V":"1"}}]}`},
+		{
+			`Item{Name: "x", N: 1, Tags: []string{"t"}}`,
+			`{"T":"jsontest.Item","V":{"Name":{"T":"string","V":"x"},"N":{"T":"int","V":"1"},"Tags":{"T":"[]string","V":[{"T":"string","V":"t"}]},"Next":{"T":"*jsontest.Item","V":null}}}`,
+		},
+		{
+			`&Item{Name: "p"}`,
+			`{"T":"*jsontest.Item","V":{"Value":{"T":"jsontest.Item","V":{"Name":{"T":"string","V":"p"},"N":{"T":"int","V":"0"},"Tags":{"T":"[]string","V":null},"Next":{"T":"*jsontest.Item","V":null}}}}}`,
+		},
+		{
+			`Cycle()`,
+			`{"T":"*jsontest.Item","V":{"Value":{"T":"jsontest.Item","V":{"Name":{"T":"string","V":"a"},"N":{"T":"int","V":"0"},"Tags":{"T":"[]string","V":null},"Next":{"T":"*jsontest.Item","V":null}}}}}`,
+		},
+		{
+			`error(&MyErr{Code: 42})`,
+			`{"T":"*jsontest.MyErr","V":{"Value":{"T":"jsontest.MyErr","V":{"Code":{"T":"int","V":"42"}}}}}`,
+		},
+		{`error(nil)`, `{"T":"","V":null}`},
+		{`itoa`, `{"T":"func(int) string","V":"itoa"}`},
+		{
+			`Pair()`,
+			`{"T":"[2]map[string]int","V":[{"T":"map[string]int","V":[{"K":{"T":"string","V":"a"},"V":{"T":"int","V":"1"}}]},{"T":"map[string]int","V":{"ObjectID":"` + sharedID + `"}}]}`,
+		},
+		{`[]string{Big()}`, `{"T":"[]string","V":[{"T":"string","V":null}]}`},
+	}
+	for _, tc := range tt {
+		t.Run(tc.expr, func(t *testing.T) {
+			tvs := m.Eval(m.MustParseExpr(tc.expr))
+			require.Len(t, tvs, 1)
+			bz, err := json.Marshal(tvs[0].ToJSONValue(store))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, string(bz))
+		})
+	}
+}
+
+func TestToJSONValues_Limits(t *testing.T) {
+	m := NewMachine("jsontest", nil)
+
+	t.Run("max nodes", func(t *testing.T) {
+		tvs := m.Eval(m.MustParseExpr(`make([]int, 20000)`))
+		jv := tvs[0].ToJSONValue(m.Store)
+
+		var elems []JSONValue
+		require.NoError(t, json.Unmarshal(jv.V, &elems))
+		// the slice itself is the first value.
+		assert.Len(t, elems, maxJSONNodes-1)
+	})
+}
@@ -33,6 +33,16 @@ type File struct {
 	// - Data whose type is defined in this realm cannot be retained in other realms.
 	Private bool `toml:"private,omitempty" json:"private,omitempty"`
 
+	// Upgradable indicates that the module can be upgraded after being added,
+	// by its creator or by the governance realm of the upgrade section.
+	// Upgradable modules:
+	// - Must be realms.
+	// - Cannot be imported by other packages, as the code of importers
+	//   refers to the declarations of the module by their index in its
+	//   package block, which an upgrade may change.
+	// - Keep their state when upgraded; see MsgUpgradePackage.
+	Upgradable bool `toml:"upgradable,omitempty" json:"upgradable,omitempty"`
+
 	// Replace is a list of replace directives for the module's dependencies.
 	// Each replace can link to a different online module path, or a local path.
 	// If this value is set, the module cannot be added to the chain.
@@ -42,6 +52,10 @@ type File struct {
 	// It is filled by the vmkeeper when a module is added.
 	// It is not intended to be used offchain.
 	AddPkg AddPkg `toml:"addpkg,omitempty" json:"addpkg,omitempty"`
+
+	// Upgrade is the upgrade section of the gnomod.toml file, for upgradable
+	// modules.
+	Upgrade Upgrade `toml:"upgrade,omitempty" json:"upgrade,omitempty"`
 }
 
 type AddPkg struct {
@@ -53,6 +67,16 @@ type AddPkg struct {
 	// XXX: Consider things like IsUsingBanker or other security-awareness flags
 }
 
+type Upgrade struct {
+	// Governance is the path of a realm allowed to authorize other
+	// addresses than the creator to upgrade the module, through its
+	// function `IsUpgradeAuthorized(pkgPath, addr string) bool`.
+	Governance string `toml:"governance,omitempty" json:"governance,omitempty"`
+	// Height is the block height at which the module was last upgraded.
+	// It is filled by the vmkeeper when a module is upgraded.
+	Height int `toml:"height,omitempty" json:"height,omitempty"`
+}
+
 type Replace struct {
 	// Old is the old module path of the dependency, i.e.,
 	// `gno.land/r/path/to/module`.
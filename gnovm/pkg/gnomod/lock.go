@@ -0,0 +1,152 @@
+package gnomod
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+	"golang.org/x/mod/sumdb/dirhash"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// LockFileName is the name of the lockfile written next to gnowork.toml or
+// gnomod.toml by `gno mod download` and `gno mod tidy`.
+const LockFileName = "gno.lock"
+
+// LockFile pins the remote dependencies of a workspace or module.
+type LockFile struct {
+	// Packages is the list of locked packages, sorted by path.
+	Packages []LockedPackage `toml:"package,omitempty" json:"package,omitempty"`
+}
+
+// LockedPackage is the lockfile entry of a remote package.
+type LockedPackage struct {
+	// Path is the package path, i.e., `gno.land/p/nt/avl/v0`.
+	Path string `toml:"path" json:"path"`
+	// ChainID is the ID of the chain the package was fetched from.
+	ChainID string `toml:"chain_id,omitempty" json:"chain_id,omitempty"`
+	// Height is the block height at which the package was added to the
+	// chain, as found in the addpkg section of its gnomod.toml file.
+	Height int `toml:"height,omitempty" json:"height,omitempty"`
+	// Hash is the content hash of the package files, see [HashFiles].
+	Hash string `toml:"hash" json:"hash"`
+}
+
+// NewLockedPackage returns the lockfile entry of the package at pkgPath made
+// of the given files.
+func NewLockedPackage(pkgPath, chainID string, files []*std.MemFile) (LockedPackage, error) {
+	hash, err := HashFiles(files)
+	if err != nil {
+		return LockedPackage{}, fmt.Errorf("hash package %q: %w", pkgPath, err)
+	}
+
+	lp := LockedPackage{Path: pkgPath, ChainID: chainID, Hash: hash}
+	for _, file := range files {
+		if file.Name != "gnomod.toml" {
+			continue
+		}
+		mod, err := parseTomlBytes(file.Name, []byte(file.Body))
+		if err != nil {
+			return LockedPackage{}, err
+		}
+		lp.Height = mod.AddPkg.Height
+	}
+	return lp, nil
+}
+
+// HashFiles returns the content hash of a package made of the given files.
+// The hash does not depend on the order of the files.
+func HashFiles(files []*std.MemFile) (string, error) {
+	bodies := make(map[string]string, len(files))
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		if _, ok := bodies[file.Name]; ok {
+			return "", fmt.Errorf("duplicate file %q", file.Name)
+		}
+		bodies[file.Name] = file.Body
+		names = append(names, file.Name)
+	}
+
+	return dirhash.Hash1(names, func(name string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(bodies[name])), nil
+	})
+}
+
+// ReadLockFile reads the lockfile at the given path. A missing lockfile is
+// returned as an empty one.
+func ReadLockFile(fpath string) (*LockFile, error) {
+	data, err := os.ReadFile(fpath)
+	if os.IsNotExist(err) {
+		return &LockFile{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read file %q: %w", fpath, err)
+	}
+
+	var lf LockFile
+	if err := toml.Unmarshal(data, &lf); err != nil {
+		return nil, fmt.Errorf("error parsing %s file at %q: %w", LockFileName, fpath, err)
+	}
+	return &lf, nil
+}
+
+// Get returns the entry of the package at pkgPath, if any.
+func (lf *LockFile) Get(pkgPath string) (LockedPackage, bool) {
+	for _, lp := range lf.Packages {
+		if lp.Path == pkgPath {
+			return lp, true
+		}
+	}
+	return LockedPackage{}, false
+}
+
+// Set adds or replaces the entry of a package.
+func (lf *LockFile) Set(lp LockedPackage) {
+	idx := slices.IndexFunc(lf.Packages, func(p LockedPackage) bool { return p.Path == lp.Path })
+	if idx == -1 {
+		lf.Packages = append(lf.Packages, lp)
+	} else {
+		lf.Packages[idx] = lp
+	}
+	slices.SortFunc(lf.Packages, func(a, b LockedPackage) int { return strings.Compare(a.Path, b.Path) })
+}
+
+// Prune removes the entries of the packages that are not in keep, and returns
+// the removed paths.
+func (lf *LockFile) Prune(keep map[string]struct{}) []string {
+	var removed []string
+	lf.Packages = slices.DeleteFunc(lf.Packages, func(lp LockedPackage) bool {
+		if _, ok := keep[lp.Path]; ok {
+			return false
+		}
+		removed = append(removed, lp.Path)
+		return true
+	})
+	return removed
+}
+
+// WriteString writes the lockfile to a string.
+func (lf *LockFile) WriteString() string {
+	var builder strings.Builder
+	builder.WriteString("# This file is generated by gno mod download and gno mod tidy. DO NOT EDIT.\n\n")
+
+	encoder := toml.NewEncoder(&builder)
+	encoder.Order(toml.OrderPreserve)
+	if err := encoder.Encode(lf); err != nil {
+		panic(err)
+	}
+
+	return builder.String()
+}
+
+// WriteFile writes the lockfile to the given absolute file path.
+func (lf *LockFile) WriteFile(fpath string) error {
+	if err := os.WriteFile(fpath, []byte(lf.WriteString()), 0o644); err != nil {
+		return fmt.Errorf("writefile %q: %w", fpath, err)
+	}
+	return nil
+}
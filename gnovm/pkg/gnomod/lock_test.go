@@ -0,0 +1,77 @@
+package gnomod
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+func TestHashFiles(t *testing.T) {
+	a := &std.MemFile{Name: "a.gno", Body: "package a"}
+	b := &std.MemFile{Name: "b.gno", Body: "package a\n"}
+
+	hash, err := HashFiles([]*std.MemFile{a, b})
+	require.NoError(t, err)
+	assert.Regexp(t, `^h1:`, hash)
+
+	reversed, err := HashFiles([]*std.MemFile{b, a})
+	require.NoError(t, err)
+	assert.Equal(t, hash, reversed, "hash should not depend on the files order")
+
+	changed, err := HashFiles([]*std.MemFile{a, {Name: "b.gno", Body: "package b\n"}})
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changed)
+
+	_, err = HashFiles([]*std.MemFile{a, a})
+	assert.ErrorContains(t, err, `duplicate file "a.gno"`)
+}
+
+func TestNewLockedPackage(t *testing.T) {
+	files := []*std.MemFile{
+		{Name: "gnomod.toml", Body: "module = \"gno.land/p/demo/foo\"\n\n[addpkg]\n  creator = \"g1creator\"\n  height = 42\n"},
+		{Name: "foo.gno", Body: "package foo"},
+	}
+
+	lp, err := NewLockedPackage("gno.land/p/demo/foo", "test5", files)
+	require.NoError(t, err)
+	hash, err := HashFiles(files)
+	require.NoError(t, err)
+	assert.Equal(t, LockedPackage{Path: "gno.land/p/demo/foo", ChainID: "test5", Height: 42, Hash: hash}, lp)
+
+	_, err = NewLockedPackage("gno.land/p/demo/foo", "test5", []*std.MemFile{{Name: "gnomod.toml", Body: "not toml"}})
+	assert.Error(t, err)
+}
+
+func TestLockFile(t *testing.T) {
+	fpath := filepath.Join(t.TempDir(), LockFileName)
+
+	lf, err := ReadLockFile(fpath)
+	require.NoError(t, err)
+	assert.Empty(t, lf.Packages, "missing lockfile should be empty")
+
+	lf.Set(LockedPackage{Path: "gno.land/p/demo/b", Hash: "h1:b"})
+	lf.Set(LockedPackage{Path: "gno.land/p/demo/a", ChainID: "test5", Height: 7, Hash: "h1:a"})
+	lf.Set(LockedPackage{Path: "gno.land/p/demo/b", Hash: "h1:b2"})
+	assert.Equal(t, []LockedPackage{
+		{Path: "gno.land/p/demo/a", ChainID: "test5", Height: 7, Hash: "h1:a"},
+		{Path: "gno.land/p/demo/b", Hash: "h1:b2"},
+	}, lf.Packages)
+
+	require.NoError(t, lf.WriteFile(fpath))
+	read, err := ReadLockFile(fpath)
+	require.NoError(t, err)
+	assert.Equal(t, lf, read)
+
+	lp, ok := read.Get("gno.land/p/demo/a")
+	assert.True(t, ok)
+	assert.Equal(t, 7, lp.Height)
+
+	removed := read.Prune(map[string]struct{}{"gno.land/p/demo/a": {}})
+	assert.Equal(t, []string{"gno.land/p/demo/b"}, removed)
+	_, ok = read.Get("gno.land/p/demo/b")
+	assert.False(t, ok)
+}
@@ -78,6 +78,7 @@ func TestMarshalTomlHelper(t *testing.T) {
 				file.Ignore = true
 				file.Draft = true
 				file.Private = true
+				file.Upgradable = true
 				file.Replace = []Replace{
 					{Old: "gno.land/r/test", New: "gno.land/r/test/v2"},
 					{Old: "gno.land/r/test/v3", New: "../.."},
@@ -85,9 +86,11 @@ func TestMarshalTomlHelper(t *testing.T) {
 				file.Gno = "0.9"
 				file.AddPkg.Creator = "addr1"
 				file.AddPkg.Height = 42
+				file.Upgrade.Governance = "gno.land/r/gov"
+				file.Upgrade.Height = 43
 				return &file
 			}(),
-			expected: "module = \"gno.land/r/test\"\ngno = \"0.9\"\nignore = true\ndraft = true\nprivate = true\n\n[[replace]]\n  old = \"gno.land/r/test\"\n  new = \"gno.land/r/test/v2\"\n\n[[replace]]\n  old = \"gno.land/r/test/v3\"\n  new = \"../..\"\n\n[addpkg]\n  creator = \"addr1\"\n  height = 42\n",
+			expected: "module = \"gno.land/r/test\"\ngno = \"0.9\"\nignore = true\ndraft = true\nprivate = true\nupgradable = true\n\n[[replace]]\n  old = \"gno.land/r/test\"\n  new = \"gno.land/r/test/v2\"\n\n[[replace]]\n  old = \"gno.land/r/test/v3\"\n  new = \"../..\"\n\n[addpkg]\n  creator = \"addr1\"\n  height = 42\n\n[upgrade]\n  governance = \"gno.land/r/gov\"\n  height = 43\n",
 		},
 		{
 			name:     "empty",
@@ -155,6 +155,17 @@ func Load(conf LoadConfig, patterns ...string) (PkgList, error) {
 	return loaded, nil
 }
 
+// RootDir returns the root directory of the loader context: the workspace
+// root if the current directory is in a workspace, the current directory if it
+// contains a gnomod.toml file.
+func RootDir() (string, error) {
+	loaderCtx, err := findLoaderContext()
+	if err != nil {
+		return "", err
+	}
+	return loaderCtx.Root, nil
+}
+
 type loaderContext struct {
 	Root        string
 	IsWorkspace bool
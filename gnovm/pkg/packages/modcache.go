@@ -9,6 +9,7 @@ import (
 	"github.com/gnolang/gno/gnovm/pkg/gnolang"
 	"github.com/gnolang/gno/gnovm/pkg/gnomod"
 	"github.com/gnolang/gno/gnovm/pkg/packages/pkgdownload"
+	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/gofrs/flock"
 )
 
@@ -16,6 +17,30 @@ func PackageDir(importPath string) string {
 	return filepath.Join(gnomod.ModCachePath(), filepath.FromSlash(importPath))
 }
 
+// ReadCachedPackage returns the files of a package downloaded in the modcache.
+func ReadCachedPackage(pkgPath string) ([]*std.MemFile, error) {
+	dir := PackageDir(pkgPath)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read cached package %q: %w", pkgPath, err)
+	}
+
+	files := []*std.MemFile{}
+	for _, entry := range entries {
+		// nested packages are stored in subdirectories
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		fpath := filepath.Join(dir, entry.Name())
+		body, err := os.ReadFile(fpath)
+		if err != nil {
+			return nil, fmt.Errorf("read file at %q: %w", fpath, err)
+		}
+		files = append(files, &std.MemFile{Name: entry.Name(), Body: string(body)})
+	}
+	return files, nil
+}
+
 // LockCache ensure the modcache dir exists, attempts to lock it and returns a filelock.
 func LockCache(modCachePath string) (*flock.Flock, error) {
 	if err := os.MkdirAll(modCachePath, 0o774); err != nil {
@@ -10,6 +10,12 @@ type PackageFetcher interface {
 	FetchPackage(pkgPath string) ([]*std.MemFile, error)
 }
 
+// ChainIDFetcher is implemented by the [PackageFetcher]s that know the ID of
+// the chain a package is fetched from.
+type ChainIDFetcher interface {
+	ChainID(pkgPath string) (string, error)
+}
+
 func NewNoopFetcher() PackageFetcher {
 	return &noopFetcher{}
 }
@@ -17,7 +17,10 @@ type gnoPackageFetcher struct {
 	remoteOverrides map[string]string
 }
 
-var _ pkgdownload.PackageFetcher = (*gnoPackageFetcher)(nil)
+var (
+	_ pkgdownload.PackageFetcher = (*gnoPackageFetcher)(nil)
+	_ pkgdownload.ChainIDFetcher = (*gnoPackageFetcher)(nil)
+)
 
 func New(remoteOverrides map[string]string) pkgdownload.PackageFetcher {
 	return &gnoPackageFetcher{
@@ -57,6 +60,27 @@ func (gpf *gnoPackageFetcher) FetchPackage(pkgPath string) ([]*std.MemFile, erro
 	return res, nil
 }
 
+// ChainID implements [pkgdownload.ChainIDFetcher].
+func (gpf *gnoPackageFetcher) ChainID(pkgPath string) (string, error) {
+	rpcURL, err := rpcURLFromPkgPath(pkgPath, gpf.remoteOverrides)
+	if err != nil {
+		return "", fmt.Errorf("get rpc url for pkg path %q: %w", pkgPath, err)
+	}
+
+	client, err := client.NewHTTPClient(rpcURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to instantiate tm2 client with remote %q: %w", rpcURL, err)
+	}
+	defer client.Close()
+
+	status, err := client.Status(context.Background(), nil)
+	if err != nil {
+		return "", fmt.Errorf("query status of remote %q: %w", rpcURL, err)
+	}
+
+	return status.NodeInfo.Network, nil
+}
+
 func rpcURLFromPkgPath(pkgPath string, remoteOverrides map[string]string) (string, error) {
 	parts := strings.Split(pkgPath, "/")
 	if len(parts) < 2 {
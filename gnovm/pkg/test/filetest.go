@@ -68,6 +68,9 @@ func (opts *TestOptions) runFiletest(fname string, source []byte, tgs gno.Store,
 		opslog = new(bytes.Buffer)
 	}
 	gasMeter := store.NewInfiniteGasMeter()
+	if opts.GasProfile != nil {
+		gasMeter = opts.GasProfile.GasMeter(gasMeter)
+	}
 	// Create machine for execution and run test
 	tcw := opts.BaseStore.CacheWrap()
 	m := gno.NewMachineWithOptions(gno.MachineOptions{
@@ -78,6 +81,8 @@ func (opts *TestOptions) runFiletest(fname string, source []byte, tgs gno.Store,
 		GasMeter:      gasMeter,
 		Debug:         opts.Debug,
 		ReviveEnabled: true,
+		Coverage:      opts.Coverage,
+		GasProfile:    opts.GasProfile,
 	})
 	defer m.Release()
 
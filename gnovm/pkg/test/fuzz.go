@@ -0,0 +1,1077 @@
+package test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"math"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
+)
+
+const (
+	// DefaultFuzzTime is the time spent fuzzing when neither
+	// [TestOptions.FuzzTime] nor [TestOptions.FuzzIterations] are set.
+	DefaultFuzzTime = 10 * time.Second
+
+	// fuzzCorpusHeader is the first line of the files in testdata/fuzz,
+	// which use the same format as Go's.
+	fuzzCorpusHeader = "go test fuzz v1"
+	fuzzCorpusDir    = "testdata/fuzz"
+
+	// Limits on the generated inputs, and on the minimization of failing
+	// inputs.
+	fuzzMaxBytes         = 4096
+	fuzzMaxPool          = 256
+	fuzzMaxMinimizeExecs = 2000
+	fuzzReportInterval   = 3 * time.Second
+)
+
+// fuzzTypes are the types supported as arguments of fuzz targets.
+var fuzzTypes = map[string]bool{
+	"string": true, "[]byte": true, "bool": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true,
+}
+
+// loadFuzzFuncs returns the fuzz tests declared in tfiles, that is, the
+// functions named FuzzXxx where Xxx does not start with a lowercase letter.
+func loadFuzzFuncs(pkgName string, tfiles *gno.FileSet) (rt []testFunc) {
+	for _, tf := range tfiles.Files {
+		for _, d := range tf.Decls {
+			fd, ok := d.(*gno.FuncDecl)
+			if !ok || fd.IsMethod {
+				continue
+			}
+			fname := string(fd.Name)
+			if !isFuzzName(fname) {
+				continue
+			}
+			rt = append(rt, testFunc{
+				Package:  pkgName,
+				Name:     fname,
+				Filename: tf.FileName,
+			})
+		}
+	}
+	return
+}
+
+func isFuzzName(name string) bool {
+	rest, ok := strings.CutPrefix(name, "Fuzz")
+	if !ok {
+		return false
+	}
+	if rest == "" {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(rest)
+	return !unicode.IsLower(r)
+}
+
+// checkFuzzFlag returns an error if opts.FuzzFlag matches more than one of
+// the fuzz tests in the given file sets.
+func (opts *TestOptions) checkFuzzFlag(sets ...*gno.FileSet) error {
+	if opts.FuzzFlag == "" {
+		return nil
+	}
+	re, err := regexp.Compile(opts.FuzzFlag)
+	if err != nil {
+		return fmt.Errorf("invalid -fuzz regexp: %w", err)
+	}
+	var matches []string
+	for _, set := range sets {
+		for _, fz := range loadFuzzFuncs("", set) {
+			if re.MatchString(fz.Name) {
+				matches = append(matches, fz.Name)
+			}
+		}
+	}
+	if len(matches) > 1 {
+		return fmt.Errorf("will not fuzz, -fuzz matches more than one fuzz test: %v", matches)
+	}
+	return nil
+}
+
+// fuzzer runs a single fuzz test, through the unexported helpers of the
+// testing package.
+type fuzzer struct {
+	opts  *TestOptions
+	name  string
+	fsDir string
+
+	newMachine func() *gno.Machine
+	m          *gno.Machine
+	testingcx  *gno.ConstExpr
+	helpers    map[string]gno.TypedValue
+
+	f      gno.TypedValue // *testing.F
+	target gno.TypedValue // fuzz target
+	params []gno.Type     // fuzzed parameters of the target
+}
+
+func (fz *fuzzer) helper(name string) *gno.ConstExpr {
+	return gno.NewConstExpr(gno.Nx(name), fz.helpers[name])
+}
+
+func (fz *fuzzer) fx() *gno.ConstExpr {
+	return gno.NewConstExpr(gno.Nx("f"), fz.f)
+}
+
+// runFuzzTest runs the fuzz test tf. The fuzz target is called with the seed
+// corpus and the inputs in testdata/fuzz; if fuzzing is true, it is also
+// called with generated inputs.
+func (opts *TestOptions) runFuzzTest(
+	mpkg *std.MemPackage,
+	pv *gno.PackageValue,
+	fsDir string,
+	tgs gno.TransactionStore,
+	tf testFunc,
+	fuzzing bool,
+) error {
+	fz := &fuzzer{
+		opts:  opts,
+		name:  tf.Name,
+		fsDir: fsDir,
+		newMachine: func() *gno.Machine {
+			m := Machine(tgs, opts.WriterForStore(), mpkg.Path, false, store.NewInfiniteGasMeter())
+			m.Coverage = opts.Coverage
+			opts.attachGasProfile(m)
+			m.SetActivePackage(pv)
+			return m
+		},
+		helpers: map[string]gno.TypedValue{},
+	}
+	fz.m = fz.newMachine()
+
+	testingpv := fz.m.Store.GetPackage("testing", false)
+	fz.testingcx = &gno.ConstExpr{TypedValue: gno.TypedValue{T: &gno.PackageType{}, V: testingpv}}
+
+	// Extract the unexported fuzzing helpers.
+	fz.m.SetActivePackage(testingpv)
+	for _, name := range []string{"fuzzStart", "fuzzTarget", "fuzzRun", "fuzzExec", "fuzzEnd"} {
+		fz.helpers[name] = fz.m.Eval(gno.Nx(name))[0]
+	}
+	fz.m.SetActivePackage(pv)
+
+	fz.f = fz.m.Eval(gno.Call(
+		fz.helper("fuzzStart"),
+		gno.Str(opts.RunFlag),
+		gno.Nx(strconv.FormatBool(opts.Verbose)),
+		gno.Nx(strconv.FormatBool(opts.FailfastFlag)),
+		gno.Str(tf.Name),
+		gno.Nx(tf.Name),
+	))[0]
+	if fz.f.V == nil {
+		// Filtered out by -run.
+		return nil
+	}
+
+	var hint string
+	if err := fz.run(fuzzing, &hint); err != nil {
+		fz.m.Eval(gno.Call(gno.Sel(fz.fx(), "Error"), gno.Str(err.Error())))
+	}
+
+	ret := fz.m.Eval(gno.Call(fz.helper("fuzzEnd"), fz.fx()))[0].GetString()
+	fmt.Fprintf(opts.Error, "--- GAS:  %d\n", fz.m.GasMeter.GasConsumed())
+	if hint != "" {
+		fmt.Fprint(opts.Error, hint)
+	}
+
+	var rep report
+	if err := json.Unmarshal([]byte(ret), &rep); err != nil {
+		fmt.Fprintf(opts.Error, "--- FAIL: %s [internal gno testing error]", tf.Name)
+		return err
+	}
+	if rep.Failed {
+		return fmt.Errorf("failed: %q", tf.Name)
+	}
+	return nil
+}
+
+// run runs the fuzz target on the corpus, and fuzzes it if requested. If a
+// failing input is found, hint is set to explain how to re-run it.
+func (fz *fuzzer) run(fuzzing bool, hint *string) error {
+	res := fz.m.Eval(gno.Call(fz.helper("fuzzTarget"), fz.fx()))
+	fz.target = res[0]
+	if fz.target.T == nil {
+		// F.Fuzz wasn't called, or the fuzz test failed or was skipped.
+		return nil
+	}
+	if err := fz.loadParams(); err != nil {
+		return err
+	}
+
+	seeds, err := fz.loadSeeds(res[1])
+	if err != nil {
+		return err
+	}
+	files, err := fz.loadCorpusFiles()
+	if err != nil {
+		return err
+	}
+
+	// Run the corpus, as sub-tests.
+	failed := false
+	for i, args := range seeds {
+		failed = fz.runSub(fmt.Sprintf("seed#%d", i), args) || failed
+	}
+	for _, file := range files {
+		failed = fz.runSub(file.name, file.args) || failed
+	}
+	if !fuzzing || failed {
+		return nil
+	}
+
+	corpus := seeds
+	for _, file := range files {
+		corpus = append(corpus, file.args)
+	}
+	args, ok := fz.fuzz(corpus)
+	if !ok {
+		return nil
+	}
+
+	args = fz.minimize(args)
+	name, err := fz.writeCorpusFile(args)
+	if err != nil {
+		return err
+	}
+	if !fz.runSub(name, args) {
+		// Flaky failure; report it anyway.
+		fz.m.Eval(gno.Call(gno.Sel(fz.fx(), "Error"), gno.Str("fuzz target failed on generated input, but passed when re-run")))
+	}
+	*hint = fmt.Sprintf("\n    Failing input written to %s\n    To re-run:\n    gno test -run=%s/%s\n",
+		filepath.Join(fuzzCorpusDir, fz.name, name), fz.name, name)
+	return nil
+}
+
+// loadParams checks the signature of the fuzz target, and loads the types of
+// its fuzzed parameters.
+func (fz *fuzzer) loadParams() error {
+	fv, ok := fz.target.V.(*gno.FuncValue)
+	if !ok {
+		return fmt.Errorf("testing: F.Fuzz must receive a function, got %s", fz.target.T.String())
+	}
+	ft := fv.GetType(fz.m.Store)
+	switch {
+	case fv.IsCrossing():
+		return errors.New("testing: fuzz target must not be a crossing function")
+	case ft.HasVarg():
+		return errors.New("testing: fuzz target must not be variadic")
+	case len(ft.Results) > 0:
+		return errors.New("testing: fuzz target must not return a value")
+	case len(ft.Params) < 2 || ft.Params[0].Type.String() != "*testing.T":
+		return errors.New("testing: fuzz target must receive a *testing.T, followed by at least one fuzzed argument")
+	}
+	for _, p := range ft.Params[1:] {
+		if !fuzzTypes[fuzzTypeName(p.Type)] {
+			return fmt.Errorf("testing: unsupported type for fuzzing %s", p.Type.String())
+		}
+		fz.params = append(fz.params, p.Type)
+	}
+	return nil
+}
+
+// loadSeeds converts the seed corpus, added with F.Add, to Go values.
+func (fz *fuzzer) loadSeeds(tv gno.TypedValue) ([][]any, error) {
+	entries := fz.sliceElems(tv)
+	seeds := make([][]any, 0, len(entries))
+	for i, entry := range entries {
+		elems := fz.sliceElems(entry)
+		if len(elems) != len(fz.params) {
+			return nil, fmt.Errorf("testing: wrong number of values in call to F.Add #%d: %d, fuzz target expects %d",
+				i, len(elems), len(fz.params))
+		}
+		args := make([]any, len(elems))
+		for j, elem := range elems {
+			if elem.T == nil || elem.T.TypeID() != fz.params[j].TypeID() {
+				return nil, fmt.Errorf("testing: mismatched types in call to F.Add #%d: argument %d, fuzz target expects %s",
+					i, j, fz.params[j].String())
+			}
+			args[j] = fz.gnoToGo(elem)
+		}
+		seeds = append(seeds, args)
+	}
+	return seeds, nil
+}
+
+func (fz *fuzzer) sliceElems(tv gno.TypedValue) []gno.TypedValue {
+	sv, ok := tv.V.(*gno.SliceValue)
+	if !ok || sv == nil {
+		return nil
+	}
+	base := sv.GetBase(fz.m.Store)
+	return base.List[sv.Offset : sv.Offset+sv.Length]
+}
+
+type corpusFile struct {
+	name string
+	args []any
+}
+
+// loadCorpusFiles loads the inputs saved in testdata/fuzz/FuzzXxx.
+func (fz *fuzzer) loadCorpusFiles() ([]corpusFile, error) {
+	dir := filepath.Join(fz.fsDir, fuzzCorpusDir, fz.name)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var files []corpusFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		args, err := unmarshalCorpusFile(b)
+		if err != nil {
+			return nil, fmt.Errorf("malformed corpus file %s: %w", entry.Name(), err)
+		}
+		if err := fz.checkArgs(args); err != nil {
+			return nil, fmt.Errorf("corpus file %s: %w", entry.Name(), err)
+		}
+		files = append(files, corpusFile{name: entry.Name(), args: args})
+	}
+	return files, nil
+}
+
+func (fz *fuzzer) checkArgs(args []any) error {
+	if len(args) != len(fz.params) {
+		return fmt.Errorf("wrong number of values: %d, fuzz target expects %d", len(args), len(fz.params))
+	}
+	for i, arg := range args {
+		if want := fuzzTypeName(fz.params[i]); goFuzzTypeName(arg) != want {
+			return fmt.Errorf("mismatched types: argument %d, fuzz target expects %s", i, want)
+		}
+	}
+	return nil
+}
+
+func (fz *fuzzer) writeCorpusFile(args []any) (string, error) {
+	b := marshalCorpusFile(args)
+	sum := sha256.Sum256(b)
+	name := hex.EncodeToString(sum[:])
+
+	dir := filepath.Join(fz.fsDir, fuzzCorpusDir, fz.name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), b, 0o644); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// call returns a function literal calling the fuzz target with args.
+func (fz *fuzzer) call(args []any) *gno.FuncLitExpr {
+	cargs := make([]any, 0, len(args)+1)
+	cargs = append(cargs, gno.Nx("t"))
+	for i, arg := range args {
+		cargs = append(cargs, gno.NewConstExpr(gno.Nx("arg"), goToGno(fz.params[i], arg)))
+	}
+	return gno.Fn(
+		gno.Flds("t", gno.Ptr(gno.Sel(fz.testingcx, "T"))),
+		nil,
+		gno.Ss(&gno.ExprStmt{
+			X: gno.Call(gno.NewConstExpr(gno.Nx("target"), fz.target), cargs...),
+		}),
+	)
+}
+
+// runSub runs the fuzz target with args as a sub-test of the fuzz test, and
+// returns whether it failed.
+func (fz *fuzzer) runSub(name string, args []any) bool {
+	res := fz.m.Eval(gno.Call(fz.helper("fuzzRun"), fz.fx(), gno.Str(name), fz.call(args)))
+	return res[0].GetBool()
+}
+
+// exec runs the fuzz target with args, discarding its output, and returns
+// whether it failed.
+func (fz *fuzzer) exec(args []any) (failed bool) {
+	revert := fz.opts.outWriter.silence()
+	defer revert()
+	defer func() {
+		if r := recover(); r != nil {
+			// The machine is left in an unknown state.
+			fz.m = fz.newMachine()
+			failed = true
+		}
+	}()
+
+	res := fz.m.Eval(gno.Call(fz.helper("fuzzExec"), fz.call(args)))
+	return res[0].GetBool()
+}
+
+// fuzz calls the fuzz target with inputs generated by mutating the corpus,
+// until it fails or the time (or iterations) given by the options elapses.
+// It returns the failing input, if any.
+func (fz *fuzzer) fuzz(corpus [][]any) ([]any, bool) {
+	if len(corpus) == 0 {
+		args := make([]any, len(fz.params))
+		for i, p := range fz.params {
+			args[i] = zeroFuzzValue(fuzzTypeName(p))
+		}
+		corpus = append(corpus, args)
+	}
+	pool := append([][]any(nil), corpus...)
+	// The corpus is always kept in the pool, with room for at least one
+	// generated input.
+	poolSize := max(fuzzMaxPool, len(corpus)+1)
+
+	var (
+		opts     = fz.opts
+		start    = time.Now()
+		deadline time.Time
+		execs    int
+	)
+	if opts.FuzzIterations == 0 {
+		d := opts.FuzzTime
+		if d <= 0 {
+			d = DefaultFuzzTime
+		}
+		deadline = start.Add(d)
+	}
+	progress := func() {
+		elapsed := time.Since(start)
+		fmt.Fprintf(opts.Error, "fuzz: elapsed: %s, execs: %d (%.0f/sec)\n",
+			elapsed.Round(time.Second), execs, float64(execs)/max(elapsed.Seconds(), 1e-9))
+	}
+	progress()
+	defer progress()
+
+	nextReport := start.Add(fuzzReportInterval)
+	for {
+		if opts.FuzzIterations > 0 && execs >= opts.FuzzIterations {
+			return nil, false
+		}
+		now := time.Now()
+		if !deadline.IsZero() && now.After(deadline) {
+			return nil, false
+		}
+		if now.After(nextReport) {
+			progress()
+			nextReport = now.Add(fuzzReportInterval)
+		}
+
+		args := mutateArgs(pool[rand.IntN(len(pool))])
+		execs++
+		if fz.exec(args) {
+			return args, true
+		}
+
+		// Keep the generated input, so that mutations can build upon it.
+		if len(pool) < poolSize {
+			pool = append(pool, args)
+		} else {
+			pool[len(corpus)+rand.IntN(poolSize-len(corpus))] = args
+		}
+	}
+}
+
+// minimize reduces a failing input, so that it is easier to understand.
+// Strings and byte slices are shortened, and integers moved towards zero,
+// as long as the fuzz target keeps failing.
+func (fz *fuzzer) minimize(args []any) []any {
+	args = append([]any(nil), args...)
+	budget := fuzzMaxMinimizeExecs
+	fails := func(i int, v any) bool {
+		if budget <= 0 {
+			return false
+		}
+		budget--
+		candidate := append([]any(nil), args...)
+		candidate[i] = v
+		if !fz.exec(candidate) {
+			return false
+		}
+		args = candidate
+		return true
+	}
+
+	for i := range args {
+		switch v := args[i].(type) {
+		case string:
+			b := minimizeBytes([]byte(v), func(b []byte) bool { return fails(i, string(b)) })
+			args[i] = string(b)
+		case []byte:
+			args[i] = minimizeBytes(v, func(b []byte) bool { return fails(i, b) })
+		case bool, float32, float64:
+		default:
+			for {
+				n := toInt64(args[i])
+				if n == 0 || !(fails(i, withInt(args[i], 0)) || fails(i, withInt(args[i], n/2))) {
+					break
+				}
+			}
+		}
+	}
+	return args
+}
+
+// minimizeBytes removes chunks of decreasing size from b, as long as fails
+// returns true.
+func minimizeBytes(b []byte, fails func([]byte) bool) []byte {
+	for size := len(b) / 2; size >= 1; size /= 2 {
+		for i := 0; i+size <= len(b); {
+			candidate := append(append([]byte(nil), b[:i]...), b[i+size:]...)
+			if fails(candidate) {
+				b = candidate
+			} else {
+				i += size
+			}
+		}
+	}
+	return b
+}
+
+// ---------------------------------------------------------------------------
+// Conversion between Go and Gno values.
+
+// fuzzTypeName returns the Go name of the type t, if it is supported.
+func fuzzTypeName(t gno.Type) string {
+	switch t := t.(type) {
+	case gno.PrimitiveType:
+		return t.String()
+	case *gno.SliceType:
+		if t.Elt.Kind() == gno.Uint8Kind && !t.Vrd {
+			return "[]byte"
+		}
+	}
+	return ""
+}
+
+func goFuzzTypeName(v any) string {
+	switch v.(type) {
+	case []byte:
+		return "[]byte"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func zeroFuzzValue(typ string) any {
+	switch typ {
+	case "string":
+		return ""
+	case "[]byte":
+		return []byte{}
+	case "bool":
+		return false
+	case "float32":
+		return float32(0)
+	case "float64":
+		return float64(0)
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		return withUint(typ, 0)
+	default:
+		return withInt(typ, 0)
+	}
+}
+
+func (fz *fuzzer) gnoToGo(tv gno.TypedValue) any {
+	switch tv.T.Kind() {
+	case gno.StringKind:
+		return tv.GetString()
+	case gno.SliceKind:
+		b := []byte{}
+		sv, ok := tv.V.(*gno.SliceValue)
+		if !ok || sv == nil {
+			return b
+		}
+		base := sv.GetBase(fz.m.Store)
+		if base.Data != nil {
+			return append(b, base.Data[sv.Offset:sv.Offset+sv.Length]...)
+		}
+		for _, elem := range base.List[sv.Offset : sv.Offset+sv.Length] {
+			b = append(b, elem.GetUint8())
+		}
+		return b
+	case gno.BoolKind:
+		return tv.GetBool()
+	case gno.IntKind:
+		return int(tv.GetInt())
+	case gno.Int8Kind:
+		return tv.GetInt8()
+	case gno.Int16Kind:
+		return tv.GetInt16()
+	case gno.Int32Kind:
+		return tv.GetInt32()
+	case gno.Int64Kind:
+		return tv.GetInt64()
+	case gno.UintKind:
+		return uint(tv.GetUint())
+	case gno.Uint8Kind:
+		return tv.GetUint8()
+	case gno.Uint16Kind:
+		return tv.GetUint16()
+	case gno.Uint32Kind:
+		return tv.GetUint32()
+	case gno.Uint64Kind:
+		return tv.GetUint64()
+	case gno.Float32Kind:
+		return math.Float32frombits(tv.GetFloat32())
+	case gno.Float64Kind:
+		return math.Float64frombits(tv.GetFloat64())
+	default:
+		panic(fmt.Sprintf("unexpected fuzz type %s", tv.T.String()))
+	}
+}
+
+func goToGno(t gno.Type, v any) gno.TypedValue {
+	tv := gno.TypedValue{T: t}
+	switch v := v.(type) {
+	case string:
+		tv.SetString(gno.StringValue(v))
+	case []byte:
+		data := append(make([]byte, 0, len(v)), v...)
+		tv.V = (*gno.Allocator)(nil).NewSliceFromData(data)
+	case bool:
+		tv.SetBool(v)
+	case int:
+		tv.SetInt(int64(v))
+	case int8:
+		tv.SetInt8(v)
+	case int16:
+		tv.SetInt16(v)
+	case int32:
+		tv.SetInt32(v)
+	case int64:
+		tv.SetInt64(v)
+	case uint:
+		tv.SetUint(uint64(v))
+	case uint8:
+		tv.SetUint8(v)
+	case uint16:
+		tv.SetUint16(v)
+	case uint32:
+		tv.SetUint32(v)
+	case uint64:
+		tv.SetUint64(v)
+	case float32:
+		tv.SetFloat32(math.Float32bits(v))
+	case float64:
+		tv.SetFloat64(math.Float64bits(v))
+	default:
+		panic(fmt.Sprintf("unexpected fuzz value %T", v))
+	}
+	return tv
+}
+
+// ---------------------------------------------------------------------------
+// Mutations.
+
+var (
+	interestingInts = []int64{
+		0, 1, -1, 2, 16, 32, 64, 100, 127, -128, 128, 255, 256,
+		1024, 4096, 32767, -32768, 65535, 65536,
+		math.MaxInt32, math.MinInt32, math.MaxInt64, math.MinInt64,
+	}
+	interestingFloats = []float64{
+		0, 1, -1, 0.5, math.SmallestNonzeroFloat64, math.MaxFloat64,
+		math.Inf(1), math.Inf(-1), math.NaN(),
+	}
+)
+
+// mutateArgs returns a copy of args, where one to five random mutations
+// were applied.
+func mutateArgs(args []any) []any {
+	out := append([]any(nil), args...)
+	for n := 1 + rand.IntN(5); n > 0; n-- {
+		i := rand.IntN(len(out))
+		out[i] = mutateValue(out[i])
+	}
+	return out
+}
+
+func mutateValue(v any) any {
+	switch v := v.(type) {
+	case string:
+		return string(mutateBytes([]byte(v)))
+	case []byte:
+		return mutateBytes(v)
+	case bool:
+		return !v
+	case float32:
+		return float32(mutateFloat(float64(v)))
+	case float64:
+		return mutateFloat(v)
+	default:
+		return withInt(v, mutateInt(toInt64(v)))
+	}
+}
+
+func mutateBytes(b []byte) []byte {
+	b = append([]byte(nil), b...)
+	op := rand.IntN(6)
+	if len(b) == 0 {
+		op = 0
+	}
+	switch op {
+	case 0: // insert random bytes
+		if len(b) >= fuzzMaxBytes {
+			return b
+		}
+		pos := rand.IntN(len(b) + 1)
+		ins := make([]byte, 1+rand.IntN(4))
+		for i := range ins {
+			ins[i] = randomByte()
+		}
+		return append(b[:pos], append(ins, b[pos:]...)...)
+	case 1: // remove a range
+		pos := rand.IntN(len(b))
+		n := 1 + rand.IntN(len(b)-pos)
+		return append(b[:pos], b[pos+n:]...)
+	case 2: // replace a byte
+		b[rand.IntN(len(b))] = randomByte()
+	case 3: // flip a bit
+		b[rand.IntN(len(b))] ^= 1 << rand.IntN(8)
+	case 4: // duplicate a range
+		if len(b) >= fuzzMaxBytes {
+			return b
+		}
+		pos := rand.IntN(len(b))
+		n := 1 + rand.IntN(min(len(b)-pos, 16))
+		dup := append([]byte(nil), b[pos:pos+n]...)
+		to := rand.IntN(len(b) + 1)
+		return append(b[:to], append(dup, b[to:]...)...)
+	case 5: // swap two bytes
+		i, j := rand.IntN(len(b)), rand.IntN(len(b))
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
+
+// randomByte returns a random byte, biased towards printable ASCII.
+func randomByte() byte {
+	if rand.IntN(4) == 0 {
+		return byte(rand.IntN(256))
+	}
+	return byte(' ' + rand.IntN('~'-' '+1))
+}
+
+func mutateInt(n int64) int64 {
+	switch rand.IntN(3) {
+	case 0:
+		return n + int64(rand.IntN(33)) - 16
+	case 1:
+		return interestingInts[rand.IntN(len(interestingInts))]
+	default:
+		return n ^ (1 << rand.IntN(64))
+	}
+}
+
+func mutateFloat(f float64) float64 {
+	switch rand.IntN(3) {
+	case 0:
+		return f + float64(rand.IntN(33)-16)
+	case 1:
+		return f * (rand.Float64()*4 - 2)
+	default:
+		return interestingFloats[rand.IntN(len(interestingFloats))]
+	}
+}
+
+// toInt64 returns the integer v as an int64; unsigned integers are
+// converted bitwise.
+func toInt64(v any) int64 {
+	switch v := v.(type) {
+	case int:
+		return int64(v)
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case uint:
+		return int64(v)
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case uint64:
+		return int64(v)
+	default:
+		panic(fmt.Sprintf("unexpected integer %T", v))
+	}
+}
+
+// withInt returns n converted (with truncation) to the integer type of v,
+// or to the integer type named v if it's a string.
+func withInt(v any, n int64) any {
+	typ, ok := v.(string)
+	if !ok {
+		typ = goFuzzTypeName(v)
+	}
+	switch typ {
+	case "int":
+		return int(n)
+	case "int8":
+		return int8(n)
+	case "int16":
+		return int16(n)
+	case "int32":
+		return int32(n)
+	case "int64":
+		return n
+	default:
+		return withUint(typ, uint64(n))
+	}
+}
+
+func withUint(typ string, n uint64) any {
+	switch typ {
+	case "uint":
+		return uint(n)
+	case "uint8":
+		return uint8(n)
+	case "uint16":
+		return uint16(n)
+	case "uint32":
+		return uint32(n)
+	case "uint64":
+		return n
+	default:
+		panic(fmt.Sprintf("unexpected integer type %s", typ))
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Corpus files.
+
+// marshalCorpusFile encodes args in the format used by Go for the files in
+// testdata/fuzz.
+func marshalCorpusFile(args []any) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(fuzzCorpusHeader + "\n")
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case string:
+			fmt.Fprintf(&buf, "string(%s)\n", strconv.Quote(v))
+		case []byte:
+			fmt.Fprintf(&buf, "[]byte(%s)\n", strconv.Quote(string(v)))
+		case float32:
+			f := float64(v)
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				fmt.Fprintf(&buf, "math.Float32frombits(0x%x)\n", math.Float32bits(v))
+			} else {
+				fmt.Fprintf(&buf, "float32(%s)\n", strconv.FormatFloat(f, 'g', -1, 32))
+			}
+		case float64:
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				fmt.Fprintf(&buf, "math.Float64frombits(0x%x)\n", math.Float64bits(v))
+			} else {
+				fmt.Fprintf(&buf, "float64(%s)\n", strconv.FormatFloat(v, 'g', -1, 64))
+			}
+		default:
+			fmt.Fprintf(&buf, "%s(%v)\n", goFuzzTypeName(v), v)
+		}
+	}
+	return buf.Bytes()
+}
+
+// unmarshalCorpusFile decodes a file written by marshalCorpusFile, or by Go.
+func unmarshalCorpusFile(b []byte) ([]any, error) {
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != fuzzCorpusHeader {
+		return nil, errors.New("missing version header")
+	}
+
+	var args []any
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		arg, err := parseCorpusValue(line)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", line, err)
+		}
+		args = append(args, arg)
+	}
+	if len(args) == 0 {
+		return nil, errors.New("no values")
+	}
+	return args, nil
+}
+
+func parseCorpusValue(line string) (any, error) {
+	x, err := parser.ParseExpr(line)
+	if err != nil {
+		return nil, err
+	}
+	call, ok := x.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil, errors.New("expected a call with a single argument")
+	}
+
+	var typ string
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		typ = fn.Name
+	case *ast.ArrayType:
+		if id, ok := fn.Elt.(*ast.Ident); ok && fn.Len == nil && (id.Name == "byte" || id.Name == "uint8") {
+			typ = "[]byte"
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := fn.X.(*ast.Ident); ok && pkg.Name == "math" {
+			typ = "math." + fn.Sel.Name
+		}
+	}
+	switch typ {
+	case "byte":
+		typ = "uint8"
+	case "rune":
+		typ = "int32"
+	}
+
+	if id, ok := call.Args[0].(*ast.Ident); ok {
+		if typ != "bool" || (id.Name != "true" && id.Name != "false") {
+			return nil, fmt.Errorf("unexpected identifier %s", id.Name)
+		}
+		return id.Name == "true", nil
+	}
+
+	lit, neg := call.Args[0], false
+	if un, ok := lit.(*ast.UnaryExpr); ok && un.Op == token.SUB {
+		lit, neg = un.X, true
+	}
+	bl, ok := lit.(*ast.BasicLit)
+	if !ok {
+		return nil, errors.New("expected a literal")
+	}
+	val := bl.Value
+	if neg {
+		val = "-" + val
+	}
+
+	switch typ {
+	case "string", "[]byte":
+		if bl.Kind != token.STRING || neg {
+			return nil, errors.New("expected a string literal")
+		}
+		s, err := strconv.Unquote(bl.Value)
+		if err != nil {
+			return nil, err
+		}
+		if typ == "string" {
+			return s, nil
+		}
+		return []byte(s), nil
+	case "float32", "float64":
+		bits := 64
+		if typ == "float32" {
+			bits = 32
+		}
+		f, err := strconv.ParseFloat(val, bits)
+		if err != nil {
+			return nil, err
+		}
+		if bits == 32 {
+			return float32(f), nil
+		}
+		return f, nil
+	case "math.Float32frombits":
+		n, err := strconv.ParseUint(val, 0, 32)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float32frombits(uint32(n)), nil
+	case "math.Float64frombits":
+		n, err := strconv.ParseUint(val, 0, 64)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(n), nil
+	case "int", "int8", "int16", "int32", "int64":
+		n, err := parseCorpusInt(bl, val, neg, func(s string) (int64, error) {
+			return strconv.ParseInt(s, 0, intBits(typ))
+		})
+		if err != nil {
+			return nil, err
+		}
+		return withInt(typ, n), nil
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		if neg {
+			return nil, errors.New("negative unsigned integer")
+		}
+		n, err := parseCorpusInt(bl, val, neg, func(s string) (int64, error) {
+			n, err := strconv.ParseUint(s, 0, intBits(typ))
+			return int64(n), err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return withUint(typ, uint64(n)), nil
+	default:
+		return nil, fmt.Errorf("unsupported type %q", typ)
+	}
+}
+
+// parseCorpusInt parses an integer literal, which can also be a character
+// literal such as 'a'.
+func parseCorpusInt(bl *ast.BasicLit, val string, neg bool, parse func(string) (int64, error)) (int64, error) {
+	if bl.Kind != token.CHAR {
+		return parse(val)
+	}
+	s, err := strconv.Unquote(bl.Value)
+	if err != nil {
+		return 0, err
+	}
+	r, _ := utf8.DecodeRuneInString(s)
+	if neg {
+		return parse("-" + strconv.Itoa(int(r)))
+	}
+	return parse(strconv.Itoa(int(r)))
+}
+
+func intBits(typ string) int {
+	switch strings.TrimPrefix(strings.TrimPrefix(typ, "u"), "int") {
+	case "8":
+		return 8
+	case "16":
+		return 16
+	case "32":
+		return 32
+	default:
+		return 64
+	}
+}
+
+// silence discards the output of the machine, until revert is called.
+func (p *proxyWriter) silence() (revert func()) {
+	w, errW := p.w, p.errW
+	p.w, p.errW = io.Discard, io.Discard
+	return func() {
+		p.w, p.errW = w, errW
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime/debug"
 	"strconv"
 	"strings"
@@ -101,20 +102,36 @@ func (o outputWithError) StderrWrite(p []byte) (int, error) { return o.errW.Writ
 // ----------------------------------------
 // testParams
 
-type testParams struct{}
+// testParams is an in-memory ParamsInterface; values set during a test can be
+// read back within the same execution context.
+type testParams struct {
+	values map[string]any
+}
 
 func newTestParams() *testParams {
-	return &testParams{}
+	return &testParams{values: map[string]any{}}
 }
 
-func (tp *testParams) SetBool(key string, val bool)                     { /* noop */ }
-func (tp *testParams) SetBytes(key string, val []byte)                  { /* noop */ }
-func (tp *testParams) SetInt64(key string, val int64)                   { /* noop */ }
-func (tp *testParams) SetUint64(key string, val uint64)                 { /* noop */ }
-func (tp *testParams) SetString(key string, val string)                 { /* noop */ }
-func (tp *testParams) SetStrings(key string, val []string)              { /* noop */ }
+func (tp *testParams) SetBool(key string, val bool)                     { tp.values[key] = val }
+func (tp *testParams) SetBytes(key string, val []byte)                  { tp.values[key] = val }
+func (tp *testParams) SetInt64(key string, val int64)                   { tp.values[key] = val }
+func (tp *testParams) SetUint64(key string, val uint64)                 { tp.values[key] = val }
+func (tp *testParams) SetString(key string, val string)                 { tp.values[key] = val }
+func (tp *testParams) SetStrings(key string, val []string)              { tp.values[key] = val }
 func (tp *testParams) UpdateStrings(key string, val []string, add bool) { /* noop */ }
 
+func (tp *testParams) GetBool(key string) (bool, bool)        { return getTestParam[bool](tp, key) }
+func (tp *testParams) GetBytes(key string) ([]byte, bool)     { return getTestParam[[]byte](tp, key) }
+func (tp *testParams) GetInt64(key string) (int64, bool)      { return getTestParam[int64](tp, key) }
+func (tp *testParams) GetUint64(key string) (uint64, bool)    { return getTestParam[uint64](tp, key) }
+func (tp *testParams) GetString(key string) (string, bool)    { return getTestParam[string](tp, key) }
+func (tp *testParams) GetStrings(key string) ([]string, bool) { return getTestParam[[]string](tp, key) }
+
+func getTestParam[T any](tp *testParams, key string) (T, bool) {
+	v, ok := tp.values[key].(T)
+	return v, ok
+}
+
 // ----------------------------------------
 // main test function
 
@@ -131,6 +148,9 @@ type TestOptions struct {
 	Error io.Writer
 	// Debug enables the interactive debugger on gno tests.
 	Debug bool
+	// DebugDAP, if set with Debug, drives the debugger from a DAP client
+	// instead of stdin and stdout.
+	DebugDAP *gno.DAPSession
 
 	// Not set by NewTestOptions:
 
@@ -147,6 +167,18 @@ type TestOptions struct {
 	Metrics bool
 	// Uses Error to print the events emitted.
 	Events bool
+	// If set, records the statements of the tested packages executed by
+	// the tests.
+	Coverage *gno.Coverage
+	// If set, records the gas consumed by the tests.
+	GasProfile *gno.GasProfile
+	// Regular expression selecting the fuzz test to fuzz. Fuzz tests which
+	// are not selected only run their seed corpus.
+	FuzzFlag string
+	// Time spent fuzzing; defaults to [DefaultFuzzTime].
+	FuzzTime time.Duration
+	// If set, the number of generated inputs to run instead of FuzzTime.
+	FuzzIterations int
 
 	filetestBuffer bytes.Buffer
 	outWriter      proxyWriter
@@ -159,6 +191,39 @@ func (opts *TestOptions) WriterForStore() io.Writer {
 	return &opts.outWriter
 }
 
+// attachGasProfile makes m record its gas consumption in opts.GasProfile, if
+// set.
+func (opts *TestOptions) attachGasProfile(m *gno.Machine) {
+	if opts.GasProfile != nil {
+		opts.GasProfile.Attach(m)
+	}
+}
+
+// enableDebugger activates the debugger of m, if requested.
+func (opts *TestOptions) enableDebugger(m *gno.Machine) {
+	if !opts.Debug {
+		return
+	}
+	fileContent := func(ppath, name string) string {
+		p := filepath.Join(opts.RootDir, ppath, name)
+		b, err := os.ReadFile(p)
+		if err != nil {
+			p = filepath.Join(opts.RootDir, "gnovm", "stdlibs", ppath, name)
+			b, err = os.ReadFile(p)
+		}
+		if err != nil {
+			p = filepath.Join(opts.RootDir, "examples", ppath, name)
+			b, _ = os.ReadFile(p)
+		}
+		return string(b)
+	}
+	if opts.DebugDAP != nil {
+		m.Debugger.EnableDAP(opts.DebugDAP, fileContent)
+		return
+	}
+	m.Debugger.Enable(os.Stdin, os.Stdout, fileContent)
+}
+
 // NewTestOptions sets up TestOptions, filling out all "required" parameters.
 func NewTestOptions(rootDir string, stdout, stderr io.Writer, pkgs packages.PkgList) *TestOptions {
 	opts := &TestOptions{
@@ -232,7 +297,12 @@ func Test(mpkg *std.MemPackage, fsDir string, opts *TestOptions) error {
 	// `pkg_test` tests. This allows us to "export" symbols from the pkg
 	// tests and import them from the `pkg_test` tests.
 	tcw := opts.BaseStore.CacheWrap()
-	tgs := opts.TestStore.BeginTransaction(tcw, tcw, nil)
+	var storeGasMeter store.GasMeter
+	if opts.GasProfile != nil {
+		// Only used to record store accesses in the profile.
+		storeGasMeter = opts.GasProfile.GasMeter(nil)
+	}
+	tgs := opts.TestStore.BeginTransaction(tcw, tcw, storeGasMeter)
 
 	// Let opts.TestStore load itself.
 	// This needs to happen before LoadImports, as LoadImports will
@@ -249,7 +319,18 @@ func Test(mpkg *std.MemPackage, fsDir string, opts *TestOptions) error {
 		// new packages by default, which we don't want.  Instead we
 		// will run the mempackage ourselves in the next line.
 		SkipPackage: true,
+		Coverage:    opts.Coverage,
+		GasProfile:  opts.GasProfile,
 	})
+	// Register the statements of the package's non-test files.
+	if opts.Coverage != nil {
+		pmpkg := gno.MPFProd.FilterMemPackage(mpkg)
+		if !pmpkg.IsEmptyOf(".gno") {
+			for _, fn := range m2.ParseMemPackage(pmpkg).Files {
+				opts.Coverage.AddFile(mpkg.Path, fn)
+			}
+		}
+	}
 	// Filter out xxx_test *_test.gno and *_filetest.gno and run.
 	// If testing with only filetests, there will be no files.
 	tmpkg := gno.MPFTest.FilterMemPackage(mpkg)
@@ -267,12 +348,15 @@ func Test(mpkg *std.MemPackage, fsDir string, opts *TestOptions) error {
 	// "integration test" are the test files with `package xxx_test` (they are
 	// not necessarily integration tests, it's just for our internal reference.)
 	tset, itset, itfiles, ftfiles := parseMemPackageTests(mpkg)
+	if err := opts.checkFuzzFlag(tset, itset); err != nil {
+		return err
+	}
 
 	// Testing with *_test.gno
 	if len(tset.Files)+len(itset.Files) > 0 {
 		// Run test files in pkg.
 		if len(tset.Files) > 0 {
-			err := opts.runTestFiles(mpkg, tset, tgs)
+			err := opts.runTestFiles(mpkg, fsDir, tset, tgs)
 			if err != nil {
 				errs = multierr.Append(errs, err)
 			}
@@ -293,7 +377,7 @@ func Test(mpkg *std.MemPackage, fsDir string, opts *TestOptions) error {
 				Files: itfiles,
 			}
 
-			err := opts.runTestFiles(itmpkg, itset, tgs)
+			err := opts.runTestFiles(itmpkg, fsDir, itset, tgs)
 			if err != nil {
 				errs = multierr.Append(errs, err)
 			}
@@ -352,6 +436,7 @@ func Test(mpkg *std.MemPackage, fsDir string, opts *TestOptions) error {
 // which runs *_filetest.go tests.
 func (opts *TestOptions) runTestFiles(
 	mpkg *std.MemPackage,
+	fsDir string,
 	files *gno.FileSet,
 	tgs gno.TransactionStore,
 ) (errs error) {
@@ -370,6 +455,7 @@ func (opts *TestOptions) runTestFiles(
 	}()
 
 	tests := loadTestFuncs(mpkg.Name, files)
+	fuzzTests := loadFuzzFuncs(mpkg.Name, files)
 
 	var alloc *gno.Allocator
 	if opts.Metrics {
@@ -379,8 +465,10 @@ func (opts *TestOptions) runTestFiles(
 	opts.TestStore.SetLogStoreOps(nil)
 
 	// Check if we already have the package - it may have been eagerly loaded.
-	m = Machine(tgs, opts.WriterForStore(), mpkg.Path, opts.Debug, nil)
+	m = Machine(tgs, opts.WriterForStore(), mpkg.Path, false, nil)
 	m.Alloc = alloc
+	m.Coverage = opts.Coverage
+	opts.attachGasProfile(m)
 	if tgs.GetMemPackage(mpkg.Path) == nil {
 		m.RunMemPackage(mpkg, false)
 	} else {
@@ -400,8 +488,10 @@ func (opts *TestOptions) runTestFiles(
 		// - Run the test files before this for loop (but persist it to store;
 		//   RunFiles doesn't do that currently)
 		// - Wrap here.
-		m = Machine(tgs, opts.WriterForStore(), mpkg.Path, opts.Debug, store.NewInfiniteGasMeter())
+		m = Machine(tgs, opts.WriterForStore(), mpkg.Path, false, store.NewInfiniteGasMeter())
 		m.Alloc = alloc.Reset()
+		m.Coverage = opts.Coverage
+		opts.attachGasProfile(m)
 		m.SetActivePackage(pv)
 
 		testingpv := m.Store.GetPackage("testing", false)
@@ -448,22 +538,7 @@ func (opts *TestOptions) runTestFiles(
 		}
 		runTestCX := gno.NewConstExpr(runTestX, runTest)
 
-		if opts.Debug {
-			fileContent := func(ppath, name string) string {
-				p := filepath.Join(opts.RootDir, ppath, name)
-				b, err := os.ReadFile(p)
-				if err != nil {
-					p = filepath.Join(opts.RootDir, "gnovm", "stdlibs", ppath, name)
-					b, err = os.ReadFile(p)
-				}
-				if err != nil {
-					p = filepath.Join(opts.RootDir, "examples", ppath, name)
-					b, _ = os.ReadFile(p)
-				}
-				return string(b)
-			}
-			m.Debugger.Enable(os.Stdin, os.Stdout, fileContent)
-		}
+		opts.enableDebugger(m)
 
 		eval := m.Eval(gno.Call(
 			runTestCX,                                     // Call testing.RunTest
@@ -537,6 +612,22 @@ func (opts *TestOptions) runTestFiles(
 		}
 	}
 
+	var fuzzRe *regexp.Regexp
+	if opts.FuzzFlag != "" {
+		// Already validated by checkFuzzFlag.
+		fuzzRe = regexp.MustCompile(opts.FuzzFlag)
+	}
+	for _, tf := range fuzzTests {
+		fuzzing := fuzzRe != nil && fuzzRe.MatchString(tf.Name)
+		err := opts.runFuzzTest(mpkg, pv, fsDir, tgs, tf, fuzzing)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			if opts.FailfastFlag {
+				return errs
+			}
+		}
+	}
+
 	return errs
 }
 
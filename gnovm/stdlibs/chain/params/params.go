@@ -47,6 +47,36 @@ func UpdateParamStrings(m *gno.Machine, key string, val []string, add bool) {
 	execctx.GetContext(m).Params.UpdateStrings(pk, val, add)
 }
 
+func GetString(m *gno.Machine, key string) (string, bool) {
+	pk := pkey(m, key)
+	return execctx.GetContext(m).Params.GetString(pk)
+}
+
+func GetBool(m *gno.Machine, key string) (bool, bool) {
+	pk := pkey(m, key)
+	return execctx.GetContext(m).Params.GetBool(pk)
+}
+
+func GetInt64(m *gno.Machine, key string) (int64, bool) {
+	pk := pkey(m, key)
+	return execctx.GetContext(m).Params.GetInt64(pk)
+}
+
+func GetUint64(m *gno.Machine, key string) (uint64, bool) {
+	pk := pkey(m, key)
+	return execctx.GetContext(m).Params.GetUint64(pk)
+}
+
+func GetBytes(m *gno.Machine, key string) ([]byte, bool) {
+	pk := pkey(m, key)
+	return execctx.GetContext(m).Params.GetBytes(pk)
+}
+
+func GetStrings(m *gno.Machine, key string) ([]string, bool) {
+	pk := pkey(m, key)
+	return execctx.GetContext(m).Params.GetStrings(pk)
+}
+
 // NOTE: further validation must happen by implementor of ParamsInterface.
 func pkey(m *gno.Machine, key string) string {
 	if len(key) == 0 {
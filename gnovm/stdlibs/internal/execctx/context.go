@@ -23,6 +23,13 @@ type ParamsInterface interface {
 	SetBytes(key string, val []byte)
 	SetStrings(key string, val []string)
 	UpdateStrings(key string, val []string, add bool)
+
+	GetString(key string) (string, bool)
+	GetBool(key string) (bool, bool)
+	GetInt64(key string) (int64, bool)
+	GetUint64(key string) (uint64, bool)
+	GetBytes(key string) ([]byte, bool)
+	GetStrings(key string) ([]string, bool)
 }
 
 type ExecContext struct {
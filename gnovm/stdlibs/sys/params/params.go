@@ -49,6 +49,36 @@ func X_updateSysParamStrings(m *gno.Machine, module, submodule, name string, val
 	execctx.GetContext(m).Params.UpdateStrings(pk, val, add)
 }
 
+func X_getSysParamString(m *gno.Machine, module, submodule, name string) (string, bool) {
+	pk := prmkey(module, submodule, name)
+	return execctx.GetContext(m).Params.GetString(pk)
+}
+
+func X_getSysParamBool(m *gno.Machine, module, submodule, name string) (bool, bool) {
+	pk := prmkey(module, submodule, name)
+	return execctx.GetContext(m).Params.GetBool(pk)
+}
+
+func X_getSysParamInt64(m *gno.Machine, module, submodule, name string) (int64, bool) {
+	pk := prmkey(module, submodule, name)
+	return execctx.GetContext(m).Params.GetInt64(pk)
+}
+
+func X_getSysParamUint64(m *gno.Machine, module, submodule, name string) (uint64, bool) {
+	pk := prmkey(module, submodule, name)
+	return execctx.GetContext(m).Params.GetUint64(pk)
+}
+
+func X_getSysParamBytes(m *gno.Machine, module, submodule, name string) ([]byte, bool) {
+	pk := prmkey(module, submodule, name)
+	return execctx.GetContext(m).Params.GetBytes(pk)
+}
+
+func X_getSysParamStrings(m *gno.Machine, module, submodule, name string) ([]string, bool) {
+	pk := prmkey(module, submodule, name)
+	return execctx.GetContext(m).Params.GetStrings(pk)
+}
+
 func assertSysParamsRealm(m *gno.Machine) {
 	// XXX improve
 	if len(m.Frames) < 2 {
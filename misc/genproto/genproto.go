@@ -17,13 +17,16 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/bft/consensus"
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/consensus/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/mempool"
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync"
 	btypes "github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/bitarray"
 	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
 	"github.com/gnolang/gno/tm2/pkg/crypto/hd"
 	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
 	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
+	"github.com/gnolang/gno/tm2/pkg/crypto/secp256r1"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
 	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
 	"github.com/gnolang/gno/tm2/pkg/std"
 )
@@ -49,12 +52,15 @@ func execGen(_ context.Context, _ []string) error {
 		consensus.Package,
 		ctypes.Package,
 		mempool.Package,
+		statesync.Package,
 		ed25519.Package,
+		secp256r1.Package,
 		blockchain.Package,
 		hd.Package,
 		multisig.Package,
 		std.Package,
 		sdk.Package,
+		auth.Package,
 		bank.Package,
 		vm.Package,
 		gno.Package,
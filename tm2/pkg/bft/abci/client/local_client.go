@@ -213,6 +213,38 @@ func (app *localClient) EndBlockSync(req abci.RequestEndBlock) (abci.ResponseEnd
 	return res, nil
 }
 
+func (app *localClient) ListSnapshotsSync(req abci.RequestListSnapshots) (abci.ResponseListSnapshots, error) {
+	app.mtx.Lock()
+	defer app.mtx.Unlock()
+
+	res := app.Application.ListSnapshots(req)
+	return res, nil
+}
+
+func (app *localClient) OfferSnapshotSync(req abci.RequestOfferSnapshot) (abci.ResponseOfferSnapshot, error) {
+	app.mtx.Lock()
+	defer app.mtx.Unlock()
+
+	res := app.Application.OfferSnapshot(req)
+	return res, nil
+}
+
+func (app *localClient) LoadSnapshotChunkSync(req abci.RequestLoadSnapshotChunk) (abci.ResponseLoadSnapshotChunk, error) {
+	app.mtx.Lock()
+	defer app.mtx.Unlock()
+
+	res := app.Application.LoadSnapshotChunk(req)
+	return res, nil
+}
+
+func (app *localClient) ApplySnapshotChunkSync(req abci.RequestApplySnapshotChunk) (abci.ResponseApplySnapshotChunk, error) {
+	app.mtx.Lock()
+	defer app.mtx.Unlock()
+
+	res := app.Application.ApplySnapshotChunk(req)
+	return res, nil
+}
+
 //-------------------------------------------------------
 
 func (app *localClient) completeRequest(req abci.Request, res abci.Response) *ReqRes {
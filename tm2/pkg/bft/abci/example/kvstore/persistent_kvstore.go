@@ -89,6 +89,22 @@ func (app *PersistentKVStoreApplication) Commit() abci.ResponseCommit {
 	return app.app.Commit()
 }
 
+func (app *PersistentKVStoreApplication) ListSnapshots(req abci.RequestListSnapshots) abci.ResponseListSnapshots {
+	return app.app.ListSnapshots(req)
+}
+
+func (app *PersistentKVStoreApplication) OfferSnapshot(req abci.RequestOfferSnapshot) abci.ResponseOfferSnapshot {
+	return app.app.OfferSnapshot(req)
+}
+
+func (app *PersistentKVStoreApplication) LoadSnapshotChunk(req abci.RequestLoadSnapshotChunk) abci.ResponseLoadSnapshotChunk {
+	return app.app.LoadSnapshotChunk(req)
+}
+
+func (app *PersistentKVStoreApplication) ApplySnapshotChunk(req abci.RequestApplySnapshotChunk) abci.ResponseApplySnapshotChunk {
+	return app.app.ApplySnapshotChunk(req)
+}
+
 // When path=/val and data={validator address}, returns the validator update (abci.ValidatorUpdate) varint encoded.
 // For any other path, returns an associated value or nil if missing.
 func (app *PersistentKVStoreApplication) Query(reqQuery abci.RequestQuery) (resQuery abci.ResponseQuery) {
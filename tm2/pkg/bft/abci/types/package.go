@@ -37,6 +37,10 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 		RequestDeliverTx{},
 		RequestEndBlock{},
 		RequestCommit{},
+		RequestListSnapshots{},
+		RequestOfferSnapshot{},
+		RequestLoadSnapshotChunk{},
+		RequestApplySnapshotChunk{},
 
 		// response types
 		ResponseBase{},
@@ -52,6 +56,10 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 		ResponseDeliverTx{},
 		ResponseEndBlock{},
 		ResponseCommit{},
+		ResponseListSnapshots{},
+		ResponseOfferSnapshot{},
+		ResponseLoadSnapshotChunk{},
+		ResponseApplySnapshotChunk{},
 
 		// error types
 		StringError(""),
@@ -61,6 +69,7 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 		BlockParams{},
 		ValidatorParams{},
 		ValidatorUpdate{},
+		Snapshot{},
 		LastCommitInfo{},
 		VoteInfo{},
 		// Validator{},
@@ -91,6 +91,34 @@ type RequestCommit struct {
 	RequestBase
 }
 
+type RequestListSnapshots struct {
+	RequestBase
+}
+
+// RequestOfferSnapshot offers a snapshot to the application, to restore its
+// state. AppHash is the trusted app hash after the snapshot height.
+type RequestOfferSnapshot struct {
+	RequestBase
+	Snapshot *Snapshot
+	AppHash  []byte
+}
+
+type RequestLoadSnapshotChunk struct {
+	RequestBase
+	Height int64
+	Format uint32
+	Chunk  uint32
+}
+
+// RequestApplySnapshotChunk applies a chunk of the offered snapshot. The
+// chunks are applied in order; Sender is the ID of the peer which sent it.
+type RequestApplySnapshotChunk struct {
+	RequestBase
+	Index  uint32
+	Chunk  []byte
+	Sender string
+}
+
 // ----------------------------------------
 // Response types
 
@@ -178,6 +206,14 @@ type ResponseCheckTx struct {
 	ResponseBase
 	GasWanted int64 // nondeterministic
 	GasUsed   int64
+
+	// Priority orders the tx in the mempool, higher first (nondeterministic).
+	Priority int64
+	// Sender and Sequence identify the tx for the mempool to replace it by
+	// another tx with the same sender and sequence. Empty Sender disables
+	// replacement.
+	Sender   string
+	Sequence uint64
 }
 
 type ResponseDeliverTx struct {
@@ -197,6 +233,30 @@ type ResponseCommit struct {
 	ResponseBase
 }
 
+type ResponseListSnapshots struct {
+	ResponseBase
+	Snapshots []*Snapshot
+}
+
+// ResponseOfferSnapshot accepts the offered snapshot, unless it has an Error.
+type ResponseOfferSnapshot struct {
+	ResponseBase
+}
+
+type ResponseLoadSnapshotChunk struct {
+	ResponseBase
+	Chunk []byte
+}
+
+// ResponseApplySnapshotChunk lists the chunks to fetch again, and the peers to
+// not fetch chunks from anymore, when the applied chunk was rejected.
+// An Error aborts the restoration of the snapshot.
+type ResponseApplySnapshotChunk struct {
+	ResponseBase
+	RefetchChunks []uint32
+	RejectSenders []string
+}
+
 // ----------------------------------------
 // Interface types
 
@@ -259,6 +319,17 @@ type ValidatorParams struct {
 	PubKeyTypeURLs []string
 }
 
+// Snapshot is a snapshot of the state of the application, made of Chunks
+// chunks, used by state sync to bootstrap new nodes. Format and Metadata are
+// defined by the application.
+type Snapshot struct {
+	Height   int64
+	Format   uint32
+	Chunks   uint32
+	Hash     []byte
+	Metadata []byte
+}
+
 type ValidatorUpdate struct {
 	Address crypto.Address
 	PubKey  crypto.PubKey
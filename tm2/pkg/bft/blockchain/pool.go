@@ -273,16 +273,18 @@ func (pool *BlockPool) MaxPeerHeight() int64 {
 	return pool.maxPeerHeight
 }
 
-// SetPeerHeight sets the peer's alleged blockchain height.
-func (pool *BlockPool) SetPeerHeight(peerID p2pTypes.ID, height int64) {
+// SetPeerRange sets the peer's alleged blockchain base and height. The peer
+// doesn't have the blocks below its base, which were pruned.
+func (pool *BlockPool) SetPeerRange(peerID p2pTypes.ID, base, height int64) {
 	pool.mtx.Lock()
 	defer pool.mtx.Unlock()
 
 	peer := pool.peers[peerID]
 	if peer != nil {
+		peer.base = base
 		peer.height = height
 	} else {
-		peer = newBPPeer(pool, peerID, height)
+		peer = newBPPeer(pool, peerID, base, height)
 		peer.setLogger(pool.Logger.With("peer", peerID))
 		pool.peers[peerID] = peer
 	}
@@ -349,7 +351,7 @@ func (pool *BlockPool) pickIncrAvailablePeer(minHeight int64) *bpPeer {
 		if peer.numPending >= maxPendingRequestsPerPeer {
 			continue
 		}
-		if peer.height < minHeight {
+		if peer.base > minHeight || peer.height < minHeight {
 			continue
 		}
 		peer.incrPending()
@@ -423,6 +425,7 @@ type bpPeer struct {
 	id          p2pTypes.ID
 	recvMonitor *flow.Monitor
 
+	base       int64
 	height     int64
 	numPending int32
 	timeout    *time.Timer
@@ -431,10 +434,11 @@ type bpPeer struct {
 	logger *slog.Logger
 }
 
-func newBPPeer(pool *BlockPool, peerID p2pTypes.ID, height int64) *bpPeer {
+func newBPPeer(pool *BlockPool, peerID p2pTypes.ID, base, height int64) *bpPeer {
 	peer := &bpPeer{
 		pool:       pool,
 		id:         peerID,
+		base:       base,
 		height:     height,
 		numPending: 0,
 		logger:     log.NewNoopLogger(),
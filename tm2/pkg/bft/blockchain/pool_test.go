@@ -94,7 +94,7 @@ func TestBlockPoolBasic(t *testing.T) {
 	// Introduce each peer.
 	go func() {
 		for _, peer := range peers {
-			pool.SetPeerHeight(peer.id, peer.height)
+			pool.SetPeerRange(peer.id, 0, peer.height)
 		}
 	}()
 
@@ -151,7 +151,7 @@ func TestBlockPoolTimeout(t *testing.T) {
 	// Introduce each peer.
 	go func() {
 		for _, peer := range peers {
-			pool.SetPeerHeight(peer.id, peer.height)
+			pool.SetPeerRange(peer.id, 0, peer.height)
 		}
 	}()
 
@@ -210,7 +210,7 @@ func TestBlockPoolRemovePeer(t *testing.T) {
 
 	// add peers
 	for peerID, peer := range peers {
-		pool.SetPeerHeight(peerID, peer.height)
+		pool.SetPeerRange(peerID, 0, peer.height)
 	}
 	assert.EqualValues(t, 10, pool.MaxPeerHeight())
 
@@ -228,3 +228,19 @@ func TestBlockPoolRemovePeer(t *testing.T) {
 
 	assert.EqualValues(t, 0, pool.MaxPeerHeight())
 }
+
+func TestBlockPoolPickPeerInRange(t *testing.T) {
+	t.Parallel()
+
+	pool := NewBlockPool(1, make(chan BlockRequest), make(chan peerError))
+	pool.SetLogger(log.NewTestingLogger(t))
+
+	// the blocks of the peer were pruned below 5
+	pool.SetPeerRange(p2pTypes.ID("pruned"), 5, 10)
+	assert.Nil(t, pool.pickIncrAvailablePeer(1))
+	assert.Nil(t, pool.pickIncrAvailablePeer(11))
+
+	peer := pool.pickIncrAvailablePeer(5)
+	require.NotNil(t, peer)
+	assert.Equal(t, p2pTypes.ID("pruned"), peer.id)
+}
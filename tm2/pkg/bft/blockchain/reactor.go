@@ -126,6 +126,29 @@ func (bcR *BlockchainReactor) OnStart() error {
 	return nil
 }
 
+// SwitchToFastSync starts fast syncing from state, when the reactor was
+// started without fast sync while the state was restored by state sync.
+func (bcR *BlockchainReactor) SwitchToFastSync(state sm.State) error {
+	if bcR.fastSync {
+		return errors.New("already fast syncing")
+	}
+	if state.LastBlockHeight != bcR.store.Height() {
+		return fmt.Errorf("state (%v) and store (%v) height mismatch", state.LastBlockHeight,
+			bcR.store.Height())
+	}
+
+	bcR.fastSync = true
+	bcR.initialState = state
+	bcR.pool.mtx.Lock()
+	bcR.pool.height = state.LastBlockHeight + 1
+	bcR.pool.mtx.Unlock()
+	if err := bcR.pool.Start(); err != nil {
+		return err
+	}
+	go bcR.poolRoutine()
+	return nil
+}
+
 // OnStop implements cmn.Service.
 func (bcR *BlockchainReactor) OnStop() {
 	bcR.pool.Stop()
@@ -146,12 +169,12 @@ func (bcR *BlockchainReactor) GetChannels() []*p2p.ChannelDescriptor {
 
 // AddPeer implements Reactor by sending our state to peer.
 func (bcR *BlockchainReactor) AddPeer(peer p2p.PeerConn) {
-	msgBytes := amino.MustMarshalAny(&bcStatusResponseMessage{bcR.store.Height()})
+	msgBytes := amino.MustMarshalAny(&bcStatusResponseMessage{Height: bcR.store.Height(), Base: bcR.store.Base()})
 	peer.Send(BlockchainChannel, msgBytes)
 	// it's OK if send fails. will try later in poolRoutine
 
 	// peer is added to the pool once we receive the first
-	// bcStatusResponseMessage from the peer and call pool.SetPeerHeight
+	// bcStatusResponseMessage from the peer and call pool.SetPeerRange
 }
 
 // RemovePeer implements Reactor by removing peer from the pool.
@@ -202,11 +225,11 @@ func (bcR *BlockchainReactor) Receive(chID byte, src p2p.PeerConn, msgBytes []by
 		bcR.pool.AddBlock(src.ID(), msg.Block, len(msgBytes))
 	case *bcStatusRequestMessage:
 		// Send peer our state.
-		msgBytes := amino.MustMarshalAny(&bcStatusResponseMessage{bcR.store.Height()})
+		msgBytes := amino.MustMarshalAny(&bcStatusResponseMessage{Height: bcR.store.Height(), Base: bcR.store.Base()})
 		src.TrySend(BlockchainChannel, msgBytes)
 	case *bcStatusResponseMessage:
 		// Got a peer status. Unverified.
-		bcR.pool.SetPeerHeight(src.ID(), msg.Height)
+		bcR.pool.SetPeerRange(src.ID(), msg.Base, msg.Height)
 	default:
 		bcR.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
 	}
@@ -455,6 +478,7 @@ func (m *bcStatusRequestMessage) String() string {
 
 type bcStatusResponseMessage struct {
 	Height int64
+	Base   int64 // lowest height of the blocks, which were pruned below
 }
 
 // ValidateBasic performs basic validation.
@@ -462,9 +486,12 @@ func (m *bcStatusResponseMessage) ValidateBasic() error {
 	if m.Height < 0 {
 		return errors.New("negative height")
 	}
+	if m.Base < 0 {
+		return errors.New("negative base")
+	}
 	return nil
 }
 
 func (m *bcStatusResponseMessage) String() string {
-	return fmt.Sprintf("[bcStatusResponseMessage %v]", m.Height)
+	return fmt.Sprintf("[bcStatusResponseMessage %v:%v]", m.Base, m.Height)
 }
@@ -13,8 +13,10 @@ import (
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	cns "github.com/gnolang/gno/tm2/pkg/bft/consensus/config"
 	mem "github.com/gnolang/gno/tm2/pkg/bft/mempool/config"
+	pruning "github.com/gnolang/gno/tm2/pkg/bft/pruner/config"
 	rpc "github.com/gnolang/gno/tm2/pkg/bft/rpc/config"
 	eventstore "github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/types"
+	statesync "github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
 	"github.com/gnolang/gno/tm2/pkg/db"
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	osm "github.com/gnolang/gno/tm2/pkg/os"
@@ -48,13 +50,15 @@ type Config struct {
 	BaseConfig `toml:",squash"`
 
 	// Options for services
-	RPC          *rpc.RPCConfig       `json:"rpc" toml:"rpc" comment:"##### rpc server configuration options #####"`
-	P2P          *p2p.P2PConfig       `json:"p2p" toml:"p2p" comment:"##### peer to peer configuration options #####"`
-	Mempool      *mem.MempoolConfig   `json:"mempool" toml:"mempool" comment:"##### mempool configuration options #####"`
-	Consensus    *cns.ConsensusConfig `json:"consensus" toml:"consensus" comment:"##### consensus configuration options #####"`
-	TxEventStore *eventstore.Config   `json:"tx_event_store" toml:"tx_event_store" comment:"##### event store #####"`
-	Telemetry    *telemetry.Config    `json:"telemetry" toml:"telemetry" comment:"##### node telemetry #####"`
-	Application  *sdk.AppConfig       `json:"application" toml:"application" comment:"##### app settings #####"`
+	RPC          *rpc.RPCConfig             `json:"rpc" toml:"rpc" comment:"##### rpc server configuration options #####"`
+	P2P          *p2p.P2PConfig             `json:"p2p" toml:"p2p" comment:"##### peer to peer configuration options #####"`
+	Mempool      *mem.MempoolConfig         `json:"mempool" toml:"mempool" comment:"##### mempool configuration options #####"`
+	Consensus    *cns.ConsensusConfig       `json:"consensus" toml:"consensus" comment:"##### consensus configuration options #####"`
+	StateSync    *statesync.StateSyncConfig `json:"state_sync" toml:"state_sync" comment:"##### state sync configuration options #####"`
+	Pruning      *pruning.PruningConfig     `json:"pruning" toml:"pruning" comment:"##### block pruning configuration options #####"`
+	TxEventStore *eventstore.Config         `json:"tx_event_store" toml:"tx_event_store" comment:"##### event store #####"`
+	Telemetry    *telemetry.Config          `json:"telemetry" toml:"telemetry" comment:"##### node telemetry #####"`
+	Application  *sdk.AppConfig             `json:"application" toml:"application" comment:"##### app settings #####"`
 }
 
 // DefaultConfig returns a default configuration for a Tendermint node
@@ -65,6 +69,8 @@ func DefaultConfig() *Config {
 		P2P:          p2p.DefaultP2PConfig(),
 		Mempool:      mem.DefaultMempoolConfig(),
 		Consensus:    cns.DefaultConsensusConfig(),
+		StateSync:    statesync.DefaultStateSyncConfig(),
+		Pruning:      pruning.DefaultPruningConfig(),
 		TxEventStore: eventstore.DefaultEventStoreConfig(),
 		Telemetry:    telemetry.DefaultTelemetryConfig(),
 		Application:  sdk.DefaultAppConfig(),
@@ -182,6 +188,8 @@ func TestConfig() *Config {
 		P2P:          testP2PConfig(),
 		Mempool:      mem.TestMempoolConfig(),
 		Consensus:    cns.TestConsensusConfig(),
+		StateSync:    statesync.TestStateSyncConfig(),
+		Pruning:      pruning.TestPruningConfig(),
 		TxEventStore: eventstore.DefaultEventStoreConfig(),
 		Telemetry:    telemetry.DefaultTelemetryConfig(),
 		Application:  sdk.DefaultAppConfig(),
@@ -241,6 +249,12 @@ func (cfg *Config) ValidateBasic() error {
 	if err := cfg.Consensus.ValidateBasic(); err != nil {
 		return errors.Wrap(err, "Error in [consensus] section")
 	}
+	if err := cfg.StateSync.ValidateBasic(); err != nil {
+		return errors.Wrap(err, "Error in [state_sync] section")
+	}
+	if err := cfg.Pruning.ValidateBasic(); err != nil {
+		return errors.Wrap(err, "Error in [pruning] section")
+	}
 	if err := cfg.Application.ValidateBasic(); err != nil {
 		return errors.Wrap(err, "Error in [application] section")
 	}
@@ -250,9 +264,10 @@ func (cfg *Config) ValidateBasic() error {
 // -----------------------------------------------------------------------------
 
 var (
-	DefaultDBDir      = "db"
-	DefaultConfigDir  = "config"
-	DefaultSecretsDir = "secrets"
+	DefaultDBDir        = "db"
+	DefaultSnapshotsDir = "snapshots"
+	DefaultConfigDir    = "config"
+	DefaultSecretsDir   = "secrets"
 
 	DefaultConfigFileName = "config.toml"
 	defaultNodeKeyName    = "node_key.json"
@@ -481,6 +481,12 @@ OUTER_LOOP:
 
 		// If the peer is on a previous height, help catch up.
 		if (0 < prs.Height) && (prs.Height < rs.Height) {
+			// The block was pruned, the peer must catch up from another peer.
+			if prs.Height < conR.conS.blockStore.Base() {
+				time.Sleep(conR.conS.config.PeerGossipSleepDuration)
+				continue OUTER_LOOP
+			}
+
 			heightLogger := logger.With("height", prs.Height)
 
 			// if we never received the commit message from the peer, the block parts wont be initialized
@@ -0,0 +1,221 @@
+// Package light verifies the headers of a chain from a trusted header, without
+// replaying its blocks, as light clients do.
+package light
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+var (
+	ErrOldHeaderExpired   = errors.New("trusted header expired")
+	ErrConflictingHeaders = errors.New("conflicting headers from the witnesses")
+)
+
+// TrustOptions are the options of the header trusted by a Client, usually
+// obtained from a trusted source out of band.
+type TrustOptions struct {
+	// Period is the time during which the validators of a trusted header are
+	// trusted to verify newer headers. It should be shorter than the time
+	// for which the validators can be punished for misbehaving.
+	Period time.Duration
+
+	// Height and Hash are the height and hash of the trusted header.
+	Height int64
+	Hash   []byte
+}
+
+// ValidateBasic checks that the options are complete.
+func (opts TrustOptions) ValidateBasic() error {
+	switch {
+	case opts.Period <= 0:
+		return errors.New("trust period must be positive")
+	case opts.Height <= 0:
+		return errors.New("trust height must be positive")
+	case len(opts.Hash) == 0:
+		return errors.New("no trust hash")
+	}
+	return nil
+}
+
+// trustedHeader is a verified header, with the validators of the next block.
+type trustedHeader struct {
+	*types.SignedHeader
+	nextVals *types.ValidatorSet
+}
+
+// Client verifies the headers returned by a primary provider, starting from a
+// trusted header, and cross-checks them with witness providers.
+type Client struct {
+	chainID   string
+	opts      TrustOptions
+	primary   Provider
+	witnesses []Provider
+	now       func() time.Time
+
+	trusted *trustedHeader // latest verified header
+}
+
+// NewClient returns a Client verifying the headers of chainID from primary,
+// starting from the header trusted by opts.
+func NewClient(ctx context.Context, chainID string, opts TrustOptions, primary Provider, witnesses ...Provider) (*Client, error) {
+	if err := opts.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	c := &Client{
+		chainID:   chainID,
+		opts:      opts,
+		primary:   primary,
+		witnesses: witnesses,
+		now:       time.Now,
+	}
+
+	sh, vals, err := c.fetch(ctx, opts.Height)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(sh.Hash(), opts.Hash) {
+		return nil, fmt.Errorf("header at trust height %d has hash %X, expected %X", opts.Height, sh.Hash(), opts.Hash)
+	}
+	if err := vals.VerifyCommit(chainID, sh.Commit.BlockID, sh.Height, sh.Commit); err != nil {
+		return nil, fmt.Errorf("verifying trusted header: %w", err)
+	}
+	trusted, err := c.trust(ctx, sh)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkExpired(trusted); err != nil {
+		return nil, err
+	}
+	c.trusted = trusted
+	return c, nil
+}
+
+// TrustedHeight returns the height of the latest verified header.
+func (c *Client) TrustedHeight() int64 {
+	return c.trusted.Height
+}
+
+// VerifyHeaderAtHeight returns the verified header at height, which must be
+// greater than the latest verified height. Non-adjacent headers are verified
+// by bisection when the validators changed too much.
+func (c *Client) VerifyHeaderAtHeight(ctx context.Context, height int64) (*types.SignedHeader, error) {
+	if height <= c.trusted.Height {
+		if height == c.trusted.Height {
+			return c.trusted.SignedHeader, nil
+		}
+		return nil, fmt.Errorf("height %d is lower than the trusted height %d", height, c.trusted.Height)
+	}
+	if err := c.checkExpired(c.trusted); err != nil {
+		return nil, err
+	}
+
+	trusted, err := c.verify(ctx, c.trusted, height)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.compareWitnesses(ctx, trusted.SignedHeader); err != nil {
+		return nil, err
+	}
+	c.trusted = trusted
+	return trusted.SignedHeader, nil
+}
+
+// verify verifies the header at height from trusted, bisecting the range of
+// heights when the validators of trusted don't hold more than 2/3 of the
+// voting power of the header.
+func (c *Client) verify(ctx context.Context, trusted *trustedHeader, height int64) (*trustedHeader, error) {
+	sh, vals, err := c.fetch(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	if !sh.Time.After(trusted.Time) {
+		return nil, fmt.Errorf("header at height %d isn't more recent than the trusted header", height)
+	}
+
+	if height == trusted.Height+1 {
+		if !bytes.Equal(sh.ValidatorsHash, trusted.NextValidatorsHash) {
+			return nil, fmt.Errorf("validators of header at height %d don't match the next validators of the trusted header", height)
+		}
+		if err := vals.VerifyCommit(c.chainID, sh.Commit.BlockID, height, sh.Commit); err != nil {
+			return nil, fmt.Errorf("verifying header at height %d: %w", height, err)
+		}
+		return c.trust(ctx, sh)
+	}
+
+	err = trusted.nextVals.VerifyFutureCommit(vals, c.chainID, sh.Commit.BlockID, height, sh.Commit)
+	switch {
+	case types.IsErrTooMuchChange(err):
+		// Verify a header in the middle first.
+		middle, err := c.verify(ctx, trusted, (trusted.Height+height)/2)
+		if err != nil {
+			return nil, err
+		}
+		return c.verify(ctx, middle, height)
+	case err != nil:
+		return nil, fmt.Errorf("verifying header at height %d: %w", height, err)
+	}
+	return c.trust(ctx, sh)
+}
+
+// fetch returns the signed header at height from the primary, and its
+// validators, after checking that they match.
+func (c *Client) fetch(ctx context.Context, height int64) (*types.SignedHeader, *types.ValidatorSet, error) {
+	sh, err := c.primary.SignedHeader(ctx, height)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetching header at height %d: %w", height, err)
+	}
+	if sh.Height != height {
+		return nil, nil, fmt.Errorf("expected header at height %d, got %d", height, sh.Height)
+	}
+	if err := sh.ValidateBasic(c.chainID); err != nil {
+		return nil, nil, err
+	}
+	vals, err := c.primary.ValidatorSet(ctx, height)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetching validators at height %d: %w", height, err)
+	}
+	if !bytes.Equal(vals.Hash(), sh.ValidatorsHash) {
+		return nil, nil, fmt.Errorf("validators at height %d don't match the header", height)
+	}
+	return sh, vals, nil
+}
+
+// trust returns the verified header sh with its next validators.
+func (c *Client) trust(ctx context.Context, sh *types.SignedHeader) (*trustedHeader, error) {
+	nextVals, err := c.primary.ValidatorSet(ctx, sh.Height+1)
+	if err != nil {
+		return nil, fmt.Errorf("fetching validators at height %d: %w", sh.Height+1, err)
+	}
+	if !bytes.Equal(nextVals.Hash(), sh.NextValidatorsHash) {
+		return nil, fmt.Errorf("validators at height %d don't match the header at height %d", sh.Height+1, sh.Height)
+	}
+	return &trustedHeader{SignedHeader: sh, nextVals: nextVals}, nil
+}
+
+func (c *Client) checkExpired(trusted *trustedHeader) error {
+	if expiry := trusted.Time.Add(c.opts.Period); !c.now().Before(expiry) {
+		return fmt.Errorf("%w: header at height %d expired at %v", ErrOldHeaderExpired, trusted.Height, expiry)
+	}
+	return nil
+}
+
+// compareWitnesses checks that the witnesses have the same header as sh.
+func (c *Client) compareWitnesses(ctx context.Context, sh *types.SignedHeader) error {
+	for i, witness := range c.witnesses {
+		wsh, err := witness.SignedHeader(ctx, sh.Height)
+		if err != nil {
+			return fmt.Errorf("fetching header at height %d from witness %d: %w", sh.Height, i, err)
+		}
+		if !bytes.Equal(wsh.Hash(), sh.Hash()) {
+			return fmt.Errorf("%w: witness %d has header %X at height %d, primary has %X",
+				ErrConflictingHeaders, i, wsh.Hash(), sh.Height, sh.Hash())
+		}
+	}
+	return nil
+}
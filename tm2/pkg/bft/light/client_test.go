@@ -0,0 +1,163 @@
+package light
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+const testChainID = "test-chain"
+
+// mockChain is a Provider of a chain whose validators change completely
+// every changeEvery blocks.
+type mockChain struct {
+	headers map[int64]*types.SignedHeader
+	vals    map[int64]*types.ValidatorSet
+}
+
+func newMockChain(t *testing.T, height int64, changeEvery int64, start time.Time) *mockChain {
+	t.Helper()
+
+	c := &mockChain{
+		headers: map[int64]*types.SignedHeader{},
+		vals:    map[int64]*types.ValidatorSet{},
+	}
+	privs := map[int64][]types.PrivValidator{}
+	for h := int64(1); h <= height+1; h++ {
+		if (h-1)%changeEvery == 0 {
+			c.vals[h], privs[h] = types.RandValidatorSet(4, 10)
+		} else {
+			c.vals[h], privs[h] = c.vals[h-1], privs[h-1]
+		}
+	}
+	for h := int64(1); h <= height; h++ {
+		header := &types.Header{
+			ChainID:            testChainID,
+			Height:             h,
+			Time:               start.Add(time.Duration(h) * time.Second),
+			ValidatorsHash:     c.vals[h].Hash(),
+			NextValidatorsHash: c.vals[h+1].Hash(),
+		}
+		blockID := types.BlockID{Hash: header.Hash()}
+		voteSet := types.NewVoteSet(testChainID, h, 0, types.PrecommitType, c.vals[h])
+		commit, err := types.MakeCommit(blockID, h, 0, voteSet, privs[h])
+		require.NoError(t, err)
+		c.headers[h] = &types.SignedHeader{Header: header, Commit: commit}
+	}
+	return c
+}
+
+func (c *mockChain) SignedHeader(_ context.Context, height int64) (*types.SignedHeader, error) {
+	sh, ok := c.headers[height]
+	if !ok {
+		return nil, fmt.Errorf("no header at height %d", height)
+	}
+	return sh, nil
+}
+
+func (c *mockChain) ValidatorSet(_ context.Context, height int64) (*types.ValidatorSet, error) {
+	vals, ok := c.vals[height]
+	if !ok {
+		return nil, fmt.Errorf("no validators at height %d", height)
+	}
+	return vals, nil
+}
+
+// countingProvider counts the headers fetched from a Provider.
+type countingProvider struct {
+	Provider
+	calls int
+}
+
+func (p *countingProvider) SignedHeader(ctx context.Context, height int64) (*types.SignedHeader, error) {
+	p.calls++
+	return p.Provider.SignedHeader(ctx, height)
+}
+
+func trustOptions(c *mockChain, height int64) TrustOptions {
+	return TrustOptions{
+		Period: time.Hour,
+		Height: height,
+		Hash:   c.headers[height].Hash(),
+	}
+}
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	start := time.Now().Add(-10 * time.Minute)
+	chain := newMockChain(t, 40, 10, start)
+
+	t.Run("skipping verification", func(t *testing.T) {
+		t.Parallel()
+
+		primary := &countingProvider{Provider: chain}
+		c, err := NewClient(ctx, testChainID, trustOptions(chain, 2), primary, chain)
+		require.NoError(t, err)
+
+		// The validators change every 10 blocks: the headers are verified by
+		// bisection.
+		sh, err := c.VerifyHeaderAtHeight(ctx, 35)
+		require.NoError(t, err)
+		assert.Equal(t, chain.headers[35].Hash(), sh.Hash())
+		assert.Equal(t, int64(35), c.TrustedHeight())
+		assert.Greater(t, primary.calls, 2)
+
+		_, err = c.VerifyHeaderAtHeight(ctx, 20)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid trust hash", func(t *testing.T) {
+		t.Parallel()
+
+		opts := trustOptions(chain, 2)
+		opts.Hash = chain.headers[3].Hash()
+		_, err := NewClient(ctx, testChainID, opts, chain)
+		require.Error(t, err)
+	})
+
+	t.Run("expired trusted header", func(t *testing.T) {
+		t.Parallel()
+
+		opts := trustOptions(chain, 2)
+		opts.Period = time.Nanosecond
+		_, err := NewClient(ctx, testChainID, opts, chain)
+		require.ErrorIs(t, err, ErrOldHeaderExpired)
+	})
+
+	t.Run("conflicting witness", func(t *testing.T) {
+		t.Parallel()
+
+		fork := newMockChain(t, 40, 10, start)
+		for h := int64(1); h <= 5; h++ {
+			fork.headers[h], fork.vals[h] = chain.headers[h], chain.vals[h]
+		}
+		c, err := NewClient(ctx, testChainID, trustOptions(chain, 2), chain, fork)
+		require.NoError(t, err)
+		_, err = c.VerifyHeaderAtHeight(ctx, 4)
+		require.NoError(t, err)
+		_, err = c.VerifyHeaderAtHeight(ctx, 30)
+		require.ErrorIs(t, err, ErrConflictingHeaders)
+	})
+
+	t.Run("forged header", func(t *testing.T) {
+		t.Parallel()
+
+		// The primary serves a chain signed by other validators.
+		forged := newMockChain(t, 40, 10, start)
+		for h := int64(1); h <= 10; h++ {
+			forged.headers[h], forged.vals[h] = chain.headers[h], chain.vals[h]
+		}
+		c, err := NewClient(ctx, testChainID, trustOptions(chain, 2), forged)
+		require.NoError(t, err)
+		_, err = c.VerifyHeaderAtHeight(ctx, 30)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,58 @@
+package light
+
+import (
+	"context"
+	"fmt"
+
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+// Provider provides the signed headers and validator sets of a chain. The
+// returned data is untrusted, and verified by the Client.
+type Provider interface {
+	// SignedHeader returns the header at height, with the commit for it.
+	SignedHeader(ctx context.Context, height int64) (*types.SignedHeader, error)
+
+	// ValidatorSet returns the validators of the block at height.
+	ValidatorSet(ctx context.Context, height int64) (*types.ValidatorSet, error)
+}
+
+// RPCClient is the subset of the RPC client used by the RPC provider.
+type RPCClient interface {
+	Commit(ctx context.Context, height *int64) (*ctypes.ResultCommit, error)
+	Validators(ctx context.Context, height *int64) (*ctypes.ResultValidators, error)
+}
+
+type rpcProvider struct {
+	client RPCClient
+}
+
+// NewRPCProvider returns a Provider querying a node over RPC.
+func NewRPCProvider(client RPCClient) Provider {
+	return rpcProvider{client: client}
+}
+
+func (p rpcProvider) SignedHeader(ctx context.Context, height int64) (*types.SignedHeader, error) {
+	res, err := p.client.Commit(ctx, &height)
+	if err != nil {
+		return nil, err
+	}
+	if res.Header == nil || res.Commit == nil {
+		return nil, fmt.Errorf("no signed header at height %d", height)
+	}
+	return &res.SignedHeader, nil
+}
+
+func (p rpcProvider) ValidatorSet(ctx context.Context, height int64) (*types.ValidatorSet, error) {
+	res, err := p.client.Validators(ctx, &height)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Validators) == 0 {
+		return nil, fmt.Errorf("no validators at height %d", height)
+	}
+	// Keep the proposer priorities of the node, instead of recomputing them
+	// with types.NewValidatorSet.
+	return &types.ValidatorSet{Validators: res.Validators}, nil
+}
@@ -2,11 +2,16 @@ package mempool
 
 import (
 	"bytes"
+	"cmp"
+	"container/heap"
 	"container/list"
 	"context"
 	"crypto/sha256"
 	"fmt"
 	"log/slog"
+	"math"
+	"math/big"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -31,8 +36,13 @@ import (
 // CheckTx abci message before the transaction is added to the pool. The
 // mempool uses a concurrent list structure for storing transactions that can
 // be efficiently accessed by multiple concurrent readers.
+//
+// The list keeps the arrival order of the transactions, in which they are
+// gossiped. Unless the mempool is of type [cfg.MempoolTypeCList], they are
+// reaped and rechecked by priority instead, see orderedTxs.
 type CListMempool struct {
-	config *cfg.MempoolConfig
+	config   *cfg.MempoolConfig
+	priority bool // order by priority, see cfg.MempoolTypePriority
 
 	mtx          sync.Mutex
 	proxyAppConn appconn.Mempool
@@ -42,10 +52,9 @@ type CListMempool struct {
 	maxTxBytes   int64
 
 	// Track whether we're rechecking txs.
-	// These are not protected by a mutex and are expected to be mutated
+	// This is not protected by a mutex and is expected to be mutated
 	// in serial (ie. by abci responses which are called in serial).
-	recheckCursor *clist.CElement // next expected response
-	recheckEnd    *clist.CElement // re-checking stops here
+	recheckQueue []*clist.CElement // expected responses, in order
 
 	// notify listeners (ie. consensus) when txs are available
 	notifiedTxsAvailable bool
@@ -55,6 +64,10 @@ type CListMempool struct {
 	// txsMap: txKey -> CElement
 	txsMap sync.Map
 
+	// Map for the replacement of txs with the same sender and sequence.
+	// senderTxsMap: senderKey -> CElement
+	senderTxsMap sync.Map
+
 	// Atomic integers
 	txsBytes   int64 // total size of mempool, in bytes
 	rechecking int32 // for re-checking filtered txs on Update()
@@ -86,15 +99,14 @@ func NewCListMempool(
 		panic("maxTxBytes must be positive")
 	}
 	mempool := &CListMempool{
-		config:        config,
-		proxyAppConn:  proxyAppConn,
-		txs:           clist.New(),
-		height:        height,
-		maxTxBytes:    maxTxBytes,
-		rechecking:    0,
-		recheckCursor: nil,
-		recheckEnd:    nil,
-		logger:        log.NewNoopLogger(),
+		config:       config,
+		priority:     config.Type != cfg.MempoolTypeCList,
+		proxyAppConn: proxyAppConn,
+		txs:          clist.New(),
+		height:       height,
+		maxTxBytes:   maxTxBytes,
+		rechecking:   0,
+		logger:       log.NewNoopLogger(),
 	}
 	if config.CacheSize > 0 {
 		mempool.cache = newMapTxCache(config.CacheSize)
@@ -187,6 +199,7 @@ func (mem *CListMempool) Flush() {
 	}
 
 	mem.txsMap = sync.Map{}
+	mem.senderTxsMap = sync.Map{}
 	_ = atomic.SwapInt64(&mem.txsBytes, 0)
 }
 
@@ -225,9 +238,11 @@ func (mem *CListMempool) CheckTxWithInfo(tx types.Tx, cb func(abci.Response), tx
 		txSize   = len(tx)
 	)
 
-	// Check max pending txs bytes
-	if memSize >= mem.config.Size ||
-		int64(txSize)+txsBytes > mem.config.MaxPendingTxsBytes {
+	// Check max pending txs bytes.
+	// The priority mempool evicts txs with a lower priority instead, once it
+	// is known from the CheckTx response.
+	if !mem.priority && (memSize >= mem.config.Size ||
+		int64(txSize)+txsBytes > mem.config.MaxPendingTxsBytes) {
 		return MempoolIsFullError{
 			memSize, mem.config.Size,
 			txsBytes, mem.config.MaxPendingTxsBytes,
@@ -297,7 +312,7 @@ func (mem *CListMempool) CheckTxWithInfo(tx types.Tx, cb func(abci.Response), tx
 // so the request specific callback can do the work.
 // When rechecking, we don't need the peerID, so the recheck callback happens here.
 func (mem *CListMempool) globalCb(req abci.Request, res abci.Response) {
-	if mem.recheckCursor == nil {
+	if len(mem.recheckQueue) == 0 {
 		return
 	} else {
 		mem.resCbRecheck(req, res)
@@ -310,17 +325,18 @@ func (mem *CListMempool) globalCb(req abci.Request, res abci.Response) {
 // NOTE: alternatively, we could include this information in the ABCI request itself.
 //
 // External callers of CheckTx, like the RPC, can also pass an externalCb through here that is called
-// when all other response processing is complete.
+// when all other response processing is complete, with the error of the mempool if it rejected a
+// tx accepted by the app.
 //
 // Used in CheckTxWithInfo to record PeerID who sent us the tx.
 func (mem *CListMempool) reqResCb(tx []byte, peerID uint16, externalCb func(abci.Response)) func(res abci.Response) {
 	return func(res abci.Response) {
-		if mem.recheckCursor != nil {
+		if len(mem.recheckQueue) != 0 {
 			// this should never happen
-			panic("recheck cursor is not nil in reqResCb")
+			panic("recheck queue is not empty in reqResCb")
 		}
 
-		mem.resCbFirstTime(tx, peerID, res)
+		res = mem.resCbFirstTime(tx, peerID, res)
 
 		// Passed in by the caller of CheckTx, eg. the RPC.
 		// The external callback cannot modify the result.
@@ -335,6 +351,9 @@ func (mem *CListMempool) reqResCb(tx []byte, peerID uint16, externalCb func(abci
 func (mem *CListMempool) addTx(memTx *mempoolTx) {
 	e := mem.txs.PushBack(memTx)
 	mem.txsMap.Store(txKey(memTx.tx), e)
+	if memTx.sender != "" {
+		mem.senderTxsMap.Store(memTx.senderKey(), e)
+	}
 	atomic.AddInt64(&mem.txsBytes, int64(len(memTx.tx)))
 
 	// Update the telemetry
@@ -357,10 +376,15 @@ func (mem *CListMempool) logTelemetry() {
 // Called from:
 //   - Update (lock held) if tx was committed
 //   - resCbRecheck (lock not held) if tx was invalidated
+//   - resCbFirstTime (lock not held) if tx was replaced or evicted
+//   - EvictTxs (lock held) if tx was evicted
 func (mem *CListMempool) removeTx(tx types.Tx, elem *clist.CElement, removeFromCache bool) {
 	mem.txs.Remove(elem)
 	elem.DetachPrev()
 	mem.txsMap.Delete(txKey(tx))
+	if memTx := elem.Value.(*mempoolTx); memTx.sender != "" {
+		mem.senderTxsMap.CompareAndDelete(memTx.senderKey(), elem)
+	}
 	atomic.AddInt64(&mem.txsBytes, int64(-len(tx)))
 
 	if removeFromCache {
@@ -372,10 +396,11 @@ func (mem *CListMempool) removeTx(tx types.Tx, elem *clist.CElement, removeFromC
 }
 
 // callback, which is called after the app checked the tx for the first time.
+// It returns res, with the error of the mempool if it rejected the tx.
 //
 // The case where the app checks the tx for the second and subsequent times is
 // handled by the resCbRecheck callback.
-func (mem *CListMempool) resCbFirstTime(tx []byte, peerID uint16, res abci.Response) {
+func (mem *CListMempool) resCbFirstTime(tx []byte, peerID uint16, res abci.Response) abci.Response {
 	switch res := res.(type) {
 	case abci.ResponseCheckTx:
 		if res.Error == nil {
@@ -384,6 +409,18 @@ func (mem *CListMempool) resCbFirstTime(tx []byte, peerID uint16, res abci.Respo
 				gasWanted: res.GasWanted,
 				tx:        tx,
 			}
+			if mem.priority {
+				memTx.priority = res.Priority
+				memTx.sender = res.Sender
+				memTx.sequence = res.Sequence
+				if err := mem.makeRoomFor(memTx); err != nil {
+					mem.logger.Info("Rejected transaction", "tx", txID(tx), "err", err)
+					// remove from cache (it might be good later)
+					mem.cache.Remove(tx)
+					res.Error = abci.StringError(err.Error())
+					return res
+				}
+			}
 			memTx.senders.Store(peerID, true)
 			mem.addTx(memTx)
 			mem.logger.Info("Added good transaction",
@@ -399,9 +436,92 @@ func (mem *CListMempool) resCbFirstTime(tx []byte, peerID uint16, res abci.Respo
 			// remove from cache (it might be good later)
 			mem.cache.Remove(tx)
 		}
+		return res
 	default:
 		// ignore other messages
+		return res
+	}
+}
+
+// makeRoomFor removes the txs that memTx replaces or that must be evicted for
+// it to fit in the mempool. It returns an error if memTx can't be added:
+//   - TxUnderpricedError if it doesn't bump enough the priority of the pending
+//     tx with the same sender and sequence;
+//   - MempoolIsFullError if the mempool is full of txs with a higher priority.
+func (mem *CListMempool) makeRoomFor(memTx *mempoolTx) error {
+	if memTx.sender != "" {
+		if e, ok := mem.senderTxsMap.Load(memTx.senderKey()); ok {
+			elem := e.(*clist.CElement)
+			pending := elem.Value.(*mempoolTx)
+			minPriority := bumpPriority(pending.priority, mem.config.PriceBump)
+			if memTx.priority < minPriority {
+				return TxUnderpricedError{memTx.priority, minPriority}
+			}
+			// keep the replaced tx in the cache so it is not added back
+			mem.removeTx(pending.tx, elem, false)
+			mem.logger.Info("Replaced transaction", "tx", txID(pending.tx), "by", txID(memTx.tx))
+		}
 	}
+
+	var (
+		memSize  = mem.Size()
+		txsBytes = mem.TxsBytes()
+		txSize   = int64(len(memTx.tx))
+	)
+	isFull := func() bool {
+		return memSize >= mem.config.Size || txSize+txsBytes > mem.config.MaxPendingTxsBytes
+	}
+	if !isFull() {
+		return nil
+	}
+
+	// evict from the last txs to be reaped, as the txs they depend on are
+	// reaped before. A tx is only as good as the txs of its sender before it,
+	// so its priority is the lowest of theirs.
+	fullErr := MempoolIsFullError{memSize, mem.config.Size, txsBytes, mem.config.MaxPendingTxsBytes}
+	ordered := mem.orderedTxs()
+	priorities := make([]int64, len(ordered))
+	senderPriorities := make(map[string]int64)
+	for i, elem := range ordered {
+		tx := elem.Value.(*mempoolTx)
+		priorities[i] = tx.priority
+		if prev, ok := senderPriorities[tx.sender]; ok && tx.sender != "" {
+			priorities[i] = min(prev, tx.priority)
+		}
+		senderPriorities[tx.sender] = priorities[i]
+	}
+	evicted := 0
+	for i := len(ordered) - 1; i >= 0 && isFull(); i-- {
+		evictTx := ordered[i].Value.(*mempoolTx)
+		if priorities[i] >= memTx.priority ||
+			(memTx.sender != "" && evictTx.sender == memTx.sender) {
+			return fullErr
+		}
+		memSize--
+		txsBytes -= int64(len(evictTx.tx))
+		evicted++
+	}
+	if isFull() {
+		return fullErr
+	}
+
+	for _, elem := range ordered[len(ordered)-evicted:] {
+		evictTx := elem.Value.(*mempoolTx)
+		mem.removeTx(evictTx.tx, elem, true)
+		mem.logger.Info("Evicted transaction", "tx", txID(evictTx.tx), "by", txID(memTx.tx))
+	}
+	return nil
+}
+
+// bumpPriority returns the minimum priority of a tx replacing a tx with the
+// given priority, increased by bump percent, and at least by one.
+func bumpPriority(priority int64, bump int64) int64 {
+	bumped := new(big.Int).Mul(big.NewInt(priority), big.NewInt(100+bump))
+	bumped.Quo(bumped, big.NewInt(100))
+	if !bumped.IsInt64() {
+		return math.MaxInt64
+	}
+	return max(bumped.Int64(), priority+1)
 }
 
 // callback, which is called after the app rechecked the tx.
@@ -412,7 +532,8 @@ func (mem *CListMempool) resCbRecheck(req abci.Request, res abci.Response) {
 	switch res := res.(type) {
 	case abci.ResponseCheckTx:
 		tx := req.(abci.RequestCheckTx).Tx
-		memTx := mem.recheckCursor.Value.(*mempoolTx)
+		elem := mem.recheckQueue[0]
+		memTx := elem.Value.(*mempoolTx)
 		if !bytes.Equal(tx, memTx.tx) {
 			panic(fmt.Sprintf(
 				"Unexpected tx response from proxy during recheck\nExpected %X, got %X",
@@ -425,14 +546,11 @@ func (mem *CListMempool) resCbRecheck(req abci.Request, res abci.Response) {
 			// Tx became invalidated due to newly committed block.
 			mem.logger.Info("Tx is no longer valid", "tx", txID(tx), "res", res, "err", res.Error)
 			// NOTE: we remove tx from the cache because it might be good later
-			mem.removeTx(tx, mem.recheckCursor, true)
-		}
-		if mem.recheckCursor == mem.recheckEnd {
-			mem.recheckCursor = nil
-		} else {
-			mem.recheckCursor = mem.recheckCursor.Next()
+			mem.removeTx(tx, elem, true)
 		}
-		if mem.recheckCursor == nil {
+		mem.recheckQueue = mem.recheckQueue[1:]
+		if len(mem.recheckQueue) == 0 {
+			mem.recheckQueue = nil
 			// Done!
 			atomic.StoreInt32(&mem.rechecking, 0)
 			mem.logger.Info("Done rechecking txs")
@@ -484,7 +602,7 @@ func (mem *CListMempool) ReapMaxBytesMaxGas(maxDataBytes, maxGas int64) types.Tx
 	// size per tx, and set the initial capacity based off of that.
 	// txs := make([]types.Tx, 0, min(mem.txs.Len(), max/mem.avgTxSize))
 	txs := make([]types.Tx, 0, mem.txs.Len())
-	for e := mem.txs.Front(); e != nil; e = e.Next() {
+	for _, e := range mem.orderedTxs() {
 		memTx := e.Value.(*mempoolTx)
 		// Check total size requirement
 		if maxDataBytes > -1 && totalBytes+int64(len(memTx.tx)) > maxDataBytes {
@@ -519,13 +637,38 @@ func (mem *CListMempool) ReapMaxTxs(maxVal int) types.Txs {
 	}
 
 	txs := make([]types.Tx, 0, min(mem.txs.Len(), maxVal))
-	for e := mem.txs.Front(); e != nil && len(txs) <= maxVal; e = e.Next() {
+	for _, e := range mem.orderedTxs() {
+		if len(txs) > maxVal {
+			break
+		}
 		memTx := e.Value.(*mempoolTx)
 		txs = append(txs, memTx.tx)
 	}
 	return txs
 }
 
+// EvictTxs removes up to maxVal of the last transactions to be reaped, i.e.
+// the lowest-priority ones in the priority mempool, and returns them.
+func (mem *CListMempool) EvictTxs(maxVal int) types.Txs {
+	mem.mtx.Lock()
+	defer mem.mtx.Unlock()
+
+	for atomic.LoadInt32(&mem.rechecking) > 0 {
+		// TODO: Something better?
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	ordered := mem.orderedTxs()
+	txs := make([]types.Tx, 0, min(len(ordered), max(maxVal, 0)))
+	for i := len(ordered) - 1; i >= 0 && len(txs) < maxVal; i-- {
+		memTx := ordered[i].Value.(*mempoolTx)
+		// remove from cache so it can be submitted again
+		mem.removeTx(memTx.tx, ordered[i], true)
+		txs = append(txs, memTx.tx)
+	}
+	return txs
+}
+
 func (mem *CListMempool) Update(
 	height int64,
 	txs types.Txs,
@@ -590,13 +733,11 @@ func (mem *CListMempool) recheckTxs() {
 		panic("recheckTxs is called, but the mempool is empty")
 	}
 
-	atomic.StoreInt32(&mem.rechecking, 1)
-	mem.recheckCursor = mem.txs.Front()
-	mem.recheckEnd = mem.txs.Back()
-
-	// Push txs to proxyAppConn
-	// NOTE: globalCb may be called concurrently.
-	for e := mem.txs.Front(); e != nil; e = e.Next() {
+	// Recheck the txs in the order they are reaped, for the txs of a sender
+	// to be checked by sequence.
+	ordered := mem.orderedTxs()
+	queue := make([]*clist.CElement, 0, len(ordered))
+	for _, e := range ordered {
 		memTx := e.Value.(*mempoolTx)
 		// check tx size
 		if int64(len(memTx.tx)) > mem.maxTxBytes {
@@ -610,9 +751,24 @@ func (mem *CListMempool) recheckTxs() {
 				continue
 			}
 		}
+		queue = append(queue, e)
+	}
+	if len(queue) == 0 {
+		if mem.Size() > 0 {
+			mem.notifyTxsAvailable()
+		}
+		return
+	}
+
+	atomic.StoreInt32(&mem.rechecking, 1)
+	mem.recheckQueue = queue
+
+	// Push txs to proxyAppConn
+	// NOTE: globalCb may be called concurrently.
+	for _, e := range queue {
 		// run proxy app checktx
 		mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{
-			Tx:   memTx.tx,
+			Tx:   e.Value.(*mempoolTx).tx,
 			Type: abci.CheckTxTypeRecheck,
 		})
 	}
@@ -620,6 +776,54 @@ func (mem *CListMempool) recheckTxs() {
 	mem.proxyAppConn.FlushAsync()
 }
 
+// orderedTxs returns the elements of the mempool in the order they are reaped:
+// by decreasing priority in the priority mempool, keeping the txs of each sender
+// by increasing sequence, and in arrival order otherwise.
+func (mem *CListMempool) orderedTxs() []*clist.CElement {
+	elems := make([]*clist.CElement, 0, mem.txs.Len())
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		elems = append(elems, e)
+	}
+	if !mem.priority {
+		return elems
+	}
+
+	// queue the txs of each sender by sequence, in arrival order
+	queues := make([]*senderQueue, 0, len(elems))
+	bySender := make(map[string]*senderQueue)
+	for i, e := range elems {
+		sender := e.Value.(*mempoolTx).sender
+		if q, ok := bySender[sender]; ok && sender != "" {
+			q.elems = append(q.elems, e)
+			continue
+		}
+		q := &senderQueue{elems: []*clist.CElement{e}, arrival: i}
+		queues = append(queues, q)
+		bySender[sender] = q
+	}
+	for _, q := range queues {
+		slices.SortStableFunc(q.elems, func(a, b *clist.CElement) int {
+			return cmp.Compare(a.Value.(*mempoolTx).sequence, b.Value.(*mempoolTx).sequence)
+		})
+	}
+
+	// then merge the queues by the priority of their next tx
+	h := senderQueues(queues)
+	heap.Init(&h)
+	ordered := make([]*clist.CElement, 0, len(elems))
+	for h.Len() > 0 {
+		q := h[0]
+		ordered = append(ordered, q.elems[0])
+		q.elems = q.elems[1:]
+		if len(q.elems) == 0 {
+			heap.Pop(&h)
+		} else {
+			heap.Fix(&h, 0)
+		}
+	}
+	return ordered
+}
+
 // --------------------------------------------------------------------------------
 
 // mempoolTx is a transaction that successfully ran
@@ -628,6 +832,11 @@ type mempoolTx struct {
 	gasWanted int64    // amount of gas this tx states it will require
 	tx        types.Tx //
 
+	// set by the CheckTx response in the priority mempool
+	priority int64
+	sender   string
+	sequence uint64
+
 	// ids of peers who've sent us this tx (as a map for quick lookups).
 	// senders: PeerID -> bool
 	senders sync.Map
@@ -638,6 +847,45 @@ func (memTx *mempoolTx) Height() int64 {
 	return atomic.LoadInt64(&memTx.height)
 }
 
+// senderKey identifies the txs replacing each other in the mempool.
+type senderKey struct {
+	sender   string
+	sequence uint64
+}
+
+func (memTx *mempoolTx) senderKey() senderKey {
+	return senderKey{memTx.sender, memTx.sequence}
+}
+
+// senderQueue is the queue of the txs of a sender, by sequence. The txs
+// without sender have their own queue.
+type senderQueue struct {
+	elems   []*clist.CElement
+	arrival int // order of the first tx of the sender in the mempool
+}
+
+// senderQueues is a max-heap of senderQueue by priority of their next tx, then
+// by arrival.
+type senderQueues []*senderQueue
+
+func (h senderQueues) Len() int { return len(h) }
+func (h senderQueues) Less(i, j int) bool {
+	pi := h[i].elems[0].Value.(*mempoolTx).priority
+	pj := h[j].elems[0].Value.(*mempoolTx).priority
+	if pi != pj {
+		return pi > pj
+	}
+	return h[i].arrival < h[j].arrival
+}
+func (h senderQueues) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *senderQueues) Push(x any)   { *h = append(*h, x.(*senderQueue)) }
+func (h *senderQueues) Pop() any {
+	old := *h
+	q := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return q
+}
+
 // --------------------------------------------------------------------------------
 
 type txCache interface {
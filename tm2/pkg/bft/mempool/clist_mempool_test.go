@@ -463,6 +463,7 @@ func TestMempoolMaxPendingTxsBytes(t *testing.T) {
 	app := kvstore.NewKVStoreApplication()
 	cc := proxy.NewLocalClientCreator(app)
 	config := cfg.TestMempoolConfig()
+	config.Type = cfg.MempoolTypeCList
 	config.MaxPendingTxsBytes = 10
 	mempool, cleanup := newMempoolWithAppAndConfig(cc, config)
 	defer cleanup()
@@ -550,3 +551,133 @@ func abciResponses(n int, err abci.Error) []abci.ResponseDeliverTx {
 	}
 	return responses
 }
+
+// priorityApp accepts the "sender/sequence/priority" txs, see priorityTx.
+type priorityApp struct {
+	abci.BaseApplication
+}
+
+func (priorityApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	var res abci.ResponseCheckTx
+	_, err := fmt.Sscanf(string(req.Tx), "%1s/%d/%d", &res.Sender, &res.Sequence, &res.Priority)
+	if err != nil {
+		res.Error = abci.StringError(err.Error())
+	}
+	return res
+}
+
+func priorityTx(sender string, sequence uint64, priority int64) types.Tx {
+	return types.Tx(fmt.Sprintf("%s/%d/%d", sender, sequence, priority))
+}
+
+func newPriorityMempool(t *testing.T, size int) *CListMempool {
+	t.Helper()
+
+	config := cfg.TestMempoolConfig()
+	config.Size = size
+	mempool, cleanup := newMempoolWithAppAndConfig(proxy.NewLocalClientCreator(priorityApp{}), config)
+	t.Cleanup(cleanup)
+	return mempool
+}
+
+// checkPriorityTx checks tx and returns the error of its CheckTx response.
+func checkPriorityTx(t *testing.T, mempool *CListMempool, tx types.Tx) error {
+	t.Helper()
+
+	var resErr error
+	require.NoError(t, mempool.CheckTx(tx, func(res abci.Response) {
+		resErr = res.(abci.ResponseCheckTx).Error
+	}))
+	return resErr
+}
+
+func TestMempoolPriority(t *testing.T) {
+	t.Parallel()
+
+	mempool := newPriorityMempool(t, 100)
+	for _, tx := range []types.Tx{
+		priorityTx("a", 1, 10),
+		priorityTx("b", 0, 20),
+		priorityTx("a", 0, 5),
+		priorityTx("c", 0, 10),
+		priorityTx("a", 2, 30),
+	} {
+		require.NoError(t, checkPriorityTx(t, mempool, tx))
+	}
+
+	// the txs of a sender are reaped by sequence, even if the next one has a
+	// higher priority.
+	expected := types.Txs{
+		priorityTx("b", 0, 20),
+		priorityTx("c", 0, 10),
+		priorityTx("a", 0, 5),
+		priorityTx("a", 1, 10),
+		priorityTx("a", 2, 30),
+	}
+	assert.Equal(t, expected, mempool.ReapMaxBytesMaxGas(-1, -1))
+	assert.Equal(t, expected[:2], mempool.ReapMaxBytesMaxGas(int64(2*len(expected[0])), -1))
+
+	// the gossip order is unchanged
+	assert.Equal(t, priorityTx("a", 1, 10), mempool.TxsFront().Value.(*mempoolTx).tx)
+
+	// the clist mempool reaps in arrival order
+	config := cfg.TestMempoolConfig()
+	config.Type = cfg.MempoolTypeCList
+	clist, cleanup := newMempoolWithAppAndConfig(proxy.NewLocalClientCreator(priorityApp{}), config)
+	defer cleanup()
+	require.NoError(t, checkPriorityTx(t, clist, priorityTx("a", 0, 5)))
+	require.NoError(t, checkPriorityTx(t, clist, priorityTx("b", 0, 20)))
+	assert.Equal(t, types.Txs{priorityTx("a", 0, 5), priorityTx("b", 0, 20)}, clist.ReapMaxBytesMaxGas(-1, -1))
+}
+
+func TestMempoolReplaceTx(t *testing.T) {
+	t.Parallel()
+
+	mempool := newPriorityMempool(t, 100)
+	require.NoError(t, checkPriorityTx(t, mempool, priorityTx("a", 0, 100)))
+
+	// the default price bump is 10%
+	err := checkPriorityTx(t, mempool, priorityTx("a", 0, 109))
+	assert.EqualError(t, err, TxUnderpricedError{109, 110}.Error())
+	assert.Equal(t, types.Txs{priorityTx("a", 0, 100)}, mempool.ReapMaxTxs(-1))
+
+	require.NoError(t, checkPriorityTx(t, mempool, priorityTx("a", 0, 110)))
+	assert.Equal(t, types.Txs{priorityTx("a", 0, 110)}, mempool.ReapMaxTxs(-1))
+
+	// the replaced tx stays in the cache
+	assert.ErrorIs(t, mempool.CheckTx(priorityTx("a", 0, 100), nil), ErrTxInCache)
+
+	// the rejected tx can be resubmitted
+	require.NoError(t, mempool.CheckTx(priorityTx("a", 0, 109), nil))
+}
+
+func TestMempoolEvictTxs(t *testing.T) {
+	t.Parallel()
+
+	mempool := newPriorityMempool(t, 3)
+	require.NoError(t, checkPriorityTx(t, mempool, priorityTx("a", 0, 10)))
+	require.NoError(t, checkPriorityTx(t, mempool, priorityTx("a", 1, 50)))
+	require.NoError(t, checkPriorityTx(t, mempool, priorityTx("b", 0, 20)))
+
+	// a tx with a lower priority than the mempool txs is rejected
+	err := checkPriorityTx(t, mempool, priorityTx("c", 0, 5))
+	assert.ErrorContains(t, err, "mempool is full")
+
+	// a tx with a higher priority evicts the last tx to be reaped, a/1 as it
+	// depends on a/0
+	require.NoError(t, checkPriorityTx(t, mempool, priorityTx("c", 0, 15)))
+	assert.Equal(t, types.Txs{
+		priorityTx("b", 0, 20),
+		priorityTx("c", 0, 15),
+		priorityTx("a", 0, 10),
+	}, mempool.ReapMaxTxs(-1))
+
+	// the txs of the same sender are never evicted
+	err = checkPriorityTx(t, mempool, priorityTx("a", 1, 50))
+	assert.ErrorContains(t, err, "mempool is full")
+
+	assert.Equal(t, types.Txs{priorityTx("a", 0, 10)}, mempool.EvictTxs(1))
+	assert.Equal(t, 2, mempool.Size())
+	// evicted txs are removed from the cache
+	require.NoError(t, checkPriorityTx(t, mempool, priorityTx("a", 0, 10)))
+}
@@ -2,23 +2,35 @@ package config
 
 import "github.com/gnolang/gno/tm2/pkg/errors"
 
+// Mempool types, see MempoolConfig.Type.
+const (
+	// MempoolTypePriority reaps txs by priority, i.e. gas price, and lets a
+	// tx replace a pending one of the same sender and sequence.
+	MempoolTypePriority = "priority"
+	// MempoolTypeCList reaps txs in arrival order.
+	MempoolTypeCList = "clist"
+)
+
 // -----------------------------------------------------------------------------
 // MempoolConfig
 
 // MempoolConfig defines the configuration options for the Tendermint mempool
 type MempoolConfig struct {
 	RootDir            string `json:"home" toml:"home"`
+	Type               string `json:"type" toml:"type" comment:"Mempool type, one of:\n - \"priority\": reap txs by gas price, allow replacing pending txs and evict the lowest-priority ones when full\n - \"clist\": reap txs in arrival order"`
 	Recheck            bool   `json:"recheck" toml:"recheck"`
 	Broadcast          bool   `json:"broadcast" toml:"broadcast"`
 	WalPath            string `json:"wal_dir" toml:"wal_dir"`
 	Size               int    `json:"size" toml:"size" comment:"Maximum number of transactions in the mempool"`
 	MaxPendingTxsBytes int64  `json:"max_pending_txs_bytes" toml:"max_pending_txs_bytes" comment:"Limit the total size of all txs in the mempool.\n This only accounts for raw transactions (e.g. given 1MB transactions and\n max_txs_bytes=5MB, mempool will only accept 5 transactions)."`
 	CacheSize          int    `json:"cache_size" toml:"cache_size" comment:"Size of the cache (used to filter transactions we saw earlier) in transactions"`
+	PriceBump          int64  `json:"price_bump" toml:"price_bump" comment:"Minimum priority increase, in percent, for a tx to replace a pending tx with the same sender and sequence (priority mempool only)"`
 }
 
 // DefaultMempoolConfig returns a default configuration for the Tendermint mempool
 func DefaultMempoolConfig() *MempoolConfig {
 	return &MempoolConfig{
+		Type:      MempoolTypePriority,
 		Recheck:   true,
 		Broadcast: true,
 		WalPath:   "",
@@ -27,6 +39,7 @@ func DefaultMempoolConfig() *MempoolConfig {
 		Size:               5000,
 		MaxPendingTxsBytes: 1024 * 1024 * 1024, // 1GB
 		CacheSize:          10000,
+		PriceBump:          10,
 	}
 }
 
@@ -59,5 +72,14 @@ func (cfg *MempoolConfig) ValidateBasic() error {
 	if cfg.CacheSize < 0 {
 		return errors.New("cache_size can't be negative")
 	}
+	switch cfg.Type {
+	case "", MempoolTypePriority, MempoolTypeCList:
+		// empty for the config files predating the mempool types
+	default:
+		return errors.New("unknown mempool type %q", cfg.Type)
+	}
+	if cfg.PriceBump < 0 {
+		return errors.New("price_bump can't be negative")
+	}
 	return nil
 }
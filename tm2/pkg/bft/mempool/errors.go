@@ -34,3 +34,14 @@ func (e MempoolIsFullError) Error() string {
 		e.numTxs, e.maxTxs,
 		e.txsBytes, e.maxTxsBytes)
 }
+
+// TxUnderpricedError means the tx doesn't bump enough the priority of the
+// pending tx it replaces, with the same sender and sequence
+type TxUnderpricedError struct {
+	priority    int64
+	minPriority int64
+}
+
+func (e TxUnderpricedError) Error() string {
+	return fmt.Sprintf("Tx underpriced. Replacing a pending tx requires a priority of %d, but got %d", e.minPriority, e.priority)
+}
@@ -32,6 +32,10 @@ type Mempool interface {
 	// transactions (~ all available transactions).
 	ReapMaxTxs(maxVal int) types.Txs
 
+	// EvictTxs removes up to maxVal of the last transactions to be reaped,
+	// i.e. the lowest-priority ones, from the mempool and returns them.
+	EvictTxs(maxVal int) types.Txs
+
 	// Lock locks the mempool. The consensus must be able to hold lock to safely update.
 	Lock()
 
@@ -4,6 +4,7 @@ package node
 // is enabled by the user by setting a profiling address
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net"
@@ -17,22 +18,27 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
 	"github.com/gnolang/gno/tm2/pkg/bft/privval"
 	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/file"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/kv"
 	"github.com/gnolang/gno/tm2/pkg/p2p/conn"
 	"github.com/gnolang/gno/tm2/pkg/p2p/discovery"
 	p2pTypes "github.com/gnolang/gno/tm2/pkg/p2p/types"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	bc "github.com/gnolang/gno/tm2/pkg/bft/blockchain"
 	cfg "github.com/gnolang/gno/tm2/pkg/bft/config"
 	cs "github.com/gnolang/gno/tm2/pkg/bft/consensus"
+	"github.com/gnolang/gno/tm2/pkg/bft/light"
 	mempl "github.com/gnolang/gno/tm2/pkg/bft/mempool"
 	"github.com/gnolang/gno/tm2/pkg/bft/proxy"
+	"github.com/gnolang/gno/tm2/pkg/bft/pruner"
 	rpccore "github.com/gnolang/gno/tm2/pkg/bft/rpc/core"
 	_ "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	rpcserver "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/server"
 	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
 	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
 	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/null"
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync"
 	"github.com/gnolang/gno/tm2/pkg/bft/store"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	tmtime "github.com/gnolang/gno/tm2/pkg/bft/types/time"
@@ -53,6 +59,7 @@ const (
 	blockchainReactorName = "BLOCKCHAIN"
 	consensusReactorName  = "CONSENSUS"
 	discoveryReactorName  = "DISCOVERY"
+	stateSyncReactorName  = "STATESYNC"
 )
 
 const (
@@ -61,6 +68,7 @@ const (
 	consensusModuleName  = "consensus"
 	p2pModuleName        = "p2p"
 	discoveryModuleName  = "discovery"
+	stateSyncModuleName  = "statesync"
 )
 
 // ------------------------------------------------------------------------------
@@ -178,9 +186,12 @@ type Node struct {
 	// services
 	evsw              events.EventSwitch
 	stateDB           dbm.DB
-	blockStore        *store.BlockStore // store the blockchain to disk
-	bcReactor         p2p.Reactor       // for fast-syncing
-	mempoolReactor    *mempl.Reactor    // for gossipping transactions
+	blockStore        *store.BlockStore  // store the blockchain to disk
+	bcReactor         p2p.Reactor        // for fast-syncing
+	stateSyncReactor  *statesync.Reactor // for serving and restoring snapshots
+	stateSync         bool               // whether to bootstrap the node with state sync
+	stateSyncGenesis  sm.State           // the genesis state, completed by state sync
+	mempoolReactor    *mempl.Reactor     // for gossipping transactions
 	mempool           mempl.Mempool
 	consensusState    *cs.ConsensusState   // latest consensus state
 	consensusReactor  *cs.ConsensusReactor // for participating in the consensus
@@ -188,6 +199,7 @@ type Node struct {
 	rpcListeners      []net.Listener       // rpc servers
 	txEventStore      eventstore.TxEventStore
 	eventStoreService *eventstore.Service
+	pruner            *pruner.Pruner // for pruning the old blocks, nil if disabled
 	firstBlockSignal  <-chan struct{}
 
 	earlyStart bool // start RPC+P2P before genesis time, defer only consensus
@@ -236,6 +248,12 @@ func createAndStartEventStoreService(
 		if err != nil {
 			return nil, nil, fmt.Errorf("unable to create file tx event store, %w", err)
 		}
+	case kv.EventStoreType:
+		// Transaction events should be indexed, and searchable over RPC
+		txEventStore, err = kv.NewTxEventStore(cfg.TxEventStore)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to create kv tx event store, %w", err)
+		}
 	default:
 		// Transaction event storing should be omitted
 		txEventStore = null.NewNullEventStore()
@@ -262,6 +280,19 @@ func doHandshake(stateDB dbm.DB, state sm.State, blockStore sm.BlockStore,
 	return nil
 }
 
+// shouldStateSync reports whether the node must be bootstrapped with state
+// sync: it is enabled, and neither the node nor the application have a state.
+func shouldStateSync(config *cfg.Config, state sm.State, proxyApp appconn.AppConns) (bool, error) {
+	if !config.StateSync.Enable || state.LastBlockHeight > 0 {
+		return false, nil
+	}
+	res, err := proxyApp.Query().InfoSync(abci.RequestInfo{})
+	if err != nil {
+		return false, fmt.Errorf("error calling Info: %w", err)
+	}
+	return res.LastBlockHeight == 0, nil
+}
+
 func logNodeStartupInfo(state sm.State, pubKey crypto.PubKey, logger, consensusLogger *slog.Logger) {
 	// Log the version info.
 	logger.Info("Version info",
@@ -410,22 +441,32 @@ func NewNode(config *cfg.Config,
 		return nil, err
 	}
 
-	// Create the handshaker, which calls RequestInfo, sets the AppVersion on the state,
-	// and replays any blocks as necessary to sync tendermint with the app.
+	// State sync only bootstraps nodes without any state.
 	consensusLogger := logger.With("module", consensusModuleName)
-	if err := doHandshake(stateDB, state, blockStore, genDoc, evsw, proxyApp, consensusLogger); err != nil {
+	stateSync, err := shouldStateSync(config, state, proxyApp)
+	if err != nil {
 		return nil, err
 	}
 
-	// Reload the state. It will have the Version.Consensus.App set by the
-	// Handshake, and may have other modifications as well (ie. depending on
-	// what happened during block replay).
-	state = sm.LoadState(stateDB)
+	if !stateSync {
+		// Create the handshaker, which calls RequestInfo, sets the AppVersion on the state,
+		// and replays any blocks as necessary to sync tendermint with the app.
+		if err := doHandshake(stateDB, state, blockStore, genDoc, evsw, proxyApp, consensusLogger); err != nil {
+			return nil, err
+		}
+
+		// Reload the state. It will have the Version.Consensus.App set by the
+		// Handshake, and may have other modifications as well (ie. depending on
+		// what happened during block replay).
+		state = sm.LoadState(stateDB)
+	}
 
 	logNodeStartupInfo(state, privValidator.PubKey(), logger, consensusLogger)
 
 	// Decide whether to fast-sync or not
 	// We don't fast-sync when the only validator is us.
+	// After state sync, the blockchain reactor is switched to fast sync, and
+	// consensus waits for it.
 	fastSync := config.FastSyncMode && !onlyValidatorIsUs(state, privValidator)
 
 	// Make MempoolReactor
@@ -442,7 +483,7 @@ func NewNode(config *cfg.Config,
 	// Make ConsensusReactor
 	consensusReactor, consensusState := createConsensusReactor(
 		config, state, blockExec, blockStore, mempool,
-		privValidator, fastSync, evsw, consensusLogger,
+		privValidator, fastSync || stateSync, evsw, consensusLogger,
 	)
 
 	// Make BlockchainReactor
@@ -450,7 +491,7 @@ func NewNode(config *cfg.Config,
 		state,
 		blockExec,
 		blockStore,
-		fastSync,
+		fastSync && !stateSync,
 		consensusReactor.SwitchToConsensus,
 		logger,
 	)
@@ -458,6 +499,10 @@ func NewNode(config *cfg.Config,
 		return nil, errors.Wrap(err, "could not create blockchain reactor")
 	}
 
+	// Make StateSyncReactor
+	stateSyncReactor := statesync.NewReactor(proxyApp.Snapshot(), proxyApp.Query())
+	stateSyncReactor.SetLogger(logger.With("module", stateSyncModuleName))
+
 	reactors := []nodeReactor{
 		{
 			mempoolReactorName, mempoolReactor,
@@ -468,6 +513,9 @@ func NewNode(config *cfg.Config,
 		{
 			consensusReactorName, consensusReactor,
 		},
+		{
+			stateSyncReactorName, stateSyncReactor,
+		},
 	}
 
 	nodeInfo, err := makeNodeInfo(config, nodeKey, txEventStore, genDoc, state)
@@ -547,6 +595,12 @@ func NewNode(config *cfg.Config,
 		}()
 	}
 
+	// Make the pruner of the old blocks and results, if enabled
+	var blockPruner *pruner.Pruner
+	if config.Pruning.Enabled() {
+		blockPruner = pruner.NewPruner(config.Pruning, stateDB, blockStore, logger.With("module", "pruner"))
+	}
+
 	node := &Node{
 		config:        config,
 		genesisDoc:    genDoc,
@@ -562,6 +616,9 @@ func NewNode(config *cfg.Config,
 		stateDB:           stateDB,
 		blockStore:        blockStore,
 		bcReactor:         bcReactor,
+		stateSyncReactor:  stateSyncReactor,
+		stateSync:         stateSync,
+		stateSyncGenesis:  state,
 		mempoolReactor:    mempoolReactor,
 		mempool:           mempool,
 		consensusState:    consensusState,
@@ -569,6 +626,7 @@ func NewNode(config *cfg.Config,
 		proxyApp:          proxyApp,
 		txEventStore:      txEventStore,
 		eventStoreService: eventStoreService,
+		pruner:            blockPruner,
 		firstBlockSignal:  cFirstBlock,
 	}
 	node.BaseService = *service.NewBaseService(logger, "Node", node)
@@ -656,6 +714,19 @@ func (n *Node) OnStart() error {
 	// Dial the persistent peers
 	n.sw.DialPeers(peerAddrs...)
 
+	if n.stateSync {
+		if err := n.startStateSync(); err != nil {
+			return fmt.Errorf("unable to start state sync, %w", err)
+		}
+	}
+
+	// Start pruning the old blocks in the background
+	if n.pruner != nil {
+		if err := n.pruner.Start(); err != nil {
+			return fmt.Errorf("unable to start the pruner, %w", err)
+		}
+	}
+
 	// If early start, wait for genesis time now (RPC+P2P already running).
 	if n.earlyStart {
 		now := tmtime.Now()
@@ -668,6 +739,55 @@ func (n *Node) OnStart() error {
 	return nil
 }
 
+// startStateSync restores a snapshot of the peers in the background, then
+// bootstraps the node at its height and fast syncs the next blocks.
+func (n *Node) startStateSync() error {
+	bcR, ok := n.bcReactor.(*bc.BlockchainReactor)
+	if !ok {
+		return errors.New("the blockchain reactor doesn't support state sync")
+	}
+	ssCfg := n.config.StateSync
+	provider, err := statesync.NewLightStateProvider(n.stateSyncGenesis, ssCfg.RPCServers, light.TrustOptions{
+		Period: ssCfg.TrustPeriod,
+		Height: ssCfg.TrustHeight,
+		Hash:   ssCfg.TrustHashBytes(),
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-n.Quit():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	go func() {
+		defer cancel()
+
+		state, commit, err := n.stateSyncReactor.Sync(ctx, provider, ssCfg)
+		if err != nil {
+			n.Logger.Error("State sync failed", "err", err)
+			return
+		}
+		if err := sm.BootstrapState(n.stateDB, state); err != nil {
+			n.Logger.Error("Failed to bootstrap the state", "err", err)
+			return
+		}
+		if err := n.blockStore.Bootstrap(state.LastBlockHeight, commit); err != nil {
+			n.Logger.Error("Failed to bootstrap the block store", "err", err)
+			return
+		}
+		if err := bcR.SwitchToFastSync(state); err != nil {
+			n.Logger.Error("Failed to switch to fast sync", "err", err)
+		}
+	}()
+	return nil
+}
+
 // OnStop stops the Node. It implements service.Service.
 func (n *Node) OnStop() {
 	n.BaseService.OnStop()
@@ -682,6 +802,9 @@ func (n *Node) OnStop() {
 	// Stop the non-reactor services
 	n.evsw.Stop()
 	n.eventStoreService.Stop()
+	if n.pruner != nil {
+		n.pruner.Stop()
+	}
 
 	// Stop the node p2p transport
 	if err := n.transport.Close(); err != nil {
@@ -721,6 +844,7 @@ func (n *Node) configureRPC() {
 	rpccore.SetBlockStore(n.blockStore)
 	rpccore.SetConsensusState(n.consensusState)
 	rpccore.SetMempool(n.mempool)
+	rpccore.SetTxEventStore(n.txEventStore)
 	rpccore.SetP2PPeers(n.sw)
 	rpccore.SetP2PTransport(n)
 	rpccore.SetPubKey(n.privValidator.PubKey())
@@ -766,7 +890,7 @@ func (n *Node) startRPC() (listeners []net.Listener, err error) {
 		wm := rpcserver.NewWebsocketManager(rpccore.Routes,
 			rpcserver.OnDisconnect(func(remoteAddr string) {
 				// any cleanup...
-				// (we used to unsubscribe from all event subscriptions)
+				// (event subscriptions are cancelled with the connection's context)
 			}),
 			rpcserver.ReadLimit(config.MaxBodyBytes),
 		)
@@ -929,6 +1053,7 @@ func makeNodeInfo(
 			bcChannel,
 			cs.StateChannel, cs.DataChannel, cs.VoteChannel, cs.VoteSetBitsChannel,
 			mempl.MempoolChannel,
+			statesync.SnapshotChannel, statesync.ChunkChannel,
 		},
 		Moniker: config.Moniker,
 		Other: p2pTypes.NodeInfoOther{
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/errors"
+)
+
+// -----------------------------------------------------------------------------
+// PruningConfig
+
+// PruningConfig defines the configuration of the node pruner, which deletes
+// the old blocks and block results in the background.
+type PruningConfig struct {
+	KeepRecentBlocks  int64         `json:"keep_recent_blocks" toml:"keep_recent_blocks" comment:"Number of recent blocks to keep in the block store (0 keeps all).\n The older blocks can't be served to the peers syncing from a lower height, nor queried over RPC."`
+	KeepRecentResults int64         `json:"keep_recent_results" toml:"keep_recent_results" comment:"Number of recent block results (ABCI responses) to keep, with their tx index (0 keeps all)"`
+	RetainHeight      int64         `json:"retain_height" toml:"retain_height" comment:"Floor of the retained heights: the blocks and results from this height are never pruned (0 for none)"`
+	Interval          time.Duration `json:"interval" toml:"interval" comment:"Interval between the pruning runs"`
+}
+
+// DefaultPruningConfig returns a default configuration for the pruner, which
+// keeps all the blocks and results
+func DefaultPruningConfig() *PruningConfig {
+	return &PruningConfig{
+		KeepRecentBlocks:  0,
+		KeepRecentResults: 0,
+		RetainHeight:      0,
+		Interval:          time.Minute,
+	}
+}
+
+// TestPruningConfig returns a configuration for testing the pruner
+func TestPruningConfig() *PruningConfig {
+	cfg := DefaultPruningConfig()
+	cfg.Interval = 100 * time.Millisecond
+	return cfg
+}
+
+// Enabled reports whether the pruner has anything to prune.
+func (cfg *PruningConfig) Enabled() bool {
+	return cfg.KeepRecentBlocks > 0 || cfg.KeepRecentResults > 0
+}
+
+// RetainHeights returns the heights from which the blocks and the results are
+// kept, given the latest height, or 0 if they are all kept.
+func (cfg *PruningConfig) RetainHeights(height int64) (blocks, results int64) {
+	retain := func(keep int64) int64 {
+		if keep == 0 || height <= keep {
+			return 0
+		}
+		retainHeight := height - keep + 1
+		if cfg.RetainHeight > 0 {
+			retainHeight = min(retainHeight, cfg.RetainHeight)
+		}
+		return retainHeight
+	}
+	return retain(cfg.KeepRecentBlocks), retain(cfg.KeepRecentResults)
+}
+
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
+func (cfg *PruningConfig) ValidateBasic() error {
+	if cfg.KeepRecentBlocks < 0 {
+		return errors.New("keep_recent_blocks can't be negative")
+	}
+	if cfg.KeepRecentResults < 0 {
+		return errors.New("keep_recent_results can't be negative")
+	}
+	if cfg.RetainHeight < 0 {
+		return errors.New("retain_height can't be negative")
+	}
+	if cfg.Interval < 0 {
+		return errors.New("interval can't be negative")
+	}
+	if cfg.Enabled() && cfg.Interval == 0 {
+		return errors.New("interval is required")
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+// Package pruner implements the background pruning of the blocks and block
+// results of a node, which would otherwise grow forever.
+package pruner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/pruner/config"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/service"
+	"github.com/gnolang/gno/tm2/pkg/telemetry"
+	"github.com/gnolang/gno/tm2/pkg/telemetry/metrics"
+)
+
+// batchHeights is the number of heights pruned between two saves of the
+// pruner progress.
+const batchHeights = 1000
+
+var stateKey = []byte("prunerState")
+
+// BlockStore is the block store interface used by the pruner.
+type BlockStore interface {
+	Base() int64
+	Height() int64
+	LoadBlock(height int64) *types.Block
+	PruneBlocks(retainHeight int64) (int64, error)
+}
+
+// State is the progress of the pruner, persisted in the state DB.
+type State struct {
+	// ResultsBase is the lowest height of the ABCI responses not yet pruned.
+	ResultsBase int64 `json:"results_base"`
+	// TxIndexBase is the lowest height of the tx result indexes not yet
+	// pruned.
+	TxIndexBase int64 `json:"tx_index_base"`
+}
+
+// LoadState returns the progress of the pruner saved in db, or the zero
+// value if it never ran.
+func LoadState(db dbm.DB) State {
+	var state State
+	bz, err := db.Get(stateKey)
+	if err != nil {
+		panic(err)
+	}
+	if len(bz) == 0 {
+		return state
+	}
+	if err := amino.UnmarshalJSON(bz, &state); err != nil {
+		panic(fmt.Sprintf("Could not unmarshal pruner state: %X", bz))
+	}
+	return state
+}
+
+func saveState(db dbm.DB, state State) error {
+	bz, err := amino.MarshalJSON(state)
+	if err != nil {
+		return err
+	}
+	return db.SetSync(stateKey, bz)
+}
+
+// Pruner periodically deletes the blocks and the block results older than the
+// retain heights of its config.
+//
+// The result index of a tx is deleted once either its block or its block
+// results are pruned, as both are needed to serve the tx. The index is pruned
+// before the blocks, from which the hashes of the txs are computed.
+type Pruner struct {
+	service.BaseService
+
+	cfg        *config.PruningConfig
+	stateDB    dbm.DB
+	blockStore BlockStore
+}
+
+// NewPruner returns a new pruner of the blocks of blockStore and of the
+// results saved in stateDB.
+func NewPruner(cfg *config.PruningConfig, stateDB dbm.DB, blockStore BlockStore, logger *slog.Logger) *Pruner {
+	p := &Pruner{
+		cfg:        cfg,
+		stateDB:    stateDB,
+		blockStore: blockStore,
+	}
+	p.BaseService = *service.NewBaseService(logger, "Pruner", p)
+	return p
+}
+
+// OnStart implements service.Service.
+func (p *Pruner) OnStart() error {
+	go p.pruneRoutine()
+	return nil
+}
+
+func (p *Pruner) pruneRoutine() {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.Quit():
+			return
+		case <-ticker.C:
+			if err := p.Prune(); err != nil {
+				p.Logger.Error("Failed to prune", "err", err)
+			}
+		}
+	}
+}
+
+// Prune deletes the blocks and results older than the retain heights of the
+// latest height. It returns early if the pruner is stopped.
+func (p *Pruner) Prune() error {
+	start := time.Now()
+
+	// The results of the last block are needed to recover from a crash, and
+	// the last block to reconstruct the last commit, so the latest height
+	// is the one of both the state and the block store.
+	height := min(p.blockStore.Height(), sm.LoadState(p.stateDB).LastBlockHeight)
+	blocksRetain, resultsRetain := p.cfg.RetainHeights(height)
+	if blocksRetain == 0 && resultsRetain == 0 {
+		return nil
+	}
+
+	state := LoadState(p.stateDB)
+	if state.ResultsBase == 0 {
+		// There are no blocks, so no results, below the base of a
+		// bootstrapped block store.
+		base := max(p.blockStore.Base(), 1)
+		state = State{ResultsBase: base, TxIndexBase: base}
+	}
+
+	var prunedResults, prunedBlocks int64
+	txIndexRetain := max(blocksRetain, resultsRetain)
+	for state.TxIndexBase < txIndexRetain && !p.stopped() {
+		to := min(state.TxIndexBase+batchHeights, txIndexRetain)
+		for h := state.TxIndexBase; h < to; h++ {
+			block := p.blockStore.LoadBlock(h)
+			if block == nil {
+				continue
+			}
+			if err := sm.PruneTxResultIndexes(p.stateDB, h, block.Txs); err != nil {
+				return fmt.Errorf("unable to prune the tx result indexes at height %d, %w", h, err)
+			}
+		}
+		state.TxIndexBase = to
+		if err := saveState(p.stateDB, state); err != nil {
+			return err
+		}
+	}
+
+	for state.ResultsBase < resultsRetain && !p.stopped() {
+		to := min(state.ResultsBase+batchHeights, resultsRetain)
+		if err := sm.PruneABCIResponses(p.stateDB, state.ResultsBase, to); err != nil {
+			return fmt.Errorf("unable to prune the results at heights %d-%d, %w", state.ResultsBase, to-1, err)
+		}
+		prunedResults += to - state.ResultsBase
+		state.ResultsBase = to
+		if err := saveState(p.stateDB, state); err != nil {
+			return err
+		}
+	}
+
+	if blocksRetain > 0 && !p.stopped() {
+		var err error
+		if prunedBlocks, err = p.blockStore.PruneBlocks(blocksRetain); err != nil {
+			return fmt.Errorf("unable to prune the blocks below height %d, %w", blocksRetain, err)
+		}
+	}
+
+	if prunedBlocks > 0 || prunedResults > 0 {
+		p.Logger.Info("Pruned",
+			"blocks", prunedBlocks,
+			"results", prunedResults,
+			"base", p.blockStore.Base(),
+			"results_base", state.ResultsBase,
+		)
+	}
+	logTelemetry(prunedBlocks, prunedResults, p.blockStore.Base(), time.Since(start))
+
+	return nil
+}
+
+// stopped reports whether the pruner was stopped, to return early.
+func (p *Pruner) stopped() bool {
+	select {
+	case <-p.Quit():
+		return true
+	default:
+		return false
+	}
+}
+
+func logTelemetry(prunedBlocks, prunedResults, base int64, duration time.Duration) {
+	if !telemetry.MetricsEnabled() {
+		return
+	}
+
+	ctx := context.Background()
+	metrics.PrunedBlocks.Add(ctx, prunedBlocks)
+	metrics.PrunedResults.Add(ctx, prunedResults)
+	metrics.PrunedBaseHeight.Record(ctx, base)
+	metrics.PruneTimer.Record(ctx, duration.Milliseconds())
+}
@@ -0,0 +1,112 @@
+package pruner
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/pruner/config"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/store"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/log"
+)
+
+// makeChain saves height blocks of a tx each, with their results and tx
+// result indexes.
+func makeChain(t *testing.T, height int64) (dbm.DB, *store.BlockStore) {
+	t.Helper()
+
+	var (
+		stateDB    = memdb.NewMemDB()
+		blockStore = store.NewBlockStore(memdb.NewMemDB())
+		lastCommit = new(types.Commit)
+	)
+	for h := int64(1); h <= height; h++ {
+		tx := types.Tx(fmt.Sprintf("tx %d", h))
+		block := types.MakeBlock(h, []types.Tx{tx}, lastCommit)
+		seenCommit := types.NewCommit(types.BlockID{}, []*types.CommitSig{{Height: h}})
+		blockStore.SaveBlock(block, block.MakePartSet(1024), seenCommit)
+		lastCommit = seenCommit
+
+		sm.SaveABCIResponses(stateDB, h, sm.NewABCIResponsesFromNum(1))
+		resultIndex := sm.TxResultIndex{BlockNum: h}
+		require.NoError(t, stateDB.Set(sm.CalcTxResultKey(tx.Hash()), resultIndex.Bytes()))
+	}
+	sm.SaveState(stateDB, sm.State{LastBlockHeight: height})
+	return stateDB, blockStore
+}
+
+func hasResults(db dbm.DB, height int64) bool {
+	_, err := sm.LoadABCIResponses(db, height)
+	return err == nil
+}
+
+func hasTxResultIndex(db dbm.DB, height int64) bool {
+	_, err := sm.LoadTxResultIndex(db, types.Tx(fmt.Sprintf("tx %d", height)).Hash())
+	return err == nil
+}
+
+func TestPrune(t *testing.T) {
+	t.Parallel()
+
+	stateDB, blockStore := makeChain(t, 20)
+	cfg := config.TestPruningConfig()
+	cfg.KeepRecentBlocks = 10
+	cfg.KeepRecentResults = 5
+	p := NewPruner(cfg, stateDB, blockStore, log.NewNoopLogger())
+
+	require.NoError(t, p.Prune())
+	assert.Equal(t, int64(11), blockStore.Base())
+	assert.Equal(t, State{ResultsBase: 16, TxIndexBase: 16}, LoadState(stateDB))
+	for h := int64(1); h <= 20; h++ {
+		assert.Equal(t, h >= 11, blockStore.LoadBlock(h) != nil, "block %d", h)
+		assert.Equal(t, h >= 16, hasResults(stateDB, h), "results %d", h)
+		assert.Equal(t, h >= 16, hasTxResultIndex(stateDB, h), "tx index %d", h)
+	}
+
+	// Nothing more to prune at the same height
+	require.NoError(t, p.Prune())
+	assert.Equal(t, int64(11), blockStore.Base())
+}
+
+func TestPrune_RetainHeight(t *testing.T) {
+	t.Parallel()
+
+	stateDB, blockStore := makeChain(t, 20)
+	cfg := config.TestPruningConfig()
+	cfg.KeepRecentBlocks = 1
+	cfg.RetainHeight = 8
+	p := NewPruner(cfg, stateDB, blockStore, log.NewNoopLogger())
+
+	require.NoError(t, p.Prune())
+	assert.Equal(t, int64(8), blockStore.Base())
+	for h := int64(1); h <= 20; h++ {
+		assert.Equal(t, h >= 8, blockStore.LoadBlock(h) != nil, "block %d", h)
+		assert.True(t, hasResults(stateDB, h), "results %d", h)
+		assert.Equal(t, h >= 8, hasTxResultIndex(stateDB, h), "tx index %d", h)
+	}
+}
+
+func TestPruner_Background(t *testing.T) {
+	t.Parallel()
+
+	stateDB, blockStore := makeChain(t, 20)
+	cfg := config.TestPruningConfig()
+	cfg.KeepRecentBlocks = 1
+	cfg.KeepRecentResults = 1
+	p := NewPruner(cfg, stateDB, blockStore, log.NewNoopLogger())
+	require.NoError(t, p.Start())
+	defer p.Stop()
+
+	require.Eventually(t, func() bool {
+		return blockStore.Base() == 20
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.True(t, hasResults(stateDB, 20))
+	assert.False(t, hasResults(stateDB, 19))
+}
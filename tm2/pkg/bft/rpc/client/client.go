@@ -38,6 +38,7 @@ const (
 	blockResultsMethod       = "block_results"
 	commitMethod             = "commit"
 	txMethod                 = "tx"
+	txSearchMethod           = "tx_search"
 	validatorsMethod         = "validators"
 )
 
@@ -331,6 +332,27 @@ func (c *RPCClient) Tx(ctx context.Context, hash []byte) (*ctypes.ResultTx, erro
 	)
 }
 
+func (c *RPCClient) TxSearch(
+	ctx context.Context,
+	query string,
+	page,
+	perPage int,
+	orderBy string,
+) (*ctypes.ResultTxSearch, error) {
+	return sendRequestCommon[ctypes.ResultTxSearch](
+		ctx,
+		c.requestTimeout,
+		c.caller,
+		txSearchMethod,
+		map[string]any{
+			"query":    query,
+			"page":     page,
+			"per_page": perPage,
+			"order_by": orderBy,
+		},
+	)
+}
+
 func (c *RPCClient) Validators(ctx context.Context, height *int64) (*ctypes.ResultValidators, error) {
 	params := map[string]any{}
 	if height != nil {
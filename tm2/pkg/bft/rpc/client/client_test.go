@@ -761,6 +761,52 @@ func TestRPCClient_Tx(t *testing.T) {
 	assert.Equal(t, expectedResult, result)
 }
 
+func TestRPCClient_TxSearch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		query   = "tx.height = 10"
+		page    = 2
+		perPage = 5
+		orderBy = "desc"
+
+		expectedResult = &ctypes.ResultTxSearch{
+			Txs: []*ctypes.ResultTx{
+				{
+					Hash:   []byte("tx hash"),
+					Height: 10,
+				},
+			},
+			TotalCount: 6,
+		}
+
+		verifyFn = func(t *testing.T, params map[string]any) {
+			t.Helper()
+
+			assert.Equal(t, query, params["query"])
+			assert.Equal(t, fmt.Sprintf("%d", page), params["page"])
+			assert.Equal(t, fmt.Sprintf("%d", perPage), params["per_page"])
+			assert.Equal(t, orderBy, params["order_by"])
+		}
+
+		mockClient = generateMockRequestClient(
+			t,
+			txSearchMethod,
+			verifyFn,
+			expectedResult,
+		)
+	)
+
+	// Create the client
+	c := NewRPCClient(mockClient)
+
+	// Get the result
+	result, err := c.TxSearch(context.Background(), query, page, perPage, orderBy)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedResult, result)
+}
+
 func TestRPCClient_Validators(t *testing.T) {
 	t.Parallel()
 
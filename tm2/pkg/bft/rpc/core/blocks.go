@@ -79,6 +79,10 @@ func BlockchainInfo(ctx *rpctypes.Context, minHeight, maxHeight int64) (*ctypes.
 	// maximum 20 block metas
 	const limit int64 = 20
 	var err error
+	// the blocks below the base were pruned
+	if base := blockStore.Base(); minHeight >= 0 && minHeight < base {
+		minHeight = base
+	}
 	minHeight, maxHeight, err = filterMinMax(blockStore.Height(), minHeight, maxHeight, limit)
 	if err != nil {
 		return nil, err
@@ -240,8 +244,7 @@ func filterMinMax(height, low, high, limit int64) (int64, int64, error) {
 func Block(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultBlock, error) {
 	_, span := traces.Tracer().Start(ctx.Context(), "Block")
 	defer span.End()
-	storeHeight := blockStore.Height()
-	height, err := getHeight(storeHeight, heightPtr)
+	height, err := getStoredHeight(heightPtr)
 	if err != nil {
 		return nil, err
 	}
@@ -334,7 +337,7 @@ func Commit(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultCommit, erro
 	_, span := traces.Tracer().Start(ctx.Context(), "Commit")
 	defer span.End()
 	storeHeight := blockStore.Height()
-	height, err := getHeight(storeHeight, heightPtr)
+	height, err := getStoredHeight(heightPtr)
 	if err != nil {
 		return nil, err
 	}
@@ -430,6 +433,19 @@ func getHeight(currentHeight int64, heightPtr *int64) (int64, error) {
 	return getHeightWithMin(currentHeight, heightPtr, 1)
 }
 
+// getStoredHeight returns the height of heightPtr like getHeight, or an error
+// if the block at this height was pruned from the block store.
+func getStoredHeight(heightPtr *int64) (int64, error) {
+	height, err := getHeight(blockStore.Height(), heightPtr)
+	if err != nil {
+		return 0, err
+	}
+	if base := blockStore.Base(); height < base {
+		return 0, fmt.Errorf("height %d is not available, the lowest height is %d", height, base)
+	}
+	return height, nil
+}
+
 func getHeightWithMin(currentHeight int64, heightPtr *int64, minVal int64) (int64, error) {
 	if heightPtr != nil {
 		height := *heightPtr
@@ -0,0 +1,414 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/events"
+	"github.com/gnolang/gno/tm2/pkg/random"
+	"github.com/gnolang/gno/tm2/pkg/telemetry/traces"
+)
+
+// Kinds of events which can be subscribed to.
+const (
+	// SubscribeNewBlock delivers every committed block (types.EventNewBlock).
+	SubscribeNewBlock = "new_block"
+	// SubscribeTx delivers the result of every committed transaction
+	// (types.EventTx).
+	SubscribeTx = "tx"
+	// SubscribeEvent delivers, one by one, the ABCI events emitted by
+	// committed transactions (e.g. the events emitted by chain.Emit).
+	SubscribeEvent = "event"
+)
+
+const (
+	maxSubscriptionsPerClient = 100
+	subscriptionBufferSize    = 100
+)
+
+var (
+	errWSOnly              = errors.New("subscriptions are only available through the websocket endpoint")
+	errTooManySubscription = fmt.Errorf("max subscriptions per client reached (%d)", maxSubscriptionsPerClient)
+	errSubscriptionFilter  = errors.New("new_block subscriptions cannot be filtered")
+)
+
+// Subscribe to events over the websocket.
+//
+// The event argument is one of "new_block", "tx" or "event". Subscriptions to
+// "tx" and "event" can be restricted to transactions / ABCI events matching
+// the given event type, package path and attributes. Attributes are given as
+// "key=value" strings, which must all be matched by an event. Matching is
+// performed on the JSON representation of the events, using the "type",
+// "pkg_path" and "attrs" fields used by Gno events.
+//
+// Each event is sent as a JSON-RPC response using the ID of the subscribe
+// request, and contains the subscription ID. If the client doesn't read
+// events fast enough, the subscription is cancelled and an error is sent.
+//
+// ```shell
+// wscat -c 'ws://localhost:26657/websocket'
+// > { "jsonrpc": "2.0", "method": "subscribe", "params": ["event", "transfer", "gno.land/r/demo/foo20", ["to=g1..."]], "id": 1 }
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+//
+//	{
+//		"jsonrpc": "2.0",
+//		"id": 1,
+//		"result": {
+//			"id": "HmjzBCa3mGqP"
+//		}
+//	}
+//
+// ```
+//
+// Followed by events:
+//
+// ```json
+//
+//	{
+//		"jsonrpc": "2.0",
+//		"id": 1,
+//		"result": {
+//			"subscription_id": "HmjzBCa3mGqP",
+//			"height": "42",
+//			"tx_hash": "...",
+//			"tx_event": {
+//				"@type": "/tm.Event",
+//				"type": "transfer",
+//				"attrs": [{"key": "to", "value": "g1..."}],
+//				"pkg_path": "gno.land/r/demo/foo20"
+//			}
+//		}
+//	}
+//
+// ```
+func Subscribe(ctx *rpctypes.Context, event, eventType, pkgPath string, attrs []string) (*ctypes.ResultSubscribe, error) {
+	_, span := traces.Tracer().Start(ctx.Context(), "Subscribe")
+	defer span.End()
+
+	if ctx.WSConn == nil {
+		return nil, errWSOnly
+	}
+
+	filter, err := newEventFilter(eventType, pkgPath, attrs)
+	if err != nil {
+		return nil, err
+	}
+	switch event {
+	case SubscribeNewBlock:
+		if !filter.isZero() {
+			return nil, errSubscriptionFilter
+		}
+	case SubscribeTx, SubscribeEvent:
+	default:
+		return nil, fmt.Errorf("unknown event kind %q, expected one of: %s, %s, %s",
+			event, SubscribeNewBlock, SubscribeTx, SubscribeEvent)
+	}
+
+	sub := &subscription{
+		id:     random.RandStr(12),
+		kind:   event,
+		filter: filter,
+		reqID:  ctx.JSONReq.ID,
+		conn:   ctx.WSConn,
+		quit:   make(chan struct{}),
+	}
+	if err := gSubscriptions.add(sub); err != nil {
+		return nil, err
+	}
+	sub.start(evsw)
+
+	return &ctypes.ResultSubscribe{ID: sub.id}, nil
+}
+
+// Unsubscribe from the subscription with the given ID.
+//
+// ```shell
+// wscat -c 'ws://localhost:26657/websocket'
+// > { "jsonrpc": "2.0", "method": "unsubscribe", "params": ["HmjzBCa3mGqP"], "id": 2 }
+// ```
+func Unsubscribe(ctx *rpctypes.Context, id string) (*ctypes.ResultUnsubscribe, error) {
+	_, span := traces.Tracer().Start(ctx.Context(), "Unsubscribe")
+	defer span.End()
+
+	if ctx.WSConn == nil {
+		return nil, errWSOnly
+	}
+	sub := gSubscriptions.remove(ctx.WSConn.GetRemoteAddr(), id)
+	if sub == nil {
+		return nil, fmt.Errorf("subscription %q not found", id)
+	}
+	sub.stop(evsw)
+	return &ctypes.ResultUnsubscribe{}, nil
+}
+
+// UnsubscribeAll cancels all the subscriptions of the client.
+//
+// ```shell
+// wscat -c 'ws://localhost:26657/websocket'
+// > { "jsonrpc": "2.0", "method": "unsubscribe_all", "params": [], "id": 3 }
+// ```
+func UnsubscribeAll(ctx *rpctypes.Context) (*ctypes.ResultUnsubscribe, error) {
+	_, span := traces.Tracer().Start(ctx.Context(), "UnsubscribeAll")
+	defer span.End()
+
+	if ctx.WSConn == nil {
+		return nil, errWSOnly
+	}
+	for _, sub := range gSubscriptions.removeAll(ctx.WSConn.GetRemoteAddr()) {
+		sub.stop(evsw)
+	}
+	return &ctypes.ResultUnsubscribe{}, nil
+}
+
+// ----------------------------------------
+// subscriptions
+
+var gSubscriptions = &subscriptions{
+	byClient: make(map[string]map[string]*subscription),
+}
+
+// subscriptions keeps track of the subscriptions of each websocket client,
+// identified by its remote address.
+type subscriptions struct {
+	mtx      sync.Mutex
+	byClient map[string]map[string]*subscription
+}
+
+func (ss *subscriptions) add(sub *subscription) error {
+	ss.mtx.Lock()
+	defer ss.mtx.Unlock()
+
+	client := sub.conn.GetRemoteAddr()
+	subs := ss.byClient[client]
+	if subs == nil {
+		subs = make(map[string]*subscription)
+		ss.byClient[client] = subs
+	}
+	if len(subs) >= maxSubscriptionsPerClient {
+		return errTooManySubscription
+	}
+	subs[sub.id] = sub
+	return nil
+}
+
+func (ss *subscriptions) remove(client, id string) *subscription {
+	ss.mtx.Lock()
+	defer ss.mtx.Unlock()
+
+	sub := ss.byClient[client][id]
+	if sub == nil {
+		return nil
+	}
+	delete(ss.byClient[client], id)
+	if len(ss.byClient[client]) == 0 {
+		delete(ss.byClient, client)
+	}
+	return sub
+}
+
+func (ss *subscriptions) removeAll(client string) []*subscription {
+	ss.mtx.Lock()
+	defer ss.mtx.Unlock()
+
+	subs := make([]*subscription, 0, len(ss.byClient[client]))
+	for _, sub := range ss.byClient[client] {
+		subs = append(subs, sub)
+	}
+	delete(ss.byClient, client)
+	return subs
+}
+
+type subscription struct {
+	id     string
+	kind   string
+	filter eventFilter
+	reqID  rpctypes.JSONRPCID
+	conn   rpctypes.WSRPCConnection
+
+	quit     chan struct{}
+	stopOnce sync.Once
+}
+
+func (sub *subscription) listenerID() string {
+	return fmt.Sprintf("rpc-subscription#%s#%s", sub.conn.GetRemoteAddr(), sub.id)
+}
+
+// start registers the subscription on the event switch, and starts
+// forwarding the events to the websocket connection.
+func (sub *subscription) start(evsw events.EventSwitch) {
+	var filter events.EventFilter
+	switch sub.kind {
+	case SubscribeNewBlock:
+		filter = func(ev events.Event) bool {
+			_, ok := ev.(types.EventNewBlock)
+			return ok
+		}
+	case SubscribeTx, SubscribeEvent:
+		filter = func(ev events.Event) bool {
+			txev, ok := ev.(types.EventTx)
+			return ok && sub.filter.matchAny(txev.Result.Response.Events)
+		}
+	}
+	ch := events.SubscribeFilteredOn(evsw, sub.listenerID(), filter,
+		make(chan events.Event, subscriptionBufferSize))
+
+	go sub.forwardRoutine(evsw, ch)
+}
+
+func (sub *subscription) stop(evsw events.EventSwitch) {
+	sub.stopOnce.Do(func() {
+		evsw.RemoveListener(sub.listenerID())
+		close(sub.quit)
+	})
+}
+
+func (sub *subscription) forwardRoutine(evsw events.EventSwitch, ch <-chan events.Event) {
+	done := sub.conn.Context().Done()
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				// The event switch dropped the subscription, as the
+				// client isn't reading fast enough.
+				gSubscriptions.remove(sub.conn.GetRemoteAddr(), sub.id)
+				sub.stop(evsw)
+				sub.conn.TryWriteRPCResponses(rpctypes.RPCResponses{
+					rpctypes.RPCInternalError(sub.reqID,
+						fmt.Errorf("subscription %s was cancelled: client is not reading events fast enough", sub.id)),
+				})
+				return
+			}
+			for _, res := range sub.results(ev) {
+				sub.conn.WriteRPCResponses(rpctypes.RPCResponses{
+					rpctypes.NewRPCSuccessResponse(sub.reqID, res),
+				})
+			}
+		case <-done:
+			// The client disconnected.
+			gSubscriptions.remove(sub.conn.GetRemoteAddr(), sub.id)
+			sub.stop(evsw)
+			return
+		case <-sub.quit:
+			return
+		}
+	}
+}
+
+// results converts an event from the event switch into the results sent to
+// the client.
+func (sub *subscription) results(ev events.Event) []*ctypes.ResultEvent {
+	switch sub.kind {
+	case SubscribeNewBlock:
+		nb := ev.(types.EventNewBlock)
+		return []*ctypes.ResultEvent{{
+			SubscriptionID: sub.id,
+			Height:         nb.Block.Height,
+			Event:          nb,
+		}}
+	case SubscribeTx:
+		txev := ev.(types.EventTx)
+		return []*ctypes.ResultEvent{{
+			SubscriptionID: sub.id,
+			Height:         txev.Result.Height,
+			TxHash:         txev.Result.Tx.Hash(),
+			Event:          txev,
+		}}
+	case SubscribeEvent:
+		txev := ev.(types.EventTx)
+		var res []*ctypes.ResultEvent
+		for _, abciev := range txev.Result.Response.Events {
+			if !sub.filter.match(abciev) {
+				continue
+			}
+			res = append(res, &ctypes.ResultEvent{
+				SubscriptionID: sub.id,
+				Height:         txev.Result.Height,
+				TxHash:         txev.Result.Tx.Hash(),
+				TxEvent:        abciev,
+			})
+		}
+		return res
+	default:
+		panic("should not happen")
+	}
+}
+
+// ----------------------------------------
+// eventFilter
+
+// eventFilter matches ABCI events on their type, package path and
+// attributes. A zero eventFilter matches everything.
+type eventFilter struct {
+	eventType string
+	pkgPath   string
+	attrs     map[string]string
+}
+
+func newEventFilter(eventType, pkgPath string, attrs []string) (eventFilter, error) {
+	f := eventFilter{
+		eventType: eventType,
+		pkgPath:   pkgPath,
+	}
+	for _, attr := range attrs {
+		key, value, ok := strings.Cut(attr, "=")
+		if !ok || key == "" {
+			return eventFilter{}, fmt.Errorf("invalid attribute filter %q, expected key=value", attr)
+		}
+		if f.attrs == nil {
+			f.attrs = make(map[string]string, len(attrs))
+		}
+		f.attrs[key] = value
+	}
+	return f, nil
+}
+
+func (f eventFilter) isZero() bool {
+	return f.eventType == "" && f.pkgPath == "" && len(f.attrs) == 0
+}
+
+// matchAny returns true if at least one of evs matches. An empty filter
+// always matches, even if there are no events.
+func (f eventFilter) matchAny(evs []abci.Event) bool {
+	if f.isZero() {
+		return true
+	}
+	for _, ev := range evs {
+		if f.match(ev) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f eventFilter) match(ev abci.Event) bool {
+	if f.isZero() {
+		return true
+	}
+	fev, err := eventstore.ParseEvent(ev)
+	if err != nil {
+		return false
+	}
+	if f.eventType != "" && f.eventType != fev.Type {
+		return false
+	}
+	if f.pkgPath != "" && f.pkgPath != fev.PkgPath {
+		return false
+	}
+	for key, value := range f.attrs {
+		if !fev.HasAttribute(key, value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,226 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWSConn struct {
+	addr string
+	ctx  context.Context
+
+	mu        sync.Mutex
+	responses rpctypes.RPCResponses
+}
+
+func (c *mockWSConn) GetRemoteAddr() string    { return c.addr }
+func (c *mockWSConn) Context() context.Context { return c.ctx }
+
+func (c *mockWSConn) WriteRPCResponses(resp rpctypes.RPCResponses) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.responses = append(c.responses, resp...)
+}
+
+func (c *mockWSConn) TryWriteRPCResponses(resp rpctypes.RPCResponses) bool {
+	c.WriteRPCResponses(resp)
+	return true
+}
+
+func (c *mockWSConn) getResponses() rpctypes.RPCResponses {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append(rpctypes.RPCResponses(nil), c.responses...)
+}
+
+// testEvent mimics the JSON structure of Gno events.
+type testEvent struct {
+	Type    string           `json:"type"`
+	Attrs   []testEventAttrs `json:"attrs"`
+	PkgPath string           `json:"pkg_path"`
+}
+
+type testEventAttrs struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func (testEvent) AssertABCIEvent() {}
+
+func TestEventFilter(t *testing.T) {
+	t.Parallel()
+
+	ev := testEvent{
+		Type:    "transfer",
+		PkgPath: "gno.land/r/demo/foo20",
+		Attrs: []testEventAttrs{
+			{Key: "from", Value: "alice"},
+			{Key: "to", Value: "bob"},
+		},
+	}
+
+	cases := []struct {
+		name      string
+		eventType string
+		pkgPath   string
+		attrs     []string
+		match     bool
+	}{
+		{"empty", "", "", nil, true},
+		{"type", "transfer", "", nil, true},
+		{"wrong type", "mint", "", nil, false},
+		{"pkg path", "", "gno.land/r/demo/foo20", nil, true},
+		{"wrong pkg path", "", "gno.land/r/demo/bar20", nil, false},
+		{"attrs", "transfer", "", []string{"to=bob", "from=alice"}, true},
+		{"wrong attr value", "", "", []string{"to=alice"}, false},
+		{"missing attr", "", "", []string{"amount=10"}, false},
+		{"empty attr value", "", "", []string{"to="}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := newEventFilter(tc.eventType, tc.pkgPath, tc.attrs)
+			require.NoError(t, err)
+			assert.Equal(t, tc.match, f.match(ev))
+			assert.Equal(t, tc.match, f.matchAny([]abci.Event{abci.EventString("x"), ev}))
+		})
+	}
+
+	t.Run("invalid attr", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newEventFilter("", "", []string{"foo"})
+		require.Error(t, err)
+	})
+}
+
+func TestSubscribe(t *testing.T) {
+	// Tests are not run in parallel because the JSON-RPC
+	// handlers utilize global package-level variables.
+	newContext := func(t *testing.T, addr string) (*rpctypes.Context, *mockWSConn, context.CancelFunc) {
+		t.Helper()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		conn := &mockWSConn{addr: addr, ctx: ctx}
+		return &rpctypes.Context{
+			JSONReq: &rpctypes.RPCRequest{ID: rpctypes.JSONRPCIntID(1)},
+			WSConn:  conn,
+		}, conn, cancel
+	}
+
+	t.Run("websocket only", func(t *testing.T) {
+		_, err := Subscribe(&rpctypes.Context{}, SubscribeNewBlock, "", "", nil)
+		assert.ErrorIs(t, err, errWSOnly)
+	})
+
+	t.Run("invalid subscriptions", func(t *testing.T) {
+		ctx, _, _ := newContext(t, "invalid")
+
+		_, err := Subscribe(ctx, "unknown", "", "", nil)
+		assert.Error(t, err)
+		_, err = Subscribe(ctx, SubscribeNewBlock, "transfer", "", nil)
+		assert.ErrorIs(t, err, errSubscriptionFilter)
+	})
+
+	t.Run("tx events", func(t *testing.T) {
+		sw := events.NewEventSwitch()
+		SetEventSwitch(sw)
+		ctx, conn, _ := newContext(t, "tx-events")
+
+		res, err := Subscribe(ctx, SubscribeEvent, "", "", nil)
+		require.NoError(t, err)
+		require.NotEmpty(t, res.ID)
+
+		sw.FireEvent(types.EventTx{Result: types.TxResult{
+			Height: 10,
+			Tx:     types.Tx("tx"),
+			Response: abci.ResponseDeliverTx{
+				ResponseBase: abci.ResponseBase{
+					Events: []abci.Event{abci.EventString("a"), abci.EventString("b")},
+				},
+			},
+		}})
+
+		require.Eventually(t, func() bool {
+			return len(conn.getResponses()) == 2
+		}, time.Second, 10*time.Millisecond)
+		for i, resp := range conn.getResponses() {
+			require.Nil(t, resp.Error)
+			assert.Equal(t, rpctypes.JSONRPCIntID(1), resp.ID)
+
+			var result struct {
+				SubscriptionID string          `json:"subscription_id"`
+				Height         string          `json:"height"`
+				TxEvent        json.RawMessage `json:"tx_event"`
+			}
+			require.NoError(t, json.Unmarshal(resp.Result, &result))
+			assert.Equal(t, res.ID, result.SubscriptionID)
+			assert.Equal(t, "10", result.Height)
+			assert.Contains(t, string(result.TxEvent), []string{`"a"`, `"b"`}[i])
+		}
+
+		_, err = Unsubscribe(ctx, res.ID)
+		require.NoError(t, err)
+		_, err = Unsubscribe(ctx, res.ID)
+		require.Error(t, err)
+	})
+
+	t.Run("unsubscribe all", func(t *testing.T) {
+		sw := events.NewEventSwitch()
+		SetEventSwitch(sw)
+		ctx, conn, _ := newContext(t, "unsubscribe-all")
+
+		for range 3 {
+			_, err := Subscribe(ctx, SubscribeNewBlock, "", "", nil)
+			require.NoError(t, err)
+		}
+		_, err := UnsubscribeAll(ctx)
+		require.NoError(t, err)
+
+		sw.FireEvent(types.EventNewBlock{Block: &types.Block{}})
+		assert.Empty(t, conn.getResponses())
+		assert.Empty(t, gSubscriptions.removeAll("unsubscribe-all"))
+	})
+
+	t.Run("disconnect", func(t *testing.T) {
+		SetEventSwitch(events.NewEventSwitch())
+		ctx, _, cancel := newContext(t, "disconnect")
+
+		_, err := Subscribe(ctx, SubscribeTx, "", "", nil)
+		require.NoError(t, err)
+		cancel()
+
+		require.Eventually(t, func() bool {
+			gSubscriptions.mtx.Lock()
+			defer gSubscriptions.mtx.Unlock()
+			return len(gSubscriptions.byClient["disconnect"]) == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("max subscriptions", func(t *testing.T) {
+		SetEventSwitch(events.NewEventSwitch())
+		ctx, _, _ := newContext(t, "max")
+
+		for range maxSubscriptionsPerClient {
+			_, err := Subscribe(ctx, SubscribeNewBlock, "", "", nil)
+			require.NoError(t, err)
+		}
+		_, err := Subscribe(ctx, SubscribeNewBlock, "", "", nil)
+		assert.ErrorIs(t, err, errTooManySubscription)
+
+		_, err = UnsubscribeAll(ctx)
+		require.NoError(t, err)
+	})
+}
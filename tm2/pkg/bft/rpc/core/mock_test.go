@@ -4,6 +4,7 @@ import "github.com/gnolang/gno/tm2/pkg/bft/types"
 
 type (
 	heightDelegate          func() int64
+	baseDelegate            func() int64
 	loadBlockMetaDelegate   func(int64) *types.BlockMeta
 	loadBlockDelegate       func(int64) *types.Block
 	loadBlockPartDelegate   func(int64, int) *types.Part
@@ -15,6 +16,7 @@ type (
 
 type mockBlockStore struct {
 	heightFn          heightDelegate
+	baseFn            baseDelegate
 	loadBlockMetaFn   loadBlockMetaDelegate
 	loadBlockFn       loadBlockDelegate
 	loadBlockPartFn   loadBlockPartDelegate
@@ -31,6 +33,14 @@ func (m *mockBlockStore) Height() int64 {
 	return 0
 }
 
+func (m *mockBlockStore) Base() int64 {
+	if m.baseFn != nil {
+		return m.baseFn()
+	}
+
+	return 0
+}
+
 func (m *mockBlockStore) LoadBlockMeta(height int64) *types.BlockMeta {
 	if m.loadBlockMetaFn != nil {
 		return m.loadBlockMetaFn(height)
@@ -1,11 +1,14 @@
 package core
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
 	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
 	"github.com/gnolang/gno/tm2/pkg/telemetry/traces"
 )
 
@@ -21,8 +24,13 @@ func Tx(ctx *rpctypes.Context, hash []byte) (*ctypes.ResultTx, error) {
 		return nil, err
 	}
 
+	return loadResultTx(resultIndex.BlockNum, resultIndex.TxIndex)
+}
+
+// loadResultTx loads the transaction at the given position, and its result
+func loadResultTx(txHeight int64, txIndex uint32) (*ctypes.ResultTx, error) {
 	// Sanity check the block height
-	height, err := getHeight(blockStore.Height(), &resultIndex.BlockNum)
+	height, err := getStoredHeight(&txHeight)
 	if err != nil {
 		return nil, err
 	}
@@ -31,39 +39,125 @@ func Tx(ctx *rpctypes.Context, hash []byte) (*ctypes.ResultTx, error) {
 	block := blockStore.LoadBlock(height)
 	numTxs := len(block.Txs)
 
-	if int(resultIndex.TxIndex) > numTxs || numTxs == 0 {
+	if int(txIndex) >= numTxs {
 		return nil, fmt.Errorf(
 			"unable to get block transaction for block %d, index %d",
-			resultIndex.BlockNum,
-			resultIndex.TxIndex,
+			txHeight,
+			txIndex,
 		)
 	}
 
-	rawTx := block.Txs[resultIndex.TxIndex]
+	rawTx := block.Txs[txIndex]
 
 	// Fetch the block results
-	blockResults, err := sm.LoadABCIResponses(stateDB, resultIndex.BlockNum)
+	blockResults, err := sm.LoadABCIResponses(stateDB, txHeight)
 	if err != nil {
 		return nil, fmt.Errorf("unable to load block results, %w", err)
 	}
 
 	// Grab the block deliver response
-	if len(blockResults.DeliverTxs) < int(resultIndex.TxIndex) {
+	if len(blockResults.DeliverTxs) <= int(txIndex) {
 		return nil, fmt.Errorf(
 			"unable to get deliver result for block %d, index %d",
-			resultIndex.BlockNum,
-			resultIndex.TxIndex,
+			txHeight,
+			txIndex,
 		)
 	}
 
-	deliverResponse := blockResults.DeliverTxs[resultIndex.TxIndex]
+	deliverResponse := blockResults.DeliverTxs[txIndex]
 
 	// Craft the response
 	return &ctypes.ResultTx{
-		Hash:     hash,
-		Height:   resultIndex.BlockNum,
-		Index:    resultIndex.TxIndex,
+		Hash:     rawTx.Hash(),
+		Height:   txHeight,
+		Index:    txIndex,
 		TxResult: deliverResponse,
 		Tx:       rawTx,
 	}, nil
 }
+
+// TxSearch allows you to search for transactions, using the transaction
+// index of the node (requires the "kv" tx event store).
+//
+// The query is made of conditions joined by AND. Transactions can be searched
+// by block height (tx.height, which also supports <, <=, > and >=), signer
+// address (tx.signer), message type (tx.msg_type), realm or package path
+// (tx.pkg_path), emitted event type (tx.event) and emitted event attributes
+// (<event type>.<attribute key>).
+//
+// Results are paginated, and ordered by position in the chain; order_by can
+// be "asc" (default) or "desc". At most maxTxSearchResults transactions are
+// counted, and can be paged through: narrow down the query (e.g. with a
+// tx.height range) to reach further results.
+//
+// ```shell
+// curl 'localhost:26657/tx_search?query="tx.pkg_path=%27gno.land/r/demo/foo20%27%20AND%20Transfer.to=%27g1...%27"&page=1&per_page=30'
+// ```
+func TxSearch(ctx *rpctypes.Context, query string, page, perPage int, orderBy string) (*ctypes.ResultTxSearch, error) {
+	_, span := traces.Tracer().Start(ctx.Context(), "TxSearch")
+	defer span.End()
+
+	searcher, ok := txEventStore.(eventstore.TxSearcher)
+	if !ok {
+		return nil, errors.New("transaction indexing is disabled")
+	}
+
+	q, err := eventstore.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse query, %w", err)
+	}
+
+	// The transactions of the pruned blocks are still indexed,
+	// but can't be loaded anymore
+	if base := blockStore.Base(); base > 1 {
+		q.Conditions = append(q.Conditions, eventstore.Condition{
+			Key:   eventstore.QueryKeyHeight,
+			Op:    eventstore.OpGreaterOrEqual,
+			Value: strconv.FormatInt(base, 10),
+		})
+	}
+
+	var desc bool
+
+	switch orderBy {
+	case "", "asc":
+	case "desc":
+		desc = true
+	default:
+		return nil, fmt.Errorf("invalid order_by %q, expected asc or desc", orderBy)
+	}
+
+	// Paginate the results while searching
+	perPage = validatePerPage(perPage)
+
+	res, err := searcher.Search(q, eventstore.SearchOptions{
+		Desc:     desc,
+		Skip:     (max(page, 1) - 1) * perPage,
+		Limit:    perPage,
+		MaxCount: maxTxSearchResults,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to search transactions, %w", err)
+	}
+
+	totalCount := res.TotalCount
+
+	if _, err := validatePage(page, perPage, totalCount); err != nil {
+		return nil, err
+	}
+
+	txs := make([]*ctypes.ResultTx, 0, len(res.Positions))
+	for _, pos := range res.Positions {
+		tx, err := loadResultTx(pos.Height, pos.Index)
+		if err != nil {
+			return nil, err
+		}
+
+		txs = append(txs, tx)
+	}
+
+	return &ctypes.ResultTxSearch{
+		Txs:        txs,
+		TotalCount: totalCount,
+	}, nil
+}
@@ -1,12 +1,15 @@
 package core
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/null"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/db/memdb"
 	"github.com/gnolang/gno/tm2/pkg/std"
@@ -220,4 +223,221 @@ func TestTxHandler(t *testing.T) {
 
 		assert.ErrorContains(t, err, "unable to load block results")
 	})
+
+	t.Run("pruned block", func(t *testing.T) {
+		var (
+			height = int64(10)
+
+			txResultIndex = state.TxResultIndex{
+				BlockNum: height,
+				TxIndex:  0,
+			}
+			hash = []byte("hash")
+		)
+
+		// Prepare the DB
+		sdb := memdb.NewMemDB()
+
+		// Save the result index to the DB
+		sdb.Set(state.CalcTxResultKey(hash), txResultIndex.Bytes())
+
+		// Set the GLOBALLY referenced db
+		SetStateDB(sdb)
+
+		// Set the GLOBALLY referenced blockstore, pruned up to the tx height
+		blockStore := &mockBlockStore{
+			heightFn: func() int64 {
+				return 20
+			},
+			baseFn: func() int64 {
+				return height + 1
+			},
+			loadBlockFn: func(_ int64) *types.Block {
+				t.Fatal("pruned block loaded")
+
+				return nil
+			},
+		}
+
+		SetBlockStore(blockStore)
+
+		// Load the result
+		loadedTxResult, err := Tx(&rpctypes.Context{}, hash)
+		require.Nil(t, loadedTxResult)
+
+		assert.ErrorContains(t, err, "height 10 is not available")
+	})
+}
+
+// mockTxSearcher is a tx event store returning fixed search results
+type mockTxSearcher struct {
+	null.TxEventStore
+
+	positions []eventstore.TxPosition
+}
+
+func (m *mockTxSearcher) Search(q *eventstore.Query, opts eventstore.SearchOptions) (*eventstore.SearchResult, error) {
+	minHeight, maxHeight := q.HeightRange()
+
+	positions := slices.DeleteFunc(slices.Clone(m.positions), func(pos eventstore.TxPosition) bool {
+		return pos.Height < minHeight || pos.Height > maxHeight
+	})
+	if opts.Desc {
+		slices.Reverse(positions)
+	}
+
+	if opts.MaxCount > 0 && len(positions) > opts.MaxCount {
+		positions = positions[:opts.MaxCount]
+	}
+
+	res := &eventstore.SearchResult{TotalCount: len(positions)}
+
+	positions = positions[min(opts.Skip, len(positions)):]
+	res.Positions = positions[:min(opts.Limit, len(positions))]
+
+	return res, nil
+}
+
+func TestTxSearchHandler(t *testing.T) {
+	// Tests are not run in parallel because the JSON-RPC
+	// handlers utilize global package-level variables
+	var (
+		height = int64(10)
+		txs    = []types.Tx{
+			types.Tx("tx 0"),
+			types.Tx("tx 1"),
+			types.Tx("tx 2"),
+		}
+
+		responses = &state.ABCIResponses{
+			DeliverTxs: []abci.ResponseDeliverTx{
+				{GasWanted: 100},
+				{GasWanted: 101},
+				{GasWanted: 102},
+			},
+		}
+	)
+
+	// Set up the GLOBALLY referenced db and blockstore
+	sdb := memdb.NewMemDB()
+	sdb.Set(state.CalcABCIResponsesKey(height), responses.Bytes())
+
+	SetStateDB(sdb)
+	SetBlockStore(&mockBlockStore{
+		heightFn: func() int64 {
+			return height
+		},
+		loadBlockFn: func(h int64) *types.Block {
+			require.Equal(t, height, h)
+
+			return &types.Block{
+				Data: types.Data{
+					Txs: txs,
+				},
+			}
+		},
+	})
+
+	t.Run("indexing disabled", func(t *testing.T) {
+		SetTxEventStore(null.NewNullEventStore())
+
+		res, err := TxSearch(&rpctypes.Context{}, "tx.height = 10", 0, 0, "")
+		require.Nil(t, res)
+
+		assert.ErrorContains(t, err, "indexing is disabled")
+	})
+
+	SetTxEventStore(&mockTxSearcher{
+		positions: []eventstore.TxPosition{
+			{Height: height, Index: 0},
+			{Height: height, Index: 2},
+		},
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		res, err := TxSearch(&rpctypes.Context{}, "tx.height", 0, 0, "")
+		require.Nil(t, res)
+
+		assert.ErrorContains(t, err, "unable to parse query")
+	})
+
+	t.Run("invalid order", func(t *testing.T) {
+		res, err := TxSearch(&rpctypes.Context{}, "tx.height = 10", 0, 0, "random")
+		require.Nil(t, res)
+
+		assert.ErrorContains(t, err, "invalid order_by")
+	})
+
+	t.Run("invalid page", func(t *testing.T) {
+		res, err := TxSearch(&rpctypes.Context{}, "tx.height = 10", 3, 1, "")
+		require.Nil(t, res)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("all results", func(t *testing.T) {
+		res, err := TxSearch(&rpctypes.Context{}, "tx.height = 10", 0, 0, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, res.TotalCount)
+		require.Len(t, res.Txs, 2)
+
+		assert.Equal(t, txs[0], res.Txs[0].Tx)
+		assert.Equal(t, txs[0].Hash(), res.Txs[0].Hash)
+		assert.Equal(t, responses.DeliverTxs[0], res.Txs[0].TxResult)
+
+		assert.Equal(t, txs[2], res.Txs[1].Tx)
+		assert.Equal(t, uint32(2), res.Txs[1].Index)
+		assert.Equal(t, responses.DeliverTxs[2], res.Txs[1].TxResult)
+	})
+
+	t.Run("paginated descending results", func(t *testing.T) {
+		res, err := TxSearch(&rpctypes.Context{}, "tx.height = 10", 2, 1, "desc")
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, res.TotalCount)
+		require.Len(t, res.Txs, 1)
+
+		assert.Equal(t, txs[0], res.Txs[0].Tx)
+	})
+
+	t.Run("pruned results", func(t *testing.T) {
+		defer SetBlockStore(blockStore)
+
+		SetBlockStore(&mockBlockStore{
+			heightFn: func() int64 {
+				return height + 1
+			},
+			baseFn: func() int64 {
+				return height + 1
+			},
+		})
+
+		res, err := TxSearch(&rpctypes.Context{}, "tx.signer = 'g1abc'", 0, 0, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, res.TotalCount)
+		assert.Empty(t, res.Txs)
+	})
+
+	t.Run("capped results", func(t *testing.T) {
+		positions := make([]eventstore.TxPosition, maxTxSearchResults+1)
+		for i := range positions {
+			positions[i] = eventstore.TxPosition{Height: height, Index: 0}
+		}
+
+		SetTxEventStore(&mockTxSearcher{positions: positions})
+
+		res, err := TxSearch(&rpctypes.Context{}, "tx.height = 10", 1, 1, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, maxTxSearchResults, res.TotalCount)
+		require.Len(t, res.Txs, 1)
+
+		// The results past the maximum count can't be paged through
+		res, err = TxSearch(&rpctypes.Context{}, "tx.height = 10", maxTxSearchResults+1, 1, "")
+		require.Nil(t, res)
+
+		assert.Error(t, err)
+	})
 }
@@ -200,9 +200,23 @@ type (
 	ResultUnsafeFlushMempool struct{}
 	ResultUnsafeProfile      struct{}
 	ResultHealth             struct{}
+	ResultUnsubscribe        struct{}
 )
 
 // Event data from a subscription
 type ResultEvent struct {
-	Event types.TMEvent `json:"event"`
+	SubscriptionID string `json:"subscription_id"`
+	Height         int64  `json:"height"`
+	TxHash         []byte `json:"tx_hash,omitempty"`
+
+	// Event is set for new_block and tx subscriptions.
+	Event types.TMEvent `json:"event,omitempty"`
+	// TxEvent is set for event subscriptions, and is one of the events
+	// emitted by the transaction TxHash.
+	TxEvent abci.Event `json:"tx_event,omitempty"`
+}
+
+// Result of subscribing to events
+type ResultSubscribe struct {
+	ID string `json:"id"`
 }
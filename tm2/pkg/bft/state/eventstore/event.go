@@ -0,0 +1,55 @@
+package eventstore
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+)
+
+// Event holds the fields of an ABCI event which can be indexed and queried.
+// They are extracted from the JSON representation of the event, following
+// the structure of the events emitted by Gno realms.
+type Event struct {
+	Type    string           `json:"type"`
+	PkgPath string           `json:"pkg_path"`
+	Attrs   []EventAttribute `json:"attrs"`
+}
+
+// EventAttribute is a key-value attribute of an Event
+type EventAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// ParseEvent extracts the indexable fields of the given ABCI event.
+// Events which are not JSON objects (e.g. abci.EventString) have no fields
+func ParseEvent(ev abci.Event) (Event, error) {
+	raw, err := amino.MarshalJSON(ev)
+	if err != nil {
+		return Event{}, fmt.Errorf("unable to marshal event, %w", err)
+	}
+
+	var parsed Event
+	if len(raw) == 0 || raw[0] != '{' {
+		return parsed, nil
+	}
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return Event{}, fmt.Errorf("unable to parse event, %w", err)
+	}
+
+	return parsed, nil
+}
+
+// HasAttribute returns true if the event has an attribute
+// with the given key and value
+func (ev Event) HasAttribute(key, value string) bool {
+	for _, attr := range ev.Attrs {
+		if attr.Key == key && attr.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,386 @@
+// Package kv implements a transaction event store which indexes
+// transactions in a key-value database, so they can be searched for.
+package kv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
+	storetypes "github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+var (
+	_ eventstore.TxEventStore  = (*TxEventStore)(nil)
+	_ eventstore.BlockAppender = (*TxEventStore)(nil)
+	_ eventstore.TxSearcher    = (*TxEventStore)(nil)
+)
+
+const (
+	EventStoreType = "kv"
+	Path           = "path"
+	Backend        = "backend"
+
+	dbName = "tx_index"
+)
+
+var (
+	errMissingPath    = errors.New("missing path param")
+	errInvalidType    = errors.New("invalid config for kv event store specified")
+	errInvalidBackend = errors.New("invalid backend param")
+)
+
+// Key prefixes. Transactions are stored under txPrefix, by position:
+//
+//	tx/<height><index>
+//
+// Each indexed value of a transaction is stored under indexPrefix:
+//
+//	ix/<key>\x00<value>\x00<height><index>
+//
+// Heights and indexes are big-endian encoded, so that keys sort by position.
+var (
+	txPrefix    = []byte("tx/")
+	indexPrefix = []byte("ix/")
+)
+
+const positionLen = 8 + 4
+
+// TxEventStore is the implementation of a transaction event store
+// that indexes transactions in a key-value database
+type TxEventStore struct {
+	path    string
+	backend dbm.BackendType
+
+	db dbm.DB
+}
+
+// NewTxEventStore creates a new kv-based tx event store
+func NewTxEventStore(cfg *storetypes.Config) (*TxEventStore, error) {
+	// Parse config params
+	if EventStoreType != cfg.EventStoreType {
+		return nil, errInvalidType
+	}
+
+	path, ok := cfg.GetParam(Path).(string)
+	if !ok {
+		return nil, errMissingPath
+	}
+
+	backend := dbm.PebbleDBBackend
+	if raw := cfg.GetParam(Backend); raw != nil {
+		name, ok := raw.(string)
+		if !ok {
+			return nil, errInvalidBackend
+		}
+
+		backend = dbm.BackendType(name)
+	}
+
+	return &TxEventStore{
+		path:    path,
+		backend: backend,
+	}, nil
+}
+
+// Start starts the kv transaction event store, by opening the database
+func (t *TxEventStore) Start() error {
+	db, err := dbm.NewDB(dbName, t.backend, t.path)
+	if err != nil {
+		return fmt.Errorf("unable to open index database, %w", err)
+	}
+
+	t.db = db
+
+	return nil
+}
+
+// Stop stops the kv transaction event store, by closing the database
+func (t *TxEventStore) Stop() error {
+	if t.db == nil {
+		// Never started
+		return nil
+	}
+
+	return t.db.Close()
+}
+
+// GetType returns the kv transaction event store type
+func (t *TxEventStore) GetType() string {
+	return EventStoreType
+}
+
+// Append indexes the transaction by height, signers, message types,
+// package paths and emitted events
+func (t *TxEventStore) Append(result types.TxResult) error {
+	return t.AppendBlock([]types.TxResult{result})
+}
+
+// AppendBlock indexes the transactions of a block, in a single write
+func (t *TxEventStore) AppendBlock(results []types.TxResult) error {
+	batch := t.db.NewBatch()
+	defer batch.Close()
+
+	for _, result := range results {
+		pos := encodePosition(result.Height, result.Index)
+
+		if err := batch.Set(append(slices.Clone(txPrefix), pos...), []byte{}); err != nil {
+			return fmt.Errorf("unable to index transaction, %w", err)
+		}
+
+		for _, entry := range indexEntries(result) {
+			if err := batch.Set(indexKey(entry.key, entry.value, pos), []byte{}); err != nil {
+				return fmt.Errorf("unable to index transaction, %w", err)
+			}
+		}
+	}
+
+	if err := batch.WriteSync(); err != nil {
+		return fmt.Errorf("unable to write transaction index, %w", err)
+	}
+
+	return nil
+}
+
+type indexEntry struct {
+	key, value string
+}
+
+// indexEntries returns the (deduplicated) values under which the transaction
+// can be searched for, apart from its height
+func indexEntries(result types.TxResult) []indexEntry {
+	var (
+		entries []indexEntry
+		seen    = map[indexEntry]bool{}
+	)
+
+	add := func(key, value string) {
+		entry := indexEntry{key: key, value: value}
+		if value == "" || seen[entry] {
+			return
+		}
+
+		seen[entry] = true
+		entries = append(entries, entry)
+	}
+
+	// Index the transaction messages. Transactions which can't be
+	// decoded are only indexed by their events
+	var tx std.Tx
+	if err := amino.Unmarshal(result.Tx, &tx); err == nil {
+		for _, signer := range tx.GetSigners() {
+			add(eventstore.QueryKeySigner, signer.String())
+		}
+
+		for _, msg := range tx.GetMsgs() {
+			add(eventstore.QueryKeyMsgType, msg.Type())
+			add(eventstore.QueryKeyPkgPath, msgPkgPath(msg))
+		}
+	}
+
+	// Index the emitted events
+	for _, abciEv := range result.Response.Events {
+		ev, err := eventstore.ParseEvent(abciEv)
+		if err != nil || ev.Type == "" {
+			continue
+		}
+
+		add(eventstore.QueryKeyEvent, ev.Type)
+		add(eventstore.QueryKeyPkgPath, ev.PkgPath)
+
+		for _, attr := range ev.Attrs {
+			add(ev.Type+"."+attr.Key, attr.Value)
+		}
+	}
+
+	return entries
+}
+
+// msgPkgPath returns the package path targeted by the message, if any.
+// Messages are inspected through their JSON representation, looking for
+// either a "pkg_path" field (e.g. calls) or a "package" with a "path"
+// (e.g. package deployments and runs)
+func msgPkgPath(msg std.Msg) string {
+	raw, err := amino.MarshalJSON(msg)
+	if err != nil {
+		return ""
+	}
+
+	var fields struct {
+		PkgPath string `json:"pkg_path"`
+		Package *struct {
+			Path string `json:"path"`
+		} `json:"package"`
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return ""
+	}
+
+	if fields.PkgPath != "" {
+		return fields.PkgPath
+	}
+
+	if fields.Package != nil {
+		return fields.Package.Path
+	}
+
+	return ""
+}
+
+// Search returns a page of the positions of the transactions matching the
+// query, ordered by position. The index of the first condition (or the
+// primary index, if only the height is given) is iterated in order, and the
+// other conditions are checked for each transaction, so only the requested
+// page is kept in memory
+func (t *TxEventStore) Search(q *eventstore.Query, opts eventstore.SearchOptions) (*eventstore.SearchResult, error) {
+	res := &eventstore.SearchResult{}
+
+	minHeight, maxHeight := q.HeightRange()
+	if minHeight > maxHeight {
+		return res, nil
+	}
+
+	var (
+		prefix  = txPrefix
+		indexed = false
+		filters []eventstore.Condition
+	)
+
+	for _, cond := range q.Conditions {
+		if cond.Key == eventstore.QueryKeyHeight {
+			continue
+		}
+
+		if !indexed {
+			prefix, indexed = indexKey(cond.Key, cond.Value, nil), true
+
+			continue
+		}
+
+		filters = append(filters, cond)
+	}
+
+	var (
+		start = append(slices.Clone(prefix), encodePosition(minHeight, 0)...)
+		end   = heightRangeEnd(prefix, maxHeight)
+
+		it  dbm.Iterator
+		err error
+	)
+
+	if opts.Desc {
+		it, err = t.db.ReverseIterator(start, end)
+	} else {
+		it, err = t.db.Iterator(start, end)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate index, %w", err)
+	}
+	defer it.Close()
+
+	for ; it.Valid(); it.Next() {
+		key := it.Key()
+
+		// Skip values having the searched value as a prefix
+		if len(key) != len(prefix)+positionLen {
+			continue
+		}
+
+		pos := key[len(prefix):]
+
+		matches, err := t.matchFilters(filters, pos)
+		if err != nil {
+			return nil, err
+		}
+
+		if !matches {
+			continue
+		}
+
+		if res.TotalCount >= opts.Skip && len(res.Positions) < opts.Limit {
+			res.Positions = append(res.Positions, decodePosition(pos))
+		}
+
+		res.TotalCount++
+
+		if opts.MaxCount > 0 && res.TotalCount >= opts.MaxCount {
+			break
+		}
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("unable to iterate index, %w", err)
+	}
+
+	return res, nil
+}
+
+// matchFilters checks if the transaction at the given (encoded) position
+// matches all the conditions
+func (t *TxEventStore) matchFilters(filters []eventstore.Condition, pos []byte) (bool, error) {
+	for _, cond := range filters {
+		found, err := t.db.Has(indexKey(cond.Key, cond.Value, pos))
+		if err != nil {
+			return false, fmt.Errorf("unable to read index, %w", err)
+		}
+
+		if !found {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// heightRangeEnd returns the exclusive end of an iteration over
+// the transactions up to maxHeight, under the given prefix
+func heightRangeEnd(prefix []byte, maxHeight int64) []byte {
+	end := slices.Clone(prefix)
+	if maxHeight == math.MaxInt64 {
+		// Iterate up to the end of the prefix
+		end[len(end)-1]++
+
+		return end
+	}
+
+	return append(end, encodePosition(maxHeight+1, 0)...)
+}
+
+func indexKey(key, value string, pos []byte) []byte {
+	var buf bytes.Buffer
+
+	buf.Write(indexPrefix)
+	buf.WriteString(key)
+	buf.WriteByte(0)
+	buf.WriteString(value)
+	buf.WriteByte(0)
+	buf.Write(pos)
+
+	return buf.Bytes()
+}
+
+func encodePosition(height int64, index uint32) []byte {
+	pos := make([]byte, positionLen)
+
+	binary.BigEndian.PutUint64(pos, uint64(height))
+	binary.BigEndian.PutUint32(pos[8:], index)
+
+	return pos
+}
+
+func decodePosition(pos []byte) eventstore.TxPosition {
+	return eventstore.TxPosition{
+		Height: int64(binary.BigEndian.Uint64(pos)),
+		Index:  binary.BigEndian.Uint32(pos[8:]),
+	}
+}
@@ -0,0 +1,300 @@
+package kv
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
+	storetypes "github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	_ "github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testEvent mimics the structure of the events emitted by Gno realms
+type testEvent struct {
+	Type    string                      `json:"type"`
+	Attrs   []eventstore.EventAttribute `json:"attrs"`
+	PkgPath string                      `json:"pkg_path"`
+}
+
+func (testEvent) AssertABCIEvent() {}
+
+func newTestEventStore(t *testing.T) *TxEventStore {
+	t.Helper()
+
+	store, err := NewTxEventStore(&storetypes.Config{
+		EventStoreType: EventStoreType,
+		Params: map[string]any{
+			Path:    t.TempDir(),
+			Backend: "memdb",
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.Start())
+
+	t.Cleanup(func() {
+		require.NoError(t, store.Stop())
+	})
+
+	return store
+}
+
+func newTestTxResult(t *testing.T, height int64, index uint32, from crypto.Address, events ...abci.Event) types.TxResult {
+	t.Helper()
+
+	tx := std.Tx{
+		Msgs: []std.Msg{
+			bank.NewMsgSend(from, crypto.Address{}, std.NewCoins(std.NewCoin("ugnot", 10))),
+		},
+	}
+
+	raw, err := amino.Marshal(tx)
+	require.NoError(t, err)
+
+	return types.TxResult{
+		Height: height,
+		Index:  index,
+		Tx:     raw,
+		Response: abci.ResponseDeliverTx{
+			ResponseBase: abci.ResponseBase{
+				Events: events,
+			},
+		},
+	}
+}
+
+func pos(height int64, index uint32) eventstore.TxPosition {
+	return eventstore.TxPosition{Height: height, Index: index}
+}
+
+func TestTxEventStore_New(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid type specified", func(t *testing.T) {
+		t.Parallel()
+
+		i, err := NewTxEventStore(&storetypes.Config{
+			EventStoreType: "invalid",
+		})
+
+		assert.Nil(t, i)
+		assert.ErrorIs(t, err, errInvalidType)
+	})
+
+	t.Run("missing path", func(t *testing.T) {
+		t.Parallel()
+
+		i, err := NewTxEventStore(&storetypes.Config{
+			EventStoreType: EventStoreType,
+		})
+
+		assert.Nil(t, i)
+		assert.ErrorIs(t, err, errMissingPath)
+	})
+
+	t.Run("invalid backend", func(t *testing.T) {
+		t.Parallel()
+
+		i, err := NewTxEventStore(&storetypes.Config{
+			EventStoreType: EventStoreType,
+			Params: map[string]any{
+				Path:    ".",
+				Backend: 10,
+			},
+		})
+
+		assert.Nil(t, i)
+		assert.ErrorIs(t, err, errInvalidBackend)
+	})
+
+	t.Run("stop without start", func(t *testing.T) {
+		t.Parallel()
+
+		i, err := NewTxEventStore(&storetypes.Config{
+			EventStoreType: EventStoreType,
+			Params: map[string]any{
+				Path: ".",
+			},
+		})
+		require.NoError(t, err)
+
+		assert.NoError(t, i.Stop())
+	})
+}
+
+func TestTxEventStore_Search(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = crypto.AddressFromPreimage([]byte("alice"))
+		bob   = crypto.AddressFromPreimage([]byte("bob"))
+
+		transfer = func(to string) abci.Event {
+			return testEvent{
+				Type:    "Transfer",
+				PkgPath: "gno.land/r/demo/foo20",
+				Attrs: []eventstore.EventAttribute{
+					{Key: "to", Value: to},
+				},
+			}
+		}
+	)
+
+	store := newTestEventStore(t)
+
+	txs := []types.TxResult{
+		newTestTxResult(t, 1, 0, alice),
+		newTestTxResult(t, 1, 1, bob, transfer("carol")),
+		newTestTxResult(t, 2, 0, alice, transfer("dave"), abci.EventString("unindexed")),
+		newTestTxResult(t, 3, 0, bob, transfer("dave")),
+		{Height: 3, Index: 1, Tx: []byte("invalid tx")},
+	}
+	require.NoError(t, store.Append(txs[0]))
+	require.NoError(t, store.AppendBlock(txs[1:]))
+
+	testTable := []struct {
+		name     string
+		query    string
+		expected []eventstore.TxPosition
+	}{
+		{
+			"height",
+			"tx.height = 1",
+			[]eventstore.TxPosition{pos(1, 0), pos(1, 1)},
+		},
+		{
+			"height range",
+			"tx.height > 1 AND tx.height <= 3",
+			[]eventstore.TxPosition{pos(2, 0), pos(3, 0), pos(3, 1)},
+		},
+		{
+			"empty height range",
+			"tx.height > 3 AND tx.height < 2",
+			nil,
+		},
+		{
+			"signer",
+			"tx.signer = '" + alice.String() + "'",
+			[]eventstore.TxPosition{pos(1, 0), pos(2, 0)},
+		},
+		{
+			"message type",
+			"tx.msg_type = send",
+			[]eventstore.TxPosition{pos(1, 0), pos(1, 1), pos(2, 0), pos(3, 0)},
+		},
+		{
+			"event package path",
+			"tx.pkg_path = 'gno.land/r/demo/foo20'",
+			[]eventstore.TxPosition{pos(1, 1), pos(2, 0), pos(3, 0)},
+		},
+		{
+			"event type",
+			"tx.event = Transfer AND tx.height >= 2",
+			[]eventstore.TxPosition{pos(2, 0), pos(3, 0)},
+		},
+		{
+			"event attribute",
+			"Transfer.to = 'dave'",
+			[]eventstore.TxPosition{pos(2, 0), pos(3, 0)},
+		},
+		{
+			"combined conditions",
+			"Transfer.to = 'dave' AND tx.signer = '" + bob.String() + "'",
+			[]eventstore.TxPosition{pos(3, 0)},
+		},
+		{
+			"no match",
+			"Transfer.to = 'da'",
+			nil,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			q, err := eventstore.ParseQuery(testCase.query)
+			require.NoError(t, err)
+
+			res, err := store.Search(q, eventstore.SearchOptions{Limit: 100})
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.expected, res.Positions)
+			assert.Equal(t, len(testCase.expected), res.TotalCount)
+		})
+	}
+}
+
+func TestTxEventStore_SearchPagination(t *testing.T) {
+	t.Parallel()
+
+	alice := crypto.AddressFromPreimage([]byte("alice"))
+
+	store := newTestEventStore(t)
+
+	for height := int64(1); height <= 3; height++ {
+		require.NoError(t, store.AppendBlock([]types.TxResult{
+			newTestTxResult(t, height, 0, alice),
+			newTestTxResult(t, height, 1, alice),
+		}))
+	}
+
+	q, err := eventstore.ParseQuery("tx.signer = '" + alice.String() + "' AND tx.msg_type = send")
+	require.NoError(t, err)
+
+	testTable := []struct {
+		name          string
+		opts          eventstore.SearchOptions
+		expected      []eventstore.TxPosition
+		expectedCount int
+	}{
+		{
+			"first page",
+			eventstore.SearchOptions{Limit: 2},
+			[]eventstore.TxPosition{pos(1, 0), pos(1, 1)},
+			6,
+		},
+		{
+			"second page",
+			eventstore.SearchOptions{Skip: 2, Limit: 2},
+			[]eventstore.TxPosition{pos(2, 0), pos(2, 1)},
+			6,
+		},
+		{
+			"descending order",
+			eventstore.SearchOptions{Desc: true, Skip: 1, Limit: 2},
+			[]eventstore.TxPosition{pos(3, 0), pos(2, 1)},
+			6,
+		},
+		{
+			"capped count",
+			eventstore.SearchOptions{Skip: 2, Limit: 2, MaxCount: 3},
+			[]eventstore.TxPosition{pos(2, 0)},
+			3,
+		},
+		{
+			"out of range",
+			eventstore.SearchOptions{Skip: 6, Limit: 2},
+			nil,
+			6,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := store.Search(q, testCase.opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.expected, res.Positions)
+			assert.Equal(t, testCase.expectedCount, res.TotalCount)
+		})
+	}
+}
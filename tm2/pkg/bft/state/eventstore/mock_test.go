@@ -54,6 +54,22 @@ func (m mockEventStore) Append(result types.TxResult) error {
 	return nil
 }
 
+type appendBlockDelegate func([]types.TxResult) error
+
+type mockBlockEventStore struct {
+	mockEventStore
+
+	appendBlockFn appendBlockDelegate
+}
+
+func (m mockBlockEventStore) AppendBlock(results []types.TxResult) error {
+	if m.appendBlockFn != nil {
+		return m.appendBlockFn(results)
+	}
+
+	return nil
+}
+
 // EventSwitch //
 
 type (
@@ -0,0 +1,181 @@
+package eventstore
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Keys with a special meaning in transaction queries. Any other key is of the
+// form <event type>.<attribute key>, and matches the attributes of the events
+// emitted by the transaction.
+const (
+	QueryKeyHeight  = "tx.height"   // height of the block including the transaction
+	QueryKeySigner  = "tx.signer"   // bech32 address of one of the signers
+	QueryKeyMsgType = "tx.msg_type" // type of one of the messages (e.g. "exec", "send")
+	QueryKeyPkgPath = "tx.pkg_path" // package path of a message, or of an emitted event
+	QueryKeyEvent   = "tx.event"    // type of one of the emitted events
+)
+
+// Operator is a comparison operator in a query condition.
+type Operator string
+
+const (
+	OpEqual          Operator = "="
+	OpLess           Operator = "<"
+	OpLessOrEqual    Operator = "<="
+	OpGreater        Operator = ">"
+	OpGreaterOrEqual Operator = ">="
+)
+
+// operators are sorted so that the longest operators are looked up first.
+var operators = []Operator{OpLessOrEqual, OpGreaterOrEqual, OpEqual, OpLess, OpGreater}
+
+var errEmptyQuery = errors.New("empty query")
+
+// Condition is a single condition of a query, such as `tx.height > 10`.
+type Condition struct {
+	Key   string
+	Op    Operator
+	Value string
+}
+
+// Query is a conjunction of conditions a transaction must satisfy.
+type Query struct {
+	Conditions []Condition
+}
+
+// ParseQuery parses a query made of conditions joined by AND, for instance:
+//
+//	tx.height >= 10 AND tx.signer = 'g1...' AND transfer.to = 'g1...'
+//
+// AND is case-insensitive. Values may be single-quoted, and quoted values
+// may contain AND. Only tx.height can be compared with operators other
+// than "=".
+func ParseQuery(s string) (*Query, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, errEmptyQuery
+	}
+
+	parts, err := splitConditions(s)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{}
+	for _, part := range parts {
+		cond, err := parseCondition(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		q.Conditions = append(q.Conditions, cond)
+	}
+	return q, nil
+}
+
+// splitConditions splits the query on the AND separators
+// which are not within a quoted value
+func splitConditions(s string) ([]string, error) {
+	const sep = " and "
+
+	var (
+		parts  []string
+		quoted bool
+		start  int
+	)
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\'':
+			quoted = !quoted
+		case !quoted && i+len(sep) <= len(s) && strings.EqualFold(s[i:i+len(sep)], sep):
+			parts = append(parts, s[start:i])
+			start = i + len(sep)
+			i = start - 1
+		}
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("invalid query %q: unterminated quoted value", s)
+	}
+
+	return append(parts, s[start:]), nil
+}
+
+func parseCondition(s string) (Condition, error) {
+	idx, op := -1, Operator("")
+	for _, candidate := range operators {
+		if i := strings.Index(s, string(candidate)); i >= 0 && (idx < 0 || i < idx) {
+			idx, op = i, candidate
+		}
+	}
+	if idx < 0 {
+		return Condition{}, fmt.Errorf("invalid condition %q: missing operator", s)
+	}
+
+	cond := Condition{
+		Key:   strings.TrimSpace(s[:idx]),
+		Op:    op,
+		Value: strings.TrimSpace(s[idx+len(op):]),
+	}
+	if len(cond.Value) >= 2 && cond.Value[0] == '\'' && cond.Value[len(cond.Value)-1] == '\'' {
+		cond.Value = cond.Value[1 : len(cond.Value)-1]
+	}
+
+	switch {
+	case cond.Key == "":
+		return Condition{}, fmt.Errorf("invalid condition %q: missing key", s)
+	case cond.Key == QueryKeyHeight:
+		if _, err := strconv.ParseInt(cond.Value, 10, 64); err != nil {
+			return Condition{}, fmt.Errorf("invalid condition %q: invalid height, %w", s, err)
+		}
+	case cond.Op != OpEqual:
+		return Condition{}, fmt.Errorf("invalid condition %q: operator %s is only supported for %s", s, cond.Op, QueryKeyHeight)
+	case !strings.Contains(cond.Key, "."):
+		return Condition{}, fmt.Errorf("invalid condition %q: key should be of the form <event>.<attribute>", s)
+	}
+	return cond, nil
+}
+
+// HeightRange returns the range of heights, inclusive, allowed by the
+// tx.height conditions of the query. The range is empty if minHeight is
+// greater than maxHeight.
+func (q *Query) HeightRange() (minHeight, maxHeight int64) {
+	minHeight, maxHeight = 1, math.MaxInt64
+	for _, cond := range q.Conditions {
+		if cond.Key != QueryKeyHeight {
+			continue
+		}
+		h, _ := strconv.ParseInt(cond.Value, 10, 64)
+		switch cond.Op {
+		case OpEqual:
+			minHeight, maxHeight = max(minHeight, h), min(maxHeight, h)
+		case OpLess:
+			if h <= minHeight {
+				// No height is lower than the minimum
+				// (also avoids overflowing h-1)
+				maxHeight = 0
+
+				continue
+			}
+			maxHeight = min(maxHeight, h-1)
+		case OpLessOrEqual:
+			maxHeight = min(maxHeight, h)
+		case OpGreater:
+			if h == math.MaxInt64 {
+				// No height is greater than the maximum
+				// (also avoids overflowing h+1)
+				maxHeight = 0
+
+				continue
+			}
+			minHeight = max(minHeight, h+1)
+		case OpGreaterOrEqual:
+			minHeight = max(minHeight, h)
+		}
+	}
+	return minHeight, maxHeight
+}
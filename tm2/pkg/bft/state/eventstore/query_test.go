@@ -0,0 +1,95 @@
+package eventstore
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid query", func(t *testing.T) {
+		t.Parallel()
+
+		q, err := ParseQuery("tx.height>=10 AND tx.signer = 'g1abc' AND Transfer.memo = 'a=b'")
+		require.NoError(t, err)
+
+		assert.Equal(t, []Condition{
+			{Key: QueryKeyHeight, Op: OpGreaterOrEqual, Value: "10"},
+			{Key: QueryKeySigner, Op: OpEqual, Value: "g1abc"},
+			{Key: "Transfer.memo", Op: OpEqual, Value: "a=b"},
+		}, q.Conditions)
+	})
+
+	t.Run("case-insensitive and quoted separators", func(t *testing.T) {
+		t.Parallel()
+
+		q, err := ParseQuery("tx.height>=10 and tx.pkg_path = 'gno.land/r/demo/foo' And Post.title = 'Salt AND pepper'")
+		require.NoError(t, err)
+
+		assert.Equal(t, []Condition{
+			{Key: QueryKeyHeight, Op: OpGreaterOrEqual, Value: "10"},
+			{Key: QueryKeyPkgPath, Op: OpEqual, Value: "gno.land/r/demo/foo"},
+			{Key: "Post.title", Op: OpEqual, Value: "Salt AND pepper"},
+		}, q.Conditions)
+	})
+
+	testTable := []struct {
+		name  string
+		query string
+	}{
+		{"empty query", "  "},
+		{"unterminated quote", "Post.title = 'Salt AND pepper"},
+		{"missing operator", "tx.height"},
+		{"missing key", "= 10"},
+		{"invalid height", "tx.height = ten"},
+		{"range on attribute", "Transfer.amount > 10"},
+		{"invalid key", "signer = 'g1abc'"},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			q, err := ParseQuery(testCase.query)
+
+			assert.Nil(t, q)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestQuery_HeightRange(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		query    string
+		min, max int64
+	}{
+		{"tx.event = Transfer", 1, math.MaxInt64},
+		{"tx.height = 5", 5, 5},
+		{"tx.height > 5 AND tx.height < 10", 6, 9},
+		{"tx.height >= 5 AND tx.height <= 10 AND tx.height <= 8", 5, 8},
+		{"tx.height = 5 AND tx.height > 5", 6, 5},
+		{"tx.height > 9223372036854775807", 1, 0},
+		{"tx.height < -9223372036854775808", 1, 0},
+		{"tx.height < 1", 1, 0},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.query, func(t *testing.T) {
+			t.Parallel()
+
+			q, err := ParseQuery(testCase.query)
+			require.NoError(t, err)
+
+			minHeight, maxHeight := q.HeightRange()
+
+			assert.Equal(t, testCase.min, minHeight)
+			assert.Equal(t, testCase.max, maxHeight)
+		})
+	}
+}
@@ -22,3 +22,44 @@ type TxEventStore interface {
 	// to the event store
 	Append(result types.TxResult) error
 }
+
+// BlockAppender is implemented by the transaction event stores
+// which can append all the transactions of a block at once
+type BlockAppender interface {
+	// AppendBlock analyzes and appends the transactions of a block
+	// to the event store
+	AppendBlock(results []types.TxResult) error
+}
+
+// TxPosition identifies a transaction by its position in the chain
+type TxPosition struct {
+	Height int64  `json:"height"`
+	Index  uint32 `json:"index"`
+}
+
+// SearchOptions paginate the results of a transaction search
+type SearchOptions struct {
+	Desc     bool // order the results by descending position
+	Skip     int  // number of matching transactions to skip
+	Limit    int  // maximum number of positions to return
+	MaxCount int  // maximum number of matching transactions to count (0 for no limit)
+}
+
+// SearchResult is a page of the transactions matching a search
+type SearchResult struct {
+	// Positions are the positions of the matching transactions
+	// on the requested page
+	Positions []TxPosition
+
+	// TotalCount is the number of matching transactions,
+	// capped to the requested maximum count
+	TotalCount int
+}
+
+// TxSearcher is implemented by the transaction event stores
+// which index transactions, and can be queried for them
+type TxSearcher interface {
+	// Search returns a page of the positions of the transactions
+	// matching the query, ordered by position
+	Search(q *Query, opts SearchOptions) (*SearchResult, error)
+}
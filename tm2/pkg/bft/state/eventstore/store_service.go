@@ -57,27 +57,69 @@ func (is *Service) OnStop() {
 }
 
 // monitorTxEvents acts as an intermediary feed service for the supplied
-// event store. It relays transaction events that come from the event stream
+// event store. It relays transaction events that come from the event stream.
+// If the event store supports it, the transactions of a block (fired right
+// after the block) are appended at once
 func (is *Service) monitorTxEvents(ctx context.Context) {
-	// Create a subscription for transaction events
-	subCh := events.SubscribeToEvent(is.evsw, "tx-event-store", types.EventTx{})
+	// Create a subscription for block and transaction events
+	subCh := events.SubscribeFiltered(is.evsw, "tx-event-store", func(ev events.Event) bool {
+		switch ev.(type) {
+		case types.EventNewBlock, types.EventTx:
+			return true
+		default:
+			return false
+		}
+	})
+
+	var (
+		blockAppender, batched = is.txEventStore.(BlockAppender)
+
+		height   int64 // height of the block being batched
+		expected int   // number of transactions in the block
+		results  []types.TxResult
+	)
+
+	flush := func() {
+		if len(results) > 0 {
+			if err := blockAppender.AppendBlock(results); err != nil {
+				is.Logger.Error("unable to store block transactions", "height", height, "err", err)
+			}
+		}
+
+		height, expected, results = 0, 0, nil
+	}
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case evRaw := <-subCh:
-			// Cast the event
-			ev, ok := evRaw.(types.EventTx)
-			if !ok {
+			switch ev := evRaw.(type) {
+			case types.EventNewBlock:
+				if !batched || ev.Block == nil {
+					continue
+				}
+
+				// Store the transactions of an incomplete previous block
+				flush()
+
+				height, expected = ev.Block.Height, len(ev.Block.Data.Txs)
+			case types.EventTx:
+				if !batched || ev.Result.Height != height {
+					// Alert the actual tx event store
+					if err := is.txEventStore.Append(ev.Result); err != nil {
+						is.Logger.Error("unable to store transaction", "err", err)
+					}
+
+					continue
+				}
+
+				results = append(results, ev.Result)
+				if len(results) == expected {
+					flush()
+				}
+			default:
 				is.Logger.Error("invalid transaction result type cast")
-
-				continue
-			}
-
-			// Alert the actual tx event store
-			if err := is.txEventStore.Append(ev.Result); err != nil {
-				is.Logger.Error("unable to store transaction", "err", err)
 			}
 		}
 	}
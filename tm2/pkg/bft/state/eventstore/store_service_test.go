@@ -9,6 +9,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/events"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // generateTxEvents generates random transaction events
@@ -157,3 +158,98 @@ func TestEventStoreService_Monitor(t *testing.T) {
 		assert.Equal(t, event.Result, receivedResults[index])
 	}
 }
+
+func TestEventStoreService_MonitorBlocks(t *testing.T) {
+	t.Parallel()
+
+	var (
+		blocks   = make(chan []types.TxResult, 2)
+		appended = make(chan types.TxResult, 1)
+
+		cb    events.EventCallback
+		cbSet = make(chan struct{})
+
+		mockEventStore = &mockBlockEventStore{
+			mockEventStore: mockEventStore{
+				appendFn: func(result types.TxResult) error {
+					appended <- result
+
+					return nil
+				},
+			},
+			appendBlockFn: func(results []types.TxResult) error {
+				blocks <- results
+
+				return nil
+			},
+		}
+		mockEventSwitch = &mockEventSwitch{
+			fireEventFn: func(event events.Event) {
+				cb(event)
+			},
+			addListenerFn: func(_ string, callback events.EventCallback) {
+				cb = callback
+				close(cbSet)
+			},
+		}
+	)
+
+	i := NewEventStoreService(mockEventStore, mockEventSwitch)
+	require.NoError(t, i.OnStart())
+	t.Cleanup(i.OnStop)
+
+	select {
+	case <-cbSet:
+	case <-time.After(5 * time.Second):
+		t.Fatal("listener not set")
+	}
+
+	newBlock := func(height int64, numTxs int) events.Event {
+		return types.EventNewBlock{
+			Block: &types.Block{
+				Header: types.Header{Height: height},
+				Data:   types.Data{Txs: make(types.Txs, numTxs)},
+			},
+		}
+	}
+
+	newTx := func(height int64, index uint32) types.EventTx {
+		return types.EventTx{Result: types.TxResult{Height: height, Index: index}}
+	}
+
+	receive := func(ch <-chan []types.TxResult) []types.TxResult {
+		select {
+		case results := <-ch:
+			return results
+		case <-time.After(5 * time.Second):
+			t.Fatal("block not appended")
+
+			return nil
+		}
+	}
+
+	// The transactions of a block are appended at once
+	mockEventSwitch.FireEvent(newBlock(1, 2))
+	mockEventSwitch.FireEvent(newTx(1, 0))
+	mockEventSwitch.FireEvent(newTx(1, 1))
+
+	assert.Equal(t, []types.TxResult{newTx(1, 0).Result, newTx(1, 1).Result}, receive(blocks))
+
+	// The transactions of an incomplete block are appended
+	// on the next block
+	mockEventSwitch.FireEvent(newBlock(2, 2))
+	mockEventSwitch.FireEvent(newTx(2, 0))
+	mockEventSwitch.FireEvent(newBlock(3, 0))
+
+	assert.Equal(t, []types.TxResult{newTx(2, 0).Result}, receive(blocks))
+
+	// Transactions outside of a block are appended one by one
+	mockEventSwitch.FireEvent(newTx(5, 0))
+
+	select {
+	case result := <-appended:
+		assert.Equal(t, newTx(5, 0).Result, result)
+	case <-time.After(5 * time.Second):
+		t.Fatal("transaction not appended")
+	}
+}
@@ -136,6 +136,28 @@ func TestABCIResponsesSaveLoad1(t *testing.T) {
 			loadedABCIResponses, abciResponses))
 }
 
+// TestABCIResponsesPrune tests pruning the ABCIResponses of past heights.
+func TestABCIResponsesPrune(t *testing.T) {
+	t.Parallel()
+
+	tearDown, stateDB, _ := setupTestCase(t)
+	defer tearDown(t)
+
+	for h := int64(1); h <= 5; h++ {
+		sm.SaveABCIResponses(stateDB, h, sm.NewABCIResponsesFromNum(1))
+	}
+	require.NoError(t, sm.PruneABCIResponses(stateDB, 1, 4))
+
+	for h := int64(1); h < 4; h++ {
+		_, err := sm.LoadABCIResponses(stateDB, h)
+		assert.ErrorAs(t, err, &sm.NoABCIResponsesForHeightError{})
+	}
+	for h := int64(4); h <= 5; h++ {
+		_, err := sm.LoadABCIResponses(stateDB, h)
+		assert.NoError(t, err)
+	}
+}
+
 // TestResultsSaveLoad tests saving and loading ABCI results.
 func TestABCIResponsesSaveLoad2(t *testing.T) {
 	t.Parallel()
@@ -121,6 +121,30 @@ func saveState(db dbm.DB, state State, key []byte) {
 	db.SetSync(key, state.Bytes())
 }
 
+// BootstrapState persists the state of a node restored by state sync, whose
+// previous blocks were never executed. Since the history of the validator
+// sets and consensus params is unknown, they are saved as if they changed at
+// the heights of the state.
+func BootstrapState(db dbm.DB, state State) error {
+	height := state.LastBlockHeight
+	if height <= 0 {
+		return fmt.Errorf("invalid state height %d", height)
+	}
+	if last := LoadState(db).LastBlockHeight; last != 0 {
+		return fmt.Errorf("cannot bootstrap a state at height %d", last)
+	}
+	if state.LastValidators == nil || state.Validators == nil || state.NextValidators == nil {
+		return errors.New("missing validators in the state")
+	}
+
+	state.LastHeightValidatorsChanged = height + 2
+	state.LastHeightConsensusParamsChanged = height + 1
+	saveValidatorsInfo(db, height, height, state.LastValidators)
+	saveValidatorsInfo(db, height+1, height+1, state.Validators)
+	saveState(db, state, stateKey)
+	return nil
+}
+
 // ------------------------------------------------------------------------
 
 // ABCIResponses retains the responses
@@ -191,6 +215,21 @@ func SaveABCIResponses(db dbm.DB, height int64, abciResponses *ABCIResponses) {
 	db.Set(CalcABCIResponsesKey(height), abciResponses.Bytes())
 }
 
+// PruneABCIResponses deletes the ABCIResponses of the heights from from to
+// to (exclusive). The responses of the last block must be kept, to recover
+// from a crash before s.Save().
+func PruneABCIResponses(db dbm.DB, from, to int64) error {
+	batch := db.NewBatch()
+	defer batch.Close()
+
+	for height := from; height < to; height++ {
+		if err := batch.Delete(CalcABCIResponsesKey(height)); err != nil {
+			return err
+		}
+	}
+	return batch.WriteSync()
+}
+
 // TxResultIndex keeps the result index information for a transaction
 type TxResultIndex struct {
 	BlockNum int64  // the block number the tx was contained in
@@ -225,6 +264,32 @@ func saveTxResultIndex(db dbm.DB, txHash []byte, resultIndex TxResultIndex) {
 	db.Set(CalcTxResultKey(txHash), resultIndex.Bytes())
 }
 
+// PruneTxResultIndexes deletes the result indexes of txs, the transactions
+// of the block at height. The index of a tx which was included again at
+// another height is kept.
+func PruneTxResultIndexes(db dbm.DB, height int64, txs types.Txs) error {
+	batch := db.NewBatch()
+	defer batch.Close()
+
+	for _, tx := range txs {
+		hash := tx.Hash()
+		resultIndex, err := LoadTxResultIndex(db, hash)
+		if err != nil {
+			if errors.As(err, &NoTxResultForHashError{}) {
+				continue
+			}
+			return err
+		}
+		if resultIndex.BlockNum != height {
+			continue
+		}
+		if err := batch.Delete(CalcTxResultKey(hash)); err != nil {
+			return err
+		}
+	}
+	return batch.WriteSync()
+}
+
 // -----------------------------------------------------------------------------
 
 // ValidatorsInfo represents the latest validator set, or the last height it changed
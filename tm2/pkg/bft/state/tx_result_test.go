@@ -114,3 +114,38 @@ func TestStoreLoadTxResult(t *testing.T) {
 		assert.ErrorIs(t, err, errTxResultIndexCorrupted)
 	})
 }
+
+func TestPruneTxResultIndexes(t *testing.T) {
+	t.Parallel()
+
+	var (
+		stateDB   = memdb.NewMemDB()
+		txResults = generateTxResults(t, 10)
+		txs       = make(types.Txs, 0, len(txResults))
+	)
+
+	for _, txResult := range txResults {
+		saveTxResultIndex(stateDB, txResult.Tx.Hash(), TxResultIndex{
+			BlockNum: txResult.Height,
+			TxIndex:  txResult.Index,
+		})
+		txs = append(txs, txResult.Tx)
+	}
+
+	// The index of a tx included again at another height is kept
+	saveTxResultIndex(stateDB, txs[0].Hash(), TxResultIndex{BlockNum: 11})
+
+	// A tx without index is ignored
+	txs = append(txs, types.Tx("not indexed"))
+
+	require.NoError(t, PruneTxResultIndexes(stateDB, 10, txs))
+
+	result, err := LoadTxResultIndex(stateDB, txs[0].Hash())
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), result.BlockNum)
+
+	for _, tx := range txs[1:] {
+		_, err := LoadTxResultIndex(stateDB, tx.Hash())
+		assert.ErrorAs(t, err, &NoTxResultForHashError{})
+	}
+}
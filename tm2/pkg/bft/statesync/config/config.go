@@ -0,0 +1,102 @@
+package config
+
+import (
+	"encoding/hex"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/errors"
+)
+
+// -----------------------------------------------------------------------------
+// StateSyncConfig
+
+// StateSyncConfig defines the configuration of state sync, which bootstraps a
+// new node from a snapshot of the application state served by its peers,
+// instead of replaying all the blocks.
+//
+// The restored state is verified by the app hash of a header verified by a
+// light client. The application state which is not merkleized (e.g. the base
+// store of the Gno VM) isn't covered by the app hash: the snapshots holding
+// such state are refused, unless the application trusts it from the peer
+// serving the snapshot (see the snapshot_trust_unverified option of the
+// application).
+type StateSyncConfig struct {
+	Enable              bool          `json:"enable" toml:"enable" comment:"State sync bootstraps a new node from a snapshot of the application state served by its peers,\n instead of replaying all the blocks. It is only used when the node has no state."`
+	RPCServers          []string      `json:"rpc_servers" toml:"rpc_servers" comment:"RPC servers used to verify the snapshots with a light client (comma separated).\n The first one is the primary, the others are witnesses of its headers."`
+	TrustHeight         int64         `json:"trust_height" toml:"trust_height" comment:"Height and hash of a trusted header, obtained from a trusted source.\n The snapshots must be more recent than this header."`
+	TrustHash           string        `json:"trust_hash" toml:"trust_hash"`
+	TrustPeriod         time.Duration `json:"trust_period" toml:"trust_period" comment:"Period during which the validators of the trusted header are trusted"`
+	DiscoveryTime       time.Duration `json:"discovery_time" toml:"discovery_time" comment:"Time spent discovering the snapshots of the peers"`
+	ChunkRequestTimeout time.Duration `json:"chunk_request_timeout" toml:"chunk_request_timeout" comment:"Timeout before requesting a chunk of a snapshot from another peer"`
+	ChunkFetchers       int           `json:"chunk_fetchers" toml:"chunk_fetchers" comment:"Number of chunks requested concurrently"`
+}
+
+// DefaultStateSyncConfig returns a default configuration for state sync
+func DefaultStateSyncConfig() *StateSyncConfig {
+	return &StateSyncConfig{
+		Enable:              false,
+		RPCServers:          []string{},
+		TrustPeriod:         7 * 24 * time.Hour,
+		DiscoveryTime:       15 * time.Second,
+		ChunkRequestTimeout: 10 * time.Second,
+		ChunkFetchers:       4,
+	}
+}
+
+// TestStateSyncConfig returns a configuration for testing state sync
+func TestStateSyncConfig() *StateSyncConfig {
+	cfg := DefaultStateSyncConfig()
+	cfg.DiscoveryTime = 100 * time.Millisecond
+	cfg.ChunkRequestTimeout = time.Second
+	return cfg
+}
+
+// TrustHashBytes returns the decoded trust hash.
+func (cfg *StateSyncConfig) TrustHashBytes() []byte {
+	// ValidateBasic checked the hash.
+	bz, _ := hex.DecodeString(cfg.TrustHash)
+	return bz
+}
+
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
+func (cfg *StateSyncConfig) ValidateBasic() error {
+	if cfg.TrustPeriod < 0 {
+		return errors.New("trust_period can't be negative")
+	}
+	if cfg.DiscoveryTime < 0 {
+		return errors.New("discovery_time can't be negative")
+	}
+	if cfg.ChunkRequestTimeout < 0 {
+		return errors.New("chunk_request_timeout can't be negative")
+	}
+	if cfg.ChunkFetchers < 0 {
+		return errors.New("chunk_fetchers can't be negative")
+	}
+	if _, err := hex.DecodeString(cfg.TrustHash); err != nil {
+		return errors.New("trust_hash isn't a valid hex string")
+	}
+	if !cfg.Enable {
+		return nil
+	}
+
+	if len(cfg.RPCServers) == 0 {
+		return errors.New("rpc_servers is required")
+	}
+	if cfg.TrustHeight <= 0 {
+		return errors.New("trust_height is required")
+	}
+	if cfg.TrustHash == "" {
+		return errors.New("trust_hash is required")
+	}
+	if cfg.TrustPeriod == 0 {
+		return errors.New("trust_period is required")
+	}
+	if cfg.ChunkRequestTimeout == 0 {
+		return errors.New("chunk_request_timeout is required")
+	}
+	if cfg.ChunkFetchers == 0 {
+		return errors.New("chunk_fetchers is required")
+	}
+	return nil
+}
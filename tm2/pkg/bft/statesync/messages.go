@@ -0,0 +1,118 @@
+package statesync
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+)
+
+const (
+	// maxChunkSize is the maximum size of a snapshot chunk.
+	maxChunkSize = 16 * 1024 * 1024
+
+	// maxMsgSize is the maximum size of a message, with some room for the
+	// fields of chunkResponseMessage.
+	maxMsgSize = maxChunkSize + 1024
+)
+
+// StateSyncMessage is a generic message for this reactor.
+type StateSyncMessage interface {
+	ValidateBasic() error
+}
+
+func decodeMsg(bz []byte) (msg StateSyncMessage, err error) {
+	if len(bz) > maxMsgSize {
+		return msg, fmt.Errorf("msg exceeds max size (%d > %d)", len(bz), maxMsgSize)
+	}
+	err = amino.Unmarshal(bz, &msg)
+	return
+}
+
+// -------------------------------------
+
+// snapshotsRequestMessage requests the recent snapshots of a peer.
+type snapshotsRequestMessage struct{}
+
+// ValidateBasic performs basic validation.
+func (m *snapshotsRequestMessage) ValidateBasic() error {
+	return nil
+}
+
+func (m *snapshotsRequestMessage) String() string {
+	return "[snapshotsRequestMessage]"
+}
+
+// snapshotsResponseMessage advertises a snapshot of a peer.
+type snapshotsResponseMessage struct {
+	Height   int64
+	Format   uint32
+	Chunks   uint32
+	Hash     []byte
+	Metadata []byte
+}
+
+// ValidateBasic performs basic validation.
+func (m *snapshotsResponseMessage) ValidateBasic() error {
+	switch {
+	case m.Height <= 0:
+		return errors.New("invalid height")
+	case m.Chunks == 0:
+		return errors.New("no chunks")
+	case len(m.Hash) == 0:
+		return errors.New("no hash")
+	}
+	return nil
+}
+
+func (m *snapshotsResponseMessage) String() string {
+	return fmt.Sprintf("[snapshotsResponseMessage %v/%v %X]", m.Height, m.Format, m.Hash)
+}
+
+// -------------------------------------
+
+// chunkRequestMessage requests a chunk of a snapshot.
+type chunkRequestMessage struct {
+	Height int64
+	Format uint32
+	Index  uint32
+}
+
+// ValidateBasic performs basic validation.
+func (m *chunkRequestMessage) ValidateBasic() error {
+	if m.Height <= 0 {
+		return errors.New("invalid height")
+	}
+	return nil
+}
+
+func (m *chunkRequestMessage) String() string {
+	return fmt.Sprintf("[chunkRequestMessage %v/%v %v]", m.Height, m.Format, m.Index)
+}
+
+// chunkResponseMessage returns a chunk of a snapshot, or reports it missing.
+type chunkResponseMessage struct {
+	Height  int64
+	Format  uint32
+	Index   uint32
+	Chunk   []byte
+	Missing bool
+}
+
+// ValidateBasic performs basic validation.
+func (m *chunkResponseMessage) ValidateBasic() error {
+	switch {
+	case m.Height <= 0:
+		return errors.New("invalid height")
+	case m.Missing && len(m.Chunk) > 0:
+		return errors.New("missing chunk with contents")
+	case len(m.Chunk) > maxChunkSize:
+		return fmt.Errorf("chunk exceeds max size (%d > %d)", len(m.Chunk), maxChunkSize)
+	}
+	return nil
+}
+
+func (m *chunkResponseMessage) String() string {
+	return fmt.Sprintf("[chunkResponseMessage %v/%v %v (%d bytes, missing=%v)]",
+		m.Height, m.Format, m.Index, len(m.Chunk), m.Missing)
+}
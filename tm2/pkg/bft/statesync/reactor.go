@@ -0,0 +1,223 @@
+// Package statesync bootstraps a node from a snapshot of the application state
+// served by its peers, instead of replaying all the blocks.
+package statesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+	p2pTypes "github.com/gnolang/gno/tm2/pkg/p2p/types"
+)
+
+const (
+	// SnapshotChannel exchanges the lists of snapshots of the peers.
+	SnapshotChannel = byte(0x60)
+	// ChunkChannel exchanges the chunks of the snapshots.
+	ChunkChannel = byte(0x61)
+
+	// recentSnapshots is the number of recent snapshots advertised to a peer.
+	recentSnapshots = 10
+)
+
+var errSyncInProgress = errors.New("state sync already in progress")
+
+// Reactor serves the snapshots of the application to the peers, and restores
+// a snapshot of the peers when syncing.
+type Reactor struct {
+	p2p.BaseReactor
+
+	conn      appconn.Snapshot
+	connQuery appconn.Query
+
+	mtx    sync.RWMutex
+	syncer *syncer // set while syncing
+}
+
+// NewReactor returns a new state sync reactor, using the snapshot and query
+// connections of the application.
+func NewReactor(conn appconn.Snapshot, connQuery appconn.Query) *Reactor {
+	r := &Reactor{
+		conn:      conn,
+		connQuery: connQuery,
+	}
+	r.BaseReactor = *p2p.NewBaseReactor("StateSyncReactor", r)
+	return r
+}
+
+// GetChannels implements Reactor
+func (r *Reactor) GetChannels() []*p2p.ChannelDescriptor {
+	return []*p2p.ChannelDescriptor{
+		{
+			ID:                  SnapshotChannel,
+			Priority:            5,
+			SendQueueCapacity:   10,
+			RecvMessageCapacity: 4096,
+		},
+		{
+			ID:                  ChunkChannel,
+			Priority:            3,
+			SendQueueCapacity:   4,
+			RecvMessageCapacity: maxMsgSize,
+		},
+	}
+}
+
+// AddPeer implements Reactor by requesting the snapshots of the peer when
+// syncing.
+func (r *Reactor) AddPeer(peer p2p.PeerConn) {
+	if r.getSyncer() != nil {
+		peer.Send(SnapshotChannel, amino.MustMarshalAny(&snapshotsRequestMessage{}))
+	}
+}
+
+// RemovePeer implements Reactor by removing the snapshots of the peer.
+func (r *Reactor) RemovePeer(peer p2p.PeerConn, reason any) {
+	if s := r.getSyncer(); s != nil {
+		s.RemovePeer(peer.ID())
+	}
+}
+
+// Receive implements Reactor by handling 4 types of messages (look below).
+func (r *Reactor) Receive(chID byte, src p2p.PeerConn, msgBytes []byte) {
+	msg, err := decodeMsg(msgBytes)
+	if err != nil {
+		r.Logger.Error("Error decoding message", "src", src, "chId", chID, "err", err)
+		r.Switch.StopPeerForError(src, err)
+		return
+	}
+
+	if err = msg.ValidateBasic(); err != nil {
+		r.Logger.Error("Peer sent us invalid msg", "peer", src, "msg", msg, "err", err)
+		r.Switch.StopPeerForError(src, err)
+		return
+	}
+
+	r.Logger.Debug("Receive", "src", src, "chID", chID, "msg", msg)
+
+	switch msg := msg.(type) {
+	case *snapshotsRequestMessage:
+		r.sendSnapshots(src)
+	case *snapshotsResponseMessage:
+		if s := r.getSyncer(); s != nil {
+			s.AddSnapshot(src.ID(), &abci.Snapshot{
+				Height:   msg.Height,
+				Format:   msg.Format,
+				Chunks:   msg.Chunks,
+				Hash:     msg.Hash,
+				Metadata: msg.Metadata,
+			})
+		}
+	case *chunkRequestMessage:
+		r.sendChunk(src, msg)
+	case *chunkResponseMessage:
+		if s := r.getSyncer(); s != nil {
+			s.AddChunk(&chunk{
+				Height:  msg.Height,
+				Format:  msg.Format,
+				Index:   msg.Index,
+				Chunk:   msg.Chunk,
+				Missing: msg.Missing,
+				Sender:  src.ID(),
+			})
+		}
+	default:
+		r.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
+	}
+}
+
+// sendSnapshots advertises the recent snapshots of the application to peer.
+func (r *Reactor) sendSnapshots(peer p2p.PeerConn) {
+	res, err := r.conn.ListSnapshotsSync(abci.RequestListSnapshots{})
+	if err != nil {
+		r.Logger.Error("Failed to list snapshots", "err", err)
+		return
+	}
+	snapshots := res.Snapshots
+	if len(snapshots) > recentSnapshots {
+		snapshots = snapshots[:recentSnapshots]
+	}
+	for _, snapshot := range snapshots {
+		peer.TrySend(SnapshotChannel, amino.MustMarshalAny(&snapshotsResponseMessage{
+			Height:   snapshot.Height,
+			Format:   snapshot.Format,
+			Chunks:   snapshot.Chunks,
+			Hash:     snapshot.Hash,
+			Metadata: snapshot.Metadata,
+		}))
+	}
+}
+
+// sendChunk sends the requested chunk to peer, or reports it missing.
+func (r *Reactor) sendChunk(peer p2p.PeerConn, msg *chunkRequestMessage) {
+	res, err := r.conn.LoadSnapshotChunkSync(abci.RequestLoadSnapshotChunk{
+		Height: msg.Height,
+		Format: msg.Format,
+		Chunk:  msg.Index,
+	})
+	if err != nil {
+		r.Logger.Error("Failed to load snapshot chunk", "height", msg.Height, "index", msg.Index, "err", err)
+		return
+	}
+	peer.TrySend(ChunkChannel, amino.MustMarshalAny(&chunkResponseMessage{
+		Height:  msg.Height,
+		Format:  msg.Format,
+		Index:   msg.Index,
+		Chunk:   res.Chunk,
+		Missing: res.Chunk == nil,
+	}))
+}
+
+// requestChunk requests a chunk of snapshot from the peer with the given ID.
+func (r *Reactor) requestChunk(peerID p2pTypes.ID, snapshot *abci.Snapshot, index uint32) {
+	peer := r.Switch.Peers().Get(peerID)
+	if peer == nil {
+		return
+	}
+	peer.TrySend(ChunkChannel, amino.MustMarshalAny(&chunkRequestMessage{
+		Height: snapshot.Height,
+		Format: snapshot.Format,
+		Index:  index,
+	}))
+}
+
+func (r *Reactor) getSyncer() *syncer {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.syncer
+}
+
+// Sync discovers the snapshots of the peers and restores one of them into
+// the application, verifying it with stateProvider. It returns the state and
+// the commit of the height of the restored snapshot.
+func (r *Reactor) Sync(ctx context.Context, stateProvider StateProvider, cfg *config.StateSyncConfig) (sm.State, *types.Commit, error) {
+	r.mtx.Lock()
+	if r.syncer != nil {
+		r.mtx.Unlock()
+		return sm.State{}, nil, errSyncInProgress
+	}
+	s := newSyncer(r.Logger, r.conn, r.connQuery, stateProvider, cfg, r.requestChunk)
+	r.syncer = s
+	r.mtx.Unlock()
+
+	defer func() {
+		r.mtx.Lock()
+		r.syncer = nil
+		r.mtx.Unlock()
+	}()
+
+	discover := func() {
+		r.Switch.Broadcast(SnapshotChannel, amino.MustMarshalAny(&snapshotsRequestMessage{}))
+	}
+	discover()
+	return s.SyncAny(ctx, cfg.DiscoveryTime, discover)
+}
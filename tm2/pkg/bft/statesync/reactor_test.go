@@ -0,0 +1,213 @@
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abcicli "github.com/gnolang/gno/tm2/pkg/bft/abci/client"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
+	cfg "github.com/gnolang/gno/tm2/pkg/bft/config"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	p2pTesting "github.com/gnolang/gno/tm2/pkg/internal/p2p"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+	p2pTypes "github.com/gnolang/gno/tm2/pkg/p2p/types"
+)
+
+var testAppHash = []byte("app hash")
+
+// testApp serves a snapshot, or restores it.
+type testApp struct {
+	abci.BaseApplication
+
+	snapshot *abci.Snapshot
+	chunks   [][]byte
+
+	mtx      sync.Mutex
+	offered  *abci.Snapshot
+	applied  [][]byte
+	restored bool
+}
+
+func newTestApp(chunks ...string) *testApp {
+	app := &testApp{
+		snapshot: &abci.Snapshot{Height: 10, Format: 1, Chunks: uint32(len(chunks)), Hash: []byte("hash")},
+	}
+	for _, c := range chunks {
+		app.chunks = append(app.chunks, []byte(c))
+	}
+	return app
+}
+
+func (app *testApp) Info(abci.RequestInfo) abci.ResponseInfo {
+	app.mtx.Lock()
+	defer app.mtx.Unlock()
+	if !app.restored {
+		return abci.ResponseInfo{}
+	}
+	return abci.ResponseInfo{LastBlockHeight: app.offered.Height, LastBlockAppHash: testAppHash}
+}
+
+func (app *testApp) ListSnapshots(abci.RequestListSnapshots) abci.ResponseListSnapshots {
+	return abci.ResponseListSnapshots{Snapshots: []*abci.Snapshot{app.snapshot}}
+}
+
+func (app *testApp) LoadSnapshotChunk(req abci.RequestLoadSnapshotChunk) abci.ResponseLoadSnapshotChunk {
+	if req.Height != app.snapshot.Height || req.Chunk >= uint32(len(app.chunks)) {
+		return abci.ResponseLoadSnapshotChunk{}
+	}
+	return abci.ResponseLoadSnapshotChunk{Chunk: app.chunks[req.Chunk]}
+}
+
+func (app *testApp) OfferSnapshot(req abci.RequestOfferSnapshot) abci.ResponseOfferSnapshot {
+	app.mtx.Lock()
+	defer app.mtx.Unlock()
+	if !bytes.Equal(req.AppHash, testAppHash) {
+		return abci.ResponseOfferSnapshot{ResponseBase: abci.ResponseBase{Error: abci.StringError("invalid app hash")}}
+	}
+	app.offered = req.Snapshot
+	app.applied = nil
+	return abci.ResponseOfferSnapshot{}
+}
+
+// ApplySnapshotChunk accepts the chunks "chunk<index>".
+func (app *testApp) ApplySnapshotChunk(req abci.RequestApplySnapshotChunk) abci.ResponseApplySnapshotChunk {
+	app.mtx.Lock()
+	defer app.mtx.Unlock()
+	if string(req.Chunk) != fmt.Sprintf("chunk%d", req.Index) {
+		return abci.ResponseApplySnapshotChunk{
+			RefetchChunks: []uint32{req.Index},
+			RejectSenders: []string{req.Sender},
+		}
+	}
+	app.applied = append(app.applied, req.Chunk)
+	app.restored = len(app.applied) == int(app.offered.Chunks)
+	return abci.ResponseApplySnapshotChunk{}
+}
+
+// testStateProvider returns the state of the snapshot height.
+type testStateProvider struct{}
+
+func (testStateProvider) AppHash(context.Context, int64) ([]byte, error) {
+	return testAppHash, nil
+}
+
+func (testStateProvider) Commit(_ context.Context, height int64) (*types.Commit, error) {
+	return &types.Commit{BlockID: types.BlockID{Hash: []byte("block")}}, nil
+}
+
+func (testStateProvider) State(_ context.Context, height int64) (sm.State, error) {
+	return sm.State{LastBlockHeight: height, AppHash: testAppHash}, nil
+}
+
+func newTestReactor(t *testing.T, app abci.Application) *Reactor {
+	t.Helper()
+
+	client := abcicli.NewLocalClient(nil, app)
+	r := NewReactor(appconn.NewSnapshot(client), appconn.NewQuery(client))
+	r.SetLogger(log.NewTestingLogger(t))
+	return r
+}
+
+func TestReactorSync(t *testing.T) {
+	t.Parallel()
+
+	config, _ := cfg.ResetTestRoot("statesync_reactor_test")
+	defer os.RemoveAll(config.RootDir)
+
+	// The first peer serves valid chunks, the second one invalid chunks.
+	apps := []*testApp{
+		newTestApp("chunk0", "chunk1", "chunk2", "chunk3"),
+		newTestApp("chunk0", "bad", "bad", "bad"),
+		newTestApp(),
+	}
+	reactors := make([]*Reactor, len(apps))
+	options := make(map[int][]p2p.SwitchOption)
+	for i, app := range apps {
+		reactors[i] = newTestReactor(t, app)
+		options[i] = []p2p.SwitchOption{p2p.WithReactor("STATESYNC", reactors[i])}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	p2pTesting.MakeConnectedPeers(t, ctx, p2pTesting.TestingConfig{
+		Count:         len(apps),
+		P2PCfg:        config.P2P,
+		SwitchOptions: options,
+		Channels:      []byte{SnapshotChannel, ChunkChannel},
+	})
+	defer func() {
+		for _, r := range reactors {
+			r.Stop()
+		}
+	}()
+
+	ssCfg := testConfig()
+	state, commit, err := reactors[2].Sync(ctx, testStateProvider{}, ssCfg)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), state.LastBlockHeight)
+	assert.NotNil(t, commit)
+
+	app := apps[2]
+	require.True(t, app.restored)
+	for i, chunk := range app.applied {
+		assert.Equal(t, fmt.Sprintf("chunk%d", i), string(chunk))
+	}
+}
+
+func TestSyncerNoSnapshots(t *testing.T) {
+	t.Parallel()
+
+	r := newTestReactor(t, newTestApp())
+	s := newSyncer(r.Logger, r.conn, r.connQuery, testStateProvider{}, testConfig(), r.requestChunk)
+	_, _, err := s.SyncAny(context.Background(), 0, func() {})
+	require.ErrorIs(t, err, errNoSnapshots)
+}
+
+func TestSyncerRejectedSnapshot(t *testing.T) {
+	t.Parallel()
+
+	var requested []uint32
+	app := newTestApp()
+	r := newTestReactor(t, app)
+	s := newSyncer(r.Logger, r.conn, r.connQuery, testStateProvider{}, testConfig(),
+		func(_ p2pTypes.ID, _ *abci.Snapshot, index uint32) { requested = append(requested, index) })
+
+	// The app rejects the app hash: the snapshot is rejected, and isn't added
+	// again.
+	snapshot := &abci.Snapshot{Height: 10, Format: 1, Chunks: 1, Hash: []byte("hash")}
+	s.AddSnapshot("peer", snapshot)
+	s.stateProvider = badAppHashProvider{}
+	_, _, err := s.SyncAny(context.Background(), 0, func() {})
+	require.ErrorIs(t, err, errNoSnapshots)
+	assert.Empty(t, requested)
+
+	s.AddSnapshot("peer", snapshot)
+	assert.Nil(t, s.best())
+}
+
+type badAppHashProvider struct {
+	testStateProvider
+}
+
+func (badAppHashProvider) AppHash(context.Context, int64) ([]byte, error) {
+	return []byte("other"), nil
+}
+
+func testConfig() *config.StateSyncConfig {
+	c := config.TestStateSyncConfig()
+	c.ChunkRequestTimeout = 200 * time.Millisecond
+	return c
+}
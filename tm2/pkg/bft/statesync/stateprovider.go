@@ -0,0 +1,192 @@
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/light"
+	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+// StateProvider provides the verified data needed to restore a snapshot, and
+// to bootstrap the node at its height.
+type StateProvider interface {
+	// AppHash returns the app hash after the block at height, from the
+	// header at height+1.
+	AppHash(ctx context.Context, height int64) ([]byte, error)
+
+	// Commit returns the commit for the block at height.
+	Commit(ctx context.Context, height int64) (*types.Commit, error)
+
+	// State returns the state after the block at height.
+	State(ctx context.Context, height int64) (sm.State, error)
+}
+
+// RPCClient is the subset of the RPC client used by the light state
+// provider.
+type RPCClient interface {
+	light.RPCClient
+	ConsensusParams(ctx context.Context, height *int64) (*ctypes.ResultConsensusParams, error)
+}
+
+// lightStateProvider verifies the headers of the RPC servers with a light
+// client.
+type lightStateProvider struct {
+	initialState sm.State
+	opts         light.TrustOptions
+	primary      RPCClient
+	providers    []light.Provider // the primary first, then the witnesses
+
+	mtx     sync.Mutex
+	headers map[int64]*types.SignedHeader // verified headers
+}
+
+// NewLightStateProvider returns a StateProvider verifying the headers of the
+// RPC servers with a light client. The first server is the primary, the
+// others are witnesses. initialState is the genesis state of the node.
+func NewLightStateProvider(initialState sm.State, servers []string, opts light.TrustOptions) (StateProvider, error) {
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("no RPC servers")
+	}
+	clients := make([]RPCClient, 0, len(servers))
+	for _, server := range servers {
+		client, err := rpcclient.NewHTTPClient(server)
+		if err != nil {
+			return nil, fmt.Errorf("creating RPC client for %s: %w", server, err)
+		}
+		clients = append(clients, client)
+	}
+	return newLightStateProvider(initialState, clients, opts)
+}
+
+func newLightStateProvider(initialState sm.State, clients []RPCClient, opts light.TrustOptions) (*lightStateProvider, error) {
+	if err := opts.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	providers := make([]light.Provider, len(clients))
+	for i, client := range clients {
+		providers[i] = light.NewRPCProvider(client)
+	}
+	return &lightStateProvider{
+		initialState: initialState,
+		opts:         opts,
+		primary:      clients[0],
+		providers:    providers,
+		headers:      make(map[int64]*types.SignedHeader),
+	}, nil
+}
+
+// verify returns the verified headers at heights, which must be increasing.
+func (p *lightStateProvider) verify(ctx context.Context, heights ...int64) ([]*types.SignedHeader, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	res := make([]*types.SignedHeader, len(heights))
+	var client *light.Client
+	for i, height := range heights {
+		if sh, ok := p.headers[height]; ok {
+			res[i] = sh
+			continue
+		}
+		// A new client is created for each verification from the trusted
+		// header, since its verified height can only increase.
+		if client == nil || client.TrustedHeight() > height {
+			var err error
+			client, err = light.NewClient(ctx, p.initialState.ChainID, p.opts, p.providers[0], p.providers[1:]...)
+			if err != nil {
+				return nil, err
+			}
+		}
+		sh, err := client.VerifyHeaderAtHeight(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+		p.headers[height] = sh
+		res[i] = sh
+	}
+	return res, nil
+}
+
+// AppHash implements StateProvider.
+func (p *lightStateProvider) AppHash(ctx context.Context, height int64) ([]byte, error) {
+	headers, err := p.verify(ctx, height, height+1)
+	if err != nil {
+		return nil, err
+	}
+	return headers[1].AppHash, nil
+}
+
+// Commit implements StateProvider.
+func (p *lightStateProvider) Commit(ctx context.Context, height int64) (*types.Commit, error) {
+	headers, err := p.verify(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return headers[0].Commit, nil
+}
+
+// State implements StateProvider.
+func (p *lightStateProvider) State(ctx context.Context, height int64) (sm.State, error) {
+	headers, err := p.verify(ctx, height, height+1)
+	if err != nil {
+		return sm.State{}, err
+	}
+	last, current := headers[0], headers[1]
+
+	provider := p.providers[0]
+	lastVals, err := p.validators(ctx, provider, height, last.ValidatorsHash)
+	if err != nil {
+		return sm.State{}, err
+	}
+	vals, err := p.validators(ctx, provider, height+1, current.ValidatorsHash)
+	if err != nil {
+		return sm.State{}, err
+	}
+	nextVals, err := p.validators(ctx, provider, height+2, current.NextValidatorsHash)
+	if err != nil {
+		return sm.State{}, err
+	}
+
+	next := height + 1
+	res, err := p.primary.ConsensusParams(ctx, &next)
+	if err != nil {
+		return sm.State{}, fmt.Errorf("fetching consensus params at height %d: %w", next, err)
+	}
+	params := res.ConsensusParams
+	if !bytes.Equal(params.Hash(), current.ConsensusHash) {
+		return sm.State{}, fmt.Errorf("consensus params at height %d don't match the header", next)
+	}
+
+	state := p.initialState.Copy()
+	state.AppVersion = current.AppVersion
+	state.LastBlockHeight = height
+	state.LastBlockTotalTx = last.TotalTxs
+	state.LastBlockID = current.LastBlockID
+	state.LastBlockTime = last.Time
+	state.LastValidators = lastVals
+	state.Validators = vals
+	state.NextValidators = nextVals
+	state.LastHeightValidatorsChanged = next + 1
+	state.ConsensusParams = params
+	state.LastHeightConsensusParamsChanged = next
+	state.LastResultsHash = current.LastResultsHash
+	state.AppHash = current.AppHash
+	return state, nil
+}
+
+// validators returns the validators at height, which must have hash.
+func (p *lightStateProvider) validators(ctx context.Context, provider light.Provider, height int64, hash []byte) (*types.ValidatorSet, error) {
+	vals, err := provider.ValidatorSet(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("fetching validators at height %d: %w", height, err)
+	}
+	if !bytes.Equal(vals.Hash(), hash) {
+		return nil, fmt.Errorf("validators at height %d don't match the verified headers", height)
+	}
+	return vals, nil
+}
@@ -0,0 +1,526 @@
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	p2pTypes "github.com/gnolang/gno/tm2/pkg/p2p/types"
+)
+
+// fetchInterval is the interval at which chunk requests are retried.
+const fetchInterval = 100 * time.Millisecond
+
+var (
+	errNoSnapshots = errors.New("no suitable snapshots found")
+	errNoPeers     = errors.New("no peers serving the snapshot")
+
+	// errAbort aborts the sync: the application can't restore another
+	// snapshot.
+	errAbort = errors.New("state sync aborted")
+)
+
+// chunk is a chunk of a snapshot received from a peer.
+type chunk struct {
+	Height  int64
+	Format  uint32
+	Index   uint32
+	Chunk   []byte
+	Missing bool
+	Sender  p2pTypes.ID
+}
+
+type snapshotKey [sha256.Size]byte
+
+func keyOf(snapshot *abci.Snapshot) snapshotKey {
+	return sha256.Sum256(amino.MustMarshal(snapshot))
+}
+
+// syncer discovers the snapshots of the peers, and restores one of them into
+// the application.
+type syncer struct {
+	logger        *slog.Logger
+	conn          appconn.Snapshot
+	connQuery     appconn.Query
+	stateProvider StateProvider
+	cfg           *config.StateSyncConfig
+	requestChunk  func(peerID p2pTypes.ID, snapshot *abci.Snapshot, index uint32)
+
+	mtx           sync.Mutex
+	snapshots     map[snapshotKey]*abci.Snapshot
+	peers         map[snapshotKey]map[p2pTypes.ID]struct{} // peers of each snapshot
+	rejected      map[snapshotKey]struct{}
+	rejectedPeers map[p2pTypes.ID]struct{}
+	chunks        *chunkQueue // chunks of the snapshot being restored
+}
+
+func newSyncer(
+	logger *slog.Logger,
+	conn appconn.Snapshot,
+	connQuery appconn.Query,
+	stateProvider StateProvider,
+	cfg *config.StateSyncConfig,
+	requestChunk func(p2pTypes.ID, *abci.Snapshot, uint32),
+) *syncer {
+	return &syncer{
+		logger:        logger,
+		conn:          conn,
+		connQuery:     connQuery,
+		stateProvider: stateProvider,
+		cfg:           cfg,
+		requestChunk:  requestChunk,
+		snapshots:     make(map[snapshotKey]*abci.Snapshot),
+		peers:         make(map[snapshotKey]map[p2pTypes.ID]struct{}),
+		rejected:      make(map[snapshotKey]struct{}),
+		rejectedPeers: make(map[p2pTypes.ID]struct{}),
+	}
+}
+
+// AddSnapshot adds a snapshot advertised by a peer.
+func (s *syncer) AddSnapshot(peerID p2pTypes.ID, snapshot *abci.Snapshot) {
+	key := keyOf(snapshot)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, ok := s.rejected[key]; ok {
+		return
+	}
+	if _, ok := s.rejectedPeers[peerID]; ok {
+		return
+	}
+	if _, ok := s.snapshots[key]; !ok {
+		s.logger.Info("Discovered new snapshot", "height", snapshot.Height, "format", snapshot.Format, "hash", snapshot.Hash)
+		s.snapshots[key] = snapshot
+		s.peers[key] = make(map[p2pTypes.ID]struct{})
+	}
+	s.peers[key][peerID] = struct{}{}
+}
+
+// RemovePeer removes the snapshots of a peer.
+func (s *syncer) RemovePeer(peerID p2pTypes.ID) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for _, peers := range s.peers {
+		delete(peers, peerID)
+	}
+}
+
+// rejectPeer removes the snapshots of a peer, and ignores its future
+// snapshots.
+func (s *syncer) rejectPeer(peerID p2pTypes.ID) {
+	s.RemovePeer(peerID)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.rejectedPeers[peerID] = struct{}{}
+}
+
+// reject removes a snapshot, and ignores it if it's advertised again.
+func (s *syncer) reject(snapshot *abci.Snapshot) {
+	key := keyOf(snapshot)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.snapshots, key)
+	delete(s.peers, key)
+	s.rejected[key] = struct{}{}
+}
+
+// best returns the most recent snapshot served by peers, preferring the
+// snapshots served by more peers.
+func (s *syncer) best() *abci.Snapshot {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var (
+		best      *abci.Snapshot
+		bestPeers int
+	)
+	for key, snapshot := range s.snapshots {
+		peers := len(s.peers[key])
+		if peers == 0 {
+			continue
+		}
+		if best == nil || snapshot.Height > best.Height ||
+			(snapshot.Height == best.Height && peers > bestPeers) {
+			best, bestPeers = snapshot, peers
+		}
+	}
+	return best
+}
+
+// peersOf returns the peers serving snapshot.
+func (s *syncer) peersOf(snapshot *abci.Snapshot) []p2pTypes.ID {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	peers := make([]p2pTypes.ID, 0, len(s.peers[keyOf(snapshot)]))
+	for peerID := range s.peers[keyOf(snapshot)] {
+		peers = append(peers, peerID)
+	}
+	return peers
+}
+
+// removeSnapshotPeer removes a peer which doesn't serve snapshot.
+func (s *syncer) removeSnapshotPeer(snapshot *abci.Snapshot, peerID p2pTypes.ID) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.peers[keyOf(snapshot)], peerID)
+}
+
+// AddChunk adds a chunk of the snapshot being restored.
+func (s *syncer) AddChunk(c *chunk) {
+	s.mtx.Lock()
+	queue := s.chunks
+	s.mtx.Unlock()
+
+	if queue == nil || c.Height != queue.snapshot.Height || c.Format != queue.snapshot.Format {
+		return
+	}
+	if c.Missing {
+		s.logger.Debug("Peer is missing a snapshot chunk", "peer", c.Sender, "index", c.Index)
+		s.removeSnapshotPeer(queue.snapshot, c.Sender)
+		queue.Release(c.Index)
+		return
+	}
+	queue.Add(c)
+}
+
+func (s *syncer) setChunks(queue *chunkQueue) {
+	s.mtx.Lock()
+	s.chunks = queue
+	s.mtx.Unlock()
+}
+
+// SyncAny restores the best snapshot discovered, trying the other snapshots
+// if it fails. The snapshots are discovered again every discoveryTime while
+// no suitable snapshot is found; if discoveryTime is 0, errNoSnapshots is
+// returned instead.
+func (s *syncer) SyncAny(ctx context.Context, discoveryTime time.Duration, discover func()) (sm.State, *types.Commit, error) {
+	if err := sleep(ctx, discoveryTime); err != nil {
+		return sm.State{}, nil, err
+	}
+
+	for {
+		snapshot := s.best()
+		if snapshot == nil {
+			if discoveryTime == 0 {
+				return sm.State{}, nil, errNoSnapshots
+			}
+			s.logger.Info("No suitable snapshot found, discovering snapshots again", "discovery_time", discoveryTime)
+			discover()
+			if err := sleep(ctx, discoveryTime); err != nil {
+				return sm.State{}, nil, err
+			}
+			continue
+		}
+
+		state, commit, err := s.sync(ctx, snapshot)
+		switch {
+		case err == nil:
+			return state, commit, nil
+		case ctx.Err() != nil:
+			return sm.State{}, nil, ctx.Err()
+		case errors.Is(err, errAbort):
+			return sm.State{}, nil, err
+		}
+		s.logger.Info("Failed to restore snapshot, rejecting it", "height", snapshot.Height, "hash", snapshot.Hash, "err", err)
+		s.reject(snapshot)
+	}
+}
+
+// sync restores snapshot into the application.
+func (s *syncer) sync(ctx context.Context, snapshot *abci.Snapshot) (sm.State, *types.Commit, error) {
+	appHash, err := s.stateProvider.AppHash(ctx, snapshot.Height)
+	if err != nil {
+		return sm.State{}, nil, fmt.Errorf("verifying the app hash: %w", err)
+	}
+
+	s.logger.Info("Offering snapshot to the application", "height", snapshot.Height, "hash", snapshot.Hash, "app_hash", appHash)
+	res, err := s.conn.OfferSnapshotSync(abci.RequestOfferSnapshot{Snapshot: snapshot, AppHash: appHash})
+	if err != nil {
+		return sm.State{}, nil, fmt.Errorf("%w: %w", errAbort, err)
+	}
+	if res.Error != nil {
+		return sm.State{}, nil, fmt.Errorf("snapshot rejected by the application: %w", res.Error)
+	}
+
+	queue := newChunkQueue(snapshot, s.cfg.ChunkFetchers)
+	s.setChunks(queue)
+	defer s.setChunks(nil)
+
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go s.fetchChunks(fetchCtx, queue)
+
+	if err := s.applyChunks(ctx, queue); err != nil {
+		return sm.State{}, nil, err
+	}
+
+	// The application state can't be rolled back from now on.
+	if err := s.verifyApp(snapshot, appHash); err != nil {
+		return sm.State{}, nil, fmt.Errorf("%w: %w", errAbort, err)
+	}
+	state, err := s.stateProvider.State(ctx, snapshot.Height)
+	if err != nil {
+		return sm.State{}, nil, fmt.Errorf("%w: building the state: %w", errAbort, err)
+	}
+	commit, err := s.stateProvider.Commit(ctx, snapshot.Height)
+	if err != nil {
+		return sm.State{}, nil, fmt.Errorf("%w: fetching the commit: %w", errAbort, err)
+	}
+
+	s.logger.Info("Restored snapshot", "height", snapshot.Height, "app_hash", appHash)
+	return state, commit, nil
+}
+
+// fetchChunks requests the chunks of queue from the peers serving its
+// snapshot, until ctx is done.
+func (s *syncer) fetchChunks(ctx context.Context, queue *chunkQueue) {
+	ticker := time.NewTicker(fetchInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			peers := s.peersOf(queue.snapshot)
+			if len(peers) == 0 {
+				break
+			}
+			index, ok := queue.Allocate(s.cfg.ChunkRequestTimeout)
+			if !ok {
+				break
+			}
+			peerID := peers[rand.Intn(len(peers))]
+			s.logger.Debug("Requesting snapshot chunk", "peer", peerID, "index", index)
+			s.requestChunk(peerID, queue.snapshot, index)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-queue.released:
+		}
+	}
+}
+
+// applyChunks applies the chunks of queue to the application, in order.
+func (s *syncer) applyChunks(ctx context.Context, queue *chunkQueue) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		index, done := queue.Next()
+		if done {
+			return nil
+		}
+		c := queue.Get(index)
+		if c == nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-queue.arrived:
+			case <-ticker.C:
+				if len(s.peersOf(queue.snapshot)) == 0 {
+					return errNoPeers
+				}
+			}
+			continue
+		}
+
+		res, err := s.conn.ApplySnapshotChunkSync(abci.RequestApplySnapshotChunk{
+			Index:  c.Index,
+			Chunk:  c.Chunk,
+			Sender: string(c.Sender),
+		})
+		if err != nil {
+			return fmt.Errorf("%w: %w", errAbort, err)
+		}
+		for _, sender := range res.RejectSenders {
+			s.logger.Info("Rejecting snapshot peer", "peer", sender)
+			s.rejectPeer(p2pTypes.ID(sender))
+			queue.DiscardSender(p2pTypes.ID(sender))
+		}
+		for _, i := range res.RefetchChunks {
+			queue.Discard(i)
+		}
+		if res.Error != nil {
+			return fmt.Errorf("applying chunk %d: %w", index, res.Error)
+		}
+		if !slices.Contains(res.RefetchChunks, index) {
+			queue.Applied(index)
+		}
+	}
+}
+
+// verifyApp checks that the application restored snapshot, with appHash.
+func (s *syncer) verifyApp(snapshot *abci.Snapshot, appHash []byte) error {
+	res, err := s.connQuery.InfoSync(abci.RequestInfo{})
+	if err != nil {
+		return err
+	}
+	if res.LastBlockHeight != snapshot.Height {
+		return fmt.Errorf("application restored height %d, expected %d", res.LastBlockHeight, snapshot.Height)
+	}
+	if !bytes.Equal(res.LastBlockAppHash, appHash) {
+		return fmt.Errorf("application restored app hash %X, expected %X", res.LastBlockAppHash, appHash)
+	}
+	return nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// chunkQueue tracks the chunks of a snapshot being restored. At most window
+// chunks past the next chunk to apply are requested at once.
+type chunkQueue struct {
+	snapshot *abci.Snapshot
+	window   uint32
+
+	mtx       sync.Mutex
+	next      uint32 // index of the next chunk to apply
+	chunks    map[uint32]*chunk
+	requested map[uint32]time.Time
+
+	arrived  chan struct{} // signaled when a chunk is added
+	released chan struct{} // signaled when a chunk must be requested again
+}
+
+func newChunkQueue(snapshot *abci.Snapshot, window int) *chunkQueue {
+	return &chunkQueue{
+		snapshot:  snapshot,
+		window:    uint32(max(window, 1)),
+		chunks:    make(map[uint32]*chunk),
+		requested: make(map[uint32]time.Time),
+		arrived:   make(chan struct{}, 1),
+		released:  make(chan struct{}, 1),
+	}
+}
+
+// Allocate returns the index of the next chunk to request: a chunk which
+// wasn't received, and wasn't requested in the last timeout.
+func (q *chunkQueue) Allocate(timeout time.Duration) (uint32, bool) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	now := time.Now()
+	end := min(q.next+q.window, q.snapshot.Chunks)
+	for index := q.next; index < end; index++ {
+		if _, ok := q.chunks[index]; ok {
+			continue
+		}
+		if at, ok := q.requested[index]; ok && now.Sub(at) < timeout {
+			continue
+		}
+		q.requested[index] = now
+		return index, true
+	}
+	return 0, false
+}
+
+// Release marks a chunk as not requested, to request it again.
+func (q *chunkQueue) Release(index uint32) {
+	q.mtx.Lock()
+	delete(q.requested, index)
+	q.mtx.Unlock()
+	notify(q.released)
+}
+
+// Add adds a received chunk, unless it was already received.
+func (q *chunkQueue) Add(c *chunk) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if c.Index < q.next || c.Index >= q.snapshot.Chunks {
+		return
+	}
+	if _, ok := q.chunks[c.Index]; ok {
+		return
+	}
+	q.chunks[c.Index] = c
+	notify(q.arrived)
+}
+
+// Get returns the received chunk at index, if any.
+func (q *chunkQueue) Get(index uint32) *chunk {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	return q.chunks[index]
+}
+
+// Discard discards the chunk at index, to request it again.
+func (q *chunkQueue) Discard(index uint32) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	q.discard(index)
+}
+
+// DiscardSender discards the chunks received from a peer which weren't
+// applied yet.
+func (q *chunkQueue) DiscardSender(peerID p2pTypes.ID) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	for index, c := range q.chunks {
+		if c.Sender == peerID {
+			q.discard(index)
+		}
+	}
+}
+
+func (q *chunkQueue) discard(index uint32) {
+	delete(q.chunks, index)
+	delete(q.requested, index)
+	notify(q.released)
+}
+
+// Next returns the index of the next chunk to apply, or reports that all the
+// chunks were applied.
+func (q *chunkQueue) Next() (uint32, bool) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	return q.next, q.next >= q.snapshot.Chunks
+}
+
+// Applied marks the chunk at index as applied.
+func (q *chunkQueue) Applied(index uint32) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	if index != q.next {
+		return
+	}
+	delete(q.chunks, index)
+	delete(q.requested, index)
+	q.next++
+	notify(q.released)
+}
+
+// notify signals ch without blocking.
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
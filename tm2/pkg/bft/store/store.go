@@ -29,6 +29,7 @@ type BlockStore struct {
 	db dbm.DB
 
 	mtx    sync.RWMutex
+	base   int64
 	height int64
 }
 
@@ -37,6 +38,7 @@ type BlockStore struct {
 func NewBlockStore(db dbm.DB) *BlockStore {
 	bsjson := LoadBlockStoreStateJSON(db)
 	return &BlockStore{
+		base:   bsjson.Base,
 		height: bsjson.Height,
 		db:     db,
 	}
@@ -49,6 +51,101 @@ func (bs *BlockStore) Height() int64 {
 	return bs.height
 }
 
+// Base returns the first height of the blocks in the store, or 0 if they
+// start at the first height of the chain.
+func (bs *BlockStore) Base() int64 {
+	bs.mtx.RLock()
+	defer bs.mtx.RUnlock()
+	return bs.base
+}
+
+// Bootstrap initializes an empty store at height, whose state was restored
+// from a state sync snapshot instead of replaying the blocks. The blocks are
+// then saved from height+1; seenCommit is the commit for the block at height,
+// from which consensus reconstructs its last commit.
+func (bs *BlockStore) Bootstrap(height int64, seenCommit *types.Commit) error {
+	if h := bs.Height(); h != 0 {
+		return fmt.Errorf("cannot bootstrap a block store at height %d", h)
+	}
+	if height <= 0 {
+		return fmt.Errorf("invalid bootstrap height %d", height)
+	}
+	if seenCommit == nil || seenCommit.Height() != height {
+		return fmt.Errorf("no commit for the bootstrap height %d", height)
+	}
+
+	bs.db.Set(calcSeenCommitKey(height), amino.MustMarshal(seenCommit))
+	BlockStoreStateJSON{Base: height + 1, Height: height}.Save(bs.db)
+
+	bs.mtx.Lock()
+	bs.base = height + 1
+	bs.height = height
+	bs.mtx.Unlock()
+	return nil
+}
+
+// PruneBlocks deletes the blocks, and their commits, from the base of the
+// store up to retainHeight (exclusive), and returns the number of pruned
+// blocks. The base is moved to retainHeight before the blocks are deleted, so
+// that a block is never loaded while being pruned. The last block can't be
+// pruned, as consensus reconstructs its last commit from the seen commit.
+func (bs *BlockStore) PruneBlocks(retainHeight int64) (int64, error) {
+	if retainHeight <= 0 {
+		return 0, fmt.Errorf("invalid retain height %d", retainHeight)
+	}
+	if h := bs.Height(); retainHeight > h {
+		return 0, fmt.Errorf("cannot prune beyond the latest height %d", h)
+	}
+	base := max(bs.Base(), 1)
+	if retainHeight <= base {
+		return 0, nil
+	}
+
+	bs.mtx.Lock()
+	bs.base = retainHeight
+	BlockStoreStateJSON{Base: bs.base, Height: bs.height}.Save(bs.db)
+	bs.mtx.Unlock()
+
+	// Delete the blocks in batches, flushed every pruneBatchSize heights.
+	batch := bs.db.NewBatch()
+	defer func() { batch.Close() }()
+	flush := func() error {
+		if err := batch.WriteSync(); err != nil {
+			return err
+		}
+		batch.Close()
+		batch = bs.db.NewBatch()
+		return nil
+	}
+	for height := base; height < retainHeight; height++ {
+		if meta := bs.LoadBlockMeta(height); meta != nil {
+			for i := range meta.BlockID.PartsHeader.Total {
+				if err := batch.Delete(calcBlockPartKey(height, i)); err != nil {
+					return 0, err
+				}
+			}
+			if err := batch.Delete(calcBlockMetaKey(height)); err != nil {
+				return 0, err
+			}
+		}
+		if err := batch.Delete(calcBlockCommitKey(height)); err != nil {
+			return 0, err
+		}
+		if err := batch.Delete(calcSeenCommitKey(height)); err != nil {
+			return 0, err
+		}
+		if (height-base+1)%pruneBatchSize == 0 {
+			if err := flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if err := batch.WriteSync(); err != nil {
+		return 0, err
+	}
+	return retainHeight - base, nil
+}
+
 // LoadBlock returns the block with the given height.
 // If no block is found for that height, it returns nil.
 func (bs *BlockStore) LoadBlock(height int64) *types.Block {
@@ -187,11 +284,12 @@ func (bs *BlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, s
 	seenCommitBytes := amino.MustMarshal(seenCommit)
 	bs.db.Set(calcSeenCommitKey(height), seenCommitBytes)
 
-	// Save new BlockStoreStateJSON descriptor
-	BlockStoreStateJSON{Height: height}.Save(bs.db)
+	// Save new BlockStoreStateJSON descriptor, under the lock as the base
+	// may be moved concurrently by PruneBlocks
+	bs.mtx.Lock()
+	BlockStoreStateJSON{Base: bs.base, Height: height}.Save(bs.db)
 
 	// Done!
-	bs.mtx.Lock()
 	bs.height = height
 	bs.mtx.Unlock()
 
@@ -209,6 +307,9 @@ func (bs *BlockStore) saveBlockPart(height int64, index int, part *types.Part) {
 
 //-----------------------------------------------------------------------------
 
+// pruneBatchSize is the number of heights deleted in a batch by PruneBlocks.
+const pruneBatchSize = 1000
+
 func calcBlockMetaKey(height int64) []byte {
 	return fmt.Appendf(nil, "H:%v", height)
 }
@@ -231,6 +332,7 @@ var blockStoreKey = []byte("blockStore")
 
 // BlockStoreStateJSON is the block store state JSON structure.
 type BlockStoreStateJSON struct {
+	Base   int64 `json:"base,omitempty"`
 	Height int64 `json:"height"`
 }
 
@@ -421,6 +421,86 @@ func TestBlockFetchAtHeight(t *testing.T) {
 	require.Nil(t, blockAtHeightPlus2, "expecting an unsuccessful load of Height()+2")
 }
 
+func TestBlockStoreBootstrap(t *testing.T) {
+	t.Parallel()
+
+	_, bs, cleanup := makeStateAndBlockStore(log.NewNoopLogger())
+	defer cleanup()
+
+	require.Error(t, bs.Bootstrap(10, nil))
+	require.Error(t, bs.Bootstrap(10, makeTestCommit(9, tmtime.Now())))
+
+	seenCommit := makeTestCommit(10, tmtime.Now())
+	require.NoError(t, bs.Bootstrap(10, seenCommit))
+	assert.Equal(t, int64(10), bs.Height())
+	assert.Equal(t, int64(11), bs.Base())
+	assert.Equal(t, seenCommit.Hash(), bs.LoadSeenCommit(10).Hash())
+	require.Error(t, bs.Bootstrap(10, seenCommit))
+
+	// The next block can be saved, and the base is kept.
+	block := types.MakeBlock(11, makeTxs(11), seenCommit)
+	bs.SaveBlock(block, block.MakePartSet(2), makeTestCommit(11, tmtime.Now()))
+	assert.Equal(t, int64(11), bs.Height())
+
+	bs = NewBlockStore(bs.db)
+	assert.Equal(t, int64(11), bs.Height())
+	assert.Equal(t, int64(11), bs.Base())
+}
+
+func TestBlockStorePruneBlocks(t *testing.T) {
+	t.Parallel()
+
+	_, bs, cleanup := makeStateAndBlockStore(log.NewNoopLogger())
+	defer cleanup()
+
+	_, err := bs.PruneBlocks(1)
+	require.Error(t, err, "nothing to prune in an empty store")
+
+	lastCommit := new(types.Commit)
+	for height := int64(1); height <= 10; height++ {
+		block := types.MakeBlock(height, makeTxs(height), lastCommit)
+		seenCommit := makeTestCommit(height, tmtime.Now())
+		bs.SaveBlock(block, block.MakePartSet(2), seenCommit)
+		lastCommit = seenCommit
+	}
+
+	_, err = bs.PruneBlocks(0)
+	require.Error(t, err)
+	_, err = bs.PruneBlocks(11)
+	require.Error(t, err, "the last block can't be pruned")
+
+	pruned, err := bs.PruneBlocks(6)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), pruned)
+	assert.Equal(t, int64(6), bs.Base())
+	assert.Equal(t, int64(10), bs.Height())
+	for height := int64(1); height < 6; height++ {
+		assert.Nil(t, bs.LoadBlockMeta(height))
+		assert.Nil(t, bs.LoadBlock(height))
+		assert.Nil(t, bs.LoadBlockPart(height, 0))
+		assert.Nil(t, bs.LoadBlockCommit(height))
+		assert.Nil(t, bs.LoadSeenCommit(height))
+	}
+	for height := int64(6); height <= 10; height++ {
+		assert.NotNil(t, bs.LoadBlock(height))
+		assert.NotNil(t, bs.LoadSeenCommit(height))
+	}
+
+	// Pruning below the base is a no-op.
+	pruned, err = bs.PruneBlocks(4)
+	require.NoError(t, err)
+	assert.Zero(t, pruned)
+
+	// The base is persisted.
+	bs = NewBlockStore(bs.db)
+	assert.Equal(t, int64(6), bs.Base())
+
+	pruned, err = bs.PruneBlocks(10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), pruned)
+	assert.NotNil(t, bs.LoadBlock(10))
+}
+
 func doFn(fn func() (any, error)) (res any, err error, panicErr error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
+	"github.com/gnolang/gno/tm2/pkg/crypto/secp256r1"
+)
+
+var errInvalidPasskeyPubKey = errors.New("invalid passkey public key")
+
+type AddPasskeyCfg struct {
+	RootCfg *AddCfg
+
+	PublicKey string
+}
+
+// NewAddPasskeyCmd creates a gnokey add passkey command
+func NewAddPasskeyCmd(rootCfg *AddCfg, io commands.IO) *commands.Command {
+	cfg := &AddPasskeyCfg{
+		RootCfg: rootCfg,
+	}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "passkey",
+			ShortUsage: "add passkey [flags] <key-name>",
+			ShortHelp:  "adds the secp256r1 public key of a passkey to the keybase",
+			LongHelp: `Adds the public key of a passkey (WebAuthn credential) to the keybase, as an
+offline key. The public key is the one returned by getPublicKey() on the
+registration response, in base64 or hex: either its DER SubjectPublicKeyInfo,
+or its compressed or uncompressed SEC 1 form.
+
+Txs of the key are signed by the passkey, with WebAuthn assertions whose
+challenge is the SHA-256 of the sign bytes.`,
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execAddPasskey(cfg, args, io)
+		},
+	)
+}
+
+func (c *AddPasskeyCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.PublicKey,
+		"pubkey",
+		"",
+		"public key of the passkey, in base64 or hex",
+	)
+}
+
+func execAddPasskey(cfg *AddPasskeyCfg, args []string, io commands.IO) error {
+	// Validate a key name was provided
+	if len(args) != 1 {
+		return flag.ErrHelp
+	}
+
+	name := args[0]
+
+	// Read the keybase from the home directory
+	kb, err := keys.NewKeyBaseFromDir(cfg.RootCfg.RootCfg.Home)
+	if err != nil {
+		return fmt.Errorf("unable to read keybase, %w", err)
+	}
+
+	// Parse the public key
+	publicKey, err := parsePasskeyPubKey(cfg.PublicKey)
+	if err != nil {
+		return fmt.Errorf("unable to parse public key, %w", err)
+	}
+
+	// If not forcing, check for collisions with existing keys
+	if !cfg.RootCfg.Force {
+		// Derive the address to check for collision
+		newAddress := publicKey.Address()
+
+		// Handle address / name collision if any
+		handled, err := handleCollision(kb, name, newAddress, keys.TypeOffline, io)
+		if err != nil {
+			return err
+		}
+		// If a collision was found and handled, we can skip saving the new key
+		if handled {
+			return nil
+		}
+	}
+
+	// Save it offline in the keybase
+	_, err = kb.CreateOffline(name, publicKey)
+	if err != nil {
+		return fmt.Errorf("unable to save public key, %w", err)
+	}
+
+	io.Printfln("Key %q saved to disk.\n", name)
+	io.Printfln("* Address:    %s", publicKey.Address())
+	io.Printfln("* Public key: %s", publicKey)
+
+	return nil
+}
+
+// parsePasskeyPubKey parses a secp256r1 public key in base64 or hex, either
+// as a DER SubjectPublicKeyInfo or in its SEC 1 form.
+func parsePasskeyPubKey(s string) (secp256r1.PubKeySecp256r1, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return secp256r1.PubKeySecp256r1{}, errInvalidPasskeyPubKey
+	}
+
+	bz, err := hex.DecodeString(s)
+	if err != nil {
+		bz, err = decodeBase64(s)
+		if err != nil {
+			return secp256r1.PubKeySecp256r1{}, fmt.Errorf("%w: neither hex nor base64", errInvalidPasskeyPubKey)
+		}
+	}
+
+	// DER SubjectPublicKeyInfo, as returned by getPublicKey()
+	if pub, err := x509.ParsePKIXPublicKey(bz); err == nil {
+		epub, ok := pub.(*ecdsa.PublicKey)
+		if !ok {
+			return secp256r1.PubKeySecp256r1{}, fmt.Errorf("%w: not an ECDSA key", errInvalidPasskeyPubKey)
+		}
+		ecdhPub, err := epub.ECDH()
+		if err != nil {
+			return secp256r1.PubKeySecp256r1{}, fmt.Errorf("%w: %w", errInvalidPasskeyPubKey, err)
+		}
+		bz = ecdhPub.Bytes()
+	}
+
+	return secp256r1.PubKeyFromBytes(bz)
+}
+
+// decodeBase64 decodes s in standard or URL base64, padded or not.
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	if strings.ContainsAny(s, "-_") {
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+	return base64.RawStdEncoding.DecodeString(s)
+}
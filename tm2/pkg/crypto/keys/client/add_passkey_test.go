@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
+	"github.com/gnolang/gno/tm2/pkg/crypto/secp256r1"
+)
+
+func TestAdd_Passkey(t *testing.T) {
+	t.Parallel()
+
+	priv := secp256r1.GenPrivKey()
+	pubKey := priv.PubKey().(secp256r1.PubKeySecp256r1)
+
+	x, y := elliptic.UnmarshalCompressed(elliptic.P256(), pubKey[:])
+	spki, err := x509.MarshalPKIXPublicKey(&ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y})
+	require.NoError(t, err)
+
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	spki384, err := x509.MarshalPKIXPublicKey(&p384.PublicKey)
+	require.NoError(t, err)
+
+	testTable := []struct {
+		name   string
+		pubkey string
+		valid  bool
+	}{
+		{"SPKI base64", base64.StdEncoding.EncodeToString(spki), true},
+		{"SPKI base64url", base64.RawURLEncoding.EncodeToString(spki), true},
+		{"compressed hex", hex.EncodeToString(pubKey[:]), true},
+		{"empty", "", false},
+		{"not base64", "not a key!", false},
+		{"truncated", hex.EncodeToString(pubKey[:20]), false},
+		{"P-384", base64.StdEncoding.EncodeToString(spki384), false},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				kbHome      = t.TempDir()
+				baseOptions = BaseOptions{
+					InsecurePasswordStdin: true,
+					Home:                  kbHome,
+				}
+
+				keyName = "passkey"
+			)
+
+			ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancelFn()
+
+			io := commands.NewTestIO()
+			io.SetIn(strings.NewReader("test1234\ntest1234\n"))
+
+			// Create the command
+			cmd := NewRootCmdWithBaseConfig(io, baseOptions)
+
+			args := []string{
+				"add",
+				"passkey",
+				"--insecure-password-stdin",
+				"--home",
+				kbHome,
+				"--pubkey",
+				testCase.pubkey,
+				keyName,
+			}
+
+			err := cmd.ParseAndRun(ctx, args)
+			if !testCase.valid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			// Check the keybase
+			kb, err := keys.NewKeyBaseFromDir(kbHome)
+			require.NoError(t, err)
+
+			info, err := kb.GetByName(keyName)
+			require.NoError(t, err)
+			assert.Equal(t, keys.TypeOffline, info.GetType())
+			assert.Equal(t, pubKey, info.GetPubKey())
+			assert.Equal(t, pubKey.Address(), info.GetAddress())
+		})
+	}
+}
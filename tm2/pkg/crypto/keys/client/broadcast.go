@@ -28,6 +28,9 @@ type BroadcastCfg struct {
 	// If true, simulation is attempted but not printed;
 	// the result is only returned in case of an error.
 	testSimulate bool
+	// Set by SignAndBroadcastHandler: the file where the gas profile of
+	// the simulation is written.
+	gasProfile string
 }
 
 func NewBroadcastCmd(rootCfg *BaseCfg, io commands.IO) *commands.Command {
@@ -123,14 +126,25 @@ func BroadcastHandler(cfg *BroadcastCfg) (*ctypes.ResultBroadcastTxCommit, error
 	// Both for DryRun and testSimulate, we perform simulation.
 	// However, DryRun always returns here, while in case of success
 	// testSimulate continues onto broadcasting the transaction.
+	var profileInfo string
 	if cfg.DryRun || cfg.testSimulate {
-		res, err := SimulateTx(cli, bz)
+		res, profile, err := simulateTx(cli, bz, cfg.gasProfile != "")
+		if err == nil && cfg.gasProfile != "" {
+			// The profile is written even if the transaction failed,
+			// e.g. to find out where it ran out of gas.
+			if err := os.WriteFile(cfg.gasProfile, profile, 0o644); err != nil {
+				return nil, errors.Wrap(err, "writing gas profile")
+			}
+			profileInfo = fmt.Sprintf("gas profile written to %s\n", cfg.gasProfile)
+			res.DeliverTx.Info += profileInfo
+		}
 		hasError := err != nil || res.CheckTx.IsErr() || res.DeliverTx.IsErr()
 		if hasError {
 			return res, err
 		}
 		if cfg.DryRun { // we estmate the gas fee in dry run
 			err = estimateGasFee(cli, res)
+			res.DeliverTx.Info += profileInfo
 			return res, err
 		}
 	}
@@ -139,6 +153,7 @@ func BroadcastHandler(cfg *BroadcastCfg) (*ctypes.ResultBroadcastTxCommit, error
 	if err != nil {
 		return nil, errors.Wrap(err, "broadcasting bytes")
 	}
+	bres.DeliverTx.Info += profileInfo
 
 	return bres, nil
 }
@@ -169,18 +184,32 @@ func estimateGasFee(cli client.ABCIClient, bres *ctypes.ResultBroadcastTxCommit)
 }
 
 func SimulateTx(cli client.ABCIClient, tx []byte) (*ctypes.ResultBroadcastTxCommit, error) {
-	bres, err := cli.ABCIQuery(context.Background(), ".app/simulate", tx)
+	res, _, err := simulateTx(cli, tx, false)
+	return res, err
+}
+
+// simulateTx simulates tx, and returns its gas profile, in pprof format, if
+// profile is set.
+func simulateTx(cli client.ABCIClient, tx []byte, profile bool) (*ctypes.ResultBroadcastTxCommit, []byte, error) {
+	path := ".app/simulate"
+	if profile {
+		path += "?profile=1"
+	}
+	bres, err := cli.ABCIQuery(context.Background(), path, tx)
 	if err != nil {
-		return nil, errors.Wrap(err, "simulate tx")
+		return nil, nil, errors.Wrap(err, "simulate tx")
+	}
+	if profile && bres.Response.Error != nil {
+		return nil, nil, errors.Wrap(bres.Response.Error, "simulate tx with a gas profile")
 	}
 
 	var result abci.ResponseDeliverTx
 	err = amino.Unmarshal(bres.Response.Value, &result)
 	if err != nil {
-		return nil, errors.Wrap(err, "unmarshaling simulate result")
+		return nil, nil, errors.Wrap(err, "unmarshaling simulate result")
 	}
 
 	return &ctypes.ResultBroadcastTxCommit{
 		DeliverTx: result,
-	}, nil
+	}, bres.Response.Data, nil
 }
@@ -0,0 +1,222 @@
+package client
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+type MakeGrantCfg struct {
+	RootCfg *MakeTxCfg
+
+	Grantee         string
+	SpendLimit      string
+	Expiration      string
+	AllowedMsgs     commands.StringArr
+	AllowedPkgPaths commands.StringArr
+}
+
+func NewMakeGrantCmd(rootCfg *MakeTxCfg, io commands.IO) *commands.Command {
+	cfg := &MakeGrantCfg{
+		RootCfg: rootCfg,
+	}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "grant",
+			ShortUsage: "grant [flags] <key-name or address>",
+			ShortHelp:  "grants a fee allowance to another account",
+			LongHelp: `Grants a fee allowance to the grantee, letting it set the key as the fee granter
+of its txs (-fee-granter) to have their gas fees paid by the key. The allowance
+replaces any previous allowance of the key for the grantee.`,
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execMakeGrant(cfg, args, io)
+		},
+	)
+}
+
+func (c *MakeGrantCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.Grantee,
+		"grantee",
+		"",
+		"address of the account whose fees are paid",
+	)
+
+	fs.StringVar(
+		&c.SpendLimit,
+		"spend-limit",
+		"",
+		"total amount of fees paid, unlimited if empty",
+	)
+
+	fs.StringVar(
+		&c.Expiration,
+		"expiration",
+		"",
+		"RFC 3339 time from which the allowance can no longer be used, never if empty",
+	)
+
+	fs.Var(
+		&c.AllowedMsgs,
+		"allowed-msg",
+		"type URL of a msg the allowance pays for, like /vm.m_call (repeatable); any msg if unset",
+	)
+
+	fs.Var(
+		&c.AllowedPkgPaths,
+		"allowed-pkgpath",
+		"path of a package the msgs must target, like a called realm (repeatable); any if unset",
+	)
+}
+
+func execMakeGrant(cfg *MakeGrantCfg, args []string, io commands.IO) error {
+	if len(args) != 1 {
+		return flag.ErrHelp
+	}
+	if cfg.Grantee == "" {
+		return errors.New("grantee must be specified")
+	}
+
+	var allowance auth.FeeAllowance
+	if cfg.SpendLimit != "" {
+		limit, err := std.ParseCoins(cfg.SpendLimit)
+		if err != nil {
+			return errors.Wrap(err, "parsing spend limit coins")
+		}
+		allowance.SpendLimit = limit
+	}
+	if cfg.Expiration != "" {
+		expiration, err := time.Parse(time.RFC3339, cfg.Expiration)
+		if err != nil {
+			return errors.Wrap(err, "parsing expiration")
+		}
+		allowance.Expiration = expiration
+	}
+	allowance.AllowedMsgs = cfg.AllowedMsgs
+	allowance.AllowedPkgPaths = cfg.AllowedPkgPaths
+
+	return makeGrantTx(cfg.RootCfg, args, cfg.Grantee, func(granter, grantee crypto.Address) std.Msg {
+		return auth.NewMsgGrantAllowance(granter, grantee, allowance)
+	}, io)
+}
+
+type MakeRevokeCfg struct {
+	RootCfg *MakeTxCfg
+
+	Grantee string
+}
+
+func NewMakeRevokeCmd(rootCfg *MakeTxCfg, io commands.IO) *commands.Command {
+	cfg := &MakeRevokeCfg{
+		RootCfg: rootCfg,
+	}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "revoke",
+			ShortUsage: "revoke [flags] <key-name or address>",
+			ShortHelp:  "revokes the fee allowance granted to another account",
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execMakeRevoke(cfg, args, io)
+		},
+	)
+}
+
+func (c *MakeRevokeCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.Grantee,
+		"grantee",
+		"",
+		"address of the account whose fees are no longer paid",
+	)
+}
+
+func execMakeRevoke(cfg *MakeRevokeCfg, args []string, io commands.IO) error {
+	if len(args) != 1 {
+		return flag.ErrHelp
+	}
+	if cfg.Grantee == "" {
+		return errors.New("grantee must be specified")
+	}
+
+	return makeGrantTx(cfg.RootCfg, args, cfg.Grantee, func(granter, grantee crypto.Address) std.Msg {
+		return auth.NewMsgRevokeAllowance(granter, grantee)
+	}, io)
+}
+
+// makeGrantTx prints or broadcasts the tx of the msg of the granter key in
+// args for grantee.
+func makeGrantTx(
+	cfg *MakeTxCfg,
+	args []string,
+	grantee string,
+	newMsg func(granter, grantee crypto.Address) std.Msg,
+	io commands.IO,
+) error {
+	if cfg.GasWanted == 0 {
+		return errors.New("gas-wanted not specified")
+	}
+	if cfg.GasFee == "" {
+		return errors.New("gas-fee not specified")
+	}
+
+	// read account pubkey.
+	nameOrBech32 := args[0]
+	kb, err := keys.NewKeyBaseFromDir(cfg.RootCfg.Home)
+	if err != nil {
+		return err
+	}
+	info, err := kb.GetByNameOrAddress(nameOrBech32)
+	if err != nil {
+		return err
+	}
+	granterAddr := info.GetAddress()
+
+	// Parse grantee address.
+	granteeAddr, err := crypto.AddressFromBech32(grantee)
+	if err != nil {
+		return err
+	}
+
+	// parse gas wanted & fee.
+	gaswanted := cfg.GasWanted
+	gasfee, err := std.ParseCoin(cfg.GasFee)
+	if err != nil {
+		return errors.Wrap(err, "parsing gas fee coin")
+	}
+
+	// construct msg & tx and marshal.
+	msg := newMsg(granterAddr, granteeAddr)
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+	tx := std.Tx{
+		Msgs:       []std.Msg{msg},
+		Fee:        cfg.NewFee(gaswanted, gasfee),
+		Signatures: nil,
+		Memo:       cfg.Memo,
+	}
+
+	if cfg.Broadcast {
+		err := ExecSignAndBroadcast(cfg, args, tx, io)
+		if err != nil {
+			return err
+		}
+	} else {
+		io.Println(string(amino.MustMarshalJSON(tx)))
+	}
+	return nil
+}
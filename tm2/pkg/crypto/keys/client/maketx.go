@@ -8,6 +8,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	"github.com/gnolang/gno/tm2/pkg/std"
@@ -16,14 +17,19 @@ import (
 type MakeTxCfg struct {
 	RootCfg *BaseCfg
 
-	GasWanted int64
-	GasFee    string
-	Memo      string
+	GasWanted  int64
+	GasFee     string
+	FeeGranter string
+	Memo       string
 
 	Broadcast bool
 	// Valid options are SimulateTest, SimulateSkip or SimulateOnly.
 	Simulate string
 	ChainID  string
+
+	// GasProfile is the file where the gas profile of the simulated
+	// transaction is written, in pprof format.
+	GasProfile string
 }
 
 // These are the valid options for MakeTxConfig.Simulate.
@@ -39,9 +45,24 @@ func (c *MakeTxCfg) Validate() error {
 	default:
 		return fmt.Errorf("invalid simulate option: %q", c.Simulate)
 	}
+	if c.GasProfile != "" && c.Simulate == SimulateSkip {
+		return errors.New("a gas profile requires simulating the transaction")
+	}
+	if c.FeeGranter != "" {
+		if _, err := crypto.AddressFromBech32(c.FeeGranter); err != nil {
+			return fmt.Errorf("invalid fee granter %q: %w", c.FeeGranter, err)
+		}
+	}
 	return nil
 }
 
+// NewFee returns the fee of the tx, paid by the fee granter if set.
+func (c *MakeTxCfg) NewFee(gasWanted int64, gasFee std.Coin) std.Fee {
+	fee := std.NewFee(gasWanted, gasFee)
+	fee.Granter = c.FeeGranter
+	return fee
+}
+
 func NewMakeTxCmd(rootCfg *BaseCfg, io commands.IO) *commands.Command {
 	cfg := &MakeTxCfg{
 		RootCfg: rootCfg,
@@ -59,6 +80,8 @@ func NewMakeTxCmd(rootCfg *BaseCfg, io commands.IO) *commands.Command {
 
 	cmd.AddSubCommands(
 		NewMakeSendCmd(cfg, io),
+		NewMakeGrantCmd(cfg, io),
+		NewMakeRevokeCmd(cfg, io),
 	)
 
 	return cmd
@@ -79,6 +102,13 @@ func (c *MakeTxCfg) RegisterFlags(fs *flag.FlagSet) {
 		"gas payment fee",
 	)
 
+	fs.StringVar(
+		&c.FeeGranter,
+		"fee-granter",
+		"",
+		"address of the account paying the gas fee out of the fee allowance it granted to the signer",
+	)
+
 	fs.StringVar(
 		&c.Memo,
 		"memo",
@@ -109,6 +139,13 @@ func (c *MakeTxCfg) RegisterFlags(fs *flag.FlagSet) {
 		"dev",
 		"chainid to sign for (only useful with --broadcast)",
 	)
+
+	fs.StringVar(
+		&c.GasProfile,
+		"gasprofile",
+		"",
+		"write the gas profile of the simulated transaction in pprof format to the file (only useful with --broadcast)",
+	)
 }
 
 func SignAndBroadcastHandler(
@@ -178,6 +215,7 @@ func SignAndBroadcastHandler(
 
 		DryRun:       cfg.Simulate == SimulateOnly,
 		testSimulate: cfg.Simulate == SimulateTest,
+		gasProfile:   cfg.GasProfile,
 	}
 
 	return BroadcastHandler(bopts)
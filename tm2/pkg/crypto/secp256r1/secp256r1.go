@@ -0,0 +1,215 @@
+// Package secp256r1 implements the keys of the NIST P-256 curve, as used by
+// the passkeys of browsers and mobile platforms.
+//
+// Besides plain ECDSA signatures, a PubKeySecp256r1 verifies WebAuthn
+// assertions, letting a passkey sign a message through the challenge of the
+// assertion. See [WebAuthnSignature].
+package secp256r1
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/tmhash"
+)
+
+// SignatureSize is the size of a plain signature of the form R || S.
+const SignatureSize = 64
+
+var (
+	curveOrder     = elliptic.P256().Params().N
+	halfCurveOrder = new(big.Int).Rsh(curveOrder, 1)
+)
+
+//-------------------------------------
+
+var _ crypto.PrivKey = PrivKeySecp256r1{}
+
+// PrivKeySecp256r1 implements crypto.PrivKey.
+// It is the big-endian scalar of the private key.
+type PrivKeySecp256r1 [32]byte
+
+// Bytes marshals the privkey using amino encoding.
+func (privKey PrivKeySecp256r1) Bytes() []byte {
+	return amino.MustMarshalAny(privKey)
+}
+
+// Sign creates an ECDSA signature on curve P-256, using SHA256 on the msg.
+// The returned signature will be of the form R || S (in lower-S form).
+func (privKey PrivKeySecp256r1) Sign(msg []byte) ([]byte, error) {
+	priv, err := privKey.ecdsa()
+	if err != nil {
+		return nil, err
+	}
+	r, s, err := ecdsa.Sign(crypto.CReader(), priv, crypto.Sha256(msg))
+	if err != nil {
+		return nil, err
+	}
+	if s.Cmp(halfCurveOrder) > 0 {
+		s.Sub(curveOrder, s)
+	}
+
+	sig := make([]byte, SignatureSize)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return sig, nil
+}
+
+// PubKey performs the point-scalar multiplication from the privKey on the
+// generator point to get the pubkey.
+func (privKey PrivKeySecp256r1) PubKey() crypto.PubKey {
+	priv, err := ecdh.P256().NewPrivateKey(privKey[:])
+	if err != nil {
+		panic(fmt.Sprintf("invalid secp256r1 private key: %v", err))
+	}
+	pubKey, err := PubKeyFromBytes(priv.PublicKey().Bytes())
+	if err != nil {
+		panic(err)
+	}
+	return pubKey
+}
+
+// Equals - you probably don't need to use this.
+// Runs in constant time based on length of the keys.
+func (privKey PrivKeySecp256r1) Equals(other crypto.PrivKey) bool {
+	if otherSecp, ok := other.(PrivKeySecp256r1); ok {
+		return subtle.ConstantTimeCompare(privKey[:], otherSecp[:]) == 1
+	}
+	return false
+}
+
+func (privKey PrivKeySecp256r1) ecdsa() (*ecdsa.PrivateKey, error) {
+	pubKey, ok := privKey.PubKey().(PubKeySecp256r1)
+	if !ok {
+		return nil, errors.New("invalid secp256r1 public key")
+	}
+	pub, err := pubKey.ecdsa()
+	if err != nil {
+		return nil, err
+	}
+	return &ecdsa.PrivateKey{
+		PublicKey: *pub,
+		D:         new(big.Int).SetBytes(privKey[:]),
+	}, nil
+}
+
+// GenPrivKey generates a new ECDSA private key on curve P-256.
+// It uses OS randomness to generate the private key.
+func GenPrivKey() PrivKeySecp256r1 {
+	return genPrivKey(crypto.CReader())
+}
+
+// genPrivKey generates a new secp256r1 private key using the provided reader.
+func genPrivKey(rand io.Reader) PrivKeySecp256r1 {
+	var privKeyBytes [32]byte
+	for {
+		_, err := io.ReadFull(rand, privKeyBytes[:])
+		if err != nil {
+			panic(err)
+		}
+
+		// break if we found a valid scalar (i.e. > 0 and < N == curveOrder)
+		if _, err := ecdh.P256().NewPrivateKey(privKeyBytes[:]); err == nil {
+			break
+		}
+	}
+
+	return PrivKeySecp256r1(privKeyBytes)
+}
+
+//-------------------------------------
+
+var _ crypto.PubKey = PubKeySecp256r1{}
+
+// PubKeySecp256r1Size is comprised of 32 bytes for one field element
+// (the x-coordinate), plus one byte for the parity of the y-coordinate.
+const PubKeySecp256r1Size = 33
+
+// PubKeySecp256r1 implements crypto.PubKey.
+// It is the compressed form of the pubkey, as defined in SEC 1: a 0x02 or 0x03
+// byte for the parity of the y-coordinate, followed with the x-coordinate.
+type PubKeySecp256r1 [PubKeySecp256r1Size]byte
+
+// PubKeyFromBytes parses a public key in its compressed or uncompressed SEC 1
+// form, as exported by passkeys in the COSE key of their credential.
+func PubKeyFromBytes(bz []byte) (PubKeySecp256r1, error) {
+	var pubKey PubKeySecp256r1
+	switch {
+	case len(bz) == PubKeySecp256r1Size:
+		copy(pubKey[:], bz)
+	case len(bz) == 65 && bz[0] == 0x04:
+		x, y := bz[1:33], bz[33:]
+		pubKey[0] = 0x02 | y[31]&1
+		copy(pubKey[1:], x)
+	default:
+		return pubKey, fmt.Errorf("invalid secp256r1 public key length: %d", len(bz))
+	}
+	if _, err := pubKey.ecdsa(); err != nil {
+		return PubKeySecp256r1{}, err
+	}
+	return pubKey, nil
+}
+
+// Address is the SHA256-20 of the raw pubkey bytes.
+func (pubKey PubKeySecp256r1) Address() crypto.Address {
+	return crypto.AddressFromBytes(tmhash.SumTruncated(pubKey[:]))
+}
+
+// Bytes returns the pubkey marshalled with amino encoding.
+func (pubKey PubKeySecp256r1) Bytes() []byte {
+	return amino.MustMarshalAny(pubKey)
+}
+
+// VerifyBytes verifies a signature of msg, either a plain signature of the
+// form R || S, which is rejected if not in lower-S form, or an amino encoded
+// WebAuthnSignature.
+func (pubKey PubKeySecp256r1) VerifyBytes(msg []byte, sig []byte) bool {
+	pub, err := pubKey.ecdsa()
+	if err != nil {
+		return false
+	}
+
+	if len(sig) == SignatureSize {
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		// Reject malleable signatures.
+		if s.Cmp(halfCurveOrder) > 0 {
+			return false
+		}
+		return ecdsa.Verify(pub, crypto.Sha256(msg), r, s)
+	}
+
+	var wsig WebAuthnSignature
+	if err := amino.Unmarshal(sig, &wsig); err != nil {
+		return false
+	}
+	return wsig.verify(pub, msg)
+}
+
+func (pubKey PubKeySecp256r1) String() string {
+	return crypto.PubKeyToBech32(pubKey)
+}
+
+func (pubKey PubKeySecp256r1) Equals(other crypto.PubKey) bool {
+	if otherSecp, ok := other.(PubKeySecp256r1); ok {
+		return bytes.Equal(pubKey[:], otherSecp[:])
+	}
+	return false
+}
+
+func (pubKey PubKeySecp256r1) ecdsa() (*ecdsa.PublicKey, error) {
+	x, y := elliptic.UnmarshalCompressed(elliptic.P256(), pubKey[:])
+	if x == nil {
+		return nil, errors.New("invalid secp256r1 public key")
+	}
+	return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+}
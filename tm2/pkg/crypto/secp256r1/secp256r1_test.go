@@ -0,0 +1,185 @@
+package secp256r1_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/secp256r1"
+)
+
+// key of RFC 6979, A.2.5.
+const (
+	testPriv            = "c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721"
+	testPub             = "0360fed4ba255a9d31c961eb74c6356d68c049b8923b61fa6ce669622e60f29fb6"
+	testPubUncompressed = "0460fed4ba255a9d31c961eb74c6356d68c049b8923b61fa6ce669622e60f29fb67903fe1008b8bc99a41ae9e95628bc64f2f1b20c2d7e9f5177a3c294d4462299"
+)
+
+func testPrivKey(t *testing.T) secp256r1.PrivKeySecp256r1 {
+	t.Helper()
+
+	bz, err := hex.DecodeString(testPriv)
+	require.NoError(t, err)
+	var priv secp256r1.PrivKeySecp256r1
+	copy(priv[:], bz)
+	return priv
+}
+
+func TestPubKeySecp256r1(t *testing.T) {
+	t.Parallel()
+
+	pubKey := testPrivKey(t).PubKey()
+	pub, ok := pubKey.(secp256r1.PubKeySecp256r1)
+	require.True(t, ok)
+	assert.Equal(t, testPub, hex.EncodeToString(pub[:]))
+
+	bz, err := hex.DecodeString(testPubUncompressed)
+	require.NoError(t, err)
+	pub2, err := secp256r1.PubKeyFromBytes(bz)
+	require.NoError(t, err)
+	assert.Equal(t, pub, pub2)
+
+	_, err = secp256r1.PubKeyFromBytes(bz[:40])
+	assert.Error(t, err)
+	bz[1] ^= 0xff // not on the curve
+	_, err = secp256r1.PubKeyFromBytes(bz)
+	assert.Error(t, err)
+
+	// amino roundtrip, as in accounts.
+	var pubKey2 crypto.PubKey
+	require.NoError(t, amino.UnmarshalAny(pubKey.Bytes(), &pubKey2))
+	assert.True(t, pubKey.Equals(pubKey2))
+	assert.Equal(t, "/tm.PubKeySecp256r1", amino.GetTypeURL(pubKey))
+}
+
+func TestSignAndValidateSecp256r1(t *testing.T) {
+	t.Parallel()
+
+	privKey := secp256r1.GenPrivKey()
+	pubKey := privKey.PubKey()
+
+	msg := crypto.CRandBytes(128)
+	sig, err := privKey.Sign(msg)
+	require.NoError(t, err)
+	require.Len(t, sig, secp256r1.SignatureSize)
+	assert.True(t, pubKey.VerifyBytes(msg, sig))
+
+	// the high-S form of the signature is rejected.
+	n := elliptic.P256().Params().N
+	s := new(big.Int).SetBytes(sig[32:])
+	highSig := append([]byte{}, sig[:32]...)
+	highSig = append(highSig, new(big.Int).Sub(n, s).FillBytes(make([]byte, 32))...)
+	assert.False(t, pubKey.VerifyBytes(msg, highSig))
+
+	// mutate the signature.
+	sig[3] ^= byte(0x01)
+	assert.False(t, pubKey.VerifyBytes(msg, sig))
+	assert.False(t, secp256r1.GenPrivKey().PubKey().VerifyBytes(msg, sig))
+}
+
+// signAssertion signs msg as a passkey would.
+func signAssertion(t *testing.T, priv secp256r1.PrivKeySecp256r1, authData []byte, cdType string, msg []byte) secp256r1.WebAuthnSignature {
+	t.Helper()
+
+	clientDataJSON, err := json.Marshal(map[string]string{
+		"type":      cdType,
+		"challenge": base64.RawURLEncoding.EncodeToString(secp256r1.Challenge(msg)),
+		"origin":    "https://gno.land",
+	})
+	require.NoError(t, err)
+
+	pub := priv.PubKey().(secp256r1.PubKeySecp256r1)
+	x, y := elliptic.UnmarshalCompressed(elliptic.P256(), pub[:])
+	key := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y},
+		D:         new(big.Int).SetBytes(priv[:]),
+	}
+	signed := append(append([]byte{}, authData...), crypto.Sha256(clientDataJSON)...)
+	r, s, err := ecdsa.Sign(crypto.CReader(), key, crypto.Sha256(signed))
+	require.NoError(t, err)
+
+	// normalize the signature to the lower-S form, as clients must.
+	n := elliptic.P256().Params().N
+	if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		s.Sub(n, s)
+	}
+
+	return secp256r1.WebAuthnSignature{
+		AuthenticatorData: authData,
+		ClientDataJSON:    clientDataJSON,
+		Signature:         marshalSignature(t, r, s),
+	}
+}
+
+func marshalSignature(t *testing.T, r, s *big.Int) []byte {
+	t.Helper()
+
+	sig, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	require.NoError(t, err)
+	return sig
+}
+
+func TestVerifyWebAuthnSignature(t *testing.T) {
+	t.Parallel()
+
+	priv := testPrivKey(t)
+	pubKey := priv.PubKey()
+	msg := []byte(`{"chain_id":"dev","msgs":[]}`)
+
+	authData := make([]byte, 37)
+	authData[32] = 0x05 // user present and verified
+
+	valid := signAssertion(t, priv, authData, "webauthn.get", msg)
+	assert.True(t, pubKey.VerifyBytes(msg, amino.MustMarshal(valid)))
+
+	// another message
+	assert.False(t, pubKey.VerifyBytes([]byte("other"), amino.MustMarshal(valid)))
+
+	// another key
+	assert.False(t, secp256r1.GenPrivKey().PubKey().VerifyBytes(msg, amino.MustMarshal(valid)))
+
+	// tampered client data
+	tampered := valid
+	tampered.ClientDataJSON = append([]byte{}, valid.ClientDataJSON...)
+	tampered.ClientDataJSON[len(tampered.ClientDataJSON)-2] ^= 0x01
+	assert.False(t, pubKey.VerifyBytes(msg, amino.MustMarshal(tampered)))
+
+	// registration instead of assertion
+	create := signAssertion(t, priv, authData, "webauthn.create", msg)
+	assert.False(t, pubKey.VerifyBytes(msg, amino.MustMarshal(create)))
+
+	// user not present
+	absent := make([]byte, 37)
+	notPresent := signAssertion(t, priv, absent, "webauthn.get", msg)
+	assert.False(t, pubKey.VerifyBytes(msg, amino.MustMarshal(notPresent)))
+
+	// authenticator data too short
+	short := signAssertion(t, priv, authData[:33], "webauthn.get", msg)
+	assert.False(t, pubKey.VerifyBytes(msg, amino.MustMarshal(short)))
+
+	// the high-S form of the signature is rejected.
+	var esig struct{ R, S *big.Int }
+	_, err := asn1.Unmarshal(valid.Signature, &esig)
+	require.NoError(t, err)
+	highS := valid
+	highS.Signature = marshalSignature(t, esig.R, new(big.Int).Sub(elliptic.P256().Params().N, esig.S))
+	assert.False(t, pubKey.VerifyBytes(msg, amino.MustMarshal(highS)))
+
+	// trailing data after the signature
+	trailing := valid
+	trailing.Signature = append(append([]byte{}, valid.Signature...), 0x00)
+	assert.False(t, pubKey.VerifyBytes(msg, amino.MustMarshal(trailing)))
+
+	// garbage
+	assert.False(t, pubKey.VerifyBytes(msg, []byte("not a signature")))
+}
@@ -0,0 +1,95 @@
+package secp256r1
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+)
+
+const (
+	// webAuthnTypeGet is the type of the client data of WebAuthn assertions.
+	webAuthnTypeGet = "webauthn.get"
+
+	// minAuthenticatorDataSize is the size of the authenticator data without
+	// extensions: the RP ID hash, the flags and the signature counter.
+	minAuthenticatorDataSize = 37
+
+	// flagUserPresent is the flag set in the authenticator data when the
+	// user was present.
+	flagUserPresent = 0x01
+)
+
+// WebAuthnSignature is a WebAuthn assertion of a passkey, whose challenge is
+// the signed message. See [Challenge].
+//
+// The origin and RP ID of the assertion are not checked, as the chain has no
+// relying party: the passkey only authorizes the signed message.
+type WebAuthnSignature struct {
+	// AuthenticatorData is the authenticatorData of the assertion response.
+	AuthenticatorData []byte `json:"authenticator_data"`
+	// ClientDataJSON is the clientDataJSON of the assertion response.
+	ClientDataJSON []byte `json:"client_data_json"`
+	// Signature is the ASN.1 DER signature of the assertion response, in
+	// lower-S form: as authenticators don't normalize their signatures,
+	// clients must replace a high S with N - S before submitting it.
+	Signature []byte `json:"signature"`
+}
+
+// Challenge returns the challenge to request a WebAuthn assertion of msg
+// with, the SHA256 of msg.
+func Challenge(msg []byte) []byte {
+	return crypto.Sha256(msg)
+}
+
+// clientData is the part of the client data of an assertion which is checked.
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+}
+
+// verify returns true if the assertion is signed by pub, with msg as its
+// challenge.
+func (sig WebAuthnSignature) verify(pub *ecdsa.PublicKey, msg []byte) bool {
+	if len(sig.AuthenticatorData) < minAuthenticatorDataSize {
+		return false
+	}
+	if sig.AuthenticatorData[32]&flagUserPresent == 0 {
+		return false
+	}
+
+	var cd clientData
+	if err := json.Unmarshal(sig.ClientDataJSON, &cd); err != nil {
+		return false
+	}
+	if cd.Type != webAuthnTypeGet {
+		return false
+	}
+	challenge, err := base64.RawURLEncoding.DecodeString(cd.Challenge)
+	if err != nil || !bytes.Equal(challenge, Challenge(msg)) {
+		return false
+	}
+
+	var esig ecdsaSignature
+	rest, err := asn1.Unmarshal(sig.Signature, &esig)
+	if err != nil || len(rest) != 0 || esig.R == nil || esig.S == nil {
+		return false
+	}
+	// Reject malleable signatures.
+	if esig.S.Cmp(halfCurveOrder) > 0 {
+		return false
+	}
+
+	// the authenticator signs its data followed with the hash of the client data.
+	signed := append(append([]byte{}, sig.AuthenticatorData...), crypto.Sha256(sig.ClientDataJSON)...)
+	return ecdsa.Verify(pub, crypto.Sha256(signed), esig.R, esig.S)
+}
+
+// ecdsaSignature is the ASN.1 structure of an ECDSA signature.
+type ecdsaSignature struct {
+	R, S *big.Int
+}
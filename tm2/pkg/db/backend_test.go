@@ -461,6 +461,48 @@ func TestDBBatchWrite(t *testing.T) {
 	}
 }
 
+func TestDBCompact(t *testing.T) {
+	t.Parallel()
+
+	for _, backend := range db.BackendList() {
+		t.Run(fmt.Sprintf("Backend %s", backend), func(t *testing.T) {
+			t.Parallel()
+
+			tmpdb := newTempDB(t, backend)
+			defer tmpdb.Close()
+
+			compacter, ok := tmpdb.(db.Compacter)
+			if !ok {
+				t.Skipf("backend %s can't compact", backend)
+			}
+
+			// Compacting an empty DB is a no-op
+			require.NoError(t, compacter.Compact(nil, nil))
+
+			for i := range 100 {
+				require.NoError(t, tmpdb.Set(int642Bytes(int64(i)), []byte(fmt.Sprintf("value %d", i))))
+			}
+			for i := range 50 {
+				require.NoError(t, tmpdb.Delete(int642Bytes(int64(i))))
+			}
+
+			require.NoError(t, compacter.Compact(int642Bytes(10), int642Bytes(60)))
+			require.NoError(t, compacter.Compact(nil, nil))
+
+			itr, err := tmpdb.Iterator(nil, nil)
+			require.NoError(t, err)
+			verifyIterator(t, itr, []int64{
+				50, 51, 52, 53, 54, 55, 56, 57, 58, 59,
+				60, 61, 62, 63, 64, 65, 66, 67, 68, 69,
+				70, 71, 72, 73, 74, 75, 76, 77, 78, 79,
+				80, 81, 82, 83, 84, 85, 86, 87, 88, 89,
+				90, 91, 92, 93, 94, 95, 96, 97, 98, 99,
+			}, "compacted DB")
+			require.NoError(t, itr.Close())
+		})
+	}
+}
+
 func newTempDB(t *testing.T, backend db.BackendType) db.DB {
 	t.Helper()
 
@@ -11,6 +11,7 @@ import (
 	"github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
 
 	"github.com/gnolang/gno/tm2/pkg/colors"
 	"github.com/gnolang/gno/tm2/pkg/db"
@@ -24,7 +25,10 @@ func init() {
 	db.InternalRegisterDBCreator(db.GoLevelDBBackend, dbCreator, false)
 }
 
-var _ db.DB = (*GoLevelDB)(nil)
+var (
+	_ db.DB        = (*GoLevelDB)(nil)
+	_ db.Compacter = (*GoLevelDB)(nil)
+)
 
 type GoLevelDB struct {
 	db *leveldb.DB
@@ -137,6 +141,11 @@ func (db *GoLevelDB) Stats() map[string]string {
 	return stats
 }
 
+// Implements Compacter.
+func (db *GoLevelDB) Compact(start, end []byte) error {
+	return db.db.CompactRange(util.Range{Start: start, Limit: end})
+}
+
 // ----------------------------------------
 // Batch
 
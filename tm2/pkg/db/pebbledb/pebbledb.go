@@ -5,6 +5,7 @@ import (
 	goerrors "errors"
 	"path/filepath"
 	"slices"
+	"strconv"
 
 	"github.com/cockroachdb/pebble"
 
@@ -19,7 +20,10 @@ func init() {
 	db.InternalRegisterDBCreator(db.PebbleDBBackend, dbCreator, false)
 }
 
-var _ db.DB = (*PebbleDB)(nil)
+var (
+	_ db.DB        = (*PebbleDB)(nil)
+	_ db.Compacter = (*PebbleDB)(nil)
+)
 
 type PebbleDB struct {
 	db *pebble.DB
@@ -109,7 +113,40 @@ func (pdb *PebbleDB) Print() error {
 
 // Implements DB.
 func (pdb *PebbleDB) Stats() map[string]string {
-	return nil
+	m := pdb.db.Metrics()
+	total := m.Total()
+	return map[string]string{
+		"pebble.metrics":          m.String(),
+		"pebble.disk-space-usage": strconv.FormatUint(m.DiskSpaceUsage(), 10),
+		"pebble.num-files":        strconv.FormatInt(total.NumFiles, 10),
+		"pebble.compactions":      strconv.FormatInt(m.Compact.Count, 10),
+	}
+}
+
+// Implements Compacter.
+func (pdb *PebbleDB) Compact(start, end []byte) error {
+	start = internal.NonNilBytes(start)
+	if end == nil {
+		// Pebble needs an end bound: compact up to the last key, inclusive.
+		it, err := pdb.db.NewIter(&pebble.IterOptions{LowerBound: start})
+		if err != nil {
+			return err
+		}
+		if it.Last() {
+			end = append(slices.Clone(it.Key()), 0)
+		}
+		if err := it.Close(); err != nil {
+			return err
+		}
+		if end == nil {
+			// No keys to compact
+			return nil
+		}
+	}
+	if bytes.Compare(start, end) >= 0 {
+		return nil
+	}
+	return pdb.db.Compact(start, end, true)
 }
 
 // ----------------------------------------
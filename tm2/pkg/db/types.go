@@ -57,6 +57,15 @@ type DB interface {
 	Stats() map[string]string
 }
 
+// Compacter is implemented by the DBs which can compact their storage on
+// demand, to reclaim the space of the deleted and overwritten keys.
+type Compacter interface {
+	// Compact compacts the domain of keys [start, end). A nil start or end
+	// is unbounded, so Compact(nil, nil) compacts the whole DB.
+	// It can be called while the DB is in use.
+	Compact(start, end []byte) error
+}
+
 // ----------------------------------------
 // Batch
 
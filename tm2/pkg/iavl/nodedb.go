@@ -68,6 +68,21 @@ var (
 	legacyRootKeyFormat = keyformat.NewKeyFormat('r', int64Size) // r<version>
 )
 
+// IsNodeDBKey reports whether key is a key of the node database, in the
+// current format: a node, fast node or metadata key. It tells the keys of a
+// tree apart from the other keys of a database shared with other stores; the
+// keys of the legacy format aren't recognized.
+func IsNodeDBKey(key []byte) bool {
+	switch {
+	case bytes.HasPrefix(key, nodeKeyFormat.Prefix()):
+		return len(key) == nodeKeyFormat.Length()
+	case bytes.HasPrefix(key, []byte(fastKeyFormat.Prefix())),
+		bytes.HasPrefix(key, []byte(metadataKeyFormat.Prefix())):
+		return true
+	}
+	return false
+}
+
 var errInvalidFastStorageVersion = fmt.Errorf("fast storage version must be in the format <storage version>%s<latest fast cache version>", fastStorageVersionDelimiter)
 
 type nodeDB struct {
@@ -1,6 +1,9 @@
 package sdk
 
-import abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+import (
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/store"
+)
 
 // InitChainer initializes application state at genesis
 type InitChainer func(ctx Context, req abci.RequestInitChain) abci.ResponseInitChain
@@ -25,3 +28,15 @@ type BeginTxHook func(ctx Context) Context
 // EndTxHook is a BaseApp-specific hook, called after all the messages in a
 // transaction have terminated.
 type EndTxHook func(ctx Context, result Result)
+
+// RestoreHook is a BaseApp-specific hook, called after the state of the
+// application was restored from a state sync snapshot, to reload any
+// application state kept outside of the multistore. Its writes to ms are
+// written to the multistore, but not committed.
+type RestoreHook func(ms store.MultiStore)
+
+// SimulateProfiler is a BaseApp-specific hook, called for the simulations
+// requested with the profile flag (".app/simulate?profile=1"). It returns a
+// ContextFn setting up the profiling of the simulated transaction, and a
+// function returning the profile once the transaction has run.
+type SimulateProfiler func() (ContextFn, func() ([]byte, error))
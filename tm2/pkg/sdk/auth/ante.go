@@ -3,6 +3,7 @@ package auth
 import (
 	"encoding/hex"
 	"fmt"
+	"math"
 	"math/big"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
@@ -10,6 +11,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
 	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
 	"github.com/gnolang/gno/tm2/pkg/crypto/secp256k1"
+	"github.com/gnolang/gno/tm2/pkg/crypto/secp256r1"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/gnolang/gno/tm2/pkg/store"
@@ -33,6 +35,11 @@ type AnteOptions struct {
 	// This is useful for development, and maybe production chains.
 	// Always check your settings and inspect genesis transactions.
 	VerifyGenesisSignatures bool
+
+	// FeeGrantKeeper lets the fee granter of a tx pay its fee, out of the fee
+	// allowance it granted to the first signer. If nil, txs with a fee granter
+	// are rejected.
+	FeeGrantKeeper FeeGrantKeeperI
 }
 
 // NewAnteHandler returns an AnteHandler that checks and increments sequence
@@ -120,20 +127,54 @@ func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer Signature
 			return newCtx, res, true
 		}
 
+		// stdSigs contains the sequence number, account number, and signatures.
+		// When simulating, this would just be a 0-length slice.
+		stdSigs := tx.GetSignatures()
+
+		// A new tx of the fee payer may replace one of its pending txs in the
+		// mempool, signed with an earlier sequence. The fee of the replaced tx
+		// is given back in the check state, where it was deducted.
+		var (
+			replaced  uint64
+			replacing bool
+		)
+		if ctx.IsCheckTx() && !simulate && len(stdSigs) > 0 {
+			replaced, replacing = pendingSequence(newCtx, ak, tx, signerAccs[0], stdSigs[0], params, sigGasConsumer)
+			if replacing {
+				if res = refundPendingFee(bank, newCtx, ak, signerAddrs[0], replaced); !res.IsOK() {
+					return newCtx, res, true
+				}
+				signerAccs[0] = ak.GetAccount(newCtx, signerAddrs[0])
+			}
+		}
+
+		// the fee granter, if any, pays the fees instead of the first signer
+		payer := signerAccs[0]
+		if tx.Fee.Granter != "" {
+			payer, res = getFeeGranterAcc(newCtx, ak, opts.FeeGrantKeeper, tx, signerAddrs[0])
+			if !res.IsOK() {
+				return newCtx, res, true
+			}
+		}
+
 		// deduct the fees
 		if !tx.Fee.GasFee.IsZero() {
-			res = DeductFees(bank, newCtx, signerAccs[0], ak.FeeCollectorAddress(ctx), std.Coins{tx.Fee.GasFee})
+			res = DeductFees(bank, newCtx, payer, ak.FeeCollectorAddress(ctx), std.Coins{tx.Fee.GasFee})
 			if !res.IsOK() {
 				return newCtx, res, true
 			}
 
 			// reload the account as fees have been deducted
-			signerAccs[0] = ak.GetAccount(newCtx, signerAccs[0].GetAddress())
+			if payer.GetAddress() == signerAddrs[0] {
+				signerAccs[0] = ak.GetAccount(newCtx, signerAccs[0].GetAddress())
+			}
 		}
 
-		// stdSigs contains the sequence number, account number, and signatures.
-		// When simulating, this would just be a 0-length slice.
-		stdSigs := tx.GetSignatures()
+		// the sequence of the fee payer identifies the tx in the mempool
+		sequence := signerAccs[0].GetSequence()
+		if replacing {
+			sequence = replaced
+		}
 
 		for i := range stdSigs {
 			// skip the fee payer, account is cached and fees were deducted already
@@ -151,19 +192,44 @@ func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer Signature
 			} else {
 				// Check signature
 				signBytes, err := GetSignBytes(newCtx.ChainID(), tx, sacc, isGenesis)
+				if i == 0 && replacing {
+					signBytes, err = getSignBytes(newCtx.ChainID(), tx, sacc.GetAccountNumber(), replaced)
+				}
 				if err != nil {
 					return newCtx, res, true
 				}
+
+				next := sacc.GetSequence()
 				signerAccs[i], res = processSig(newCtx, sacc, stdSigs[i], signBytes, simulate, params, sigGasConsumer)
 				if !res.IsOK() {
 					return newCtx, res, true
 				}
+				if i == 0 && replacing {
+					// the sequence was already incremented by the
+					// replaced tx.
+					if err := signerAccs[i].SetSequence(next); err != nil {
+						panic(err)
+					}
+				}
 			}
 			ak.SetAccount(newCtx, signerAccs[i])
 		}
 
+		// record the fee of the tx, in case it gets replaced.
+		if ctx.IsCheckTx() && !simulate {
+			ak.setPendingFee(newCtx, signerAddrs[0], sequence, pendingFee{
+				Payer: payer.GetAddress(),
+				Fee:   tx.Fee.GasFee,
+			})
+		}
+
 		// TODO: tx tags (?)
-		return newCtx, sdk.Result{GasWanted: tx.Fee.GasWanted}, false // continue...
+		return newCtx, sdk.Result{
+			GasWanted: tx.Fee.GasWanted,
+			Priority:  TxPriority(tx.Fee),
+			Sender:    signerAddrs[0].String(),
+			Sequence:  sequence,
+		}, false // continue...
 	}
 }
 
@@ -240,6 +306,64 @@ func processSig(
 	return acc, res
 }
 
+// pendingSequence returns the sequence of a pending tx of acc in the mempool
+// that sig is valid for, unless it is valid for the next sequence of acc. On
+// CheckTx, acc has the sequence following its pending txs, and the committed
+// account the sequence of the first of them. Each pending sequence checked
+// consumes the gas of a signature verification.
+func pendingSequence(
+	ctx sdk.Context, ak AccountKeeper, tx std.Tx, acc std.Account, sig std.Signature, params Params,
+	sigGasConsumer SignatureVerificationGasConsumer,
+) (uint64, bool) {
+	committedCtx, ok := sdk.CommittedContext(ctx)
+	if !ok {
+		return 0, false
+	}
+	committed := ak.GetAccount(committedCtx, acc.GetAddress())
+	if committed == nil || committed.GetSequence() >= acc.GetSequence() {
+		// no pending txs
+		return 0, false
+	}
+
+	pubKey, res := ProcessPubKey(acc, sig)
+	if !res.IsOK() {
+		return 0, false
+	}
+	signBytes, err := getSignBytes(ctx.ChainID(), tx, acc.GetAccountNumber(), acc.GetSequence())
+	if err != nil || pubKey.VerifyBytes(signBytes, sig.Signature) {
+		// not a replacement
+		return 0, false
+	}
+
+	for seq := committed.GetSequence(); seq < acc.GetSequence(); seq++ {
+		if res := sigGasConsumer(ctx.GasMeter(), sig.Signature, pubKey, params); !res.IsOK() {
+			return 0, false
+		}
+		signBytes, err := getSignBytes(ctx.ChainID(), tx, acc.GetAccountNumber(), seq)
+		if err != nil {
+			return 0, false
+		}
+		if pubKey.VerifyBytes(signBytes, sig.Signature) {
+			return seq, true
+		}
+	}
+	return 0, false
+}
+
+// refundPendingFee gives back the fee paid for the pending tx of addr with
+// sequence, which is being replaced, to its payer.
+func refundPendingFee(bank BankKeeperI, ctx sdk.Context, ak AccountKeeper, addr crypto.Address, sequence uint64) sdk.Result {
+	pf, ok := ak.getPendingFee(ctx, addr, sequence)
+	if !ok || pf.Fee.IsZero() {
+		return sdk.Result{}
+	}
+	err := bank.SendCoinsUnrestricted(ctx, ak.FeeCollectorAddress(ctx), pf.Payer, std.Coins{pf.Fee})
+	if err != nil {
+		return abciResult(err)
+	}
+	return sdk.Result{}
+}
+
 // ProcessPubKey verifies that the given account address matches that of the
 // std.Signature. In addition, it will set the public key of the account if it
 // has not been set.
@@ -277,6 +401,10 @@ func DefaultSigVerificationGasConsumer(
 		meter.ConsumeGas(params.SigVerifyCostSecp256k1, "ante verify: secp256k1")
 		return sdk.Result{}
 
+	case secp256r1.PubKeySecp256r1:
+		meter.ConsumeGas(params.sigVerifyCostSecp256r1(), "ante verify: secp256r1")
+		return sdk.Result{}
+
 	case multisig.PubKeyMultisigThreshold:
 		var multisignature multisig.Multisignature
 		amino.MustUnmarshal(sig, &multisignature)
@@ -303,6 +431,26 @@ func consumeMultisignatureVerificationGas(meter store.GasMeter,
 	}
 }
 
+// getFeeGranterAcc uses the fee allowance of the fee granter of tx for
+// grantee, and returns the account of the fee granter.
+func getFeeGranterAcc(ctx sdk.Context, ak AccountKeeper, fgk FeeGrantKeeperI, tx std.Tx, grantee crypto.Address) (std.Account, sdk.Result) {
+	if fgk == nil {
+		return nil, abciResult(std.ErrUnauthorized("fee grants are not enabled"))
+	}
+	granter, err := crypto.AddressFromBech32(tx.Fee.Granter)
+	if err != nil {
+		return nil, abciResult(std.ErrInvalidAddress(fmt.Sprintf("invalid fee granter %q", tx.Fee.Granter)))
+	}
+	if err := fgk.UseFeeGrant(ctx, granter, grantee, std.Coins{tx.Fee.GasFee}, tx.GetMsgs()); err != nil {
+		return nil, abciResult(err)
+	}
+	acc := ak.GetAccount(ctx, granter)
+	if acc == nil {
+		return nil, abciResult(std.ErrUnknownAddress(fmt.Sprintf("fee granter %s does not exist", granter)))
+	}
+	return acc, sdk.Result{}
+}
+
 // DeductFees deducts fees from the given account.
 //
 // NOTE: We could use the CoinKeeper (in addition to the AccountKeeper, because
@@ -406,6 +554,27 @@ func EnsureSufficientMempoolFees(ctx sdk.Context, fee std.Fee) sdk.Result {
 	))
 }
 
+// PriorityScale is the number of gas units the gas price of a tx is computed
+// over for its mempool priority, see [TxPriority].
+const PriorityScale = 1_000_000
+
+// TxPriority returns the mempool priority of a tx paying fee: its gas fee per
+// PriorityScale units of gas wanted, capped to math.MaxInt64. The denom of the
+// gas fee is not taken into account.
+func TxPriority(fee std.Fee) int64 {
+	if fee.GasWanted <= 0 || fee.GasFee.Amount <= 0 {
+		return 0
+	}
+
+	priority := big.NewInt(fee.GasFee.Amount)
+	priority.Mul(priority, big.NewInt(PriorityScale))
+	priority.Quo(priority, big.NewInt(fee.GasWanted))
+	if !priority.IsInt64() {
+		return math.MaxInt64
+	}
+	return priority.Int64()
+}
+
 // SetGasMeter returns a new context with a gas meter set from a given context.
 func SetGasMeter(ctx sdk.Context, gasLimit int64) sdk.Context {
 	// In various cases such as simulation and during the genesis block, we do not
@@ -429,6 +598,10 @@ func GetSignBytes(chainID string, tx std.Tx, acc std.Account, genesis bool) ([]b
 		accSequence = acc.GetSequence()
 	}
 
+	return getSignBytes(chainID, tx, accNum, accSequence)
+}
+
+func getSignBytes(chainID string, tx std.Tx, accNum, accSequence uint64) ([]byte, error) {
 	return std.GetSignaturePayload(
 		std.SignDoc{
 			ChainID:       chainID,
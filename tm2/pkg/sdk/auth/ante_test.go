@@ -2,6 +2,7 @@ package auth
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"reflect"
 	"strings"
@@ -17,6 +18,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
 	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
 	"github.com/gnolang/gno/tm2/pkg/crypto/secp256k1"
+	"github.com/gnolang/gno/tm2/pkg/crypto/secp256r1"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
 	tu "github.com/gnolang/gno/tm2/pkg/sdk/testutils"
 	"github.com/gnolang/gno/tm2/pkg/std"
@@ -352,6 +354,144 @@ func TestAnteHandlerFees(t *testing.T) {
 	require.Equal(t, env.acck.GetAccount(ctx, addr1).GetCoins().AmountOf("atom"), int64(0))
 }
 
+// Test the replacement of a pending tx in the check state, where the fee of
+// the replaced tx is given back, when the balance covers exactly one fee.
+func TestAnteHandlerReplacement(t *testing.T) {
+	t.Parallel()
+
+	// setup
+	env := setupTestEnv()
+	anteHandler := NewAnteHandler(env.acck, env.bankk, DefaultSigVerificationGasConsumer, defaultAnteOptions())
+
+	// keys and addresses
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+
+	// set the accounts, in the committed state
+	acc1 := env.acck.NewAccountWithAddress(env.ctx, addr1)
+	acc1.SetCoins(std.NewCoins(std.NewCoin("atom", 150)))
+	env.acck.SetAccount(env.ctx, acc1)
+
+	// the check state, where the ante handler of each tx runs on a cache
+	// written on success, like in CheckTx.
+	checkMS := env.ctx.MultiStore().MultiCacheWrap()
+	ctx := env.ctx.WithMode(sdk.RunTxModeCheck).WithMultiStore(checkMS).
+		WithValue(GasPriceContextKey{}, std.GasPrice{})
+	ctx = sdk.WithCommittedMultiStore(ctx, env.ctx.MultiStore())
+	checkTx := func(tx std.Tx) sdk.Result {
+		t.Helper()
+
+		msCache := checkMS.MultiCacheWrap()
+		_, res, abort := anteHandler(ctx.WithMultiStore(msCache), tx, false)
+		if !abort {
+			msCache.MultiWrite()
+		}
+		return res
+	}
+	feeCollector := env.acck.FeeCollectorAddress(ctx)
+	balances := func() (int64, int64) {
+		t.Helper()
+
+		collected := int64(0)
+		if collector := env.acck.GetAccount(ctx, feeCollector); collector != nil {
+			collected = collector.GetCoins().AmountOf("atom")
+		}
+		return env.acck.GetAccount(ctx, addr1).GetCoins().AmountOf("atom"), collected
+	}
+
+	privs, accnums := []crypto.PrivKey{priv1}, []uint64{0}
+	fee := tu.NewTestFee()
+
+	// the pending tx
+	tx := tu.NewTestTx(t, ctx.ChainID(), []std.Msg{tu.NewTestMsg(addr1)}, privs, accnums, []uint64{0}, fee)
+	res := checkTx(tx)
+	require.True(t, res.IsOK(), "result: %v", res)
+	assert.Equal(t, uint64(0), res.Sequence)
+	balance, collected := balances()
+	assert.Equal(t, int64(0), balance)
+	assert.Equal(t, int64(150), collected)
+
+	// its replacement pays the same fee, instead of the replaced tx
+	tx = tu.NewTestTx(t, ctx.ChainID(), []std.Msg{tu.NewTestMsg(addr1), tu.NewTestMsg(addr1)}, privs, accnums, []uint64{0}, fee)
+	msgs := tx.Msgs
+	res = checkTx(tx)
+	require.True(t, res.IsOK(), "result: %v", res)
+	assert.Equal(t, uint64(0), res.Sequence)
+	balance, collected = balances()
+	assert.Equal(t, int64(0), balance)
+	assert.Equal(t, int64(150), collected)
+	assert.Equal(t, uint64(1), env.acck.GetAccount(ctx, addr1).GetSequence())
+
+	// a replacement with a higher fee is not covered by the balance, and
+	// leaves the check state unchanged
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{0}, std.NewFee(50000, std.NewCoin("atom", 151)))
+	res = checkTx(tx)
+	assert.Equal(t, reflect.TypeOf(std.InsufficientFundsError{}), reflect.TypeOf(sdk.ABCIError(res.Error)), "result: %v", res)
+	balance, collected = balances()
+	assert.Equal(t, int64(0), balance)
+	assert.Equal(t, int64(150), collected)
+
+	// the next tx is not covered by the balance
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{1}, fee)
+	res = checkTx(tx)
+	assert.Equal(t, reflect.TypeOf(std.InsufficientFundsError{}), reflect.TypeOf(sdk.ABCIError(res.Error)), "result: %v", res)
+}
+
+// Test logic around fees paid by a fee granter.
+func TestAnteHandlerFeeGranter(t *testing.T) {
+	t.Parallel()
+
+	// setup
+	env := setupTestEnv()
+	ctx := env.ctx
+	opts := defaultAnteOptions()
+	opts.FeeGrantKeeper = env.fgk
+	anteHandler := NewAnteHandler(env.acck, env.bankk, DefaultSigVerificationGasConsumer, opts)
+
+	// keys and addresses
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+	_, _, granter := tu.KeyTestPubAddr()
+
+	// set the accounts, the grantee has no funds
+	acc1 := env.acck.NewAccountWithAddress(ctx, addr1)
+	env.acck.SetAccount(ctx, acc1)
+	gacc := env.acck.NewAccountWithAddress(ctx, granter)
+	gacc.SetCoins(std.NewCoins(std.NewCoin("atom", 1000)))
+	env.acck.SetAccount(ctx, gacc)
+
+	// msg and signatures
+	msgs := []std.Msg{tu.NewTestMsg(addr1)}
+	privs, accnums, seqs := []crypto.PrivKey{priv1}, []uint64{0}, []uint64{0}
+	fee := tu.NewTestFee()
+	fee.Granter = granter.String()
+
+	// no fee allowance
+	tx := tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, seqs, fee)
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+
+	// fee grants are not enabled
+	noGrants := NewAnteHandler(env.acck, env.bankk, DefaultSigVerificationGasConsumer, defaultAnteOptions())
+	checkInvalidTx(t, noGrants, ctx, tx, false, std.UnauthorizedError{})
+
+	// the granter pays the fee out of the allowance
+	env.fgk.SetFeeGrant(ctx, FeeGrant{
+		Granter:   granter,
+		Grantee:   addr1,
+		Allowance: FeeAllowance{SpendLimit: std.NewCoins(std.NewCoin("atom", 200))},
+	})
+	checkValidTx(t, anteHandler, ctx, tx, false)
+	require.Equal(t, int64(850), env.acck.GetAccount(ctx, granter).GetCoins().AmountOf("atom"))
+	require.Equal(t, uint64(1), env.acck.GetAccount(ctx, addr1).GetSequence())
+	grant, ok := env.fgk.GetFeeGrant(ctx, granter, addr1)
+	require.True(t, ok)
+	require.Equal(t, std.NewCoins(std.NewCoin("atom", 50)), grant.Allowance.SpendLimit)
+
+	// the spend limit left is too low
+	seqs = []uint64{1}
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, seqs, fee)
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.InsufficientFundsError{})
+	require.Equal(t, int64(850), env.acck.GetAccount(ctx, granter).GetCoins().AmountOf("atom"))
+}
+
 // Test logic around memo gas consumption.
 func TestAnteHandlerMemoGas(t *testing.T) {
 	t.Parallel()
@@ -588,6 +728,36 @@ func TestAnteHandlerSetPubKey(t *testing.T) {
 	require.Nil(t, acc2.GetPubKey())
 }
 
+func TestAnteHandlerSecp256r1(t *testing.T) {
+	t.Parallel()
+
+	// setup
+	env := setupTestEnv()
+	anteHandler := NewAnteHandler(env.acck, env.bankk, DefaultSigVerificationGasConsumer, defaultAnteOptions())
+	ctx := env.ctx
+
+	// a passkey account
+	priv1 := secp256r1.GenPrivKey()
+	addr1 := priv1.PubKey().Address()
+	acc1 := env.acck.NewAccountWithAddress(ctx, addr1)
+	acc1.SetCoins(tu.NewTestCoins())
+	env.acck.SetAccount(ctx, acc1)
+
+	msgs := []std.Msg{tu.NewTestMsg(addr1)}
+	privs, accnums, seqs := []crypto.PrivKey{priv1}, []uint64{0}, []uint64{0}
+	fee := tu.NewTestFee()
+	tx := tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, seqs, fee)
+	checkValidTx(t, anteHandler, ctx, tx, false)
+
+	acc1 = env.acck.GetAccount(ctx, addr1)
+	require.Equal(t, priv1.PubKey(), acc1.GetPubKey())
+	require.Equal(t, uint64(1), acc1.GetSequence())
+
+	// signed by another passkey
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, []crypto.PrivKey{secp256r1.GenPrivKey()}, accnums, []uint64{1}, fee)
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+}
+
 func TestProcessPubKey(t *testing.T) {
 	t.Parallel()
 
@@ -634,6 +804,8 @@ func TestConsumeSignatureVerificationGas(t *testing.T) {
 	t.Parallel()
 
 	params := DefaultParams()
+	unsetR1Params := params
+	unsetR1Params.SigVerifyCostSecp256r1 = 0 // params saved before the cost was added
 	msg := []byte{1, 2, 3, 4}
 
 	pkSet1, sigSet1 := generatePubKeysAndSignatures(5, msg, false)
@@ -658,6 +830,8 @@ func TestConsumeSignatureVerificationGas(t *testing.T) {
 	}{
 		{"PubKeyEd25519", args{store.NewInfiniteGasMeter(), nil, ed25519.GenPrivKey().PubKey(), params}, DefaultSigVerifyCostED25519, false},
 		{"PubKeySecp256k1", args{store.NewInfiniteGasMeter(), nil, secp256k1.GenPrivKey().PubKey(), params}, DefaultSigVerifyCostSecp256k1, false},
+		{"PubKeySecp256r1", args{store.NewInfiniteGasMeter(), nil, secp256r1.GenPrivKey().PubKey(), params}, DefaultSigVerifyCostSecp256r1, false},
+		{"PubKeySecp256r1 unset cost", args{store.NewInfiniteGasMeter(), nil, secp256r1.GenPrivKey().PubKey(), unsetR1Params}, DefaultSigVerifyCostSecp256r1, false},
 		{"Multisig", args{store.NewInfiniteGasMeter(), amino.MustMarshal(multisignature1), multisigKey1, params}, expectedCost1, false},
 		{"unknown key", args{store.NewInfiniteGasMeter(), nil, nil, params}, 0, true},
 	}
@@ -948,3 +1122,26 @@ func TestInvalidUserFee(t *testing.T) {
 	require.False(t, res2.IsOK())
 	assert.Contains(t, res2.Log, "Gas price denominations should be equal;")
 }
+
+func TestTxPriority(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		fee      std.Fee
+		expected int64
+	}{
+		{"zero gas wanted", std.NewFee(0, std.NewCoin("ugnot", 10)), 0},
+		{"zero gas fee", std.NewFee(100, std.NewCoin("ugnot", 0)), 0},
+		{"gas price of 1", std.NewFee(100, std.NewCoin("ugnot", 100)), PriorityScale},
+		{"fractional gas price", std.NewFee(3, std.NewCoin("ugnot", 1)), PriorityScale / 3},
+		{"overflow", std.NewFee(1, std.NewCoin("ugnot", math.MaxInt64)), math.MaxInt64},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.expected, TxPriority(tc.fee))
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"encoding/binary"
+
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 )
 
@@ -16,6 +18,11 @@ const (
 
 	// AddressStoreKeyPrefix prefix for account-by-address store
 	AddressStoreKeyPrefix = "/a/"
+	// FeeGrantStoreKeyPrefix prefix for fee-allowance-by-grantee store
+	FeeGrantStoreKeyPrefix = "/fg/"
+	// PendingFeeStoreKeyPrefix prefix for the fees of the pending txs, only
+	// stored in the check state
+	PendingFeeStoreKeyPrefix = "/pf/"
 	// key for gas price
 	GasPriceKey = "gasPrice"
 	// param key for global account number
@@ -26,3 +33,17 @@ const (
 func AddressStoreKey(addr crypto.Address) []byte {
 	return append([]byte(AddressStoreKeyPrefix), addr.Bytes()...)
 }
+
+// PendingFeeStoreKey turns the address and the sequence of the signer of a
+// pending tx to the key used to get the fee it paid from the check state
+func PendingFeeStoreKey(addr crypto.Address, sequence uint64) []byte {
+	key := append([]byte(PendingFeeStoreKeyPrefix), addr.Bytes()...)
+	return binary.BigEndian.AppendUint64(key, sequence)
+}
+
+// FeeGrantStoreKey turns the addresses of a grantee and a granter to the key
+// used to get the fee allowance of the granter from the store
+func FeeGrantStoreKey(grantee, granter crypto.Address) []byte {
+	key := append([]byte(FeeGrantStoreKeyPrefix), grantee.Bytes()...)
+	return append(key, granter.Bytes()...)
+}
@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
+)
+
+// FeeAllowance limits the fees a granter pays for the txs of a grantee.
+type FeeAllowance struct {
+	// SpendLimit is the amount of fees left to pay, unlimited if empty.
+	SpendLimit std.Coins `json:"spend_limit,omitempty" yaml:"spend_limit,omitempty"`
+	// Expiration is the block time from which the allowance can no longer be
+	// used, never if zero.
+	Expiration time.Time `json:"expiration,omitempty" yaml:"expiration,omitempty"`
+	// AllowedMsgs are the type URLs of the msgs the allowance pays for, like
+	// "/vm.m_call", or any msg if empty.
+	AllowedMsgs []string `json:"allowed_msgs,omitempty" yaml:"allowed_msgs,omitempty"`
+	// AllowedPkgPaths are the paths of the packages the msgs must target, like
+	// the realms they call, or any if empty. See [PkgPathMsg].
+	AllowedPkgPaths []string `json:"allowed_pkg_paths,omitempty" yaml:"allowed_pkg_paths,omitempty"`
+}
+
+// ValidateBasic performs a stateless validation of the allowance.
+func (fa FeeAllowance) ValidateBasic() error {
+	if !fa.SpendLimit.IsValid() {
+		return std.ErrInvalidCoins(fa.SpendLimit.String())
+	}
+	if slices.Contains(fa.AllowedMsgs, "") {
+		return std.ErrUnknownRequest("empty allowed msg type URL")
+	}
+	if slices.Contains(fa.AllowedPkgPaths, "") {
+		return std.ErrUnknownRequest("empty allowed package path")
+	}
+	return nil
+}
+
+// IsExpired returns true if the allowance can no longer be used at blockTime.
+func (fa FeeAllowance) IsExpired(blockTime time.Time) bool {
+	return !fa.Expiration.IsZero() && !blockTime.Before(fa.Expiration)
+}
+
+// Allows returns an error if the allowance doesn't pay for msg.
+func (fa FeeAllowance) Allows(msg std.Msg) error {
+	if len(fa.AllowedMsgs) > 0 {
+		typeURL := amino.GetTypeURL(msg)
+		if !slices.Contains(fa.AllowedMsgs, typeURL) {
+			return std.ErrUnauthorized(fmt.Sprintf("fee allowance does not allow msg %s", typeURL))
+		}
+	}
+	if len(fa.AllowedPkgPaths) > 0 {
+		pmsg, ok := msg.(PkgPathMsg)
+		if !ok {
+			return std.ErrUnauthorized(fmt.Sprintf("fee allowance does not allow msg %s without package path", amino.GetTypeURL(msg)))
+		}
+		if !slices.Contains(fa.AllowedPkgPaths, pmsg.GetPkgPath()) {
+			return std.ErrUnauthorized(fmt.Sprintf("fee allowance does not allow package %s", pmsg.GetPkgPath()))
+		}
+	}
+	return nil
+}
+
+// PkgPathMsg is implemented by the msgs targeting a package, whose path fee
+// allowances can be restricted to.
+type PkgPathMsg interface {
+	std.Msg
+	GetPkgPath() string
+}
+
+// FeeGrant is a fee allowance of a granter for a grantee.
+type FeeGrant struct {
+	Granter   crypto.Address `json:"granter" yaml:"granter"`
+	Grantee   crypto.Address `json:"grantee" yaml:"grantee"`
+	Allowance FeeAllowance   `json:"allowance" yaml:"allowance"`
+}
+
+// FeeGrantKeeper stores the fee allowances, and lets the fee granter of a tx
+// pay its fee. See [std.Fee].
+type FeeGrantKeeper struct {
+	key store.StoreKey
+}
+
+// NewFeeGrantKeeper returns a new FeeGrantKeeper using the store with key.
+func NewFeeGrantKeeper(key store.StoreKey) FeeGrantKeeper {
+	return FeeGrantKeeper{
+		key: key,
+	}
+}
+
+// GetFeeGrant returns the fee allowance of granter for grantee, if any.
+func (fk FeeGrantKeeper) GetFeeGrant(ctx sdk.Context, granter, grantee crypto.Address) (FeeGrant, bool) {
+	stor := ctx.Store(fk.key)
+	bz := stor.Get(FeeGrantStoreKey(grantee, granter))
+	if bz == nil {
+		return FeeGrant{}, false
+	}
+
+	var grant FeeGrant
+	amino.MustUnmarshal(bz, &grant)
+	return grant, true
+}
+
+// GetFeeGrants returns the fee allowances granted to grantee.
+func (fk FeeGrantKeeper) GetFeeGrants(ctx sdk.Context, grantee crypto.Address) []FeeGrant {
+	stor := ctx.Store(fk.key)
+	prefix := append([]byte(FeeGrantStoreKeyPrefix), grantee.Bytes()...)
+	iter := store.PrefixIterator(stor, prefix)
+	defer iter.Close()
+
+	var grants []FeeGrant
+	for ; iter.Valid(); iter.Next() {
+		var grant FeeGrant
+		amino.MustUnmarshal(iter.Value(), &grant)
+		grants = append(grants, grant)
+	}
+	return grants
+}
+
+// SetFeeGrant stores grant, replacing any allowance of the same granter for
+// the same grantee.
+func (fk FeeGrantKeeper) SetFeeGrant(ctx sdk.Context, grant FeeGrant) {
+	stor := ctx.Store(fk.key)
+	stor.Set(FeeGrantStoreKey(grant.Grantee, grant.Granter), amino.MustMarshal(grant))
+}
+
+// RemoveFeeGrant removes the fee allowance of granter for grantee.
+func (fk FeeGrantKeeper) RemoveFeeGrant(ctx sdk.Context, granter, grantee crypto.Address) {
+	stor := ctx.Store(fk.key)
+	stor.Delete(FeeGrantStoreKey(grantee, granter))
+}
+
+// UseFeeGrant checks that the fee allowance of granter for grantee pays fee
+// for msgs, and deducts fee from its spend limit. The allowance is removed
+// once its spend limit is used up.
+func (fk FeeGrantKeeper) UseFeeGrant(ctx sdk.Context, granter, grantee crypto.Address, fee std.Coins, msgs []std.Msg) error {
+	grant, ok := fk.GetFeeGrant(ctx, granter, grantee)
+	if !ok {
+		return std.ErrUnauthorized(fmt.Sprintf("no fee allowance from %s to %s", granter, grantee))
+	}
+	allowance := grant.Allowance
+	if allowance.IsExpired(ctx.BlockTime()) {
+		return std.ErrUnauthorized(fmt.Sprintf("fee allowance from %s to %s expired", granter, grantee))
+	}
+	for _, msg := range msgs {
+		if err := allowance.Allows(msg); err != nil {
+			return err
+		}
+	}
+
+	if allowance.SpendLimit.Empty() {
+		// unlimited
+		return nil
+	}
+	left := allowance.SpendLimit.SubUnsafe(fee)
+	if !left.IsValid() {
+		return std.ErrInsufficientFunds(fmt.Sprintf("fee exceeds the spend limit of the fee allowance; %s < %s", allowance.SpendLimit, fee))
+	}
+	if left.IsZero() {
+		fk.RemoveFeeGrant(ctx, granter, grantee)
+		return nil
+	}
+	grant.Allowance.SpendLimit = left
+	fk.SetFeeGrant(ctx, grant)
+	return nil
+}
@@ -0,0 +1,234 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	tu "github.com/gnolang/gno/tm2/pkg/sdk/testutils"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+type pkgPathMsg struct {
+	*tu.TestMsg
+	pkgPath string
+}
+
+func (msg pkgPathMsg) GetPkgPath() string { return msg.pkgPath }
+
+func TestFeeGrantKeeperGetSet(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	_, _, granter1 := tu.KeyTestPubAddr()
+	_, _, granter2 := tu.KeyTestPubAddr()
+	_, _, grantee := tu.KeyTestPubAddr()
+
+	_, ok := env.fgk.GetFeeGrant(env.ctx, granter1, grantee)
+	assert.False(t, ok)
+	assert.Empty(t, env.fgk.GetFeeGrants(env.ctx, grantee))
+
+	grant1 := FeeGrant{Granter: granter1, Grantee: grantee}
+	grant2 := FeeGrant{
+		Granter:   granter2,
+		Grantee:   grantee,
+		Allowance: FeeAllowance{SpendLimit: std.NewCoins(std.NewCoin("ugnot", 10))},
+	}
+	env.fgk.SetFeeGrant(env.ctx, grant1)
+	env.fgk.SetFeeGrant(env.ctx, grant2)
+
+	grant, ok := env.fgk.GetFeeGrant(env.ctx, granter2, grantee)
+	require.True(t, ok)
+	assert.Equal(t, grant2, grant)
+	assert.ElementsMatch(t, []FeeGrant{grant1, grant2}, env.fgk.GetFeeGrants(env.ctx, grantee))
+	assert.Empty(t, env.fgk.GetFeeGrants(env.ctx, granter1))
+
+	env.fgk.RemoveFeeGrant(env.ctx, granter1, grantee)
+	assert.Equal(t, []FeeGrant{grant2}, env.fgk.GetFeeGrants(env.ctx, grantee))
+}
+
+func TestUseFeeGrant(t *testing.T) {
+	t.Parallel()
+
+	_, _, granter := tu.KeyTestPubAddr()
+	_, _, grantee := tu.KeyTestPubAddr()
+	fee := std.NewCoins(std.NewCoin("ugnot", 10))
+	pkgMsg := pkgPathMsg{tu.NewTestMsg(grantee), "gno.land/r/demo/foo"}
+
+	tests := []struct {
+		name      string
+		allowance FeeAllowance
+		msg       std.Msg // the package path msg if nil
+		err       error
+		left      std.Coins // nil if the grant is removed
+	}{
+		{"unlimited", FeeAllowance{}, nil, nil, nil},
+		{
+			"spend limit",
+			FeeAllowance{SpendLimit: std.NewCoins(std.NewCoin("ugnot", 15))},
+			nil,
+			nil,
+			std.NewCoins(std.NewCoin("ugnot", 5)),
+		},
+		{
+			"spend limit used up",
+			FeeAllowance{SpendLimit: std.NewCoins(std.NewCoin("ugnot", 10))},
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"spend limit exceeded",
+			FeeAllowance{SpendLimit: std.NewCoins(std.NewCoin("ugnot", 9))},
+			nil,
+			std.InsufficientFundsError{},
+			nil,
+		},
+		{
+			"spend limit of another denom",
+			FeeAllowance{SpendLimit: std.NewCoins(std.NewCoin("atom", 100))},
+			nil,
+			std.InsufficientFundsError{},
+			nil,
+		},
+		{
+			"not expired",
+			FeeAllowance{Expiration: time.Unix(2, 0)},
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"expired",
+			FeeAllowance{Expiration: time.Unix(1, 0)},
+			nil,
+			std.UnauthorizedError{},
+			nil,
+		},
+		{
+			"allowed msg",
+			FeeAllowance{AllowedMsgs: []string{"/sdk.testutils.TestMsg"}},
+			tu.NewTestMsg(grantee),
+			nil,
+			nil,
+		},
+		{
+			"disallowed msg",
+			FeeAllowance{AllowedMsgs: []string{"/vm.m_call"}},
+			tu.NewTestMsg(grantee),
+			std.UnauthorizedError{},
+			nil,
+		},
+		{
+			"allowed package",
+			FeeAllowance{AllowedPkgPaths: []string{"gno.land/r/demo/bar", "gno.land/r/demo/foo"}},
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"disallowed package",
+			FeeAllowance{AllowedPkgPaths: []string{"gno.land/r/demo/bar"}},
+			nil,
+			std.UnauthorizedError{},
+			nil,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			env := setupTestEnv()
+			ctx := env.ctx.WithBlockHeader(&bft.Header{Height: 1, ChainID: "test-chain-id", Time: time.Unix(1, 0)})
+			env.fgk.SetFeeGrant(ctx, FeeGrant{Granter: granter, Grantee: grantee, Allowance: tc.allowance})
+			grant, ok := env.fgk.GetFeeGrant(ctx, granter, grantee) // as decoded
+			require.True(t, ok)
+
+			msg := tc.msg
+			if msg == nil {
+				msg = pkgMsg
+			}
+			err := env.fgk.UseFeeGrant(ctx, granter, grantee, fee, []std.Msg{msg})
+			if tc.err != nil {
+				require.IsType(t, tc.err, sdk.ABCIError(err))
+				got, ok := env.fgk.GetFeeGrant(ctx, granter, grantee)
+				require.True(t, ok)
+				assert.Equal(t, grant, got)
+				return
+			}
+			require.NoError(t, err)
+
+			got, ok := env.fgk.GetFeeGrant(ctx, granter, grantee)
+			switch {
+			case tc.allowance.SpendLimit.Empty():
+				require.True(t, ok)
+				assert.Equal(t, grant, got)
+			case tc.left == nil:
+				assert.False(t, ok)
+			default:
+				require.True(t, ok)
+				assert.Equal(t, tc.left, got.Allowance.SpendLimit)
+			}
+		})
+	}
+
+	// msgs without a package path are not allowed by package
+	env := setupTestEnv()
+	env.fgk.SetFeeGrant(env.ctx, FeeGrant{
+		Granter:   granter,
+		Grantee:   grantee,
+		Allowance: FeeAllowance{AllowedPkgPaths: []string{"gno.land/r/demo/foo"}},
+	})
+	err := env.fgk.UseFeeGrant(env.ctx, granter, grantee, fee, []std.Msg{tu.NewTestMsg(grantee)})
+	require.IsType(t, std.UnauthorizedError{}, sdk.ABCIError(err))
+
+	// no grant
+	err = env.fgk.UseFeeGrant(env.ctx, grantee, granter, fee, []std.Msg{pkgMsg})
+	require.IsType(t, std.UnauthorizedError{}, sdk.ABCIError(err))
+}
+
+func TestMsgGrantAllowanceValidateBasic(t *testing.T) {
+	t.Parallel()
+
+	_, _, addr1 := tu.KeyTestPubAddr()
+	_, _, addr2 := tu.KeyTestPubAddr()
+
+	tests := []struct {
+		name  string
+		msg   MsgGrantAllowance
+		valid bool
+	}{
+		{"valid", NewMsgGrantAllowance(addr1, addr2, FeeAllowance{}), true},
+		{"no granter", NewMsgGrantAllowance(crypto.Address{}, addr2, FeeAllowance{}), false},
+		{"no grantee", NewMsgGrantAllowance(addr1, crypto.Address{}, FeeAllowance{}), false},
+		{"self grant", NewMsgGrantAllowance(addr1, addr1, FeeAllowance{}), false},
+		{
+			"invalid spend limit",
+			NewMsgGrantAllowance(addr1, addr2, FeeAllowance{SpendLimit: std.Coins{{Denom: "ugnot", Amount: -1}}}),
+			false,
+		},
+		{
+			"empty allowed msg",
+			NewMsgGrantAllowance(addr1, addr2, FeeAllowance{AllowedMsgs: []string{""}}),
+			false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.msg.ValidateBasic()
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+			assert.Equal(t, tc.msg.Granter, tc.msg.GetSigners()[0])
+		})
+	}
+}
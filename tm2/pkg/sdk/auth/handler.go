@@ -14,20 +14,52 @@ import (
 type authHandler struct {
 	acck  AccountKeeper
 	gpKpr GasPriceKeeper
+	fgk   FeeGrantKeeperI
 }
 
 // NewHandler returns a handler for "auth" type messages.
-func NewHandler(acck AccountKeeper, gpKpr GasPriceKeeper) authHandler {
+func NewHandler(acck AccountKeeper, gpKpr GasPriceKeeper, fgk FeeGrantKeeperI) authHandler {
 	return authHandler{
 		acck:  acck,
 		gpKpr: gpKpr,
+		fgk:   fgk,
 	}
 }
 
 func (ah authHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
-	// no messages supported yet.
-	errMsg := fmt.Sprintf("unrecognized auth message type: %T", msg)
-	return abciResult(std.ErrUnknownRequest(errMsg))
+	switch msg := msg.(type) {
+	case MsgGrantAllowance:
+		return ah.handleMsgGrantAllowance(ctx, msg)
+	case MsgRevokeAllowance:
+		return ah.handleMsgRevokeAllowance(ctx, msg)
+	default:
+		errMsg := fmt.Sprintf("unrecognized auth message type: %T", msg)
+		return abciResult(std.ErrUnknownRequest(errMsg))
+	}
+}
+
+// Handle MsgGrantAllowance.
+func (ah authHandler) handleMsgGrantAllowance(ctx sdk.Context, msg MsgGrantAllowance) sdk.Result {
+	if ah.acck.GetAccount(ctx, msg.Granter) == nil {
+		return abciResult(std.ErrUnknownAddress(
+			fmt.Sprintf("granter %s does not exist", msg.Granter)))
+	}
+	ah.fgk.SetFeeGrant(ctx, FeeGrant{
+		Granter:   msg.Granter,
+		Grantee:   msg.Grantee,
+		Allowance: msg.Allowance,
+	})
+	return sdk.Result{}
+}
+
+// Handle MsgRevokeAllowance.
+func (ah authHandler) handleMsgRevokeAllowance(ctx sdk.Context, msg MsgRevokeAllowance) sdk.Result {
+	if _, ok := ah.fgk.GetFeeGrant(ctx, msg.Granter, msg.Grantee); !ok {
+		return abciResult(std.ErrUnknownRequest(
+			fmt.Sprintf("no fee allowance from %s to %s", msg.Granter, msg.Grantee)))
+	}
+	ah.fgk.RemoveFeeGrant(ctx, msg.Granter, msg.Grantee)
+	return sdk.Result{}
 }
 
 //----------------------------------------
@@ -35,8 +67,9 @@ func (ah authHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
 
 // query path
 const (
-	QueryAccount  = "accounts"
-	QueryGasPrice = "gasprice"
+	QueryAccount    = "accounts"
+	QueryGasPrice   = "gasprice"
+	QueryAllowances = "allowances"
 )
 
 func (ah authHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
@@ -45,6 +78,8 @@ func (ah authHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.Re
 		return ah.queryAccount(ctx, req)
 	case QueryGasPrice:
 		return ah.queryGasPrice(ctx, req)
+	case QueryAllowances:
+		return ah.queryAllowances(ctx, req)
 	default:
 		res = sdk.ABCIResponseQueryFromError(
 			std.ErrUnknownRequest("unknown auth query endpoint"))
@@ -95,6 +130,34 @@ func (ah authHandler) queryGasPrice(ctx sdk.Context, req abci.RequestQuery) (res
 	return
 }
 
+// queryAllowances fetch the fee allowances granted to an address.
+// Grantee address are passed as path component.
+func (ah authHandler) queryAllowances(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	// parse addr from path.
+	b32addr := thirdPart(req.Path)
+	addr, err := crypto.AddressFromBech32(b32addr)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInvalidAddress(
+				"invalid query address " + b32addr))
+		return
+	}
+
+	grants := ah.fgk.GetFeeGrants(ctx, addr)
+	if grants == nil {
+		grants = []FeeGrant{}
+	}
+	bz, err := amino.MarshalJSONIndent(grants, "", "  ")
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInternal(fmt.Sprintf("could not marshal result to JSON: %s", err.Error())))
+		return
+	}
+
+	res.Data = bz
+	return
+}
+
 //----------------------------------------
 // misc
 
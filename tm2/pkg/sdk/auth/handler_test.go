@@ -19,7 +19,7 @@ import (
 func TestInvalidMsg(t *testing.T) {
 	t.Parallel()
 
-	h := NewHandler(AccountKeeper{}, GasPriceKeeper{})
+	h := NewHandler(AccountKeeper{}, GasPriceKeeper{}, FeeGrantKeeper{})
 	res := h.Process(sdk.NewContext(sdk.RunTxModeDeliver, nil, &bft.Header{ChainID: "test-chain"}, nil), tu.NewTestMsg())
 	require.False(t, res.IsOK())
 	require.True(t, strings.Contains(res.Log, "unrecognized auth message type"))
@@ -29,7 +29,7 @@ func TestQueryAccount(t *testing.T) {
 	t.Parallel()
 
 	env := setupTestEnv()
-	h := NewHandler(env.acck, env.gk)
+	h := NewHandler(env.acck, env.gk, env.fgk)
 	_, pubkey, addr := tu.KeyTestPubAddr()
 
 	acc := env.acck.NewAccountWithAddress(env.ctx, addr)
@@ -62,7 +62,7 @@ func TestQueryGasPrice(t *testing.T) {
 	t.Parallel()
 
 	env := setupTestEnv()
-	h := NewHandler(env.acck, env.gk)
+	h := NewHandler(env.acck, env.gk, env.fgk)
 
 	req := abci.RequestQuery{
 		Path: fmt.Sprintf("auth/%s", QueryGasPrice),
@@ -89,11 +89,51 @@ func TestQueryGasPrice(t *testing.T) {
 	require.True(t, gp == gp2)
 }
 
+func TestGrantRevokeAllowance(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	h := NewHandler(env.acck, env.gk, env.fgk)
+	_, _, granter := tu.KeyTestPubAddr()
+	_, _, grantee := tu.KeyTestPubAddr()
+	allowance := FeeAllowance{SpendLimit: std.NewCoins(std.NewCoin("ugnot", 1000))}
+
+	// the granter must exist
+	res := h.Process(env.ctx, NewMsgGrantAllowance(granter, grantee, allowance))
+	require.False(t, res.IsOK())
+	require.IsType(t, std.UnknownAddressError{}, res.Error)
+
+	env.acck.SetAccount(env.ctx, env.acck.NewAccountWithAddress(env.ctx, granter))
+	res = h.Process(env.ctx, NewMsgGrantAllowance(granter, grantee, allowance))
+	require.True(t, res.IsOK(), res.Log)
+
+	req := abci.RequestQuery{
+		Path: fmt.Sprintf("auth/%s/%s", QueryAllowances, grantee),
+		Data: []byte{},
+	}
+	res2 := h.Query(env.ctx, req)
+	require.Nil(t, res2.Error)
+	var grants []FeeGrant
+	require.NoError(t, amino.UnmarshalJSON(res2.Data, &grants))
+	require.Equal(t, []FeeGrant{{Granter: granter, Grantee: grantee, Allowance: allowance}}, grants)
+
+	res = h.Process(env.ctx, NewMsgRevokeAllowance(granter, grantee))
+	require.True(t, res.IsOK(), res.Log)
+	res2 = h.Query(env.ctx, req)
+	require.Nil(t, res2.Error)
+	require.NoError(t, amino.UnmarshalJSON(res2.Data, &grants))
+	require.Empty(t, grants)
+
+	// nothing left to revoke
+	res = h.Process(env.ctx, NewMsgRevokeAllowance(granter, grantee))
+	require.False(t, res.IsOK())
+}
+
 func TestQuerierRouteNotFound(t *testing.T) {
 	t.Parallel()
 
 	env := setupTestEnv()
-	h := NewHandler(env.acck, env.gk)
+	h := NewHandler(env.acck, env.gk, env.fgk)
 	req := abci.RequestQuery{
 		Path: "auth/notexist",
 		Data: []byte{},
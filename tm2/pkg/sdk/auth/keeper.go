@@ -123,6 +123,33 @@ func (ak AccountKeeper) IterateAccounts(ctx sdk.Context, process func(std.Accoun
 	}
 }
 
+// pendingFee is the fee paid by Payer for a tx pending in the mempool.
+type pendingFee struct {
+	Payer crypto.Address
+	Fee   std.Coin
+}
+
+// getPendingFee returns the fee paid for the pending tx of addr with sequence,
+// recorded in the check state.
+func (ak AccountKeeper) getPendingFee(ctx sdk.Context, addr crypto.Address, sequence uint64) (pendingFee, bool) {
+	stor := ctx.Store(ak.key)
+	bz := stor.Get(PendingFeeStoreKey(addr, sequence))
+	if bz == nil {
+		return pendingFee{}, false
+	}
+	var pf pendingFee
+	amino.MustUnmarshal(bz, &pf)
+	return pf, true
+}
+
+// setPendingFee records the fee paid for the pending tx of addr with
+// sequence. It must only be called in the check state, which is reset to the
+// committed state after each block.
+func (ak AccountKeeper) setPendingFee(ctx sdk.Context, addr crypto.Address, sequence uint64, pf pendingFee) {
+	stor := ctx.Store(ak.key)
+	stor.Set(PendingFeeStoreKey(addr, sequence), amino.MustMarshal(pf))
+}
+
 // GetPubKey Returns the PubKey of the account at address
 func (ak AccountKeeper) GetPubKey(ctx sdk.Context, addr crypto.Address) (crypto.PubKey, error) {
 	acc := ak.GetAccount(ctx, addr)
@@ -20,6 +20,7 @@ const (
 	DefaultTxSizeCostPerByte      int64 = 10
 	DefaultSigVerifyCostED25519   int64 = 590
 	DefaultSigVerifyCostSecp256k1 int64 = 1000
+	DefaultSigVerifyCostSecp256r1 int64 = 1500 // includes the WebAuthn client data checks
 
 	DefaultGasPricesChangeCompressor int64 = 10
 	DefaultTargetGasRatio            int64 = 70 //  70% of the MaxGas in a block
@@ -34,6 +35,7 @@ type Params struct {
 	TxSizeCostPerByte         int64            `json:"tx_size_cost_per_byte" yaml:"tx_size_cost_per_byte"`
 	SigVerifyCostED25519      int64            `json:"sig_verify_cost_ed25519" yaml:"sig_verify_cost_ed25519"`
 	SigVerifyCostSecp256k1    int64            `json:"sig_verify_cost_secp256k1" yaml:"sig_verify_cost_secp256k1"`
+	SigVerifyCostSecp256r1    int64            `json:"sig_verify_cost_secp256r1" yaml:"sig_verify_cost_secp256r1"` // 0 for DefaultSigVerifyCostSecp256r1
 	GasPricesChangeCompressor int64            `json:"gas_price_change_compressor" yaml:"gas_price_change_compressor"`
 	TargetGasRatio            int64            `json:"target_gas_ratio" yaml:"target_gas_ratio"`
 	InitialGasPrice           std.GasPrice     `json:"initial_gasprice"`
@@ -43,7 +45,7 @@ type Params struct {
 
 // NewParams creates a new Params object
 func NewParams(maxMemoBytes, txSigLimit, txSizeCostPerByte,
-	sigVerifyCostED25519, sigVerifyCostSecp256k1, gasPricesChangeCompressor, targetGasRatio int64,
+	sigVerifyCostED25519, sigVerifyCostSecp256k1, sigVerifyCostSecp256r1, gasPricesChangeCompressor, targetGasRatio int64,
 	feeCollector crypto.Address,
 ) Params {
 	return Params{
@@ -52,6 +54,7 @@ func NewParams(maxMemoBytes, txSigLimit, txSizeCostPerByte,
 		TxSizeCostPerByte:         txSizeCostPerByte,
 		SigVerifyCostED25519:      sigVerifyCostED25519,
 		SigVerifyCostSecp256k1:    sigVerifyCostSecp256k1,
+		SigVerifyCostSecp256r1:    sigVerifyCostSecp256r1,
 		GasPricesChangeCompressor: gasPricesChangeCompressor,
 		TargetGasRatio:            targetGasRatio,
 		FeeCollector:              feeCollector,
@@ -71,6 +74,7 @@ func DefaultParams() Params {
 		DefaultTxSizeCostPerByte,
 		DefaultSigVerifyCostED25519,
 		DefaultSigVerifyCostSecp256k1,
+		DefaultSigVerifyCostSecp256r1,
 		DefaultGasPricesChangeCompressor,
 		DefaultTargetGasRatio,
 		crypto.AddressFromPreimage([]byte(DefaultFeeCollectorName)),
@@ -87,6 +91,7 @@ func (p Params) String() string {
 	fmt.Fprintf(sb, "TxSizeCostPerByte: %d\n", p.TxSizeCostPerByte)
 	fmt.Fprintf(sb, "SigVerifyCostED25519: %d\n", p.SigVerifyCostED25519)
 	fmt.Fprintf(sb, "SigVerifyCostSecp256k1: %d\n", p.SigVerifyCostSecp256k1)
+	fmt.Fprintf(sb, "SigVerifyCostSecp256r1: %d\n", p.SigVerifyCostSecp256r1)
 	fmt.Fprintf(sb, "GasPricesChangeCompressor: %d\n", p.GasPricesChangeCompressor)
 	fmt.Fprintf(sb, "TargetGasRatio: %d\n", p.TargetGasRatio)
 	fmt.Fprintf(sb, "FeeCollector: %s\n", p.FeeCollector.String())
@@ -106,6 +111,10 @@ func (p Params) Validate() error {
 	if p.SigVerifyCostSecp256k1 <= 0 {
 		return fmt.Errorf("invalid SECK256k1 signature verification cost: %d", p.SigVerifyCostSecp256k1)
 	}
+	// A zero cost is allowed for the params saved before it was added.
+	if p.SigVerifyCostSecp256r1 < 0 {
+		return fmt.Errorf("invalid SECP256r1 signature verification cost: %d", p.SigVerifyCostSecp256r1)
+	}
 	if p.TxSizeCostPerByte <= 0 {
 		return fmt.Errorf("invalid tx size cost per byte: %d", p.TxSizeCostPerByte)
 	}
@@ -152,6 +161,15 @@ func (ak AccountKeeper) SetParams(ctx sdk.Context, params Params) error {
 	return nil
 }
 
+// sigVerifyCostSecp256r1 returns the secp256r1 signature verification cost,
+// which defaults to DefaultSigVerifyCostSecp256r1 when unset.
+func (p Params) sigVerifyCostSecp256r1() int64 {
+	if p.SigVerifyCostSecp256r1 == 0 {
+		return DefaultSigVerifyCostSecp256r1
+	}
+	return p.SigVerifyCostSecp256r1
+}
+
 func (ak AccountKeeper) GetParams(ctx sdk.Context) Params {
 	params := Params{}
 	ak.prmk.GetStruct(ctx, "p", &params)
@@ -171,6 +189,8 @@ func (ak AccountKeeper) WillSetParam(ctx sdk.Context, key string, value any) {
 		params.SigVerifyCostED25519 = sdkparams.MustParamInt64("sig_verify_cost_ed25519", value)
 	case "p:sig_verify_cost_secp256k1":
 		params.SigVerifyCostSecp256k1 = sdkparams.MustParamInt64("sig_verify_cost_secp256k1", value)
+	case "p:sig_verify_cost_secp256r1":
+		params.SigVerifyCostSecp256r1 = sdkparams.MustParamInt64("sig_verify_cost_secp256r1", value)
 	case "p:gas_price_change_compressor":
 		params.GasPricesChangeCompressor = sdkparams.MustParamInt64("gas_price_change_compressor", value)
 	case "p:target_gas_ratio":
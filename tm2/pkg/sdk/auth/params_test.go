@@ -1,12 +1,16 @@
 package auth
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/secp256r1"
+	"github.com/gnolang/gno/tm2/pkg/store"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -25,6 +29,7 @@ func TestValidate(t *testing.T) {
 				TxSizeCostPerByte:         1,
 				SigVerifyCostED25519:      100,
 				SigVerifyCostSecp256k1:    200,
+				SigVerifyCostSecp256r1:    300,
 				GasPricesChangeCompressor: 1,
 				TargetGasRatio:            50,
 				FeeCollector:              crypto.AddressFromPreimage([]byte("test_collector")),
@@ -80,6 +85,7 @@ func TestNewParams(t *testing.T) {
 	txSizeCostPerByte := int64(5)
 	sigVerifyCostED25519 := int64(100)
 	sigVerifyCostSecp256k1 := int64(200)
+	sigVerifyCostSecp256r1 := int64(300)
 	gasPricesChangeCompressor := int64(50)
 	targetGasRatio := int64(75)
 	feeCollector := crypto.AddressFromPreimage([]byte("test_collector"))
@@ -91,6 +97,7 @@ func TestNewParams(t *testing.T) {
 		txSizeCostPerByte,
 		sigVerifyCostED25519,
 		sigVerifyCostSecp256k1,
+		sigVerifyCostSecp256r1,
 		gasPricesChangeCompressor,
 		targetGasRatio,
 		feeCollector,
@@ -103,6 +110,7 @@ func TestNewParams(t *testing.T) {
 		TxSizeCostPerByte:         txSizeCostPerByte,
 		SigVerifyCostED25519:      sigVerifyCostED25519,
 		SigVerifyCostSecp256k1:    sigVerifyCostSecp256k1,
+		SigVerifyCostSecp256r1:    sigVerifyCostSecp256r1,
 		GasPricesChangeCompressor: gasPricesChangeCompressor,
 		TargetGasRatio:            targetGasRatio,
 		FeeCollector:              feeCollector,
@@ -274,11 +282,11 @@ func TestParamsString(t *testing.T) {
 		params Params
 		want   string
 	}{
-		{"blank params", Params{}, "Params: \nMaxMemoBytes: 0\nTxSigLimit: 0\nTxSizeCostPerByte: 0\nSigVerifyCostED25519: 0\nSigVerifyCostSecp256k1: 0\nGasPricesChangeCompressor: 0\nTargetGasRatio: 0\nFeeCollector: g1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqluuxe\n"},
+		{"blank params", Params{}, "Params: \nMaxMemoBytes: 0\nTxSigLimit: 0\nTxSizeCostPerByte: 0\nSigVerifyCostED25519: 0\nSigVerifyCostSecp256k1: 0\nSigVerifyCostSecp256r1: 0\nGasPricesChangeCompressor: 0\nTargetGasRatio: 0\nFeeCollector: g1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqluuxe\n"},
 		{"some values", Params{
 			MaxMemoBytes:      1_000_000,
 			TxSizeCostPerByte: 8192,
-		}, "Params: \nMaxMemoBytes: 1000000\nTxSigLimit: 0\nTxSizeCostPerByte: 8192\nSigVerifyCostED25519: 0\nSigVerifyCostSecp256k1: 0\nGasPricesChangeCompressor: 0\nTargetGasRatio: 0\nFeeCollector: g1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqluuxe\n"},
+		}, "Params: \nMaxMemoBytes: 1000000\nTxSigLimit: 0\nTxSizeCostPerByte: 8192\nSigVerifyCostED25519: 0\nSigVerifyCostSecp256k1: 0\nSigVerifyCostSecp256r1: 0\nGasPricesChangeCompressor: 0\nTargetGasRatio: 0\nFeeCollector: g1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqluuxe\n"},
 	}
 
 	for _, tt := range cases {
@@ -288,3 +296,36 @@ func TestParamsString(t *testing.T) {
 		})
 	}
 }
+
+func TestParamsWithoutSigVerifyCostSecp256r1(t *testing.T) {
+	env := setupTestEnv()
+
+	// A genesis created before the secp256r1 cost was added
+	bz := amino.MustMarshalJSON(DefaultGenesisState())
+	var raw map[string]map[string]any
+	require.NoError(t, json.Unmarshal(bz, &raw))
+	delete(raw["params"], "sig_verify_cost_secp256r1")
+	bz, err := json.Marshal(raw)
+	require.NoError(t, err)
+
+	var genesis GenesisState
+	require.NoError(t, amino.UnmarshalJSON(bz, &genesis))
+	require.Zero(t, genesis.Params.SigVerifyCostSecp256r1)
+
+	require.NotPanics(t, func() {
+		env.acck.InitGenesis(env.ctx, genesis)
+	})
+	params := env.acck.GetParams(env.ctx)
+	assert.Zero(t, params.SigVerifyCostSecp256r1)
+
+	// Changing another param of the stored params
+	require.NotPanics(t, func() {
+		env.acck.WillSetParam(env.ctx, "p:max_memo_bytes", int64(1024))
+	})
+
+	// The verification of a secp256r1 signature is charged the default cost
+	meter := store.NewInfiniteGasMeter()
+	res := DefaultSigVerificationGasConsumer(meter, nil, secp256r1.GenPrivKey().PubKey(), params)
+	require.True(t, res.IsOK())
+	assert.Equal(t, DefaultSigVerifyCostSecp256r1, meter.GasConsumed())
+}
@@ -18,6 +18,7 @@ type testEnv struct {
 	acck  AccountKeeper
 	bankk BankKeeperI
 	gk    GasPriceKeeper
+	fgk   FeeGrantKeeper
 }
 
 func setupTestEnv() testEnv {
@@ -33,6 +34,7 @@ func setupTestEnv() testEnv {
 	acck := NewAccountKeeper(authCapKey, prmk.ForModule(ModuleName), std.ProtoBaseAccount)
 	bankk := NewDummyBankKeeper(acck, prmk.ForModule("dummybank"))
 	gk := NewGasPriceKeeper(authCapKey)
+	fgk := NewFeeGrantKeeper(authCapKey)
 
 	prmk.Register(ModuleName, acck)
 	prmk.Register("dummybank", bankk)
@@ -55,7 +57,7 @@ func setupTestEnv() testEnv {
 		},
 	})
 
-	return testEnv{ctx: ctx, acck: acck, bankk: bankk, gk: gk}
+	return testEnv{ctx: ctx, acck: acck, bankk: bankk, gk: gk, fgk: fgk}
 }
 
 // DummyBankKeeper defines a supply keeper used only for testing to avoid
@@ -32,3 +32,13 @@ type GasPriceKeeperI interface {
 }
 
 var _ GasPriceKeeperI = GasPriceKeeper{}
+
+type FeeGrantKeeperI interface {
+	GetFeeGrant(ctx sdk.Context, granter, grantee crypto.Address) (FeeGrant, bool)
+	GetFeeGrants(ctx sdk.Context, grantee crypto.Address) []FeeGrant
+	SetFeeGrant(ctx sdk.Context, grant FeeGrant)
+	RemoveFeeGrant(ctx sdk.Context, granter, grantee crypto.Address)
+	UseFeeGrant(ctx sdk.Context, granter, grantee crypto.Address, fee std.Coins, msgs []std.Msg) error
+}
+
+var _ FeeGrantKeeperI = FeeGrantKeeper{}
@@ -1,8 +1,11 @@
 package sdk
 
 import (
+	"bytes"
+	goerrors "errors"
 	"fmt"
 	"log/slog"
+	"net/url"
 	"os"
 	"runtime/debug"
 	"sort"
@@ -16,6 +19,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/gnolang/gno/tm2/pkg/store"
+	"github.com/gnolang/gno/tm2/pkg/store/snapshots"
 )
 
 // Key to store the consensus params in the main store.
@@ -44,6 +48,13 @@ type BaseApp struct {
 
 	beginTxHook BeginTxHook // BaseApp-specific hook run before running transaction messages.
 	endTxHook   EndTxHook   // BaseApp-specific hook run after running transaction messages.
+	restoreHook RestoreHook // BaseApp-specific hook run after restoring a state sync snapshot.
+
+	simulateProfiler SimulateProfiler // BaseApp-specific hook profiling simulated transactions.
+
+	// state sync snapshots, set by SetSnapshotOptions.
+	snapshotManager *snapshots.Manager
+	restoreAppHash  []byte // trusted app hash of the snapshot being restored
 
 	// --------------------
 	// Volatile state
@@ -411,15 +422,42 @@ func handleQueryApp(app *BaseApp, path []string, req abci.RequestQuery) (res abc
 	if len(path) >= 2 {
 		var result Result
 
-		switch path[1] {
+		// The query may have flags, e.g. ".app/simulate?profile=1".
+		name, rawFlags, _ := strings.Cut(path[1], "?")
+		switch name {
 		case "simulate":
+			flags, err := url.ParseQuery(rawFlags)
+			if err != nil {
+				res.Error = ABCIError(std.ErrUnknownRequest(fmt.Sprintf("invalid query flags: %s", err)))
+				return
+			}
+			// With the profile flag, the profile of the transaction is
+			// returned in res.Data.
+			var (
+				ctxFn   ContextFn
+				profile func() ([]byte, error)
+			)
+			if flags.Get("profile") == "1" {
+				if app.simulateProfiler == nil {
+					res.Error = ABCIError(std.ErrUnknownRequest("the app cannot profile simulated transactions"))
+					return
+				}
+				ctxFn, profile = app.simulateProfiler()
+			}
+
 			txBytes := req.Data
 			var tx Tx
-			err := amino.Unmarshal(txBytes, &tx)
+			err = amino.Unmarshal(txBytes, &tx)
 			if err != nil {
 				res.Error = ABCIError(std.ErrTxDecode(err.Error()))
 			} else {
-				result = app.Simulate(txBytes, tx)
+				result = app.Simulate(txBytes, tx, ctxFn)
+				if profile != nil {
+					res.Data, err = profile()
+					if err != nil {
+						res.Error = ABCIError(std.ErrInternal(fmt.Sprintf("cannot write the profile: %s", err)))
+					}
+				}
 			}
 
 			res.Height = req.Height
@@ -508,12 +546,31 @@ func handleQueryCustom(app *BaseApp, path []string, req abci.RequestQuery) (res
 	// cache wrap the commit-multistore for safety
 	// XXX RunTxModeQuery?
 	ctx := NewContext(RunTxModeCheck, cacheMS, app.checkState.ctx.BlockHeader(), app.logger).WithMinGasPrices(app.minGasPrices)
+	if req.Prove {
+		// lets the handler prove its results with the committed stores.
+		if queryable, ok := app.cms.(store.Queryable); ok {
+			ctx = ctx.WithValue(queryableContextKey{}, queryable)
+		}
+	}
 
 	// Passes the query to the handler.
 	res = handler.Query(ctx, req)
 	return
 }
 
+type queryableContextKey struct{}
+
+// QueryStore queries the committed multistore, like the "/.store" queries,
+// e.g. to prove a value of a store at the height of a custom query. It is
+// only available to the handlers of the custom queries requesting a proof.
+func QueryStore(ctx Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	queryable, ok := ctx.Value(queryableContextKey{}).(store.Queryable)
+	if !ok {
+		return ABCIResponseQueryFromError(std.ErrInternal("store queries are only available to queries requesting a proof"))
+	}
+	return queryable.Query(req)
+}
+
 func (app *BaseApp) validateHeight(req abci.RequestBeginBlock) error {
 	if req.Header.GetHeight() < 1 {
 		return fmt.Errorf("invalid height: %d", req.Header.GetHeight())
@@ -579,15 +636,42 @@ func (app *BaseApp) CheckTx(req abci.RequestCheckTx) (res abci.ResponseCheckTx)
 		return
 	} else {
 		ctx := app.getContextForTx(RunTxModeCheck, req.Tx)
+		if req.Type == abci.CheckTxTypeNew {
+			// lets the ante handler accept replacements of pending txs.
+			ctx = WithCommittedMultiStore(ctx, app.cms)
+		}
 
 		result := app.runTx(ctx, tx)
 		res.ResponseBase = result.ResponseBase
 		res.GasWanted = result.GasWanted
 		res.GasUsed = result.GasUsed
+		res.Priority = result.Priority
+		res.Sender = result.Sender
+		res.Sequence = result.Sequence
 		return
 	}
 }
 
+type committedContextKey struct{}
+
+// WithCommittedMultiStore returns ctx with ms, the last committed multistore,
+// letting the ante handler accept replacements of the pending txs of the check
+// state. See [CommittedContext].
+func WithCommittedMultiStore(ctx Context, ms store.MultiStore) Context {
+	return ctx.WithValue(committedContextKey{}, ms)
+}
+
+// CommittedContext returns ctx on a cache of the last committed multistore,
+// i.e. without the pending txs of the mempool. It is only available during the
+// CheckTx of new txs, as reported by the second return value.
+func CommittedContext(ctx Context) (Context, bool) {
+	ms, ok := ctx.Value(committedContextKey{}).(store.MultiStore)
+	if !ok {
+		return ctx, false
+	}
+	return ctx.WithMultiStore(ms.MultiCacheWrap()), true
+}
+
 // DeliverTx implements the ABCI interface.
 func (app *BaseApp) DeliverTx(req abci.RequestDeliverTx) (res abci.ResponseDeliverTx) {
 	var tx Tx
@@ -734,6 +818,8 @@ func (app *BaseApp) runTx(ctx Context, tx Tx) (result Result) {
 		// determined by the GasMeter. We need access to the context to get the gas
 		// meter so we initialize upfront.
 		gasWanted int64
+		// NOTE: the mempool fields are also returned by the AnteHandler.
+		anteResult Result
 
 		ms   = ctx.MultiStore()
 		mode = ctx.Mode()
@@ -848,6 +934,7 @@ func (app *BaseApp) runTx(ctx Context, tx Tx) (result Result) {
 			ctx = newCtx.WithMultiStore(ms)
 			msCache.MultiWrite()
 			gasWanted = result.GasWanted
+			anteResult = result
 		}
 	}
 
@@ -861,6 +948,9 @@ func (app *BaseApp) runTx(ctx Context, tx Tx) (result Result) {
 
 	result = app.runMsgs(runMsgCtx, msgs, mode)
 	result.GasWanted = gasWanted
+	result.Priority = anteResult.Priority
+	result.Sender = anteResult.Sender
+	result.Sequence = anteResult.Sequence
 
 	// Safety check: don't write the cache state unless we're in DeliverTx.
 	if mode != RunTxModeDeliver {
@@ -935,6 +1025,11 @@ func (app *BaseApp) Commit() (res abci.ResponseCommit) {
 	headerBz := amino.MustMarshal(header)
 	baseStore.Set(mainLastHeaderKey, headerBz)
 
+	// Take a state sync snapshot of the committed state, including the header.
+	if app.snapshotManager != nil {
+		app.snapshotManager.SnapshotIfNeeded(commitID.Version)
+	}
+
 	// Reset the Check state to the latest committed.
 	//
 	// NOTE: This is safe because Tendermint holds a lock on the mempool for
@@ -949,6 +1044,115 @@ func (app *BaseApp) Commit() (res abci.ResponseCommit) {
 	return
 }
 
+// ListSnapshots implements the ABCI interface. It returns the state sync
+// snapshots saved by the application.
+func (app *BaseApp) ListSnapshots(req abci.RequestListSnapshots) (res abci.ResponseListSnapshots) {
+	if app.snapshotManager == nil {
+		return
+	}
+	snapshots, err := app.snapshotManager.List()
+	if err != nil {
+		res.Error = ABCIError(err)
+		return
+	}
+	res.Snapshots = snapshots
+	return
+}
+
+// LoadSnapshotChunk implements the ABCI interface.
+func (app *BaseApp) LoadSnapshotChunk(req abci.RequestLoadSnapshotChunk) (res abci.ResponseLoadSnapshotChunk) {
+	if app.snapshotManager == nil {
+		return
+	}
+	chunk, err := app.snapshotManager.LoadChunk(req.Height, req.Format, req.Chunk)
+	if err != nil {
+		res.Error = ABCIError(err)
+		return
+	}
+	res.Chunk = chunk
+	return
+}
+
+// OfferSnapshot implements the ABCI interface. The snapshot is accepted if the
+// application has no state yet; its restoration is verified against the
+// trusted req.AppHash once all the chunks are applied.
+func (app *BaseApp) OfferSnapshot(req abci.RequestOfferSnapshot) (res abci.ResponseOfferSnapshot) {
+	switch {
+	case app.snapshotManager == nil:
+		res.Error = ABCIError(errors.New("state sync snapshots are disabled"))
+	case req.Snapshot == nil:
+		res.Error = ABCIError(errors.New("no snapshot offered"))
+	case app.LastBlockHeight() != 0:
+		res.Error = ABCIError(errors.New("cannot restore a snapshot at height %d", app.LastBlockHeight()))
+	case len(req.AppHash) == 0:
+		res.Error = ABCIError(errors.New("no trusted app hash"))
+	default:
+		if err := app.snapshotManager.Restore(req.Snapshot); err != nil {
+			res.Error = ABCIError(err)
+			return
+		}
+		app.restoreAppHash = req.AppHash
+		app.logger.Info("Restoring state sync snapshot",
+			"height", req.Snapshot.Height, "chunks", req.Snapshot.Chunks, "hash", fmt.Sprintf("%X", req.Snapshot.Hash))
+	}
+	return
+}
+
+// ApplySnapshotChunk implements the ABCI interface. A chunk which doesn't
+// match the snapshot metadata is rejected, to be fetched again from another
+// peer; any other error aborts the restoration.
+func (app *BaseApp) ApplySnapshotChunk(req abci.RequestApplySnapshotChunk) (res abci.ResponseApplySnapshotChunk) {
+	if app.snapshotManager == nil {
+		res.Error = ABCIError(snapshots.ErrNoRestore)
+		return
+	}
+	next, err := app.snapshotManager.RestoreNext()
+	if err != nil {
+		res.Error = ABCIError(err)
+		return
+	}
+	if req.Index != next {
+		res.Error = ABCIError(errors.New("expected snapshot chunk %d, got %d", next, req.Index))
+		return
+	}
+
+	done, err := app.snapshotManager.RestoreChunk(req.Chunk)
+	switch {
+	case goerrors.Is(err, snapshots.ErrChunkHashMismatch):
+		app.logger.Info("Rejected invalid snapshot chunk", "index", req.Index, "sender", req.Sender)
+		res.RefetchChunks = []uint32{req.Index}
+		if req.Sender != "" {
+			res.RejectSenders = []string{req.Sender}
+		}
+		return
+	case err != nil:
+		res.Error = ABCIError(err)
+		return
+	case !done:
+		return
+	}
+
+	// The snapshot is restored: check it against the trusted app hash.
+	commitID := app.cms.LastCommitID()
+	if !bytes.Equal(commitID.Hash, app.restoreAppHash) {
+		res.Error = ABCIError(errors.New("restored app hash %X doesn't match the trusted app hash %X",
+			commitID.Hash, app.restoreAppHash))
+		return
+	}
+	app.restoreAppHash = nil
+	if err := app.initFromMainStore(); err != nil {
+		res.Error = ABCIError(err)
+		return
+	}
+	if app.restoreHook != nil {
+		ms := app.cms.MultiCacheWrap()
+		app.restoreHook(ms)
+		ms.MultiWrite()
+	}
+	app.logger.Info("Restored state sync snapshot", "height", commitID.Version, "hash", fmt.Sprintf("%X", commitID.Hash))
+	return
+}
+
 // halt attempts to gracefully shutdown the node via SIGINT and SIGTERM falling
 // back on os.Exit if both fail.
 func (app *BaseApp) halt() {
@@ -972,6 +1176,9 @@ func (app *BaseApp) halt() {
 }
 
 func (app *BaseApp) Close() error {
+	if app.snapshotManager != nil {
+		app.snapshotManager.Wait()
+	}
 	if app.db == nil {
 		return nil
 	}
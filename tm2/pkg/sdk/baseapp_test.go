@@ -22,6 +22,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/store"
 	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
 	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/snapshots"
 )
 
 var (
@@ -794,6 +795,60 @@ func TestSimulateTx(t *testing.T) {
 	}
 }
 
+// Query(".app/simulate?profile=1", txBytes) should return the profile of the
+// simulated transaction.
+func TestSimulateTxProfile(t *testing.T) {
+	t.Parallel()
+
+	type profileKey struct{}
+	routerOpt := func(bapp *BaseApp) {
+		bapp.Router().AddRoute(routeMsgCounter, newTestHandler(func(ctx Context, msg Msg) Result {
+			if p, ok := ctx.Value(profileKey{}).(*[]byte); ok {
+				*p = append(*p, "msg;"...)
+			}
+			return Result{}
+		}))
+	}
+
+	app := setupBaseApp(t, routerOpt)
+	app.InitChain(abci.RequestInitChain{ChainID: "test-chain"})
+	app.BeginBlock(abci.RequestBeginBlock{Header: &bft.Header{ChainID: "test-chain", Height: 1}})
+
+	tx := newTxCounter(1, 1, 2)
+	txBytes, err := amino.Marshal(tx)
+	require.NoError(t, err)
+	query := abci.RequestQuery{
+		Path: ".app/simulate?profile=1",
+		Data: txBytes,
+	}
+
+	// The app must support profiling.
+	queryResult := app.Query(query)
+	require.False(t, queryResult.IsOK())
+	_, ok := queryResult.Error.(std.UnknownRequestError)
+	require.True(t, ok)
+
+	app.simulateProfiler = func() (ContextFn, func() ([]byte, error)) {
+		var p []byte
+		ctxFn := func(ctx Context) Context {
+			return ctx.WithValue(profileKey{}, &p)
+		}
+		return ctxFn, func() ([]byte, error) { return p, nil }
+	}
+	queryResult = app.Query(query)
+	require.True(t, queryResult.IsOK(), queryResult.Log)
+	assert.Equal(t, "msg;msg;", string(queryResult.Data))
+	var res Result
+	require.NoError(t, amino.Unmarshal(queryResult.Value, &res))
+	require.True(t, res.IsOK(), res.Log)
+
+	// Without the flag, there is no profile.
+	query.Path = ".app/simulate"
+	queryResult = app.Query(query)
+	require.True(t, queryResult.IsOK(), queryResult.Log)
+	assert.Empty(t, queryResult.Data)
+}
+
 func TestRunInvalidTransaction(t *testing.T) {
 	t.Parallel()
 
@@ -1247,3 +1302,75 @@ func TestGetMaximumBlockGas(t *testing.T) {
 	app.setConsensusParams(&abci.ConsensusParams{Block: &abci.BlockParams{MaxGas: -5000000}})
 	require.Panics(t, func() { app.getMaximumBlockGas() })
 }
+
+func TestSnapshotRestore(t *testing.T) {
+	t.Parallel()
+
+	key, value := []byte("hello"), []byte("goodbye")
+	routerOpt := func(bapp *BaseApp) {
+		bapp.Router().AddRoute(routeMsgCounter, newTestHandler(func(ctx Context, msg Msg) Result {
+			ctx.Store(mainKey).Set(key, value)
+			return Result{}
+		}))
+	}
+	snapshotOpt := SetSnapshotOptions(t.TempDir(), snapshots.Options{Interval: 2, ChunkSize: 64})
+	app := setupBaseApp(t, routerOpt, snapshotOpt)
+	app.InitChain(abci.RequestInitChain{ChainID: "test-chain"})
+
+	for height := int64(1); height <= 2; height++ {
+		app.BeginBlock(abci.RequestBeginBlock{Header: &bft.Header{ChainID: "test-chain", Height: height}})
+		resTx := app.Deliver(newTxCounter(height, height))
+		require.True(t, resTx.IsOK(), fmt.Sprintf("%v", resTx))
+		app.EndBlock(abci.RequestEndBlock{})
+		app.Commit()
+	}
+	app.snapshotManager.Wait()
+
+	resList := app.ListSnapshots(abci.RequestListSnapshots{})
+	require.NoError(t, resList.Error)
+	require.Len(t, resList.Snapshots, 1)
+	snapshot := resList.Snapshots[0]
+	assert.Equal(t, int64(2), snapshot.Height)
+
+	// An application with a state refuses snapshots.
+	resOffer := app.OfferSnapshot(abci.RequestOfferSnapshot{Snapshot: snapshot, AppHash: app.LastCommitID().Hash})
+	require.Error(t, resOffer.Error)
+
+	var restored []store.MultiStore
+	restoreOpt := func(bapp *BaseApp) {
+		bapp.SetRestoreHook(func(ms store.MultiStore) { restored = append(restored, ms) })
+	}
+	// The base store isn't verified by the app hash: it must be trusted.
+	app2 := setupBaseApp(t, routerOpt, SetSnapshotOptions(t.TempDir(), snapshots.Options{}))
+	resOffer = app2.OfferSnapshot(abci.RequestOfferSnapshot{Snapshot: snapshot, AppHash: app.LastCommitID().Hash})
+	require.ErrorContains(t, resOffer.Error, "not verified by the app hash")
+
+	app2 = setupBaseApp(t, routerOpt, restoreOpt, SetSnapshotOptions(t.TempDir(), snapshots.Options{TrustUnverifiedStores: true}))
+	resOffer = app2.OfferSnapshot(abci.RequestOfferSnapshot{Snapshot: snapshot, AppHash: app.LastCommitID().Hash})
+	require.NoError(t, resOffer.Error)
+
+	for i := range snapshot.Chunks {
+		resLoad := app.LoadSnapshotChunk(abci.RequestLoadSnapshotChunk{Height: 2, Format: snapshot.Format, Chunk: i})
+		require.NoError(t, resLoad.Error)
+
+		// A corrupted chunk is fetched again, and its sender rejected.
+		resApply := app2.ApplySnapshotChunk(abci.RequestApplySnapshotChunk{Index: i, Chunk: []byte("bad"), Sender: "peer"})
+		require.NoError(t, resApply.Error)
+		assert.Equal(t, []uint32{i}, resApply.RefetchChunks)
+		assert.Equal(t, []string{"peer"}, resApply.RejectSenders)
+
+		resApply = app2.ApplySnapshotChunk(abci.RequestApplySnapshotChunk{Index: i, Chunk: resLoad.Chunk})
+		require.NoError(t, resApply.Error)
+	}
+
+	assert.Len(t, restored, 1)
+	assert.Equal(t, app.LastCommitID(), app2.LastCommitID())
+	res := app2.Query(abci.RequestQuery{Path: ".store/main/key", Data: key})
+	assert.Equal(t, value, res.Value)
+
+	// The restored application continues the chain.
+	app2.BeginBlock(abci.RequestBeginBlock{Header: &bft.Header{ChainID: "test-chain", Height: 3}})
+	app2.EndBlock(abci.RequestEndBlock{})
+	app2.Commit()
+	assert.Equal(t, int64(3), app2.LastBlockHeight())
+}
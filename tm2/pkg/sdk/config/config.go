@@ -14,6 +14,8 @@ import (
 var (
 	ErrInvalidMinGasPrices  = errors.New("invalid min gas prices")
 	ErrInvalidPruneStrategy = errors.New("invalid prune strategy")
+	ErrInvalidSnapshots     = errors.New("invalid state sync snapshot options")
+	ErrInvalidKeepHistory   = errors.New("invalid history options")
 )
 
 // AppConfig defines the configuration options for the Application
@@ -22,17 +24,52 @@ type AppConfig struct {
 	MinGasPrices string `json:"min_gas_prices" toml:"min_gas_prices" comment:"Lowest gas prices accepted by a validator"`
 
 	// The enforced state pruning stategy for the app
-	PruneStrategy types.PruneStrategy `json:"prune_strategy" toml:"prune_strategy" comment:"State pruning strategy [everything, nothing, syncable]"`
+	PruneStrategy types.PruneStrategy `json:"prune_strategy" toml:"prune_strategy" comment:"State pruning strategy [everything, nothing, syncable, custom]"`
+
+	// The number of recent states kept by the custom prune strategy
+	PruneKeepRecent int64 `json:"prune_keep_recent" toml:"prune_keep_recent" comment:"Number of recent states to keep, with the custom prune strategy"`
+
+	// The interval of the states always kept by the custom prune strategy
+	PruneKeepEvery int64 `json:"prune_keep_every" toml:"prune_keep_every" comment:"Interval between the older states to keep, with the custom prune strategy (0 keeps none, 1 keeps all)"`
+
+	// The number of blocks between the state sync snapshots of the app state
+	SnapshotInterval int64 `json:"snapshot_interval" toml:"snapshot_interval" comment:"Number of blocks between state sync snapshots, served to the syncing peers (0 disables snapshots)"`
+
+	// The number of recent state sync snapshots to keep
+	SnapshotKeepRecent int `json:"snapshot_keep_recent" toml:"snapshot_keep_recent" comment:"Number of recent state sync snapshots to keep (0 keeps all)"`
+
+	// Whether the state sync snapshots of stores not verified by the app hash are restored
+	SnapshotTrustUnverified bool `json:"snapshot_trust_unverified" toml:"snapshot_trust_unverified" comment:"Restore the state sync snapshots of the app state which is not merkleized (e.g. of the VM),\n which can't be verified by the app hash and is trusted from the peer serving the snapshot"`
+
+	// Whether the history of the non-merkleized app state is kept, to serve queries at past heights
+	KeepHistory bool `json:"keep_history" toml:"keep_history" comment:"Keep the history of the app state which is not merkleized, to serve queries at past heights (e.g. of the VM)"`
+
+	// The number of recent heights of the history to keep
+	HistoryKeepRecent int64 `json:"history_keep_recent" toml:"history_keep_recent" comment:"Number of recent heights of the history to keep, pruned in the background (0 keeps all)"`
 }
 
 // DefaultAppConfig returns a default configuration for the application
 func DefaultAppConfig() *AppConfig {
 	return &AppConfig{
-		MinGasPrices:  "",
-		PruneStrategy: types.PruneSyncableStrategy,
+		MinGasPrices:            "",
+		PruneStrategy:           types.PruneSyncableStrategy,
+		SnapshotInterval:        0,
+		SnapshotKeepRecent:      2,
+		SnapshotTrustUnverified: false,
+		KeepHistory:             false,
+		HistoryKeepRecent:       0,
 	}
 }
 
+// PruningOptions returns the pruning options of the prune strategy, or the
+// custom options of the config for the custom strategy.
+func (cfg *AppConfig) PruningOptions() types.PruningOptions {
+	if cfg.PruneStrategy == types.PruneCustomStrategy {
+		return types.NewPruningOptions(cfg.PruneKeepRecent, cfg.PruneKeepEvery)
+	}
+	return cfg.PruneStrategy.Options()
+}
+
 // ValidateBasic performs basic validation, checking format and param bounds, etc., and
 // returns an error if any check fails.
 func (cfg *AppConfig) ValidateBasic() error {
@@ -44,11 +81,36 @@ func (cfg *AppConfig) ValidateBasic() error {
 	}
 
 	// Make sure the prune strategy is recognized
-	if cfg.PruneStrategy != types.PruneEverythingStrategy &&
-		cfg.PruneStrategy != types.PruneNothingStrategy &&
-		cfg.PruneStrategy != types.PruneSyncableStrategy {
+	switch cfg.PruneStrategy {
+	case types.PruneEverythingStrategy, types.PruneNothingStrategy, types.PruneSyncableStrategy:
+	case types.PruneCustomStrategy:
+		if cfg.PruneKeepRecent < 0 || cfg.PruneKeepEvery < 0 {
+			return fmt.Errorf("%w: negative custom pruning options", ErrInvalidPruneStrategy)
+		}
+	default:
 		return fmt.Errorf("%w: %q", ErrInvalidPruneStrategy, cfg.PruneStrategy)
 	}
+	pruneEverything := cfg.PruningOptions() == types.PruneEverything
+
+	// Make sure the snapshots can be taken: they are exported in the
+	// background, from a state that must not be pruned meanwhile
+	switch {
+	case cfg.SnapshotInterval < 0:
+		return fmt.Errorf("%w: negative snapshot interval", ErrInvalidSnapshots)
+	case cfg.SnapshotKeepRecent < 0:
+		return fmt.Errorf("%w: negative number of snapshots to keep", ErrInvalidSnapshots)
+	case cfg.SnapshotInterval > 0 && pruneEverything:
+		return fmt.Errorf("%w: snapshots require a prune strategy keeping past states", ErrInvalidSnapshots)
+	}
+
+	// The history is only useful along with the past states of the
+	// merkleized stores
+	switch {
+	case cfg.KeepHistory && pruneEverything:
+		return fmt.Errorf("%w: keeping history requires a prune strategy keeping past states", ErrInvalidKeepHistory)
+	case cfg.HistoryKeepRecent < 0:
+		return fmt.Errorf("%w: negative number of history heights to keep", ErrInvalidKeepHistory)
+	}
 
 	return nil
 }
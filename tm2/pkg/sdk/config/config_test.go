@@ -65,6 +65,94 @@ func TestConfig_ValidateBasic(t *testing.T) {
 		assert.NoError(t, cfg.ValidateBasic())
 	})
 
+	t.Run("invalid custom prune strategy", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultAppConfig()
+		cfg.PruneStrategy = types.PruneCustomStrategy
+		cfg.PruneKeepRecent = -1
+		assert.ErrorIs(t, cfg.ValidateBasic(), ErrInvalidPruneStrategy)
+
+		cfg = DefaultAppConfig()
+		cfg.PruneStrategy = types.PruneCustomStrategy
+		cfg.PruneKeepEvery = -1
+		assert.ErrorIs(t, cfg.ValidateBasic(), ErrInvalidPruneStrategy)
+
+		// Pruning all the past states, like the everything strategy.
+		cfg = DefaultAppConfig()
+		cfg.PruneStrategy = types.PruneCustomStrategy
+		cfg.SnapshotInterval = 100
+		assert.ErrorIs(t, cfg.ValidateBasic(), ErrInvalidSnapshots)
+	})
+
+	t.Run("valid custom prune strategy", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultAppConfig()
+		cfg.PruneStrategy = types.PruneCustomStrategy
+		cfg.PruneKeepRecent = 1000
+		cfg.PruneKeepEvery = 100
+
+		assert.NoError(t, cfg.ValidateBasic())
+		assert.Equal(t, types.NewPruningOptions(1000, 100), cfg.PruningOptions())
+	})
+
+	t.Run("invalid snapshot options", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultAppConfig()
+		cfg.SnapshotInterval = -1
+		assert.ErrorIs(t, cfg.ValidateBasic(), ErrInvalidSnapshots)
+
+		cfg = DefaultAppConfig()
+		cfg.SnapshotKeepRecent = -1
+		assert.ErrorIs(t, cfg.ValidateBasic(), ErrInvalidSnapshots)
+
+		// Pruned states can't be exported in the background.
+		cfg = DefaultAppConfig()
+		cfg.SnapshotInterval = 100
+		cfg.PruneStrategy = types.PruneEverythingStrategy
+		assert.ErrorIs(t, cfg.ValidateBasic(), ErrInvalidSnapshots)
+	})
+
+	t.Run("valid snapshot options", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultAppConfig()
+		cfg.SnapshotInterval = 100
+		cfg.SnapshotKeepRecent = 0
+
+		assert.NoError(t, cfg.ValidateBasic())
+	})
+
+	t.Run("invalid keep history", func(t *testing.T) {
+		t.Parallel()
+
+		// The past states of the merkleized stores are pruned.
+		cfg := DefaultAppConfig()
+		cfg.KeepHistory = true
+		cfg.PruneStrategy = types.PruneEverythingStrategy
+		assert.ErrorIs(t, cfg.ValidateBasic(), ErrInvalidKeepHistory)
+	})
+
+	t.Run("invalid history keep recent", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultAppConfig()
+		cfg.KeepHistory = true
+		cfg.HistoryKeepRecent = -1
+		assert.ErrorIs(t, cfg.ValidateBasic(), ErrInvalidKeepHistory)
+	})
+
+	t.Run("valid keep history", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultAppConfig()
+		cfg.KeepHistory = true
+
+		assert.NoError(t, cfg.ValidateBasic())
+	})
+
 	t.Run("valid default config", func(t *testing.T) {
 		t.Parallel()
 
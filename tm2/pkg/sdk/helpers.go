@@ -15,9 +15,17 @@ func (app *BaseApp) Check(tx Tx) (result Result) {
 	return app.runTx(ctx, tx)
 }
 
-func (app *BaseApp) Simulate(txBytes []byte, tx Tx) (result Result) {
+func (app *BaseApp) Simulate(txBytes []byte, tx Tx, ctxFns ...ContextFn) (result Result) {
 	ctx := app.getContextForTx(RunTxModeSimulate, txBytes)
 
+	for _, ctxFn := range ctxFns {
+		if ctxFn == nil {
+			continue
+		}
+
+		ctx = ctxFn(ctx)
+	}
+
 	return app.runTx(ctx, tx)
 }
 
@@ -37,7 +45,8 @@ func (app *BaseApp) Deliver(tx Tx, ctxFns ...ContextFn) (result Result) {
 
 // ContextFn is the custom execution context builder.
 // It can be used to add custom metadata when replaying transactions
-// during InitChainer or in the context of a unit test.
+// during InitChainer, when simulating transactions, or in the context of a
+// unit test.
 type ContextFn func(ctx Context) Context
 
 // Context with current {check, deliver}State of the app
@@ -5,6 +5,7 @@ import (
 
 	dbm "github.com/gnolang/gno/tm2/pkg/db"
 	"github.com/gnolang/gno/tm2/pkg/store"
+	"github.com/gnolang/gno/tm2/pkg/store/snapshots"
 )
 
 // File for storing in-package BaseApp optional functions,
@@ -29,6 +30,22 @@ func SetMinGasPrices(gasPricesStr string) func(*BaseApp) {
 	return func(bap *BaseApp) { bap.setMinGasPrices(gasPrices) }
 }
 
+// SetSnapshotOptions returns an option that saves the state sync snapshots of
+// the multistore into dir, and allows restoring snapshots from peers.
+func SetSnapshotOptions(dir string, opts snapshots.Options) func(*BaseApp) {
+	return func(bap *BaseApp) {
+		target, ok := bap.cms.(store.Snapshotter)
+		if !ok {
+			panic("multistore doesn't support state sync snapshots")
+		}
+		manager, err := snapshots.NewManager(dir, target, opts, bap.logger.With("module", "snapshots"))
+		if err != nil {
+			panic(fmt.Sprintf("invalid state sync snapshot options: %v", err))
+		}
+		bap.snapshotManager = manager
+	}
+}
+
 func (app *BaseApp) SetName(name string) {
 	if app.sealed {
 		panic("SetName() on sealed BaseApp")
@@ -99,3 +116,17 @@ func (app *BaseApp) SetEndTxHook(endTx EndTxHook) {
 	}
 	app.endTxHook = endTx
 }
+
+func (app *BaseApp) SetRestoreHook(restore RestoreHook) {
+	if app.sealed {
+		panic("SetRestoreHook() on sealed BaseApp")
+	}
+	app.restoreHook = restore
+}
+
+func (app *BaseApp) SetSimulateProfiler(profiler SimulateProfiler) {
+	if app.sealed {
+		panic("SetSimulateProfiler() on sealed BaseApp")
+	}
+	app.simulateProfiler = profiler
+}
@@ -25,6 +25,11 @@ type Result struct {
 	abci.ResponseBase
 	GasWanted int64
 	GasUsed   int64
+
+	// Mempool fields of ResponseCheckTx, set by the AnteHandler.
+	Priority int64
+	Sender   string
+	Sequence uint64
 }
 
 // AnteHandler authenticates transactions, before their internal messages are handled.
@@ -10,6 +10,7 @@ import (
 	_ "github.com/gnolang/gno/tm2/pkg/crypto/mock"
 	_ "github.com/gnolang/gno/tm2/pkg/crypto/multisig"
 	_ "github.com/gnolang/gno/tm2/pkg/crypto/secp256k1"
+	_ "github.com/gnolang/gno/tm2/pkg/crypto/secp256r1"
 )
 
 // Account is an interface used to store coins at a given address within state.
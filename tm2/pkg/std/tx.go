@@ -50,6 +50,11 @@ func (tx Tx) ValidateBasic() error {
 	if !tx.Fee.GasFee.IsValid() {
 		return ErrInsufficientFee(fmt.Sprintf("invalid fee %s amount provided", tx.Fee.GasFee))
 	}
+	if tx.Fee.Granter != "" {
+		if _, err := crypto.AddressFromBech32(tx.Fee.Granter); err != nil {
+			return ErrInvalidAddress(fmt.Sprintf("invalid fee granter %q", tx.Fee.Granter))
+		}
+	}
 	if len(stdSigs) == 0 {
 		return ErrNoSignatures("no signers")
 	}
@@ -123,9 +128,13 @@ func (tx Tx) GetSignBytes(chainID string, accountNumber uint64, sequence uint64)
 // Fee includes the amount of coins paid in fees and the maximum
 // gas to be used by the transaction. The ratio yields an effective "gasprice",
 // which must be above some miminum to be accepted into the mempool.
+//
+// The fee is paid by the first signer, or by the Granter if set, out of the
+// fee allowance it granted to the first signer.
 type Fee struct {
-	GasWanted int64 `json:"gas_wanted" yaml:"gas_wanted"`
-	GasFee    Coin  `json:"gas_fee" yaml:"gas_fee"`
+	GasWanted int64  `json:"gas_wanted" yaml:"gas_wanted"`
+	GasFee    Coin   `json:"gas_fee" yaml:"gas_fee"`
+	Granter   string `json:"granter,omitempty" yaml:"granter,omitempty"` // bech32 address
 }
 
 // NewFee returns a new instance of Fee
@@ -0,0 +1,410 @@
+package dbadapter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"sync/atomic"
+
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/log"
+
+	"github.com/gnolang/gno/tm2/pkg/store/cache"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+// HistoryStoreConstructor returns a CommitStoreConstructor of stores which
+// keep, in historyDB, the values overwritten at each version, so that the
+// state of a past version can be read from the immutable stores loaded at
+// that version (e.g. with MultiImmutableCacheWrapWithVersion).
+//
+// The history is not part of the commit ID of the store, which is always
+// zero like the one of [Store]: nodes may or may not keep it, and may start
+// to keep it at any version.
+//
+// If keepRecent is positive, the history older than the keepRecent last
+// versions is pruned in the background (see [HistoryStore.PruneHistory]),
+// logging the errors to logger.
+func HistoryStoreConstructor(historyDB dbm.DB, keepRecent int64, logger *slog.Logger) types.CommitStoreConstructor {
+	if logger == nil {
+		logger = log.NewNoopLogger()
+	}
+
+	// The stores of the constructor (i.e. the stores loaded at the past
+	// versions, and the one committing the history) share the lock.
+	lock := &historyLock{}
+
+	return func(db dbm.DB, opts types.StoreOptions) types.CommitStore {
+		return &HistoryStore{
+			Store:      Store{DB: db},
+			history:    historyDB,
+			opts:       opts,
+			lock:       lock,
+			logger:     logger,
+			keepRecent: keepRecent,
+			written:    make(map[string]struct{}),
+		}
+	}
+}
+
+// ErrHistoryIterator is the error of the iterators over a past version of a
+// [HistoryStore], which is not supported.
+var ErrHistoryIterator = errors.New("iterating over a past version of a history store is not supported")
+
+// historyLock synchronizes the reads of the history at past versions
+// with its pruning.
+type historyLock struct {
+	mtx sync.RWMutex
+	// earliest is the first version of the history since it was
+	// last pruned, or 0.
+	earliest int64
+}
+
+// HistoryStore is a [Store] keeping the history of its values.
+//
+// For each key written in version v, the value of the key at version v-1 is
+// saved in the history DB at (key, v), before the key is written. The value
+// of a key at a past version v is so the one saved at the lowest version
+// greater than v, or its current value if the key was not written since v.
+type HistoryStore struct {
+	Store
+	history dbm.DB
+	opts    types.StoreOptions
+	lock    *historyLock
+	logger  *slog.Logger
+
+	// version is the last committed version, or, if the store is
+	// immutable, the version which is read.
+	version int64
+	// written contains the keys written since the last commit.
+	written map[string]struct{}
+
+	// keepRecent is the number of recent versions of the history to keep,
+	// or 0 to keep all.
+	keepRecent int64
+	// pruning is set while the history is pruned in the background.
+	pruning atomic.Bool
+}
+
+var (
+	_ types.CommitStore = (*HistoryStore)(nil)
+	_ types.Snapshotter = (*HistoryStore)(nil)
+)
+
+const (
+	historyEarliestKey = "e"        // first version of the history
+	historyValuePrefix = "v/"       // v/<len(key)><key><version>
+	historyValueAbsent = byte(0x00) // the key was not set
+	historyValueSet    = byte(0x01) // followed by the value
+	historyValueEnd    = "v0"       // end of the historyValuePrefix domain
+	historyVersionMax  = math.MaxInt64
+
+	// the history is pruned every historyPruneInterval versions, in
+	// batches of historyPruneBatchSize keys.
+	historyPruneInterval  = 100
+	historyPruneBatchSize = 10000
+)
+
+// Get returns nil iff key doesn't exist. Panics on nil key.
+func (hs *HistoryStore) Get(key []byte) []byte {
+	// NOTE: the current value is read before the history, which is
+	// written before the value is overwritten.
+	value := hs.Store.Get(key)
+	if !hs.opts.Immutable {
+		return value
+	}
+	if old, ok := hs.getHistory(key, hs.version); ok {
+		return old
+	}
+	return value
+}
+
+// Has checks if a key exists. Panics on nil key.
+func (hs *HistoryStore) Has(key []byte) bool {
+	if !hs.opts.Immutable {
+		return hs.Store.Has(key)
+	}
+	return hs.Get(key) != nil
+}
+
+// Set sets the key. Panics on nil key or value.
+func (hs *HistoryStore) Set(key, value []byte) {
+	hs.saveHistory(key)
+	hs.Store.Set(key, value)
+}
+
+// Delete deletes the key. Panics on nil key.
+func (hs *HistoryStore) Delete(key []byte) {
+	hs.saveHistory(key)
+	hs.Store.Delete(key)
+}
+
+// Iterator over a domain of keys in ascending order.
+// If the store is loaded at a past version, which is not supported, the
+// iterator is invalid and its Error is ErrHistoryIterator.
+func (hs *HistoryStore) Iterator(start, end []byte) types.Iterator {
+	if hs.opts.Immutable {
+		return errIterator{start: start, end: end, err: ErrHistoryIterator}
+	}
+	return hs.Store.Iterator(start, end)
+}
+
+// Iterator over a domain of keys in descending order.
+// If the store is loaded at a past version, which is not supported, the
+// iterator is invalid and its Error is ErrHistoryIterator.
+func (hs *HistoryStore) ReverseIterator(start, end []byte) types.Iterator {
+	if hs.opts.Immutable {
+		return errIterator{start: start, end: end, err: ErrHistoryIterator}
+	}
+	return hs.Store.ReverseIterator(start, end)
+}
+
+// CacheWrap cache wraps the underlying store.
+func (hs *HistoryStore) CacheWrap() types.Store {
+	return cache.New(hs)
+}
+
+// Implements Committer/CommitStore.
+func (hs *HistoryStore) Commit() types.CommitID {
+	hs.version++
+	clear(hs.written)
+	id := hs.Store.Commit()
+	hs.pruneIfNeeded()
+	return id
+}
+
+// Implements Committer/CommitStore.
+func (hs *HistoryStore) GetStoreOptions() types.StoreOptions {
+	return hs.opts
+}
+
+// Implements Committer/CommitStore.
+func (hs *HistoryStore) SetStoreOptions(opts types.StoreOptions) {
+	hs.opts = opts
+}
+
+// Implements Committer/CommitStore.
+// Returns an error if the store is immutable and ver is before the first
+// version of the history.
+func (hs *HistoryStore) LoadVersion(ver int64) error {
+	hs.version = ver
+	bz, err := hs.history.Get([]byte(historyEarliestKey))
+	if err != nil {
+		return err
+	}
+	if bz == nil {
+		if hs.opts.Immutable {
+			return fmt.Errorf("no history of the store at version %d", ver)
+		}
+		// the history starts at the loaded version.
+		return hs.history.SetSync([]byte(historyEarliestKey), encodeHistoryVersion(ver))
+	}
+	if earliest := decodeHistoryVersion(bz); hs.opts.Immutable && ver < earliest {
+		return fmt.Errorf("version %d is not available, the history of the store starts at version %d", ver, earliest)
+	}
+	return nil
+}
+
+// Implements types.Snapshotter.
+// The history of the store restarts at the imported version.
+func (hs *HistoryStore) Import(version int64, items types.SnapshotIterator) error {
+	if err := hs.Store.Import(version, items); err != nil {
+		return err
+	}
+	return hs.history.SetSync([]byte(historyEarliestKey), encodeHistoryVersion(version))
+}
+
+// PruneHistory deletes the history of the versions before version to, which
+// becomes the first version of the history.
+//
+// The first version is moved before the history is deleted, so that the
+// pruned versions can't be loaded anymore. It is moved while no store reads
+// the history, and the stores already loaded at a pruned version panic when
+// read afterwards, instead of reading a partially deleted history. The
+// history saved at versions up to to is only read at the versions before to,
+// so it can then be deleted while the other versions are read.
+func (hs *HistoryStore) PruneHistory(to int64) error {
+	if pruned, err := hs.setEarliest(to); err != nil || !pruned {
+		return err
+	}
+
+	// The value saved at version v is the one of version v-1, only read at
+	// the versions before v.
+	start := []byte(historyValuePrefix)
+	for start != nil {
+		keys, next, err := hs.prunableKeys(start, to)
+		if err != nil {
+			return err
+		}
+		if err := hs.deleteHistory(keys); err != nil {
+			return err
+		}
+		start = next
+	}
+	return nil
+}
+
+// Moves the first version of the history to version to, if it is before.
+// Returns whether it was moved.
+func (hs *HistoryStore) setEarliest(to int64) (bool, error) {
+	hs.lock.mtx.Lock()
+	defer hs.lock.mtx.Unlock()
+
+	bz, err := hs.history.Get([]byte(historyEarliestKey))
+	if err != nil {
+		return false, err
+	}
+	if bz != nil && decodeHistoryVersion(bz) >= to {
+		return false, nil
+	}
+	if err := hs.history.SetSync([]byte(historyEarliestKey), encodeHistoryVersion(to)); err != nil {
+		return false, err
+	}
+	hs.lock.earliest = to
+	return true, nil
+}
+
+// Returns the keys of the values saved up to version to, among the
+// historyPruneBatchSize keys from start, and the key to start the next batch
+// from, if any. The keys are not deleted while iterating, which may not be
+// supported by the DB.
+func (hs *HistoryStore) prunableKeys(start []byte, to int64) (keys [][]byte, next []byte, err error) {
+	it, err := hs.history.Iterator(start, []byte(historyValueEnd))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer it.Close()
+
+	for n := 0; it.Valid(); it.Next() {
+		key := it.Key()
+		if n == historyPruneBatchSize {
+			return keys, bytes.Clone(key), nil
+		}
+		n++
+		if decodeHistoryVersion(key[len(key)-8:]) <= to {
+			keys = append(keys, bytes.Clone(key))
+		}
+	}
+	return keys, nil, it.Error()
+}
+
+func (hs *HistoryStore) deleteHistory(keys [][]byte) error {
+	batch := hs.history.NewBatch()
+	defer batch.Close()
+
+	for _, key := range keys {
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+	}
+	return batch.WriteSync()
+}
+
+// Prunes the history older than the keepRecent last versions in the
+// background, every historyPruneInterval versions, if it is not already
+// being pruned. Errors are logged, and pruning is retried at the next
+// interval: the history of the versions already pruned can't be read
+// anyway, and is deleted along with the next versions.
+func (hs *HistoryStore) pruneIfNeeded() {
+	if hs.keepRecent <= 0 || hs.version%historyPruneInterval != 0 {
+		return
+	}
+	to := hs.version - hs.keepRecent
+	if to <= 0 || !hs.pruning.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer hs.pruning.Store(false)
+		if err := hs.PruneHistory(to); err != nil {
+			hs.logger.Error("Failed to prune the store history, retrying at the next interval", "version", to, "err", err)
+		}
+	}()
+}
+
+// Saves the value of key at the last committed version, if it was not yet
+// saved while writing the next version.
+func (hs *HistoryStore) saveHistory(key []byte) {
+	if _, ok := hs.written[string(key)]; ok {
+		return
+	}
+	hkey := historyValueKey(key, hs.version+1)
+	// a replayed version must keep the values saved the first time.
+	has, err := hs.history.Has(hkey)
+	if err != nil {
+		panic(err)
+	}
+	if !has {
+		old := []byte{historyValueAbsent}
+		if value := hs.Store.Get(key); value != nil {
+			old = append([]byte{historyValueSet}, value...)
+		}
+		if err := hs.history.Set(hkey, old); err != nil {
+			panic(err)
+		}
+	}
+	hs.written[string(key)] = struct{}{}
+}
+
+// Returns the value of key at version ver, if the key was written after ver.
+// Panics if the history of version ver was pruned.
+func (hs *HistoryStore) getHistory(key []byte, ver int64) ([]byte, bool) {
+	hs.lock.mtx.RLock()
+	defer hs.lock.mtx.RUnlock()
+
+	if ver < hs.lock.earliest {
+		panic(fmt.Sprintf("the history of the store at version %d was pruned while being read", ver))
+	}
+
+	start := historyValueKey(key, ver+1)
+	end := historyValueKey(key, historyVersionMax)
+	it, err := hs.history.Iterator(start, end)
+	if err != nil {
+		panic(err)
+	}
+	defer it.Close()
+	if !it.Valid() {
+		return nil, false
+	}
+	old := it.Value()
+	if old[0] == historyValueAbsent {
+		return nil, true
+	}
+	return old[1:], true
+}
+
+// Returns the key of the value of key saved at version ver. The length of key
+// is prefixed so that the keys of a key are not mixed with the ones of
+// another key.
+func historyValueKey(key []byte, ver int64) []byte {
+	hkey := make([]byte, 0, len(historyValuePrefix)+binary.MaxVarintLen64+len(key)+8)
+	hkey = append(hkey, historyValuePrefix...)
+	hkey = binary.AppendUvarint(hkey, uint64(len(key)))
+	hkey = append(hkey, key...)
+	return append(hkey, encodeHistoryVersion(ver)...)
+}
+
+func encodeHistoryVersion(ver int64) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(ver))
+}
+
+func decodeHistoryVersion(bz []byte) int64 {
+	return int64(binary.BigEndian.Uint64(bz))
+}
+
+// errIterator is an invalid iterator, whose Error is err.
+type errIterator struct {
+	start, end []byte
+	err        error
+}
+
+func (it errIterator) Domain() ([]byte, []byte) { return it.start, it.end }
+func (it errIterator) Valid() bool              { return false }
+func (it errIterator) Next()                    {}
+func (it errIterator) Key() []byte              { return nil }
+func (it errIterator) Value() []byte            { return nil }
+func (it errIterator) Error() error             { return it.err }
+func (it errIterator) Close() error             { return nil }
@@ -0,0 +1,175 @@
+package dbadapter_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+func TestHistoryStore(t *testing.T) {
+	db, historyDB := memdb.NewMemDB(), memdb.NewMemDB()
+	cons := dbadapter.HistoryStoreConstructor(historyDB, 0, log.NewNoopLogger())
+
+	store := cons(db, types.StoreOptions{})
+	require.NoError(t, store.LoadVersion(0))
+
+	// version 1
+	store.Set([]byte("a"), []byte("a1"))
+	store.Set([]byte("ab"), []byte("ab1"))
+	store.Commit()
+	// version 2
+	store.Set([]byte("a"), []byte("a2"))
+	store.Set([]byte("a"), []byte("a2bis"))
+	store.Delete([]byte("ab"))
+	store.Commit()
+	// version 3, written through a cache.
+	cached := store.CacheWrap()
+	cached.Set([]byte("ab"), []byte("ab3"))
+	cached.Set([]byte("b"), []byte("b3"))
+	cached.Write()
+	store.Commit()
+
+	// The latest values are not changed.
+	assert.Equal(t, []byte("a2bis"), store.Get([]byte("a")))
+	assert.Equal(t, []byte("ab3"), store.Get([]byte("ab")))
+
+	expected := map[int64]map[string]string{
+		0: {},
+		1: {"a": "a1", "ab": "ab1"},
+		2: {"a": "a2bis"},
+		3: {"a": "a2bis", "ab": "ab3", "b": "b3"},
+	}
+	for ver, values := range expected {
+		past := cons(dbm.NewImmutableDB(db), types.StoreOptions{Immutable: true})
+		require.NoError(t, past.LoadVersion(ver))
+		for _, key := range []string{"a", "ab", "b"} {
+			value, ok := values[key]
+			if !ok {
+				assert.Nil(t, past.Get([]byte(key)), "version %d, key %s", ver, key)
+				assert.False(t, past.Has([]byte(key)), "version %d, key %s", ver, key)
+				continue
+			}
+			assert.Equal(t, value, string(past.Get([]byte(key))), "version %d, key %s", ver, key)
+			assert.True(t, past.Has([]byte(key)), "version %d, key %s", ver, key)
+		}
+		it := past.Iterator(nil, nil)
+		assert.False(t, it.Valid())
+		assert.ErrorIs(t, it.Error(), dbadapter.ErrHistoryIterator)
+		it = past.ReverseIterator(nil, nil)
+		assert.False(t, it.Valid())
+		assert.ErrorIs(t, it.Error(), dbadapter.ErrHistoryIterator)
+	}
+}
+
+func TestHistoryStore_Earliest(t *testing.T) {
+	db, historyDB := memdb.NewMemDB(), memdb.NewMemDB()
+	cons := dbadapter.HistoryStoreConstructor(historyDB, 0, log.NewNoopLogger())
+
+	// The node has no history yet.
+	past := cons(db, types.StoreOptions{Immutable: true})
+	require.Error(t, past.LoadVersion(2))
+
+	// The history starts at the loaded version.
+	store := cons(db, types.StoreOptions{})
+	require.NoError(t, store.LoadVersion(2))
+	store.Set([]byte("a"), []byte("a3"))
+	store.Commit()
+
+	past = cons(db, types.StoreOptions{Immutable: true})
+	require.Error(t, past.LoadVersion(1))
+	require.NoError(t, past.LoadVersion(2))
+	assert.Nil(t, past.Get([]byte("a")))
+
+	// Replaying a version keeps the values saved the first time.
+	store = cons(db, types.StoreOptions{})
+	require.NoError(t, store.LoadVersion(2))
+	store.Set([]byte("a"), []byte("a3"))
+	store.Commit()
+
+	past = cons(db, types.StoreOptions{Immutable: true})
+	require.NoError(t, past.LoadVersion(2))
+	assert.Nil(t, past.Get([]byte("a")))
+}
+
+func TestHistoryStore_PruneHistory(t *testing.T) {
+	db, historyDB := memdb.NewMemDB(), memdb.NewMemDB()
+	cons := dbadapter.HistoryStoreConstructor(historyDB, 0, log.NewNoopLogger())
+
+	store := cons(db, types.StoreOptions{})
+	require.NoError(t, store.LoadVersion(0))
+	for ver := 1; ver <= 5; ver++ {
+		store.Set([]byte("a"), fmt.Appendf(nil, "a%d", ver))
+		if ver == 2 {
+			store.Set([]byte("b"), []byte("b2"))
+		}
+		store.Commit()
+	}
+
+	require.NoError(t, store.(*dbadapter.HistoryStore).PruneHistory(3))
+
+	past := cons(db, types.StoreOptions{Immutable: true})
+	require.Error(t, past.LoadVersion(2))
+	for ver := 3; ver <= 5; ver++ {
+		past := cons(dbm.NewImmutableDB(db), types.StoreOptions{Immutable: true})
+		require.NoError(t, past.LoadVersion(int64(ver)))
+		assert.Equal(t, fmt.Sprintf("a%d", ver), string(past.Get([]byte("a"))), "version %d", ver)
+		assert.Equal(t, "b2", string(past.Get([]byte("b"))), "version %d", ver)
+	}
+
+	// Only the values of the versions from 3 are kept: a3 and a4, saved
+	// when writing the next versions.
+	it, err := historyDB.Iterator(nil, nil)
+	require.NoError(t, err)
+	defer it.Close()
+	var values []string
+	for ; it.Valid(); it.Next() {
+		if it.Key()[0] == 'v' {
+			values = append(values, string(it.Value()[1:]))
+		}
+	}
+	assert.ElementsMatch(t, []string{"a3", "a4"}, values)
+
+	// Pruning an older version is a no-op.
+	require.NoError(t, store.(*dbadapter.HistoryStore).PruneHistory(1))
+	require.Error(t, past.LoadVersion(2))
+
+	// A store loaded at a version pruned afterwards can't be read anymore.
+	past = cons(dbm.NewImmutableDB(db), types.StoreOptions{Immutable: true})
+	require.NoError(t, past.LoadVersion(3))
+	assert.Equal(t, "a3", string(past.Get([]byte("a"))))
+	require.NoError(t, store.(*dbadapter.HistoryStore).PruneHistory(4))
+	assert.PanicsWithValue(t, "the history of the store at version 3 was pruned while being read", func() {
+		past.Get([]byte("a"))
+	})
+}
+
+func TestHistoryStore_KeepRecent(t *testing.T) {
+	db, historyDB := memdb.NewMemDB(), memdb.NewMemDB()
+	cons := dbadapter.HistoryStoreConstructor(historyDB, 10, log.NewNoopLogger())
+
+	store := cons(db, types.StoreOptions{})
+	require.NoError(t, store.LoadVersion(0))
+	for ver := 1; ver <= 100; ver++ {
+		store.Set([]byte("a"), fmt.Appendf(nil, "a%d", ver))
+		store.Commit()
+	}
+
+	// The history is pruned in the background at version 100.
+	require.Eventually(t, func() bool {
+		past := cons(db, types.StoreOptions{Immutable: true})
+		return past.LoadVersion(89) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	past := cons(db, types.StoreOptions{Immutable: true})
+	require.NoError(t, past.LoadVersion(90))
+	assert.Equal(t, "a90", string(past.Get([]byte("a"))))
+}
@@ -0,0 +1,84 @@
+package dbadapter
+
+import (
+	"errors"
+	"io"
+
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+// maxBatchSize is the number of entries written at once by Import.
+const maxBatchSize = 10000
+
+var _ types.Snapshotter = Store{}
+
+// Export implements types.Snapshotter. As the store isn't versioned, only the
+// current state can be exported, whatever the version. It is read from an
+// iterator opened by Export, which sees a consistent state with the databases
+// whose iterators read a snapshot of the database (e.g. pebbledb, goleveldb).
+func (dsa Store) Export(version int64) (types.SnapshotIterator, error) {
+	it, err := dsa.DB.Iterator(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshotExporter{it: it}, nil
+}
+
+// Implements types.Snapshotter. As the store isn't merkleized, the imported
+// items can't be verified by the app hash: they are trusted from the peer
+// serving the snapshot.
+func (dsa Store) Import(version int64, items types.SnapshotIterator) error {
+	batch := dsa.DB.NewBatch()
+	defer func() { batch.Close() }()
+
+	size := 0
+	for {
+		item, err := items.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := batch.Set(item.Key, item.Value); err != nil {
+			return err
+		}
+		size++
+		if size >= maxBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Close()
+			batch = dsa.DB.NewBatch()
+			size = 0
+		}
+	}
+	return batch.WriteSync()
+}
+
+type snapshotExporter struct {
+	it      dbm.Iterator
+	started bool
+}
+
+func (e *snapshotExporter) Next() (types.SnapshotItem, error) {
+	if e.started {
+		e.it.Next()
+	}
+	e.started = true
+	if !e.it.Valid() {
+		if err := e.it.Error(); err != nil {
+			return types.SnapshotItem{}, err
+		}
+		return types.SnapshotItem{}, io.EOF
+	}
+	return types.SnapshotItem{
+		Key:   e.it.Key(),
+		Value: e.it.Value(),
+	}, nil
+}
+
+func (e *snapshotExporter) Close() {
+	e.it.Close()
+}
@@ -19,6 +19,9 @@ type (
 	StoreKey               = types.StoreKey
 	StoreOptions           = types.StoreOptions
 	Queryable              = types.Queryable
+	Snapshotter            = types.Snapshotter
+	SnapshotItem           = types.SnapshotItem
+	SnapshotIterator       = types.SnapshotIterator
 	Gas                    = types.Gas
 	GasMeter               = types.GasMeter
 	GasConfig              = types.GasConfig
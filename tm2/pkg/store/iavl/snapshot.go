@@ -0,0 +1,95 @@
+package iavl
+
+import (
+	goerrors "errors"
+	"io"
+
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+var _ types.VerifiedSnapshotter = (*Store)(nil)
+
+// Implements types.Snapshotter.
+func (st *Store) Export(version int64) (types.SnapshotIterator, error) {
+	if !st.VersionExists(version) {
+		return nil, iavl.ErrVersionDoesNotExist
+	}
+	iTree, err := st.tree.GetImmutable(version)
+	if err != nil {
+		return nil, err
+	}
+	exporter, err := iTree.Export()
+	if err != nil {
+		return nil, err
+	}
+	return snapshotExporter{exporter}, nil
+}
+
+// Implements types.Snapshotter.
+func (st *Store) Import(version int64, items types.SnapshotIterator) error {
+	tree, ok := st.tree.(*iavl.MutableTree)
+	if !ok {
+		return errors.New("cannot import into an immutable IAVL store")
+	}
+	importer, err := tree.Import(version)
+	if err != nil {
+		return err
+	}
+	defer importer.Close()
+
+	for {
+		item, err := items.Next()
+		if goerrors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		err = importer.Add(&iavl.ExportNode{
+			Key:     item.Key,
+			Value:   item.Value,
+			Version: item.Version,
+			Height:  item.Height,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return importer.Commit()
+}
+
+// SnapshotVerified implements types.VerifiedSnapshotter: the items of the
+// tree are verified by its root hash.
+func (st *Store) SnapshotVerified() {}
+
+// OwnsKey reports whether key is a key of the tree, in a database shared with
+// other stores.
+func (st *Store) OwnsKey(key []byte) bool {
+	return iavl.IsNodeDBKey(key)
+}
+
+type snapshotExporter struct {
+	exporter *iavl.Exporter
+}
+
+func (e snapshotExporter) Next() (types.SnapshotItem, error) {
+	node, err := e.exporter.Next()
+	if goerrors.Is(err, iavl.ErrExportDone) {
+		return types.SnapshotItem{}, io.EOF
+	}
+	if err != nil {
+		return types.SnapshotItem{}, err
+	}
+	return types.SnapshotItem{
+		Key:     node.Key,
+		Value:   node.Value,
+		Version: node.Version,
+		Height:  node.Height,
+	}, nil
+}
+
+func (e snapshotExporter) Close() {
+	e.exporter.Close()
+}
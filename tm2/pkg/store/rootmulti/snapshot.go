@@ -0,0 +1,243 @@
+package rootmulti
+
+import (
+	goerrors "errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+var _ types.Snapshotter = (*multiStore)(nil)
+
+// keyOwner is implemented by the stores which recognize their keys in a
+// database shared with other stores.
+type keyOwner interface {
+	OwnsKey(key []byte) bool
+}
+
+// Export implements types.Snapshotter. The items of the stores are exported
+// in the order of their names. Only the last committed version can be
+// exported, as some stores (e.g. dbadapter stores) aren't versioned: Export
+// must be called before anything else is written to the stores.
+func (ms *multiStore) Export(version int64) (types.SnapshotIterator, error) {
+	if version != ms.lastCommitID.Version {
+		return nil, errors.New("cannot export version %d, the last committed version is %d",
+			version, ms.lastCommitID.Version)
+	}
+
+	names := make([]string, 0, len(ms.keysByName))
+	for name := range ms.keysByName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	exp := &multiStoreExporter{}
+	for _, name := range names {
+		key := ms.keysByName[name]
+		store, ok := ms.stores[key].(types.Snapshotter)
+		if !ok {
+			exp.Close()
+			return nil, errors.New("store %s doesn't support snapshots", name)
+		}
+		items, err := store.Export(version)
+		if err != nil {
+			exp.Close()
+			return nil, fmt.Errorf("exporting store %s: %w", name, err)
+		}
+		if owners := ms.sharedDBOwners(key); len(owners) > 0 {
+			items = &filteredExporter{SnapshotIterator: items, owners: owners}
+		}
+		exp.names = append(exp.names, name)
+		exp.stores = append(exp.stores, items)
+	}
+	return exp, nil
+}
+
+// sharedDBOwners returns the stores which share the database of the store of
+// key, and recognize their keys in it.
+func (ms *multiStore) sharedDBOwners(key types.StoreKey) (owners []keyOwner) {
+	db := ms.storesParams[key].db
+	if db == nil {
+		return nil
+	}
+	if _, ok := ms.stores[key].(keyOwner); ok {
+		// The store knows its own keys.
+		return nil
+	}
+	for other, params := range ms.storesParams {
+		if other == key || params.db != db {
+			continue
+		}
+		if owner, ok := ms.stores[other].(keyOwner); ok {
+			owners = append(owners, owner)
+		}
+	}
+	return owners
+}
+
+// UnverifiedStores returns the names of the stores whose items, once imported
+// from a snapshot, can't be verified by the app hash (see
+// types.VerifiedSnapshotter), in the order of their names.
+func (ms *multiStore) UnverifiedStores() []string {
+	var names []string
+	for name, key := range ms.keysByName {
+		if _, ok := ms.stores[key].(types.VerifiedSnapshotter); !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Import implements types.Snapshotter. The multistore must be empty. The
+// imported version becomes the last committed version. The items of the
+// UnverifiedStores are imported as they are: only the other stores are
+// verified by the app hash of the imported version.
+func (ms *multiStore) Import(version int64, items types.SnapshotIterator) error {
+	if ms.lastCommitID.Version != 0 {
+		return errors.New("cannot import into a multistore at version %d", ms.lastCommitID.Version)
+	}
+	if version <= 0 {
+		return errors.New("invalid version %d", version)
+	}
+
+	item, err := items.Next()
+	for err == nil {
+		key := ms.keysByName[item.Store]
+		if key == nil {
+			return errors.New("unknown store %q", item.Store)
+		}
+		store, ok := ms.stores[key].(types.Snapshotter)
+		if !ok {
+			return errors.New("store %s doesn't support snapshots", item.Store)
+		}
+		storeItems := &storeImporter{items: items}
+		if err := store.Import(version, storeItems); err != nil {
+			return fmt.Errorf("importing store %s: %w", item.Store, err)
+		}
+		// Skip the items not read by the store, if any.
+		for storeItems.next == nil {
+			if _, err := storeItems.Next(); err != nil {
+				break
+			}
+		}
+		if storeItems.next != nil {
+			item = *storeItems.next
+		} else {
+			err = storeItems.err
+		}
+	}
+	if !goerrors.Is(err, io.EOF) {
+		return err
+	}
+
+	// Record the commit info of the imported version.
+	storeInfos := make([]storeInfo, 0, len(ms.stores))
+	for key, store := range ms.stores {
+		si := storeInfo{}
+		si.Name = key.Name()
+		si.Core.CommitID = store.LastCommitID()
+		storeInfos = append(storeInfos, si)
+	}
+	ci := commitInfo{
+		Version:    version,
+		StoreInfos: storeInfos,
+	}
+	batch := ms.db.NewBatch()
+	defer batch.Close()
+	setCommitInfo(batch, version, ci)
+	setLatestVersion(batch, version)
+	if err := batch.WriteSync(); err != nil {
+		return err
+	}
+	ms.lastCommitID = ci.CommitID()
+	return nil
+}
+
+// multiStoreExporter exports the items of several stores, each prefixed by an
+// item with the name of the store.
+type multiStoreExporter struct {
+	names  []string
+	stores []types.SnapshotIterator
+	header bool // whether the name of the current store was returned
+}
+
+func (e *multiStoreExporter) Next() (types.SnapshotItem, error) {
+	for len(e.stores) > 0 {
+		if !e.header {
+			e.header = true
+			return types.SnapshotItem{Store: e.names[0]}, nil
+		}
+		item, err := e.stores[0].Next()
+		if goerrors.Is(err, io.EOF) {
+			e.stores[0].Close()
+			e.names, e.stores = e.names[1:], e.stores[1:]
+			e.header = false
+			continue
+		}
+		return item, err
+	}
+	return types.SnapshotItem{}, io.EOF
+}
+
+func (e *multiStoreExporter) Close() {
+	for _, store := range e.stores {
+		store.Close()
+	}
+	e.names, e.stores = nil, nil
+}
+
+// filteredExporter skips the keys of the other stores sharing the database of
+// the exported store.
+type filteredExporter struct {
+	types.SnapshotIterator
+	owners []keyOwner
+}
+
+func (e *filteredExporter) Next() (types.SnapshotItem, error) {
+NEXT:
+	for {
+		item, err := e.SnapshotIterator.Next()
+		if err != nil {
+			return item, err
+		}
+		for _, owner := range e.owners {
+			if owner.OwnsKey(item.Key) {
+				continue NEXT
+			}
+		}
+		return item, nil
+	}
+}
+
+// storeImporter returns the items of a multistore snapshot up to the name of
+// the next store, which is kept in next.
+type storeImporter struct {
+	items types.SnapshotIterator
+	next  *types.SnapshotItem
+	err   error
+}
+
+func (i *storeImporter) Next() (types.SnapshotItem, error) {
+	if i.next != nil || i.err != nil {
+		return types.SnapshotItem{}, io.EOF
+	}
+	item, err := i.items.Next()
+	switch {
+	case err != nil:
+		i.err = err
+		if goerrors.Is(err, io.EOF) {
+			return types.SnapshotItem{}, io.EOF
+		}
+		return types.SnapshotItem{}, err
+	case item.Store != "":
+		i.next = &item
+		return types.SnapshotItem{}, io.EOF
+	}
+	return item, nil
+}
+
+func (i *storeImporter) Close() {}
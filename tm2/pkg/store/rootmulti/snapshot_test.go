@@ -0,0 +1,122 @@
+package rootmulti
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+// newSnapshotMultiStore mounts an IAVL and a dbadapter store sharing the same
+// database, like gno.land does.
+func newSnapshotMultiStore(t *testing.T, db dbm.DB) *multiStore {
+	t.Helper()
+
+	ms := NewMultiStore(db)
+	ms.MountStoreWithDB(types.NewStoreKey("main"), iavl.StoreConstructor, db)
+	ms.MountStoreWithDB(types.NewStoreKey("base"), dbadapter.StoreConstructor, db)
+	ms.MountStoreWithDB(types.NewStoreKey("other"), iavl.StoreConstructor, nil)
+	require.NoError(t, ms.LoadLatestVersion())
+	return ms
+}
+
+type sliceIterator []types.SnapshotItem
+
+func (it *sliceIterator) Next() (types.SnapshotItem, error) {
+	if len(*it) == 0 {
+		return types.SnapshotItem{}, io.EOF
+	}
+	item := (*it)[0]
+	*it = (*it)[1:]
+	return item, nil
+}
+
+func (it *sliceIterator) Close() {}
+
+func TestMultiStoreSnapshot(t *testing.T) {
+	t.Parallel()
+
+	ms := newSnapshotMultiStore(t, memdb.NewMemDB())
+	main, base, other := ms.getStoreByName("main"), ms.getStoreByName("base"), ms.getStoreByName("other")
+	for i := range 3 {
+		for j := range 10 {
+			main.Set(fmt.Appendf(nil, "main%d", j), fmt.Appendf(nil, "v%d", i))
+		}
+		base.Set(fmt.Appendf(nil, "base%d", i), []byte("v"))
+		other.Set(fmt.Appendf(nil, "other%d", i), []byte("v"))
+		ms.Commit()
+	}
+	// A previous version can't be exported.
+	_, err := ms.Export(2)
+	require.Error(t, err)
+
+	exp, err := ms.Export(3)
+	require.NoError(t, err)
+	// Changes made after the export aren't exported.
+	base.Set([]byte("base3"), []byte("v"))
+
+	var items sliceIterator
+	stores := map[string]int{}
+	var store string
+	for {
+		item, err := exp.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		if item.Store != "" {
+			store = item.Store
+		} else {
+			stores[store]++
+		}
+		items = append(items, item)
+	}
+	exp.Close()
+	assert.Equal(t, "base", items[0].Store)
+	// The IAVL nodes in the shared database aren't exported with the base store.
+	assert.Equal(t, 3, stores["base"])
+	assert.Equal(t, 19, stores["main"]) // 10 leaves and 9 inner nodes
+	assert.Equal(t, 5, stores["other"])
+
+	ms2 := newSnapshotMultiStore(t, memdb.NewMemDB())
+	require.NoError(t, ms2.Import(3, &items))
+	assert.Equal(t, int64(3), ms2.LastCommitID().Version)
+
+	// The imported multistore has the hash of the exported version.
+	ci, err := getCommitInfo(ms.db, 3)
+	require.NoError(t, err)
+	assert.Equal(t, ci.CommitID(), ms2.LastCommitID())
+	assert.Equal(t, []byte("v2"), ms2.getStoreByName("main").Get([]byte("main5")))
+	assert.Equal(t, []byte("v"), ms2.getStoreByName("base").Get([]byte("base2")))
+	assert.Nil(t, ms2.getStoreByName("base").Get([]byte("base3")))
+	assert.Equal(t, []byte("v"), ms2.getStoreByName("other").Get([]byte("other1")))
+
+	// The imported version is reloaded after a restart.
+	ms3 := NewMultiStore(ms2.db)
+	ms3.MountStoreWithDB(types.NewStoreKey("main"), iavl.StoreConstructor, ms2.db)
+	ms3.MountStoreWithDB(types.NewStoreKey("base"), dbadapter.StoreConstructor, ms2.db)
+	ms3.MountStoreWithDB(types.NewStoreKey("other"), iavl.StoreConstructor, nil)
+	require.NoError(t, ms3.LoadLatestVersion())
+	assert.Equal(t, ms2.LastCommitID(), ms3.LastCommitID())
+
+	// A non-empty multistore can't be imported into.
+	require.Error(t, ms.Import(3, &sliceIterator{}))
+}
+
+func TestMultiStoreUnverifiedStores(t *testing.T) {
+	t.Parallel()
+
+	// Only the dbadapter store isn't verified by the app hash.
+	ms := newSnapshotMultiStore(t, memdb.NewMemDB())
+	assert.Equal(t, []string{"base"}, ms.UnverifiedStores())
+}
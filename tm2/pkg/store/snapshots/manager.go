@@ -0,0 +1,388 @@
+// Package snapshots takes the state sync snapshots of a store, and restores
+// them.
+package snapshots
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+const (
+	// FormatV1 is the format of the snapshots: the items of the exported
+	// store as length-prefixed amino, compressed with gzip, and split into
+	// chunks. The metadata of the snapshot holds the SHA-256 hashes of the
+	// chunks, and its hash is their merkle root.
+	FormatV1 uint32 = 1
+
+	// DefaultChunkSize is the default maximum size of the chunks.
+	DefaultChunkSize int64 = 4 * 1024 * 1024
+
+	// maxItemSize is the maximum size of an encoded snapshot item.
+	maxItemSize = 64 * 1024 * 1024
+)
+
+var (
+	ErrUnknownFormat     = errors.New("unknown snapshot format")
+	ErrChunkHashMismatch = errors.New("chunk hash doesn't match the snapshot metadata")
+	ErrNoRestore         = errors.New("no snapshot restoration in progress")
+	ErrUnverifiedStores  = errors.New("cannot restore snapshots of stores not verified by the app hash")
+)
+
+// Options configures the snapshots taken by a Manager.
+type Options struct {
+	// Interval is the number of heights between snapshots; 0 disables
+	// snapshots.
+	Interval int64
+
+	// KeepRecent is the number of recent snapshots to keep; 0 keeps them all.
+	KeepRecent int
+
+	// ChunkSize is the maximum size of the chunks; DefaultChunkSize is used
+	// if it is 0.
+	ChunkSize int64
+
+	// TrustUnverifiedStores allows restoring the snapshots of a target with
+	// stores whose items can't be verified by the app hash (see
+	// types.VerifiedSnapshotter): their items are then trusted from the peer
+	// serving the snapshot. Otherwise, such snapshots are refused.
+	TrustUnverifiedStores bool
+}
+
+// unverifiedStorer is implemented by the targets made of several stores, some
+// of which may not be verified by the app hash (e.g. multistores).
+type unverifiedStorer interface {
+	UnverifiedStores() []string
+}
+
+// Manager takes the snapshots of a store, and restores them.
+type Manager struct {
+	store  *Store
+	target types.Snapshotter
+	opts   Options
+	logger *slog.Logger
+
+	mtx     sync.Mutex
+	saving  bool         // a snapshot is being saved
+	restore *restoration // the snapshot being restored, if any
+	wg      sync.WaitGroup
+}
+
+// NewManager returns a Manager taking the snapshots of target into dir.
+func NewManager(dir string, target types.Snapshotter, opts Options, logger *slog.Logger) (*Manager, error) {
+	store, err := NewStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = DefaultChunkSize
+	}
+	return &Manager{
+		store:  store,
+		target: target,
+		opts:   opts,
+		logger: logger,
+	}, nil
+}
+
+// SnapshotIfNeeded takes a snapshot of the target if height is a snapshot
+// height. The state of the target is exported before SnapshotIfNeeded
+// returns, so it must be called right after height is committed; the snapshot
+// is saved in the background. Heights are skipped while a previous snapshot is
+// still being saved.
+func (m *Manager) SnapshotIfNeeded(height int64) {
+	if m.opts.Interval <= 0 || height%m.opts.Interval != 0 {
+		return
+	}
+
+	m.mtx.Lock()
+	if m.saving || m.restore != nil {
+		m.mtx.Unlock()
+		m.logger.Info("Skipping state sync snapshot, another snapshot is in progress", "height", height)
+		return
+	}
+	m.saving = true
+	m.mtx.Unlock()
+
+	items, err := m.target.Export(height)
+	if err != nil {
+		m.logger.Error("Failed to export state sync snapshot", "height", height, "err", err)
+		m.setSaving(false)
+		return
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer m.setSaving(false)
+
+		snapshot, err := m.save(height, items)
+		if err != nil {
+			m.logger.Error("Failed to save state sync snapshot", "height", height, "err", err)
+			return
+		}
+		m.logger.Info("Saved state sync snapshot", "height", height, "chunks", snapshot.Chunks, "hash", snapshot.Hash)
+	}()
+}
+
+// Create takes a snapshot of the target at height, which must be its last
+// committed version.
+func (m *Manager) Create(height int64) (*abci.Snapshot, error) {
+	m.mtx.Lock()
+	if m.saving || m.restore != nil {
+		m.mtx.Unlock()
+		return nil, errors.New("another snapshot is in progress")
+	}
+	m.saving = true
+	m.mtx.Unlock()
+	defer m.setSaving(false)
+
+	items, err := m.target.Export(height)
+	if err != nil {
+		return nil, err
+	}
+	return m.save(height, items)
+}
+
+func (m *Manager) setSaving(saving bool) {
+	m.mtx.Lock()
+	m.saving = saving
+	m.mtx.Unlock()
+}
+
+// save saves the exported items, closes them, and prunes the old snapshots.
+func (m *Manager) save(height int64, items types.SnapshotIterator) (*abci.Snapshot, error) {
+	defer items.Close()
+
+	snapshot, err := m.store.Save(height, FormatV1, func(w io.Writer) error {
+		zw := gzip.NewWriter(w)
+		bw := bufio.NewWriter(zw)
+		for {
+			item, err := items.Next()
+			if goerrors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if _, err := amino.MarshalSizedWriter(bw, item); err != nil {
+				return err
+			}
+		}
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+		return zw.Close()
+	}, m.opts.ChunkSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.opts.KeepRecent > 0 {
+		if _, err := m.store.Prune(m.opts.KeepRecent); err != nil {
+			m.logger.Error("Failed to prune state sync snapshots", "err", err)
+		}
+	}
+	return snapshot, nil
+}
+
+// Wait waits for the snapshots being saved in the background.
+func (m *Manager) Wait() {
+	m.wg.Wait()
+}
+
+// List returns the saved snapshots, the most recent first.
+func (m *Manager) List() ([]*abci.Snapshot, error) {
+	return m.store.List()
+}
+
+// LoadChunk returns a chunk of a saved snapshot, or nil if it doesn't exist.
+func (m *Manager) LoadChunk(height int64, format uint32, chunk uint32) ([]byte, error) {
+	return m.store.LoadChunk(height, format, chunk)
+}
+
+// restoration is a snapshot being restored. Its chunks are written to a pipe,
+// read by the import of the target running in the background.
+type restoration struct {
+	snapshot *abci.Snapshot
+	hashes   [][]byte
+	next     uint32 // index of the next chunk
+	pw       *io.PipeWriter
+	done     chan error
+}
+
+// Restore starts restoring snapshot into the target, whose chunks are then
+// passed to RestoreChunk. Any previous restoration is aborted. The snapshot
+// is refused if the target can't be verified, unless
+// Options.TrustUnverifiedStores is set.
+func (m *Manager) Restore(snapshot *abci.Snapshot) error {
+	if snapshot.Format != FormatV1 {
+		return ErrUnknownFormat
+	}
+	if err := m.checkVerified(); err != nil {
+		return err
+	}
+	hashes, err := decodeMetadata(snapshot)
+	if err != nil {
+		return err
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.saving {
+		return errors.New("a snapshot is being saved")
+	}
+	m.abortRestore()
+
+	pr, pw := io.Pipe()
+	r := &restoration{
+		snapshot: snapshot,
+		hashes:   hashes,
+		pw:       pw,
+		done:     make(chan error, 1),
+	}
+	go func() {
+		err := m.importStream(snapshot.Height, pr)
+		// Unblock RestoreChunk if the import stopped early.
+		pr.CloseWithError(errors.New("snapshot import stopped"))
+		r.done <- err
+	}()
+	m.restore = r
+	return nil
+}
+
+// checkVerified returns ErrUnverifiedStores if the items of some stores of
+// the target can't be verified by the app hash, and aren't trusted.
+func (m *Manager) checkVerified() error {
+	if m.opts.TrustUnverifiedStores {
+		return nil
+	}
+	var names []string
+	switch target := m.target.(type) {
+	case unverifiedStorer:
+		names = target.UnverifiedStores()
+	case types.VerifiedSnapshotter:
+	default:
+		names = []string{"target"}
+	}
+	if len(names) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnverifiedStores, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// importStream imports into the target the items read from the stream of a
+// snapshot.
+func (m *Manager) importStream(height int64, r io.Reader) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	items := &itemReader{r: bufio.NewReader(zr)}
+	if err := m.target.Import(height, items); err != nil {
+		return err
+	}
+	// All the stream must be read.
+	if _, err := items.Next(); !goerrors.Is(err, io.EOF) {
+		return errors.New("unexpected snapshot item after the end of the import")
+	}
+	return nil
+}
+
+// RestoreChunk restores the next chunk of the snapshot being restored, and
+// reports whether the restoration is complete. If the chunk doesn't match its
+// hash, ErrChunkHashMismatch is returned and the chunk can be restored again;
+// any other error aborts the restoration.
+func (m *Manager) RestoreChunk(chunk []byte) (done bool, err error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	r := m.restore
+	if r == nil {
+		return false, ErrNoRestore
+	}
+
+	hash := sha256.Sum256(chunk)
+	if string(hash[:]) != string(r.hashes[r.next]) {
+		return false, ErrChunkHashMismatch
+	}
+	if _, err := r.pw.Write(chunk); err != nil {
+		// The import failed: return its error.
+		m.restore = nil
+		if ierr := <-r.done; ierr != nil {
+			err = ierr
+		}
+		return false, errors.Wrap(err, "restoring snapshot")
+	}
+	r.next++
+	if r.next < r.snapshot.Chunks {
+		return false, nil
+	}
+
+	// That was the last chunk: wait for the end of the import.
+	r.pw.Close()
+	err = <-r.done
+	m.restore = nil
+	if err != nil {
+		return false, errors.Wrap(err, "restoring snapshot")
+	}
+	return true, nil
+}
+
+// RestoreNext returns the index of the next chunk expected by RestoreChunk.
+func (m *Manager) RestoreNext() (uint32, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.restore == nil {
+		return 0, ErrNoRestore
+	}
+	return m.restore.next, nil
+}
+
+// AbortRestore aborts the restoration in progress, if any. The target is left
+// in an unspecified state.
+func (m *Manager) AbortRestore() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.abortRestore()
+}
+
+func (m *Manager) abortRestore() {
+	if m.restore == nil {
+		return
+	}
+	r := m.restore
+	m.restore = nil
+	r.pw.CloseWithError(errors.New("snapshot restoration aborted"))
+	go func() { <-r.done }()
+}
+
+// itemReader reads the snapshot items of a stream.
+type itemReader struct {
+	r *bufio.Reader
+}
+
+func (ir *itemReader) Next() (types.SnapshotItem, error) {
+	var item types.SnapshotItem
+	_, err := amino.UnmarshalSizedReader(ir.r, &item, maxItemSize)
+	if goerrors.Is(err, io.EOF) {
+		return types.SnapshotItem{}, io.EOF
+	}
+	if err != nil {
+		return types.SnapshotItem{}, errors.Wrap(err, "reading snapshot item")
+	}
+	return item, nil
+}
+
+func (ir *itemReader) Close() {}
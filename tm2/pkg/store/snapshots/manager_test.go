@@ -0,0 +1,124 @@
+package snapshots
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/rootmulti"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+var (
+	mainKey = types.NewStoreKey("main")
+	baseKey = types.NewStoreKey("base")
+)
+
+func newMultiStore(t *testing.T) types.CommitMultiStore {
+	t.Helper()
+
+	db := memdb.NewMemDB()
+	ms := rootmulti.NewMultiStore(db)
+	ms.MountStoreWithDB(mainKey, iavl.StoreConstructor, db)
+	ms.MountStoreWithDB(baseKey, dbadapter.StoreConstructor, db)
+	require.NoError(t, ms.LoadLatestVersion())
+	return ms
+}
+
+func newManager(t *testing.T, ms types.CommitMultiStore, opts Options) *Manager {
+	t.Helper()
+
+	m, err := NewManager(t.TempDir(), ms.(types.Snapshotter), opts, log.NewNoopLogger())
+	require.NoError(t, err)
+	return m
+}
+
+func TestManager(t *testing.T) {
+	t.Parallel()
+
+	ms := newMultiStore(t)
+	m := newManager(t, ms, Options{Interval: 2, KeepRecent: 2, ChunkSize: 512})
+	main, base := ms.GetStore(mainKey), ms.GetStore(baseKey)
+	for i := range 6 {
+		for j := range 100 {
+			main.Set(fmt.Appendf(nil, "key%d", j), fmt.Appendf(nil, "value%d-%d", i, j))
+		}
+		base.Set(fmt.Appendf(nil, "base%d", i), []byte("v"))
+		id := ms.Commit()
+		m.SnapshotIfNeeded(id.Version)
+		m.Wait()
+	}
+
+	// Only the 2 most recent snapshots are kept.
+	snapshots, err := m.List()
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, int64(6), snapshots[0].Height)
+	assert.Equal(t, int64(4), snapshots[1].Height)
+	snapshot := snapshots[0]
+	require.Greater(t, snapshot.Chunks, uint32(1))
+
+	chunk, err := m.LoadChunk(6, FormatV1, snapshot.Chunks)
+	require.NoError(t, err)
+	assert.Nil(t, chunk)
+
+	// The base store isn't verified by the app hash: it must be trusted.
+	ms2 := newMultiStore(t)
+	require.ErrorIs(t, newManager(t, ms2, Options{}).Restore(snapshot), ErrUnverifiedStores)
+	m2 := newManager(t, ms2, Options{TrustUnverifiedStores: true})
+	require.NoError(t, m2.Restore(snapshot))
+	for i := range snapshot.Chunks {
+		chunk, err := m.LoadChunk(6, FormatV1, i)
+		require.NoError(t, err)
+		require.NotNil(t, chunk)
+
+		// A corrupted chunk is rejected, and can be restored again.
+		_, err = m2.RestoreChunk(append([]byte{0}, chunk...))
+		require.ErrorIs(t, err, ErrChunkHashMismatch)
+
+		done, err := m2.RestoreChunk(chunk)
+		require.NoError(t, err)
+		assert.Equal(t, i == snapshot.Chunks-1, done)
+	}
+	_, err = m2.RestoreChunk(nil)
+	require.ErrorIs(t, err, ErrNoRestore)
+
+	assert.Equal(t, ms.LastCommitID(), ms2.LastCommitID())
+	assert.Equal(t, []byte("value5-42"), ms2.GetStore(mainKey).Get([]byte("key42")))
+	assert.Equal(t, []byte("v"), ms2.GetStore(baseKey).Get([]byte("base5")))
+}
+
+func TestManagerRestoreInvalid(t *testing.T) {
+	t.Parallel()
+
+	ms := newMultiStore(t)
+	ms.GetStore(mainKey).Set([]byte("key"), []byte("value"))
+	ms.Commit()
+	m := newManager(t, ms, Options{})
+	snapshot, err := m.Create(1)
+	require.NoError(t, err)
+
+	m2 := newManager(t, newMultiStore(t), Options{TrustUnverifiedStores: true})
+
+	unknown := *snapshot
+	unknown.Format = 2
+	require.ErrorIs(t, m2.Restore(&unknown), ErrUnknownFormat)
+
+	invalid := *snapshot
+	invalid.Hash = []byte("invalid")
+	require.Error(t, m2.Restore(&invalid))
+
+	// The snapshot can't be restored into a store which isn't empty.
+	m3 := newManager(t, ms, Options{TrustUnverifiedStores: true})
+	require.NoError(t, m3.Restore(snapshot))
+	chunk, err := m.LoadChunk(1, FormatV1, 0)
+	require.NoError(t, err)
+	_, err = m3.RestoreChunk(chunk)
+	require.Error(t, err)
+}
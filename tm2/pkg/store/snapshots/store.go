@@ -0,0 +1,251 @@
+package snapshots
+
+import (
+	"crypto/sha256"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+)
+
+// snapshotFile is the name of the file holding the description of a snapshot,
+// written once all of its chunks are saved.
+const snapshotFile = "snapshot"
+
+// metadata is the Metadata of the snapshots, in FormatV1.
+type metadata struct {
+	ChunkHashes [][]byte
+}
+
+// decodeMetadata returns the chunk hashes of snapshot, after checking that
+// they match its hash.
+func decodeMetadata(snapshot *abci.Snapshot) ([][]byte, error) {
+	var meta metadata
+	if err := amino.Unmarshal(snapshot.Metadata, &meta); err != nil {
+		return nil, errors.Wrap(err, "invalid snapshot metadata")
+	}
+	if uint32(len(meta.ChunkHashes)) != snapshot.Chunks {
+		return nil, errors.New("snapshot has %d chunks, but %d chunk hashes",
+			snapshot.Chunks, len(meta.ChunkHashes))
+	}
+	if snapshot.Chunks == 0 {
+		return nil, errors.New("snapshot has no chunks")
+	}
+	if hash := merkle.SimpleHashFromByteSlices(meta.ChunkHashes); string(hash) != string(snapshot.Hash) {
+		return nil, errors.New("snapshot hash %X doesn't match its chunk hashes", snapshot.Hash)
+	}
+	return meta.ChunkHashes, nil
+}
+
+// Store stores the snapshots in a directory, as one directory per snapshot
+// (<height>/<format>/) holding a file per chunk, and the snapshot file.
+type Store struct {
+	dir string
+}
+
+// NewStore returns a Store saving the snapshots into dir, which is created if
+// needed.
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Wrap(err, "creating snapshot directory")
+	}
+	return &Store{dir: dir}, nil
+}
+
+func (s *Store) path(height int64, format uint32) string {
+	return filepath.Join(s.dir, strconv.FormatInt(height, 10), strconv.FormatUint(uint64(format), 10))
+}
+
+// Save saves the snapshot at height, whose stream is written by write, in
+// chunks of chunkSize bytes. It replaces any previous snapshot at height in
+// the same format.
+func (s *Store) Save(height int64, format uint32, write func(w io.Writer) error, chunkSize int64) (*abci.Snapshot, error) {
+	dir := s.path(height, format)
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	cw := &chunkWriter{dir: dir, size: chunkSize}
+	err := write(cw)
+	if cerr := cw.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	snapshot := &abci.Snapshot{
+		Height:   height,
+		Format:   format,
+		Chunks:   uint32(len(cw.hashes)),
+		Hash:     merkle.SimpleHashFromByteSlices(cw.hashes),
+		Metadata: amino.MustMarshal(metadata{ChunkHashes: cw.hashes}),
+	}
+	if err := os.WriteFile(filepath.Join(dir, snapshotFile), amino.MustMarshal(snapshot), 0o644); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// Get returns the snapshot at height in format, or nil if there is none.
+func (s *Store) Get(height int64, format uint32) (*abci.Snapshot, error) {
+	bz, err := os.ReadFile(filepath.Join(s.path(height, format), snapshotFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &abci.Snapshot{}
+	if err := amino.Unmarshal(bz, snapshot); err != nil {
+		return nil, errors.Wrapf(err, "invalid snapshot at height %d", height)
+	}
+	return snapshot, nil
+}
+
+// List returns the saved snapshots, the most recent first. The snapshots
+// being saved aren't returned.
+func (s *Store) List() ([]*abci.Snapshot, error) {
+	heights, err := s.heights()
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []*abci.Snapshot
+	for i := len(heights) - 1; i >= 0; i-- {
+		formats, err := os.ReadDir(filepath.Join(s.dir, strconv.FormatInt(heights[i], 10)))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range formats {
+			format, err := strconv.ParseUint(f.Name(), 10, 32)
+			if err != nil {
+				continue
+			}
+			snapshot, err := s.Get(heights[i], uint32(format))
+			if err != nil {
+				return nil, err
+			}
+			if snapshot != nil {
+				snapshots = append(snapshots, snapshot)
+			}
+		}
+	}
+	return snapshots, nil
+}
+
+// heights returns the heights of the snapshot directories, in increasing
+// order.
+func (s *Store) heights() ([]int64, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	heights := make([]int64, 0, len(entries))
+	for _, e := range entries {
+		height, err := strconv.ParseInt(e.Name(), 10, 64)
+		if err != nil || !e.IsDir() {
+			continue
+		}
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights, nil
+}
+
+// LoadChunk returns a chunk of a saved snapshot, or nil if it doesn't exist.
+func (s *Store) LoadChunk(height int64, format uint32, chunk uint32) ([]byte, error) {
+	snapshot, err := s.Get(height, format)
+	if err != nil || snapshot == nil || chunk >= snapshot.Chunks {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Join(s.path(height, format), strconv.FormatUint(uint64(chunk), 10)))
+}
+
+// Prune deletes all the snapshots but the keepRecent most recent ones, and
+// returns the number of deleted heights.
+func (s *Store) Prune(keepRecent int) (int, error) {
+	heights, err := s.heights()
+	if err != nil {
+		return 0, err
+	}
+	pruned := 0
+	for i := 0; i < len(heights)-keepRecent; i++ {
+		if err := os.RemoveAll(filepath.Join(s.dir, strconv.FormatInt(heights[i], 10))); err != nil {
+			return pruned, err
+		}
+		pruned++
+	}
+	return pruned, nil
+}
+
+// chunkWriter writes the stream of a snapshot into chunk files of size bytes,
+// and records their hashes.
+type chunkWriter struct {
+	dir    string
+	size   int64
+	file   *os.File
+	hasher hash.Hash
+	n      int64 // bytes written to file
+	hashes [][]byte
+}
+
+func (cw *chunkWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if cw.file == nil {
+			name := filepath.Join(cw.dir, strconv.Itoa(len(cw.hashes)))
+			f, err := os.Create(name)
+			if err != nil {
+				return written, err
+			}
+			cw.file, cw.n = f, 0
+			cw.hasher = sha256.New()
+		}
+		n := min(int64(len(p)), cw.size-cw.n)
+		m, err := cw.file.Write(p[:n])
+		cw.hasher.Write(p[:m])
+		written += m
+		cw.n += int64(m)
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+		if cw.n == cw.size {
+			if err := cw.closeChunk(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// closeChunk closes the current chunk file and records its hash.
+func (cw *chunkWriter) closeChunk() error {
+	if err := cw.file.Close(); err != nil {
+		return err
+	}
+	cw.file = nil
+	cw.hashes = append(cw.hashes, cw.hasher.Sum(nil))
+	return nil
+}
+
+// Close closes the last chunk.
+func (cw *chunkWriter) Close() error {
+	if cw.file == nil {
+		return nil
+	}
+	return cw.closeChunk()
+}
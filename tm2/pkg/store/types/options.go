@@ -45,10 +45,13 @@ const (
 	PruneEverythingStrategy PruneStrategy = "everything"
 	PruneNothingStrategy    PruneStrategy = "nothing"
 	PruneSyncableStrategy   PruneStrategy = "syncable"
+	PruneCustomStrategy     PruneStrategy = "custom" // options set by the operator
 )
 
 // Options returns the corresponding prune options.
-// If the pruning strategy is invalid, defaults to no pruning.
+// The custom strategy has no options of its own, they are set by the
+// operator in the app config (see AppConfig.PruningOptions): like an invalid
+// strategy, it returns the syncable options.
 func (s PruneStrategy) Options() PruningOptions {
 	switch s {
 	case PruneEverythingStrategy:
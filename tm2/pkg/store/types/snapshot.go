@@ -0,0 +1,52 @@
+package types
+
+// SnapshotItem is an item of an exported store, as written to state sync
+// snapshots.
+//
+// The items of an IAVL store are the nodes of its tree, in the order of the
+// IAVL exporter; the items of the other stores are their key-value pairs, with
+// a zero Version and Height. A multistore prefixes the items of each of its
+// stores with an item holding only the name of the store.
+type SnapshotItem struct {
+	Store   string
+	Key     []byte
+	Value   []byte
+	Version int64
+	Height  int8
+}
+
+// SnapshotIterator iterates over the items of an exported store.
+type SnapshotIterator interface {
+	// Next returns the next item, or io.EOF after the last one.
+	Next() (SnapshotItem, error)
+
+	// Close releases the resources of the iterator.
+	Close()
+}
+
+// Snapshotter is implemented by the stores which can be exported to, and
+// restored from, state sync snapshots.
+//
+// This is an optional extension to any CommitStore.
+type Snapshotter interface {
+	// Export returns the items of the store at version. The state to export
+	// is captured before Export returns, so the items may be read in the
+	// background while new versions are committed.
+	Export(version int64) (SnapshotIterator, error)
+
+	// Import restores the items of a store exported at version. The store
+	// must be empty; the iterator isn't closed.
+	Import(version int64, items SnapshotIterator) error
+}
+
+// VerifiedSnapshotter is implemented by the Snapshotters whose commit hash
+// covers all their items (e.g. merkle trees), so that their imported items are
+// verified by the app hash of the snapshot. The items imported into the other
+// stores (e.g. the dbadapter stores) can't be verified: they are trusted from
+// the peer serving the snapshot.
+type VerifiedSnapshotter interface {
+	Snapshotter
+
+	// SnapshotVerified is a marker method.
+	SnapshotVerified()
+}
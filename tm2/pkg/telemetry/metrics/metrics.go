@@ -35,6 +35,11 @@ const (
 	blockSizeKey            = "block_size_hist"
 	gasPriceKey             = "block_gas_price_hist"
 
+	prunedBlocksKey     = "pruned_blocks_counter"
+	prunedResultsKey    = "pruned_results_counter"
+	prunedBaseHeightKey = "pruned_base_height_gauge"
+	pruneTimerKey       = "prune_hist"
+
 	httpRequestTimeKey = "http_request_time_hist"
 	wsRequestTimeKey   = "ws_request_time_hist"
 )
@@ -90,6 +95,20 @@ var (
 	// BlockGasPriceAmount measures the block gas price of the last block
 	BlockGasPriceAmount metric.Int64Histogram
 
+	// Pruning //
+
+	// PrunedBlocks measures the number of blocks pruned from the block store
+	PrunedBlocks metric.Int64Counter
+
+	// PrunedResults measures the number of block results (ABCI responses) pruned from the state
+	PrunedResults metric.Int64Counter
+
+	// PrunedBaseHeight measures the lowest height of the blocks kept in the block store
+	PrunedBaseHeight metric.Int64Gauge
+
+	// PruneTimer measures the duration of a pruning run
+	PruneTimer metric.Int64Histogram
+
 	// RPC //
 
 	// HTTPRequestTime measures the HTTP request response time
@@ -258,6 +277,36 @@ func Init(config config.Config) error {
 	); err != nil {
 		return fmt.Errorf("unable to create histogram, %w", err)
 	}
+	// Pruning //
+	if PrunedBlocks, err = meter.Int64Counter(
+		prunedBlocksKey,
+		metric.WithDescription("number of pruned blocks"),
+	); err != nil {
+		return fmt.Errorf("unable to create counter, %w", err)
+	}
+
+	if PrunedResults, err = meter.Int64Counter(
+		prunedResultsKey,
+		metric.WithDescription("number of pruned block results"),
+	); err != nil {
+		return fmt.Errorf("unable to create counter, %w", err)
+	}
+
+	if PrunedBaseHeight, err = meter.Int64Gauge(
+		prunedBaseHeightKey,
+		metric.WithDescription("lowest height of the stored blocks"),
+	); err != nil {
+		return fmt.Errorf("unable to create gauge, %w", err)
+	}
+
+	if PruneTimer, err = meter.Int64Histogram(
+		pruneTimerKey,
+		metric.WithDescription("pruning duration"),
+		metric.WithUnit("ms"),
+	); err != nil {
+		return fmt.Errorf("unable to create histogram, %w", err)
+	}
+
 	// RPC //
 
 	if HTTPRequestTime, err = meter.Int64Histogram(